@@ -4,19 +4,29 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"strings"
 	"time"
 
 	backwardcompatibility "github.com/kyverno/kyverno/pkg/backward_compatibility"
+	"github.com/kyverno/kyverno/pkg/capture"
+	"github.com/kyverno/kyverno/pkg/certmanager"
 	kyvernoclient "github.com/kyverno/kyverno/pkg/client/clientset/versioned"
 	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions"
 	"github.com/kyverno/kyverno/pkg/config"
 	dclient "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine"
 	event "github.com/kyverno/kyverno/pkg/event"
 	"github.com/kyverno/kyverno/pkg/generate"
 	generatecleanup "github.com/kyverno/kyverno/pkg/generate/cleanup"
+	"github.com/kyverno/kyverno/pkg/generateexisting"
+	"github.com/kyverno/kyverno/pkg/globalcontext"
+	"github.com/kyverno/kyverno/pkg/metrics"
+	"github.com/kyverno/kyverno/pkg/mutateexisting"
+	"github.com/kyverno/kyverno/pkg/notifier"
 	"github.com/kyverno/kyverno/pkg/openapi"
 	"github.com/kyverno/kyverno/pkg/policy"
 	"github.com/kyverno/kyverno/pkg/policycache"
@@ -24,11 +34,14 @@ import (
 	"github.com/kyverno/kyverno/pkg/policystatus"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
 	"github.com/kyverno/kyverno/pkg/signal"
+	tlsutils "github.com/kyverno/kyverno/pkg/tls"
 	"github.com/kyverno/kyverno/pkg/utils"
 	"github.com/kyverno/kyverno/pkg/version"
 	"github.com/kyverno/kyverno/pkg/webhookconfig"
 	"github.com/kyverno/kyverno/pkg/webhooks"
 	webhookgenerate "github.com/kyverno/kyverno/pkg/webhooks/generate"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
@@ -47,12 +60,45 @@ var (
 	excludeGroupRole               string
 	excludeUsername                string
 	profilePort                    string
-
-	webhookTimeout int
-
-	profile      bool
-	policyReport bool
-	setupLog     = log.Log.WithName("setup")
+	notifiersConfig                string
+	captureConfig                  string
+	metricsPort                    string
+	certManagerCertificate         string
+	certManagerSecret              string
+	tlsCertFile                    string
+	tlsKeyFile                     string
+	tlsCAFile                      string
+	tlsMinVersion                  string
+	tlsCipherSuites                string
+	certDNSNames                   string
+	certKeyAlgorithm               string
+
+	webhookTimeout                int
+	backgroundScanWorkers         int
+	backgroundScanKindConcurrency int
+	backgroundScanMaxListCalls    int
+	backgroundScanListBurst       int
+	maxReportResultsPerPolicy     int
+
+	generateQPS   float64
+	generateBurst int
+	eventsQPS     float64
+	eventsBurst   int
+
+	backgroundScanListQPS      float64
+	backgroundScanListPageSize int64
+
+	reportResultsTTL       time.Duration
+	reportFlushInterval    time.Duration
+	certRenewBefore        time.Duration
+	certValidity           time.Duration
+	backgroundScanInterval time.Duration
+
+	profile              bool
+	policyReport         bool
+	mutateExistingDryRun bool
+	reportOwners         bool
+	setupLog             = log.Log.WithName("setup")
 )
 
 func main() {
@@ -67,6 +113,36 @@ func main() {
 	flag.StringVar(&runValidationInMutatingWebhook, "runValidationInMutatingWebhook", "", "Validation will also be done using the mutation webhook, set to 'true' to enable. Older kubernetes versions do not work properly when a validation webhook is registered.")
 	flag.BoolVar(&profile, "profile", false, "Set this flag to 'true', to enable profiling.")
 	flag.StringVar(&profilePort, "profile-port", "6060", "Enable profiling at given port, default to 6060.")
+	flag.Float64Var(&generateQPS, "generateQPS", 20, "Maximum number of writes of generated resources per second by the generate controller.")
+	flag.IntVar(&generateBurst, "generateBurst", 50, "Maximum burst of writes of generated resources by the generate controller.")
+	flag.Float64Var(&eventsQPS, "eventsQPS", 10, "Maximum number of events recorded per second, the events are dropped when too many are queued.")
+	flag.IntVar(&eventsBurst, "eventsBurst", 50, "Maximum burst of events recorded.")
+	flag.DurationVar(&reportResultsTTL, "reportResultsTTL", 0, "Maximum time since the results of the policy reports were last reported, e.g. 168h. The results are not pruned when set to 0.")
+	flag.BoolVar(&reportOwners, "reportOwners", false, "Set this flag to 'true' to attribute the report results of the controlled resources, e.g. Pods, to their top-level controlling owner, e.g. the Deployment.")
+	flag.DurationVar(&reportFlushInterval, "reportFlushInterval", 3*time.Second, "Interval of the creation of the report change requests, the results of the admission requests are buffered and merged in between.")
+	flag.IntVar(&maxReportResultsPerPolicy, "maxReportResultsPerPolicy", 0, "Maximum number of results of a policy in each policy report, the most recent results are kept. The results are not limited when set to 0.")
+	flag.StringVar(&metricsPort, "metricsPort", "8000", "Port of the Prometheus metrics endpoint /metrics, the metrics are disabled when empty.")
+	flag.StringVar(&notifiersConfig, "notifiersConfig", "", "Path to the configuration of the notifiers of the policy violations and blocked requests, e.g. webhook, slack or syslog.")
+	flag.StringVar(&captureConfig, "captureConfig", "", "Path to the configuration of the capture of a sample of the admission requests, the sanitized requests are written to a directory or posted to a webhook and replayed with the CLI.")
+	flag.StringVar(&certManagerCertificate, "certManagerCertificate", "", "Name of the cert-manager Certificate of the webhook server in the Kyverno namespace, the serving certificate is read from its secret instead of being self-signed.")
+	flag.StringVar(&certManagerSecret, "certManagerSecret", "", "Name of the secret issued by cert-manager with the serving certificate of the webhook server in the Kyverno namespace, instead of a self-signed certificate.")
+	flag.StringVar(&tlsCertFile, "tlsCertFile", "", "Path to the serving certificate of the webhook server provided externally, e.g. by a mounted secret. The self-signed certificate is not generated when set.")
+	flag.StringVar(&tlsKeyFile, "tlsKeyFile", "", "Path to the private key of the serving certificate provided externally.")
+	flag.StringVar(&tlsCAFile, "tlsCAFile", "", "Path to the CA certificate of the serving certificate provided externally, it is injected in the webhook configurations. The CA bundle of the webhook configurations is empty without it, for the certificates trusted by the system roots of the API server.")
+	flag.StringVar(&tlsMinVersion, "tlsMinVersion", "1.2", "Minimum TLS version of the webhook server, 1.2 or 1.3.")
+	flag.StringVar(&tlsCipherSuites, "tlsCipherSuites", "", "Comma separated list of the TLS 1.2 cipher suites of the webhook server, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256. The defaults of the Go library are used when empty.")
+	flag.DurationVar(&certValidity, "certValidity", 10*365*24*time.Hour, "Validity period of the self-signed serving certificate and of its CA.")
+	flag.StringVar(&certDNSNames, "certDNSNames", "", "Comma separated list of the additional DNS names of the self-signed serving certificate, e.g. the custom names of the webhook service.")
+	flag.StringVar(&certKeyAlgorithm, "certKeyAlgorithm", tlsutils.KeyAlgorithmRSA, "Key algorithm of the self-signed serving certificate, RSA or ECDSA.")
+	flag.DurationVar(&certRenewBefore, "certRenewBefore", 30*24*time.Hour, "Duration before the expiry of the self-signed serving certificate when it is renewed.")
+	flag.DurationVar(&backgroundScanInterval, "backgroundScanInterval", time.Hour, "Interval of the background scans of the existing resources, the validation rules of the policies are applied on the resources and the results are reported. The resources are only scanned when the policies change when set to 0.")
+	flag.IntVar(&backgroundScanWorkers, "backgroundScanWorkers", 2, "Number of policies scanned concurrently by the background scans.")
+	flag.IntVar(&backgroundScanKindConcurrency, "backgroundScanKindConcurrency", 1, "Number of namespaces scanned concurrently for each kind of a policy rule by the background scans.")
+	flag.IntVar(&backgroundScanMaxListCalls, "backgroundScanMaxListCalls", 0, "Maximum number of concurrent list requests to the API server of the background scans, for the resources which are not in the informer caches. The requests are not limited when set to 0.")
+	flag.Int64Var(&backgroundScanListPageSize, "backgroundScanListPageSize", 500, "Number of resources of each page of the list requests to the API server of the background scans, for the resources which are not in the informer caches. The resources are listed at once when set to 0.")
+	flag.Float64Var(&backgroundScanListQPS, "backgroundScanListQPS", 10, "Maximum number of pages listed per second from the API server by the background scans, for the resources which are not in the informer caches. The rate is not limited when set to 0.")
+	flag.IntVar(&backgroundScanListBurst, "backgroundScanListBurst", 20, "Maximum burst of pages listed from the API server by the background scans.")
+	flag.BoolVar(&mutateExistingDryRun, "mutateExistingDryRun", false, "Set this flag to 'true', to report the mutations of existing resources without persisting them.")
 	if err := flag.Set("v", "2"); err != nil {
 		setupLog.Error(err, "failed to set log level")
 		os.Exit(1)
@@ -95,6 +171,28 @@ func main() {
 
 	}
 
+	// METRICS
+	// - serves the Prometheus metrics, e.g. the violations of the policy reports, the coverage of the rules, the compliance of the policies and the dropped events and notifications
+	var violations *metrics.Violations
+	var coverage *metrics.Coverage
+	var compliance *metrics.Compliance
+	var droppedEvents, droppedNotifications *metrics.DroppedEvents
+	if metricsPort != "" {
+		violations = metrics.NewViolations(prometheus.DefaultRegisterer)
+		coverage = metrics.NewCoverage(prometheus.DefaultRegisterer)
+		compliance = metrics.NewCompliance(prometheus.DefaultRegisterer)
+		droppedEvents = metrics.NewDroppedEvents(prometheus.DefaultRegisterer)
+		droppedNotifications = metrics.NewDroppedNotifications(prometheus.DefaultRegisterer)
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", promhttp.Handler())
+		go func() {
+			if err := http.ListenAndServe(":"+metricsPort, metricsMux); err != nil {
+				setupLog.Error(err, "Failed to serve the metrics")
+				os.Exit(1)
+			}
+		}()
+	}
+
 	// KYVERNO CRD CLIENT
 	// access CRD resources
 	//		- ClusterPolicy, Policy
@@ -152,6 +250,7 @@ func main() {
 	//		- ClusterPolicyReport, PolicyReport
 	//		- GenerateRequest
 	//		- ClusterReportChangeRequest, ReportChangeRequest
+	//		- GlobalContextEntry
 	pInformer := kyvernoinformer.NewSharedInformerFactoryWithOptions(pclient, resyncPeriod)
 
 	// Configuration Data
@@ -173,13 +272,39 @@ func main() {
 		client,
 		pInformer.Kyverno().V1().ClusterPolicies(),
 		rCache,
+		eventsQPS,
+		eventsBurst,
+		droppedEvents,
 		log.Log.WithName("EventGenerator"))
 
+	// NOTIFIER
+	// - pushes the violation and enforcement events to the configured notifiers
+	var notifierDispatcher *notifier.Dispatcher
+	if notifiersConfig != "" {
+		notifierCfg, err := notifier.LoadConfig(notifiersConfig)
+		if err != nil {
+			setupLog.Error(err, "Failed to load the notifiers configuration")
+			os.Exit(1)
+		}
+
+		notifierDispatcher, err = notifier.NewDispatcher(notifierCfg, droppedNotifications, log.Log.WithName("Notifier"))
+		if err != nil {
+			setupLog.Error(err, "Failed to create the notifiers")
+			os.Exit(1)
+		}
+
+		eventGenerator.AddSink(notifierDispatcher)
+	}
+
 	// Policy Status Handler - deals with all logic related to policy status
 	statusSync := policystatus.NewSync(
 		pclient,
 		pInformer.Kyverno().V1().ClusterPolicies().Lister(),
-		pInformer.Kyverno().V1().Policies().Lister())
+		pInformer.Kyverno().V1().Policies().Lister(),
+		pInformer.Wgpolicyk8s().V1alpha1().PolicyReports().Lister(),
+		pInformer.Wgpolicyk8s().V1alpha1().ClusterPolicyReports().Lister(),
+		coverage,
+		compliance)
 
 	// POLICY Report GENERATOR
 	// -- generate policy report
@@ -192,6 +317,9 @@ func main() {
 		pInformer.Kyverno().V1().ClusterPolicies(),
 		pInformer.Kyverno().V1().Policies(),
 		statusSync.Listener,
+		reportFlushInterval,
+		rCache,
+		reportOwners,
 		log.Log.WithName("ReportChangeRequestGenerator"),
 	)
 
@@ -201,6 +329,8 @@ func main() {
 		pInformer.Kyverno().V1alpha1().ReportChangeRequests(),
 		pInformer.Kyverno().V1alpha1().ClusterReportChangeRequests(),
 		kubeInformer.Core().V1().Namespaces(),
+		policyreport.ResultRetention{TTL: reportResultsTTL, MaxResultsPerPolicy: maxReportResultsPerPolicy},
+		violations,
 		log.Log.WithName("PolicyReportGenerator"),
 	)
 
@@ -208,6 +338,16 @@ func main() {
 	// - reconciliation policy and policy violation
 	// - process policy on existing resources
 	// - status aggregator: receives stats when a policy is applied & updates the policy status
+	if backgroundScanWorkers < 1 {
+		setupLog.Error(fmt.Errorf("backgroundScanWorkers must be at least 1, got %d", backgroundScanWorkers), "Invalid background scan configuration")
+		os.Exit(1)
+	}
+
+	if backgroundScanListQPS > 0 && backgroundScanListBurst < 1 {
+		setupLog.Error(fmt.Errorf("backgroundScanListBurst must be at least 1 when the rate is limited, got %d", backgroundScanListBurst), "Invalid background scan configuration")
+		os.Exit(1)
+	}
+
 	policyCtrl, err := policy.NewPolicyController(pclient,
 		client,
 		pInformer.Kyverno().V1().ClusterPolicies(),
@@ -219,6 +359,14 @@ func main() {
 		kubeInformer.Core().V1().Namespaces(),
 		log.Log.WithName("PolicyController"),
 		rCache,
+		policy.ScanOptions{
+			Interval:        backgroundScanInterval,
+			KindConcurrency: backgroundScanKindConcurrency,
+			MaxListCalls:    backgroundScanMaxListCalls,
+			ListPageSize:    backgroundScanListPageSize,
+			ListQPS:         backgroundScanListQPS,
+			ListBurst:       backgroundScanListBurst,
+		},
 	)
 
 	if err != nil {
@@ -235,6 +383,7 @@ func main() {
 		pclient,
 		client,
 		pInformer.Kyverno().V1().ClusterPolicies(),
+		pInformer.Kyverno().V1().Policies(),
 		pInformer.Kyverno().V1().GenerateRequests(),
 		eventGenerator,
 		kubedynamicInformer,
@@ -242,6 +391,8 @@ func main() {
 		log.Log.WithName("GenerateController"),
 		configData,
 		rCache,
+		generateQPS,
+		generateBurst,
 	)
 	if err != nil {
 		setupLog.Error(err, "Failed to create generate controller")
@@ -254,6 +405,7 @@ func main() {
 		pclient,
 		client,
 		pInformer.Kyverno().V1().ClusterPolicies(),
+		pInformer.Kyverno().V1().Policies(),
 		pInformer.Kyverno().V1().GenerateRequests(),
 		kubedynamicInformer,
 		log.Log.WithName("GenerateCleanUpController"),
@@ -269,6 +421,42 @@ func main() {
 		log.Log.WithName("PolicyCacheController"),
 	)
 
+	// MUTATE EXISTING CONTROLLER
+	// - applies mutate rules with targets to existing resources
+	mutateExistingController := mutateexisting.NewController(
+		client,
+		pInformer.Kyverno().V1().ClusterPolicies(),
+		pInformer.Kyverno().V1().Policies(),
+		kubeInformer.Core().V1().Namespaces(),
+		configData,
+		rCache,
+		mutateExistingDryRun,
+		log.Log.WithName("MutateExistingController"),
+	)
+
+	// GENERATE EXISTING CONTROLLER
+	// - applies generate rules with generateExisting to existing resources
+	generateExistingController := generateexisting.NewController(
+		client,
+		pInformer.Kyverno().V1().ClusterPolicies(),
+		pInformer.Kyverno().V1().Policies(),
+		kubeInformer.Core().V1().Namespaces(),
+		grgen,
+		configData,
+		rCache,
+		log.Log.WithName("GenerateExistingController"),
+	)
+
+	// GLOBAL CONTEXT CONTROLLER
+	// - refreshes the data of the global context entries referenced by the rules
+	globalContextController := globalcontext.NewController(
+		client,
+		pInformer.Kyverno().V1().GlobalContextEntries(),
+		log.Log.WithName("GlobalContextController"),
+	)
+	engine.SetGlobalContextStore(globalContextController)
+	engine.SetSecretAllowlist(configData)
+
 	auditHandler := webhooks.NewValidateAuditHandler(
 		pCacheController.Cache,
 		eventGenerator,
@@ -284,12 +472,100 @@ func main() {
 	)
 
 	// Configure certificates
-	tlsPair, err := client.InitTLSPemPair(clientConfig, serverIP)
+	// - the certificate provided externally is read from the files, e.g. of a mounted secret
+	// - the certificate issued by cert-manager is read from its secret
+	// - otherwise a self-signed certificate is generated
+	// the CA of the certificates which are not self-signed is injected in the webhook configurations
+	var tlsPair *tlsutils.PemPair
+	var caData []byte
+	var certManagerWatcher *certmanager.Watcher
+	if certManagerCertificate != "" && certManagerSecret == "" {
+		certManagerSecret, err = client.GetCertManagerSecretName(certManagerCertificate)
+		if err != nil {
+			setupLog.Error(err, "Failed to get the secret of the cert-manager certificate")
+			os.Exit(1)
+		}
+	}
+
+	externalCert := tlsCertFile != "" || certManagerSecret != ""
+	certOptions := tlsutils.CertificateOptions{
+		Validity:     certValidity,
+		KeyAlgorithm: certKeyAlgorithm,
+	}
+	for _, dnsName := range strings.Split(certDNSNames, ",") {
+		if dnsName = strings.TrimSpace(dnsName); dnsName != "" {
+			certOptions.DNSNames = append(certOptions.DNSNames, dnsName)
+		}
+	}
+	if err := certOptions.Validate(); err != nil {
+		setupLog.Error(err, "Invalid self-signed certificate settings")
+		os.Exit(1)
+	}
+	if !externalCert && certValidity <= certRenewBefore {
+		setupLog.Error(fmt.Errorf("certificate validity %v must be longer than the renewal period %v", certValidity, certRenewBefore), "Invalid self-signed certificate settings")
+		os.Exit(1)
+	}
+	if tlsCertFile != "" {
+		tlsPair, caData, err = tlsutils.ReadPemPairFiles(tlsCertFile, tlsKeyFile, tlsCAFile)
+		if err != nil {
+			setupLog.Error(err, "Failed to read the TLS key/certificate pair files")
+			os.Exit(1)
+		}
+	} else if certManagerSecret != "" {
+		tlsPair, caData, err = client.ReadTLSSecret(certManagerSecret)
+		if err != nil {
+			setupLog.Error(err, "Failed to read the TLS key/certificate pair issued by cert-manager")
+			os.Exit(1)
+		}
+	} else {
+		tlsPair, err = client.InitTLSPemPair(clientConfig, serverIP, certOptions)
+		if err != nil {
+			setupLog.Error(err, "Failed to initialize TLS key/certificate pair")
+			os.Exit(1)
+		}
+	}
+
+	if externalCert {
+		// the certificate must be valid for the webhook service, or for the server IP when running outside a cluster
+		certProps, err := client.GetTLSCertProps(clientConfig)
+		if err != nil {
+			setupLog.Error(err, "Failed to get TLS Cert Properties")
+			os.Exit(1)
+		}
+		dnsName := tlsutils.GenerateInClusterServiceName(certProps)
+		if serverIP != "" {
+			dnsName = serverIP
+			if host, _, err := net.SplitHostPort(serverIP); err == nil {
+				dnsName = host
+			}
+		}
+
+		if err := tlsutils.ValidateServingCertificate(tlsPair, caData, dnsName); err != nil {
+			setupLog.Error(err, "Invalid TLS key/certificate pair")
+			os.Exit(1)
+		}
+		webhookCfg.SetCABundle(caData)
+	}
+
+	certificates, err := tlsutils.NewCertificateHolder(tlsPair)
+	if err != nil {
+		setupLog.Error(err, "Failed to load TLS key/certificate pair")
+		os.Exit(1)
+	}
+
+	tlsOptions, err := tlsutils.NewServerOptions(tlsMinVersion, tlsCipherSuites)
 	if err != nil {
-		setupLog.Error(err, "Failed to initialize TLS key/certificate pair")
+		setupLog.Error(err, "Invalid TLS settings of the webhook server")
 		os.Exit(1)
 	}
 
+	var certRenewer *webhookconfig.CertRenewer
+	if certManagerSecret != "" {
+		certManagerWatcher = certmanager.NewWatcher(client, certManagerSecret, certificates, webhookCfg, log.Log.WithName("CertManagerWatcher"))
+	} else if !externalCert {
+		certRenewer = webhookconfig.NewCertRenewer(client, clientConfig, serverIP, certificates, certOptions, webhookCfg, certRenewBefore, log.Log.WithName("CertRenewer"))
+	}
+
 	// Register webhookCfg
 	if err = webhookCfg.Register(); err != nil {
 		setupLog.Error(err, "Failed to register admission control webhooks")
@@ -314,10 +590,29 @@ func main() {
 	// -- generate policy violation resource
 	// -- generate events on policy and resource
 	debug := serverIP != ""
+
+	// CAPTURE
+	// - writes a sample of the sanitized admission requests to the configured store, to be replayed with the CLI
+	var capturer *capture.Capturer
+	if captureConfig != "" {
+		captureCfg, err := capture.LoadConfig(captureConfig)
+		if err != nil {
+			setupLog.Error(err, "Failed to load the capture configuration")
+			os.Exit(1)
+		}
+
+		capturer, err = capture.NewCapturer(captureCfg, log.Log.WithName("Capture"))
+		if err != nil {
+			setupLog.Error(err, "Failed to create the capture store")
+			os.Exit(1)
+		}
+	}
+
 	server, err := webhooks.NewWebhookServer(
 		pclient,
 		client,
-		tlsPair,
+		certificates,
+		tlsOptions,
 		pInformer.Kyverno().V1().GenerateRequests(),
 		pInformer.Kyverno().V1().ClusterPolicies(),
 		kubeInformer.Rbac().V1().RoleBindings(),
@@ -325,6 +620,8 @@ func main() {
 		kubeInformer.Rbac().V1().Roles(),
 		kubeInformer.Rbac().V1().ClusterRoles(),
 		kubeInformer.Core().V1().Namespaces(),
+		pInformer.Wgpolicyk8s().V1alpha1().PolicyReports(),
+		pInformer.Wgpolicyk8s().V1alpha1().ClusterPolicyReports(),
 		eventGenerator,
 		pCacheController.Cache,
 		webhookCfg,
@@ -340,6 +637,8 @@ func main() {
 		openAPIController,
 		rCache,
 		grc,
+		mutateExistingController,
+		capturer,
 		debug,
 	)
 
@@ -357,13 +656,28 @@ func main() {
 	go prgen.Run(1, stopCh)
 	go grgen.Run(1, stopCh)
 	go configData.Run(stopCh)
-	go policyCtrl.Run(2, stopCh)
+	go policyCtrl.Run(backgroundScanWorkers, stopCh)
 	go eventGenerator.Run(3, stopCh)
+	if notifierDispatcher != nil {
+		go notifierDispatcher.Run(2, stopCh)
+	}
+	if capturer != nil {
+		go capturer.Run(stopCh)
+	}
 	go grc.Run(1, stopCh)
 	go grcc.Run(1, stopCh)
 	go statusSync.Run(1, stopCh)
 	go pCacheController.Run(1, stopCh)
 	go auditHandler.Run(10, stopCh)
+	go mutateExistingController.Run(2, stopCh)
+	go generateExistingController.Run(2, stopCh)
+	go globalContextController.Run(1, stopCh)
+	if certManagerWatcher != nil {
+		go certManagerWatcher.Run(stopCh)
+	}
+	if certRenewer != nil {
+		go certRenewer.Run(stopCh)
+	}
 	openAPISync.Run(1, stopCh)
 
 	// verifies if the admission control is enabled and active
@@ -384,6 +698,11 @@ func main() {
 	// cleanup webhookconfigurations followed by webhook shutdown
 	server.Stop(ctx)
 
+	// create the buffered report change requests, so that the results are not lost
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	reportReqGen.Flush(flushCtx)
+	cancelFlush()
+
 	// resource cleanup
 	// remove webhook configurations
 	<-cleanUp
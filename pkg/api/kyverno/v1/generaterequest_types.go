@@ -45,6 +45,17 @@ type GenerateRequestSpec struct {
 type GenerateRequestContext struct {
 	// +optional
 	UserRequestInfo RequestInfo `json:"userInfo,omitempty" yaml:"userInfo,omitempty"`
+
+	// AdmissionRequestInfo holds the details of the admission request which triggered the generate request.
+	// +optional
+	AdmissionRequestInfo AdmissionRequestInfoObject `json:"admissionRequestInfo,omitempty" yaml:"admissionRequestInfo,omitempty"`
+}
+
+// AdmissionRequestInfoObject stores the details of an admission request.
+type AdmissionRequestInfoObject struct {
+	// Operation is the type of the admission request operation.
+	// +optional
+	Operation string `json:"operation,omitempty" yaml:"operation,omitempty"`
 }
 
 // RequestInfo contains permission info carried in an admission request.
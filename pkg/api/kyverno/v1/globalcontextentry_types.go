@@ -0,0 +1,51 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GlobalContextEntry declares data that is fetched on an interval and shared by the rules of all policies.
+// Rules reference the data by the entry name, instead of fetching it for each admission request.
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=globalcontextentries,scope="Cluster",shortName=gctxentry
+// +kubebuilder:printcolumn:name="RefreshInterval",type="string",JSONPath=".spec.refreshInterval"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type GlobalContextEntry struct {
+	metav1.TypeMeta   `json:",inline,omitempty" yaml:",inline,omitempty"`
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// Spec declares the data source of the entry.
+	Spec GlobalContextEntrySpec `json:"spec" yaml:"spec"`
+}
+
+// GlobalContextEntrySpec declares the data source and the refresh interval of a
+// global context entry. Either an APICall or a ServiceCall must be provided.
+type GlobalContextEntrySpec struct {
+
+	// APICall defines an HTTP request to the Kubernetes API server. The JSON
+	// data retrieved is stored in the global context.
+	// +optional
+	APICall *APICall `json:"apiCall,omitempty" yaml:"apiCall,omitempty"`
+
+	// ServiceCall defines an HTTPS request to an external service. The JSON
+	// data retrieved is stored in the global context.
+	// +optional
+	ServiceCall *ServiceCall `json:"serviceCall,omitempty" yaml:"serviceCall,omitempty"`
+
+	// RefreshInterval is the interval at which the data is fetched again.
+	// Defaults to 10 minutes.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty"`
+}
+
+// GlobalContextEntryList is a list of GlobalContextEntry instances.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type GlobalContextEntryList struct {
+	metav1.TypeMeta `json:",inline" yaml:",inline"`
+	metav1.ListMeta `json:"metadata" yaml:"metadata"`
+	Items           []GlobalContextEntry `json:"items" yaml:"items"`
+}
@@ -53,6 +53,45 @@ type Spec struct {
 	// uses variables that are only available in the admission review request (e.g. user name).
 	// +optional
 	Background *bool `json:"background,omitempty" yaml:"background,omitempty"`
+
+	// StrictVariables controls if a variable which cannot be resolved in the preconditions or
+	// the deny conditions of a rule results in a rule failure (true), or in a condition which
+	// is not satisfied (false). Optional. The default value is "true" for policies with the
+	// enforce validationFailureAction and "false" otherwise.
+	// +optional
+	StrictVariables *bool `json:"strictVariables,omitempty" yaml:"strictVariables,omitempty"`
+
+	// SuppressEvents disables the events of the policy, e.g. for high-volume audit policies.
+	// The policy reports and the policy status are still recorded. Optional. The default
+	// value is "false", unless the policy has the "policies.kyverno.io/events: disabled" annotation.
+	// +optional
+	SuppressEvents bool `json:"suppressEvents,omitempty" yaml:"suppressEvents,omitempty"`
+
+	// Variables declares the variables used by the rules, with their types and default values.
+	// When variables are declared, the policy is rejected if a rule references an undeclared
+	// or malformed variable. Built-in variables (e.g. request) and context entries do not need
+	// to be declared.
+	// +optional
+	Variables []Variable `json:"variables,omitempty" yaml:"variables,omitempty"`
+}
+
+// Variable declares a variable used by the policy rules. The variable has the default value
+// unless a context entry of a rule with the same name sets it.
+type Variable struct {
+
+	// Name is the variable name used in the rules, e.g. {{ maxReplicas }}.
+	Name string `json:"name" yaml:"name"`
+
+	// Type is the JSON type of the value, one of string, number, boolean, array or object.
+	// When set, the value is checked before the rules are applied. Optional.
+	// +kubebuilder:validation:Enum=string;number;boolean;array;object
+	// +optional
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Default is the value of the variable when no context entry sets it.
+	// +kubebuilder:validation:XPreserveUnknownFields
+	// +optional
+	Default apiextensions.JSON `json:"default,omitempty" yaml:"default,omitempty"`
 }
 
 // Rule defines a validation, mutation, or generation control for matching resources.
@@ -64,6 +103,23 @@ type Rule struct {
 	// +kubebuilder:validation:MaxLength=63
 	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 
+	// Category groups the results of the rule in the policy reports, it overrides the
+	// policies.kyverno.io/category annotation of the policy. Optional.
+	// +optional
+	Category string `json:"category,omitempty" yaml:"category,omitempty"`
+
+	// Severity is the severity of the results of the rule, one of critical, high, medium or low.
+	// It is reported in the policy reports and in the events of the rule, and overrides the
+	// policies.kyverno.io/severity annotation of the policy. Optional.
+	// +kubebuilder:validation:Enum=critical;high;medium;low
+	// +optional
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+
+	// ReportProperties are added to the data of the results of the rule in the policy reports,
+	// e.g. the offending image or a remediation link. The values may contain variables.
+	// +optional
+	ReportProperties map[string]string `json:"reportProperties,omitempty" yaml:"reportProperties,omitempty"`
+
 	// Context defines variables and data sources that can be used during rule execution.
 	// +optional
 	Context []ContextEntry `json:"context,omitempty" yaml:"context,omitempty"`
@@ -123,7 +179,8 @@ type AnyAllConditions struct {
 }
 
 // ContextEntry adds variables and data sources to a rule Context. Either a
-// ConfigMap reference or a APILookup must be provided.
+// ConfigMap reference, an APILookup, an ImageRegistry, a ServiceCall or a
+// GlobalReference must be provided.
 type ContextEntry struct {
 
 	// Name is the variable name.
@@ -135,6 +192,23 @@ type ContextEntry struct {
 	// APICall defines an HTTP request to the Kubernetes API server. The JSON
 	// data retrieved is stored in the context.
 	APICall *APICall `json:"apiCall,omitempty" yaml:"apiCall,omitempty"`
+
+	// ImageRegistry defines requests to an OCI/Docker V2 registry to fetch image
+	// details. The image data retrieved is stored in the context.
+	ImageRegistry *ImageRegistry `json:"imageRegistry,omitempty" yaml:"imageRegistry,omitempty"`
+
+	// ServiceCall defines an HTTPS request to an external service. The JSON
+	// data retrieved is stored in the context.
+	ServiceCall *ServiceCall `json:"serviceCall,omitempty" yaml:"serviceCall,omitempty"`
+
+	// GlobalReference refers to a GlobalContextEntry. The data of the global
+	// context entry is stored in the context.
+	GlobalReference *GlobalContextEntryReference `json:"globalReference,omitempty" yaml:"globalReference,omitempty"`
+
+	// Secret refers to a key of a Secret. The decoded value of the key is stored in
+	// the context. The Secret must be allowed by the allowedSecrets of the Kyverno
+	// configuration.
+	Secret *SecretKeyReference `json:"secret,omitempty" yaml:"secret,omitempty"`
 }
 
 // ConfigMapReference refers to a ConfigMap
@@ -147,6 +221,18 @@ type ConfigMapReference struct {
 	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 }
 
+// GlobalContextEntryReference refers to a GlobalContextEntry
+type GlobalContextEntryReference struct {
+
+	// Name is the GlobalContextEntry name.
+	Name string `json:"name" yaml:"name"`
+
+	// JMESPath is an optional JSON Match Expression that can be used to
+	// transform the data of the global context entry.
+	// +optional
+	JMESPath string `json:"jmesPath,omitempty" yaml:"jmesPath,omitempty"`
+}
+
 // APICall defines an HTTP request to the Kubernetes API server. The JSON
 // data retrieved is stored in the context. An APICall contains a URLPath
 // used to perform the HTTP GET request and an optional JMESPath used to
@@ -167,6 +253,71 @@ type APICall struct {
 	JMESPath string `json:"jmesPath,omitempty" yaml:"jmesPath,omitempty"`
 }
 
+// ImageRegistry defines requests to an OCI/Docker V2 registry to fetch image
+// details. The image manifest and configuration are retrieved for the image
+// reference, the registry credentials are read from the imagePullSecrets of
+// the resource.
+type ImageRegistry struct {
+
+	// Reference is the image reference to a container image in the registry
+	// (e.g. "ghcr.io/kyverno/kyverno:latest" or "{{element.image}}").
+	Reference string `json:"reference" yaml:"reference"`
+
+	// JMESPath is an optional JSON Match Expression that can be used to
+	// transform the image data. For example a JMESPath of "configData.config.Labels"
+	// returns the labels of the image.
+	// +optional
+	JMESPath string `json:"jmesPath,omitempty" yaml:"jmesPath,omitempty"`
+}
+
+// ServiceCall defines an HTTPS GET request to an external service. The JSON
+// data retrieved is stored in the context. A ServiceCall contains the URL of
+// the service, the TLS and authentication settings of the request and an
+// optional JMESPath used to transform the retrieved JSON data.
+type ServiceCall struct {
+
+	// URL is the HTTPS URL of the service (e.g. "https://cmdb.corp.com/apps/{{request.namespace}}").
+	URL string `json:"url" yaml:"url"`
+
+	// CABundle is a PEM encoded CA bundle used to validate the certificate of
+	// the service. The system roots are used if it is not set.
+	// +optional
+	CABundle string `json:"caBundle,omitempty" yaml:"caBundle,omitempty"`
+
+	// AuthSecret references the key of a Secret holding the value of the
+	// Authorization header of the request (e.g. "Bearer <token>").
+	// +optional
+	AuthSecret *SecretKeyReference `json:"authSecret,omitempty" yaml:"authSecret,omitempty"`
+
+	// TimeoutSeconds is the timeout of the request. Defaults to 5 seconds.
+	// +optional
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"`
+
+	// CacheSeconds is the duration for which the response is cached and reused
+	// by subsequent requests to the same URL. The response is not cached across
+	// admission requests if it is not set.
+	// +optional
+	CacheSeconds int64 `json:"cacheSeconds,omitempty" yaml:"cacheSeconds,omitempty"`
+
+	// JMESPath is an optional JSON Match Expression that can be used to
+	// transform the JSON response returned from the service.
+	// +optional
+	JMESPath string `json:"jmesPath,omitempty" yaml:"jmesPath,omitempty"`
+}
+
+// SecretKeyReference refers to a key of a Secret
+type SecretKeyReference struct {
+
+	// Name is the Secret name.
+	Name string `json:"name" yaml:"name"`
+
+	// Namespace is the Secret namespace.
+	Namespace string `json:"namespace" yaml:"namespace"`
+
+	// Key is the key of the Secret data.
+	Key string `json:"key" yaml:"key"`
+}
+
 // Condition defines variable-based conditional criteria for rule execution.
 type Condition struct {
 	// Key is the context entry (using JMESPath) for conditional rule evaluation.
@@ -203,13 +354,13 @@ const (
 	In ConditionOperator = "In"
 	// NotIn evaluates if the key is not contained in the set of values.
 	NotIn ConditionOperator = "NotIn"
-	// GreaterThanOrEquals evaluates if the key (numeric) is greater than or equal to the value (numeric).
+	// GreaterThanOrEquals evaluates if the key (numeric, quantity, semantic version or duration) is greater than or equal to the value.
 	GreaterThanOrEquals ConditionOperator = "GreaterThanOrEquals"
-	// GreaterThan evaluates if the key (numeric) is greater than the value (numeric).
+	// GreaterThan evaluates if the key (numeric, quantity, semantic version or duration) is greater than the value.
 	GreaterThan ConditionOperator = "GreaterThan"
-	// LessThanOrEquals evaluates if the key (numeric) is less than or equal to the value (numeric).
+	// LessThanOrEquals evaluates if the key (numeric, quantity, semantic version or duration) is less than or equal to the value.
 	LessThanOrEquals ConditionOperator = "LessThanOrEquals"
-	// LessThan evaluates if the key (numeric) is less than the value (numeric).
+	// LessThan evaluates if the key (numeric, quantity, semantic version or duration) is less than the value.
 	LessThan ConditionOperator = "LessThan"
 )
 
@@ -318,8 +469,58 @@ type Mutation struct {
 	// See https://tools.ietf.org/html/rfc6902 and https://kubectl.docs.kubernetes.io/references/kustomize/patchesjson6902/.
 	// +optional
 	PatchesJSON6902 string `json:"patchesJson6902,omitempty" yaml:"patchesJson6902,omitempty"`
+
+	// MissingPathPolicy specifies how PatchesJSON6902 add and replace operations are handled
+	// when their path does not exist in the resource. "Create" (default) creates the missing
+	// parents of add operations, "Skip" ignores the operations and "Fail" fails the rule.
+	// +kubebuilder:validation:Enum=Create;Skip;Fail
+	// +optional
+	MissingPathPolicy MissingPathPolicy `json:"missingPathPolicy,omitempty" yaml:"missingPathPolicy,omitempty"`
+
+	// ForEachMutation applies a patchStrategicMerge or patchesJson6902 declaration to each
+	// element of a list selected from the resource. The current element and its index are
+	// available as the variables "element" and "elementIndex".
+	// +optional
+	ForEachMutation *ForEachMutation `json:"foreach,omitempty" yaml:"foreach,omitempty"`
+
+	// Targets specifies existing resources to mutate. The matched resource triggers the
+	// mutation, which is applied to each target in the background instead of the admission
+	// request. The target resource is available as the variable "target".
+	// +optional
+	Targets []ResourceSpec `json:"targets,omitempty" yaml:"targets,omitempty"`
+}
+
+// +k8s:deepcopy-gen=false
+
+// ForEachMutation specifies the list of elements and the patch applied for each element.
+type ForEachMutation struct {
+
+	// List is a JMESPath expression that selects the list of elements to mutate
+	// (e.g. "request.object.spec.containers").
+	List string `json:"list" yaml:"list"`
+
+	// PatchStrategicMerge is a strategic merge patch applied for each element.
+	// +kubebuilder:validation:XPreserveUnknownFields
+	// +optional
+	PatchStrategicMerge apiextensions.JSON `json:"patchStrategicMerge,omitempty" yaml:"patchStrategicMerge,omitempty"`
+
+	// PatchesJSON6902 is a list of RFC 6902 JSON Patch declarations applied for each element.
+	// +optional
+	PatchesJSON6902 string `json:"patchesJson6902,omitempty" yaml:"patchesJson6902,omitempty"`
 }
 
+// MissingPathPolicy specifies how RFC 6902 operations on missing paths are handled.
+type MissingPathPolicy string
+
+const (
+	// MissingPathCreate creates the missing parents of add operations.
+	MissingPathCreate MissingPathPolicy = "Create"
+	// MissingPathSkip skips the add and replace operations with a missing path.
+	MissingPathSkip MissingPathPolicy = "Skip"
+	// MissingPathFail fails the rule if an add or replace operation has a missing path.
+	MissingPathFail MissingPathPolicy = "Fail"
+)
+
 // +k8s:deepcopy-gen=false
 
 // Patch is a RFC 6902 JSON Patch.
@@ -360,6 +561,54 @@ type Validation struct {
 	// Deny defines conditions to fail the validation rule.
 	// +optional
 	Deny *Deny `json:"deny,omitempty" yaml:"deny,omitempty"`
+
+	// ForEachValidation applies a pattern, anyPattern or deny declaration to each element
+	// of a list selected from the resource. The current element and its index are
+	// available as the variables "element" and "elementIndex".
+	// +optional
+	ForEachValidation *ForEachValidation `json:"foreach,omitempty" yaml:"foreach,omitempty"`
+
+	// DeprecatedAPIs checks if the resource uses an API version which is deprecated
+	// or removed in the target Kubernetes version.
+	// +optional
+	DeprecatedAPIs *DeprecatedAPIs `json:"deprecatedAPIs,omitempty" yaml:"deprecatedAPIs,omitempty"`
+}
+
+// +k8s:deepcopy-gen=false
+
+// ForEachValidation specifies the list of elements and the checks applied to each element.
+type ForEachValidation struct {
+
+	// List is a JMESPath expression that selects the list of elements to validate
+	// (e.g. "request.object.spec.containers").
+	List string `json:"list" yaml:"list"`
+
+	// Pattern specifies an overlay-style pattern used to check each element.
+	// +kubebuilder:validation:XPreserveUnknownFields
+	// +optional
+	Pattern apiextensions.JSON `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+
+	// AnyPattern specifies list of validation patterns. At least one of the patterns
+	// must be satisfied by each element.
+	// +kubebuilder:validation:XPreserveUnknownFields
+	// +optional
+	AnyPattern apiextensions.JSON `json:"anyPattern,omitempty" yaml:"anyPattern,omitempty"`
+
+	// Deny defines conditions to fail the validation of an element.
+	// +optional
+	Deny *Deny `json:"deny,omitempty" yaml:"deny,omitempty"`
+}
+
+// DeprecatedAPIs specifies the target Kubernetes version used to check for deprecated API versions.
+type DeprecatedAPIs struct {
+
+	// KubernetesVersion is the target Kubernetes version (e.g. "1.22").
+	KubernetesVersion string `json:"kubernetesVersion" yaml:"kubernetesVersion"`
+
+	// RemovedOnly restricts the check to API versions removed in the target version.
+	// By default, API versions deprecated in the target version also fail the rule.
+	// +optional
+	RemovedOnly bool `json:"removedOnly,omitempty" yaml:"removedOnly,omitempty"`
 }
 
 // Deny specifies a list of conditions. The validation rule fails, if any Condition
@@ -384,6 +633,20 @@ type Generation struct {
 	// +optional
 	Synchronize bool `json:"synchronize,omitempty" yaml:"synchronize,omitempty"`
 
+	// TriggerDeletionPolicy specifies what happens to the generated resources when the trigger
+	// resource is deleted. "Delete" (default) deletes them, "Orphan" keeps them and removes the
+	// labels linking them to the generate request, and "Retain" keeps them, stops synchronizing
+	// them and labels them with policy.kyverno.io/trigger-deleted.
+	// +kubebuilder:validation:Enum=Delete;Orphan;Retain
+	// +optional
+	TriggerDeletionPolicy TriggerDeletionPolicy `json:"triggerDeletionPolicy,omitempty" yaml:"triggerDeletionPolicy,omitempty"`
+
+	// GenerateExisting controls if the rule is applied to the resources which exist when the
+	// policy is created or updated. The existing resources are processed in the background.
+	// Optional. Defaults to "false" if not specified.
+	// +optional
+	GenerateExisting bool `json:"generateExisting,omitempty" yaml:"generateExisting,omitempty"`
+
 	// Data provides the resource declaration used to populate each generated resource.
 	// At most one of Data or Clone must be specified. If neither are provided, the generated
 	// resource will be created with default data only.
@@ -396,8 +659,26 @@ type Generation struct {
 	// resource will be created with default data only.
 	// +optional
 	Clone CloneFrom `json:"clone,omitempty" yaml:"clone,omitempty"`
+
+	// CloneList selects the source resources cloned to the generated namespace, e.g. all
+	// the Secrets with a label. The generated resources have the names of the source
+	// resources, the name of the rule must not be set.
+	// +optional
+	CloneList *CloneList `json:"cloneList,omitempty" yaml:"cloneList,omitempty"`
 }
 
+// TriggerDeletionPolicy specifies how generated resources are handled when their trigger is deleted.
+type TriggerDeletionPolicy string
+
+const (
+	// TriggerDeletionDelete deletes the generated resources.
+	TriggerDeletionDelete TriggerDeletionPolicy = "Delete"
+	// TriggerDeletionOrphan keeps the generated resources, they are no longer managed by the generate request.
+	TriggerDeletionOrphan TriggerDeletionPolicy = "Orphan"
+	// TriggerDeletionRetain keeps the generated resources with the trigger-deleted label.
+	TriggerDeletionRetain TriggerDeletionPolicy = "Retain"
+)
+
 // CloneFrom provides the location of the source resource used to generate target resources.
 // The resource kind is derived from the match criteria.
 type CloneFrom struct {
@@ -410,6 +691,19 @@ type CloneFrom struct {
 	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 }
 
+// CloneList provides the location of the source resources used to generate target resources.
+// The resource kind is the kind of the generate rule.
+type CloneList struct {
+
+	// Namespace specifies the namespace of the source resources.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
+	// Selector is a label selector of the source resources. All the resources of the kind
+	// in the namespace are cloned if it is not specified.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty" yaml:"selector,omitempty"`
+}
+
 // PolicyStatus mostly contains runtime information related to policy execution.
 type PolicyStatus struct {
 	// AvgExecutionTime is the average time taken to process the policy rules on a resource.
@@ -443,6 +737,27 @@ type PolicyStatus struct {
 	// Rules provides per rule statistics
 	// +optional
 	Rules []RuleStats `json:"ruleStatus,omitempty" yaml:"ruleStatus,omitempty"`
+
+	// UnmatchedRules lists the rules which have not matched any resource yet, they may be
+	// mis-scoped or not needed anymore.
+	// +optional
+	UnmatchedRules []string `json:"unmatchedRules,omitempty" yaml:"unmatchedRules,omitempty"`
+
+	// Compliance summarizes the results of the policy in the policy reports.
+	// +optional
+	Compliance *ComplianceSummary `json:"compliance,omitempty" yaml:"compliance,omitempty"`
+}
+
+// ComplianceSummary summarizes the results of a policy in the policy reports.
+type ComplianceSummary struct {
+	// Evaluated is the number of resources with results of the policy.
+	Evaluated int `json:"evaluated" yaml:"evaluated"`
+
+	// Passed is the number of evaluated resources without any failure or error result.
+	Passed int `json:"passed" yaml:"passed"`
+
+	// Percentage is the percentage of the evaluated resources which passed, rounded down.
+	Percentage int `json:"percentage" yaml:"percentage"`
 }
 
 // RuleStats provides statistics for an individual rule within a policy.
@@ -462,6 +777,10 @@ type RuleStats struct {
 	// +optional
 	FailedCount int `json:"failedCount,omitempty" yaml:"failedCount,omitempty"`
 
+	// MatchedCount is the total number of resources matched by this rule.
+	// +optional
+	MatchedCount int `json:"matchedCount,omitempty" yaml:"matchedCount,omitempty"`
+
 	// AppliedCount is the total number of times this rule was applied.
 	// +optional
 	AppliedCount int `json:"appliedCount,omitempty" yaml:"appliedCount,omitempty"`
@@ -35,6 +35,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ClusterPolicyList{},
 		&GenerateRequest{},
 		&GenerateRequestList{},
+		&GlobalContextEntry{},
+		&GlobalContextEntryList{},
 		&Policy{},
 		&PolicyList{},
 	)
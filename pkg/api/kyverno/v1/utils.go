@@ -31,11 +31,62 @@ func (p *ClusterPolicy) BackgroundProcessingEnabled() bool {
 	return *p.Spec.Background
 }
 
+// StrictVariablesEnabled checks if unresolved variables fail the rules, by default for enforce policies
+func (p *ClusterPolicy) StrictVariablesEnabled() bool {
+	if p.Spec.StrictVariables == nil {
+		return p.Spec.ValidationFailureAction == "enforce"
+	}
+
+	return *p.Spec.StrictVariables
+}
+
+// EventsEnabled checks if the events of the policy are recorded, they are suppressed by the spec or the events annotation
+func (p *ClusterPolicy) EventsEnabled() bool {
+	if p.Spec.SuppressEvents {
+		return false
+	}
+
+	return p.GetAnnotations()["policies.kyverno.io/events"] != "disabled"
+}
+
+// IsScored checks if the failures of the policy are counted as failures, the failures of the
+// policies with the scored annotation set to false are counted as warnings
+func (p *ClusterPolicy) IsScored() bool {
+	return p.GetAnnotations()["policies.kyverno.io/scored"] != "false"
+}
+
+// GetRuleSeverity returns the severity of the rule, or the severity annotation of the policy
+func (p *ClusterPolicy) GetRuleSeverity(name string) string {
+	for _, rule := range p.Spec.Rules {
+		if rule.Name == name && rule.Severity != "" {
+			return rule.Severity
+		}
+	}
+
+	return p.GetAnnotations()["policies.kyverno.io/severity"]
+}
+
+// GetRuleCategory returns the category of the rule, or the category annotation of the policy
+func (p *ClusterPolicy) GetRuleCategory(name string) string {
+	for _, rule := range p.Spec.Rules {
+		if rule.Name == name && rule.Category != "" {
+			return rule.Category
+		}
+	}
+
+	return p.GetAnnotations()["policies.kyverno.io/category"]
+}
+
 // HasMutate checks for mutate rule
 func (r Rule) HasMutate() bool {
 	return !reflect.DeepEqual(r.Mutation, Mutation{})
 }
 
+// HasMutateExisting checks for mutate rule with targets
+func (r Rule) HasMutateExisting() bool {
+	return len(r.Mutation.Targets) != 0
+}
+
 // HasValidate checks for validate rule
 func (r Rule) HasValidate() bool {
 	return !reflect.DeepEqual(r.Validation, Validation{})
@@ -46,6 +97,11 @@ func (r Rule) HasGenerate() bool {
 	return !reflect.DeepEqual(r.Generation, Generation{})
 }
 
+// HasGenerateExisting checks for generate rule applied to existing resources
+func (r Rule) HasGenerateExisting() bool {
+	return r.HasGenerate() && r.Generation.GenerateExisting
+}
+
 // DeserializeAnyPattern deserialize apiextensions.JSON to []interface{}
 func (in *Validation) DeserializeAnyPattern() ([]interface{}, error) {
 	if in.AnyPattern == nil {
@@ -73,6 +129,23 @@ func (in *Mutation) DeepCopyInto(out *Mutation) {
 	}
 }
 
+// DeepCopyInto is declared because k8s:deepcopy-gen is
+// not able to generate this method for interface{} member
+func (in *ForEachMutation) DeepCopyInto(out *ForEachMutation) {
+	if out != nil {
+		*out = *in
+	}
+}
+
+// ElementMutation returns the mutation declaration applied for each element
+func (in *ForEachMutation) ElementMutation(missingPathPolicy MissingPathPolicy) *Mutation {
+	return &Mutation{
+		PatchStrategicMerge: in.PatchStrategicMerge,
+		PatchesJSON6902:     in.PatchesJSON6902,
+		MissingPathPolicy:   missingPathPolicy,
+	}
+}
+
 // DeepCopyInto is declared because k8s:deepcopy-gen is
 // not able to generate this method for interface{} member
 func (pp *Patch) DeepCopyInto(out *Patch) {
@@ -89,11 +162,30 @@ func (in *Validation) DeepCopyInto(out *Validation) {
 	}
 }
 
+// DeepCopyInto is declared because k8s:deepcopy-gen is
+// not able to generate this method for interface{} member
+func (in *ForEachValidation) DeepCopyInto(out *ForEachValidation) {
+	if out != nil {
+		*out = *in
+	}
+}
+
+// ElementValidation returns the validation declaration applied to each element
+func (in *ForEachValidation) ElementValidation(message string) *Validation {
+	return &Validation{
+		Message:    message,
+		Pattern:    in.Pattern,
+		AnyPattern: in.AnyPattern,
+		Deny:       in.Deny,
+	}
+}
+
 // DeepCopyInto is declared because k8s:deepcopy-gen is
 // not able to generate this method for interface{} member
 func (gen *Generation) DeepCopyInto(out *Generation) {
 	if out != nil {
 		*out = *gen
+		out.CloneList = gen.CloneList.DeepCopy()
 	}
 }
 
@@ -124,4 +216,12 @@ type ViolatedRule struct {
 
 	// +optional
 	Check string `json:"check" yaml:"check"`
+
+	// Specifies the severity of the rule.
+	// +optional
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+
+	// Specifies the report properties of the rule.
+	// +optional
+	Properties map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
 }
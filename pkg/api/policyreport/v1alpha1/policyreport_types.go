@@ -30,6 +30,18 @@ const (
 	StatusSkip  = "skip"
 )
 
+// ResultTimestampKey is the key of the result data which records when the result was reported,
+// the timestamp is kept while the status and the message of the result do not change
+const ResultTimestampKey = "reportedAt"
+
+// ResultLastSeenKey is the key of the result data which records when the result was last reported,
+// the timestamp is refreshed each time the result is reported again, e.g. by the background scans
+const ResultLastSeenKey = "lastSeenAt"
+
+// ResultCountKey is the key of the result data which records how many times the result was reported
+// since its timestamp, the results reported once have no count
+const ResultCountKey = "count"
+
 // PolicyReportSummary provides a status count summary
 type PolicyReportSummary struct {
 
@@ -65,10 +77,11 @@ type PolicyReportSummary struct {
 type PolicyStatus string
 
 // PolicySeverity has one of the following values:
+//   - critical
 //   - high
 //   - low
 //   - medium
-// +kubebuilder:validation:Enum=high;low;medium
+// +kubebuilder:validation:Enum=critical;high;low;medium
 type PolicySeverity string
 
 // PolicyReportResult provides the result for an individual policy
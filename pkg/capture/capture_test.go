@@ -0,0 +1,247 @@
+package capture
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"gotest.tools/assert"
+	v1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var secretRequest = &v1beta1.AdmissionRequest{
+	UID:       types.UID("b2c4"),
+	Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Secret"},
+	Namespace: "default",
+	Name:      "credentials",
+	Operation: v1beta1.Create,
+	UserInfo: authenticationv1.UserInfo{
+		Username: "alice",
+		Extra:    map[string]authenticationv1.ExtraValue{"authentication.kubernetes.io/credential-id": {"X509SHA256=abc"}},
+	},
+	Object: runtime.RawExtension{Raw: []byte(`{
+		"apiVersion": "v1",
+		"kind": "Secret",
+		"metadata": {
+			"name": "credentials",
+			"annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{}", "kubectl.kubernetes.io/restartedAt": "2021-04-01T10:00:00Z", "team": "payments"},
+			"managedFields": [{"manager": "kubectl"}]
+		},
+		"data": {"password": "cGFzc3dvcmQ="},
+		"stringData": {"token": "secret"}
+	}`)},
+}
+
+func Test_ParseConfig(t *testing.T) {
+	testcases := []struct {
+		description string
+		config      string
+		sampleRate  float64
+		err         string
+	}{
+		{
+			description: "valid configuration",
+			config: `
+kinds:
+- Pod
+store:
+  type: directory
+  path: /tmp/capture`,
+			sampleRate: 0.01,
+		},
+		{
+			description: "capture disabled",
+			config: `
+sampleRate: 0
+store:
+  type: directory
+  path: /tmp/capture`,
+			sampleRate: 0,
+		},
+		{
+			description: "invalid webhook",
+			config: `
+webhooks:
+- generate
+store:
+  type: directory
+  path: /tmp/capture`,
+			err: "invalid webhook generate, expected one of mutate or validate",
+		},
+		{
+			description: "missing path",
+			config: `
+store:
+  type: directory`,
+			err: "path is required for directory store",
+		},
+		{
+			description: "invalid sample rate",
+			config: `
+sampleRate: 2
+store:
+  type: webhook
+  url: https://capture.example.com`,
+			err: "invalid sample rate 2, expected a value between 0 and 1",
+		},
+		{
+			description: "invalid store type",
+			config: `
+store:
+  type: s3`,
+			err: "invalid store type s3, expected one of directory or webhook",
+		},
+	}
+
+	for _, testcase := range testcases {
+		config, err := ParseConfig([]byte(testcase.config))
+		if testcase.err != "" {
+			assert.Error(t, err, testcase.err, testcase.description)
+			continue
+		}
+
+		assert.NilError(t, err, testcase.description)
+		assert.Equal(t, *config.SampleRate, testcase.sampleRate, testcase.description)
+		assert.Equal(t, config.QueueSize, 100, testcase.description)
+		assert.Equal(t, config.Store.MaxRequests, 1000, testcase.description)
+	}
+}
+
+func Test_Sanitize(t *testing.T) {
+	review, err := Sanitize(secretRequest, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, review.Kind, "AdmissionReview")
+	assert.Assert(t, review.Request.UserInfo.Extra == nil)
+	assert.Equal(t, review.Request.UserInfo.Username, "alice")
+
+	var object map[string]interface{}
+	assert.NilError(t, json.Unmarshal(review.Request.Object.Raw, &object))
+	assert.DeepEqual(t, object["data"], map[string]interface{}{"password": ""})
+	assert.DeepEqual(t, object["stringData"], map[string]interface{}{"token": ""})
+
+	metadata := object["metadata"].(map[string]interface{})
+	assert.Assert(t, metadata["managedFields"] == nil)
+	assert.DeepEqual(t, metadata["annotations"], map[string]interface{}{"team": "payments"})
+
+	// the request is not modified
+	assert.Equal(t, len(secretRequest.UserInfo.Extra), 1)
+}
+
+func Test_Sanitize_Redactions(t *testing.T) {
+	request := &v1beta1.AdmissionRequest{
+		Kind: metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Object: runtime.RawExtension{Raw: []byte(`{
+			"apiVersion": "apps/v1",
+			"kind": "Deployment",
+			"metadata": {"name": "nginx", "annotations": {"internal.example.com/token": "abc"}},
+			"spec": {"template": {"spec": {
+				"containers": [{"name": "nginx", "image": "nginx", "env": [
+					{"name": "PASSWORD", "value": "password"},
+					{"name": "TOKEN", "valueFrom": {"secretKeyRef": {"name": "credentials", "key": "token"}}}
+				]}]
+			}}}
+		}`)},
+	}
+
+	review, err := Sanitize(request, []Redaction{{Kinds: []string{"deployment"}, Fields: []string{"metadata.annotations"}}})
+	assert.NilError(t, err)
+
+	var object map[string]interface{}
+	assert.NilError(t, json.Unmarshal(review.Request.Object.Raw, &object))
+	metadata := object["metadata"].(map[string]interface{})
+	assert.DeepEqual(t, metadata["annotations"], map[string]interface{}{"internal.example.com/token": ""})
+
+	containers := object["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["containers"].([]interface{})
+	env := containers[0].(map[string]interface{})["env"].([]interface{})
+	assert.DeepEqual(t, env[0], map[string]interface{}{"name": "PASSWORD", "value": ""})
+	assert.DeepEqual(t, env[1], map[string]interface{}{"name": "TOKEN", "valueFrom": map[string]interface{}{"secretKeyRef": map[string]interface{}{"name": "credentials", "key": "token"}}})
+
+	configMap := &v1beta1.AdmissionRequest{Object: runtime.RawExtension{Raw: []byte(`{"kind": "ConfigMap", "data": {"url": "https://example.com"}, "binaryData": {"key": "a2V5"}}`)}}
+	review, err = Sanitize(configMap, nil)
+	assert.NilError(t, err)
+	assert.NilError(t, json.Unmarshal(review.Request.Object.Raw, &object))
+	assert.DeepEqual(t, object["data"], map[string]interface{}{"url": ""})
+	assert.DeepEqual(t, object["binaryData"], map[string]interface{}{"key": ""})
+}
+
+func Test_DirectoryStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "capture")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewStore(StoreConfig{Type: DirectoryStore, Path: dir, MaxRequests: 2})
+	assert.NilError(t, err)
+
+	for _, uid := range []string{"first", "second", "third"} {
+		request := secretRequest.DeepCopy()
+		request.UID = types.UID(uid)
+		review, err := Sanitize(request, nil)
+		assert.NilError(t, err)
+		assert.NilError(t, store.Write(review))
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(files), 2)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+	assert.NilError(t, err)
+
+	review := &v1beta1.AdmissionReview{}
+	assert.NilError(t, json.Unmarshal(data, review))
+	assert.Equal(t, review.Request.UID, types.UID("second"))
+}
+
+func Test_Capture(t *testing.T) {
+	var received []v1beta1.AdmissionReview
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		review := v1beta1.AdmissionReview{}
+		_ = json.NewDecoder(r.Body).Decode(&review)
+		received = append(received, review)
+	}))
+	defer server.Close()
+
+	sampleRate := 1.0
+	config := &Config{SampleRate: &sampleRate, Webhooks: []string{ValidateWebhook}, Kinds: []string{"secret"}, QueueSize: 1, Store: StoreConfig{Type: WebhookStore, URL: server.URL}}
+	capturer, err := NewCapturer(config, logr.Discard())
+	assert.NilError(t, err)
+
+	pod := secretRequest.DeepCopy()
+	pod.Kind.Kind = "Pod"
+	dryRun := true
+	dryRunRequest := secretRequest.DeepCopy()
+	dryRunRequest.DryRun = &dryRun
+	capturer.Capture(ValidateWebhook, pod)
+	capturer.Capture(ValidateWebhook, dryRunRequest)
+	capturer.Capture(MutateWebhook, secretRequest)
+	capturer.Capture(ValidateWebhook, secretRequest)
+	// the queue is full
+	capturer.Capture(ValidateWebhook, secretRequest)
+	assert.Equal(t, len(capturer.queue), 1)
+
+	capturer.write(<-capturer.queue)
+	assert.Equal(t, len(received), 1)
+	assert.Equal(t, received[0].Request.Name, "credentials")
+	// the request is sanitized when it is stored
+	assert.Assert(t, received[0].Request.UserInfo.Extra == nil)
+
+	// the dry run requests are captured when they are enabled
+	config.DryRun = true
+	capturer.Capture(ValidateWebhook, dryRunRequest)
+	assert.Equal(t, len(capturer.queue), 1)
+
+	// no request is captured when the sample rate is 0
+	<-capturer.queue
+	sampleRate = 0
+	capturer.Capture(ValidateWebhook, secretRequest)
+	assert.Equal(t, len(capturer.queue), 0)
+}
@@ -0,0 +1,108 @@
+package capture
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1beta1 "k8s.io/api/admission/v1beta1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+)
+
+// Capturer captures a sample of the admission requests of the resource webhooks, the captured
+// requests are sanitized and written to the store asynchronously, so that the capture does not
+// delay the admission requests
+type Capturer struct {
+	config *Config
+	store  Store
+	queue  chan *v1beta1.AdmissionRequest
+	sample func() float64
+	log    logr.Logger
+}
+
+// NewCapturer returns a capturer of the configuration
+func NewCapturer(config *Config, log logr.Logger) (*Capturer, error) {
+	store, err := NewStore(config.Store)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Capturer{
+		config: config,
+		store:  store,
+		queue:  make(chan *v1beta1.AdmissionRequest, config.QueueSize),
+		sample: rand.New(rand.NewSource(time.Now().UnixNano())).Float64,
+		log:    log,
+	}, nil
+}
+
+// Capture queues a copy of the request of the webhook if it matches the filters and is sampled,
+// the request is dropped when the queue is full. The request is sanitized when it is stored.
+func (c *Capturer) Capture(webhook string, request *v1beta1.AdmissionRequest) {
+	if !c.matches(webhook, request) || c.sample() >= *c.config.SampleRate {
+		return
+	}
+
+	select {
+	case c.queue <- request.DeepCopy():
+	default:
+		c.log.V(3).Info("capture queue is full, dropping admission request", "uid", request.UID)
+	}
+}
+
+func (c *Capturer) matches(webhook string, request *v1beta1.AdmissionRequest) bool {
+	if !c.config.DryRun && request.DryRun != nil && *request.DryRun {
+		return false
+	}
+
+	return contains(c.config.Webhooks, webhook) &&
+		contains(c.config.Kinds, request.Kind.Kind) &&
+		contains(c.config.Namespaces, request.Namespace) &&
+		contains(c.config.Operations, string(request.Operation))
+}
+
+// contains checks if the value is one of the values, all values match when there is no value
+func contains(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run writes the captured requests to the store until the stop channel is closed
+func (c *Capturer) Run(stopCh <-chan struct{}) {
+	logger := c.log
+	defer utilruntime.HandleCrash()
+
+	logger.Info("start", "store", c.config.Store.Type, "sampleRate", *c.config.SampleRate)
+	defer logger.Info("shutting down")
+
+	for {
+		select {
+		case request := <-c.queue:
+			c.write(request)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// write sanitizes the request and writes it to the store
+func (c *Capturer) write(request *v1beta1.AdmissionRequest) {
+	review, err := Sanitize(request, c.config.Redactions)
+	if err != nil {
+		c.log.Error(err, "failed to sanitize admission request", "uid", request.UID)
+		return
+	}
+
+	if err := c.store.Write(review); err != nil {
+		c.log.Error(err, "failed to store admission request", "uid", request.UID)
+	}
+}
@@ -0,0 +1,133 @@
+package capture
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"sigs.k8s.io/yaml"
+)
+
+// the webhooks of the captured requests
+const (
+	MutateWebhook   = "mutate"
+	ValidateWebhook = "validate"
+)
+
+// defaultSampleRate is the sample rate when the configuration has none
+const defaultSampleRate = 0.01
+
+// the store types
+const (
+	DirectoryStore = "directory"
+	WebhookStore   = "webhook"
+)
+
+// Config configures the capture of the admission requests of the resource webhook
+type Config struct {
+	// SampleRate is the fraction of the matching requests which are captured, between 0 and 1,
+	// 0.01 by default, no request is captured with 0
+	SampleRate *float64 `json:"sampleRate,omitempty"`
+
+	// Webhooks, Kinds, Namespaces and Operations filter the captured requests, all requests are
+	// captured when they are empty. The webhooks are mutate and validate, the requests of the
+	// validating webhook have the mutated resources.
+	Webhooks   []string `json:"webhooks,omitempty"`
+	Kinds      []string `json:"kinds,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	Operations []string `json:"operations,omitempty"`
+
+	// DryRun captures the dry run requests, they are not captured by default
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Redactions are the fields emptied in addition to the data of the secrets and of the config
+	// maps and to the values of the environment variables of the containers
+	Redactions []Redaction `json:"redactions,omitempty"`
+
+	// QueueSize is the maximum number of requests waiting to be stored, the requests are dropped
+	// when the queue is full, 100 by default
+	QueueSize int `json:"queueSize,omitempty"`
+
+	// Store is where the captured requests are written
+	Store StoreConfig `json:"store"`
+}
+
+// StoreConfig configures the store of the captured requests
+type StoreConfig struct {
+	// Type is one of directory or webhook
+	Type string `json:"type"`
+
+	// Path is the directory of the directory store, a file per request
+	Path string `json:"path,omitempty"`
+
+	// MaxRequests is the maximum number of requests kept by the directory store, the oldest
+	// requests are removed first, 1000 by default
+	MaxRequests int `json:"maxRequests,omitempty"`
+
+	// URL is the endpoint of the webhook store, the requests are posted as AdmissionReview objects
+	URL string `json:"url,omitempty"`
+
+	// Headers are added to the requests of the webhook store
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// LoadConfig reads the capture configuration from a YAML or JSON file
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read capture configuration %s: %v", path, err)
+	}
+
+	return ParseConfig(data)
+}
+
+// ParseConfig parses and validates the capture configuration
+func ParseConfig(data []byte) (*Config, error) {
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse capture configuration: %v", err)
+	}
+
+	if config.SampleRate == nil {
+		sampleRate := defaultSampleRate
+		config.SampleRate = &sampleRate
+	}
+
+	if *config.SampleRate < 0 || *config.SampleRate > 1 {
+		return nil, fmt.Errorf("invalid sample rate %v, expected a value between 0 and 1", *config.SampleRate)
+	}
+
+	for _, webhook := range config.Webhooks {
+		if webhook != MutateWebhook && webhook != ValidateWebhook {
+			return nil, fmt.Errorf("invalid webhook %s, expected one of %s or %s", webhook, MutateWebhook, ValidateWebhook)
+		}
+	}
+
+	for _, redaction := range config.Redactions {
+		if len(redaction.Fields) == 0 {
+			return nil, fmt.Errorf("fields are required for the redaction of kinds %v", redaction.Kinds)
+		}
+	}
+
+	if config.QueueSize <= 0 {
+		config.QueueSize = 100
+	}
+
+	switch config.Store.Type {
+	case DirectoryStore:
+		if config.Store.Path == "" {
+			return nil, fmt.Errorf("path is required for %s store", config.Store.Type)
+		}
+
+		if config.Store.MaxRequests <= 0 {
+			config.Store.MaxRequests = 1000
+		}
+	case WebhookStore:
+		if config.Store.URL == "" {
+			return nil, fmt.Errorf("url is required for %s store", config.Store.Type)
+		}
+	default:
+		return nil, fmt.Errorf("invalid store type %s, expected one of %s or %s", config.Store.Type, DirectoryStore, WebhookStore)
+	}
+
+	return config, nil
+}
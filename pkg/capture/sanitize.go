@@ -0,0 +1,137 @@
+package capture
+
+import (
+	"encoding/json"
+	"strings"
+
+	v1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// kubectlAnnotationPrefix is the prefix of the kubectl annotations, e.g. the last applied
+// configuration which duplicates the resource, including the data of the secrets
+const kubectlAnnotationPrefix = "kubectl.kubernetes.io/"
+
+// Redaction empties the values of the fields of the resources of the kinds, the fields are paths
+// separated by dots where * matches all the keys of a map or all the items of a list, e.g.
+// spec.containers.*.env.*.value. When a field is a map, its values are emptied and its keys are
+// kept. The redaction applies to all the kinds when there is no kind.
+type Redaction struct {
+	Kinds  []string `json:"kinds,omitempty"`
+	Fields []string `json:"fields"`
+}
+
+// defaultRedactions are always applied, the redactions of the configuration are added to them
+var defaultRedactions = []Redaction{
+	{Kinds: []string{"Secret"}, Fields: []string{"data", "stringData"}},
+	{Kinds: []string{"ConfigMap"}, Fields: []string{"data", "binaryData"}},
+	{Fields: envValueFields()},
+}
+
+// envValueFields returns the paths of the environment variable values of the containers of the
+// pods and of the pod templates of the workloads
+func envValueFields() []string {
+	var fields []string
+	for _, podSpec := range []string{"spec", "spec.template.spec", "spec.jobTemplate.spec.template.spec"} {
+		for _, containers := range []string{"containers", "initContainers", "ephemeralContainers"} {
+			fields = append(fields, podSpec+"."+containers+".*.env.*.value")
+		}
+	}
+	return fields
+}
+
+// Sanitize returns the AdmissionReview of the request without its sensitive data: the fields of the
+// default redactions and of the redactions are emptied, and the extra user information, e.g. the
+// credential IDs, is removed. The managed fields and the kubectl annotations are removed.
+func Sanitize(request *v1beta1.AdmissionRequest, redactions []Redaction) (*v1beta1.AdmissionReview, error) {
+	sanitized := request.DeepCopy()
+	sanitized.UserInfo.Extra = nil
+
+	redactions = append(append([]Redaction{}, defaultRedactions...), redactions...)
+
+	var err error
+	if sanitized.Object.Raw, err = sanitizeObject(sanitized.Object.Raw, redactions); err != nil {
+		return nil, err
+	}
+
+	if sanitized.OldObject.Raw, err = sanitizeObject(sanitized.OldObject.Raw, redactions); err != nil {
+		return nil, err
+	}
+
+	sanitized.Object.Object = nil
+	sanitized.OldObject.Object = nil
+
+	return &v1beta1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+		Request:  sanitized,
+	}, nil
+}
+
+func sanitizeObject(raw []byte, redactions []Redaction) ([]byte, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, err
+	}
+
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "managedFields")
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			for key := range annotations {
+				if strings.HasPrefix(key, kubectlAnnotationPrefix) {
+					delete(annotations, key)
+				}
+			}
+		}
+	}
+
+	kind, _ := object["kind"].(string)
+	for _, redaction := range redactions {
+		if !contains(redaction.Kinds, kind) {
+			continue
+		}
+
+		for _, field := range redaction.Fields {
+			redact(object, strings.Split(field, "."))
+		}
+	}
+
+	return json.Marshal(object)
+}
+
+// redact empties the values of the path in the value, the missing fields are ignored
+func redact(value interface{}, path []string) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if path[0] != "*" && path[0] != key {
+				continue
+			}
+
+			if len(path) > 1 {
+				redact(child, path[1:])
+			} else if values, ok := child.(map[string]interface{}); ok {
+				for k := range values {
+					values[k] = ""
+				}
+			} else {
+				typed[key] = ""
+			}
+		}
+	case []interface{}:
+		if path[0] != "*" {
+			return
+		}
+
+		for i, child := range typed {
+			if len(path) > 1 {
+				redact(child, path[1:])
+			} else {
+				typed[i] = ""
+			}
+		}
+	}
+}
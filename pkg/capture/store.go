@@ -0,0 +1,130 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1beta1 "k8s.io/api/admission/v1beta1"
+)
+
+const requestTimeout = 10 * time.Second
+
+// Store writes the captured requests
+type Store interface {
+	Write(review *v1beta1.AdmissionReview) error
+}
+
+// NewStore returns the store of the configuration
+func NewStore(config StoreConfig) (Store, error) {
+	switch config.Type {
+	case DirectoryStore:
+		if err := os.MkdirAll(config.Path, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create the capture directory %s: %v", config.Path, err)
+		}
+		return &directoryStore{path: config.Path, maxRequests: config.MaxRequests}, nil
+	case WebhookStore:
+		return &webhookStore{url: config.URL, headers: config.Headers, client: &http.Client{Timeout: requestTimeout}}, nil
+	default:
+		return nil, fmt.Errorf("invalid store type %s", config.Type)
+	}
+}
+
+// directoryStore writes the requests to a directory, a JSON file per request, the files are named
+// after the time of the capture so that the oldest files are removed first
+type directoryStore struct {
+	path        string
+	maxRequests int
+	mutex       sync.Mutex
+}
+
+func (s *directoryStore) Write(review *v1beta1.AdmissionReview) error {
+	data, err := json.Marshal(review)
+	if err != nil {
+		return fmt.Errorf("failed to marshal admission review: %v", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	name := fmt.Sprintf("%d-%s.json", time.Now().UnixNano(), review.Request.UID)
+	if err := ioutil.WriteFile(filepath.Join(s.path, name), data, 0600); err != nil {
+		return err
+	}
+
+	return s.prune()
+}
+
+// prune removes the oldest requests when there are more than the maximum number of requests
+func (s *directoryStore) prune() error {
+	files, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".json") {
+			names = append(names, file.Name())
+		}
+	}
+
+	if len(names) <= s.maxRequests {
+		return nil
+	}
+
+	sort.Strings(names)
+	for _, name := range names[:len(names)-s.maxRequests] {
+		if err := os.Remove(filepath.Join(s.path, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// webhookStore posts the requests to an HTTP endpoint
+type webhookStore struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (s *webhookStore) Write(review *v1beta1.AdmissionReview) error {
+	data, err := json.Marshal(review)
+	if err != nil {
+		return fmt.Errorf("failed to marshal admission review: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("request failed with status %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package certmanager
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/go-logr/logr"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	tls "github.com/kyverno/kyverno/pkg/tls"
+	"github.com/kyverno/kyverno/pkg/webhookconfig"
+)
+
+// secretCheckInterval is the interval of the checks of the secret for a renewed certificate
+const secretCheckInterval time.Duration = time.Minute
+
+// Watcher watches the secret of the serving certificate issued by cert-manager. When the
+// certificate is renewed the webhook server serves the new certificate, and the CA of the
+// secret is injected in the webhook configurations.
+type Watcher struct {
+	client       *client.Client
+	secretName   string
+	certificates *tls.CertificateHolder
+	register     *webhookconfig.Register
+	log          logr.Logger
+}
+
+// NewWatcher returns a new instance of the watcher of the cert-manager secret
+func NewWatcher(client *client.Client, secretName string, certificates *tls.CertificateHolder, register *webhookconfig.Register, log logr.Logger) *Watcher {
+	return &Watcher{
+		client:       client,
+		secretName:   secretName,
+		certificates: certificates,
+		register:     register,
+		log:          log,
+	}
+}
+
+// Run checks the secret until the stop channel is closed
+func (w *Watcher) Run(stopCh <-chan struct{}) {
+	logger := w.log
+	logger.Info("watching the cert-manager secret", "name", w.secretName, "interval", secretCheckInterval)
+
+	ticker := time.NewTicker(secretCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.sync()
+
+		case <-stopCh:
+			logger.V(2).Info("stopping the cert-manager secret watcher")
+			return
+		}
+	}
+}
+
+func (w *Watcher) sync() {
+	logger := w.log.WithValues("name", w.secretName)
+	pemPair, caData, err := w.client.ReadTLSSecret(w.secretName)
+	if err != nil {
+		logger.Error(err, "failed to read the cert-manager secret")
+		return
+	}
+
+	if current := w.certificates.PemPair(); current != nil && bytes.Equal(current.Certificate, pemPair.Certificate) {
+		return
+	}
+
+	// inject the CA first, the clients must trust the renewed certificate before it is served
+	if len(caData) != 0 {
+		if err := w.register.UpdateCABundle(caData); err != nil {
+			logger.Error(err, "failed to inject the CA bundle in the webhook configurations")
+			return
+		}
+	}
+
+	if err := w.certificates.Update(pemPair); err != nil {
+		logger.Error(err, "failed to load the renewed certificate")
+		return
+	}
+
+	logger.Info("serving the renewed certificate")
+}
@@ -36,6 +36,10 @@ func (c *FakeKyvernoV1) GenerateRequests(namespace string) v1.GenerateRequestInt
 	return &FakeGenerateRequests{c, namespace}
 }
 
+func (c *FakeKyvernoV1) GlobalContextEntries() v1.GlobalContextEntryInterface {
+	return &FakeGlobalContextEntries{c}
+}
+
 func (c *FakeKyvernoV1) Policies(namespace string) v1.PolicyInterface {
 	return &FakePolicies{c, namespace}
 }
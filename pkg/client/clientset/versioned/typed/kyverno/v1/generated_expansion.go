@@ -22,4 +22,6 @@ type ClusterPolicyExpansion interface{}
 
 type GenerateRequestExpansion interface{}
 
+type GlobalContextEntryExpansion interface{}
+
 type PolicyExpansion interface{}
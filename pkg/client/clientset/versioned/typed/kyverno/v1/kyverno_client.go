@@ -28,6 +28,7 @@ type KyvernoV1Interface interface {
 	RESTClient() rest.Interface
 	ClusterPoliciesGetter
 	GenerateRequestsGetter
+	GlobalContextEntriesGetter
 	PoliciesGetter
 }
 
@@ -44,6 +45,10 @@ func (c *KyvernoV1Client) GenerateRequests(namespace string) GenerateRequestInte
 	return newGenerateRequests(c, namespace)
 }
 
+func (c *KyvernoV1Client) GlobalContextEntries() GlobalContextEntryInterface {
+	return newGlobalContextEntries(c)
+}
+
 func (c *KyvernoV1Client) Policies(namespace string) PolicyInterface {
 	return newPolicies(c, namespace)
 }
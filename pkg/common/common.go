@@ -5,11 +5,14 @@ import (
 	"strings"
 
 	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
 	enginutils "github.com/kyverno/kyverno/pkg/engine/utils"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/informers"
 	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -66,6 +69,36 @@ func GetNamespaceLabels(namespaceObj *v1.Namespace, logger logr.Logger) map[stri
 	return namespaceUnstructured.GetLabels()
 }
 
+// GetNamespaceAnnotationsFromGenericInformer - extract the namespace annotations when generic informer is passed
+func GetNamespaceAnnotationsFromGenericInformer(kind, namespaceOfResource string, nsInformer informers.GenericInformer, logger logr.Logger) map[string]string {
+	namespaceAnnotations := make(map[string]string)
+	if kind != "Namespace" {
+		runtimeNamespaceObj, err := nsInformer.Lister().Get(namespaceOfResource)
+		if err != nil {
+			logger.Error(err, "failed to get the namespace", "name", namespaceOfResource)
+			return namespaceAnnotations
+		}
+
+		unstructuredObj := runtimeNamespaceObj.(*unstructured.Unstructured)
+		return unstructuredObj.GetAnnotations()
+	}
+	return namespaceAnnotations
+}
+
+// GetNamespaceAnnotationsFromNamespaceLister - extract the namespace annotations when namespace lister is passed
+func GetNamespaceAnnotationsFromNamespaceLister(kind, namespaceOfResource string, nsLister listerv1.NamespaceLister, logger logr.Logger) map[string]string {
+	namespaceAnnotations := make(map[string]string)
+	if kind != "Namespace" {
+		namespaceObj, err := nsLister.Get(namespaceOfResource)
+		if err != nil {
+			logger.Error(err, "failed to get the namespace", "name", namespaceOfResource)
+			return namespaceAnnotations
+		}
+		return namespaceObj.GetAnnotations()
+	}
+	return namespaceAnnotations
+}
+
 // GetKindFromGVK - get kind and APIVersion from GVK
 func GetKindFromGVK(str string) (apiVersion string, kind string) {
 	if strings.Count(str, "/") == 0 {
@@ -77,3 +110,24 @@ func GetKindFromGVK(str string) (apiVersion string, kind string) {
 	}
 	return splitString[0] + "/" + splitString[1], splitString[2]
 }
+
+// GetPolicy returns the policy of the key, the keys of the namespaced policies are <namespace>/<name>
+// and the namespaced policies are returned as cluster policies
+func GetPolicy(pLister kyvernolister.ClusterPolicyLister, npLister kyvernolister.PolicyLister, key string) (*kyverno.ClusterPolicy, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if namespace == "" {
+		return pLister.Get(name)
+	}
+
+	policy, err := npLister.Policies(namespace).Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cpol := kyverno.ClusterPolicy(*policy)
+	return &cpol, nil
+}
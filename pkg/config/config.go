@@ -69,6 +69,12 @@ var (
 	//ValidatingWebhookServicePath is the path for validation webhook
 	ValidatingWebhookServicePath = "/validate"
 
+	//MutatePreviewServicePath is the path for mutation preview(used to return the fully mutated resource)
+	MutatePreviewServicePath = "/mutate/preview"
+
+	//ReportResultsServicePath is the path of the read-only API of the policy report results
+	ReportResultsServicePath = "/reports/results"
+
 	//PolicyValidatingWebhookServicePath is the path for policy validation webhook(used to validate policy resource)
 	PolicyValidatingWebhookServicePath = "/policyvalidate"
 
@@ -30,6 +30,9 @@ type ConfigData struct {
 	excludeGroupRole            []string
 	excludeUsername             []string
 	restrictDevelopmentUsername []string
+	immutableFields             []immutableField
+	allowedSecrets              []secretReference
+	protectedNamespaces         []string
 	cmSycned                    cache.InformerSynced
 	log                         logr.Logger
 }
@@ -74,6 +77,43 @@ func (cd *ConfigData) GetExcludeUsername() []string {
 	return cd.excludeUsername
 }
 
+// GetImmutableFields returns the paths of the fields of the kind that mutations must not modify
+func (cd *ConfigData) GetImmutableFields(kind string) []string {
+	cd.mux.RLock()
+	defer cd.mux.RUnlock()
+	var paths []string
+	for _, f := range cd.immutableFields {
+		if wildcard.Match(f.Kind, kind) {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// IsSecretAllowed checks if policies may read the Secret, no Secrets are allowed unless configured
+func (cd *ConfigData) IsSecretAllowed(namespace, name string) bool {
+	cd.mux.RLock()
+	defer cd.mux.RUnlock()
+	for _, s := range cd.allowedSecrets {
+		if wildcard.Match(s.Namespace, namespace) && wildcard.Match(s.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNamespaceProtected checks if generate rules must not create or update resources in the namespace
+func (cd *ConfigData) IsNamespaceProtected(namespace string) bool {
+	cd.mux.RLock()
+	defer cd.mux.RUnlock()
+	for _, ns := range cd.protectedNamespaces {
+		if wildcard.Match(ns, namespace) {
+			return true
+		}
+	}
+	return false
+}
+
 // FilterNamespaces filters exclude namespace
 func (cd *ConfigData) FilterNamespaces(namespaces []string) []string {
 	var results []string
@@ -92,6 +132,9 @@ type Interface interface {
 	GetExcludeGroupRole() []string
 	GetExcludeUsername() []string
 	RestrictDevelopmentUsername() []string
+	GetImmutableFields(kind string) []string
+	IsSecretAllowed(namespace, name string) bool
+	IsNamespaceProtected(namespace string) bool
 	FilterNamespaces(namespaces []string) []string
 }
 
@@ -243,6 +286,44 @@ func (cd *ConfigData) load(cm v1.ConfigMap) {
 		}
 	}
 
+	// get immutable fields
+	immutableFields, ok := cm.Data["immutableFields"]
+	if !ok {
+		logger.V(4).Info("configuration: No immutableFields defined in ConfigMap")
+	}
+	newImmutableFields := parseImmutableFields(immutableFields)
+	if reflect.DeepEqual(newImmutableFields, cd.immutableFields) {
+		logger.V(4).Info("immutableFields did not change")
+	} else {
+		logger.V(2).Info("Updated immutable fields", "oldImmutableFields", cd.immutableFields, "newImmutableFields", newImmutableFields)
+		cd.immutableFields = newImmutableFields
+	}
+
+	// get allowed secrets
+	allowedSecrets, ok := cm.Data["allowedSecrets"]
+	if !ok {
+		logger.V(4).Info("configuration: No allowedSecrets defined in ConfigMap")
+	}
+	newAllowedSecrets := parseSecretReferences(allowedSecrets)
+	if reflect.DeepEqual(newAllowedSecrets, cd.allowedSecrets) {
+		logger.V(4).Info("allowedSecrets did not change")
+	} else {
+		logger.V(2).Info("Updated allowed secrets", "oldAllowedSecrets", cd.allowedSecrets, "newAllowedSecrets", newAllowedSecrets)
+		cd.allowedSecrets = newAllowedSecrets
+	}
+
+	// get namespaces protected from generate rules
+	protectedNamespaces, ok := cm.Data["generateProtectedNamespaces"]
+	if !ok {
+		logger.V(4).Info("configuration: No generateProtectedNamespaces defined in ConfigMap")
+	}
+	newProtectedNamespaces := parseNamespaces(protectedNamespaces)
+	if reflect.DeepEqual(newProtectedNamespaces, cd.protectedNamespaces) {
+		logger.V(4).Info("generateProtectedNamespaces did not change")
+	} else {
+		logger.V(2).Info("Updated generate protected namespaces", "oldProtectedNamespaces", cd.protectedNamespaces, "newProtectedNamespaces", newProtectedNamespaces)
+		cd.protectedNamespaces = newProtectedNamespaces
+	}
 }
 
 //TODO: this has been added to backward support command line arguments
@@ -285,6 +366,9 @@ func (cd *ConfigData) unload(cm v1.ConfigMap) {
 	cd.excludeGroupRole = []string{}
 	cd.excludeGroupRole = append(cd.excludeGroupRole, defaultExcludeGroupRole...)
 	cd.excludeUsername = []string{}
+	cd.immutableFields = []immutableField{}
+	cd.allowedSecrets = []secretReference{}
+	cd.protectedNamespaces = []string{}
 }
 
 type k8Resource struct {
@@ -322,6 +406,68 @@ func parseKinds(list string) []k8Resource {
 	return resources
 }
 
+type immutableField struct {
+	Kind string
+	Path string
+}
+
+// parseImmutableFields parses the immutable fields declared as [Kind,path]
+// "[Deployment,spec.selector][*,metadata.name]" => {{"Deployment","spec.selector"},{"*","metadata.name"}}
+func parseImmutableFields(list string) []immutableField {
+	fields := []immutableField{}
+	re := regexp.MustCompile(`\[([^\[\]]*)\]`)
+	for _, element := range re.FindAllStringSubmatch(list, -1) {
+		elements := strings.Split(element[1], ",")
+		if len(elements) != 2 {
+			continue
+		}
+
+		kind, path := strings.TrimSpace(elements[0]), strings.TrimSpace(elements[1])
+		if kind == "" || path == "" {
+			continue
+		}
+		fields = append(fields, immutableField{Kind: kind, Path: path})
+	}
+	return fields
+}
+
+type secretReference struct {
+	Namespace string
+	Name      string
+}
+
+// parseSecretReferences parses the Secrets declared as [namespace,name]
+// "[kyverno,registry-*][team-?,signing-key]" => {{"kyverno","registry-*"},{"team-?","signing-key"}}
+func parseSecretReferences(list string) []secretReference {
+	secrets := []secretReference{}
+	re := regexp.MustCompile(`\[([^\[\]]*)\]`)
+	for _, element := range re.FindAllStringSubmatch(list, -1) {
+		elements := strings.Split(element[1], ",")
+		if len(elements) != 2 {
+			continue
+		}
+
+		namespace, name := strings.TrimSpace(elements[0]), strings.TrimSpace(elements[1])
+		if namespace == "" || name == "" {
+			continue
+		}
+		secrets = append(secrets, secretReference{Namespace: namespace, Name: name})
+	}
+	return secrets
+}
+
+// parseNamespaces parses the comma separated namespaces, empty entries are ignored
+// "kube-system, team-*" => {"kube-system","team-*"}
+func parseNamespaces(list string) []string {
+	namespaces := []string{}
+	for _, ns := range strings.Split(list, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
+
 func parseRbac(list string) []string {
 	return strings.Split(list, ",")
 }
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func Test_IsNamespaceProtected(t *testing.T) {
+	cd := &ConfigData{log: logr.Discard()}
+	assert.Assert(t, !cd.IsNamespaceProtected("kube-system"))
+
+	cd.load(v1.ConfigMap{Data: map[string]string{"generateProtectedNamespaces": " kube-system, kyverno ,tenant-*,"}})
+
+	testcases := []struct {
+		namespace string
+		protected bool
+	}{
+		{namespace: "kube-system", protected: true},
+		{namespace: "kyverno", protected: true},
+		{namespace: "tenant-a", protected: true},
+		{namespace: "tenant", protected: false},
+		{namespace: "default", protected: false},
+		{namespace: "", protected: false},
+	}
+
+	for _, testcase := range testcases {
+		assert.Equal(t, cd.IsNamespaceProtected(testcase.namespace), testcase.protected, testcase.namespace)
+	}
+
+	// the namespaces are no longer protected when they are removed from the ConfigMap
+	cd.load(v1.ConfigMap{Data: map[string]string{"resourceFilters": "[Event,*,*]"}})
+	assert.Assert(t, !cd.IsNamespaceProtected("kube-system"))
+}
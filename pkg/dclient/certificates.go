@@ -4,10 +4,12 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/kyverno/kyverno/pkg/config"
 	tls "github.com/kyverno/kyverno/pkg/tls"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/rest"
@@ -16,7 +18,7 @@ import (
 // InitTLSPemPair Loads or creates PEM private key and TLS certificate for webhook server.
 // Created pair is stored in cluster's secret.
 // Returns struct with key/certificate pair.
-func (c *Client) InitTLSPemPair(configuration *rest.Config, serverIP string) (*tls.PemPair, error) {
+func (c *Client) InitTLSPemPair(configuration *rest.Config, serverIP string, options tls.CertificateOptions) (*tls.PemPair, error) {
 	logger := c.log
 	certProps, err := c.GetTLSCertProps(configuration)
 	if err != nil {
@@ -24,7 +26,7 @@ func (c *Client) InitTLSPemPair(configuration *rest.Config, serverIP string) (*t
 	}
 
 	logger.Info("Building key/certificate pair for TLS")
-	tlsPair, err := c.buildTLSPemPair(certProps, serverIP)
+	tlsPair, err := c.buildTLSPemPair(certProps, serverIP, options)
 	if err != nil {
 		return nil, err
 	}
@@ -37,8 +39,8 @@ func (c *Client) InitTLSPemPair(configuration *rest.Config, serverIP string) (*t
 
 // buildTLSPemPair Issues TLS certificate for webhook server using self-signed CA cert
 // Returns signed and approved TLS certificate in PEM format
-func (c *Client) buildTLSPemPair(props tls.CertificateProps, serverIP string) (*tls.PemPair, error) {
-	caCert, caPEM, err := tls.GenerateCACert()
+func (c *Client) buildTLSPemPair(props tls.CertificateProps, serverIP string, options tls.CertificateOptions) (*tls.PemPair, error) {
+	caCert, caPEM, err := tls.GenerateCACert(options)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +49,7 @@ func (c *Client) buildTLSPemPair(props tls.CertificateProps, serverIP string) (*
 		return nil, fmt.Errorf("failed to write CA cert to secret: %v", err)
 	}
 
-	return tls.GenerateCertPem(caCert, props, serverIP)
+	return tls.GenerateCertPem(caCert, props, serverIP, options)
 }
 
 //ReadRootCASecret returns the RootCA from the pre-defined secret
@@ -220,6 +222,52 @@ func (c *Client) WriteTLSPairToSecret(props tls.CertificateProps, pemPair *tls.P
 	return nil
 }
 
+// The annotations of the root CA secret of the lock of the self-signed certificate
+const (
+	certLockHolderAnnotation string = "kyverno.io/cert-lock-holder"
+	certLockExpiryAnnotation string = "kyverno.io/cert-lock-expiry"
+)
+
+// AcquireRootCALock acquires or refreshes the lock of the replica which renews the self-signed certificate
+// and reconciles the CA bundle of the webhook configurations, so that the replicas do not overwrite the CA
+// of each other. The lock is stored in the annotations of the root CA secret, it is acquired by another
+// holder once it expires, and the concurrent updates of the secret conflict. The lock is written again
+// when less than half of its duration is left.
+func (c *Client) AcquireRootCALock(props tls.CertificateProps, holder string, duration time.Duration, now time.Time) (bool, error) {
+	name := generateRootCASecretName(props)
+	secret, err := c.GetResource("", Secrets, props.Namespace, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the root CA secret %s/%s: %v", props.Namespace, name, err)
+	}
+
+	annotations := secret.GetAnnotations()
+	expiry, err := time.Parse(time.RFC3339, annotations[certLockExpiryAnnotation])
+	if err == nil && now.Before(expiry) {
+		if annotations[certLockHolderAnnotation] != holder {
+			return false, nil
+		}
+		if expiry.Sub(now) > duration/2 {
+			return true, nil
+		}
+	}
+
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[certLockHolderAnnotation] = holder
+	annotations[certLockExpiryAnnotation] = now.Add(duration).UTC().Format(time.RFC3339)
+	secret.SetAnnotations(annotations)
+
+	if _, err := c.UpdateResource("", Secrets, props.Namespace, secret, false); err != nil {
+		if errors.IsConflict(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to update the lock of the root CA secret %s/%s: %v", props.Namespace, name, err)
+	}
+
+	return true, nil
+}
+
 func generateTLSPairSecretName(props tls.CertificateProps) string {
 	return tls.GenerateInClusterServiceName(props) + ".kyverno-tls-pair"
 }
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyverno/kyverno/pkg/config"
+	tls "github.com/kyverno/kyverno/pkg/tls"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func Test_AcquireRootCALock(t *testing.T) {
+	props := tls.CertificateProps{Service: config.KyvernoServiceName, Namespace: config.KyvernoNamespace}
+	secret := &unstructured.Unstructured{}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	secret.SetNamespace(props.Namespace)
+	secret.SetName(generateRootCASecretName(props))
+
+	client, err := NewMockClient(runtime.NewScheme(), nil, secret)
+	assert.NilError(t, err)
+	client.SetDiscovery(NewFakeDiscoveryClient(nil))
+
+	now := time.Now()
+	held, err := client.AcquireRootCALock(props, "kyverno-a", time.Minute, now)
+	assert.NilError(t, err)
+	assert.Assert(t, held)
+
+	// the lock is held by the holder until it expires
+	held, err = client.AcquireRootCALock(props, "kyverno-b", time.Minute, now.Add(30*time.Second))
+	assert.NilError(t, err)
+	assert.Assert(t, !held)
+
+	held, err = client.AcquireRootCALock(props, "kyverno-a", time.Minute, now.Add(10*time.Second))
+	assert.NilError(t, err)
+	assert.Assert(t, held)
+
+	// the expiry is refreshed once half of the duration is left
+	held, err = client.AcquireRootCALock(props, "kyverno-a", time.Minute, now.Add(40*time.Second))
+	assert.NilError(t, err)
+	assert.Assert(t, held)
+
+	held, err = client.AcquireRootCALock(props, "kyverno-b", time.Minute, now.Add(70*time.Second))
+	assert.NilError(t, err)
+	assert.Assert(t, !held)
+
+	// the expired lock is acquired by another holder
+	held, err = client.AcquireRootCALock(props, "kyverno-b", time.Minute, now.Add(2*time.Minute))
+	assert.NilError(t, err)
+	assert.Assert(t, held)
+
+	secret, err = client.GetResource("", Secrets, props.Namespace, generateRootCASecretName(props))
+	assert.NilError(t, err)
+	assert.Equal(t, secret.GetAnnotations()[certLockHolderAnnotation], "kyverno-b")
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/kyverno/kyverno/pkg/config"
+	tls "github.com/kyverno/kyverno/pkg/tls"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const certManagerAPIVersion string = "cert-manager.io/v1"
+
+// caCertKey is the key of the CA certificate in the secrets issued by cert-manager
+const caCertKey string = "ca.crt"
+
+// GetCertManagerSecretName returns the name of the secret of the cert-manager Certificate
+// in the Kyverno namespace
+func (c *Client) GetCertManagerSecretName(certificate string) (string, error) {
+	unstrCertificate, err := c.GetResource(certManagerAPIVersion, "Certificate", config.KyvernoNamespace, certificate)
+	if err != nil {
+		return "", fmt.Errorf("failed to get certificate %s/%s: %v", config.KyvernoNamespace, certificate, err)
+	}
+
+	secretName, _, err := unstructured.NestedString(unstrCertificate.Object, "spec", "secretName")
+	if err != nil || secretName == "" {
+		return "", fmt.Errorf("secret name of certificate %s/%s not found", config.KyvernoNamespace, certificate)
+	}
+
+	return secretName, nil
+}
+
+// ReadTLSSecret reads the TLS pair and the CA certificate from the secret in the Kyverno
+// namespace, e.g. issued by cert-manager, the CA certificate is empty when the issuer
+// does not provide it
+func (c *Client) ReadTLSSecret(name string) (*tls.PemPair, []byte, error) {
+	unstrSecret, err := c.GetResource("", Secrets, config.KyvernoNamespace, name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get secret %s/%s: %v", config.KyvernoNamespace, name, err)
+	}
+
+	secret, err := convertToSecret(unstrSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pemPairFromSecret(secret)
+}
+
+func pemPairFromSecret(secret v1.Secret) (*tls.PemPair, []byte, error) {
+	pemPair := &tls.PemPair{
+		Certificate: secret.Data[v1.TLSCertKey],
+		PrivateKey:  secret.Data[v1.TLSPrivateKeyKey],
+	}
+
+	if len(pemPair.Certificate) == 0 {
+		return nil, nil, fmt.Errorf("TLS certificate not found in secret %s/%s", secret.Namespace, secret.Name)
+	}
+
+	if len(pemPair.PrivateKey) == 0 {
+		return nil, nil, fmt.Errorf("TLS private key not found in secret %s/%s", secret.Namespace, secret.Name)
+	}
+
+	return pemPair, secret.Data[caCertKey], nil
+}
@@ -8,8 +8,11 @@ import (
 
 	"github.com/go-logr/logr"
 	openapiv2 "github.com/googleapis/gnostic/openapiv2"
+	"golang.org/x/time/rate"
 	certificates "k8s.io/api/certificates/v1beta1"
 	v1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	helperv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -24,6 +27,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	csrtype "k8s.io/client-go/kubernetes/typed/certificates/v1beta1"
 	event "k8s.io/client-go/kubernetes/typed/core/v1"
+	structuredevents "k8s.io/client-go/kubernetes/typed/events/v1"
 	"k8s.io/client-go/rest"
 )
 
@@ -83,6 +87,15 @@ func (c *Client) GetEventsInterface() (event.EventInterface, error) {
 	return c.kclient.CoreV1().Events(""), nil
 }
 
+//GetStructuredEventsInterface provides typed interface for the events.k8s.io/v1 events,
+// it returns an error when the API is not served by the cluster
+func (c *Client) GetStructuredEventsInterface() (structuredevents.EventsV1Interface, error) {
+	if _, err := c.kclient.Discovery().ServerResourcesForGroupVersion(eventsv1.SchemeGroupVersion.String()); err != nil {
+		return nil, err
+	}
+	return c.kclient.EventsV1(), nil
+}
+
 //GetCSRInterface provides type interface for CSR
 func (c *Client) GetCSRInterface() (csrtype.CertificateSigningRequestInterface, error) {
 	return c.kclient.CertificatesV1beta1().CertificateSigningRequests(), nil
@@ -117,7 +130,12 @@ func (c *Client) getGroupVersionMapper(apiVersion string, kind string) schema.Gr
 
 // GetResource returns the resource in unstructured/json format
 func (c *Client) GetResource(apiVersion string, kind string, namespace string, name string, subresources ...string) (*unstructured.Unstructured, error) {
-	return c.getResourceInterface(apiVersion, kind, namespace).Get(context.TODO(), name, meta.GetOptions{}, subresources...)
+	return c.GetResourceWithContext(context.TODO(), apiVersion, kind, namespace, name, subresources...)
+}
+
+// GetResourceWithContext returns the resource, the request is cancelled with the context
+func (c *Client) GetResourceWithContext(ctx context.Context, apiVersion string, kind string, namespace string, name string, subresources ...string) (*unstructured.Unstructured, error) {
+	return c.getResourceInterface(apiVersion, kind, namespace).Get(ctx, name, meta.GetOptions{}, subresources...)
 }
 
 //PatchResource patches the resource
@@ -133,12 +151,61 @@ func (c *Client) GetDynamicInterface() dynamic.Interface {
 // ListResource returns the list of resources in unstructured/json format
 // Access items using []Items
 func (c *Client) ListResource(apiVersion string, kind string, namespace string, lselector *meta.LabelSelector) (*unstructured.UnstructuredList, error) {
+	return c.ListResourceWithContext(context.TODO(), apiVersion, kind, namespace, lselector)
+}
+
+// ListResourceWithContext returns the list of resources, the request is cancelled with the context
+func (c *Client) ListResourceWithContext(ctx context.Context, apiVersion string, kind string, namespace string, lselector *meta.LabelSelector) (*unstructured.UnstructuredList, error) {
 	options := meta.ListOptions{}
 	if lselector != nil {
 		options = meta.ListOptions{LabelSelector: helperv1.FormatLabelSelector(lselector)}
 	}
 
-	return c.getResourceInterface(apiVersion, kind, namespace).List(context.TODO(), options)
+	return c.getResourceInterface(apiVersion, kind, namespace).List(ctx, options)
+}
+
+// ListResourceInPages returns the list of resources, listed in pages of the page size with the
+// limit and continue options so that the API server does not load all the resources at once.
+// The limiter is waited for before each page when it is not nil. The listing restarts from the
+// first page once when the continue token expires.
+func (c *Client) ListResourceInPages(ctx context.Context, apiVersion string, kind string, namespace string, lselector *meta.LabelSelector, pageSize int64, limiter *rate.Limiter) (*unstructured.UnstructuredList, error) {
+	options := meta.ListOptions{Limit: pageSize}
+	if lselector != nil {
+		options.LabelSelector = helperv1.FormatLabelSelector(lselector)
+	}
+
+	resourceInterface := c.getResourceInterface(apiVersion, kind, namespace)
+	list := &unstructured.UnstructuredList{}
+	restarted := false
+	for {
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		page, err := resourceInterface.List(ctx, options)
+		if err != nil {
+			if errors.IsResourceExpired(err) && options.Continue != "" && !restarted {
+				restarted = true
+				options.Continue = ""
+				list = &unstructured.UnstructuredList{}
+				continue
+			}
+			return nil, err
+		}
+
+		if options.Continue == "" {
+			list.Object = page.Object
+		}
+		list.Items = append(list.Items, page.Items...)
+
+		if page.GetContinue() == "" {
+			list.SetContinue("")
+			return list, nil
+		}
+		options.Continue = page.GetContinue()
+	}
 }
 
 // DeleteResource deletes the specified resource
@@ -2,12 +2,21 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/kyverno/kyverno/pkg/config"
+	"golang.org/x/time/rate"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 )
 
 // GetResource
@@ -126,3 +135,96 @@ func TestCSRInterface(t *testing.T) {
 		t.Errorf("Testing CSR interface not working: %s", err)
 	}
 }
+
+func TestPemPairFromSecret(t *testing.T) {
+	secret := v1.Secret{
+		ObjectMeta: meta.ObjectMeta{Name: "kyverno-svc-tls", Namespace: config.KyvernoNamespace},
+		Data: map[string][]byte{
+			v1.TLSCertKey:       []byte("cert"),
+			v1.TLSPrivateKeyKey: []byte("key"),
+			caCertKey:           []byte("ca"),
+		},
+	}
+
+	pemPair, caData, err := pemPairFromSecret(secret)
+	if err != nil {
+		t.Fatalf("pemPairFromSecret not working: %s", err)
+	}
+	if string(pemPair.Certificate) != "cert" || string(pemPair.PrivateKey) != "key" || string(caData) != "ca" {
+		t.Errorf("unexpected TLS pair %s %s %s", pemPair.Certificate, pemPair.PrivateKey, caData)
+	}
+
+	delete(secret.Data, v1.TLSPrivateKeyKey)
+	if _, _, err := pemPairFromSecret(secret); err == nil {
+		t.Errorf("expected an error for the secret without private key")
+	}
+}
+
+func TestListResourceInPages(t *testing.T) {
+	// the API server returns a resource per page, the continue token is the name of the next resource
+	names := []string{"name-foo", "name-bar", "name-baz"}
+	var requests []string
+	expire := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		if r.URL.Query().Get("limit") != "1" {
+			t.Errorf("unexpected limit %s", r.URL.Query().Get("limit"))
+		}
+
+		index := 0
+		if token := r.URL.Query().Get("continue"); token != "" {
+			if expire {
+				expire = false
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusGone)
+				status := errors.NewResourceExpired("continue token expired").Status()
+				status.TypeMeta = meta.TypeMeta{APIVersion: "v1", Kind: "Status"}
+				_ = json.NewEncoder(w).Encode(status)
+				return
+			}
+			for i, name := range names {
+				if name == token {
+					index = i
+				}
+			}
+		}
+
+		list := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "group/version", "kind": "TheKindList"}}
+		list.Items = []unstructured.Unstructured{*newUnstructured("group/version", "TheKind", "ns-foo", names[index])}
+		if index+1 < len(names) {
+			list.SetContinue(names[index+1])
+		}
+
+		data, _ := list.MarshalJSON()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	dynamicClient, err := dynamic.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &Client{client: dynamicClient}
+	client.SetDiscovery(NewFakeDiscoveryClient([]schema.GroupVersionResource{{Group: "group", Version: "version", Resource: "thekinds"}}))
+
+	limiter := rate.NewLimiter(rate.Limit(100), 1)
+	list, err := client.ListResourceInPages(context.TODO(), "", "thekind", "ns-foo", nil, 1, limiter)
+	if err != nil {
+		t.Fatalf("ListResourceInPages not working: %s", err)
+	}
+	if len(requests) != 3 || len(list.Items) != 3 || list.GetContinue() != "" {
+		t.Errorf("expected 3 resources listed in 3 pages, got %d resources in %d pages", len(list.Items), len(requests))
+	}
+
+	// the listing restarts from the first page when the continue token expires
+	requests = nil
+	expire = true
+	list, err = client.ListResourceInPages(context.TODO(), "", "thekind", "ns-foo", nil, 1, nil)
+	if err != nil {
+		t.Fatalf("ListResourceInPages not working after the continue token expired: %s", err)
+	}
+	if len(requests) != 5 || len(list.Items) != 3 {
+		t.Errorf("expected 3 resources listed in 5 requests, got %d resources in %d requests", len(list.Items), len(requests))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/go-logr/logr"
 	openapiv2 "github.com/googleapis/gnostic/openapiv2"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -34,6 +35,7 @@ func NewMockClient(scheme *runtime.Scheme, gvrToListKind map[schema.GroupVersion
 	return &Client{
 		client:  client,
 		kclient: kclient,
+		log:     logr.Discard(),
 	}, nil
 
 }
@@ -0,0 +1,37 @@
+package common
+
+import (
+	"regexp"
+
+	"github.com/Masterminds/semver"
+)
+
+// semverRegex matches versions of the form [v]MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD]
+var semverRegex = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// IsSemver checks if the value is a string holding a full semantic version.
+// Partial versions like "1.21" are not matched so that they keep their numeric meaning.
+func IsSemver(value interface{}) bool {
+	str, ok := value.(string)
+	if !ok {
+		return false
+	}
+
+	return semverRegex.MatchString(str)
+}
+
+// CompareSemver compares two semantic versions and returns -1, 0 or 1
+// if the value is less than, equal to or greater than the other version
+func CompareSemver(value, other string) (int, error) {
+	valueVersion, err := semver.NewVersion(value)
+	if err != nil {
+		return 0, err
+	}
+
+	otherVersion, err := semver.NewVersion(other)
+	if err != nil {
+		return 0, err
+	}
+
+	return valueVersion.Compare(otherVersion), nil
+}
@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/engine/variables"
+)
+
+// evaluateConditions evaluates the conditions, with strict variables the variables of the conditions are
+// substituted first and a variable which cannot be resolved is an error instead of a false condition
+func evaluateConditions(log logr.Logger, ctx context.EvalInterface, conditions interface{}, strict bool) (bool, error) {
+	if strict {
+		var err error
+		if conditions, err = variables.SubstituteAllInConditions(log, ctx, conditions); err != nil {
+			return false, err
+		}
+	}
+
+	return variables.EvaluateConditions(log, ctx, conditions), nil
+}
+
+// variableErrorResponse is the response of a rule failed by a variable which cannot be resolved
+func variableErrorResponse(rule kyverno.Rule, ruleType utils.RuleType, err error) response.RuleResponse {
+	return response.RuleResponse{
+		Name:    rule.Name,
+		Type:    ruleType.String(),
+		Message: fmt.Sprintf("variable substitution failed for rule %s: %v", rule.Name, err),
+		Success: false,
+	}
+}
@@ -3,6 +3,7 @@ package context
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"sync"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -31,6 +33,12 @@ type Interface interface {
 	// AddNamespace merges resource json under request.namespace
 	AddNamespace(namespace string) error
 
+	// AddOperation merges the admission request operation under request.operation
+	AddOperation(operation string) error
+
+	// AddNamespaceMetadata merges the labels and annotations of the resource namespace under namespaceLabels and namespaceAnnotations
+	AddNamespaceMetadata(labels, annotations map[string]string) error
+
 	EvalInterface
 }
 
@@ -41,11 +49,22 @@ type EvalInterface interface {
 
 //Context stores the data resources as JSON
 type Context struct {
-	mutex             sync.RWMutex
-	jsonRaw           []byte
-	jsonRawCheckpoint []byte
-	builtInVars       []string
-	log               logr.Logger
+	mutex              sync.RWMutex
+	jsonRaw            []byte
+	jsonRawCheckpoint  []byte
+	deferred           []deferredLoader
+	deferredCheckpoint []deferredLoader
+	builtInVars        []string
+	log                logr.Logger
+}
+
+// deferredLoader adds the data of a context entry when a query first references the entry
+type deferredLoader struct {
+	name  string
+	load  func() error
+	regex *regexp.Regexp
+	// err is the error of a failed load, it is returned by the later queries referencing the entry
+	err error
 }
 
 //NewContext returns a new context
@@ -128,6 +147,22 @@ func (ctx *Context) AddResource(dataRaw []byte) error {
 	return ctx.AddJSON(objRaw)
 }
 
+// ReplaceResource replaces the data at path: request.object by the resource,
+// the fields removed from the resource are removed from the context
+func (ctx *Context) ReplaceResource(resource unstructured.Unstructured) error {
+	resourceRaw, err := resource.MarshalJSON()
+	if err != nil {
+		ctx.log.Error(err, "failed to marshal the resource")
+		return err
+	}
+
+	if err := ctx.AddJSON([]byte(`{"request":{"object":null}}`)); err != nil {
+		return err
+	}
+
+	return ctx.AddResource(resourceRaw)
+}
+
 //AddUserInfo adds userInfo at path request.userInfo
 func (ctx *Context) AddUserInfo(userRequestInfo kyverno.RequestInfo) error {
 	modifiedResource := struct {
@@ -214,6 +249,106 @@ func (ctx *Context) AddNamespace(namespace string) error {
 	return ctx.AddJSON(objRaw)
 }
 
+// AddOperation merges the admission request operation under request.operation
+func (ctx *Context) AddOperation(operation string) error {
+	modifiedResource := struct {
+		Request interface{} `json:"request"`
+	}{
+		Request: struct {
+			Operation string `json:"operation"`
+		}{
+			Operation: operation,
+		},
+	}
+
+	objRaw, err := json.Marshal(modifiedResource)
+	if err != nil {
+		ctx.log.Error(err, "failed to marshal the operation")
+		return err
+	}
+
+	return ctx.AddJSON(objRaw)
+}
+
+// AddNamespaceMetadata merges the labels and annotations of the resource namespace
+// at paths: namespaceLabels and namespaceAnnotations. Previously added metadata is replaced.
+func (ctx *Context) AddNamespaceMetadata(labels, annotations map[string]string) error {
+	if err := ctx.AddJSON([]byte(`{"namespaceLabels":null,"namespaceAnnotations":null}`)); err != nil {
+		return err
+	}
+
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	metadata := struct {
+		NamespaceLabels      map[string]string `json:"namespaceLabels"`
+		NamespaceAnnotations map[string]string `json:"namespaceAnnotations"`
+	}{
+		NamespaceLabels:      labels,
+		NamespaceAnnotations: annotations,
+	}
+
+	objRaw, err := json.Marshal(metadata)
+	if err != nil {
+		ctx.log.Error(err, "failed to marshal the namespace metadata")
+		return err
+	}
+
+	return ctx.AddJSON(objRaw)
+}
+
+// AddElement adds the current element of a foreach loop at path: element
+// and its index at path: elementIndex. A previously added element is replaced.
+func (ctx *Context) AddElement(data interface{}, index int) error {
+	// a merge patch would merge a map element with the previous element, remove it first
+	if err := ctx.AddJSON([]byte(`{"element":null}`)); err != nil {
+		return err
+	}
+
+	element := struct {
+		Element      interface{} `json:"element"`
+		ElementIndex int         `json:"elementIndex"`
+	}{
+		Element:      data,
+		ElementIndex: index,
+	}
+
+	objRaw, err := json.Marshal(element)
+	if err != nil {
+		ctx.log.Error(err, "failed to marshal the element")
+		return err
+	}
+
+	return ctx.AddJSON(objRaw)
+}
+
+// AddTarget adds the target resource of a mutate existing rule at path: target.
+// A previously added target is replaced.
+func (ctx *Context) AddTarget(data map[string]interface{}) error {
+	if err := ctx.AddJSON([]byte(`{"target":null}`)); err != nil {
+		return err
+	}
+
+	target := struct {
+		Target interface{} `json:"target"`
+	}{
+		Target: data,
+	}
+
+	objRaw, err := json.Marshal(target)
+	if err != nil {
+		ctx.log.Error(err, "failed to marshal the target")
+		return err
+	}
+
+	return ctx.AddJSON(objRaw)
+}
+
 // Checkpoint creates a copy of the internal state.
 // Prior checkpoints will be overridden.
 func (ctx *Context) Checkpoint() {
@@ -222,6 +357,7 @@ func (ctx *Context) Checkpoint() {
 
 	ctx.jsonRawCheckpoint = make([]byte, len(ctx.jsonRaw))
 	copy(ctx.jsonRawCheckpoint, ctx.jsonRaw)
+	ctx.deferredCheckpoint = append([]deferredLoader{}, ctx.deferred...)
 }
 
 // Restore restores internal state from a prior checkpoint, if one exists.
@@ -236,6 +372,61 @@ func (ctx *Context) Restore() {
 
 	ctx.jsonRaw = make([]byte, len(ctx.jsonRawCheckpoint))
 	copy(ctx.jsonRaw, ctx.jsonRawCheckpoint)
+	ctx.deferred = append([]deferredLoader{}, ctx.deferredCheckpoint...)
+}
+
+// AddDeferredLoader adds a loader of the data of the named context entry. The loader runs once,
+// before the first query which references the name, and it is discarded by Restore when it was
+// added after the checkpoint. The error of a failed load is returned by the later queries.
+func (ctx *Context) AddDeferredLoader(name string, load func() error) {
+	ctx.mutex.Lock()
+	defer ctx.mutex.Unlock()
+
+	// the name is referenced unless it is a field of another value, e.g. request.name
+	regex := regexp.MustCompile(`(^|[^\w.])` + regexp.QuoteMeta(name) + `(\W|$)`)
+	ctx.deferred = append(ctx.deferred, deferredLoader{name: name, load: load, regex: regex})
+}
+
+// loadDeferred runs the deferred loaders of the context entries referenced by the query.
+// The loaders may query the context themselves, e.g. to resolve the variables of an API call.
+func (ctx *Context) loadDeferred(query string) error {
+	for {
+		loader, ok := ctx.nextDeferredLoader(query)
+		if !ok {
+			return nil
+		}
+
+		if loader.err != nil {
+			return loader.err
+		}
+
+		if err := loader.load(); err != nil {
+			ctx.log.Error(err, "failed to load context entry", "name", loader.name)
+			loader.err = err
+			ctx.mutex.Lock()
+			ctx.deferred = append(ctx.deferred, loader)
+			ctx.mutex.Unlock()
+			return err
+		}
+	}
+}
+
+// nextDeferredLoader returns the first deferred loader referenced by the query, the loader
+// is removed unless it failed
+func (ctx *Context) nextDeferredLoader(query string) (deferredLoader, bool) {
+	ctx.mutex.Lock()
+	defer ctx.mutex.Unlock()
+
+	for i, loader := range ctx.deferred {
+		if loader.regex.MatchString(query) {
+			if loader.err == nil {
+				ctx.deferred = append(append([]deferredLoader{}, ctx.deferred[:i]...), ctx.deferred[i+1:]...)
+			}
+			return loader, true
+		}
+	}
+
+	return deferredLoader{}, false
 }
 
 // AddBuiltInVars adds given pattern to the builtInVars
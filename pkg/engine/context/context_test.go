@@ -1,11 +1,23 @@
 package context
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
 	"reflect"
 	"testing"
+	"time"
 
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func Test_addResourceAndUserContext(t *testing.T) {
@@ -118,3 +130,521 @@ func Test_addResourceAndUserContext(t *testing.T) {
 		t.Error("exected result does not match")
 	}
 }
+
+func Test_ReplaceResource(t *testing.T) {
+	ctx := NewContext()
+	if err := ctx.AddResource([]byte(`{"kind": "Pod", "metadata": {"name": "nginx", "labels": {"app": "nginx"}}}`)); err != nil {
+		t.Error(err)
+	}
+
+	resource := unstructured.Unstructured{}
+	resource.SetKind("Pod")
+	resource.SetName("nginx")
+	resource.SetAnnotations(map[string]string{"team": "dev"})
+	if err := ctx.ReplaceResource(resource); err != nil {
+		t.Error(err)
+	}
+
+	result, err := ctx.Query("request.object.metadata.labels")
+	if err != nil {
+		t.Error(err)
+	}
+	if result != nil {
+		t.Errorf("expected removed labels, found %v", result)
+	}
+
+	result, err = ctx.Query("request.object.metadata.annotations.team")
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual("dev", result) {
+		t.Error("exected result does not match")
+	}
+}
+
+func Test_AddDeferredLoader(t *testing.T) {
+	ctx := NewContext()
+	if err := ctx.AddResource([]byte(`{"kind": "Pod", "metadata": {"name": "nginx"}}`)); err != nil {
+		t.Error(err)
+	}
+
+	ctx.Checkpoint()
+	loads := 0
+	ctx.AddDeferredLoader("name", func() error {
+		loads++
+		return ctx.AddJSON([]byte(`{"name": "team"}`))
+	})
+
+	// a field with the name of the entry does not reference it
+	if _, err := ctx.Query("request.object.metadata.name"); err != nil {
+		t.Error(err)
+	}
+	if loads != 0 {
+		t.Errorf("expected no loads for a field, found %d", loads)
+	}
+
+	for i := 0; i < 2; i++ {
+		result, err := ctx.Query("to_upper(name)")
+		if err != nil {
+			t.Error(err)
+		}
+		if !reflect.DeepEqual("TEAM", result) {
+			t.Errorf("expected TEAM, found %v", result)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expected one load, found %d", loads)
+	}
+
+	// the loader added after the checkpoint is discarded
+	ctx.Restore()
+	result, err := ctx.Query("name")
+	if err != nil {
+		t.Error(err)
+	}
+	if result != nil || loads != 1 {
+		t.Errorf("expected the loader to be discarded, found %v after %d loads", result, loads)
+	}
+}
+
+func Test_AddDeferredLoader_Failure(t *testing.T) {
+	ctx := NewContext()
+	loads := 0
+	ctx.AddDeferredLoader("team", func() error {
+		loads++
+		return errors.New("failed to fetch the team")
+	})
+
+	// the failure is reported by every query referencing the entry
+	for i := 0; i < 2; i++ {
+		if _, err := ctx.Query("team"); err == nil || err.Error() != "failed to fetch the team" {
+			t.Errorf("expected the load failure, found %v", err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expected one load, found %d", loads)
+	}
+}
+
+func Test_AddDeferredLoader_InvalidQuery(t *testing.T) {
+	ctx := NewContext("request.object")
+	loads := 0
+	ctx.AddDeferredLoader("team", func() error {
+		loads++
+		return ctx.AddJSON([]byte(`{"team": "dev"}`))
+	})
+
+	// the entry is not loaded for the queries which are rejected
+	if _, err := ctx.Query("team"); err == nil {
+		t.Error("expected the query to be rejected")
+	}
+	if _, err := ctx.Query("team["); err == nil {
+		t.Error("expected the query to fail to compile")
+	}
+	if loads != 0 {
+		t.Errorf("expected no loads, found %d", loads)
+	}
+}
+
+func Test_AddNamespaceAndOperation(t *testing.T) {
+	ctx := NewContext()
+	if err := ctx.AddResource([]byte(`{"kind": "ConfigMap", "metadata": {"name": "app", "namespace": "dev"}}`)); err != nil {
+		t.Error(err)
+	}
+
+	if err := ctx.AddNamespace("dev"); err != nil {
+		t.Error(err)
+	}
+
+	if err := ctx.AddOperation("CREATE"); err != nil {
+		t.Error(err)
+	}
+
+	expected := map[string]interface{}{
+		"request.namespace":            "dev",
+		"request.operation":            "CREATE",
+		"request.object.metadata.name": "app",
+	}
+
+	for query, value := range expected {
+		result, err := ctx.Query(query)
+		if err != nil {
+			t.Error(err)
+		}
+		if !reflect.DeepEqual(value, result) {
+			t.Errorf("expected %v for %s, found %v", value, query, result)
+		}
+	}
+}
+
+func Test_AddNamespaceMetadata(t *testing.T) {
+	ctx := NewContext()
+	if err := ctx.AddNamespaceMetadata(map[string]string{"environment": "prod", "team": "payments"}, map[string]string{"owner": "alice"}); err != nil {
+		t.Error(err)
+	}
+
+	// metadata of another namespace replaces the previous one
+	if err := ctx.AddNamespaceMetadata(map[string]string{"environment": "dev"}, nil); err != nil {
+		t.Error(err)
+	}
+
+	expected := map[string]interface{}{
+		"namespaceLabels.environment": "dev",
+		"namespaceLabels.team":        nil,
+		"namespaceAnnotations.owner":  nil,
+		"namespaceAnnotations":        map[string]interface{}{},
+	}
+
+	for query, value := range expected {
+		result, err := ctx.Query(query)
+		if err != nil {
+			t.Error(err)
+		}
+		if !reflect.DeepEqual(value, result) {
+			t.Errorf("expected %v for %s, found %v", value, query, result)
+		}
+	}
+}
+
+func Test_QueryFunctions(t *testing.T) {
+	now = func() time.Time {
+		return time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	}
+	local := time.Local
+	time.Local = time.UTC
+	defer func() { now, time.Local = time.Now, local }()
+
+	ctx := NewContext("request.object")
+	err := ctx.AddResource([]byte(`{"metadata": {"creationTimestamp": "2021-01-01T10:30:00+01:00"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		query    string
+		expected interface{}
+	}{
+		{query: "time_now()", expected: "2021-01-01T12:00:00Z"},
+		{query: "time_parse(request.object.metadata.creationTimestamp)", expected: "2021-01-01T09:30:00Z"},
+		{query: "time_add(time_now(), '-1h30m')", expected: "2021-01-01T10:30:00Z"},
+		{query: "time_diff(request.object.metadata.creationTimestamp, time_now())", expected: "2h30m0s"},
+		{query: "time_before(request.object.metadata.creationTimestamp, time_now())", expected: true},
+		{query: "time_after(request.object.metadata.creationTimestamp, `\"2021-01-01T10:00:00Z\"`)", expected: false},
+	}
+
+	for _, tc := range testcases {
+		result, err := ctx.Query(tc.query)
+		if err != nil {
+			t.Errorf("query %s: unexpected error %v", tc.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("query %s: expected %v, found %v", tc.query, tc.expected, result)
+		}
+	}
+
+	invalid := []string{
+		"time_now(request.object)",
+		"time_add(time_now(), 'month')",
+		"time_parse(request.object.metadata.name)",
+		"time_parse(serviceAccountName)",
+	}
+
+	for _, query := range invalid {
+		if _, err := ctx.Query(query); err == nil {
+			t.Errorf("query %s: expected an error", query)
+		}
+	}
+}
+
+func Test_QueryNetworkFunctions(t *testing.T) {
+	ctx := NewContext()
+	err := ctx.AddResource([]byte(`{"spec": {"clusterIP": "10.96.0.10", "loadBalancerSourceRanges": ["192.168.1.0/24"]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		query    string
+		expected interface{}
+	}{
+		{query: "parse_ip('2001:0db8:0000:0000:0000:0000:0000:0001')", expected: "2001:db8::1"},
+		{query: "cidr_contains('10.96.0.0/12', request.object.spec.clusterIP)", expected: true},
+		{query: "cidr_contains('10.0.0.0/16', request.object.spec.clusterIP)", expected: false},
+		{query: "cidr_contains('192.168.0.0/16', request.object.spec.loadBalancerSourceRanges[0])", expected: true},
+		{query: "cidr_contains('192.168.1.0/25', request.object.spec.loadBalancerSourceRanges[0])", expected: false},
+		{query: "cidr_contains('::/0', request.object.spec.loadBalancerSourceRanges[0])", expected: false},
+		{query: "ip_in_range(request.object.spec.clusterIP, '10.96.0.1', '10.96.0.10')", expected: true},
+		{query: "ip_in_range(request.object.spec.clusterIP, '10.96.0.11', '10.96.0.20')", expected: false},
+		{query: "ip_in_range('::1', '10.0.0.0', '10.255.255.255')", expected: false},
+	}
+
+	for _, tc := range testcases {
+		result, err := ctx.Query(tc.query)
+		if err != nil {
+			t.Errorf("query %s: unexpected error %v", tc.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("query %s: expected %v, found %v", tc.query, tc.expected, result)
+		}
+	}
+
+	invalid := []string{
+		"parse_ip('10.0.0.256')",
+		"cidr_contains('10.0.0.0', request.object.spec.clusterIP)",
+		"cidr_contains('10.0.0.0/8', request.object.spec.loadBalancerSourceRanges)",
+	}
+
+	for _, query := range invalid {
+		if _, err := ctx.Query(query); err == nil {
+			t.Errorf("query %s: expected an error", query)
+		}
+	}
+}
+
+func Test_QueryStringFunctions(t *testing.T) {
+	ctx := NewContext()
+	err := ctx.AddResource([]byte(`{"metadata": {"name": "nginx-prod", "labels": {"team": "Payments"}}, "data": {"password": "c2VjcmV0"}, "spec": {"replicas": 3, "image": "ghcr.io/kyverno/nginx:1.21"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		query    string
+		expected interface{}
+	}{
+		{query: "regex_match('^nginx-(dev|prod)$', request.object.metadata.name)", expected: true},
+		{query: "regex_match('^nginx-dev$', request.object.metadata.name)", expected: false},
+		{query: "regex_match('^[0-9]+$', request.object.spec.replicas)", expected: true},
+		{query: "pattern_match('ghcr.io/*', request.object.spec.image)", expected: true},
+		{query: "pattern_match('docker.io/*', request.object.spec.image)", expected: false},
+		{query: "split(request.object.spec.image, ':')", expected: []interface{}{"ghcr.io/kyverno/nginx", "1.21"}},
+		{query: "replace_all(request.object.metadata.name, '-', '_')", expected: "nginx_prod"},
+		{query: "to_upper(request.object.metadata.labels.team)", expected: "PAYMENTS"},
+		{query: "to_lower(request.object.metadata.labels.team)", expected: "payments"},
+		{query: "base64_decode(request.object.data.password)", expected: "secret"},
+		{query: "base64_encode('secret')", expected: "c2VjcmV0"},
+		{query: "add(request.object.spec.replicas, `2`)", expected: 5.0},
+		{query: "subtract(request.object.spec.replicas, `1.5`)", expected: 1.5},
+		{query: "to_upper(replace_all(request.object.metadata.name, 'nginx-', ''))", expected: "PROD"},
+		// the custom functions are JMESPath functions, they can be nested and combined with expressions
+		{query: "length(split(request.object.spec.image, '/'))", expected: 3.0},
+		{query: "to_lower(request.object.metadata.labels.team) == 'payments'", expected: true},
+		{query: "split(request.object.spec.image, ':')[1]", expected: "1.21"},
+		{query: "[request.object.metadata.name, request.object.metadata.labels.team] | map(&to_upper(@), @)", expected: []interface{}{"NGINX-PROD", "PAYMENTS"}},
+	}
+
+	for _, tc := range testcases {
+		result, err := ctx.Query(tc.query)
+		if err != nil {
+			t.Errorf("query %s: unexpected error %v", tc.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("query %s: expected %v, found %v", tc.query, tc.expected, result)
+		}
+	}
+
+	invalid := []string{
+		"regex_match('[', request.object.metadata.name)",
+		"split(request.object.spec.replicas, ':')",
+		"base64_decode(request.object.metadata.name)",
+		"add(request.object.metadata.name, `1`)",
+		"to_upper('a', 'b')",
+	}
+
+	for _, query := range invalid {
+		if _, err := ctx.Query(query); err == nil {
+			t.Errorf("query %s: expected an error", query)
+		}
+	}
+}
+
+func Test_QueryX509Functions(t *testing.T) {
+	now = func() time.Time {
+		return time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	}
+	defer func() { now = time.Now }()
+
+	certificate := generateCertificate(t, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC))
+	ctx := NewContext()
+	err := ctx.AddResource([]byte(`{"kind": "Secret", "type": "kubernetes.io/tls", "data": {"tls.crt": "` + base64.StdEncoding.EncodeToString(certificate) + `"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []struct {
+		query    string
+		expected interface{}
+	}{
+		{query: "x509_decode(base64_decode(request.object.data.\"tls.crt\")).issuer", expected: "CN=example.com,O=Example"},
+		{query: "x509_decode(base64_decode(request.object.data.\"tls.crt\")).commonName", expected: "example.com"},
+		{query: "x509_decode(base64_decode(request.object.data.\"tls.crt\")).dnsNames", expected: []interface{}{"example.com", "www.example.com"}},
+		{query: "x509_decode(base64_decode(request.object.data.\"tls.crt\")).ipAddresses[0]", expected: "10.0.0.1"},
+		{query: "x509_decode(base64_decode(request.object.data.\"tls.crt\")).notAfter", expected: "2021-04-01T00:00:00Z"},
+		{query: "x509_decode(base64_decode(request.object.data.\"tls.crt\")).isCA", expected: false},
+		{query: "time_before(x509_decode(base64_decode(request.object.data.\"tls.crt\")).notAfter, time_now())", expected: true},
+	}
+
+	for _, tc := range testcases {
+		result, err := ctx.Query(tc.query)
+		if err != nil {
+			t.Errorf("query %s: unexpected error %v", tc.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("query %s: expected %v, found %v", tc.query, tc.expected, result)
+		}
+	}
+
+	invalid := []string{
+		"x509_decode(request.object.data.\"tls.crt\")",
+		"x509_decode('-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----')",
+		"x509_decode(request.object.kind) | issuer",
+	}
+
+	for _, query := range invalid {
+		if _, err := ctx.Query(query); err == nil {
+			t.Errorf("query %s: expected an error", query)
+		}
+	}
+}
+
+// generateCertificate returns a PEM encoded self-signed certificate
+func generateCertificate(t *testing.T, notBefore, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com", Organization: []string{"Example"}},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		DNSNames:     []string{"example.com", "www.example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("10.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func Test_QueryTimeNow_LocalZone(t *testing.T) {
+	now = func() time.Time {
+		return time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	}
+	local := time.Local
+	time.Local = time.FixedZone("UTC+2", 2*60*60)
+	defer func() { now, time.Local = time.Now, local }()
+
+	ctx := NewContext()
+	expected := map[string]interface{}{
+		"time_now()":                            "2021-01-01T14:00:00+02:00",
+		"time_now_utc()":                        "2021-01-01T12:00:00Z",
+		"time_diff(time_now(), time_now_utc())": "0s",
+	}
+
+	for query, value := range expected {
+		result, err := ctx.Query(query)
+		if err != nil {
+			t.Errorf("query %s: unexpected error %v", query, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, value) {
+			t.Errorf("query %s: expected %v, found %v", query, value, result)
+		}
+	}
+}
+
+func Test_QueryScheduleFunctions(t *testing.T) {
+	// Friday 1 January 2021
+	now = func() time.Time {
+		return time.Date(2021, 1, 1, 16, 30, 0, 0, time.UTC)
+	}
+	defer func() { now = time.Now }()
+
+	ctx := NewContext()
+	testcases := []struct {
+		query    string
+		expected interface{}
+	}{
+		{query: "time_now_utc()", expected: "2021-01-01T16:30:00Z"},
+		{query: "time_in_zone(time_now_utc(), 'Europe/Paris')", expected: "2021-01-01T17:30:00+01:00"},
+		{query: "time_in_window(time_now_utc(), '0 9 * * 1-5', '8h')", expected: true},
+		{query: "time_in_window(time_now_utc(), '0 9 * * 1-5', '7h30m')", expected: false},
+		{query: "time_in_window(time_now_utc(), '30 16 * * 5', '1m')", expected: true},
+		{query: "time_in_window(time_in_zone(time_now_utc(), 'Europe/Paris'), '0 9 * * 1-5', '8h')", expected: false},
+		{query: "time_in_window(time_now_utc(), '0 9 * * 6,0', '8h')", expected: false},
+		{query: "time_in_window(time_now_utc(), '0 22 * * 7', '48h')", expected: false},
+		{query: "time_in_window(time_now_utc(), '0 22 31 12 *', '24h')", expected: true},
+		{query: "time_in_window(time_now_utc(), '*/20 * * * *', '10m')", expected: false},
+		{query: "time_in_window(time_now_utc(), '5/25 * * * *', '5m')", expected: true},
+		{query: "time_in_window(time_now_utc(), '0 0 1 1 1', '1s')", expected: false},
+		{query: "time_in_window(time_now_utc(), '0 16 13 * 5', '1h')", expected: true},
+	}
+
+	for _, tc := range testcases {
+		result, err := ctx.Query(tc.query)
+		if err != nil {
+			t.Errorf("query %s: unexpected error %v", tc.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("query %s: expected %v, found %v", tc.query, tc.expected, result)
+		}
+	}
+
+	invalid := []string{
+		"time_in_zone(time_now_utc(), 'Mars/Olympus')",
+		"time_in_window(time_now_utc(), '0 9 * *', '8h')",
+		"time_in_window(time_now_utc(), '60 9 * * *', '8h')",
+		"time_in_window(time_now_utc(), '0 9-5 * * *', '8h')",
+		"time_in_window(time_now_utc(), '*/0 * * * *', '8h')",
+		"time_in_window(time_now_utc(), '0 9 * * *', '-1h')",
+		"time_in_window(time_now_utc(), '0 9 * * *', '1000h')",
+	}
+
+	for _, query := range invalid {
+		if _, err := ctx.Query(query); err == nil {
+			t.Errorf("query %s: expected an error", query)
+		}
+	}
+}
+
+func Test_QueryBuiltInVariables(t *testing.T) {
+	ctx := NewContext("request.object", "maxReplicas")
+	if err := ctx.AddResource([]byte(`{"metadata": {"name": "nginx"}, "spec": {"replicas": 3}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := []string{
+		"request.object.metadata.name",
+		"add(request.object.spec.replicas, `1`)",
+		"time_now()",
+		"request.object.spec.containers[?name == other].image",
+	}
+
+	for _, query := range valid {
+		if _, err := ctx.Query(query); err != nil {
+			t.Errorf("query %s: unexpected error %v", query, err)
+		}
+	}
+
+	invalid := []string{
+		"request.operation",
+		"add(request.object.spec.replicas, request.userInfo.count)",
+		"[request.object.metadata.name, team]",
+	}
+
+	for _, query := range invalid {
+		if _, err := ctx.Query(query); err == nil {
+			t.Errorf("query %s: expected an error", query)
+		}
+	}
+}
@@ -16,20 +16,27 @@ func (ctx *Context) Query(query string) (interface{}, error) {
 	}
 
 	var emptyResult interface{}
+	// compile the query
+	queryPath, err := jmespath.Compile(query)
+	if err != nil {
+		ctx.log.Error(err, "incorrect query", "query", query)
+		return emptyResult, fmt.Errorf("incorrect query %s: %v", query, err)
+	}
+	register(queryPath)
+
 	// check for white-listed variables
-	if !ctx.isBuiltInVariable(query) {
+	if !ctx.isBuiltInQuery(query) {
 		return emptyResult, InvalidVariableErr{
 			variable:  query,
 			whiteList: ctx.getBuiltInVars(),
 		}
 	}
 
-	// compile the query
-	queryPath, err := jmespath.Compile(query)
-	if err != nil {
-		ctx.log.Error(err, "incorrect query", "query", query)
-		return emptyResult, fmt.Errorf("incorrect query %s: %v", query, err)
+	// load the context entries referenced by the query
+	if err := ctx.loadDeferred(query); err != nil {
+		return emptyResult, err
 	}
+
 	// search
 	ctx.mutex.RLock()
 	defer ctx.mutex.RUnlock()
@@ -48,6 +55,25 @@ func (ctx *Context) Query(query string) (interface{}, error) {
 	return result, nil
 }
 
+// isBuiltInQuery checks the fields of the query evaluated against the context are white-listed
+func (ctx *Context) isBuiltInQuery(query string) bool {
+	if len(ctx.getBuiltInVars()) == 0 {
+		return true
+	}
+
+	ast, err := jmespath.NewParser().Parse(query)
+	if err != nil {
+		return false
+	}
+
+	for _, path := range VariablePaths(ast) {
+		if !ctx.isBuiltInVariable(strings.Join(path, ".")) {
+			return false
+		}
+	}
+	return true
+}
+
 func (ctx *Context) isBuiltInVariable(variable string) bool {
 	if len(ctx.getBuiltInVars()) == 0 {
 		return true
@@ -59,3 +85,73 @@ func (ctx *Context) isBuiltInVariable(variable string) bool {
 	}
 	return false
 }
+
+// VariablePaths returns the paths of the fields of the JMESPath expression which are evaluated
+// against the context, e.g. [request object spec replicas] and [maxReplicas] in
+// add(request.object.spec.replicas, maxReplicas). The fields evaluated against other values, in
+// sub-expressions, projections, filters, pipes and expression references, are not returned.
+func VariablePaths(ast jmespath.ASTNode) [][]string {
+	var paths [][]string
+	walkFields(ast, false, func(path []string) {
+		paths = append(paths, path)
+	})
+	return paths
+}
+
+// walkFields visits the paths of the fields of the node, relative is set when the node is
+// evaluated against another value than the context
+func walkFields(node jmespath.ASTNode, relative bool, visit func(path []string)) {
+	children := node.Children()
+	switch node.Type() {
+	case jmespath.ASTField, jmespath.ASTSubexpression, jmespath.ASTIndexExpression:
+		if path, ok := fieldPath(node); ok {
+			if !relative {
+				visit(path)
+			}
+			return
+		}
+		// the right side is evaluated against the result of the left side
+		walkFields(children[0], relative, visit)
+		for _, child := range children[1:] {
+			walkFields(child, true, visit)
+		}
+	case jmespath.ASTProjection, jmespath.ASTFilterProjection, jmespath.ASTValueProjection, jmespath.ASTPipe:
+		walkFields(children[0], relative, visit)
+		for _, child := range children[1:] {
+			walkFields(child, true, visit)
+		}
+	case jmespath.ASTExpRef:
+		for _, child := range children {
+			walkFields(child, true, visit)
+		}
+	default:
+		for _, child := range children {
+			walkFields(child, relative, visit)
+		}
+	}
+}
+
+// fieldPath returns the names of the fields of a chain of sub-expressions, the indexes are ignored,
+// e.g. [spec containers image] for spec.containers[0].image
+func fieldPath(node jmespath.ASTNode) ([]string, bool) {
+	children := node.Children()
+	switch node.Type() {
+	case jmespath.ASTField:
+		name, ok := node.Value().(string)
+		return []string{name}, ok
+	case jmespath.ASTSubexpression:
+		left, ok := fieldPath(children[0])
+		if !ok || children[1].Type() != jmespath.ASTField {
+			return nil, false
+		}
+		right, ok := fieldPath(children[1])
+		return append(left, right...), ok
+	case jmespath.ASTIndexExpression:
+		if children[1].Type() != jmespath.ASTIndex {
+			return nil, false
+		}
+		return fieldPath(children[0])
+	}
+
+	return nil, false
+}
@@ -0,0 +1,207 @@
+package context
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	jmespath "github.com/jmespath/go-jmespath"
+)
+
+// now returns the current time, it is replaced in tests
+var now = time.Now
+
+// function is a custom function which can be called in variables, e.g. {{ time_now() }}
+type function struct {
+	arity   int
+	handler func(args []interface{}) (interface{}, error)
+}
+
+// functions registered in addition to the JMESPath built-in functions
+// - timestamps are RFC3339 strings, e.g. "2021-01-02T15:04:05Z"
+// - durations are Go duration strings, e.g. "720h"
+// - schedules are cron strings with 5 fields, e.g. "0 9 * * 1-5" for 9:00 on weekdays
+// - addresses are IPv4 or IPv6 strings and ranges are CIDR strings, e.g. "10.0.0.0/8"
+// - patterns are wildcard patterns with * and ?, e.g. "ghcr.io/*"
+// - certificates are PEM strings, the data of Secrets is decoded with base64_decode
+var functions = map[string]function{
+	// time_now() returns the current time in the local time zone of Kyverno, e.g. set by the TZ environment variable
+	"time_now": {arity: 0, handler: timeNow},
+	// time_now_utc() returns the current time in UTC
+	"time_now_utc": {arity: 0, handler: timeNowUTC},
+	// time_parse(timestamp) returns the timestamp in UTC
+	"time_parse": {arity: 1, handler: timeParse},
+	// time_add(timestamp, duration) returns the timestamp moved by the duration
+	"time_add": {arity: 2, handler: timeAdd},
+	// time_diff(start, end) returns the duration from start to end
+	"time_diff": {arity: 2, handler: timeDiff},
+	// time_before(timestamp, other) checks if the timestamp is before the other timestamp
+	"time_before": {arity: 2, handler: timeBefore},
+	// time_after(timestamp, other) checks if the timestamp is after the other timestamp
+	"time_after": {arity: 2, handler: timeAfter},
+	// time_in_zone(timestamp, zone) returns the timestamp in the IANA time zone, e.g. "Europe/Paris"
+	"time_in_zone": {arity: 2, handler: timeInZone},
+	// time_in_window(timestamp, schedule, duration) checks if the timestamp is within the duration after
+	// an occurrence of the schedule, the schedule is evaluated in the time zone of the timestamp
+	"time_in_window": {arity: 3, handler: timeInWindow},
+	// parse_ip(address) returns the address in its canonical form
+	"parse_ip": {arity: 1, handler: parseIP},
+	// cidr_contains(cidr, value) checks if the address or CIDR value is within the CIDR
+	"cidr_contains": {arity: 2, handler: cidrContains},
+	// ip_in_range(address, first, last) checks if the address is within the inclusive range
+	"ip_in_range": {arity: 3, handler: ipInRange},
+	// regex_match(regex, value) checks if the value matches the regular expression
+	"regex_match": {arity: 2, handler: regexMatch},
+	// pattern_match(pattern, value) checks if the value matches the wildcard pattern
+	"pattern_match": {arity: 2, handler: patternMatch},
+	// split(string, separator) returns the substrings between the separators
+	"split": {arity: 2, handler: split},
+	// replace_all(string, old, new) returns the string with all the occurrences of old replaced by new
+	"replace_all": {arity: 3, handler: replaceAll},
+	// to_upper(string) returns the string in upper case
+	"to_upper": {arity: 1, handler: toUpper},
+	// to_lower(string) returns the string in lower case
+	"to_lower": {arity: 1, handler: toLower},
+	// base64_decode(string) returns the decoded base64 string
+	"base64_decode": {arity: 1, handler: base64Decode},
+	// base64_encode(string) returns the base64 encoding of the string
+	"base64_encode": {arity: 1, handler: base64Encode},
+	// add(a, b) returns the sum of the numbers
+	"add": {arity: 2, handler: add},
+	// subtract(a, b) returns the difference of the numbers
+	"subtract": {arity: 2, handler: subtract},
+	// x509_decode(certificate) returns the subject, issuer, SANs and validity of the certificate
+	"x509_decode": {arity: 1, handler: x509Decode},
+}
+
+// FunctionNames returns the names of the custom functions, sorted
+func FunctionNames() []string {
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// register registers the custom functions on the compiled query, the arguments of the functions
+// are evaluated by JMESPath, e.g. the address of cidr_contains('10.0.0.0/8', spec.clusterIP)
+func register(queryPath *jmespath.JMESPath) {
+	for name, fn := range functions {
+		queryPath.Register(fn.entry(name))
+	}
+}
+
+// entry returns the JMESPath function, the handlers check the types of their arguments
+func (fn function) entry(name string) jmespath.FunctionEntry {
+	arguments := make([]jmespath.ArgSpec, fn.arity)
+	for i := range arguments {
+		arguments[i] = jmespath.ArgSpec{Types: []jmespath.JpType{jmespath.JpAny}}
+	}
+
+	return jmespath.FunctionEntry{
+		Name:      name,
+		Arguments: arguments,
+		Handler: func(args []interface{}) (interface{}, error) {
+			if len(args) != fn.arity {
+				return nil, fmt.Errorf("function %s expects %d arguments, found %d", name, fn.arity, len(args))
+			}
+			return fn.handler(args)
+		},
+	}
+}
+
+func timeNow(args []interface{}) (interface{}, error) {
+	return now().Local().Format(time.RFC3339), nil
+}
+
+func timeNowUTC(args []interface{}) (interface{}, error) {
+	return now().UTC().Format(time.RFC3339), nil
+}
+
+func timeParse(args []interface{}) (interface{}, error) {
+	t, err := parseTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+func timeAdd(args []interface{}) (interface{}, error) {
+	t, err := parseTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := parseDuration(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Add(d).UTC().Format(time.RFC3339), nil
+}
+
+func timeDiff(args []interface{}) (interface{}, error) {
+	start, err := parseTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := parseTime(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return end.Sub(start).String(), nil
+}
+
+func timeBefore(args []interface{}) (interface{}, error) {
+	t, other, err := parseTimes(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.Before(other), nil
+}
+
+func timeAfter(args []interface{}) (interface{}, error) {
+	t, other, err := parseTimes(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.After(other), nil
+}
+
+func parseTimes(args []interface{}) (time.Time, time.Time, error) {
+	t, err := parseTime(args[0])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	other, err := parseTime(args[1])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return t, other, nil
+}
+
+func parseTime(value interface{}) (time.Time, error) {
+	str, ok := value.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("expected an RFC3339 timestamp, found %v of type %T", value, value)
+	}
+
+	return time.Parse(time.RFC3339, str)
+}
+
+func parseDuration(value interface{}) (time.Duration, error) {
+	str, ok := value.(string)
+	if !ok {
+		return 0, fmt.Errorf("expected a duration, found %v of type %T", value, value)
+	}
+
+	return time.ParseDuration(str)
+}
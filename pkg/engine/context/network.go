@@ -0,0 +1,98 @@
+package context
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+)
+
+func parseIP(args []interface{}) (interface{}, error) {
+	ip, err := toIP(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return ip.String(), nil
+}
+
+func cidrContains(args []interface{}) (interface{}, error) {
+	network, err := toCIDR(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	str, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("expected an IP address or CIDR, found %v of type %T", args[1], args[1])
+	}
+
+	// a CIDR is contained if its first address is contained and its prefix is not shorter
+	if _, subnet, err := net.ParseCIDR(str); err == nil {
+		ones, bits := network.Mask.Size()
+		subnetOnes, subnetBits := subnet.Mask.Size()
+		return bits == subnetBits && subnetOnes >= ones && network.Contains(subnet.IP), nil
+	}
+
+	ip, err := toIP(str)
+	if err != nil {
+		return nil, err
+	}
+
+	return network.Contains(ip), nil
+}
+
+func ipInRange(args []interface{}) (interface{}, error) {
+	ip, err := toIP(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := toIP(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := toIP(args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	ip, first, last = ip.To16(), first.To16(), last.To16()
+	if isIPv4(ip) != isIPv4(first) || isIPv4(ip) != isIPv4(last) {
+		return false, nil
+	}
+
+	return bytes.Compare(ip, first) >= 0 && bytes.Compare(ip, last) <= 0, nil
+}
+
+func isIPv4(ip net.IP) bool {
+	return ip.To4() != nil
+}
+
+func toIP(value interface{}) (net.IP, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected an IP address, found %v of type %T", value, value)
+	}
+
+	ip := net.ParseIP(str)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %s", str)
+	}
+
+	return ip, nil
+}
+
+func toCIDR(value interface{}) (*net.IPNet, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a CIDR, found %v of type %T", value, value)
+	}
+
+	_, network, err := net.ParseCIDR(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR %s: %v", str, err)
+	}
+
+	return network, nil
+}
@@ -0,0 +1,188 @@
+package context
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxWindow bounds the search of the schedule occurrence which opened the window
+const maxWindow = 31 * 24 * time.Hour
+
+// schedule is a cron schedule with the fields minute, hour, day of month, month and day of week
+type schedule struct {
+	minutes, hours, days, months, weekdays uint64
+
+	// days and weekdays restricted by the schedule match either of them, as with cron
+	anyDay, anyWeekday bool
+}
+
+// scheduleField is the range of the values of a cron field
+type scheduleField struct {
+	name     string
+	min, max int
+}
+
+var scheduleFields = []scheduleField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+func timeInZone(args []interface{}) (interface{}, error) {
+	t, err := parseTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	zone, err := toString(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %s: %v", zone, err)
+	}
+
+	return t.In(location).Format(time.RFC3339), nil
+}
+
+func timeInWindow(args []interface{}) (interface{}, error) {
+	t, err := parseTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err := toString(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := parseSchedule(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := parseDuration(args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	if d <= 0 || d > maxWindow {
+		return nil, fmt.Errorf("the window duration %s must be positive and at most %s", d, maxWindow)
+	}
+
+	return s.inWindow(t, d), nil
+}
+
+// inWindow checks if the time is within the duration after an occurrence of the schedule
+func (s *schedule) inWindow(t time.Time, d time.Duration) bool {
+	start := t.Truncate(time.Minute)
+	for occurrence := start; t.Sub(occurrence) < d; occurrence = occurrence.Add(-time.Minute) {
+		if s.matches(occurrence) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches checks if the schedule occurs at the minute of the time, in the time zone of the time
+func (s *schedule) matches(t time.Time) bool {
+	if !hasBit(s.minutes, t.Minute()) || !hasBit(s.hours, t.Hour()) || !hasBit(s.months, int(t.Month())) {
+		return false
+	}
+
+	day := hasBit(s.days, t.Day())
+	weekday := hasBit(s.weekdays, int(t.Weekday()))
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+
+	return day || weekday
+}
+
+// parseSchedule parses the standard cron format, e.g. "0 9 * * 1-5" for 9:00 on weekdays
+func parseSchedule(expr string) (*schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(scheduleFields) {
+		return nil, fmt.Errorf("invalid schedule %s: expected %d fields, found %d", expr, len(scheduleFields), len(fields))
+	}
+
+	values := make([]uint64, len(fields))
+	for i, field := range fields {
+		bits, err := parseScheduleField(field, scheduleFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %s: %v", expr, err)
+		}
+		values[i] = bits
+	}
+
+	// 7 is an alias of Sunday
+	weekdays := values[4]
+	if hasBit(weekdays, 7) {
+		weekdays |= 1
+	}
+
+	return &schedule{
+		minutes:    values[0],
+		hours:      values[1],
+		days:       values[2],
+		months:     values[3],
+		weekdays:   weekdays,
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}, nil
+}
+
+// parseScheduleField parses the comma separated values, ranges and steps of a cron field, e.g. "*/15" or "1-5,0"
+func parseScheduleField(field string, f scheduleField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangeExpr, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field %s", f.name, item)
+			}
+			rangeExpr, step = item[:i], n
+		}
+
+		first, last := f.min, f.max
+		if rangeExpr != "*" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if first, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value in %s field %s", f.name, item)
+			}
+
+			switch {
+			case len(bounds) == 2:
+				if last, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value in %s field %s", f.name, item)
+				}
+			case step == 1:
+				// a single value unless a step follows it, e.g. "5/10" is every 10 from 5
+				last = first
+			}
+		}
+
+		if first < f.min || last > f.max || first > last {
+			return 0, fmt.Errorf("%s field %s is out of the range %d-%d", f.name, item, f.min, f.max)
+		}
+
+		for v := first; v <= last; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func hasBit(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
@@ -0,0 +1,183 @@
+package context
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+func regexMatch(args []interface{}) (interface{}, error) {
+	expr, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %s: %v", expr, err)
+	}
+
+	return regex.MatchString(toText(args[1])), nil
+}
+
+func patternMatch(args []interface{}) (interface{}, error) {
+	pattern, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return wildcard.Match(pattern, toText(args[1])), nil
+}
+
+func split(args []interface{}) (interface{}, error) {
+	str, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	sep, err := toString(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return toList(strings.Split(str, sep)), nil
+}
+
+func replaceAll(args []interface{}) (interface{}, error) {
+	str, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := toString(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	replacement, err := toString(args[2])
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.ReplaceAll(str, old, replacement), nil
+}
+
+func toUpper(args []interface{}) (interface{}, error) {
+	str, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.ToUpper(str), nil
+}
+
+func toLower(args []interface{}) (interface{}, error) {
+	str, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.ToLower(str), nil
+}
+
+func base64Decode(args []interface{}) (interface{}, error) {
+	str, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 string: %v", err)
+	}
+
+	return string(decoded), nil
+}
+
+func base64Encode(args []interface{}) (interface{}, error) {
+	str, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(str)), nil
+}
+
+func add(args []interface{}) (interface{}, error) {
+	a, b, err := toNumbers(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return a + b, nil
+}
+
+func subtract(args []interface{}) (interface{}, error) {
+	a, b, err := toNumbers(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return a - b, nil
+}
+
+func toString(value interface{}) (string, error) {
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("expected a string, found %v of type %T", value, value)
+	}
+
+	return str, nil
+}
+
+// toText returns strings as is and the text representation of other scalar values, e.g. of numbers
+func toText(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+func toList(values []string) []interface{} {
+	var list []interface{}
+	for _, value := range values {
+		list = append(list, value)
+	}
+
+	return list
+}
+
+func toNumbers(args []interface{}) (float64, float64, error) {
+	a, err := toNumber(args[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	b, err := toNumber(args[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return a, b, nil
+}
+
+func toNumber(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("expected a number, found %v of type %T", value, value)
+	}
+}
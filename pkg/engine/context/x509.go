@@ -0,0 +1,69 @@
+package context
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+)
+
+// x509Decode returns the fields of the first certificate of a PEM string, e.g. of the tls.crt of a TLS Secret
+func x509Decode(args []interface{}) (interface{}, error) {
+	str, err := toString(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := parseCertificate(str)
+	if err != nil {
+		return nil, err
+	}
+
+	var ipAddresses []interface{}
+	for _, ip := range cert.IPAddresses {
+		ipAddresses = append(ipAddresses, ip.String())
+	}
+
+	var uris []interface{}
+	for _, uri := range cert.URIs {
+		uris = append(uris, uri.String())
+	}
+
+	return map[string]interface{}{
+		"subject":            cert.Subject.String(),
+		"commonName":         cert.Subject.CommonName,
+		"issuer":             cert.Issuer.String(),
+		"serialNumber":       cert.SerialNumber.String(),
+		"notBefore":          cert.NotBefore.UTC().Format(time.RFC3339),
+		"notAfter":           cert.NotAfter.UTC().Format(time.RFC3339),
+		"dnsNames":           toList(cert.DNSNames),
+		"ipAddresses":        ipAddresses,
+		"emailAddresses":     toList(cert.EmailAddresses),
+		"uris":               uris,
+		"isCA":               cert.IsCA,
+		"signatureAlgorithm": cert.SignatureAlgorithm.String(),
+		"publicKeyAlgorithm": cert.PublicKeyAlgorithm.String(),
+	}, nil
+}
+
+func parseCertificate(str string) (*x509.Certificate, error) {
+	rest := []byte(str)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM certificate found")
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %v", err)
+		}
+
+		return cert, nil
+	}
+}
@@ -0,0 +1,57 @@
+package deprecations
+
+import (
+	"fmt"
+
+	"github.com/kyverno/kyverno/pkg/engine/common"
+)
+
+// Deprecation describes an API version of a kind which is deprecated and removed in a Kubernetes release
+type Deprecation struct {
+	APIVersion   string
+	Kind         string
+	DeprecatedIn string
+	RemovedIn    string
+	// Replacement is the API version to migrate to, empty if the kind is no longer served
+	Replacement string
+}
+
+// Lookup returns the deprecation for the API version and kind, if any
+func Lookup(apiVersion, kind string) (Deprecation, bool) {
+	for _, d := range table {
+		if d.APIVersion == apiVersion && d.Kind == kind {
+			return d, true
+		}
+	}
+
+	return Deprecation{}, false
+}
+
+// IsDeprecated checks if the API version is deprecated in the Kubernetes version
+func (d Deprecation) IsDeprecated(version string) (bool, error) {
+	return isReached(d.DeprecatedIn, version)
+}
+
+// IsRemoved checks if the API version is no longer served by the Kubernetes version
+func (d Deprecation) IsRemoved(version string) (bool, error) {
+	return isReached(d.RemovedIn, version)
+}
+
+// String returns a description of the deprecation
+func (d Deprecation) String() string {
+	msg := fmt.Sprintf("%s %s is deprecated in Kubernetes v%s and removed in v%s", d.APIVersion, d.Kind, d.DeprecatedIn, d.RemovedIn)
+	if d.Replacement == "" {
+		return msg
+	}
+
+	return fmt.Sprintf("%s, migrate to %s", msg, d.Replacement)
+}
+
+func isReached(release, version string) (bool, error) {
+	result, err := common.CompareSemver(version, release)
+	if err != nil {
+		return false, fmt.Errorf("invalid Kubernetes version %s: %v", version, err)
+	}
+
+	return result >= 0, nil
+}
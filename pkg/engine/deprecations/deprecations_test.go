@@ -0,0 +1,45 @@
+package deprecations
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_Lookup(t *testing.T) {
+	deprecation, ok := Lookup("extensions/v1beta1", "Ingress")
+	assert.Assert(t, ok)
+	assert.Equal(t, deprecation.RemovedIn, "1.22")
+	assert.Equal(t, deprecation.String(), "extensions/v1beta1 Ingress is deprecated in Kubernetes v1.14 and removed in v1.22, migrate to networking.k8s.io/v1")
+
+	_, ok = Lookup("networking.k8s.io/v1", "Ingress")
+	assert.Assert(t, !ok)
+}
+
+func Test_Deprecation_Versions(t *testing.T) {
+	deprecation, _ := Lookup("batch/v1beta1", "CronJob")
+
+	testcases := []struct {
+		version    string
+		deprecated bool
+		removed    bool
+	}{
+		{version: "1.20", deprecated: false, removed: false},
+		{version: "v1.21.3", deprecated: true, removed: false},
+		{version: "1.25", deprecated: true, removed: true},
+		{version: "1.26.0", deprecated: true, removed: true},
+	}
+
+	for _, tc := range testcases {
+		deprecated, err := deprecation.IsDeprecated(tc.version)
+		assert.NilError(t, err)
+		assert.Equal(t, deprecated, tc.deprecated, tc.version)
+
+		removed, err := deprecation.IsRemoved(tc.version)
+		assert.NilError(t, err)
+		assert.Equal(t, removed, tc.removed, tc.version)
+	}
+
+	_, err := deprecation.IsRemoved("latest")
+	assert.Assert(t, err != nil)
+}
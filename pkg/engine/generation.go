@@ -24,7 +24,8 @@ func filterRules(policyContext *PolicyContext) *response.EngineResponse {
 	apiVersion := policyContext.NewResource.GetAPIVersion()
 	resp := &response.EngineResponse{
 		PolicyResponse: response.PolicyResponse{
-			Policy: policyContext.Policy.Name,
+			Policy:    policyContext.Policy.Name,
+			Namespace: policyContext.Policy.Namespace,
 			Resource: response.ResourceSpec{
 				Kind:       kind,
 				Name:       name,
@@ -87,7 +88,7 @@ func filterRule(rule kyverno.Rule, policyContext *PolicyContext) *response.RuleR
 	policyContext.JSONContext.Checkpoint()
 	defer policyContext.JSONContext.Restore()
 
-	if err := LoadContext(logger, rule.Context, resCache, policyContext); err != nil {
+	if err := LoadContextLazily(logger, rule.Context, resCache, policyContext); err != nil {
 		logger.V(4).Info("cannot add external data to the context", "reason", err.Error())
 		return nil
 	}
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+)
+
+// GlobalContextStore provides the data of the GlobalContextEntry resources
+type GlobalContextStore interface {
+	// Get returns the JSON data of the global context entry
+	Get(name string) ([]byte, error)
+}
+
+// globalContextStore provides the data of the GlobalReference context entries, it is not set
+// when the global context is not available (e.g. in the CLI)
+var globalContextStore GlobalContextStore
+
+// SetGlobalContextStore sets the store of the global context entries referenced by the rules
+func SetGlobalContextStore(store GlobalContextStore) {
+	globalContextStore = store
+}
+
+func loadGlobalContextData(entry kyverno.ContextEntry, ctx *PolicyContext) error {
+	if globalContextStore == nil {
+		return fmt.Errorf("global context entry %s is not available for context entry %s: the global context is not enabled", entry.GlobalReference.Name, entry.Name)
+	}
+
+	jsonData, err := globalContextStore.Get(entry.GlobalReference.Name)
+	if err != nil {
+		return fmt.Errorf("failed to load global context entry for context entry %s: %v", entry.Name, err)
+	}
+
+	return addNamedData(ctx.JSONContext, entry.Name, jsonData, entry.GlobalReference.JMESPath)
+}
+
+// FetchGlobalContextData returns the JSON data of a global context entry, transformed by the
+// JMESPath of the API call or service call
+func FetchGlobalContextData(logger logr.Logger, client *client.Client, name string, spec kyverno.GlobalContextEntrySpec) ([]byte, error) {
+	// global context entries are not evaluated for a resource, no variables are available
+	ctx := &PolicyContext{JSONContext: context.NewContext(), Client: client}
+	entry := kyverno.ContextEntry{Name: name, APICall: spec.APICall, ServiceCall: spec.ServiceCall}
+
+	var jsonData []byte
+	var jmesPath string
+	var err error
+	if spec.APICall != nil {
+		jsonData, err = fetchAPIData(logger, entry, ctx)
+		jmesPath = spec.APICall.JMESPath
+	} else if spec.ServiceCall != nil {
+		jsonData, err = fetchServiceData(logger, entry, ctx)
+		jmesPath = spec.ServiceCall.JMESPath
+	} else {
+		return nil, fmt.Errorf("an apiCall or serviceCall is required for global context entry %s", name)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if jmesPath == "" {
+		return jsonData, nil
+	}
+
+	results, err := applyJMESPath(jmesPath, jsonData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply JMESPath for global context entry %s: %v", name, err)
+	}
+
+	return json.Marshal(results)
+}
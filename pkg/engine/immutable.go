@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+)
+
+// immutableFields returns the immutable fields of the kind configured in the policy context
+func immutableFields(policyContext *PolicyContext, kind string) []string {
+	if policyContext.ImmutableFieldsFunc == nil {
+		return nil
+	}
+
+	return policyContext.ImmutableFieldsFunc(kind)
+}
+
+// immutableFieldPatched returns the first immutable field modified by the patches.
+// Fields are declared in dot notation, e.g. "spec.selector", and a patch modifies a field
+// if it operates on the field, on one of its children or on one of its parents.
+func immutableFieldPatched(patches [][]byte, fields []string) (string, bool) {
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	for _, patch := range patches {
+		var operation struct {
+			Path string `json:"path"`
+			From string `json:"from"`
+		}
+
+		if err := json.Unmarshal(patch, &operation); err != nil {
+			continue
+		}
+
+		for _, field := range fields {
+			pointer := "/" + strings.ReplaceAll(strings.Trim(field, "."), ".", "/")
+			if pathOverlaps(operation.Path, pointer) || (operation.From != "" && pathOverlaps(operation.From, pointer)) {
+				return field, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func pathOverlaps(path, pointer string) bool {
+	return path == pointer || strings.HasPrefix(path, pointer+"/") || strings.HasPrefix(pointer, path+"/")
+}
+
+// failImmutableFieldMutation fails the rule and discards its patches
+func failImmutableFieldMutation(ruleResponse *response.RuleResponse, field string) {
+	ruleResponse.Success = false
+	ruleResponse.Patches = nil
+	ruleResponse.Message = fmt.Sprintf("mutation of immutable field %s is not allowed", field)
+}
@@ -1,50 +1,128 @@
 package engine
 
 import (
+	contextdefault "context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/jmespath/go-jmespath"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/engine/variables"
+	"github.com/kyverno/kyverno/pkg/registryclient"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic/dynamiclister"
 )
 
+// apiCallTimeout bounds the duration of the API calls of context entries, to keep the admission latency bounded
+const apiCallTimeout = 5 * time.Second
+
+// registryClient fetches the image data of ImageRegistry context entries
+var registryClient = registryclient.DefaultClient
+
 // LoadContext - Fetches and adds external data to the Context.
 func LoadContext(logger logr.Logger, contextEntries []kyverno.ContextEntry, resCache resourcecache.ResourceCache, ctx *PolicyContext) error {
-	if len(contextEntries) == 0 {
+	if len(contextEntries) == 0 && len(ctx.Policy.Spec.Variables) == 0 {
 		return nil
 	}
 
-	// get GVR Cache for "configmaps"
-	// can get cache for other resources if the informers are enabled in resource cache
-	gvrC, ok := resCache.GetGVRCache("ConfigMap")
-	if !ok {
-		return errors.New("configmaps GVR Cache not found")
+	if err := loadVariableDefaults(ctx); err != nil {
+		return err
+	}
+
+	for _, entry := range contextEntries {
+		if err := loadContextEntry(logger, entry, resCache, ctx); err != nil {
+			return err
+		}
 	}
 
-	lister := gvrC.Lister()
+	return nil
+}
+
+// LoadContextLazily adds the context entries to the Context without fetching their data. The data
+// of an entry is fetched when a variable of the rule first references the entry, so that the
+// entries of rules which do not pass their preconditions are not fetched, and the errors of an
+// entry are returned by the queries referencing it. The API calls without a JMESPath are loaded
+// eagerly, as their data is merged at the root of the Context and not under the name of the entry.
+func LoadContextLazily(logger logr.Logger, contextEntries []kyverno.ContextEntry, resCache resourcecache.ResourceCache, ctx *PolicyContext) error {
+	if len(contextEntries) == 0 && len(ctx.Policy.Spec.Variables) == 0 {
+		return nil
+	}
+
+	if err := loadVariableDefaults(ctx); err != nil {
+		return err
+	}
 
 	for _, entry := range contextEntries {
-		if entry.ConfigMap != nil {
-			if err := loadConfigMap(logger, entry, lister, ctx.JSONContext); err != nil {
-				return err
-			}
-		} else if entry.APICall != nil {
-			if err := loadAPIData(logger, entry, ctx); err != nil {
+		if entry.APICall != nil && entry.APICall.JMESPath == "" {
+			if err := loadContextEntry(logger, entry, resCache, ctx); err != nil {
 				return err
 			}
+			continue
 		}
+
+		entry := entry
+		ctx.JSONContext.AddDeferredLoader(entry.Name, func() error {
+			logger.V(4).Info("loading context entry", "name", entry.Name)
+			return loadContextEntry(logger, entry, resCache, ctx)
+		})
 	}
 
 	return nil
 }
 
+// loadContextEntry fetches the data of the context entry and checks the type of the declared variable it overrides
+func loadContextEntry(logger logr.Logger, entry kyverno.ContextEntry, resCache resourcecache.ResourceCache, ctx *PolicyContext) error {
+	var err error
+	if entry.ConfigMap != nil {
+		var lister dynamiclister.Lister
+		if lister, err = configMapLister(resCache); err != nil {
+			return err
+		}
+
+		err = loadConfigMap(logger, entry, lister, ctx.JSONContext)
+	} else if entry.APICall != nil {
+		err = loadAPIData(logger, entry, ctx)
+	} else if entry.ImageRegistry != nil {
+		err = loadImageData(logger, entry, ctx)
+	} else if entry.ServiceCall != nil {
+		err = loadServiceData(logger, entry, ctx)
+	} else if entry.GlobalReference != nil {
+		err = loadGlobalContextData(entry, ctx)
+	} else if entry.Secret != nil {
+		err = loadSecretData(logger, entry, ctx)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return checkVariableType(ctx, entry.Name)
+}
+
+// configMapLister returns the lister of the ConfigMap cache, the cache is only required
+// by ConfigMap context entries
+func configMapLister(resCache resourcecache.ResourceCache) (dynamiclister.Lister, error) {
+	if resCache == nil {
+		return nil, errors.New("configmaps GVR Cache not found")
+	}
+
+	// get GVR Cache for "configmaps"
+	// can get cache for other resources if the informers are enabled in resource cache
+	gvrC, ok := resCache.GetGVRCache("ConfigMap")
+	if !ok {
+		return nil, errors.New("configmaps GVR Cache not found")
+	}
+
+	return gvrC.Lister(), nil
+}
+
 func loadAPIData(logger logr.Logger, entry kyverno.ContextEntry, ctx *PolicyContext) error {
 	jsonData, err := fetchAPIData(logger, entry, ctx)
 	if err != nil {
@@ -112,25 +190,61 @@ func fetchAPIData(log logr.Logger, entry kyverno.ContextEntry, ctx *PolicyContex
 		return nil, fmt.Errorf("failed to build API path for %s %v: %v", entry.Name, entry.APICall, err)
 	}
 
-	var jsonData []byte
-	if p.Name != "" {
-		jsonData, err = loadResource(ctx, p)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add resource with urlPath: %s: %v", p, err)
+	key := p.String()
+	if jsonData, ok := ctx.apiCallCache[key]; ok {
+		log.V(4).Info("using cached API call response", "urlPath", key)
+		return jsonData, nil
+	}
+
+	jsonData, err := callWithTimeout(func(callCtx contextdefault.Context) ([]byte, error) {
+		if p.Name != "" {
+			data, err := loadResource(callCtx, ctx, p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to add resource with urlPath: %s: %v", p, err)
+			}
+
+			return data, nil
 		}
 
-	} else {
-		jsonData, err = loadResourceList(ctx, p)
+		data, err := loadResourceList(callCtx, ctx, p)
 		if err != nil {
 			return nil, fmt.Errorf("failed to add resource list with urlPath: %s, error: %v", p, err)
 		}
+
+		return data, nil
+	}, apiCallTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.apiCallCache == nil {
+		ctx.apiCallCache = make(map[string][]byte)
 	}
+	ctx.apiCallCache[key] = jsonData
 
 	return jsonData, nil
 }
 
-func loadResourceList(ctx *PolicyContext, p *APIPath) ([]byte, error) {
-	l, err := ctx.Client.ListResource(p.Version, p.ResourceType, p.Namespace, nil)
+// callWithTimeout returns the result of the call, or an error if the call does not complete within the timeout.
+// The context of the call is cancelled once the timeout expires, so that the API request is cancelled.
+func callWithTimeout(call func(ctx contextdefault.Context) ([]byte, error), timeout time.Duration) ([]byte, error) {
+	ctx, cancel := contextdefault.WithTimeout(contextdefault.Background(), timeout)
+	defer cancel()
+
+	data, err := call(ctx)
+	if ctx.Err() == contextdefault.DeadlineExceeded {
+		return nil, fmt.Errorf("API call did not complete within %v", timeout)
+	}
+
+	return data, err
+}
+
+func loadResourceList(callCtx contextdefault.Context, ctx *PolicyContext, p *APIPath) ([]byte, error) {
+	if ctx.Client == nil {
+		return nil, fmt.Errorf("API client is not available")
+	}
+
+	l, err := ctx.Client.ListResourceWithContext(callCtx, p.Version, p.ResourceType, p.Namespace, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -138,12 +252,12 @@ func loadResourceList(ctx *PolicyContext, p *APIPath) ([]byte, error) {
 	return l.MarshalJSON()
 }
 
-func loadResource(ctx *PolicyContext, p *APIPath) ([]byte, error) {
+func loadResource(callCtx contextdefault.Context, ctx *PolicyContext, p *APIPath) ([]byte, error) {
 	if ctx.Client == nil {
 		return nil, fmt.Errorf("API client is not available")
 	}
 
-	r, err := ctx.Client.GetResource(p.Version, p.ResourceType, p.Namespace, p.Name)
+	r, err := ctx.Client.GetResourceWithContext(callCtx, p.Version, p.ResourceType, p.Namespace, p.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -151,6 +265,130 @@ func loadResource(ctx *PolicyContext, p *APIPath) ([]byte, error) {
 	return r.MarshalJSON()
 }
 
+func loadImageData(logger logr.Logger, entry kyverno.ContextEntry, ctx *PolicyContext) error {
+	jsonData, err := fetchImageData(logger, entry, ctx)
+	if err != nil {
+		return err
+	}
+
+	return addNamedData(ctx.JSONContext, entry.Name, jsonData, entry.ImageRegistry.JMESPath)
+}
+
+// addNamedData stores the JSON data under the name of the context entry, transformed by the JMESPath if it is set
+func addNamedData(jsonContext *context.Context, name string, jsonData []byte, jmesPath string) error {
+	var results interface{}
+	if jmesPath == "" {
+		if err := json.Unmarshal(jsonData, &results); err != nil {
+			return fmt.Errorf("failed to unmarshal data for context entry %s: %v", name, err)
+		}
+	} else {
+		var err error
+		if results, err = applyJMESPath(jmesPath, jsonData); err != nil {
+			return fmt.Errorf("failed to apply JMESPath for context entry %s: %v", name, err)
+		}
+	}
+
+	contextData, err := json.Marshal(map[string]interface{}{name: results})
+	if err != nil {
+		return fmt.Errorf("failed to marshall data for context entry %s: %v", name, err)
+	}
+
+	if err := jsonContext.AddJSON(contextData); err != nil {
+		return fmt.Errorf("failed to add data to context for context entry %s: %v", name, err)
+	}
+
+	return nil
+}
+
+func fetchImageData(logger logr.Logger, entry kyverno.ContextEntry, ctx *PolicyContext) ([]byte, error) {
+	ref, err := variables.SubstituteVars(logger, ctx.JSONContext, entry.ImageRegistry.Reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to substitute variables in context entry %s %s: %v", entry.Name, entry.ImageRegistry.Reference, err)
+	}
+
+	image, ok := ref.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid image reference %v in context entry %s, expected a string", ref, entry.Name)
+	}
+
+	key := "image:" + image
+	if jsonData, ok := ctx.apiCallCache[key]; ok {
+		logger.V(4).Info("using cached image data", "image", image)
+		return jsonData, nil
+	}
+
+	keychain, err := imagePullSecretsKeychain(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image pull secrets for context entry %s: %v", entry.Name, err)
+	}
+
+	jsonData, err := callWithTimeout(func(callCtx contextdefault.Context) ([]byte, error) {
+		imageData, err := registryClient.FetchImageData(callCtx, image, keychain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch image data of %s: %v", image, err)
+		}
+
+		return json.Marshal(imageData)
+	}, apiCallTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.apiCallCache == nil {
+		ctx.apiCallCache = make(map[string][]byte)
+	}
+	ctx.apiCallCache[key] = jsonData
+
+	return jsonData, nil
+}
+
+// imagePullSecretsKeychain returns the registry credentials of the image pull secrets of the resource,
+// the secrets are read from the pod spec of pods and pod controllers
+func imagePullSecretsKeychain(ctx *PolicyContext) (registryclient.Keychain, error) {
+	if ctx.Client == nil {
+		return nil, nil
+	}
+
+	resource := ctx.NewResource.Object
+	var pullSecrets []interface{}
+	for _, path := range [][]string{
+		{"spec", "imagePullSecrets"},
+		{"spec", "template", "spec", "imagePullSecrets"},
+		{"spec", "jobTemplate", "spec", "template", "spec", "imagePullSecrets"},
+	} {
+		if secrets, found, _ := unstructured.NestedSlice(resource, path...); found {
+			pullSecrets = secrets
+			break
+		}
+	}
+
+	var secrets []corev1.Secret
+	for _, pullSecret := range pullSecrets {
+		reference, ok := pullSecret.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := reference["name"].(string)
+		if name == "" {
+			continue
+		}
+
+		obj, err := ctx.Client.GetResource("v1", "Secret", ctx.NewResource.GetNamespace(), name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s/%s: %v", ctx.NewResource.GetNamespace(), name, err)
+		}
+
+		var secret corev1.Secret
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &secret); err != nil {
+			return nil, fmt.Errorf("failed to convert secret %s/%s: %v", ctx.NewResource.GetNamespace(), name, err)
+		}
+		secrets = append(secrets, secret)
+	}
+
+	return registryclient.NewKeychain(secrets...)
+}
+
 func loadConfigMap(logger logr.Logger, entry kyverno.ContextEntry, lister dynamiclister.Lister, ctx *context.Context) error {
 	data, err := fetchConfigMap(logger, entry, lister, ctx)
 	if err != nil {
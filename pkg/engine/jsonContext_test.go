@@ -0,0 +1,261 @@
+package engine
+
+import (
+	contextdefault "context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"gotest.tools/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_LoadAPICallFromCache(t *testing.T) {
+	ingressList := []byte(`{
+		"apiVersion": "v1",
+		"kind": "List",
+		"items": [
+			{"metadata": {"name": "web", "namespace": "dev"}, "spec": {"rules": [{"host": "example.com"}]}},
+			{"metadata": {"name": "api", "namespace": "dev"}, "spec": {"rules": [{"host": "api.example.com"}]}},
+			{"metadata": {"name": "web", "namespace": "prod"}, "spec": {"rules": [{"host": "example.com"}]}}
+		]
+	}`)
+
+	ctx := context.NewContext()
+	assert.NilError(t, ctx.AddResource([]byte(`{"kind": "Ingress", "metadata": {"name": "web", "namespace": "test"}}`)))
+
+	// the API client is not set, the response must be served from the cache
+	policyContext := &PolicyContext{
+		JSONContext:  ctx,
+		apiCallCache: map[string][]byte{"/apis/networking.k8s.io/v1/ingresses": ingressList},
+	}
+
+	entries := []kyverno.ContextEntry{
+		{
+			Name: "hostCount",
+			APICall: &kyverno.APICall{
+				URLPath:  "/apis/networking.k8s.io/v1/ingresses",
+				JMESPath: "items[?spec.rules[0].host=='example.com'] | length(@)",
+			},
+		},
+	}
+
+	err := LoadContext(log.Log, entries, nil, policyContext)
+	assert.NilError(t, err)
+
+	count, err := ctx.Query("hostCount")
+	assert.NilError(t, err)
+	assert.Equal(t, count, 2.0)
+
+	// uncached paths are fetched from the API server
+	entries[0].APICall.URLPath = "/apis/networking.k8s.io/v1/namespaces/dev/ingresses"
+	err = LoadContext(log.Log, entries, nil, policyContext)
+	assert.ErrorContains(t, err, "API client is not available")
+
+	// the API calls without a JMESPath are merged at the root, they are not loaded lazily
+	ctx = context.NewContext()
+	policyContext = &PolicyContext{
+		JSONContext:  ctx,
+		apiCallCache: map[string][]byte{"/apis/networking.k8s.io/v1/ingresses": ingressList},
+	}
+	entries = []kyverno.ContextEntry{{Name: "ingresses", APICall: &kyverno.APICall{URLPath: "/apis/networking.k8s.io/v1/ingresses"}}}
+	assert.NilError(t, LoadContextLazily(log.Log, entries, nil, policyContext))
+
+	count, err = ctx.Query("length(items)")
+	assert.NilError(t, err)
+	assert.Equal(t, count, 3.0)
+}
+
+func Test_callWithTimeout(t *testing.T) {
+	data, err := callWithTimeout(func(ctx contextdefault.Context) ([]byte, error) {
+		return []byte(`{}`), nil
+	}, time.Second)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{}`)
+
+	// the call is cancelled once the timeout expires
+	_, err = callWithTimeout(func(ctx contextdefault.Context) ([]byte, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, 10*time.Millisecond)
+	assert.ErrorContains(t, err, "API call did not complete within 10ms")
+}
+
+func Test_LoadImageDataFromCache(t *testing.T) {
+	imageData := []byte(`{
+		"image": "ghcr.io/corp/app:1.0",
+		"resolvedImage": "ghcr.io/corp/app@sha256:4a5b6c",
+		"registry": "ghcr.io",
+		"repository": "corp/app",
+		"identifier": "1.0",
+		"digest": "sha256:4a5b6c",
+		"configData": {"architecture": "amd64", "config": {"Labels": {"team": "payments"}}}
+	}`)
+
+	ctx := context.NewContext()
+	assert.NilError(t, ctx.AddResource([]byte(`{"kind": "Pod", "metadata": {"name": "app"}, "spec": {"containers": [{"name": "app", "image": "ghcr.io/corp/app:1.0"}]}}`)))
+
+	policyContext := &PolicyContext{
+		JSONContext:  ctx,
+		apiCallCache: map[string][]byte{"image:ghcr.io/corp/app:1.0": imageData},
+	}
+
+	entries := []kyverno.ContextEntry{
+		{
+			Name:          "image",
+			ImageRegistry: &kyverno.ImageRegistry{Reference: "{{request.object.spec.containers[0].image}}"},
+		},
+		{
+			Name: "labels",
+			ImageRegistry: &kyverno.ImageRegistry{
+				Reference: "ghcr.io/corp/app:1.0",
+				JMESPath:  "configData.config.Labels",
+			},
+		},
+	}
+
+	err := LoadContext(log.Log, entries, nil, policyContext)
+	assert.NilError(t, err)
+
+	digest, err := ctx.Query("image.digest")
+	assert.NilError(t, err)
+	assert.Equal(t, digest, "sha256:4a5b6c")
+
+	team, err := ctx.Query("labels.team")
+	assert.NilError(t, err)
+	assert.Equal(t, team, "payments")
+}
+
+type globalContextStoreFunc func(name string) ([]byte, error)
+
+func (f globalContextStoreFunc) Get(name string) ([]byte, error) {
+	return f(name)
+}
+
+func Test_LoadGlobalContextData(t *testing.T) {
+	entries := []kyverno.ContextEntry{
+		{
+			Name:            "registries",
+			GlobalReference: &kyverno.GlobalContextEntryReference{Name: "allowed-registries", JMESPath: "items[?trusted].name"},
+		},
+	}
+
+	ctx := context.NewContext()
+	err := LoadContext(log.Log, entries, nil, &PolicyContext{JSONContext: ctx})
+	assert.ErrorContains(t, err, "the global context is not enabled")
+
+	SetGlobalContextStore(globalContextStoreFunc(func(name string) ([]byte, error) {
+		if name != "allowed-registries" {
+			return nil, fmt.Errorf("global context entry %s not found", name)
+		}
+		return []byte(`{"items": [{"name": "ghcr.io", "trusted": true}, {"name": "docker.io", "trusted": false}]}`), nil
+	}))
+	defer SetGlobalContextStore(nil)
+
+	err = LoadContext(log.Log, entries, nil, &PolicyContext{JSONContext: ctx})
+	assert.NilError(t, err)
+
+	registries, err := ctx.Query("registries")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, registries, []interface{}{"ghcr.io"})
+
+	entries[0].GlobalReference.Name = "missing"
+	err = LoadContext(log.Log, entries, nil, &PolicyContext{JSONContext: ctx})
+	assert.ErrorContains(t, err, "global context entry missing not found")
+}
+
+func Test_LoadContext_Variables(t *testing.T) {
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal([]byte(`{"spec": {"variables": [
+		{"name": "maxReplicas", "type": "number", "default": 3},
+		{"name": "registries", "type": "array", "default": ["ghcr.io"]},
+		{"name": "team", "type": "string"}
+	]}}`), &policy)
+	assert.NilError(t, err)
+
+	SetGlobalContextStore(globalContextStoreFunc(func(name string) ([]byte, error) {
+		return []byte(`{"registries": ["docker.io"], "replicas": "5"}`), nil
+	}))
+	defer SetGlobalContextStore(nil)
+
+	ctx := context.NewContext()
+	err = LoadContext(log.Log, nil, nil, &PolicyContext{Policy: policy, JSONContext: ctx})
+	assert.NilError(t, err)
+
+	maxReplicas, err := ctx.Query("maxReplicas")
+	assert.NilError(t, err)
+	assert.Equal(t, maxReplicas, 3.0)
+
+	// context entries override the default values
+	entries := []kyverno.ContextEntry{
+		{Name: "registries", GlobalReference: &kyverno.GlobalContextEntryReference{Name: "registries", JMESPath: "registries"}},
+	}
+	err = LoadContext(log.Log, entries, nil, &PolicyContext{Policy: policy, JSONContext: ctx})
+	assert.NilError(t, err)
+
+	registries, err := ctx.Query("registries")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, registries, []interface{}{"docker.io"})
+
+	entries = []kyverno.ContextEntry{
+		{Name: "maxReplicas", GlobalReference: &kyverno.GlobalContextEntryReference{Name: "registries", JMESPath: "replicas"}},
+	}
+	err = LoadContext(log.Log, entries, nil, &PolicyContext{Policy: policy, JSONContext: ctx})
+	assert.ErrorContains(t, err, "variable maxReplicas has value 5 of type string, expected number")
+}
+
+func Test_LoadContextLazily(t *testing.T) {
+	resourceRaw := []byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "nginx", "labels": {"tier": "web"}}}`)
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "allowed-tiers"},
+		"spec": {
+			"rules": [
+				{
+					"name": "allowed-tiers",
+					"match": {"resources": {"kinds": ["Pod"]}},
+					"context": [{"name": "tiers", "globalReference": {"name": "tiers", "jmesPath": "items"}}],
+					"preconditions": [{"key": "{{ request.object.metadata.name }}", "operator": "Equals", "value": "nginx"}],
+					"validate": {
+						"message": "the tier is not allowed",
+						"deny": {
+							"conditions": [{"key": "{{ request.object.metadata.labels.tier }}", "operator": "NotIn", "value": "{{ tiers }}"}]
+						}
+					}
+				}
+			]
+		}
+	}`)
+
+	loads := 0
+	SetGlobalContextStore(globalContextStoreFunc(func(name string) ([]byte, error) {
+		loads++
+		return []byte(`{"items": ["web", "db"]}`), nil
+	}))
+	defer SetGlobalContextStore(nil)
+
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+	resource, err := utils.ConvertToUnstructured(resourceRaw)
+	assert.NilError(t, err)
+
+	ctx := context.NewContext()
+	assert.NilError(t, ctx.AddResource(resourceRaw))
+	er := Validate(&PolicyContext{Policy: policy, JSONContext: ctx, NewResource: *resource})
+	assert.Equal(t, len(er.PolicyResponse.Rules), 1)
+	assert.Assert(t, er.PolicyResponse.Rules[0].Success)
+	assert.Equal(t, loads, 1)
+
+	// the entry is not loaded when the preconditions do not pass
+	policy.Spec.Rules[0].AnyAllConditions = []interface{}{map[string]interface{}{"key": "{{ request.object.metadata.name }}", "operator": "Equals", "value": "redis"}}
+	ctx = context.NewContext()
+	assert.NilError(t, ctx.AddResource(resourceRaw))
+	er = Validate(&PolicyContext{Policy: policy, JSONContext: ctx, NewResource: *resource})
+	assert.Equal(t, len(er.PolicyResponse.Rules), 0)
+	assert.Equal(t, loads, 1)
+}
@@ -0,0 +1,96 @@
+package mutate
+
+import (
+	anchor "github.com/kyverno/kyverno/pkg/engine/anchor/common"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+)
+
+// EphemeralContainersSubresource is the pod subresource used to add ephemeral containers
+const EphemeralContainersSubresource = "ephemeralcontainers"
+
+// containerPatterns returns the patterns to apply to the init containers, and to the ephemeral
+// containers if the request is for the ephemeral containers subresource, of the resource.
+// Only the container elements that select existing containers with conditional anchors
+// are applied to the other container lists, elements adding new containers are not.
+func containerPatterns(pattern interface{}, resource map[string]interface{}, ctx context.EvalInterface) []interface{} {
+	lists := []string{"initContainers"}
+	if isEphemeralContainersRequest(ctx) {
+		lists = append(lists, "ephemeralContainers")
+	}
+
+	var patterns []interface{}
+	for _, list := range lists {
+		if p, ok := renameContainers(pattern, resource, list); ok {
+			patterns = append(patterns, p)
+		}
+	}
+
+	return patterns
+}
+
+// renameContainers returns a copy of the pattern where the containers selected with conditional anchors
+// are moved to the given list, the list must be present in the resource
+func renameContainers(pattern interface{}, resource interface{}, list string) (interface{}, bool) {
+	patternMap, ok := pattern.(map[string]interface{})
+	if !ok {
+		return pattern, false
+	}
+
+	resourceMap, _ := resource.(map[string]interface{})
+	renamed := make(map[string]interface{}, len(patternMap))
+	found := false
+	for key, value := range patternMap {
+		if key == "containers" {
+			continue
+		}
+
+		noAnchorKey, _ := anchor.RemoveAnchor(key)
+		element, ok := renameContainers(value, resourceMap[noAnchorKey], list)
+		renamed[key] = element
+		found = found || ok
+	}
+
+	if containers, ok := patternMap["containers"].([]interface{}); ok {
+		selectors := selectingContainers(containers)
+		resourceContainers, _ := resourceMap[list].([]interface{})
+		if _, defined := patternMap[list]; !defined && len(selectors) > 0 && len(resourceContainers) > 0 {
+			renamed[list] = selectors
+			found = true
+		}
+	}
+
+	return renamed, found
+}
+
+// selectingContainers returns the container elements with a conditional anchor
+func selectingContainers(containers []interface{}) []interface{} {
+	var selectors []interface{}
+	for _, container := range containers {
+		containerMap, ok := container.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for key := range containerMap {
+			if anchor.IsConditionAnchor(key) {
+				selectors = append(selectors, container)
+				break
+			}
+		}
+	}
+
+	return selectors
+}
+
+func isEphemeralContainersRequest(ctx context.EvalInterface) bool {
+	if ctx == nil {
+		return false
+	}
+
+	subresource, err := ctx.Query("request.subResource")
+	if err != nil {
+		return false
+	}
+
+	return subresource == EphemeralContainersSubresource
+}
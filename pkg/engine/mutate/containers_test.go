@@ -0,0 +1,121 @@
+package mutate
+
+import (
+	"encoding/json"
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+	"gotest.tools/assert"
+	v1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var containersPattern = []byte(`{
+	"spec": {
+		"containers": [
+			{
+				"(image)": "*:latest",
+				"imagePullPolicy": "Always"
+			},
+			{
+				"name": "sidecar",
+				"image": "sidecar:1.0"
+			}
+		]
+	}
+}`)
+
+var containersResource = []byte(`{
+	"apiVersion": "v1",
+	"kind": "Pod",
+	"metadata": {
+		"name": "debug"
+	},
+	"spec": {
+		"containers": [
+			{
+				"name": "nginx",
+				"image": "nginx:latest"
+			}
+		],
+		"initContainers": [
+			{
+				"name": "init",
+				"image": "busybox:latest"
+			},
+			{
+				"name": "setup",
+				"image": "busybox:1.28"
+			}
+		],
+		"ephemeralContainers": [
+			{
+				"name": "debugger",
+				"image": "busybox:latest"
+			}
+		]
+	}
+}`)
+
+func Test_ContainerMutations(t *testing.T) {
+	testCases := []struct {
+		name        string
+		subresource string
+		ephemeral   interface{}
+	}{
+		{
+			name:      "pod",
+			ephemeral: nil,
+		},
+		{
+			name:        "ephemeral containers subresource",
+			subresource: EphemeralContainersSubresource,
+			ephemeral:   "Always",
+		},
+	}
+
+	for _, test := range testCases {
+		var pattern interface{}
+		assert.NilError(t, json.Unmarshal(containersPattern, &pattern))
+		var resource unstructured.Unstructured
+		assert.NilError(t, resource.UnmarshalJSON(containersResource))
+
+		ctx := context.NewContext()
+		assert.NilError(t, ctx.AddRequest(&v1beta1.AdmissionRequest{SubResource: test.subresource}))
+
+		mutation := &kyverno.Mutation{PatchStrategicMerge: pattern}
+		resp, patchedResource := CreateMutateHandler("set-pull-policy", mutation, resource, ctx, log.Log).Handle()
+		assert.Assert(t, resp.Success, test.name)
+
+		containers, _, err := unstructured.NestedSlice(patchedResource.Object, "spec", "containers")
+		assert.NilError(t, err)
+		assert.Equal(t, len(containers), 2, test.name)
+		for _, c := range containers {
+			container := c.(map[string]interface{})
+			if container["name"] == "nginx" {
+				assert.Equal(t, container["imagePullPolicy"], "Always", test.name)
+			}
+		}
+
+		// only the containers selected by the conditional anchor are mutated
+		initContainers, _, err := unstructured.NestedSlice(patchedResource.Object, "spec", "initContainers")
+		assert.NilError(t, err)
+		assert.Equal(t, len(initContainers), 2, test.name)
+		for _, c := range initContainers {
+			container := c.(map[string]interface{})
+			switch container["name"] {
+			case "init":
+				assert.Equal(t, container["imagePullPolicy"], "Always", test.name)
+			case "setup":
+				assert.Equal(t, container["imagePullPolicy"], nil, test.name)
+			}
+		}
+
+		ephemeralContainers, _, err := unstructured.NestedSlice(patchedResource.Object, "spec", "ephemeralContainers")
+		assert.NilError(t, err)
+		assert.Equal(t, len(ephemeralContainers), 1, test.name)
+		assert.Equal(t, ephemeralContainers[0].(map[string]interface{})["imagePullPolicy"], test.ephemeral, test.name)
+	}
+}
@@ -69,7 +69,31 @@ func (h patchStrategicMergeHandler) Handle() (response.RuleResponse, unstructure
 		return ruleResponse, h.patchedResource
 	}
 
-	return ProcessStrategicMergePatch(h.ruleName, PatchStrategicMerge, h.patchedResource, log)
+	ruleResponse, patchedResource := ProcessStrategicMergePatch(h.ruleName, PatchStrategicMerge, h.patchedResource, log)
+	if !ruleResponse.Success {
+		return ruleResponse, patchedResource
+	}
+
+	// apply the container selectors to the init and ephemeral containers
+	for _, pattern := range containerPatterns(PatchStrategicMerge, patchedResource.Object, h.evalCtx) {
+		containersResponse, containersPatchedResource := ProcessStrategicMergePatch(h.ruleName, pattern, patchedResource, log)
+		if !containersResponse.Success {
+			return containersResponse, h.patchedResource
+		}
+
+		if len(containersResponse.Patches) == 0 {
+			continue
+		}
+
+		if len(ruleResponse.Patches) == 0 {
+			ruleResponse.Message = containersResponse.Message
+		}
+		ruleResponse.Patches = append(ruleResponse.Patches, containersResponse.Patches...)
+		ruleResponse.RuleStats.ProcessingTime += containersResponse.RuleStats.ProcessingTime
+		patchedResource = containersPatchedResource
+	}
+
+	return ruleResponse, patchedResource
 }
 
 // overlayHandler
@@ -131,7 +155,7 @@ func (h patchesJSON6902Handler) Handle() (resp response.RuleResponse, patchedRes
 		return resp, h.patchedResource
 	}
 
-	return ProcessPatchJSON6902(h.ruleName, patchesJSON6902, h.patchedResource, h.logger)
+	return processPatchJSON6902(h.ruleName, patchesJSON6902, h.mutation.MissingPathPolicy, h.patchedResource, h.logger)
 }
 
 func (h overlayHandler) Handle() (response.RuleResponse, unstructured.Unstructured) {
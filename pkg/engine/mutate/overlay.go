@@ -199,6 +199,18 @@ func applyOverlayToMap(resourceMap, overlayMap map[string]interface{}, path stri
 		currentPath := path + noAnchorKey + "/"
 		resourcePart, ok := resourceMap[noAnchorKey]
 
+		// null value removes the field from the resource
+		if value == nil {
+			if ok && !commonAnchors.IsAddingAnchor(key) {
+				patch, err := removeSubtree(currentPath)
+				if err != nil {
+					return nil, err
+				}
+				appliedPatches = append(appliedPatches, patch)
+			}
+			continue
+		}
+
 		if ok && !commonAnchors.IsAddingAnchor(key) {
 			// Key exists - go down through the overlay and resource trees
 			patches, err := applyOverlay(resourcePart, value, currentPath)
@@ -290,15 +302,11 @@ func applyOverlayToArrayOfMaps(resource, overlay []interface{}, path string) ([]
 			appliedPatches = append(appliedPatches, patches...)
 		} else if hasNestedAnchors(overlayElement) {
 			// If we have anchors on the lower level - continue traversing overlay and resource trees
-			for j, resourceElement := range resource {
-				currentPath := path + strconv.Itoa(j) + "/"
-				// currentPath example: /spec/template/spec/containers/3/
-				patches, err := applyOverlay(resourceElement, overlayElement, currentPath)
-				if err != nil {
-					return nil, err
-				}
-				appliedPatches = append(appliedPatches, patches...)
+			patches, err := applyOverlayWithAnchors(resource, overlayElement, path)
+			if err != nil {
+				return nil, err
 			}
+			appliedPatches = append(appliedPatches, patches...)
 		} else {
 			// Overlay subtree has no anchors - insert new element
 			currentPath := path + strconv.Itoa(lastElementIdx+i) + "/"
@@ -314,12 +322,19 @@ func applyOverlayToArrayOfMaps(resource, overlay []interface{}, path string) ([]
 	return appliedPatches, nil
 }
 
+// applyOverlayWithAnchors applies overlay to the resource elements which meet the overlay conditions,
+// the conditions are evaluated per element
 func applyOverlayWithAnchors(resource []interface{}, overlay interface{}, path string) ([][]byte, error) {
 	var appliedPatches [][]byte
 
 	for i, resourceElement := range resource {
 		currentPath := path + strconv.Itoa(i) + "/"
 		// currentPath example: /spec/template/spec/containers/3/
+		if _, err := checkConditions(log.Log, resourceElement, overlay, currentPath); !reflect.DeepEqual(err, overlayError{}) {
+			log.Log.V(4).Info("skip applying overlay to element", "path", currentPath, "reason", err.ErrorMsg())
+			continue
+		}
+
 		patches, err := applyOverlay(resourceElement, overlay, currentPath)
 		if err != nil {
 			return nil, err
@@ -338,6 +353,23 @@ func replaceSubtree(overlay interface{}, path string) ([]byte, error) {
 	return processSubtree(overlay, path, "replace")
 }
 
+func removeSubtree(path string) ([]byte, error) {
+	if len(path) > 1 && path[len(path)-1] == '/' {
+		path = path[:len(path)-1]
+	}
+
+	path = preparePath(path)
+	patchStr := fmt.Sprintf(`{ "op": "remove", "path": "%s" }`, path)
+
+	// check the patch
+	_, err := jsonpatch.DecodePatch([]byte("[" + patchStr + "]"))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to make 'remove' patch for path %s, err: %v", path, err)
+	}
+
+	return []byte(patchStr), nil
+}
+
 func processSubtree(overlay interface{}, path string, op string) ([]byte, error) {
 
 	if len(path) > 1 && path[len(path)-1] == '/' {
@@ -1066,6 +1066,143 @@ func TestProcessOverlayPatches_InsertIfNotPresentWithConditions(t *testing.T) {
 	compareJSONAsMap(t, expectedResult, doc)
 }
 
+func TestProcessOverlayPatches_ConditionPerListElement(t *testing.T) {
+	overlayRaw := []byte(`{
+		"spec": {
+			"containers": [
+				{
+					"(image)": "*:latest",
+					"imagePullPolicy": "Always"
+				}
+			]
+		}
+	}`)
+	resourceRaw := []byte(`{
+		"spec": {
+			"containers": [
+				{
+					"name": "nginx",
+					"image": "nginx:latest"
+				},
+				{
+					"name": "busybox",
+					"image": "busybox:1.28"
+				}
+			]
+		}
+	}`)
+
+	var resource, overlay interface{}
+
+	err := json.Unmarshal(resourceRaw, &resource)
+	assert.NilError(t, err)
+	err = json.Unmarshal(overlayRaw, &overlay)
+	assert.NilError(t, err)
+
+	patches, overlayerr := processOverlayPatches(log.Log, resource, overlay)
+	assert.Assert(t, reflect.DeepEqual(overlayerr, overlayError{}))
+	assert.Equal(t, len(patches), 1)
+
+	doc, err := utils.ApplyPatches(resourceRaw, patches)
+	assert.NilError(t, err)
+	expectedResult := []byte(`{
+		"spec": {
+			"containers": [
+				{
+					"name": "nginx",
+					"image": "nginx:latest",
+					"imagePullPolicy": "Always"
+				},
+				{
+					"name": "busybox",
+					"image": "busybox:1.28"
+				}
+			]
+		}
+	}`)
+
+	compareJSONAsMap(t, expectedResult, doc)
+}
+
+func TestProcessOverlayPatches_RemoveField(t *testing.T) {
+	overlayRaw := []byte(`{
+		"metadata": {
+			"labels": {
+				"debug": null
+			}
+		},
+		"spec": {
+			"hostIPC": null,
+			"hostPID": null,
+			"containers": [
+				{
+					"(name)": "*",
+					"securityContext": {
+						"privileged": null
+					}
+				}
+			]
+		}
+	}`)
+	resourceRaw := []byte(`{
+		"metadata": {
+			"name": "nginx",
+			"labels": {
+				"app": "nginx",
+				"debug": "true"
+			}
+		},
+		"spec": {
+			"hostIPC": true,
+			"containers": [
+				{
+					"name": "nginx",
+					"image": "nginx:latest",
+					"securityContext": {
+						"privileged": true,
+						"runAsNonRoot": true
+					}
+				}
+			]
+		}
+	}`)
+
+	var resource, overlay interface{}
+
+	err := json.Unmarshal(resourceRaw, &resource)
+	assert.NilError(t, err)
+	err = json.Unmarshal(overlayRaw, &overlay)
+	assert.NilError(t, err)
+
+	patches, overlayerr := processOverlayPatches(log.Log, resource, overlay)
+	assert.Assert(t, reflect.DeepEqual(overlayerr, overlayError{}))
+	assert.Equal(t, len(patches), 3)
+
+	doc, err := utils.ApplyPatches(resourceRaw, patches)
+	assert.NilError(t, err)
+	expectedResult := []byte(`{
+		"metadata": {
+			"name": "nginx",
+			"labels": {
+				"app": "nginx"
+			}
+		},
+		"spec": {
+			"containers": [
+				{
+					"name": "nginx",
+					"image": "nginx:latest",
+					"securityContext": {
+						"runAsNonRoot": true
+					}
+				}
+			]
+		}
+	}`)
+
+	compareJSONAsMap(t, expectedResult, doc)
+}
+
 func Test_wrapBoolean(t *testing.T) {
 	tests := []struct {
 		test     string
@@ -1,11 +1,15 @@
 package mutate
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/engine/response"
 	"github.com/kyverno/kyverno/pkg/engine/utils"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -14,6 +18,10 @@ import (
 
 // ProcessPatchJSON6902 ...
 func ProcessPatchJSON6902(ruleName string, patchesJSON6902 []byte, resource unstructured.Unstructured, log logr.Logger) (resp response.RuleResponse, patchedResource unstructured.Unstructured) {
+	return processPatchJSON6902(ruleName, patchesJSON6902, kyverno.MissingPathCreate, resource, log)
+}
+
+func processPatchJSON6902(ruleName string, patchesJSON6902 []byte, missingPathPolicy kyverno.MissingPathPolicy, resource unstructured.Unstructured, log logr.Logger) (resp response.RuleResponse, patchedResource unstructured.Unstructured) {
 	logger := log.WithValues("rule", ruleName)
 	startTime := time.Now()
 	logger.V(4).Info("started JSON6902 patch", "startTime", startTime)
@@ -32,6 +40,14 @@ func ProcessPatchJSON6902(ruleName string, patchesJSON6902 []byte, resource unst
 		return resp, resource
 	}
 
+	patchesJSON6902, err = validatePatchPaths(patchesJSON6902, resourceRaw, missingPathPolicy, logger)
+	if err != nil {
+		resp.Success = false
+		logger.Error(err, "invalid RFC 6902 patches")
+		resp.Message = fmt.Sprintf("invalid RFC 6902 patches: %v", err)
+		return resp, resource
+	}
+
 	patchedResourceRaw, err := applyPatchesWithOptions(resourceRaw, patchesJSON6902)
 	if err != nil {
 		resp.Success = false
@@ -72,8 +88,7 @@ func applyPatchesWithOptions(resource, patch []byte) ([]byte, error) {
 		return resource, fmt.Errorf("failed to decode patches: %v", err)
 	}
 
-	options := &jsonpatch.ApplyOptions{SupportNegativeIndices: true, AllowMissingPathOnRemove: true, EnsurePathExistsOnAdd: true}
-	patchedResource, err := patches.ApplyWithOptions(resource, options)
+	patchedResource, err := patches.ApplyWithOptions(resource, patchOptions())
 	if err != nil {
 		return resource, err
 	}
@@ -81,6 +96,112 @@ func applyPatchesWithOptions(resource, patch []byte) ([]byte, error) {
 	return patchedResource, nil
 }
 
+func patchOptions() *jsonpatch.ApplyOptions {
+	return &jsonpatch.ApplyOptions{SupportNegativeIndices: true, AllowMissingPathOnRemove: true, EnsurePathExistsOnAdd: true}
+}
+
+// validatePatchPaths checks the paths of the add and replace operations against the resource,
+// and returns the patches to apply according to the missing path policy.
+// The operations are checked in order so that a path added by a previous operation exists.
+func validatePatchPaths(patchesJSON6902, resource []byte, missingPathPolicy kyverno.MissingPathPolicy, log logr.Logger) ([]byte, error) {
+	if missingPathPolicy == "" || missingPathPolicy == kyverno.MissingPathCreate {
+		return patchesJSON6902, nil
+	}
+
+	patches, err := jsonpatch.DecodePatch(patchesJSON6902)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode patches: %v", err)
+	}
+
+	validPatches := jsonpatch.Patch{}
+	doc := resource
+	for _, patch := range patches {
+		if kind := patch.Kind(); kind == "add" || kind == "replace" {
+			path, err := patch.Path()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get path in JSON Patch: %v", err)
+			}
+
+			// add operations require the parent, replace operations require the target
+			target := path
+			if kind == "add" {
+				target = parentPath(path)
+			}
+
+			exists, err := pathExists(doc, target)
+			if err != nil {
+				return nil, err
+			}
+
+			if !exists {
+				if missingPathPolicy == kyverno.MissingPathFail {
+					return nil, fmt.Errorf("path %s of %s operation does not exist in the resource", target, kind)
+				}
+
+				log.V(4).Info("skipping JSON patch with a missing path", "op", kind, "path", path)
+				continue
+			}
+		}
+
+		if doc, err = (jsonpatch.Patch{patch}).ApplyWithOptions(doc, patchOptions()); err != nil {
+			return nil, err
+		}
+
+		validPatches = append(validPatches, patch)
+	}
+
+	return json.Marshal(validPatches)
+}
+
+// parentPath returns the JSON pointer of the parent, e.g. "/spec" for "/spec/containers"
+func parentPath(path string) string {
+	if i := strings.LastIndex(path, "/"); i > 0 {
+		return path[:i]
+	}
+
+	return ""
+}
+
+// pathExists checks if the JSON pointer references a value in the document
+func pathExists(doc []byte, path string) (bool, error) {
+	var obj interface{}
+	if err := json.Unmarshal(doc, &obj); err != nil {
+		return false, fmt.Errorf("failed to unmarshal resource: %v", err)
+	}
+
+	if path == "" {
+		return true, nil
+	}
+
+	for _, key := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		key = strings.ReplaceAll(strings.ReplaceAll(key, "~1", "/"), "~0", "~")
+		switch typed := obj.(type) {
+		case map[string]interface{}:
+			value, ok := typed[key]
+			if !ok {
+				return false, nil
+			}
+			obj = value
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil {
+				return false, nil
+			}
+			if index < 0 {
+				index += len(typed)
+			}
+			if index < 0 || index >= len(typed) {
+				return false, nil
+			}
+			obj = typed[index]
+		default:
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func convertPatchesToJSON(patchesJSON6902 string) ([]byte, error) {
 	if len(patchesJSON6902) == 0 {
 		return []byte(patchesJSON6902), nil
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/ghodss/yaml"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	assert "github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -375,3 +376,73 @@ spec:
 		}
 	}
 }
+
+func Test_MissingPathPolicy(t *testing.T) {
+	resource := []byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+spec:
+  containers:
+  - name: nginx
+    image: nginx:1.14.2
+`)
+
+	patches := []byte(`
+- path: "/spec/template/spec/nodeSelector"
+  op: add
+  value: {"node.kubernetes.io/role": "test"}
+- path: "/spec/tolerations"
+  op: replace
+  value: []
+- path: "/spec/containers/0/image"
+  op: replace
+  value: nginx:1.20
+- path: "/spec/containers/0/imagePullPolicy"
+  op: add
+  value: Always
+`)
+
+	tests := []struct {
+		name              string
+		missingPathPolicy kyverno.MissingPathPolicy
+		success           bool
+		expectedPatches   map[string]bool
+	}{
+		{
+			name:              "skip",
+			missingPathPolicy: kyverno.MissingPathSkip,
+			success:           true,
+			expectedPatches: map[string]bool{
+				`{"op":"replace","path":"/spec/containers/0/image","value":"nginx:1.20"}`:   true,
+				`{"op":"add","path":"/spec/containers/0/imagePullPolicy","value":"Always"}`: true,
+			},
+		},
+		{
+			name:              "fail",
+			missingPathPolicy: kyverno.MissingPathFail,
+			success:           false,
+		},
+	}
+
+	r, err := yaml.YAMLToJSON(resource)
+	assert.Nil(t, err)
+
+	var u unstructured.Unstructured
+	err = u.UnmarshalJSON(r)
+	assert.Nil(t, err)
+
+	p, err := yaml.YAMLToJSON(patches)
+	assert.Nil(t, err)
+
+	for _, test := range tests {
+		resp, _ := processPatchJSON6902(test.name, p, test.missingPathPolicy, u, log.Log)
+		assert.Equal(t, test.success, resp.Success, fmt.Sprintf("test: %s\nmessage: %s", test.name, resp.Message))
+		assert.Equal(t, len(test.expectedPatches), len(resp.Patches), test.name)
+		for _, patch := range resp.Patches {
+			assert.Equal(t, test.expectedPatches[string(patch)], true,
+				fmt.Sprintf("test: %s\nunexpected patch: %s", test.name, string(patch)))
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/engine/response"
@@ -60,6 +61,11 @@ func ProcessPatches(log logr.Logger, ruleName string, mutation kyverno.Mutation,
 			errs = append(errs, err)
 			continue
 		}
+		// skip the patch if the resource is already in the desired state
+		if jsonpatch.Equal(resourceRaw, patchResource) {
+			logger.V(4).Info("skip JSON patch, resource is unchanged", "path", patch.Path)
+			continue
+		}
 		resourceRaw = patchResource
 		patches = append(patches, patchRaw)
 	}
@@ -162,6 +162,25 @@ func TestProcessPatches_RemovePathDoesntExist_NotEmptyResult(t *testing.T) {
 	assertEqStringAndData(t, `{"path":"/metadata/labels/label2","op":"add","value":"label2Value"}`, rr.Patches[0])
 }
 
+func TestProcessPatches_ResourceUnchanged_EmptyResult(t *testing.T) {
+	patch1 := types.Patch{Path: "/metadata/labels/originalLabel", Operation: "replace", Value: "isHere"}
+	patch2 := types.Patch{Path: "/metadata/labels/label2", Operation: "add", Value: "label2Value"}
+	rule := makeRuleWithPatches([]types.Patch{patch1, patch2})
+	resourceUnstructured, err := utils.ConvertToUnstructured([]byte(endpointsDocument))
+	if err != nil {
+		t.Error(err)
+	}
+	rr, patchedResource := ProcessPatches(log.Log, rule.Name, rule.Mutation, *resourceUnstructured)
+	assert.Check(t, rr.Success)
+	assert.Assert(t, len(rr.Patches) == 1)
+	assertEqStringAndData(t, `{"path":"/metadata/labels/label2","op":"add","value":"label2Value"}`, rr.Patches[0])
+
+	// applying the patches again results in no patches
+	rr, _ = ProcessPatches(log.Log, rule.Name, rule.Mutation, patchedResource)
+	assert.Check(t, rr.Success)
+	assert.Assert(t, len(rr.Patches) == 0)
+}
+
 func assertEqDataImpl(t *testing.T, expected, actual []byte, formatModifier string) {
 	if len(expected) != len(actual) {
 		t.Errorf("len(expected) != len(actual): %d != %d\n1:"+formatModifier+"\n2:"+formatModifier, len(expected), len(actual), expected, actual)
@@ -1,8 +1,12 @@
 package mutate
 
 import (
+	"encoding/json"
+	"reflect"
+
 	anchor "github.com/kyverno/kyverno/pkg/engine/anchor/common"
 	"github.com/minio/minio/pkg/wildcard"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	yaml "sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
@@ -317,7 +321,7 @@ func processAnchorSequence(pattern, resource, arrayPattern *yaml.RNode) error {
 
 // processAnchorMap - process arrays
 // in many cases like containers, volumes kustomize uses name field to match resource for processing
-// 1> If any conditional anchor match resource field and if the pattern doesn't contains "name" field and
+// 1> If all the conditional anchors match the resource element and if the pattern doesn't contains "name" field and
 // 		resource contains "name" field then copy the name field from resource to pattern.
 // 2> If the resource doesn't contains "name" field then just remove anchor field from yaml.
 /*
@@ -356,75 +360,93 @@ func processAnchorMap(pattern, resource, arrayPattern *yaml.RNode) error {
 	if err != nil {
 		return err
 	}
+
+	var conditions []string
 	for _, key := range sfields {
 		if anchor.IsConditionAnchor(key) {
-			_, efields, err := getAnchorSortedFields(resource)
-			if err != nil {
-				return err
-			}
-			noAnchorKey := removeAnchor(key)
-			eind := getIndex("name", efields)
-			if eind != -1 && getIndex("name", fields) == -1 {
-				patternMapNode := pattern.Field(key)
-				resourceMapNode := resource.Field(noAnchorKey)
-				if resourceMapNode != nil {
-					pval, err := patternMapNode.Value.String()
-					if err != nil {
-						return err
-					}
-					eval, err := resourceMapNode.Value.String()
-					if err != nil {
-						return err
-					}
-					if wildcard.Match(pval, eval) {
-						newNodeString, err := pattern.String()
-						if err != nil {
-							return err
-						}
-						newNode, err := yaml.Parse(newNodeString)
-						if err != nil {
-							return err
-						}
-						for i, ekey := range efields {
-							if ekey == noAnchorKey {
-								pind := getIndex(key, fields)
-								if pind == -1 {
-									continue
-								}
-								removeAnchorNode(newNode, pind)
-								sfields = removeKeyFromFields(key, sfields)
-								fields = removeKeyFromFields(key, fields)
-
-								if ekey == "name" {
-									newNode.YNode().Content = append(newNode.YNode().Content, resource.YNode().Content[2*i])
-									newNode.YNode().Content = append(newNode.YNode().Content, resource.YNode().Content[2*i+1])
-								}
-
-							} else if ekey == "name" {
-								newNode.YNode().Content = append(newNode.YNode().Content, resource.YNode().Content[2*i])
-								newNode.YNode().Content = append(newNode.YNode().Content, resource.YNode().Content[2*i+1])
-							}
-						}
-						arrayPattern.YNode().Content = append(arrayPattern.YNode().Content, newNode.YNode())
-					}
-				}
+			conditions = append(conditions, key)
+		}
+	}
 
-			} else {
-				ind := getIndex(key, fields)
-				if ind == -1 {
-					continue
-				}
-				removeAnchorNode(pattern, ind)
-				sfields = removeKeyFromFields(key, sfields)
-				fields = removeKeyFromFields(key, fields)
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	_, efields, err := getAnchorSortedFields(resource)
+	if err != nil {
+		return err
+	}
 
+	eind := getIndex("name", efields)
+	if eind == -1 || getIndex("name", fields) != -1 {
+		for _, key := range conditions {
+			ind := getIndex(key, fields)
+			if ind == -1 {
+				continue
 			}
+			removeAnchorNode(pattern, ind)
+			fields = removeKeyFromFields(key, fields)
+		}
+		return nil
+	}
+
+	// all the conditions of the pattern must be met by the resource element
+	met, err := conditionsMet(pattern, resource)
+	if err != nil || !met {
+		return err
+	}
+
+	newNodeString, err := pattern.String()
+	if err != nil {
+		return err
+	}
+	newNode, err := yaml.Parse(newNodeString)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range conditions {
+		pind := getIndex(key, fields)
+		if pind == -1 {
 			continue
 		}
+		removeAnchorNode(newNode, pind)
+		fields = removeKeyFromFields(key, fields)
 	}
+
+	newNode.YNode().Content = append(newNode.YNode().Content, resource.YNode().Content[eind], resource.YNode().Content[eind+1])
+	arrayPattern.YNode().Content = append(arrayPattern.YNode().Content, newNode.YNode())
 	return nil
 }
 
+// conditionsMet checks the condition anchors of the pattern against the resource, the nested
+// conditions are checked as well
+func conditionsMet(pattern, resource *yaml.RNode) (bool, error) {
+	patternValue, err := toInterface(pattern)
+	if err != nil {
+		return false, err
+	}
+
+	resourceValue, err := toInterface(resource)
+	if err != nil {
+		return false, err
+	}
+
+	_, overlayerr := checkConditions(log.Log, resourceValue, patternValue, "/")
+	return reflect.DeepEqual(overlayerr, overlayError{}), nil
+}
+
+func toInterface(node *yaml.RNode) (interface{}, error) {
+	data, err := node.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	err = json.Unmarshal(data, &value)
+	return value, err
+}
+
 func processNonAssocSequence(pattern, resource *yaml.RNode) error {
 	pafs, err := pattern.Elements()
 	if err != nil {
@@ -168,12 +168,12 @@ func Test_preProcessStrategicMergePatch_multipleAnchors(t *testing.T) {
 		{
 			rawPolicy:   []byte(`{"spec": {"containers": [{"(name)": "*","(image)": "gcr.io/google-containers/busybox:latest"}],"imagePullSecrets": [{"name": "regcred"}]}}`),
 			rawResource: []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "hello"},"spec": {"containers": [{"name": "hello","image": "gcr.io/google-containers/busybox:latest"}]}}`),
-			expected:    []byte(`{"spec":{"containers":[],"imagePullSecrets":[{"name":"regcred"}]}}`),
+			expected:    []byte(`{"spec":{"containers":[{"name":"hello"}],"imagePullSecrets":[{"name":"regcred"}]}}`),
 		},
 		{
 			rawPolicy:   []byte(`{"spec": {"containers": [{"(name)": "*","(image)": "gcr.io/google-containers/busybox:*"}],"imagePullSecrets": [{"name": "regcred"}]}}`),
 			rawResource: []byte(`{"apiVersion": "v1","kind": "Pod","metadata": {"name": "hello2"},"spec": {"containers": [{"name": "hello","image": "gcr.io/google-containers/busybox:latest"}]}}`),
-			expected:    []byte(`{"spec":{"containers":[],"imagePullSecrets":[{"name":"regcred"}]}}`),
+			expected:    []byte(`{"spec":{"containers":[{"name":"hello"}],"imagePullSecrets":[{"name":"regcred"}]}}`),
 		},
 		{
 			rawPolicy:   []byte(`{"spec": {"containers": [{"(image)": "gcr.io/google-containers/busybox:latest"}],"imagePullSecrets": [{"name": "regcred"}]}}`),
@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/mutate"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/engine/variables"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// TargetGetter fetches the target resource of a mutate existing rule
+type TargetGetter func(target kyverno.ResourceSpec) (*unstructured.Unstructured, error)
+
+// MutateExisting applies the mutate rules with targets to the existing target resources.
+// The policy context holds the trigger resource, a response is returned for each target.
+func MutateExisting(policyContext *PolicyContext, getTarget TargetGetter) (responses []*response.EngineResponse) {
+	startTime := time.Now()
+	policy := policyContext.Policy
+	trigger := policyContext.NewResource
+	ctx := policyContext.JSONContext
+
+	logger := log.Log.WithName("EngineMutateExisting").WithValues("policy", policy.Name, "kind", trigger.GetKind(),
+		"namespace", trigger.GetNamespace(), "name", trigger.GetName())
+
+	logger.V(4).Info("start policy processing", "startTime", startTime)
+	defer func() {
+		logger.V(5).Info("finished processing policy", "processingTime", time.Since(startTime).String(), "responses", len(responses))
+	}()
+
+	policyContext.JSONContext.Checkpoint()
+	defer policyContext.JSONContext.Restore()
+
+	for _, rule := range policy.Spec.Rules {
+		logger := logger.WithValues("rule", rule.Name)
+		if !rule.HasMutateExisting() {
+			continue
+		}
+
+		if err := MatchesResourceDescription(trigger, rule, policyContext.AdmissionInfo, policyContext.ExcludeGroupRole, policyContext.NamespaceLabels); err != nil {
+			logger.V(4).Info("rule not matched", "reason", err.Error())
+			continue
+		}
+
+		policyContext.JSONContext.Restore()
+		if err := LoadContextLazily(logger, rule.Context, policyContext.ResourceCache, policyContext); err != nil {
+			logger.Error(err, "failed to load context")
+			continue
+		}
+
+		copyConditions, err := copyConditions(rule.AnyAllConditions)
+		if err != nil {
+			logger.V(2).Info("failed to load context", "reason", err.Error())
+			continue
+		}
+
+		if !variables.EvaluateConditions(logger, ctx, copyConditions) {
+			logger.V(3).Info("resource fails the preconditions")
+			continue
+		}
+
+		for _, targetSpec := range rule.Mutation.Targets {
+			if resp := mutateTarget(logger, policyContext, rule, targetSpec, getTarget); resp != nil {
+				responses = append(responses, resp)
+			}
+		}
+	}
+
+	return responses
+}
+
+// mutateTarget resolves the target resource and applies the rule mutation to it.
+// It returns nil if the mutation results in no patches.
+func mutateTarget(logger logr.Logger, policyContext *PolicyContext, rule kyverno.Rule, targetSpec kyverno.ResourceSpec, getTarget TargetGetter) *response.EngineResponse {
+	startTime := time.Now()
+	resp := &response.EngineResponse{}
+	startMutateResultResponse(resp, policyContext.Policy, unstructured.Unstructured{})
+	defer func() {
+		resp.PolicyResponse.ProcessingTime = time.Since(startTime)
+	}()
+
+	ruleResp := response.RuleResponse{
+		Name: rule.Name,
+		Type: utils.Mutation.String(),
+	}
+
+	target, err := resolveTarget(logger, policyContext, targetSpec, getTarget)
+	if err != nil {
+		ruleResp.Success = false
+		ruleResp.Message = err.Error()
+		resp.PolicyResponse.Resource = response.ResourceSpec{Kind: targetSpec.Kind, APIVersion: targetSpec.APIVersion, Namespace: targetSpec.Namespace, Name: targetSpec.Name}
+		resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, ruleResp)
+		return resp
+	}
+
+	startMutateResultResponse(resp, policyContext.Policy, *target)
+	resp.PatchedResource = *target
+
+	if err := policyContext.JSONContext.AddTarget(target.Object); err != nil {
+		ruleResp.Success = false
+		ruleResp.Message = fmt.Sprintf("failed to add target to the context: %v", err)
+		resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, ruleResp)
+		return resp
+	}
+
+	mutation := rule.Mutation.DeepCopy()
+	mutation.Targets = nil
+	if mutation.ForEachMutation != nil {
+		rule.Mutation = *mutation
+		ruleResp, resp.PatchedResource = mutateForEach(logger, policyContext.JSONContext, rule, *target)
+	} else {
+		mutateHandler := mutate.CreateMutateHandler(rule.Name, mutation, *target, policyContext.JSONContext, logger)
+		ruleResp, resp.PatchedResource = mutateHandler.Handle()
+	}
+
+	if ruleResp.Success && ruleResp.Patches != nil {
+		if field, ok := immutableFieldPatched(ruleResp.Patches, immutableFields(policyContext, target.GetKind())); ok {
+			logger.V(3).Info("mutation of immutable field is not allowed", "field", field, "kind", target.GetKind(), "namespace", target.GetNamespace(), "name", target.GetName())
+			failImmutableFieldMutation(&ruleResp, field)
+			resp.PatchedResource = *target
+		}
+	}
+
+	if ruleResp.Success && ruleResp.Patches == nil {
+		logger.V(4).Info("no patches for the target", "kind", target.GetKind(), "namespace", target.GetNamespace(), "name", target.GetName())
+		return nil
+	}
+
+	resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, ruleResp)
+	incrementAppliedRuleCount(resp)
+	return resp
+}
+
+// resolveTarget substitutes the variables in the target declaration and fetches the target resource
+func resolveTarget(logger logr.Logger, policyContext *PolicyContext, targetSpec kyverno.ResourceSpec, getTarget TargetGetter) (*unstructured.Unstructured, error) {
+	fields := []*string{&targetSpec.APIVersion, &targetSpec.Kind, &targetSpec.Namespace, &targetSpec.Name}
+	for _, field := range fields {
+		value, err := variables.SubstituteVars(logger, policyContext.JSONContext, *field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to substitute variables in target %s/%s/%s: %v", targetSpec.Kind, targetSpec.Namespace, targetSpec.Name, err)
+		}
+
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("target field %v must be a string, found %T", *field, value)
+		}
+		*field = str
+	}
+
+	target, err := getTarget(targetSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target %s/%s/%s: %v", targetSpec.Kind, targetSpec.Namespace, targetSpec.Name, err)
+	}
+
+	return target, nil
+}
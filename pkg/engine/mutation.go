@@ -1,12 +1,15 @@
 package engine
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/engine/mutate"
 	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
 	"github.com/kyverno/kyverno/pkg/engine/variables"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -47,6 +50,8 @@ func Mutate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 	policyContext.JSONContext.Checkpoint()
 	defer policyContext.JSONContext.Restore()
 
+	// rules are applied in declaration order, each rule mutates the resource patched by the previous rules
+	mutated := false
 	for _, rule := range policy.Spec.Rules {
 		var ruleResponse response.RuleResponse
 		logger := logger.WithValues("rule", rule.Name)
@@ -54,6 +59,11 @@ func Mutate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 			continue
 		}
 
+		// rules with targets mutate existing resources in the background
+		if rule.HasMutateExisting() {
+			continue
+		}
+
 		// check if the resource satisfies the filter conditions defined in the rule
 		//TODO: this needs to be extracted, to filter the resource so that we can avoid passing resources that
 		// dont satisfy a policy rule resource description
@@ -70,7 +80,14 @@ func Mutate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 		logger.V(3).Info("matched mutate rule")
 
 		policyContext.JSONContext.Restore()
-		if err := LoadContext(logger, rule.Context, resCache, policyContext); err != nil {
+		if mutated {
+			if err := ctx.ReplaceResource(patchedResource); err != nil {
+				logger.Error(err, "failed to update the resource in context")
+				continue
+			}
+		}
+
+		if err := LoadContextLazily(logger, rule.Context, resCache, policyContext); err != nil {
 			logger.Error(err, "failed to load context")
 			continue
 		}
@@ -83,14 +100,35 @@ func Mutate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 		}
 		// evaluate pre-conditions
 		// - handle variable substitutions
-		if !variables.EvaluateConditions(logger, ctx, copyConditions) {
+		passed, err := evaluateConditions(logger, ctx, copyConditions, policy.StrictVariablesEnabled())
+		if err != nil {
+			logger.V(3).Info("failed to substitute variables in the preconditions", "reason", err.Error())
+			resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, variableErrorResponse(rule, utils.Mutation, err))
+			continue
+		}
+
+		if !passed {
 			logger.V(3).Info("resource fails the preconditions")
 			continue
 		}
 
-		mutation := rule.Mutation.DeepCopy()
-		mutateHandler := mutate.CreateMutateHandler(rule.Name, mutation, patchedResource, ctx, logger)
-		ruleResponse, patchedResource = mutateHandler.Handle()
+		resource := patchedResource
+		if rule.Mutation.ForEachMutation != nil {
+			ruleResponse, patchedResource = mutateForEach(logger, ctx, rule, patchedResource)
+		} else {
+			mutation := rule.Mutation.DeepCopy()
+			mutateHandler := mutate.CreateMutateHandler(rule.Name, mutation, patchedResource, ctx, logger)
+			ruleResponse, patchedResource = mutateHandler.Handle()
+		}
+
+		if ruleResponse.Success && ruleResponse.Patches != nil {
+			if field, ok := immutableFieldPatched(ruleResponse.Patches, immutableFields(policyContext, resource.GetKind())); ok {
+				logger.V(3).Info("mutation of immutable field is not allowed", "field", field)
+				failImmutableFieldMutation(&ruleResponse, field)
+				patchedResource = resource
+			}
+		}
+
 		if ruleResponse.Success {
 			// - overlay pattern does not match the resource conditions
 			if ruleResponse.Patches == nil {
@@ -98,6 +136,7 @@ func Mutate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 			}
 
 			logger.V(4).Info("mutate rule applied successfully", "ruleName", rule.Name)
+			mutated = true
 		}
 
 		resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, ruleResponse)
@@ -108,6 +147,64 @@ func Mutate(policyContext *PolicyContext) (resp *response.EngineResponse) {
 	return resp
 }
 
+// mutateForEach applies the foreach mutation for each element of the selected list.
+// The patches of the elements are applied in order, the rule fails on the first element which fails.
+func mutateForEach(logger logr.Logger, ctx *context.Context, rule kyverno.Rule, resource unstructured.Unstructured) (response.RuleResponse, unstructured.Unstructured) {
+	startTime := time.Now()
+	foreach := rule.Mutation.ForEachMutation
+	resp := response.RuleResponse{
+		Name: rule.Name,
+		Type: utils.Mutation.String(),
+	}
+	defer func() {
+		resp.RuleStats.ProcessingTime = time.Since(startTime)
+	}()
+
+	elements, err := evaluateList(foreach.List, ctx)
+	if err != nil {
+		resp.Success = false
+		resp.Message = fmt.Sprintf("failed to evaluate list %s: %v", foreach.List, err)
+		return resp, resource
+	}
+
+	patchedResource := resource
+	for index, element := range elements {
+		if err := ctx.AddElement(element, index); err != nil {
+			resp.Success = false
+			resp.Message = fmt.Sprintf("failed to add element %d to the context: %v", index, err)
+			return resp, resource
+		}
+
+		mutation := foreach.ElementMutation(rule.Mutation.MissingPathPolicy)
+		if mutation.PatchesJSON6902 != "" {
+			patchesJSON6902, err := variables.SubstituteVars(logger, ctx, mutation.PatchesJSON6902)
+			if err != nil {
+				resp.Success = false
+				resp.Message = fmt.Sprintf("failed to substitute variables in patchesJson6902 for %s[%d]: %v", foreach.List, index, err)
+				return resp, resource
+			}
+
+			mutation.PatchesJSON6902 = patchesJSON6902.(string)
+		}
+
+		mutateHandler := mutate.CreateMutateHandler(rule.Name, mutation, patchedResource, ctx, logger)
+		elementResp, elementPatchedResource := mutateHandler.Handle()
+		if !elementResp.Success {
+			logger.V(3).Info("mutation failed for element", "list", foreach.List, "index", index)
+			resp.Success = false
+			resp.Message = fmt.Sprintf("mutation failure for %s[%d]: %s", foreach.List, index, elementResp.Message)
+			return resp, resource
+		}
+
+		patchedResource = elementPatchedResource
+		resp.Patches = append(resp.Patches, elementResp.Patches...)
+	}
+
+	resp.Success = true
+	resp.Message = fmt.Sprintf("mutation rule '%s' applied for %d elements of %s.", rule.Name, len(elements), foreach.List)
+	return resp, patchedResource
+}
+
 func incrementAppliedRuleCount(resp *response.EngineResponse) {
 	resp.PolicyResponse.RulesAppliedCount++
 }
@@ -118,6 +215,7 @@ func startMutateResultResponse(resp *response.EngineResponse, policy kyverno.Clu
 	}
 
 	resp.PolicyResponse.Policy = policy.Name
+	resp.PolicyResponse.Namespace = policy.Namespace
 	resp.PolicyResponse.Resource.Name = resource.GetName()
 	resp.PolicyResponse.Resource.Namespace = resource.GetNamespace()
 	resp.PolicyResponse.Resource.Kind = resource.GetKind()
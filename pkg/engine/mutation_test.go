@@ -2,11 +2,15 @@ package engine
 
 import (
 	"encoding/json"
+	"fmt"
 
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/engine/response"
 	"github.com/kyverno/kyverno/pkg/engine/utils"
 	"gotest.tools/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"reflect"
 	"testing"
 )
@@ -164,3 +168,682 @@ func Test_variableSubstitutionPathNotExist(t *testing.T) {
 	t.Log(er.PolicyResponse.Rules[0].Message)
 	assert.Equal(t, er.PolicyResponse.Rules[0].Message, expectedErrorStr)
 }
+
+func Test_ForEachMutation(t *testing.T) {
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+			"name": "mutate-containers"
+		},
+		"spec": {
+			"rules": [
+				{
+					"name": "set-pull-policy",
+					"match": {
+						"resources": {
+							"kinds": [
+								"Pod"
+							]
+						}
+					},
+					"mutate": {
+						"foreach": {
+							"list": "request.object.spec.containers",
+							"patchStrategicMerge": {
+								"spec": {
+									"containers": [
+										{
+											"name": "{{ element.name }}",
+											"imagePullPolicy": "Always"
+										}
+									]
+								}
+							}
+						}
+					}
+				},
+				{
+					"name": "rewrite-registry",
+					"match": {
+						"resources": {
+							"kinds": [
+								"Pod"
+							]
+						}
+					},
+					"mutate": {
+						"foreach": {
+							"list": "request.object.spec.containers",
+							"patchesJson6902": "- op: replace\n  path: /spec/containers/{{elementIndex}}/image\n  value: registry.io/{{element.image}}"
+						}
+					}
+				}
+			]
+		}
+	}`)
+
+	resourceRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {
+			"name": "nginx"
+		},
+		"spec": {
+			"containers": [
+				{
+					"name": "nginx",
+					"image": "nginx:1.20"
+				},
+				{
+					"name": "busybox",
+					"image": "busybox:1.28"
+				}
+			]
+		}
+	}`)
+
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+	resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+	assert.NilError(t, err)
+	ctx := context.NewContext()
+	assert.NilError(t, ctx.AddResource(resourceRaw))
+
+	policyContext := &PolicyContext{
+		Policy:      policy,
+		JSONContext: ctx,
+		NewResource: *resourceUnstructured}
+	er := Mutate(policyContext)
+
+	assert.Equal(t, len(er.PolicyResponse.Rules), 2)
+	for _, r := range er.PolicyResponse.Rules {
+		assert.Assert(t, r.Success, r.Message)
+	}
+
+	containers, _, err := unstructured.NestedSlice(er.PatchedResource.Object, "spec", "containers")
+	assert.NilError(t, err)
+	assert.Equal(t, len(containers), 2)
+	// the rewrite-registry rule selects the containers of the resource patched by the set-pull-policy rule
+	images := map[string]string{"nginx": "registry.io/nginx:1.20", "busybox": "registry.io/busybox:1.28"}
+	for _, c := range containers {
+		container := c.(map[string]interface{})
+		assert.Equal(t, container["image"], images[container["name"].(string)])
+		assert.Equal(t, container["imagePullPolicy"], "Always")
+	}
+}
+
+func Test_MutateExisting(t *testing.T) {
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+			"name": "mutate-existing-secret"
+		},
+		"spec": {
+			"rules": [
+				{
+					"name": "label-secret",
+					"match": {
+						"resources": {
+							"kinds": [
+								"ConfigMap"
+							]
+						}
+					},
+					"mutate": {
+						"targets": [
+							{
+								"apiVersion": "v1",
+								"kind": "Secret",
+								"namespace": "{{request.object.metadata.namespace}}",
+								"name": "{{request.object.metadata.name}}-secret"
+							}
+						],
+						"patchStrategicMerge": {
+							"metadata": {
+								"labels": {
+									"config": "{{request.object.metadata.name}}",
+									"secret": "{{target.metadata.name}}"
+								}
+							}
+						}
+					}
+				}
+			]
+		}
+	}`)
+
+	triggerRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "ConfigMap",
+		"metadata": {
+			"name": "app",
+			"namespace": "default"
+		}
+	}`)
+
+	targetRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "Secret",
+		"metadata": {
+			"name": "app-secret",
+			"namespace": "default"
+		}
+	}`)
+
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+	trigger, err := utils.ConvertToUnstructured(triggerRaw)
+	assert.NilError(t, err)
+	target, err := utils.ConvertToUnstructured(targetRaw)
+	assert.NilError(t, err)
+	ctx := context.NewContext()
+	assert.NilError(t, ctx.AddResource(triggerRaw))
+
+	policyContext := &PolicyContext{
+		Policy:      policy,
+		JSONContext: ctx,
+		NewResource: *trigger}
+
+	// the rule must not be applied to the admission request
+	er := Mutate(policyContext)
+	assert.Equal(t, len(er.PolicyResponse.Rules), 0)
+
+	var requested kyverno.ResourceSpec
+	getTarget := func(spec kyverno.ResourceSpec) (*unstructured.Unstructured, error) {
+		requested = spec
+		return target.DeepCopy(), nil
+	}
+
+	responses := MutateExisting(policyContext, getTarget)
+	assert.Equal(t, len(responses), 1)
+	assert.Equal(t, requested, kyverno.ResourceSpec{APIVersion: "v1", Kind: "Secret", Namespace: "default", Name: "app-secret"})
+	assert.Equal(t, len(responses[0].PolicyResponse.Rules), 1)
+	assert.Assert(t, responses[0].PolicyResponse.Rules[0].Success, responses[0].PolicyResponse.Rules[0].Message)
+	assert.DeepEqual(t, responses[0].PatchedResource.GetLabels(), map[string]string{"config": "app", "secret": "app-secret"})
+}
+
+func Test_MutationIdempotent(t *testing.T) {
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+			"name": "idempotent"
+		},
+		"spec": {
+			"rules": [
+				{
+					"name": "add-label",
+					"match": {
+						"resources": {
+							"kinds": [
+								"Pod"
+							]
+						}
+					},
+					"mutate": {
+						"patchStrategicMerge": {
+							"metadata": {
+								"labels": {
+									"+(team)": "dev",
+									"app": "nginx"
+								}
+							}
+						}
+					}
+				},
+				{
+					"name": "add-annotation",
+					"match": {
+						"resources": {
+							"kinds": [
+								"Pod"
+							]
+						}
+					},
+					"mutate": {
+						"patchesJson6902": "- op: add\n  path: /metadata/annotations/owner\n  value: platform"
+					}
+				},
+				{
+					"name": "set-pull-policy",
+					"match": {
+						"resources": {
+							"kinds": [
+								"Pod"
+							]
+						}
+					},
+					"mutate": {
+						"foreach": {
+							"list": "request.object.spec.containers",
+							"patchStrategicMerge": {
+								"spec": {
+									"containers": [
+										{
+											"name": "{{ element.name }}",
+											"imagePullPolicy": "Always"
+										}
+									]
+								}
+							}
+						}
+					}
+				}
+			]
+		}
+	}`)
+
+	resourceRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {
+			"name": "nginx"
+		},
+		"spec": {
+			"containers": [
+				{
+					"name": "nginx",
+					"image": "nginx:1.20"
+				}
+			]
+		}
+	}`)
+
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+
+	mutate := func(resourceRaw []byte) *response.EngineResponse {
+		resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+		assert.NilError(t, err)
+		ctx := context.NewContext()
+		assert.NilError(t, ctx.AddResource(resourceRaw))
+
+		policyContext := &PolicyContext{
+			Policy:      policy,
+			JSONContext: ctx,
+			NewResource: *resourceUnstructured}
+		return Mutate(policyContext)
+	}
+
+	er := mutate(resourceRaw)
+	assert.Assert(t, er.IsSuccessful())
+	assert.Assert(t, len(er.GetPatches()) > 0)
+
+	// mutating the patched resource again results in no patches
+	patchedRaw, err := er.PatchedResource.MarshalJSON()
+	assert.NilError(t, err)
+	er = mutate(patchedRaw)
+	assert.Assert(t, er.IsSuccessful())
+	assert.Equal(t, len(er.GetPatches()), 0)
+}
+
+func Test_MutateRulesOrdered(t *testing.T) {
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+			"name": "ordered"
+		},
+		"spec": {
+			"rules": [
+				{
+					"name": "add-label",
+					"match": {
+						"resources": {
+							"kinds": [
+								"Pod"
+							]
+						}
+					},
+					"mutate": {
+						"patchStrategicMerge": {
+							"metadata": {
+								"labels": {
+									"app": "{{request.object.metadata.name}}"
+								}
+							}
+						}
+					}
+				},
+				{
+					"name": "copy-label",
+					"match": {
+						"resources": {
+							"kinds": [
+								"Pod"
+							]
+						}
+					},
+					"preconditions": [
+						{
+							"key": "{{request.object.metadata.labels.app}}",
+							"operator": "Equals",
+							"value": "nginx"
+						}
+					],
+					"mutate": {
+						"patchStrategicMerge": {
+							"metadata": {
+								"annotations": {
+									"app": "{{request.object.metadata.labels.app}}"
+								}
+							}
+						}
+					}
+				}
+			]
+		}
+	}`)
+
+	resourceRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {
+			"name": "nginx"
+		},
+		"spec": {
+			"containers": [
+				{
+					"name": "nginx",
+					"image": "nginx:1.20"
+				}
+			]
+		}
+	}`)
+
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+	resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+	assert.NilError(t, err)
+	ctx := context.NewContext()
+	assert.NilError(t, ctx.AddResource(resourceRaw))
+
+	policyContext := &PolicyContext{
+		Policy:      policy,
+		JSONContext: ctx,
+		NewResource: *resourceUnstructured}
+	er := Mutate(policyContext)
+
+	assert.Equal(t, len(er.PolicyResponse.Rules), 2)
+	assert.Equal(t, er.PolicyResponse.Rules[0].Name, "add-label")
+	assert.Equal(t, er.PolicyResponse.Rules[1].Name, "copy-label")
+	assert.Assert(t, er.IsSuccessful())
+	assert.DeepEqual(t, er.PatchedResource.GetAnnotations(), map[string]string{"app": "nginx"})
+}
+
+func Test_MutateImmutableField(t *testing.T) {
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+			"name": "add-selector"
+		},
+		"spec": {
+			"rules": [
+				{
+					"name": "add-selector",
+					"match": {
+						"resources": {
+							"kinds": [
+								"Deployment"
+							]
+						}
+					},
+					"mutate": {
+						"patchStrategicMerge": {
+							"spec": {
+								"selector": {
+									"matchLabels": {
+										"team": "dev"
+									}
+								}
+							}
+						}
+					}
+				},
+				{
+					"name": "add-label",
+					"match": {
+						"resources": {
+							"kinds": [
+								"Deployment"
+							]
+						}
+					},
+					"mutate": {
+						"patchStrategicMerge": {
+							"metadata": {
+								"labels": {
+									"team": "dev"
+								}
+							}
+						}
+					}
+				}
+			]
+		}
+	}`)
+
+	resourceRaw := []byte(`{
+		"apiVersion": "apps/v1",
+		"kind": "Deployment",
+		"metadata": {
+			"name": "nginx"
+		},
+		"spec": {
+			"selector": {
+				"matchLabels": {
+					"app": "nginx"
+				}
+			}
+		}
+	}`)
+
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+	resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+	assert.NilError(t, err)
+	ctx := context.NewContext()
+	assert.NilError(t, ctx.AddResource(resourceRaw))
+
+	policyContext := &PolicyContext{
+		Policy:      policy,
+		JSONContext: ctx,
+		NewResource: *resourceUnstructured,
+		ImmutableFieldsFunc: func(kind string) []string {
+			if kind == "Deployment" {
+				return []string{"spec.selector"}
+			}
+			return nil
+		}}
+	er := Mutate(policyContext)
+
+	assert.Equal(t, len(er.PolicyResponse.Rules), 2)
+	assert.Assert(t, !er.PolicyResponse.Rules[0].Success)
+	assert.Equal(t, er.PolicyResponse.Rules[0].Message, "mutation of immutable field spec.selector is not allowed")
+	assert.Equal(t, len(er.PolicyResponse.Rules[0].Patches), 0)
+	assert.Assert(t, er.PolicyResponse.Rules[1].Success)
+
+	matchLabels, _, err := unstructured.NestedStringMap(er.PatchedResource.Object, "spec", "selector", "matchLabels")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, matchLabels, map[string]string{"app": "nginx"})
+	assert.DeepEqual(t, er.PatchedResource.GetLabels(), map[string]string{"team": "dev"})
+}
+
+func Test_MutateUserInfoVariables(t *testing.T) {
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+			"name": "created-by"
+		},
+		"spec": {
+			"rules": [
+				{
+					"name": "created-by",
+					"match": {
+						"resources": {
+							"kinds": [
+								"ConfigMap"
+							]
+						}
+					},
+					"mutate": {
+						"patchStrategicMerge": {
+							"metadata": {
+								"annotations": {
+									"+(created-by)": "{{request.userInfo.username}}",
+									"+(team)": "{{request.userInfo.extra.team[0]}}"
+								}
+							}
+						}
+					}
+				}
+			]
+		}
+	}`)
+
+	resourceRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "ConfigMap",
+		"metadata": {
+			"name": "app"
+		}
+	}`)
+
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+	resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+	assert.NilError(t, err)
+	ctx := context.NewContext()
+	assert.NilError(t, ctx.AddResource(resourceRaw))
+
+	userInfo := kyverno.RequestInfo{}
+	userInfo.AdmissionUserInfo.Username = "jane"
+	userInfo.AdmissionUserInfo.Groups = []string{"dev"}
+	userInfo.AdmissionUserInfo.Extra = map[string]authenticationv1.ExtraValue{"team": {"payments"}}
+	assert.NilError(t, ctx.AddUserInfo(userInfo))
+
+	policyContext := &PolicyContext{
+		Policy:        policy,
+		JSONContext:   ctx,
+		NewResource:   *resourceUnstructured,
+		AdmissionInfo: userInfo}
+	er := Mutate(policyContext)
+
+	assert.Assert(t, er.IsSuccessful())
+	assert.DeepEqual(t, er.PatchedResource.GetAnnotations(), map[string]string{"created-by": "jane", "team": "payments"})
+}
+
+// mutatePod applies the overlay of a mutate rule to the pod, the rule is not reported when it does not patch the pod
+func mutatePod(t *testing.T, overlay, pod string) *response.EngineResponse {
+	t.Helper()
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "mutate-pod"},
+		"spec": {
+			"rules": [{
+				"name": "mutate-pod",
+				"match": {"resources": {"kinds": ["Pod"]}},
+				"mutate": {"overlay": ` + overlay + `}
+			}]
+		}
+	}`)
+
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+	resourceUnstructured, err := utils.ConvertToUnstructured([]byte(pod))
+	assert.NilError(t, err)
+	ctx := context.NewContext()
+	assert.NilError(t, ctx.AddResource([]byte(pod)))
+
+	er := Mutate(&PolicyContext{Policy: policy, JSONContext: ctx, NewResource: *resourceUnstructured})
+	for _, rule := range er.PolicyResponse.Rules {
+		assert.Assert(t, rule.Success, rule.Message)
+	}
+	return er
+}
+
+func Test_MutateConditionPerListElement(t *testing.T) {
+	pod := `{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {"name": "web", "labels": {"team": "payments"}},
+		"spec": {
+			"containers": [
+				{"name": "nginx", "image": "nginx:latest"},
+				{"name": "sidecar", "image": "envoy:latest", "securityContext": {"privileged": true}},
+				{"name": "busybox", "image": "busybox:1.28"}
+			]
+		}
+	}`
+
+	imagePullPolicies := func(er *response.EngineResponse) map[string]interface{} {
+		containers, _, err := unstructured.NestedSlice(er.PatchedResource.Object, "spec", "containers")
+		assert.NilError(t, err)
+		policies := map[string]interface{}{}
+		for _, container := range containers {
+			container := container.(map[string]interface{})
+			if policy, ok := container["imagePullPolicy"]; ok {
+				policies[container["name"].(string)] = policy
+			}
+		}
+		return policies
+	}
+
+	// the conditions of a list element are evaluated per element and all of them must be met
+	er := mutatePod(t, `{"spec": {"containers": [{"(image)": "*:latest", "(name)": "nginx", "imagePullPolicy": "Always"}]}}`, pod)
+	assert.DeepEqual(t, imagePullPolicies(er), map[string]interface{}{"nginx": "Always"})
+
+	// the conditions can be nested in the list elements
+	er = mutatePod(t, `{"spec": {"containers": [{"(securityContext)": {"privileged": true}, "imagePullPolicy": "Always"}]}}`, pod)
+	assert.DeepEqual(t, imagePullPolicies(er), map[string]interface{}{"sidecar": "Always"})
+
+	// the conditions on the resource apply to the whole overlay
+	tolerations := `{"metadata": {"labels": {"(team)": "%s"}}, "spec": {"tolerations": [{"key": "dedicated", "operator": "Exists"}]}}`
+	er = mutatePod(t, fmt.Sprintf(tolerations, "payments"), pod)
+	tolerationList, _, err := unstructured.NestedSlice(er.PatchedResource.Object, "spec", "tolerations")
+	assert.NilError(t, err)
+	assert.Equal(t, len(tolerationList), 1)
+
+	er = mutatePod(t, fmt.Sprintf(tolerations, "billing"), pod)
+	assert.Equal(t, len(er.PolicyResponse.Rules), 0)
+}
+
+func Test_MutateRemoveField(t *testing.T) {
+	pod := `{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {"name": "web"},
+		"spec": {
+			"hostIPC": true,
+			"hostPID": true,
+			"containers": [
+				{"name": "nginx", "image": "nginx:latest", "securityContext": {"privileged": true, "runAsNonRoot": true}},
+				{"name": "busybox", "image": "busybox:1.28", "securityContext": {"privileged": true}}
+			]
+		}
+	}`
+
+	er := mutatePod(t, `{"spec": {"hostIPC": null, "hostNetwork": null, "containers": [{"(image)": "*:latest", "securityContext": {"privileged": null}}]}}`, pod)
+	spec, _, err := unstructured.NestedMap(er.PatchedResource.Object, "spec")
+	assert.NilError(t, err)
+	_, ok := spec["hostIPC"]
+	assert.Assert(t, !ok)
+	_, ok = spec["hostNetwork"]
+	assert.Assert(t, !ok)
+	assert.Equal(t, spec["hostPID"], true)
+
+	securityContexts := map[string]interface{}{}
+	for _, container := range spec["containers"].([]interface{}) {
+		container := container.(map[string]interface{})
+		securityContexts[container["name"].(string)] = container["securityContext"]
+	}
+	assert.DeepEqual(t, securityContexts, map[string]interface{}{
+		"nginx":   map[string]interface{}{"runAsNonRoot": true},
+		"busybox": map[string]interface{}{"privileged": true},
+	})
+}
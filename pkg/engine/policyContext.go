@@ -31,6 +31,9 @@ type PolicyContext struct {
 
 	ExcludeResourceFunc func(kind, namespace, name string) bool
 
+	// ImmutableFieldsFunc returns the paths of the fields of a kind that mutations must not modify
+	ImmutableFieldsFunc func(kind string) []string
+
 	// ResourceCache provides listers to resources. Currently Supports Configmap
 	ResourceCache resourcecache.ResourceCache
 
@@ -39,4 +42,8 @@ type PolicyContext struct {
 
 	// NamespaceLabels stores the label of namespace to be processed by namespace selector
 	NamespaceLabels map[string]string
+
+	// apiCallCache stores the responses of the API calls of context entries by URL path,
+	// so that each URL path is fetched once per policy context
+	apiCallCache map[string][]byte
 }
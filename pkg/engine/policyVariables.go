@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// loadVariableDefaults adds the default values of the variables declared by the policy, the
+// context entries of the rule are loaded afterwards and override them
+func loadVariableDefaults(ctx *PolicyContext) error {
+	for _, variable := range ctx.Policy.Spec.Variables {
+		if variable.Default == nil {
+			continue
+		}
+
+		jsonData, err := json.Marshal(map[string]interface{}{variable.Name: variable.Default})
+		if err != nil {
+			return fmt.Errorf("failed to marshal the default value of variable %s: %v", variable.Name, err)
+		}
+
+		if err := ctx.JSONContext.AddJSON(jsonData); err != nil {
+			return fmt.Errorf("failed to add the default value of variable %s: %v", variable.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// checkVariableType checks that the value of the declared variable has the declared type, the
+// default values are checked when the policy is validated
+func checkVariableType(ctx *PolicyContext, name string) error {
+	for _, variable := range ctx.Policy.Spec.Variables {
+		if variable.Name != name || variable.Type == "" {
+			continue
+		}
+
+		value, err := ctx.JSONContext.Query(variable.Name)
+		if err != nil {
+			return fmt.Errorf("failed to query variable %s: %v", variable.Name, err)
+		}
+
+		if value == nil {
+			continue
+		}
+
+		if !HasVariableType(value, variable.Type) {
+			return fmt.Errorf("variable %s has value %v of type %s, expected %s", variable.Name, value, jsonType(value), variable.Type)
+		}
+	}
+
+	return nil
+}
+
+// HasVariableType checks if the JSON value has the type of a variable declaration
+func HasVariableType(value interface{}, variableType string) bool {
+	return jsonType(value) == variableType
+}
+
+// jsonType returns the JSON type of an unmarshalled value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64, int, int64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -19,6 +19,8 @@ type EngineResponse struct {
 type PolicyResponse struct {
 	// policy name
 	Policy string `json:"policy"`
+	// policy namespace, empty for the cluster policies
+	Namespace string `json:"namespace,omitempty"`
 	// resource details
 	Resource ResourceSpec `json:"resource"`
 	// policy statistics
@@ -66,6 +68,12 @@ type RuleResponse struct {
 	Patches [][]byte `json:"patches,omitempty"`
 	// success/fail
 	Success bool `json:"success"`
+	// the rule is skipped, e.g. the rules which reference the admission requests in background mode
+	Skipped bool `json:"skipped,omitempty"`
+	// severity of the rule, optional
+	Severity string `json:"severity,omitempty"`
+	// report properties of the rule with the variables substituted, optional
+	Properties map[string]string `json:"properties,omitempty"`
 	// statistics
 	RuleStats `json:",inline"`
 }
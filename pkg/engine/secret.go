@@ -0,0 +1,102 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/variables"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SecretAllowlist restricts the Secrets read by policies, so that a policy cannot expose
+// the data of any Secret of the cluster
+type SecretAllowlist interface {
+	// IsSecretAllowed checks if policies may read the Secret
+	IsSecretAllowed(namespace, name string) bool
+}
+
+// secretAllowlist is not set when no Secrets are allowed (e.g. in the CLI)
+var secretAllowlist SecretAllowlist
+
+// SetSecretAllowlist sets the allowlist of the Secrets read by Secret context entries and service calls
+func SetSecretAllowlist(allowlist SecretAllowlist) {
+	secretAllowlist = allowlist
+}
+
+func loadSecretData(logger logr.Logger, entry kyverno.ContextEntry, ctx *PolicyContext) error {
+	ref, err := substituteSecretReference(logger, entry, ctx)
+	if err != nil {
+		return err
+	}
+
+	value, err := readSecretKey(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("failed to load secret for context entry %s: %v", entry.Name, err)
+	}
+
+	jsonData, err := json.Marshal(map[string]interface{}{entry.Name: value})
+	if err != nil {
+		return fmt.Errorf("failed to marshal secret for context entry %s: %v", entry.Name, err)
+	}
+
+	if err := ctx.JSONContext.AddJSON(jsonData); err != nil {
+		return fmt.Errorf("failed to add secret for context entry %s: %v", entry.Name, err)
+	}
+
+	return nil
+}
+
+func substituteSecretReference(logger logr.Logger, entry kyverno.ContextEntry, ctx *PolicyContext) (*kyverno.SecretKeyReference, error) {
+	name, err := variables.SubstituteVars(logger, ctx.JSONContext, entry.Secret.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to substitute variables in context %s secret.name %s: %v", entry.Name, entry.Secret.Name, err)
+	}
+
+	namespace, err := variables.SubstituteVars(logger, ctx.JSONContext, entry.Secret.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to substitute variables in context %s secret.namespace %s: %v", entry.Name, entry.Secret.Namespace, err)
+	}
+
+	ref := &kyverno.SecretKeyReference{Key: entry.Secret.Key}
+	var ok bool
+	if ref.Name, ok = name.(string); !ok {
+		return nil, fmt.Errorf("invalid secret name %v in context entry %s, expected a string", name, entry.Name)
+	}
+
+	if ref.Namespace, ok = namespace.(string); !ok {
+		return nil, fmt.Errorf("invalid secret namespace %v in context entry %s, expected a string", namespace, entry.Name)
+	}
+
+	return ref, nil
+}
+
+// readSecretKey returns the decoded value of the key of an allowed Secret
+func readSecretKey(ctx *PolicyContext, ref *kyverno.SecretKeyReference) (string, error) {
+	if secretAllowlist == nil || !secretAllowlist.IsSecretAllowed(ref.Namespace, ref.Name) {
+		return "", fmt.Errorf("secret %s/%s is not allowed by the allowedSecrets of the Kyverno configuration", ref.Namespace, ref.Name)
+	}
+
+	if ctx.Client == nil {
+		return "", fmt.Errorf("API client is not available")
+	}
+
+	obj, err := ctx.Client.GetResource("v1", "Secret", ref.Namespace, ref.Name)
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s/%s: %v", ref.Namespace, ref.Name, err)
+	}
+
+	var secret corev1.Secret
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &secret); err != nil {
+		return "", fmt.Errorf("failed to convert secret %s/%s: %v", ref.Namespace, ref.Name, err)
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret %s/%s", ref.Key, ref.Namespace, ref.Name)
+	}
+
+	return string(value), nil
+}
@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type secretAllowlistFunc func(namespace, name string) bool
+
+func (f secretAllowlistFunc) IsSecretAllowed(namespace, name string) bool {
+	return f(namespace, name)
+}
+
+func Test_LoadSecretData(t *testing.T) {
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "cosign", "namespace": "test"},
+		// "-----BEGIN PUBLIC KEY-----"
+		"data": map[string]interface{}{"cosign.pub": "LS0tLS1CRUdJTiBQVUJMSUMgS0VZLS0tLS0="},
+	}}
+	dclient, err := client.NewMockClient(runtime.NewScheme(), nil, secret)
+	assert.NilError(t, err)
+	dclient.SetDiscovery(client.NewFakeDiscoveryClient(nil))
+
+	entries := []kyverno.ContextEntry{
+		{
+			Name:   "publicKey",
+			Secret: &kyverno.SecretKeyReference{Name: "cosign", Namespace: "{{request.object.metadata.namespace}}", Key: "cosign.pub"},
+		},
+	}
+
+	newPolicyContext := func() *PolicyContext {
+		ctx := context.NewContext()
+		assert.NilError(t, ctx.AddResource([]byte(`{"kind": "Pod", "metadata": {"name": "web", "namespace": "test"}}`)))
+		return &PolicyContext{JSONContext: ctx, Client: dclient}
+	}
+
+	// no Secrets are allowed without an allowlist
+	err = LoadContext(log.Log, entries, nil, newPolicyContext())
+	assert.ErrorContains(t, err, "secret test/cosign is not allowed by the allowedSecrets of the Kyverno configuration")
+
+	SetSecretAllowlist(secretAllowlistFunc(func(namespace, name string) bool {
+		return namespace == "test" && name == "cosign"
+	}))
+	defer SetSecretAllowlist(nil)
+
+	policyContext := newPolicyContext()
+	err = LoadContext(log.Log, entries, nil, policyContext)
+	assert.NilError(t, err)
+
+	publicKey, err := policyContext.JSONContext.Query("publicKey")
+	assert.NilError(t, err)
+	assert.Equal(t, publicKey, "-----BEGIN PUBLIC KEY-----")
+
+	entries[0].Secret.Key = "missing"
+	err = LoadContext(log.Log, entries, nil, newPolicyContext())
+	assert.ErrorContains(t, err, "key missing not found in secret test/cosign")
+
+	entries[0].Secret.Name = "other"
+	err = LoadContext(log.Log, entries, nil, newPolicyContext())
+	assert.ErrorContains(t, err, "secret test/other is not allowed")
+}
@@ -0,0 +1,241 @@
+package engine
+
+import (
+	contextdefault "context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/variables"
+)
+
+// maxServiceResponseSize limits the size of the responses read from external services
+const maxServiceResponseSize = 2 << 20
+
+// serviceCallCache stores the responses of ServiceCall context entries with cacheSeconds set,
+// the responses are shared across admission requests
+var serviceCallCache = newResponseCache()
+
+// responseCache is a cache of responses expiring after their time to live
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	data    []byte
+	expires time.Time
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{entries: make(map[string]cachedResponse)}
+}
+
+func (c *responseCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return entry.data, true
+}
+
+func (c *responseCache) set(key string, data []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = cachedResponse{data: data, expires: now.Add(ttl)}
+}
+
+func loadServiceData(logger logr.Logger, entry kyverno.ContextEntry, ctx *PolicyContext) error {
+	jsonData, err := fetchServiceData(logger, entry, ctx)
+	if err != nil {
+		return err
+	}
+
+	return addNamedData(ctx.JSONContext, entry.Name, jsonData, entry.ServiceCall.JMESPath)
+}
+
+func fetchServiceData(logger logr.Logger, entry kyverno.ContextEntry, ctx *PolicyContext) ([]byte, error) {
+	call := entry.ServiceCall
+	u, err := variables.SubstituteVars(logger, ctx.JSONContext, call.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to substitute variables in context entry %s %s: %v", entry.Name, call.URL, err)
+	}
+
+	serviceURL, ok := u.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid URL %v in context entry %s, expected a string", u, entry.Name)
+	}
+
+	if parsed, err := url.Parse(serviceURL); err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid URL %s in context entry %s, an HTTPS URL is required", serviceURL, entry.Name)
+	}
+
+	// responses are only shared between calls with the same credentials
+	key := "service:" + serviceURL
+	if call.AuthSecret != nil {
+		key = fmt.Sprintf("%s:%s/%s/%s", key, call.AuthSecret.Namespace, call.AuthSecret.Name, call.AuthSecret.Key)
+	}
+
+	if jsonData, ok := ctx.apiCallCache[key]; ok {
+		logger.V(4).Info("using cached service response", "url", serviceURL)
+		return jsonData, nil
+	}
+
+	if call.CacheSeconds > 0 {
+		if jsonData, ok := serviceCallCache.get(key); ok {
+			logger.V(4).Info("using cached service response", "url", serviceURL)
+			return jsonData, nil
+		}
+	}
+
+	authorization, err := serviceAuthorization(ctx, call.AuthSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the authorization of context entry %s: %v", entry.Name, err)
+	}
+
+	httpClient, err := newServiceClient(call.CABundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure TLS of context entry %s: %v", entry.Name, err)
+	}
+
+	timeout := apiCallTimeout
+	if call.TimeoutSeconds > 0 {
+		timeout = time.Duration(call.TimeoutSeconds) * time.Second
+	}
+	httpClient.Timeout = timeout
+
+	jsonData, err := callWithTimeout(func(callCtx contextdefault.Context) ([]byte, error) {
+		return callService(callCtx, httpClient, serviceURL, authorization)
+	}, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.apiCallCache == nil {
+		ctx.apiCallCache = make(map[string][]byte)
+	}
+	ctx.apiCallCache[key] = jsonData
+
+	if call.CacheSeconds > 0 {
+		serviceCallCache.set(key, jsonData, time.Duration(call.CacheSeconds)*time.Second)
+	}
+
+	return jsonData, nil
+}
+
+// serviceTransports stores the transports of the ServiceCall context entries by CA bundle, the transports
+// are shared so that their connections are reused across admission requests
+var serviceTransports = &transportCache{transports: make(map[string]*http.Transport)}
+
+type transportCache struct {
+	mu         sync.Mutex
+	transports map[string]*http.Transport
+}
+
+// get returns the transport trusting the CA bundle, or the system roots if the bundle is empty
+func (c *transportCache) get(caBundle string) (*http.Transport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if transport, ok := c.transports[caBundle]; ok {
+		return transport, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caBundle != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caBundle)) {
+			return nil, fmt.Errorf("caBundle does not contain any PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}
+	c.transports[caBundle] = transport
+	return transport, nil
+}
+
+// newServiceClient returns an HTTP client with the shared transport trusting the CA bundle
+func newServiceClient(caBundle string) (*http.Client, error) {
+	transport, err := serviceTransports.get(caBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// serviceAuthorization returns the value of the Authorization header stored in the secret
+func serviceAuthorization(ctx *PolicyContext, ref *kyverno.SecretKeyReference) (string, error) {
+	if ref == nil {
+		return "", nil
+	}
+
+	return readSecretKey(ctx, ref)
+}
+
+func callService(ctx contextdefault.Context, httpClient *http.Client, serviceURL, authorization string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serviceURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call service %s: %v", serviceURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to call service %s: %s", serviceURL, resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxServiceResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of service %s: %v", serviceURL, err)
+	}
+
+	if len(data) > maxServiceResponseSize {
+		return nil, fmt.Errorf("response of service %s exceeds %d bytes", serviceURL, maxServiceResponseSize)
+	}
+
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("response of service %s is not valid JSON", serviceURL)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_LoadServiceData(t *testing.T) {
+	calls := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer cmdb-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		calls++
+		fmt.Fprintf(w, `{"app": "%s", "owner": "payments", "tier": 1}`, r.URL.Path)
+	}))
+	defer server.Close()
+
+	caBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "cmdb", "namespace": "kyverno"},
+		// "Bearer cmdb-token"
+		"data": map[string]interface{}{"token": "QmVhcmVyIGNtZGItdG9rZW4="},
+	}}
+	dclient, err := client.NewMockClient(runtime.NewScheme(), nil, secret)
+	assert.NilError(t, err)
+	dclient.SetDiscovery(client.NewFakeDiscoveryClient(nil))
+
+	SetSecretAllowlist(secretAllowlistFunc(func(namespace, name string) bool {
+		return namespace == "kyverno" && name == "cmdb"
+	}))
+	defer SetSecretAllowlist(nil)
+
+	newPolicyContext := func() *PolicyContext {
+		ctx := context.NewContext()
+		assert.NilError(t, ctx.AddResource([]byte(`{"kind": "Deployment", "metadata": {"name": "web", "namespace": "test"}}`)))
+		return &PolicyContext{JSONContext: ctx, Client: dclient}
+	}
+
+	entries := []kyverno.ContextEntry{
+		{
+			Name: "app",
+			ServiceCall: &kyverno.ServiceCall{
+				URL:          server.URL + "/apps/{{request.object.metadata.name}}",
+				CABundle:     caBundle,
+				AuthSecret:   &kyverno.SecretKeyReference{Name: "cmdb", Namespace: "kyverno", Key: "token"},
+				CacheSeconds: 60,
+			},
+		},
+		{
+			Name: "owner",
+			ServiceCall: &kyverno.ServiceCall{
+				URL:        server.URL + "/apps/web",
+				CABundle:   caBundle,
+				AuthSecret: &kyverno.SecretKeyReference{Name: "cmdb", Namespace: "kyverno", Key: "token"},
+				JMESPath:   "owner",
+			},
+		},
+	}
+
+	policyContext := newPolicyContext()
+	err = LoadContext(log.Log, entries, nil, policyContext)
+	assert.NilError(t, err)
+
+	app, err := policyContext.JSONContext.Query("app.app")
+	assert.NilError(t, err)
+	assert.Equal(t, app, "/apps/web")
+
+	owner, err := policyContext.JSONContext.Query("owner")
+	assert.NilError(t, err)
+	assert.Equal(t, owner, "payments")
+
+	// the second entry calls the same URL and is served from the cache of the request
+	assert.Equal(t, calls, 1)
+
+	// the response of the first entry is cached across requests, the second entry is called again
+	err = LoadContext(log.Log, entries, nil, newPolicyContext())
+	assert.NilError(t, err)
+	assert.Equal(t, calls, 2)
+
+	// the certificate of the server is not trusted without the CA bundle
+	entries[1].ServiceCall.CABundle = ""
+	err = LoadContext(log.Log, entries[1:], nil, newPolicyContext())
+	assert.ErrorContains(t, err, "failed to call service")
+
+	// the request is rejected without the authorization
+	entries[1].ServiceCall.CABundle = caBundle
+	entries[1].ServiceCall.AuthSecret = nil
+	err = LoadContext(log.Log, entries[1:], nil, newPolicyContext())
+	assert.ErrorContains(t, err, "401 Unauthorized")
+
+	entries[1].ServiceCall.URL = "http://cmdb.corp.com/apps/web"
+	err = LoadContext(log.Log, entries[1:], nil, newPolicyContext())
+	assert.ErrorContains(t, err, "an HTTPS URL is required")
+}
+
+func Test_NewServiceClient_SharesTransports(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	first, err := newServiceClient(caBundle)
+	assert.NilError(t, err)
+	second, err := newServiceClient(caBundle)
+	assert.NilError(t, err)
+	assert.Equal(t, first.Transport, second.Transport)
+
+	system, err := newServiceClient("")
+	assert.NilError(t, err)
+	assert.Assert(t, system.Transport != first.Transport)
+
+	_, err = newServiceClient("not a certificate")
+	assert.Error(t, err, "caBundle does not contain any PEM encoded certificate")
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+// PatchesAnnotation is the annotation key listing the policies and rules that patched a resource
+const PatchesAnnotation = "policies.kyverno.io/patches"
+
+type rulePatch struct {
+	RuleName string `json:"rulename"`
+	Op       string `json:"op"`
+	Path     string `json:"path"`
+}
+
+var operationToPastTense = map[string]string{
+	"add":     "added",
+	"remove":  "removed",
+	"replace": "replaced",
+	"move":    "moved",
+	"copy":    "copied",
+	"test":    "tested",
+}
+
+// PatchesAnnotationValue builds the value of the patches annotation from the successful engine responses.
+// Each applied rule is listed as "<rule>.<policy>.kyverno.io" with the operations of its patches,
+// nil is returned if there are no patches.
+func PatchesAnnotationValue(engineResponses []*response.EngineResponse, log logr.Logger) []byte {
+	var annotationContent = make(map[string]string)
+	for _, engineResponse := range engineResponses {
+		if !engineResponse.IsSuccessful() {
+			log.V(3).Info("skip building annotation; policy failed to apply", "policy", engineResponse.PolicyResponse.Policy)
+			continue
+		}
+
+		rulePatches := annotationFromPolicyResponse(engineResponse.PolicyResponse, log)
+		if rulePatches == nil {
+			continue
+		}
+
+		policyName := engineResponse.PolicyResponse.Policy
+		operations := make(map[string][]string)
+		var keys []string
+		for _, rulePatch := range rulePatches {
+			key := rulePatch.RuleName + "." + policyName + ".kyverno.io"
+			if _, ok := operations[key]; !ok {
+				keys = append(keys, key)
+			}
+			operations[key] = append(operations[key], operationToPastTense[rulePatch.Op]+" "+rulePatch.Path)
+		}
+
+		for _, key := range keys {
+			annotationContent[key] = strings.Join(operations[key], ", ")
+		}
+	}
+
+	// return nil if there's no patches
+	// otherwise result = null, len(result) = 4
+	if len(annotationContent) == 0 {
+		return nil
+	}
+
+	result, _ := yamlv2.Marshal(annotationContent)
+
+	return result
+}
+
+func annotationFromPolicyResponse(policyResponse response.PolicyResponse, log logr.Logger) []rulePatch {
+	var rulePatches []rulePatch
+	for _, ruleInfo := range policyResponse.Rules {
+		for _, patch := range ruleInfo.Patches {
+			var patchmap map[string]interface{}
+			if err := json.Unmarshal(patch, &patchmap); err != nil {
+				log.Error(err, "Failed to parse JSON patch bytes")
+				continue
+			}
+
+			rp := rulePatch{
+				RuleName: ruleInfo.Name,
+				Op:       patchmap["op"].(string),
+				Path:     patchmap["path"].(string)}
+
+			rulePatches = append(rulePatches, rp)
+			log.V(4).Info("annotation value prepared", "patches", rulePatches)
+		}
+	}
+	if len(rulePatches) == 0 {
+		return nil
+	}
+	return rulePatches
+}
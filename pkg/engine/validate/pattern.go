@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/engine/common"
 	"github.com/kyverno/kyverno/pkg/engine/operator"
 	"github.com/minio/minio/pkg/wildcard"
 	apiresource "k8s.io/apimachinery/pkg/api/resource"
@@ -84,11 +85,17 @@ func validateValueWithIntPattern(log logr.Logger, value interface{}, pattern int
 	case string:
 		// extract int64 from string
 		int64Num, err := strconv.ParseInt(typedValue, 10, 64)
-		if err != nil {
-			log.Error(err, "Failed to parse int64 from string")
-			return false
+		if err == nil {
+			return int64Num == pattern
+		}
+
+		// the value may be a resource quantity (e.g. "2Gi"), compare it as such
+		if valueQuan, err := apiresource.ParseQuantity(typedValue); err == nil {
+			return compareQuantity(valueQuan, *apiresource.NewQuantity(pattern, apiresource.DecimalSI), operator.Equal)
 		}
-		return int64Num == pattern
+
+		log.Error(err, "Failed to parse int64 from string")
+		return false
 	default:
 		log.Info("Expected type int", "type", fmt.Sprintf("%T", value), "value", value)
 		return false
@@ -117,11 +124,24 @@ func validateValueWithFloatPattern(log logr.Logger, value interface{}, pattern f
 	case string:
 		// extract float64 from string
 		float64Num, err := strconv.ParseFloat(typedValue, 64)
-		if err != nil {
+		if err == nil {
+			return float64Num == pattern
+		}
+
+		// the value may be a resource quantity (e.g. "500m"), compare it as such
+		valueQuan, quanErr := apiresource.ParseQuantity(typedValue)
+		if quanErr != nil {
 			log.Error(err, "Failed to parse float64 from string")
 			return false
 		}
-		return float64Num == pattern
+
+		patternQuan, quanErr := apiresource.ParseQuantity(strconv.FormatFloat(pattern, 'f', -1, 64))
+		if quanErr != nil {
+			log.Error(quanErr, "Failed to parse quantity from pattern", "pattern", pattern)
+			return false
+		}
+
+		return compareQuantity(valueQuan, patternQuan, operator.Equal)
 	default:
 		log.Info("Expected type float", "type", fmt.Sprintf("%T", value), "value", value)
 		return false
@@ -183,6 +203,10 @@ func validateValueWithStringPattern(log logr.Logger, value interface{}, pattern
 
 	operator := operator.GetOperatorFromStringPattern(pattern)
 	pattern = pattern[len(operator):]
+	if common.IsSemver(pattern) {
+		return validateSemver(log, value, pattern, operator)
+	}
+
 	number, str := getNumberAndStringPartsFromPattern(pattern)
 
 	if "" == number {
@@ -262,8 +286,29 @@ func validateNumberWithStr(log logr.Logger, value interface{}, pattern string, o
 	return true
 }
 
+// validateSemver compares semantic versions (e.g. ">=1.21.0"). Values which are
+// not semantic versions fall back to a wildcard string comparison.
+func validateSemver(log logr.Logger, value interface{}, pattern string, operatorVariable operator.Operator) bool {
+	if !common.IsSemver(value) {
+		log.V(4).Info("value is not a semantic version", "type", fmt.Sprintf("%T", value), "value", value, "pattern", pattern)
+		return validateString(log, value, pattern, operatorVariable)
+	}
+
+	result, err := common.CompareSemver(value.(string), pattern)
+	if err != nil {
+		log.Error(err, "failed to compare semantic versions", "value", value, "pattern", pattern)
+		return false
+	}
+
+	return compareResult(result, operatorVariable)
+}
+
 func compareQuantity(value, pattern apiresource.Quantity, op operator.Operator) bool {
-	result := value.Cmp(pattern)
+	return compareResult(value.Cmp(pattern), op)
+}
+
+// compareResult checks a comparison result (-1, 0, 1) against the operator
+func compareResult(result int, op operator.Operator) bool {
 	switch op {
 	case operator.Equal:
 		return result == int(equal)
@@ -338,6 +338,37 @@ func TestValidateQuantity_Operation(t *testing.T) {
 	assert.Assert(t, validateNumberWithStr(log.Log, "0.2", ".5", operator.NotEqual))
 }
 
+func TestValidateValueWithPattern_Quantity(t *testing.T) {
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "2048Mi", "<=2Gi"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "2Gi", "<=2Gi"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "2147483648", "<=2Gi"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, 2147483648.0, "<=2Gi"))
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, "2049Mi", "<=2Gi"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "2048Mi", "2Gi"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "256Mi", ">128Mi & <=2Gi"))
+}
+
+func TestValidateValueWithPattern_NumericPatternQuantityValue(t *testing.T) {
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "2Gi", int64(2147483648)))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "2Gi", 2147483648.0))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "500m", 0.5))
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, "1Gi", 1024))
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, "invalid", 1024))
+}
+
+func TestValidateValueWithPattern_Semver(t *testing.T) {
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "1.21.0", ">=1.21.0"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "v1.22.3", ">=1.21.0"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "1.10.0", ">1.9.0"))
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, "1.21.0-rc.1", ">=1.21.0"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "1.20.15", "<1.21.0 & >=1.20.0"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "v1.21.0", "1.21.0"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "1.21.1", "!1.21.0"))
+	// non-semver values fall back to string comparison
+	assert.Assert(t, !ValidateValueWithPattern(log.Log, "latest", ">=1.21.0"))
+	assert.Assert(t, ValidateValueWithPattern(log.Log, "latest", "!1.21.0"))
+}
+
 func TestGetOperatorFromStringPattern_OneChar(t *testing.T) {
 	assert.Equal(t, operator.GetOperatorFromStringPattern("f"), operator.Equal)
 }
@@ -10,6 +10,7 @@ import (
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/engine/common"
 	"github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/engine/deprecations"
 	"github.com/kyverno/kyverno/pkg/engine/response"
 	"github.com/kyverno/kyverno/pkg/engine/utils"
 	"github.com/kyverno/kyverno/pkg/engine/validate"
@@ -61,6 +62,7 @@ func buildResponse(logger logr.Logger, ctx *PolicyContext, resp *response.Engine
 	}
 
 	for i := range resp.PolicyResponse.Rules {
+		resp.PolicyResponse.Rules[i].Severity = ctx.Policy.GetRuleSeverity(resp.PolicyResponse.Rules[i].Name)
 		messageInterface, err := variables.SubstituteVars(logger, ctx.JSONContext, resp.PolicyResponse.Rules[i].Message)
 		if err != nil {
 			logger.V(4).Info("failed to substitute variables", "error", err.Error())
@@ -71,6 +73,7 @@ func buildResponse(logger logr.Logger, ctx *PolicyContext, resp *response.Engine
 	}
 
 	resp.PolicyResponse.Policy = ctx.Policy.Name
+	resp.PolicyResponse.Namespace = ctx.Policy.Namespace
 	resp.PolicyResponse.Resource.Name = resp.PatchedResource.GetName()
 	resp.PolicyResponse.Resource.Namespace = resp.PatchedResource.GetNamespace()
 	resp.PolicyResponse.Resource.Kind = resp.PatchedResource.GetKind()
@@ -105,7 +108,7 @@ func validateResource(log logr.Logger, ctx *PolicyContext) *response.EngineRespo
 		}
 
 		ctx.JSONContext.Restore()
-		if err := LoadContext(log, rule.Context, ctx.ResourceCache, ctx); err != nil {
+		if err := LoadContextLazily(log, rule.Context, ctx.ResourceCache, ctx); err != nil {
 			log.Error(err, "failed to load context")
 			continue
 		}
@@ -120,12 +123,34 @@ func validateResource(log logr.Logger, ctx *PolicyContext) *response.EngineRespo
 		}
 		// evaluate pre-conditions
 		// - handle variable substitutions
-		if !variables.EvaluateConditions(log, ctx.JSONContext, preconditionsCopy) {
+		strict := ctx.Policy.StrictVariablesEnabled()
+		passed, err := evaluateConditions(log, ctx.JSONContext, preconditionsCopy, strict)
+		if err != nil {
+			log.V(3).Info("failed to substitute variables in the preconditions", "reason", err.Error())
+			incrementAppliedCount(resp)
+			resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, variableErrorResponse(rule, utils.Validation, err))
+			continue
+		}
+
+		if !passed {
 			log.V(4).Info("resource fails the preconditions")
 			continue
 		}
 
-		if rule.Validation.Pattern != nil || rule.Validation.AnyPattern != nil {
+		applied := len(resp.PolicyResponse.Rules)
+		if rule.Validation.DeprecatedAPIs != nil {
+			ruleResponse := validateDeprecatedAPIs(log, ctx, rule)
+			if ruleResponse != nil {
+				incrementAppliedCount(resp)
+				resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, *ruleResponse)
+			}
+		} else if rule.Validation.ForEachValidation != nil {
+			ruleResponse := validateForEach(log, ctx, rule)
+			if ruleResponse != nil {
+				incrementAppliedCount(resp)
+				resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, *ruleResponse)
+			}
+		} else if rule.Validation.Pattern != nil || rule.Validation.AnyPattern != nil {
 			ruleResponse := validateResourceWithRule(log, ctx, rule)
 			if ruleResponse != nil {
 				if !common.IsConditionalAnchorError(ruleResponse.Message) {
@@ -139,7 +164,14 @@ func validateResource(log logr.Logger, ctx *PolicyContext) *response.EngineRespo
 				log.V(2).Info("wrongfully configured data", "reason", err.Error())
 				continue
 			}
-			deny := variables.EvaluateConditions(log, ctx.JSONContext, denyConditionsCopy)
+			deny, err := evaluateConditions(log, ctx.JSONContext, denyConditionsCopy, strict)
+			if err != nil {
+				log.V(3).Info("failed to substitute variables in the deny conditions", "reason", err.Error())
+				incrementAppliedCount(resp)
+				resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, variableErrorResponse(rule, utils.Validation, err))
+				continue
+			}
+
 			ruleResp := response.RuleResponse{
 				Name:    rule.Name,
 				Type:    utils.Validation.String(),
@@ -150,11 +182,32 @@ func validateResource(log logr.Logger, ctx *PolicyContext) *response.EngineRespo
 			incrementAppliedCount(resp)
 			resp.PolicyResponse.Rules = append(resp.PolicyResponse.Rules, ruleResp)
 		}
+
+		if len(resp.PolicyResponse.Rules) > applied && len(rule.ReportProperties) > 0 {
+			resp.PolicyResponse.Rules[applied].Properties = substituteReportProperties(log, ctx, rule)
+		}
 	}
 
 	return resp
 }
 
+// substituteReportProperties returns the report properties of the rule with the variables substituted,
+// the properties with variables which cannot be substituted are skipped
+func substituteReportProperties(log logr.Logger, ctx *PolicyContext, rule kyverno.Rule) map[string]string {
+	properties := make(map[string]string, len(rule.ReportProperties))
+	for key, value := range rule.ReportProperties {
+		substituted, err := variables.SubstituteVars(log, ctx.JSONContext, value)
+		if err != nil {
+			log.V(3).Info("failed to substitute variables in the report property", "property", key, "reason", err.Error())
+			continue
+		}
+
+		properties[key] = fmt.Sprint(substituted)
+	}
+
+	return properties
+}
+
 func validateResourceWithRule(log logr.Logger, ctx *PolicyContext, rule kyverno.Rule) (resp *response.RuleResponse) {
 	if reflect.DeepEqual(ctx.OldResource, unstructured.Unstructured{}) {
 		resp := validatePatterns(log, ctx.JSONContext, ctx.NewResource, rule)
@@ -176,6 +229,172 @@ func validateResourceWithRule(log logr.Logger, ctx *PolicyContext, rule kyverno.
 	return &newResp
 }
 
+// validateDeprecatedAPIs checks the API version of the resource against the deprecated API versions
+// of the target Kubernetes version.
+func validateDeprecatedAPIs(log logr.Logger, ctx *PolicyContext, rule kyverno.Rule) *response.RuleResponse {
+	if reflect.DeepEqual(ctx.NewResource, unstructured.Unstructured{}) {
+		log.V(3).Info("skipping validation on deleted resource")
+		return nil
+	}
+
+	startTime := time.Now()
+	check := rule.Validation.DeprecatedAPIs
+	resp := &response.RuleResponse{
+		Name:    rule.Name,
+		Type:    utils.Validation.String(),
+		Success: true,
+		Message: fmt.Sprintf("validation rule '%s' passed.", rule.Name),
+	}
+	defer func() {
+		resp.RuleStats.ProcessingTime = time.Since(startTime)
+	}()
+
+	deprecation, ok := deprecations.Lookup(ctx.NewResource.GetAPIVersion(), ctx.NewResource.GetKind())
+	if !ok {
+		return resp
+	}
+
+	failed, err := deprecation.IsRemoved(check.KubernetesVersion)
+	if err == nil && !failed && !check.RemovedOnly {
+		failed, err = deprecation.IsDeprecated(check.KubernetesVersion)
+	}
+
+	if err != nil {
+		resp.Success = false
+		resp.Message = fmt.Sprintf("failed to check deprecated API versions: %v", err)
+		return resp
+	}
+
+	if failed {
+		log.V(3).Info("resource uses a deprecated API version", "apiVersion", deprecation.APIVersion, "kubernetesVersion", check.KubernetesVersion)
+		resp.Success = false
+		resp.Message = buildDeprecationMessage(rule.Validation.Message, deprecation.String())
+	}
+
+	return resp
+}
+
+func buildDeprecationMessage(message, deprecation string) string {
+	if message == "" {
+		return fmt.Sprintf("validation error: %s", deprecation)
+	}
+
+	if strings.HasSuffix(message, ".") {
+		return fmt.Sprintf("validation error: %s %s", message, deprecation)
+	}
+
+	return fmt.Sprintf("validation error: %s. %s", message, deprecation)
+}
+
+// validateForEach applies the foreach validation to each element of the selected list.
+// The rule fails on the first element which does not satisfy the validation.
+func validateForEach(log logr.Logger, ctx *PolicyContext, rule kyverno.Rule) *response.RuleResponse {
+	if reflect.DeepEqual(ctx.NewResource, unstructured.Unstructured{}) {
+		log.V(3).Info("skipping validation on deleted resource")
+		return nil
+	}
+
+	startTime := time.Now()
+	foreach := rule.Validation.ForEachValidation
+	resp := &response.RuleResponse{
+		Name: rule.Name,
+		Type: utils.Validation.String(),
+	}
+	defer func() {
+		resp.RuleStats.ProcessingTime = time.Since(startTime)
+	}()
+
+	elements, err := evaluateList(foreach.List, ctx.JSONContext)
+	if err != nil {
+		resp.Success = false
+		resp.Message = fmt.Sprintf("failed to evaluate list %s: %v", foreach.List, err)
+		return resp
+	}
+
+	validationRule := foreach.ElementValidation(rule.Validation.Message)
+	for index, element := range elements {
+		if err := ctx.JSONContext.AddElement(element, index); err != nil {
+			resp.Success = false
+			resp.Message = fmt.Sprintf("failed to add element %d to the context: %v", index, err)
+			return resp
+		}
+
+		elementResp := validateElement(log, ctx.JSONContext, element, rule, validationRule, ctx.Policy.StrictVariablesEnabled())
+		if !elementResp.Success {
+			log.V(3).Info("validation failed for element", "list", foreach.List, "index", index)
+			resp.Success = false
+			// substitute the element variables while the element is in the context
+			if message, err := variables.SubstituteVars(log, ctx.JSONContext, elementResp.Message); err == nil {
+				elementResp.Message, _ = message.(string)
+			}
+			resp.Message = fmt.Sprintf("validation failure for %s[%d]: %s", foreach.List, index, elementResp.Message)
+			return resp
+		}
+	}
+
+	resp.Success = true
+	resp.Message = fmt.Sprintf("validation rule '%s' passed for %d elements of %s.", rule.Name, len(elements), foreach.List)
+	return resp
+}
+
+// validateElement validates a single foreach element with the pattern, anyPattern or deny declaration
+func validateElement(log logr.Logger, ctx context.EvalInterface, element interface{}, rule kyverno.Rule, validationRule *kyverno.Validation, strict bool) response.RuleResponse {
+	if validationRule.Pattern != nil || validationRule.AnyPattern != nil {
+		return validateElementPatterns(log, ctx, element, rule, validationRule)
+	}
+
+	resp := response.RuleResponse{
+		Name:    rule.Name,
+		Type:    utils.Validation.String(),
+		Success: true,
+	}
+
+	if validationRule.Deny != nil {
+		denyConditionsCopy, err := copyConditions(validationRule.Deny.AnyAllConditions)
+		if err != nil {
+			resp.Success = false
+			resp.Message = fmt.Sprintf("failed to copy deny conditions: %v", err)
+			return resp
+		}
+
+		deny, err := evaluateConditions(log, ctx, denyConditionsCopy, strict)
+		if err != nil {
+			return variableErrorResponse(rule, utils.Validation, err)
+		}
+
+		if deny {
+			resp.Success = false
+			resp.Message = validationRule.Message
+			if resp.Message == "" {
+				resp.Message = "denied"
+			}
+		}
+	}
+
+	return resp
+}
+
+// evaluateList queries the JSON context with the JMESPath expression of a foreach list.
+// A list which is not present in the context results in no elements.
+func evaluateList(jmesPath string, ctx context.EvalInterface) ([]interface{}, error) {
+	jmesPath = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(jmesPath), "{{"), "}}")
+	result, err := ctx.Query(jmesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, nil
+	}
+
+	elements, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list, found %T", result)
+	}
+
+	return elements, nil
+}
+
 // matches checks if either the new or old resource satisfies the filter conditions defined in the rule
 func matches(logger logr.Logger, rule kyverno.Rule, ctx *PolicyContext) bool {
 	err := MatchesResourceDescription(ctx.NewResource, rule, ctx.AdmissionInfo, ctx.ExcludeGroupRole, ctx.NamespaceLabels)
@@ -219,14 +438,19 @@ func validatePatterns(log logr.Logger, ctx context.EvalInterface, resource unstr
 	startTime := time.Now()
 	logger := log.WithValues("rule", rule.Name, "name", resource.GetName(), "kind", resource.GetKind())
 	logger.V(5).Info("start processing rule", "startTime", startTime)
-	resp.Name = rule.Name
-	resp.Type = utils.Validation.String()
 	defer func() {
 		resp.RuleStats.ProcessingTime = time.Since(startTime)
 		logger.V(4).Info("finished processing rule", "processingTime", resp.RuleStats.ProcessingTime.String())
 	}()
 
-	validationRule := rule.Validation.DeepCopy()
+	return validateElementPatterns(logger, ctx, resource.Object, rule, rule.Validation.DeepCopy())
+}
+
+// validateElementPatterns validates pattern and anyPattern of the validation declaration against the element
+func validateElementPatterns(logger logr.Logger, ctx context.EvalInterface, element interface{}, rule kyverno.Rule, validationRule *kyverno.Validation) (resp response.RuleResponse) {
+	resp.Name = rule.Name
+	resp.Type = utils.Validation.String()
+
 	if validationRule.Pattern != nil {
 		pattern := validationRule.Pattern
 		var err error
@@ -236,10 +460,10 @@ func validatePatterns(log logr.Logger, ctx context.EvalInterface, resource unstr
 			return resp
 		}
 
-		if path, err := validate.ValidateResourceWithPattern(logger, resource.Object, pattern); err != nil {
+		if path, err := validate.ValidateResourceWithPattern(logger, element, pattern); err != nil {
 			logger.V(3).Info("validation failed", "path", path, "error", err.Error())
 			resp.Success = false
-			resp.Message = buildErrorMessage(rule, path)
+			resp.Message = buildErrorMessage(validationRule.Message, rule.Name, path)
 			return resp
 		}
 
@@ -254,7 +478,7 @@ func validatePatterns(log logr.Logger, ctx context.EvalInterface, resource unstr
 		var failedAnyPatternsErrors []error
 		var err error
 
-		anyPatterns, err := rule.Validation.DeserializeAnyPattern()
+		anyPatterns, err := validationRule.DeserializeAnyPattern()
 		if err != nil {
 			resp.Success = false
 			resp.Message = fmt.Sprintf("failed to deserialize anyPattern, expected type array: %v", err)
@@ -267,7 +491,7 @@ func validatePatterns(log logr.Logger, ctx context.EvalInterface, resource unstr
 				continue
 			}
 
-			path, err := validate.ValidateResourceWithPattern(logger, resource.Object, pattern)
+			path, err := validate.ValidateResourceWithPattern(logger, element, pattern)
 			if err == nil {
 				resp.Success = true
 				resp.Message = fmt.Sprintf("validation rule '%s' anyPattern[%d] passed.", rule.Name, idx)
@@ -293,10 +517,10 @@ func validatePatterns(log logr.Logger, ctx context.EvalInterface, resource unstr
 				errorStr = append(errorStr, err.Error())
 			}
 
-			log.V(4).Info(fmt.Sprintf("Validation rule '%s' failed. %s", rule.Name, errorStr))
+			logger.V(4).Info(fmt.Sprintf("Validation rule '%s' failed. %s", rule.Name, errorStr))
 
 			resp.Success = false
-			resp.Message = buildAnyPatternErrorMessage(rule, errorStr)
+			resp.Message = buildAnyPatternErrorMessage(validationRule.Message, errorStr)
 			return resp
 		}
 	}
@@ -304,27 +528,27 @@ func validatePatterns(log logr.Logger, ctx context.EvalInterface, resource unstr
 	return resp
 }
 
-func buildErrorMessage(rule kyverno.Rule, path string) string {
-	if rule.Validation.Message == "" {
-		return fmt.Sprintf("validation error: rule %s failed at path %s", rule.Name, path)
+func buildErrorMessage(message, ruleName, path string) string {
+	if message == "" {
+		return fmt.Sprintf("validation error: rule %s failed at path %s", ruleName, path)
 	}
 
-	if strings.HasSuffix(rule.Validation.Message, ".") {
-		return fmt.Sprintf("validation error: %s Rule %s failed at path %s", rule.Validation.Message, rule.Name, path)
+	if strings.HasSuffix(message, ".") {
+		return fmt.Sprintf("validation error: %s Rule %s failed at path %s", message, ruleName, path)
 	}
 
-	return fmt.Sprintf("validation error: %s. Rule %s failed at path %s", rule.Validation.Message, rule.Name, path)
+	return fmt.Sprintf("validation error: %s. Rule %s failed at path %s", message, ruleName, path)
 }
 
-func buildAnyPatternErrorMessage(rule kyverno.Rule, errors []string) string {
+func buildAnyPatternErrorMessage(message string, errors []string) string {
 	errStr := strings.Join(errors, " ")
-	if rule.Validation.Message == "" {
+	if message == "" {
 		return fmt.Sprintf("validation error: %s", errStr)
 	}
 
-	if strings.HasSuffix(rule.Validation.Message, ".") {
-		return fmt.Sprintf("validation error: %s %s", rule.Validation.Message, errStr)
+	if strings.HasSuffix(message, ".") {
+		return fmt.Sprintf("validation error: %s %s", message, errStr)
 	}
 
-	return fmt.Sprintf("validation error: %s. %s", rule.Validation.Message, errStr)
+	return fmt.Sprintf("validation error: %s. %s", message, errStr)
 }
@@ -9,8 +9,13 @@ import (
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/resourcecache"
 	utils2 "github.com/kyverno/kyverno/pkg/utils"
 	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamiclister"
+	"k8s.io/client-go/tools/cache"
 )
 
 func TestGetAnchorsFromMap_ThereAreAnchors(t *testing.T) {
@@ -1714,6 +1719,37 @@ func Test_denyFeatureIssue744_BlockDelete(t *testing.T) {
 	}
 }
 
+func Test_denyOldObjectComparison(t *testing.T) {
+	testcases := []testCase{
+		{
+			description:   "Blocks updates that decrease the replicas(success case)",
+			policy:        []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"prevent-scale-down"},"spec":{"validationFailureAction":"enforce","background":false,"rules":[{"name":"replicas-can-only-increase","match":{"resources":{"kinds":["Deployment"]}},"validate":{"message":"replicas cannot be decreased from {{request.oldObject.spec.replicas}}","deny":{"conditions":{"all":[{"key":"{{request.operation}}","operator":"Equals","value":"UPDATE"},{"key":"{{request.object.spec.replicas}}","operator":"LessThan","value":"{{request.oldObject.spec.replicas}}"}]}}}}]}}`),
+			request:       []byte(`{"uid":"4f3b2c1d-6a1e-4b7a-9c5d-2e8f1a0b3c4d","kind":{"group":"apps","version":"v1","kind":"Deployment"},"resource":{"group":"apps","version":"v1","resource":"deployments"},"name":"nginx","namespace":"default","operation":"UPDATE","userInfo":{"username":"kubernetes-admin","groups":["system:masters","system:authenticated"]},"object":{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx","namespace":"default"},"spec":{"replicas":2}},"oldObject":{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx","namespace":"default"},"spec":{"replicas":3}},"dryRun":false}`),
+			userInfo:      []byte(`{"roles":null,"clusterRoles":null,"userInfo":{"username":"kubernetes-admin","groups":["system:masters","system:authenticated"]}}`),
+			requestDenied: true,
+		},
+		{
+			description:   "Allows updates that increase the replicas(failure case)",
+			policy:        []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"prevent-scale-down"},"spec":{"validationFailureAction":"enforce","background":false,"rules":[{"name":"replicas-can-only-increase","match":{"resources":{"kinds":["Deployment"]}},"validate":{"message":"replicas cannot be decreased from {{request.oldObject.spec.replicas}}","deny":{"conditions":{"all":[{"key":"{{request.operation}}","operator":"Equals","value":"UPDATE"},{"key":"{{request.object.spec.replicas}}","operator":"LessThan","value":"{{request.oldObject.spec.replicas}}"}]}}}}]}}`),
+			request:       []byte(`{"uid":"4f3b2c1d-6a1e-4b7a-9c5d-2e8f1a0b3c4d","kind":{"group":"apps","version":"v1","kind":"Deployment"},"resource":{"group":"apps","version":"v1","resource":"deployments"},"name":"nginx","namespace":"default","operation":"UPDATE","userInfo":{"username":"kubernetes-admin","groups":["system:masters","system:authenticated"]},"object":{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx","namespace":"default"},"spec":{"replicas":4}},"oldObject":{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx","namespace":"default"},"spec":{"replicas":3}},"dryRun":false}`),
+			userInfo:      []byte(`{"roles":null,"clusterRoles":null,"userInfo":{"username":"kubernetes-admin","groups":["system:masters","system:authenticated"]}}`),
+			requestDenied: false,
+		},
+		{
+			description:   "Allows create requests without an old object(failure case)",
+			policy:        []byte(`{"apiVersion":"kyverno.io/v1","kind":"ClusterPolicy","metadata":{"name":"prevent-scale-down"},"spec":{"validationFailureAction":"enforce","background":false,"rules":[{"name":"replicas-can-only-increase","match":{"resources":{"kinds":["Deployment"]}},"validate":{"message":"replicas cannot be decreased from {{request.oldObject.spec.replicas}}","deny":{"conditions":{"all":[{"key":"{{request.operation}}","operator":"Equals","value":"UPDATE"},{"key":"{{request.object.spec.replicas}}","operator":"LessThan","value":"{{request.oldObject.spec.replicas}}"}]}}}}]}}`),
+			request:       []byte(`{"uid":"4f3b2c1d-6a1e-4b7a-9c5d-2e8f1a0b3c4d","kind":{"group":"apps","version":"v1","kind":"Deployment"},"resource":{"group":"apps","version":"v1","resource":"deployments"},"name":"nginx","namespace":"default","operation":"CREATE","userInfo":{"username":"kubernetes-admin","groups":["system:masters","system:authenticated"]},"object":{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx","namespace":"default"},"spec":{"replicas":1}},"oldObject":null,"dryRun":false}`),
+			userInfo:      []byte(`{"roles":null,"clusterRoles":null,"userInfo":{"username":"kubernetes-admin","groups":["system:masters","system:authenticated"]}}`),
+			requestDenied: false,
+		},
+	}
+
+	var err error
+	for _, testcase := range testcases {
+		executeTest(t, err, testcase)
+	}
+}
+
 func executeTest(t *testing.T, err error, test testCase) {
 	var policy kyverno.ClusterPolicy
 	err = json.Unmarshal(test.policy, &policy)
@@ -1767,3 +1803,557 @@ func executeTest(t *testing.T, err error, test testCase) {
 		t.Errorf("Testcase has failed, policy: %v", policy.Name)
 	}
 }
+
+func Test_ValidateForEach(t *testing.T) {
+	resourceRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {
+			"name": "test-foreach"
+		},
+		"spec": {
+			"containers": [
+				{
+					"name": "nginx",
+					"image": "registry.corp.com/nginx:1.19"
+				},
+				{
+					"name": "busybox",
+					"image": "docker.io/busybox:latest"
+				}
+			]
+		}
+	}`)
+
+	testcases := []struct {
+		description string
+		foreach     string
+		success     bool
+		message     string
+	}{
+		{
+			description: "pattern passes for all elements",
+			foreach:     `{"list": "request.object.spec.containers", "pattern": {"name": "?*"}}`,
+			success:     true,
+			message:     "validation rule 'validate-containers' passed for 2 elements of request.object.spec.containers.",
+		},
+		{
+			description: "pattern fails for second element",
+			foreach:     `{"list": "request.object.spec.containers", "pattern": {"image": "registry.corp.com/*"}}`,
+			success:     false,
+			message:     "validation failure for request.object.spec.containers[1]: validation error: images must be pulled from the corporate registry. Rule validate-containers failed at path /image/",
+		},
+		{
+			description: "deny fails for second element",
+			foreach:     `{"list": "request.object.spec.containers", "deny": {"conditions": [{"key": "{{element.image}}", "operator": "Equals", "value": "*:latest"}]}}`,
+			success:     false,
+			message:     "validation failure for request.object.spec.containers[1]: images must be pulled from the corporate registry",
+		},
+		{
+			description: "element index is available for substitution",
+			foreach:     `{"list": "request.object.spec.containers", "deny": {"conditions": [{"key": "{{elementIndex}}", "operator": "Equals", "value": 2}]}}`,
+			success:     true,
+			message:     "validation rule 'validate-containers' passed for 2 elements of request.object.spec.containers.",
+		},
+	}
+
+	for _, tc := range testcases {
+		policyRaw := []byte(`{
+			"apiVersion": "kyverno.io/v1",
+			"kind": "ClusterPolicy",
+			"metadata": {"name": "validate-foreach"},
+			"spec": {
+				"rules": [
+					{
+						"name": "validate-containers",
+						"match": {"resources": {"kinds": ["Pod"]}},
+						"validate": {
+							"message": "images must be pulled from the corporate registry",
+							"foreach": ` + tc.foreach + `
+						}
+					}
+				]
+			}
+		}`)
+
+		var policy kyverno.ClusterPolicy
+		err := json.Unmarshal(policyRaw, &policy)
+		assert.NilError(t, err, tc.description)
+		resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+		assert.NilError(t, err, tc.description)
+
+		ctx := context.NewContext()
+		err = ctx.AddResource(resourceRaw)
+		assert.NilError(t, err, tc.description)
+
+		er := Validate(&PolicyContext{Policy: policy, JSONContext: ctx, NewResource: *resourceUnstructured})
+		assert.Equal(t, len(er.PolicyResponse.Rules), 1, tc.description)
+		assert.Equal(t, er.PolicyResponse.Rules[0].Success, tc.success, tc.description)
+		assert.Equal(t, er.PolicyResponse.Rules[0].Message, tc.message, tc.description)
+	}
+}
+
+// configMapResourceCache serves the ConfigMap cache from a static lister
+type configMapResourceCache struct {
+	resourcecache.ResourceCache
+	cache resourcecache.GenericCache
+}
+
+func (c configMapResourceCache) GetGVRCache(resource string) (resourcecache.GenericCache, bool) {
+	return c.cache, resource == "ConfigMap"
+}
+
+type configMapCache struct {
+	resourcecache.GenericCache
+	lister dynamiclister.Lister
+}
+
+func (c configMapCache) Lister() dynamiclister.Lister {
+	return c.lister
+}
+
+func newConfigMapResourceCache(t *testing.T, configMaps ...[]byte) resourcecache.ResourceCache {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, raw := range configMaps {
+		var configMap unstructured.Unstructured
+		assert.NilError(t, configMap.UnmarshalJSON(raw))
+		assert.NilError(t, indexer.Add(&configMap))
+	}
+
+	lister := dynamiclister.New(indexer, schema.GroupVersionResource{Version: "v1", Resource: "configmaps"})
+	return configMapResourceCache{cache: configMapCache{lister: lister}}
+}
+
+func Test_ValidateConfigMapContext(t *testing.T) {
+	configMapRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "ConfigMap",
+		"metadata": {
+			"name": "allowed-registries",
+			"namespace": "kyverno"
+		},
+		"data": {
+			"registries": "[\"ghcr.io\", \"registry.corp.com\"]"
+		}
+	}`)
+
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "restrict-registries"},
+		"spec": {
+			"rules": [
+				{
+					"name": "allowed-registries",
+					"match": {"resources": {"kinds": ["Pod"]}},
+					"context": [
+						{"name": "allowed", "configMap": {"name": "allowed-registries", "namespace": "kyverno"}}
+					],
+					"validate": {
+						"message": "registry {{request.object.metadata.labels.registry}} is not in {{allowed.data.registries}}",
+						"deny": {
+							"conditions": [
+								{"key": "{{request.object.metadata.labels.registry}}", "operator": "NotIn", "value": "{{allowed.data.registries}}"}
+							]
+						}
+					}
+				}
+			]
+		}
+	}`)
+
+	testcases := []struct {
+		registry string
+		success  bool
+	}{
+		{registry: "ghcr.io", success: true},
+		{registry: "docker.io", success: false},
+	}
+
+	for _, tc := range testcases {
+		resourceRaw := []byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "nginx", "labels": {"registry": "` + tc.registry + `"}}}`)
+
+		var policy kyverno.ClusterPolicy
+		err := json.Unmarshal(policyRaw, &policy)
+		assert.NilError(t, err, tc.registry)
+		resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+		assert.NilError(t, err, tc.registry)
+
+		ctx := context.NewContext()
+		err = ctx.AddResource(resourceRaw)
+		assert.NilError(t, err, tc.registry)
+
+		er := Validate(&PolicyContext{
+			Policy:        policy,
+			JSONContext:   ctx,
+			NewResource:   *resourceUnstructured,
+			ResourceCache: newConfigMapResourceCache(t, configMapRaw),
+		})
+		assert.Equal(t, len(er.PolicyResponse.Rules), 1, tc.registry)
+		assert.Equal(t, er.PolicyResponse.Rules[0].Success, tc.success, tc.registry)
+	}
+}
+
+func Test_ValidateDeprecatedAPIs(t *testing.T) {
+	resourceRaw := []byte(`{
+		"apiVersion": "networking.k8s.io/v1beta1",
+		"kind": "Ingress",
+		"metadata": {
+			"name": "test-ingress"
+		},
+		"spec": {
+			"backend": {
+				"serviceName": "test",
+				"servicePort": 80
+			}
+		}
+	}`)
+
+	testcases := []struct {
+		description    string
+		deprecatedAPIs string
+		success        bool
+		message        string
+	}{
+		{
+			description:    "API version is served and not deprecated",
+			deprecatedAPIs: `{"kubernetesVersion": "1.18"}`,
+			success:        true,
+			message:        "validation rule 'check-deprecated-apis' passed.",
+		},
+		{
+			description:    "API version is deprecated",
+			deprecatedAPIs: `{"kubernetesVersion": "1.19"}`,
+			success:        false,
+			message:        "validation error: upgrade the API version. networking.k8s.io/v1beta1 Ingress is deprecated in Kubernetes v1.19 and removed in v1.22, migrate to networking.k8s.io/v1",
+		},
+		{
+			description:    "API version is deprecated but only removed versions are checked",
+			deprecatedAPIs: `{"kubernetesVersion": "1.19", "removedOnly": true}`,
+			success:        true,
+			message:        "validation rule 'check-deprecated-apis' passed.",
+		},
+		{
+			description:    "API version is removed",
+			deprecatedAPIs: `{"kubernetesVersion": "v1.22.0", "removedOnly": true}`,
+			success:        false,
+			message:        "validation error: upgrade the API version. networking.k8s.io/v1beta1 Ingress is deprecated in Kubernetes v1.19 and removed in v1.22, migrate to networking.k8s.io/v1",
+		},
+	}
+
+	for _, tc := range testcases {
+		policyRaw := []byte(`{
+			"apiVersion": "kyverno.io/v1",
+			"kind": "ClusterPolicy",
+			"metadata": {"name": "deprecated-apis"},
+			"spec": {
+				"rules": [
+					{
+						"name": "check-deprecated-apis",
+						"match": {"resources": {"kinds": ["Ingress"]}},
+						"validate": {
+							"message": "upgrade the API version",
+							"deprecatedAPIs": ` + tc.deprecatedAPIs + `
+						}
+					}
+				]
+			}
+		}`)
+
+		var policy kyverno.ClusterPolicy
+		err := json.Unmarshal(policyRaw, &policy)
+		assert.NilError(t, err, tc.description)
+		resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+		assert.NilError(t, err, tc.description)
+
+		er := Validate(&PolicyContext{Policy: policy, NewResource: *resourceUnstructured, JSONContext: context.NewContext()})
+		assert.Equal(t, len(er.PolicyResponse.Rules), 1, tc.description)
+		assert.Equal(t, er.PolicyResponse.Rules[0].Success, tc.success, tc.description)
+		assert.Equal(t, er.PolicyResponse.Rules[0].Message, tc.message, tc.description)
+	}
+}
+
+func Test_ValidateNetworkPolicyCIDRs(t *testing.T) {
+	resourceRaw := []byte(`{
+		"apiVersion": "networking.k8s.io/v1",
+		"kind": "NetworkPolicy",
+		"metadata": {
+			"name": "allow-ingress"
+		},
+		"spec": {
+			"podSelector": {},
+			"ingress": [
+				{"from": [{"ipBlock": {"cidr": "10.10.0.0/16"}}]},
+				{"from": [{"ipBlock": {"cidr": "0.0.0.0/0"}}]}
+			]
+		}
+	}`)
+
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "restrict-cidrs"},
+		"spec": {
+			"rules": [
+				{
+					"name": "approved-cidrs",
+					"match": {"resources": {"kinds": ["NetworkPolicy"]}},
+					"validate": {
+						"message": "CIDR {{element}} is not within 10.0.0.0/8",
+						"foreach": {
+							"list": "request.object.spec.ingress[].from[].ipBlock.cidr",
+							"deny": {
+								"conditions": [
+									{"key": "{{ cidr_contains('10.0.0.0/8', element) }}", "operator": "Equals", "value": false}
+								]
+							}
+						}
+					}
+				}
+			]
+		}
+	}`)
+
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal(policyRaw, &policy)
+	assert.NilError(t, err)
+	resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+	assert.NilError(t, err)
+
+	ctx := context.NewContext()
+	err = ctx.AddResource(resourceRaw)
+	assert.NilError(t, err)
+
+	er := Validate(&PolicyContext{Policy: policy, JSONContext: ctx, NewResource: *resourceUnstructured})
+	assert.Equal(t, len(er.PolicyResponse.Rules), 1)
+	assert.Assert(t, !er.PolicyResponse.Rules[0].Success)
+	assert.Equal(t, er.PolicyResponse.Rules[0].Message,
+		"validation failure for request.object.spec.ingress[].from[].ipBlock.cidr[1]: CIDR 0.0.0.0/0 is not within 10.0.0.0/8")
+}
+
+func Test_ValidateStringFunctions(t *testing.T) {
+	resourceRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {
+			"name": "nginx",
+			"annotations": {"team": "cGF5bWVudHM="}
+		},
+		"spec": {
+			"containers": [
+				{"name": "nginx", "image": "ghcr.io/kyverno/nginx:1.21"},
+				{"name": "sidecar", "image": "docker.io/library/busybox:latest"}
+			]
+		}
+	}`)
+
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "restrict-images"},
+		"spec": {
+			"rules": [
+				{
+					"name": "approved-registries",
+					"match": {"resources": {"kinds": ["Pod"]}},
+					"preconditions": [
+						{"key": "{{ to_upper(base64_decode(request.object.metadata.annotations.team)) }}", "operator": "Equals", "value": "PAYMENTS"}
+					],
+					"validate": {
+						"message": "image {{element.image}} is not from ghcr.io",
+						"foreach": {
+							"list": "request.object.spec.containers",
+							"deny": {
+								"conditions": [
+									{"key": "{{ pattern_match('ghcr.io/*', element.image) }}", "operator": "Equals", "value": false}
+								]
+							}
+						}
+					}
+				}
+			]
+		}
+	}`)
+
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal(policyRaw, &policy)
+	assert.NilError(t, err)
+	resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+	assert.NilError(t, err)
+
+	ctx := context.NewContext()
+	err = ctx.AddResource(resourceRaw)
+	assert.NilError(t, err)
+
+	er := Validate(&PolicyContext{Policy: policy, JSONContext: ctx, NewResource: *resourceUnstructured})
+	assert.Equal(t, len(er.PolicyResponse.Rules), 1)
+	assert.Assert(t, !er.PolicyResponse.Rules[0].Success)
+	assert.Equal(t, er.PolicyResponse.Rules[0].Message,
+		"validation failure for request.object.spec.containers[1]: image docker.io/library/busybox:latest is not from ghcr.io")
+}
+
+func Test_StrictVariables(t *testing.T) {
+	resourceRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {"name": "nginx"},
+		"spec": {"containers": [{"name": "nginx", "image": "nginx"}]}
+	}`)
+
+	testcases := []struct {
+		description string
+		spec        string
+		success     bool
+		message     string
+	}{
+		{
+			description: "unresolved variable in deny conditions of an audit policy",
+			spec:        `"validationFailureAction": "audit"`,
+			success:     true,
+		},
+		{
+			description: "unresolved variable in deny conditions of an enforce policy",
+			spec:        `"validationFailureAction": "enforce"`,
+			success:     false,
+			message:     "variable substitution failed for rule require-team: variable request.object.metadata.labels.team not resolved at path /0/key",
+		},
+		{
+			description: "strict variables disabled for an enforce policy",
+			spec:        `"validationFailureAction": "enforce", "strictVariables": false`,
+			success:     true,
+		},
+		{
+			description: "strict variables enabled for an audit policy",
+			spec:        `"validationFailureAction": "audit", "strictVariables": true`,
+			success:     false,
+			message:     "variable substitution failed for rule require-team: variable request.object.metadata.labels.team not resolved at path /0/key",
+		},
+	}
+
+	for _, testcase := range testcases {
+		policyRaw := []byte(`{
+			"apiVersion": "kyverno.io/v1",
+			"kind": "ClusterPolicy",
+			"metadata": {"name": "require-team"},
+			"spec": {
+				` + testcase.spec + `,
+				"rules": [
+					{
+						"name": "require-team",
+						"match": {"resources": {"kinds": ["Pod"]}},
+						"validate": {
+							"message": "the team label must not be empty",
+							"deny": {
+								"conditions": [
+									{"key": "{{ request.object.metadata.labels.team }}", "operator": "Equals", "value": ""}
+								]
+							}
+						}
+					}
+				]
+			}
+		}`)
+
+		var policy kyverno.ClusterPolicy
+		err := json.Unmarshal(policyRaw, &policy)
+		assert.NilError(t, err, testcase.description)
+		resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+		assert.NilError(t, err, testcase.description)
+
+		ctx := context.NewContext()
+		err = ctx.AddResource(resourceRaw)
+		assert.NilError(t, err, testcase.description)
+
+		er := Validate(&PolicyContext{Policy: policy, JSONContext: ctx, NewResource: *resourceUnstructured})
+		assert.Equal(t, len(er.PolicyResponse.Rules), 1, testcase.description)
+		assert.Equal(t, er.PolicyResponse.Rules[0].Success, testcase.success, testcase.description)
+		if testcase.message != "" {
+			assert.Equal(t, er.PolicyResponse.Rules[0].Message, testcase.message, testcase.description)
+		}
+	}
+}
+
+func Test_RuleSeverity(t *testing.T) {
+	resourceRaw := []byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "nginx"}, "spec": {"containers": [{"name": "nginx", "image": "nginx"}]}}`)
+
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+			"name": "require-labels",
+			"annotations": {"policies.kyverno.io/severity": "medium"}
+		},
+		"spec": {
+			"rules": [
+				{
+					"name": "require-team",
+					"severity": "high",
+					"match": {"resources": {"kinds": ["Pod"]}},
+					"validate": {"message": "the team label is required", "pattern": {"metadata": {"labels": {"team": "?*"}}}}
+				},
+				{
+					"name": "require-app",
+					"match": {"resources": {"kinds": ["Pod"]}},
+					"validate": {"message": "the app label is required", "pattern": {"metadata": {"labels": {"app": "?*"}}}}
+				}
+			]
+		}
+	}`)
+
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal(policyRaw, &policy)
+	assert.NilError(t, err)
+	resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+	assert.NilError(t, err)
+
+	ctx := context.NewContext()
+	err = ctx.AddResource(resourceRaw)
+	assert.NilError(t, err)
+
+	er := Validate(&PolicyContext{Policy: policy, JSONContext: ctx, NewResource: *resourceUnstructured})
+	assert.Equal(t, len(er.PolicyResponse.Rules), 2)
+	assert.Equal(t, er.PolicyResponse.Rules[0].Severity, "high")
+	assert.Equal(t, er.PolicyResponse.Rules[1].Severity, "medium")
+}
+
+func Test_RuleReportProperties(t *testing.T) {
+	resourceRaw := []byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "nginx"}, "spec": {"containers": [{"name": "nginx", "image": "nginx:latest"}]}}`)
+
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "disallow-latest-tag"},
+		"spec": {
+			"rules": [
+				{
+					"name": "validate-image-tag",
+					"reportProperties": {
+						"image": "{{ request.object.spec.containers[0].image }}",
+						"remediation": "https://kyverno.io/policies/best-practices/disallow_latest_tag/",
+						"unresolved": "{{ request.object.spec.unknown(@) }}"
+					},
+					"match": {"resources": {"kinds": ["Pod"]}},
+					"validate": {"message": "the latest tag is not allowed", "pattern": {"spec": {"containers": [{"image": "!*:latest"}]}}}
+				}
+			]
+		}
+	}`)
+
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal(policyRaw, &policy)
+	assert.NilError(t, err)
+	resourceUnstructured, err := utils.ConvertToUnstructured(resourceRaw)
+	assert.NilError(t, err)
+
+	ctx := context.NewContext()
+	err = ctx.AddResource(resourceRaw)
+	assert.NilError(t, err)
+
+	er := Validate(&PolicyContext{Policy: policy, JSONContext: ctx, NewResource: *resourceUnstructured})
+	assert.Equal(t, len(er.PolicyResponse.Rules), 1)
+	assert.Equal(t, er.PolicyResponse.Rules[0].Success, false)
+	assert.DeepEqual(t, er.PolicyResponse.Rules[0].Properties, map[string]string{
+		"image":       "nginx:latest",
+		"remediation": "https://kyverno.io/policies/best-practices/disallow_latest_tag/",
+	})
+}
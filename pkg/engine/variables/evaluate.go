@@ -1,6 +1,8 @@
 package variables
 
 import (
+	"fmt"
+
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/engine/context"
@@ -28,6 +30,52 @@ func EvaluateConditions(log logr.Logger, ctx context.EvalInterface, conditions i
 	return false
 }
 
+// SubstituteAllInConditions substitutes the variables of the keys and values of the conditions,
+// it fails on the first variable which cannot be resolved
+func SubstituteAllInConditions(log logr.Logger, ctx context.EvalInterface, conditions interface{}) (interface{}, error) {
+	switch typedConditions := conditions.(type) {
+	case kyverno.AnyAllConditions:
+		anyConditions, err := substituteAllInConditionList(log, ctx, typedConditions.AnyConditions, "/any")
+		if err != nil {
+			return nil, err
+		}
+
+		allConditions, err := substituteAllInConditionList(log, ctx, typedConditions.AllConditions, "/all")
+		if err != nil {
+			return nil, err
+		}
+
+		return kyverno.AnyAllConditions{AnyConditions: anyConditions, AllConditions: allConditions}, nil
+	case []kyverno.Condition: // backwards compatibility
+		return substituteAllInConditionList(log, ctx, typedConditions, "")
+	}
+
+	return conditions, nil
+}
+
+func substituteAllInConditionList(log logr.Logger, ctx context.EvalInterface, conditions []kyverno.Condition, path string) ([]kyverno.Condition, error) {
+	if conditions == nil {
+		return nil, nil
+	}
+
+	substituted := make([]kyverno.Condition, len(conditions))
+	for i, condition := range conditions {
+		key, err := subVars(log, ctx, condition.Key, fmt.Sprintf("%s/%d/key", path, i))
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := subVars(log, ctx, condition.Value, fmt.Sprintf("%s/%d/value", path, i))
+		if err != nil {
+			return nil, err
+		}
+
+		substituted[i] = kyverno.Condition{Key: key, Operator: condition.Operator, Value: value}
+	}
+
+	return substituted, nil
+}
+
 //evaluateAnyAllConditions evaluates multiple conditions as a logical AND (all) or OR (any) operation depending on the conditions
 func evaluateAnyAllConditions(log logr.Logger, ctx context.EvalInterface, conditions kyverno.AnyAllConditions) bool {
 	anyConditions, allConditions := conditions.AnyConditions, conditions.AllConditions
@@ -102,6 +102,149 @@ func Test_Eval_GreaterThanOrEquals_Const_string_Fail(t *testing.T) {
 	}
 }
 
+func Test_Eval_GreaterThanOrEquals_Const_Quantity_Pass(t *testing.T) {
+	ctx := context.NewContext()
+	condition := kyverno.Condition{
+		Key:      "2048Mi",
+		Operator: kyverno.GreaterThanOrEquals,
+		Value:    "2Gi",
+	}
+	if !Evaluate(log.Log, ctx, condition) {
+		t.Error("expected to pass")
+	}
+}
+
+func Test_Eval_LessThan_Const_Quantity_Fail(t *testing.T) {
+	ctx := context.NewContext()
+	condition := kyverno.Condition{
+		Key:      "2Gi",
+		Operator: kyverno.LessThan,
+		Value:    2147483648,
+	}
+	if Evaluate(log.Log, ctx, condition) {
+		t.Error("expected to fail")
+	}
+}
+
+func Test_Eval_LessThanOrEquals_Variable_Quantity_Pass(t *testing.T) {
+	resourceRaw := []byte(`{"spec":{"containers":[{"resources":{"limits":{"memory":"1536Mi"}}}]}}`)
+	ctx := context.NewContext()
+	if err := ctx.AddResource(resourceRaw); err != nil {
+		t.Fatal(err)
+	}
+	condition := kyverno.Condition{
+		Key:      "{{request.object.spec.containers[0].resources.limits.memory}}",
+		Operator: kyverno.LessThanOrEquals,
+		Value:    "2Gi",
+	}
+	if !Evaluate(log.Log, ctx, condition) {
+		t.Error("expected to pass")
+	}
+}
+
+func Test_Eval_GreaterThanOrEquals_Const_Semver_Pass(t *testing.T) {
+	ctx := context.NewContext()
+	condition := kyverno.Condition{
+		Key:      "1.21.10",
+		Operator: kyverno.GreaterThanOrEquals,
+		Value:    "1.21.9",
+	}
+	if !Evaluate(log.Log, ctx, condition) {
+		t.Error("expected to pass")
+	}
+}
+
+func Test_Eval_LessThan_Const_Semver_Fail(t *testing.T) {
+	ctx := context.NewContext()
+	condition := kyverno.Condition{
+		Key:      "v1.22.0",
+		Operator: kyverno.LessThan,
+		Value:    "1.21.0",
+	}
+	if Evaluate(log.Log, ctx, condition) {
+		t.Error("expected to fail")
+	}
+}
+
+func Test_Eval_LessThan_Const_Semver_NotSemver_Fail(t *testing.T) {
+	ctx := context.NewContext()
+	condition := kyverno.Condition{
+		Key:      "1.21.0",
+		Operator: kyverno.LessThan,
+		Value:    "latest",
+	}
+	if Evaluate(log.Log, ctx, condition) {
+		t.Error("expected to fail")
+	}
+}
+
+func Test_Eval_LessThan_Const_Duration_Pass(t *testing.T) {
+	ctx := context.NewContext()
+	condition := kyverno.Condition{
+		Key:      "90m",
+		Operator: kyverno.LessThan,
+		Value:    "2h",
+	}
+	if !Evaluate(log.Log, ctx, condition) {
+		t.Error("expected to pass")
+	}
+}
+
+func Test_Eval_LessThan_Variable_TimeDiff_Pass(t *testing.T) {
+	resourceRaw := []byte(`
+	{
+		"metadata": {
+			"name": "test-certificate"
+		},
+		"status": {
+			"notBefore": "2021-01-01T00:00:00Z",
+			"notAfter": "2021-01-20T00:00:00+02:00"
+		}
+	}`)
+
+	ctx := context.NewContext()
+	err := ctx.AddResource(resourceRaw)
+	if err != nil {
+		t.Error(err)
+	}
+
+	condition := kyverno.Condition{
+		Key:      "{{ time_diff(request.object.status.notBefore, request.object.status.notAfter) }}",
+		Operator: kyverno.LessThan,
+		Value:    "720h",
+	}
+	if !Evaluate(log.Log, ctx, condition) {
+		t.Error("expected to pass")
+	}
+}
+
+func Test_Eval_Equal_Variable_TimeBefore_Pass(t *testing.T) {
+	resourceRaw := []byte(`
+	{
+		"metadata": {
+			"name": "test-certificate"
+		},
+		"status": {
+			"notAfter": "2021-01-20T00:00:00Z"
+		}
+	}`)
+
+	ctx := context.NewContext()
+	err := ctx.AddResource(resourceRaw)
+	if err != nil {
+		t.Error(err)
+	}
+
+	condition := kyverno.Condition{
+		Key:      "{{ time_before(request.object.status.notAfter, time_add('2021-01-01T00:00:00Z', '720h')) }}",
+		Operator: kyverno.Equal,
+		Value:    true,
+	}
+	if !Evaluate(log.Log, ctx, condition) {
+		t.Error("expected to pass")
+	}
+}
+
 func Test_Eval_GreaterThan_Const_string_Equal_Fail(t *testing.T) {
 	ctx := context.NewContext()
 	condition := kyverno.Condition{
@@ -3,13 +3,19 @@ package operator
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/common"
 	"github.com/kyverno/kyverno/pkg/engine/context"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
 )
 
 //NewNumericOperatorHandler returns handler to manage the provided numeric operations (>, >=, <=, <)
+// Resource quantities such as "2Gi" or "500m" are compared by their value and
+// semantic versions such as "1.21.0" are compared by their precedence and
+// durations such as "720h" are compared by their length.
 func NewNumericOperatorHandler(log logr.Logger, ctx context.EvalInterface, subHandler VariableSubstitutionHandler, op kyverno.ConditionOperator) OperatorHandler {
 	return NumericOperatorHandler{
 		ctx:        ctx,
@@ -44,13 +50,89 @@ func compareByCondition(key float64, value float64, op kyverno.ConditionOperator
 	}
 }
 
+// compareQuantityByCondition compares two resource quantities on the basis of the provided operator
+func compareQuantityByCondition(key, value apiresource.Quantity, op kyverno.ConditionOperator, log *logr.Logger) bool {
+	return compareByCondition(float64(key.Cmp(value)), 0, op, log)
+}
+
+// parseQuantity converts a numeric or string value to a resource quantity
+func parseQuantity(value interface{}) (apiresource.Quantity, error) {
+	switch typed := value.(type) {
+	case int:
+		return *apiresource.NewQuantity(int64(typed), apiresource.DecimalSI), nil
+	case int64:
+		return *apiresource.NewQuantity(typed, apiresource.DecimalSI), nil
+	case float64:
+		return apiresource.ParseQuantity(strconv.FormatFloat(typed, 'f', -1, 64))
+	case string:
+		return apiresource.ParseQuantity(typed)
+	default:
+		return apiresource.Quantity{}, fmt.Errorf("could not convert %v of type %T to quantity", value, value)
+	}
+}
+
+// validateValueWithQuantity compares key and value as resource quantities (e.g. "2Gi" and "2048Mi")
+func (noh NumericOperatorHandler) validateValueWithQuantity(key, value interface{}) bool {
+	keyQuan, err := parseQuantity(key)
+	if err != nil {
+		noh.log.Error(err, "Failed to parse quantity from the key", "key", key)
+		return false
+	}
+	valueQuan, err := parseQuantity(value)
+	if err != nil {
+		noh.log.Error(err, "Failed to parse quantity from the value", "value", value)
+		return false
+	}
+	return compareQuantityByCondition(keyQuan, valueQuan, noh.condition, &noh.log)
+}
+
+// validateValueWithSemver compares key and value as semantic versions (e.g. "1.21.0")
+func (noh NumericOperatorHandler) validateValueWithSemver(key string, value interface{}) bool {
+	if !common.IsSemver(value) {
+		noh.log.Info("Expected a semantic version", "value", value, "type", fmt.Sprintf("%T", value))
+		return false
+	}
+
+	result, err := common.CompareSemver(key, value.(string))
+	if err != nil {
+		noh.log.Error(err, "Failed to compare semantic versions", "key", key, "value", value)
+		return false
+	}
+	return compareByCondition(float64(result), 0, noh.condition, &noh.log)
+}
+
+// parseDuration converts a string value with a unit (e.g. "30s") to a duration
+func parseDuration(value interface{}) (time.Duration, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+
+	d, err := time.ParseDuration(str)
+	return d, err == nil
+}
+
+// validateValueWithDuration compares key and value as durations (e.g. "720h"), ok is false if either is not a duration
+func (noh NumericOperatorHandler) validateValueWithDuration(key string, value interface{}) (result bool, ok bool) {
+	keyDuration, ok := parseDuration(key)
+	if !ok {
+		return false, false
+	}
+	valueDuration, ok := parseDuration(value)
+	if !ok {
+		return false, false
+	}
+	return compareByCondition(float64(keyDuration), float64(valueDuration), noh.condition, &noh.log), true
+}
+
 func (noh NumericOperatorHandler) Evaluate(key, value interface{}) bool {
-	if key, err := noh.subHandler(noh.log, noh.ctx, key); err != nil {
+	var err error
+	if key, err = noh.subHandler(noh.log, noh.ctx, key); err != nil {
 		// Failed to resolve the variable
 		noh.log.Error(err, "Failed to resolve variable", "variable", key)
 		return false
 	}
-	if value, err := noh.subHandler(noh.log, noh.ctx, value); err != nil {
+	if value, err = noh.subHandler(noh.log, noh.ctx, value); err != nil {
 		// Failed to resolve the variable
 		noh.log.Error(err, "Failed to resolve variable", "variable", value)
 		return false
@@ -64,6 +146,12 @@ func (noh NumericOperatorHandler) Evaluate(key, value interface{}) bool {
 	case float64:
 		return noh.validateValueWithFloatPattern(typedKey, value)
 	case string:
+		if common.IsSemver(typedKey) {
+			return noh.validateValueWithSemver(typedKey, value)
+		}
+		if result, ok := noh.validateValueWithDuration(typedKey, value); ok {
+			return result
+		}
 		return noh.validateValueWithStringPattern(typedKey, value)
 	default:
 		noh.log.Info("Unsupported type", "value", typedKey, "type", fmt.Sprintf("%T", typedKey))
@@ -89,8 +177,7 @@ func (noh NumericOperatorHandler) validateValueWithIntPattern(key int64, value i
 		if err == nil {
 			return compareByCondition(float64(key), float64(int64val), noh.condition, &noh.log)
 		}
-		noh.log.Error(fmt.Errorf("Parse Error: "), "Failed to parse both float64 and int64 from the string value")
-		return false
+		return noh.validateValueWithQuantity(key, typedValue)
 	default:
 		noh.log.Info("Expected type int", "value", value, "type", fmt.Sprintf("%T", value))
 		return false
@@ -114,8 +201,7 @@ func (noh NumericOperatorHandler) validateValueWithFloatPattern(key float64, val
 		if err == nil {
 			return compareByCondition(key, float64(int64val), noh.condition, &noh.log)
 		}
-		noh.log.Error(fmt.Errorf("Parse Error: "), "Failed to parse both float64 and int64 from the string value")
-		return false
+		return noh.validateValueWithQuantity(key, typedValue)
 	default:
 		noh.log.Info("Expected type float", "value", value, "type", fmt.Sprintf("%T", value))
 		return false
@@ -133,8 +219,8 @@ func (noh NumericOperatorHandler) validateValueWithStringPattern(key string, val
 	if err == nil {
 		return noh.validateValueWithIntPattern(int64key, value)
 	}
-	noh.log.Error(fmt.Errorf("Parse Error: "), "Failed to parse both float64 and int64 from the string keyt")
-	return false
+	// extracting a resource quantity (e.g. "2Gi") because numeric extraction failed
+	return noh.validateValueWithQuantity(key, value)
 }
 
 // the following functions are unreachable because the key is strictly supposed to be numeric
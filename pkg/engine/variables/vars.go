@@ -116,6 +116,13 @@ func subValR(log logr.Logger, ctx context.EvalInterface, valuePattern string, pa
 					return substitutedVar, nil
 				}
 
+				// numbers and booleans can be embedded in strings, e.g. the index in a JSON patch path
+				switch substitutedVar.(type) {
+				case float64, int, int64, bool:
+					valuePattern = strings.Replace(valuePattern, v, fmt.Sprint(substitutedVar), -1)
+					continue
+				}
+
 				return nil, fmt.Errorf("failed to resolve %v at path %s", variable, path)
 			}
 
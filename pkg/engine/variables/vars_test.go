@@ -203,6 +203,15 @@ func Test_SubstituteRecursive(t *testing.T) {
 	}
 }
 
+func Test_SubstituteNumberInString(t *testing.T) {
+	ctx := context.NewContext()
+	assert.Assert(t, ctx.AddJSON([]byte(`{"elementIndex": 2}`)))
+
+	results, err := subValR(log.Log, ctx, "/spec/containers/{{elementIndex}}/image", "/")
+	assert.NilError(t, err)
+	assert.Equal(t, results, "/spec/containers/2/image")
+}
+
 func Test_policyContextValidation(t *testing.T) {
 	policyContext := []byte(`
 	{
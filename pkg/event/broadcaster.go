@@ -0,0 +1,152 @@
+package event
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned/scheme"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	v1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/tools/reference"
+	"k8s.io/klog/v2"
+)
+
+// broadcaster is shared by the recorders of all the event sources
+type broadcaster interface {
+	// StartRecordingToSink starts sending the recorded events to the API server
+	StartRecordingToSink(stopCh <-chan struct{})
+	// NewRecorder returns the recorder of the events generated by the source
+	NewRecorder(source Source) events.EventRecorder
+}
+
+// structuredBroadcaster records the events with the events.k8s.io/v1 API, the isomorphic events
+// are recorded as an event series. The events are correlated as the events of the core v1 API.
+type structuredBroadcaster struct {
+	broadcaster events.EventBroadcaster
+	correlator  *record.EventCorrelator
+}
+
+func (b *structuredBroadcaster) StartRecordingToSink(stopCh <-chan struct{}) {
+	stopWatcher := b.broadcaster.StartEventWatcher(func(obj runtime.Object) {
+		if event, ok := obj.(*eventsv1.Event); ok {
+			klog.V(5).Infof("Event(%#v): type: '%v' reason: '%v' %v", event.Regarding, event.Type, event.Reason, event.Note)
+		}
+	})
+	b.broadcaster.StartRecordingToSink(stopCh)
+	go func() {
+		<-stopCh
+		stopWatcher()
+	}()
+}
+
+func (b *structuredBroadcaster) NewRecorder(source Source) events.EventRecorder {
+	return &correlatingRecorder{
+		recorder:   b.broadcaster.NewRecorder(scheme.Scheme, source.String()),
+		correlator: b.correlator,
+		source:     source,
+	}
+}
+
+// correlatingRecorder applies the correlation of the core v1 events to the events.k8s.io/v1 events:
+// the similar events of an object are combined, and the events of an object are throttled by the
+// spam filter of the correlator
+type correlatingRecorder struct {
+	recorder   events.EventRecorder
+	correlator *record.EventCorrelator
+	source     Source
+}
+
+func (r *correlatingRecorder) Eventf(regarding runtime.Object, related runtime.Object, eventtype, reason, action, note string, args ...interface{}) {
+	message := fmt.Sprintf(note, args...)
+	ref, err := reference.GetReference(scheme.Scheme, regarding)
+	if err == nil {
+		result, err := r.correlator.EventCorrelate(&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: ref.Namespace},
+			InvolvedObject: *ref,
+			Source:         v1.EventSource{Component: r.source.String()},
+			Type:           eventtype,
+			Reason:         reason,
+			Message:        message,
+			Count:          1,
+		})
+		if err == nil {
+			if result.Skip {
+				return
+			}
+			message = result.Event.Message
+		}
+	}
+
+	r.recorder.Eventf(regarding, related, eventtype, reason, action, "%s", message)
+}
+
+// legacyBroadcaster records the events with the core v1 API, when the events.k8s.io/v1 API is
+// not served by the cluster. The action and the related object of the events are not recorded.
+type legacyBroadcaster struct {
+	broadcaster record.EventBroadcaster
+	sink        record.EventSink
+}
+
+func (b *legacyBroadcaster) StartRecordingToSink(stopCh <-chan struct{}) {
+	b.broadcaster.StartLogging(klog.V(5).Infof)
+	b.broadcaster.StartRecordingToSink(b.sink)
+	go func() {
+		<-stopCh
+		b.broadcaster.Shutdown()
+	}()
+}
+
+func (b *legacyBroadcaster) NewRecorder(source Source) events.EventRecorder {
+	return record.NewEventRecorderAdapter(b.broadcaster.NewRecorder(
+		scheme.Scheme,
+		v1.EventSource{Component: source.String()}))
+}
+
+// correlatorOptions configures the correlation of the recorded events: the identical events
+// are recorded as a single event with an incrementing count, and the similar events of an
+// object, e.g. the violations of different rules, are combined once they exceed the maximum
+// number of events in the interval
+func correlatorOptions() record.CorrelatorOptions {
+	return record.CorrelatorOptions{
+		LRUCacheSize:         eventCacheSize,
+		MaxEvents:            maxSimilarEvents,
+		MaxIntervalInSeconds: similarEventsInterval,
+	}
+}
+
+// initBroadcaster returns the broadcaster of the events.k8s.io/v1 API, with a fallback to
+// the core v1 API
+func initBroadcaster(client *client.Client, log logr.Logger) broadcaster {
+	// Initliaze Event Broadcaster
+	err := scheme.AddToScheme(scheme.Scheme)
+	if err != nil {
+		log.Error(err, "failed to add to scheme")
+		return nil
+	}
+
+	if eventsInterface, err := client.GetStructuredEventsInterface(); err == nil {
+		log.V(2).Info("recording events with the events.k8s.io/v1 API")
+		return &structuredBroadcaster{
+			broadcaster: events.NewBroadcaster(&events.EventSinkImpl{Interface: eventsInterface}),
+			correlator:  record.NewEventCorrelatorWithOptions(correlatorOptions()),
+		}
+	}
+
+	eventInterface, err := client.GetEventsInterface()
+	if err != nil {
+		log.Error(err, "failed to get event interface for logging")
+		return nil
+	}
+
+	log.V(2).Info("recording events with the core v1 API")
+	return &legacyBroadcaster{
+		broadcaster: record.NewBroadcasterWithCorrelatorOptions(correlatorOptions()),
+		sink:        &typedcorev1.EventSinkImpl{Interface: eventInterface},
+	}
+}
@@ -0,0 +1,103 @@
+package event
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+)
+
+type fakeRecorder struct {
+	notes []string
+}
+
+func (r *fakeRecorder) Eventf(regarding runtime.Object, related runtime.Object, eventtype, reason, action, note string, args ...interface{}) {
+	r.notes = append(r.notes, fmt.Sprintf(note, args...))
+}
+
+type fakeSink struct {
+	mutex  sync.Mutex
+	events []*v1.Event
+}
+
+func (s *fakeSink) Create(event *v1.Event) (*v1.Event, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.events = append(s.events, event)
+	return event, nil
+}
+
+func (s *fakeSink) Update(event *v1.Event) (*v1.Event, error) {
+	return s.Create(event)
+}
+
+func (s *fakeSink) Patch(event *v1.Event, data []byte) (*v1.Event, error) {
+	return s.Create(event)
+}
+
+func (s *fakeSink) messages() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var messages []string
+	for _, event := range s.events {
+		messages = append(messages, event.Message)
+	}
+	return messages
+}
+
+func newTestPod() *unstructured.Unstructured {
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace("default")
+	pod.SetName("pod1")
+	return pod
+}
+
+func Test_StructuredRecorder_CorrelatesSimilarEvents(t *testing.T) {
+	fake := &fakeRecorder{}
+	recorder := &correlatingRecorder{
+		recorder:   fake,
+		correlator: record.NewEventCorrelatorWithOptions(correlatorOptions()),
+		source:     AdmissionController,
+	}
+
+	for i := 0; i <= maxSimilarEvents; i++ {
+		recorder.Eventf(newTestPod(), nil, v1.EventTypeWarning, PolicyViolation.String(), "Resource Blocked", "policy require-labels/rule-%d fail", i)
+	}
+
+	// the similar events are combined once they exceed the maximum number of events, as the core v1 events
+	assert.Equal(t, len(fake.notes), maxSimilarEvents+1)
+	assert.Equal(t, fake.notes[0], "policy require-labels/rule-0 fail")
+	assert.Equal(t, fake.notes[maxSimilarEvents], "(combined from similar events): policy require-labels/rule-10 fail")
+}
+
+func Test_LegacyBroadcaster_CorrelatesSimilarEvents(t *testing.T) {
+	sink := &fakeSink{}
+	b := &legacyBroadcaster{
+		broadcaster: record.NewBroadcasterWithCorrelatorOptions(correlatorOptions()),
+		sink:        sink,
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	b.StartRecordingToSink(stopCh)
+
+	recorder := b.NewRecorder(AdmissionController)
+	for i := 0; i <= maxSimilarEvents; i++ {
+		recorder.Eventf(newTestPod(), nil, v1.EventTypeWarning, PolicyViolation.String(), "Resource Blocked", "policy require-labels/rule-%d fail", i)
+	}
+
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return len(sink.messages()) == maxSimilarEvents+1, nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, sink.messages()[maxSimilarEvents], "(combined from similar events): policy require-labels/rule-10 fail")
+}
@@ -1,25 +1,25 @@
 package event
 
 import (
+	"context"
 	"time"
 
 	"github.com/go-logr/logr"
-	"github.com/kyverno/kyverno/pkg/client/clientset/versioned/scheme"
 	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1"
 	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
 	client "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/metrics"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
+	"golang.org/x/time/rate"
 	v1 "k8s.io/api/core/v1"
 	errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
-	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog/v2"
 )
 
 //Generator generate events
@@ -29,16 +29,26 @@ type Generator struct {
 	pLister kyvernolister.ClusterPolicyLister
 	// returns true if the cluster policy store has been synced at least once
 	pSynced cache.InformerSynced
-	// queue to store event generation requests
+	// queue to store event generation requests, the identical requests which are pending
+	// are batched in a single event
 	queue workqueue.RateLimitingInterface
+	// limiter throttles the recording of events, so that a misbehaving policy does not
+	// flood the API server with events
+	limiter *rate.Limiter
+	// dropped counts the events which were not recorded, it is not set when the metrics are disabled
+	dropped *metrics.DroppedEvents
+	// broadcaster sends the events of all the recorders to the API server
+	broadcaster broadcaster
 	// events generated at policy controller
-	policyCtrRecorder record.EventRecorder
+	policyCtrRecorder events.EventRecorder
 	// events generated at admission control
-	admissionCtrRecorder record.EventRecorder
+	admissionCtrRecorder events.EventRecorder
 	// events generated at namespaced policy controller to process 'generate' rule
-	genPolicyRecorder record.EventRecorder
-	resCache          resourcecache.ResourceCache
-	log               logr.Logger
+	genPolicyRecorder events.EventRecorder
+	// sinks receive the events in addition to the event recorders, e.g. the notifiers
+	sinks    []Interface
+	resCache resourcecache.ResourceCache
+	log      logr.Logger
 }
 
 //Interface to generate event
@@ -47,18 +57,31 @@ type Interface interface {
 }
 
 //NewEventGenerator to generate a new event controller
-func NewEventGenerator(client *client.Client, pInformer kyvernoinformer.ClusterPolicyInformer, resCache resourcecache.ResourceCache, log logr.Logger) *Generator {
+func NewEventGenerator(client *client.Client,
+	pInformer kyvernoinformer.ClusterPolicyInformer,
+	resCache resourcecache.ResourceCache,
+	qps float64,
+	burst int,
+	dropped *metrics.DroppedEvents,
+	log logr.Logger) *Generator {
+	broadcaster := initBroadcaster(client, log)
 
 	gen := Generator{
-		client:               client,
-		pLister:              pInformer.Lister(),
-		queue:                workqueue.NewNamedRateLimitingQueue(rateLimiter(), eventWorkQueueName),
-		pSynced:              pInformer.Informer().HasSynced,
-		policyCtrRecorder:    initRecorder(client, PolicyController, log),
-		admissionCtrRecorder: initRecorder(client, AdmissionController, log),
-		genPolicyRecorder:    initRecorder(client, GeneratePolicyController, log),
-		resCache:             resCache,
-		log:                  log,
+		client:      client,
+		pLister:     pInformer.Lister(),
+		queue:       workqueue.NewNamedRateLimitingQueue(rateLimiter(), eventWorkQueueName),
+		limiter:     rate.NewLimiter(rate.Limit(qps), burst),
+		dropped:     dropped,
+		pSynced:     pInformer.Informer().HasSynced,
+		broadcaster: broadcaster,
+		resCache:    resCache,
+		log:         log,
+	}
+
+	if broadcaster != nil {
+		gen.policyCtrRecorder = broadcaster.NewRecorder(PolicyController)
+		gen.admissionCtrRecorder = broadcaster.NewRecorder(AdmissionController)
+		gen.genPolicyRecorder = broadcaster.NewRecorder(GeneratePolicyController)
 	}
 	return &gen
 }
@@ -67,27 +90,9 @@ func rateLimiter() workqueue.RateLimiter {
 	return workqueue.DefaultItemBasedRateLimiter()
 }
 
-func initRecorder(client *client.Client, eventSource Source, log logr.Logger) record.EventRecorder {
-	// Initliaze Event Broadcaster
-	err := scheme.AddToScheme(scheme.Scheme)
-	if err != nil {
-		log.Error(err, "failed to add to scheme")
-		return nil
-	}
-	eventBroadcaster := record.NewBroadcaster()
-	eventBroadcaster.StartLogging(klog.V(5).Infof)
-	eventInterface, err := client.GetEventsInterface()
-	if err != nil {
-		log.Error(err, "failed to get event interface for logging")
-		return nil
-	}
-	eventBroadcaster.StartRecordingToSink(
-		&typedcorev1.EventSinkImpl{
-			Interface: eventInterface})
-	recorder := eventBroadcaster.NewRecorder(
-		scheme.Scheme,
-		v1.EventSource{Component: eventSource.String()})
-	return recorder
+// AddSink forwards the events to the sink, it must be called before the generator is started
+func (gen *Generator) AddSink(sink Interface) {
+	gen.sinks = append(gen.sinks, sink)
 }
 
 //Add queues an event for generation
@@ -100,10 +105,27 @@ func (gen *Generator) Add(infos ...Info) {
 			logger.V(4).Info("not creating an event as the resource has not been assigned a name yet", "kind", info.Kind, "name", info.Name, "namespace", info.Namespace)
 			continue
 		}
+
+		for _, sink := range gen.sinks {
+			sink.Add(info)
+		}
+
+		if gen.queue.Len() >= maxQueuedEvents {
+			logger.V(4).Info("dropping event as too many events are queued", "kind", info.Kind, "name", info.Name, "namespace", info.Namespace, "reason", info.Reason)
+			gen.drop(metrics.DropReasonQueueFull)
+			continue
+		}
+
 		gen.queue.Add(info)
 	}
 }
 
+func (gen *Generator) drop(reason string) {
+	if gen.dropped != nil {
+		gen.dropped.Inc(reason)
+	}
+}
+
 // Run begins generator
 func (gen *Generator) Run(workers int, stopCh <-chan struct{}) {
 	logger := gen.log
@@ -116,14 +138,22 @@ func (gen *Generator) Run(workers int, stopCh <-chan struct{}) {
 		logger.Info("failed to sync informer cache")
 	}
 
+	if gen.broadcaster != nil {
+		gen.broadcaster.StartRecordingToSink(stopCh)
+	}
+
+	// the workers waiting for the rate limiter stop once the generator is stopped
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	for i := 0; i < workers; i++ {
-		go wait.Until(gen.runWorker, time.Second, stopCh)
+		go wait.Until(func() { gen.runWorker(ctx) }, time.Second, stopCh)
 	}
 	<-stopCh
 }
 
-func (gen *Generator) runWorker() {
-	for gen.processNextWorkItem() {
+func (gen *Generator) runWorker(ctx context.Context) {
+	for gen.processNextWorkItem(ctx) {
 	}
 }
 
@@ -145,10 +175,11 @@ func (gen *Generator) handleErr(err error, key interface{}) {
 	gen.queue.Forget(key)
 	if !errors.IsNotFound(err) {
 		logger.Error(err, "failed to generate event", "key", key)
+		gen.drop(metrics.DropReasonRetriesExceeded)
 	}
 }
 
-func (gen *Generator) processNextWorkItem() bool {
+func (gen *Generator) processNextWorkItem(ctx context.Context) bool {
 	logger := gen.log
 	obj, shutdown := gen.queue.Get()
 	if shutdown {
@@ -165,11 +196,22 @@ func (gen *Generator) processNextWorkItem() bool {
 			logger.Info("Incorrect type; expected type 'info'", "obj", obj)
 			return nil
 		}
+
+		if err := gen.limiter.Wait(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			logger.Error(err, "failed to wait for the event rate limiter")
+		}
 		err := gen.syncHandler(key)
 		gen.handleErr(err, obj)
 		return nil
 	}(obj)
 	if err != nil {
+		if ctx.Err() != nil {
+			logger.V(4).Info("stopped waiting for the event rate limiter", "reason", err.Error())
+			return false
+		}
 		logger.Error(err, "failed to process next work item")
 		return true
 	}
@@ -200,15 +242,18 @@ func (gen *Generator) syncHandler(key Info) error {
 
 	// set the event type based on reason
 	eventType := v1.EventTypeWarning
+	if key.Reason == PolicyApplied.String() {
+		eventType = v1.EventTypeNormal
+	}
 
 	// based on the source of event generation, use different event recorders
 	switch key.Source {
 	case AdmissionController:
-		gen.admissionCtrRecorder.Event(robj, eventType, key.Reason, key.Message)
+		gen.admissionCtrRecorder.Eventf(robj, key.related(), eventType, key.Reason, key.action(), "%s", key.Message)
 	case PolicyController:
-		gen.policyCtrRecorder.Event(robj, eventType, key.Reason, key.Message)
+		gen.policyCtrRecorder.Eventf(robj, key.related(), eventType, key.Reason, key.action(), "%s", key.Message)
 	case GeneratePolicyController:
-		gen.genPolicyRecorder.Event(robj, eventType, key.Reason, key.Message)
+		gen.genPolicyRecorder.Eventf(robj, key.related(), eventType, key.Reason, key.action(), "%s", key.Message)
 	default:
 		logger.Info("info.source not defined for the request")
 	}
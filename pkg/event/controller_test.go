@@ -0,0 +1,119 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/kyverno/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"golang.org/x/time/rate"
+	"gotest.tools/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newTestEvent(message string) *v1.Event {
+	return &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "pod1.1", Namespace: "default"},
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "pod1"},
+		Source:         v1.EventSource{Component: AdmissionController.String()},
+		Type:           v1.EventTypeWarning,
+		Reason:         PolicyViolation.String(),
+		Message:        message,
+		Count:          1,
+	}
+}
+
+func Test_CorrelateIdenticalEvents(t *testing.T) {
+	correlator := record.NewEventCorrelatorWithOptions(correlatorOptions())
+
+	result, err := correlator.EventCorrelate(newTestEvent("policy require-labels/check-team fail"))
+	assert.NilError(t, err)
+	assert.Equal(t, result.Event.Count, int32(1))
+	assert.Assert(t, result.Patch == nil)
+
+	// the identical event patches the count of the recorded event
+	for i := 2; i <= 5; i++ {
+		result, err = correlator.EventCorrelate(newTestEvent("policy require-labels/check-team fail"))
+		assert.NilError(t, err)
+		assert.Equal(t, result.Event.Count, int32(i))
+		assert.Assert(t, result.Patch != nil)
+	}
+}
+
+func Test_CorrelateSimilarEvents(t *testing.T) {
+	correlator := record.NewEventCorrelatorWithOptions(correlatorOptions())
+
+	var result *record.EventCorrelateResult
+	var err error
+	for i := 0; i <= maxSimilarEvents; i++ {
+		result, err = correlator.EventCorrelate(newTestEvent(fmt.Sprintf("policy require-labels/rule-%d fail", i)))
+		assert.NilError(t, err)
+	}
+
+	// the similar events are combined once they exceed the maximum number of events
+	assert.Equal(t, result.Event.Message, "(combined from similar events): policy require-labels/rule-10 fail")
+}
+
+func Test_DropEventsWhenQueueIsFull(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	gen := &Generator{
+		queue:   workqueue.NewNamedRateLimitingQueue(rateLimiter(), eventWorkQueueName),
+		dropped: metrics.NewDroppedEvents(registry),
+		log:     log.Log,
+	}
+	defer gen.queue.ShutDown()
+
+	// the identical pending events are batched in the queue
+	gen.Add(Info{Kind: "Pod", Namespace: "default", Name: "pod0", Reason: PolicyViolation.String()})
+	gen.Add(Info{Kind: "Pod", Namespace: "default", Name: "pod0", Reason: PolicyViolation.String()})
+	assert.Equal(t, gen.queue.Len(), 1)
+
+	for i := 1; i < maxQueuedEvents+5; i++ {
+		gen.Add(Info{Kind: "Pod", Namespace: "default", Name: fmt.Sprintf("pod%d", i), Reason: PolicyViolation.String()})
+	}
+	assert.Equal(t, gen.queue.Len(), maxQueuedEvents)
+
+	expected := `
+# HELP kyverno_events_dropped_total Number of the events which were not recorded by reason, e.g. queue_full or retries_exceeded.
+# TYPE kyverno_events_dropped_total counter
+kyverno_events_dropped_total{reason="queue_full"} 5
+`
+	assert.NilError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "kyverno_events_dropped_total"))
+}
+
+func Test_InfoActionAndRelated(t *testing.T) {
+	info := Info{Kind: "Pod", Namespace: "default", Name: "pod1", Source: PolicyController}
+	assert.Equal(t, info.action(), "Scan")
+	assert.Assert(t, info.related() == nil)
+
+	info.Action = "Block"
+	info.Related = Object{APIVersion: "kyverno.io/v1", Kind: "ClusterPolicy", Name: "require-labels"}
+	assert.Equal(t, info.action(), "Block")
+	assert.DeepEqual(t, info.related(), &v1.ObjectReference{APIVersion: "kyverno.io/v1", Kind: "ClusterPolicy", Name: "require-labels"})
+}
+
+func Test_StopWaitingForTheRateLimiter(t *testing.T) {
+	gen := &Generator{
+		queue:   workqueue.NewNamedRateLimitingQueue(rateLimiter(), eventWorkQueueName),
+		limiter: rate.NewLimiter(rate.Every(time.Hour), 1),
+		log:     log.Log,
+	}
+	defer gen.queue.ShutDown()
+
+	// the event is not recorded and the worker stops once the generator is stopped
+	assert.Assert(t, gen.limiter.Allow())
+	gen.Add(Info{Kind: "Pod", Namespace: "default", Name: "pod1", Reason: PolicyViolation.String()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go cancel()
+	assert.Assert(t, !gen.processNextWorkItem(ctx))
+	assert.Equal(t, gen.queue.Len(), 0)
+}
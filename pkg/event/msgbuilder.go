@@ -15,6 +15,8 @@ const (
 	FPolicyBlockResourceUpdate
 	FPolicyApplyFailed
 	FResourcePolicyFailed
+	FPolicyApplied
+	FResourcePolicyApplied
 )
 
 func (k MsgKey) String() string {
@@ -24,6 +26,8 @@ func (k MsgKey) String() string {
 		"Resource %s update blocked by rule(s) %s",
 		"Rule(s) '%s' failed to apply on resource %s",
 		"Rule(s) '%s' of policy '%s' failed to apply on the resource",
+		"Rule(s) '%s' applied on resource %s",
+		"Rule(s) '%s' of policy '%s' applied on the resource",
 	}[k]
 }
 
@@ -1,21 +1,32 @@
 package event
 
 //Reason types of Event Reasons
+// The reasons are a fixed set which alerting rules match on, they must not be renamed.
+// The blocked requests are violations with the Block action.
 type Reason int
 
 const (
-	//PolicyViolation there is a violation of policy
+	//PolicyViolation the resource violates validation rules of the policy
 	PolicyViolation Reason = iota
-	//RequestBlocked the request to create/update the resource was blocked( generated from admission-controller)
-	RequestBlocked
-	//PolicyFailed policy failed
-	PolicyFailed
+	//PolicyApplied the rules of the policy were applied, e.g. the resource was mutated
+	PolicyApplied
+	//PolicyError the rules of the policy failed to be applied, e.g. a mutation failed
+	PolicyError
+	//PolicySkipped the policy does not apply to the resource anymore
+	PolicySkipped
+	//ResourceGenerated the generate rules of the policy generated a resource
+	ResourceGenerated
+	//GenerationFailed the generate rules of the policy failed to generate resources
+	GenerationFailed
 )
 
 func (r Reason) String() string {
 	return [...]string{
 		"PolicyViolation",
-		"RequestBlocked",
-		"PolicyFailed",
+		"PolicyApplied",
+		"PolicyError",
+		"PolicySkipped",
+		"ResourceGenerated",
+		"GenerationFailed",
 	}[r]
 }
@@ -1,9 +1,29 @@
 package event
 
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
 const eventWorkQueueName = "kyverno-events"
 
 const workQueueRetryLimit = 10
 
+// maxQueuedEvents is the maximum number of pending events, the new events are dropped
+// when the events are added faster than they are recorded
+const maxQueuedEvents = 1000
+
+const (
+	// eventCacheSize is the number of events kept by the correlator to deduplicate the
+	// identical events, kyverno records events for many resources
+	eventCacheSize = 16384
+
+	// similar events of an object are combined when more than maxSimilarEvents are
+	// recorded within similarEventsInterval seconds
+	maxSimilarEvents      = 10
+	similarEventsInterval = 600
+)
+
 //Info defines the event details
 type Info struct {
 	Kind      string
@@ -12,4 +32,53 @@ type Info struct {
 	Reason    string
 	Message   string
 	Source    Source
+	// Action is the action taken by kyverno, the action of the source is used when it is not set
+	Action string
+	// Related is the secondary object of the event, e.g. the policy of an event on a resource
+	Related Object
+}
+
+// Object identifies the object related to an event
+type Object struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+const (
+	// ActionBlock is the action of the events of the admission requests blocked by the policies
+	ActionBlock = "Block"
+	// ActionMutate is the action of the events of the resources mutated by the policies
+	ActionMutate = "Mutate"
+)
+
+func (i Info) action() string {
+	if i.Action != "" {
+		return i.Action
+	}
+
+	switch i.Source {
+	case AdmissionController:
+		return "Admit"
+	case PolicyController:
+		return "Scan"
+	case GeneratePolicyController:
+		return "Generate"
+	default:
+		return "Process"
+	}
+}
+
+func (i Info) related() runtime.Object {
+	if i.Related.Kind == "" {
+		return nil
+	}
+
+	return &v1.ObjectReference{
+		APIVersion: i.Related.APIVersion,
+		Kind:       i.Related.Kind,
+		Namespace:  i.Related.Namespace,
+		Name:       i.Related.Name,
+	}
 }
@@ -13,6 +13,7 @@ func (c *Controller) processGR(gr kyverno.GenerateRequest) error {
 	// then we don't delete the generated resources
 
 	// 2- The trigger resource is deleted, then delete the generated resources
+	// unless the trigger deletion policy of the rule keeps them
 	if !ownerResourceExists(logger, c.client, gr) {
 		if err := deleteGeneratedResources(logger, c.client, gr); err != nil {
 			return err
@@ -39,14 +40,45 @@ func ownerResourceExists(log logr.Logger, client *dclient.Client, gr kyverno.Gen
 	return true
 }
 
+// deleteGeneratedResources handles the generated resources of a deleted trigger
+// according to the trigger deletion policy of the rule
 func deleteGeneratedResources(log logr.Logger, client *dclient.Client, gr kyverno.GenerateRequest) error {
 	for _, genResource := range gr.Status.GeneratedResources {
-		err := client.DeleteResource("", genResource.Kind, genResource.Namespace, genResource.Name, false)
-		if err != nil && !apierrors.IsNotFound(err) {
+		logger := log.WithValues("genKind", genResource.Kind, "genNamespace", genResource.Namespace, "genName", genResource.Name)
+		resource, err := client.GetResource(genResource.APIVersion, genResource.Kind, genResource.Namespace, genResource.Name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
 			return err
 		}
 
-		log.V(3).Info("generated resource deleted", "genKind", gr.Spec.Resource.Kind, "genNamespace", gr.Spec.Resource.Namespace, "genName", gr.Spec.Resource.Name)
+		labels := resource.GetLabels()
+		policy := kyverno.TriggerDeletionPolicy(labels["policy.kyverno.io/trigger-deletion-policy"])
+		switch policy {
+		case kyverno.TriggerDeletionOrphan:
+			delete(labels, "policy.kyverno.io/gr-name")
+			delete(labels, "policy.kyverno.io/synchronize")
+			delete(labels, "policy.kyverno.io/trigger-deletion-policy")
+		case kyverno.TriggerDeletionRetain:
+			labels["policy.kyverno.io/synchronize"] = "disable"
+			labels["policy.kyverno.io/trigger-deleted"] = "true"
+		default:
+			err := client.DeleteResource(resource.GetAPIVersion(), resource.GetKind(), resource.GetNamespace(), resource.GetName(), false)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+
+			logger.V(3).Info("generated resource deleted")
+			continue
+		}
+
+		resource.SetLabels(labels)
+		if _, err := client.UpdateResource(resource.GetAPIVersion(), resource.GetKind(), resource.GetNamespace(), resource.Object, false); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		logger.V(3).Info("generated resource retained", "triggerDeletionPolicy", policy)
 	}
 	return nil
 }
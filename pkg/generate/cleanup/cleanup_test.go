@@ -0,0 +1,66 @@
+package cleanup
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	dclient "github.com/kyverno/kyverno/pkg/dclient"
+	"gotest.tools/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newGenerated(name, triggerDeletionPolicy string) *unstructured.Unstructured {
+	labels := map[string]string{
+		"policy.kyverno.io/policy-name": "add-quota",
+		"policy.kyverno.io/gr-name":     "gr-prod",
+		"policy.kyverno.io/synchronize": "enable",
+	}
+	if triggerDeletionPolicy != "" {
+		labels["policy.kyverno.io/trigger-deletion-policy"] = triggerDeletionPolicy
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("prod")
+	obj.SetName(name)
+	obj.SetLabels(labels)
+	return obj
+}
+
+func Test_deleteGeneratedResources(t *testing.T) {
+	client, err := dclient.NewMockClient(runtime.NewScheme(), map[schema.GroupVersionResource]string{{Version: "v1", Resource: "configmaps"}: "ConfigMapList"},
+		newGenerated("deleted", ""),
+		newGenerated("deleted-explicitly", string(kyverno.TriggerDeletionDelete)),
+		newGenerated("orphaned", string(kyverno.TriggerDeletionOrphan)),
+		newGenerated("retained", string(kyverno.TriggerDeletionRetain)),
+	)
+	assert.NilError(t, err)
+	client.SetDiscovery(dclient.NewFakeDiscoveryClient(nil))
+
+	gr := kyverno.GenerateRequest{}
+	for _, name := range []string{"deleted", "deleted-explicitly", "orphaned", "retained", "not-found"} {
+		gr.Status.GeneratedResources = append(gr.Status.GeneratedResources, kyverno.ResourceSpec{APIVersion: "v1", Kind: "ConfigMap", Namespace: "prod", Name: name})
+	}
+	assert.NilError(t, deleteGeneratedResources(logr.Discard(), client, gr))
+
+	for _, name := range []string{"deleted", "deleted-explicitly"} {
+		_, err := client.GetResource("v1", "ConfigMap", "prod", name)
+		assert.Assert(t, apierrors.IsNotFound(err), name)
+	}
+
+	orphaned, err := client.GetResource("v1", "ConfigMap", "prod", "orphaned")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, orphaned.GetLabels(), map[string]string{"policy.kyverno.io/policy-name": "add-quota"})
+
+	retained, err := client.GetResource("v1", "ConfigMap", "prod", "retained")
+	assert.NilError(t, err)
+	labels := retained.GetLabels()
+	assert.Equal(t, labels["policy.kyverno.io/gr-name"], "gr-prod")
+	assert.Equal(t, labels["policy.kyverno.io/synchronize"], "disable")
+	assert.Equal(t, labels["policy.kyverno.io/trigger-deleted"], "true")
+}
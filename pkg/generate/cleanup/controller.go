@@ -8,6 +8,7 @@ import (
 	kyvernoclient "github.com/kyverno/kyverno/pkg/client/clientset/versioned"
 	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1"
 	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/common"
 	"github.com/kyverno/kyverno/pkg/config"
 	dclient "github.com/kyverno/kyverno/pkg/dclient"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -42,10 +43,14 @@ type Controller struct {
 	queue workqueue.RateLimitingInterface
 	// pLister can list/get cluster policy from the shared informer's store
 	pLister kyvernolister.ClusterPolicyLister
+	// npLister can list/get namespace policy from the shared informer's store
+	npLister kyvernolister.PolicyLister
 	// grLister can list/get generate request from the shared informer's store
 	grLister kyvernolister.GenerateRequestNamespaceLister
 	// pSynced returns true if the cluster policy has been synced at least once
 	pSynced cache.InformerSynced
+	// npSynced returns true if the namespace policy has been synced at least once
+	npSynced cache.InformerSynced
 	// grSynced returns true if the generate request store has been synced at least once
 	grSynced cache.InformerSynced
 	// dynamic sharedinformer factory
@@ -61,6 +66,7 @@ func NewController(
 	kyvernoclient *kyvernoclient.Clientset,
 	client *dclient.Client,
 	pInformer kyvernoinformer.ClusterPolicyInformer,
+	npInformer kyvernoinformer.PolicyInformer,
 	grInformer kyvernoinformer.GenerateRequestInformer,
 	dynamicInformer dynamicinformer.DynamicSharedInformerFactory,
 	log logr.Logger,
@@ -78,9 +84,11 @@ func NewController(
 	c.syncHandler = c.syncGenerateRequest
 
 	c.pLister = pInformer.Lister()
+	c.npLister = npInformer.Lister()
 	c.grLister = grInformer.Lister().GenerateRequests(config.KyvernoNamespace)
 
 	c.pSynced = pInformer.Informer().HasSynced
+	c.npSynced = npInformer.Informer().HasSynced
 	c.grSynced = grInformer.Informer().HasSynced
 
 	pInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -132,9 +140,9 @@ func (c *Controller) deletePolicy(obj interface{}) {
 			logger.Info("couldn't get object from tombstone", "obj", obj)
 			return
 		}
-		_, ok = tombstone.Obj.(*kyverno.ClusterPolicy)
+		p, ok = tombstone.Obj.(*kyverno.ClusterPolicy)
 		if !ok {
-			logger.Info("Tombstone contained object that is not a Generate Request", "obj", obj)
+			logger.Info("Tombstone contained object that is not a ClusterPolicy", "obj", obj)
 			return
 		}
 	}
@@ -174,9 +182,9 @@ func (c *Controller) deleteGR(obj interface{}) {
 			return
 		}
 
-		_, ok = tombstone.Obj.(*kyverno.GenerateRequest)
+		gr, ok = tombstone.Obj.(*kyverno.GenerateRequest)
 		if !ok {
-			logger.Info("ombstone contained object that is not a Generate Request", "obj", obj)
+			logger.Info("Tombstone contained object that is not a Generate Request", "obj", obj)
 			return
 		}
 	}
@@ -221,7 +229,7 @@ func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
 	logger.Info("starting")
 	defer logger.Info("shutting down")
 
-	if !cache.WaitForCacheSync(stopCh, c.pSynced, c.grSynced) {
+	if !cache.WaitForCacheSync(stopCh, c.pSynced, c.npSynced, c.grSynced) {
 		logger.Info("failed to sync informer cache")
 		return
 	}
@@ -294,7 +302,7 @@ func (c *Controller) syncGenerateRequest(key string) error {
 		return err
 	}
 
-	_, err = c.pLister.Get(gr.Spec.Policy)
+	_, err = common.GetPolicy(c.pLister, c.npLister, gr.Spec.Policy)
 	if err != nil {
 		if !errors.IsNotFound(err) {
 			return err
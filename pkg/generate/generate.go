@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func (c *Controller) processGR(gr *kyverno.GenerateRequest) error {
@@ -48,16 +49,24 @@ func (c *Controller) processGR(gr *kyverno.GenerateRequest) error {
 	namespaceLabels := pkgcommon.GetNamespaceSelectorsFromGenericInformer(resource.GetKind(), resource.GetNamespace(), c.nsInformer, logger)
 	genResources, err = c.applyGenerate(*resource, *gr, namespaceLabels)
 
+	eventsEnabled := c.eventsEnabled(gr.Spec.Policy)
 	if err != nil {
 		// Need not update the stauts when policy doesn't apply on resource, because all the generate requests are removed by the cleanup controller
 		if strings.Contains(err.Error(), doesNotApply) {
 			logger.V(4).Info("skipping updating status of generate request")
+			if eventsEnabled {
+				c.eventGen.Add(skippedEvents(*gr, *resource)...)
+			}
 			return nil
 		}
 
 		// 3 - Report failure Events
-		events := failedEvents(err, *gr, *resource)
-		c.eventGen.Add(events...)
+		if eventsEnabled {
+			events := failedEvents(err, *gr, *resource)
+			c.eventGen.Add(events...)
+		}
+	} else if eventsEnabled {
+		c.eventGen.Add(generatedEvents(*gr, *resource, genResources)...)
 	}
 
 	// 4 - Update Status
@@ -66,6 +75,16 @@ func (c *Controller) processGR(gr *kyverno.GenerateRequest) error {
 
 const doesNotApply = "policy does not apply to resource"
 
+// eventsEnabled checks if the events of the policy are recorded, the events of deleted policies are recorded
+func (c *Controller) eventsEnabled(policyName string) bool {
+	policy, err := pkgcommon.GetPolicy(c.policyLister, c.npolicyLister, policyName)
+	if err != nil {
+		return true
+	}
+
+	return policy.EventsEnabled()
+}
+
 func (c *Controller) applyGenerate(resource unstructured.Unstructured, gr kyverno.GenerateRequest, namespaceLabels map[string]string) ([]kyverno.ResourceSpec, error) {
 	logger := c.log.WithValues("name", gr.Name, "policy", gr.Spec.Policy, "kind", gr.Spec.Resource.Kind, "apiVersion", gr.Spec.Resource.APIVersion, "namespace", gr.Spec.Resource.Namespace, "name", gr.Spec.Resource.Name)
 	// Get the list of rules to be applied
@@ -75,7 +94,8 @@ func (c *Controller) applyGenerate(resource unstructured.Unstructured, gr kyvern
 
 	logger.V(3).Info("applying generate policy rule")
 
-	policyObj, err := c.policyLister.Get(gr.Spec.Policy)
+	// the generate requests of namespaced policies have the <namespace>/<name> key of the policy
+	policyObj, err := pkgcommon.GetPolicy(c.policyLister, c.npolicyLister, gr.Spec.Policy)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			for _, e := range gr.Status.GeneratedResources {
@@ -111,6 +131,32 @@ func (c *Controller) applyGenerate(resource unstructured.Unstructured, gr kyvern
 		return nil, err
 	}
 
+	// request.namespace and request.operation are resolved as for the admission request
+	err = ctx.AddNamespace(resource.GetNamespace())
+	if err != nil {
+		logger.Error(err, "failed to load namespace in context")
+		return nil, err
+	}
+
+	namespaceAnnotations := make(map[string]string)
+	if resource.GetNamespace() != "" {
+		namespaceAnnotations = pkgcommon.GetNamespaceAnnotationsFromGenericInformer(resource.GetKind(), resource.GetNamespace(), c.nsInformer, logger)
+	}
+
+	err = ctx.AddNamespaceMetadata(namespaceLabels, namespaceAnnotations)
+	if err != nil {
+		logger.Error(err, "failed to load namespace metadata in context")
+		return nil, err
+	}
+
+	if operation := gr.Spec.Context.AdmissionRequestInfo.Operation; operation != "" {
+		err = ctx.AddOperation(operation)
+		if err != nil {
+			logger.Error(err, "failed to load operation in context")
+			return nil, err
+		}
+	}
+
 	err = ctx.AddUserInfo(gr.Spec.Context.UserRequestInfo)
 	if err != nil {
 		logger.Error(err, "failed to load SA in context")
@@ -207,7 +253,8 @@ func (c *Controller) applyGeneratePolicy(log logr.Logger, policyContext *engine.
 		processExisting := false
 		var genResource kyverno.ResourceSpec
 
-		if len(rule.MatchResources.Kinds) > 0 {
+		// resources created before the policy are only processed by rules with generateExisting
+		if len(rule.MatchResources.Kinds) > 0 && !rule.Generation.GenerateExisting {
 			if len(rule.MatchResources.Annotations) == 0 && rule.MatchResources.Selector == nil {
 				rcreationTime := resource.GetCreationTimestamp()
 				pcreationTime := policy.GetCreationTimestamp()
@@ -222,7 +269,19 @@ func (c *Controller) applyGeneratePolicy(log logr.Logger, policyContext *engine.
 		}
 
 		if !processExisting {
-			genResource, err = applyRule(log, c.client, rule, resource, jsonContext, policy.Name, gr)
+			if rule.Generation.CloneList != nil {
+				cloned, err := applyCloneList(log, c.client, c.writeLimiter, rule, resource, jsonContext, policy.Name, gr, c.Config.IsNamespaceProtected)
+				if err != nil {
+					log.Error(err, "failed to apply generate rule", "policy", policy.Name,
+						"rule", rule.Name, "resource", resource.GetName())
+					return nil, err
+				}
+				ruleNameToProcessingTime[rule.Name] = time.Since(startTime)
+				genResources = append(genResources, cloned...)
+				continue
+			}
+
+			genResource, err = applyRule(log, c.client, c.writeLimiter, rule, resource, jsonContext, policy.Name, gr, c.Config.IsNamespaceProtected)
 			if err != nil {
 				log.Error(err, "failed to apply generate rule", "policy", policy.Name,
 					"rule", rule.Name, "resource", resource.GetName())
@@ -302,7 +361,7 @@ func getResourceInfo(object map[string]interface{}) (kind, name, namespace, apiv
 	return
 }
 
-func applyRule(log logr.Logger, client *dclient.Client, rule kyverno.Rule, resource unstructured.Unstructured, ctx context.EvalInterface, policy string, gr kyverno.GenerateRequest) (kyverno.ResourceSpec, error) {
+func applyRule(log logr.Logger, client *dclient.Client, limiter *writeLimiter, rule kyverno.Rule, resource unstructured.Unstructured, ctx context.EvalInterface, policy string, gr kyverno.GenerateRequest, isProtected func(namespace string) bool) (kyverno.ResourceSpec, error) {
 	var rdata map[string]interface{}
 	var err error
 	var mode ResourceMode
@@ -329,6 +388,11 @@ func applyRule(log logr.Logger, client *dclient.Client, rule kyverno.Rule, resou
 
 	logger := log.WithValues("genKind", genKind, "genAPIVersion", genAPIVersion, "genNamespace", genNamespace, "genName", genName)
 
+	// the namespace may be computed from variables, it is checked once they are substituted
+	if genNamespace != "" && isProtected(genNamespace) {
+		return noGenResource, fmt.Errorf("cannot generate %s %s in the protected namespace %s", genKind, genName, genNamespace)
+	}
+
 	// Resource to be generated
 	newGenResource := kyverno.ResourceSpec{
 		APIVersion: genAPIVersion,
@@ -359,10 +423,120 @@ func applyRule(log logr.Logger, client *dclient.Client, rule kyverno.Rule, resou
 	}
 
 	logger.V(3).Info("applying generate rule", "mode", mode)
+	if err := generateResource(logger, client, limiter, rule, resource, policy, gr, newGenResource, rdata, mode); err != nil {
+		return noGenResource, err
+	}
 
+	return newGenResource, nil
+}
+
+// applyCloneList clones the source resources selected by the cloneList of the rule to the generated
+// namespace, the generated resources have the names of the source resources
+func applyCloneList(log logr.Logger, client *dclient.Client, limiter *writeLimiter, rule kyverno.Rule, resource unstructured.Unstructured, ctx context.EvalInterface, policy string, gr kyverno.GenerateRequest, isProtected func(namespace string) bool) ([]kyverno.ResourceSpec, error) {
+	genUnst, err := getUnstrRule(rule.Generation.DeepCopy())
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := variables.SubstituteVars(log, ctx, genUnst.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	var generation kyverno.Generation
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.(map[string]interface{}), &generation); err != nil {
+		return nil, fmt.Errorf("failed to read `cloneList`: %v", err)
+	}
+
+	if generation.Namespace != "" && isProtected(generation.Namespace) {
+		return nil, fmt.Errorf("cannot generate %s resources in the protected namespace %s", generation.Kind, generation.Namespace)
+	}
+
+	cloneList := generation.CloneList
+	sources, err := client.ListResource(generation.APIVersion, generation.Kind, cloneList.Namespace, cloneList.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list source resources %s %s/%s: %v", generation.APIVersion, generation.Kind, cloneList.Namespace, err)
+	}
+
+	var genResources []kyverno.ResourceSpec
+	for i := range sources.Items {
+		source := &sources.Items[i]
+		genResource := kyverno.ResourceSpec{
+			APIVersion: generation.APIVersion,
+			Kind:       generation.Kind,
+			Namespace:  generation.Namespace,
+			Name:       source.GetName(),
+		}
+
+		logger := log.WithValues("genKind", genResource.Kind, "genAPIVersion", genResource.APIVersion, "genNamespace", genResource.Namespace, "genName", genResource.Name)
+		if cloneList.Namespace == genResource.Namespace {
+			logger.V(4).Info("skip resource self-clone")
+			continue
+		}
+
+		rdata, mode, err := manageCloneSource(source, genResource, client)
+		if err != nil {
+			logger.Error(err, "failed to generate resource", "mode", mode)
+			return nil, err
+		}
+
+		logger.V(3).Info("applying generate rule", "mode", mode)
+		if err := generateResource(logger, client, limiter, rule, resource, policy, gr, genResource, rdata, mode); err != nil {
+			return nil, err
+		}
+
+		genResources = append(genResources, genResource)
+	}
+
+	// the synchronized clones of the sources deleted, or no longer selected, are deleted
+	if rule.Generation.Synchronize {
+		if err := deleteStaleClones(log, client, rule.Name, policy, gr, generation, genResources); err != nil {
+			return nil, err
+		}
+	}
+
+	return genResources, nil
+}
+
+// deleteStaleClones deletes the resources cloned by the cloneList of the rule for the generate request
+// which are not in the cloned resources
+func deleteStaleClones(log logr.Logger, client *dclient.Client, rule, policy string, gr kyverno.GenerateRequest, generation kyverno.Generation, cloned []kyverno.ResourceSpec) error {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{
+		"policy.kyverno.io/policy-name":     policy,
+		"policy.kyverno.io/gr-name":         gr.Name,
+		"policy.kyverno.io/clone-list-rule": rule,
+	}}
+
+	clones, err := client.ListResource(generation.APIVersion, generation.Kind, generation.Namespace, selector)
+	if err != nil {
+		return fmt.Errorf("failed to list cloned resources %s %s/%s: %v", generation.APIVersion, generation.Kind, generation.Namespace, err)
+	}
+
+	names := make(map[string]bool, len(cloned))
+	for _, genResource := range cloned {
+		names[genResource.Name] = true
+	}
+
+	for _, clone := range clones.Items {
+		if names[clone.GetName()] {
+			continue
+		}
+
+		if err := client.DeleteResource(generation.APIVersion, generation.Kind, generation.Namespace, clone.GetName(), false); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete the clone %s %s/%s of a source no longer selected: %v", generation.Kind, generation.Namespace, clone.GetName(), err)
+		}
+		log.V(2).Info("deleted the clone of a source no longer selected", "genKind", generation.Kind, "genNamespace", generation.Namespace, "genName", clone.GetName())
+	}
+
+	return nil
+}
+
+// generateResource creates or updates the generated resource with the data of the rule
+func generateResource(logger logr.Logger, client *dclient.Client, limiter *writeLimiter, rule kyverno.Rule, resource unstructured.Unstructured, policy string, gr kyverno.GenerateRequest, genResource kyverno.ResourceSpec, rdata map[string]interface{}, mode ResourceMode) error {
+	genAPIVersion, genKind, genNamespace, genName := genResource.APIVersion, genResource.Kind, genResource.Namespace, genResource.Name
 	if rdata == nil && mode == Update {
 		logger.V(4).Info("no changes required for target resource")
-		return newGenResource, nil
+		return nil
 	}
 
 	// build the resource template
@@ -384,6 +558,19 @@ func applyRule(log logr.Logger, client *dclient.Client, rule kyverno.Rule, resou
 	label["policy.kyverno.io/policy-name"] = policy
 	label["policy.kyverno.io/gr-name"] = gr.Name
 	delete(label, "generate.kyverno.io/clone-policy-name")
+	// the clones of a cloneList are deleted with their sources when synchronized
+	if rule.Generation.CloneList != nil {
+		label["policy.kyverno.io/clone-list-rule"] = rule.Name
+	} else {
+		delete(label, "policy.kyverno.io/clone-list-rule")
+	}
+	// the cleanup controller reads the trigger deletion policy from the generated resource
+	if policy := rule.Generation.TriggerDeletionPolicy; policy != "" && policy != kyverno.TriggerDeletionDelete {
+		label["policy.kyverno.io/trigger-deletion-policy"] = string(policy)
+	} else {
+		delete(label, "policy.kyverno.io/trigger-deletion-policy")
+	}
+
 	if mode == Create {
 		if rule.Generation.Synchronize {
 			label["policy.kyverno.io/synchronize"] = "enable"
@@ -394,10 +581,21 @@ func applyRule(log logr.Logger, client *dclient.Client, rule kyverno.Rule, resou
 		// Reset resource version
 		newResource.SetResourceVersion("")
 		newResource.SetLabels(label)
+		// a dry-run reports the schema and admission failures of the generated resource in the request status
+		if err := limiter.wait(); err != nil {
+			return err
+		}
+		if _, err := client.CreateResource(genAPIVersion, genKind, genNamespace, newResource, true); err != nil {
+			return fmt.Errorf("dry-run create of %s %s/%s failed: %v", genKind, genNamespace, genName, err)
+		}
+
 		// Create the resource
-		_, err = client.CreateResource(genAPIVersion, genKind, genNamespace, newResource, false)
+		if err := limiter.wait(); err != nil {
+			return err
+		}
+		_, err := client.CreateResource(genAPIVersion, genKind, genNamespace, newResource, false)
 		if err != nil {
-			return noGenResource, err
+			return err
 		}
 
 		logger.V(2).Info("generated target resource")
@@ -412,16 +610,64 @@ func applyRule(log logr.Logger, client *dclient.Client, rule kyverno.Rule, resou
 		if rule.Generation.Synchronize {
 			logger.V(4).Info("updating existing resource")
 			newResource.SetLabels(label)
+			if err := limiter.wait(); err != nil {
+				return err
+			}
+			if _, err := client.UpdateResource(genAPIVersion, genKind, genNamespace, newResource, true); err != nil {
+				return fmt.Errorf("dry-run update of %s %s/%s failed: %v", genKind, genNamespace, genName, err)
+			}
+
+			if err := limiter.wait(); err != nil {
+				return err
+			}
 			_, err := client.UpdateResource(genAPIVersion, genKind, genNamespace, newResource, false)
 			if err != nil {
 				logger.Error(err, "failed to update resource")
-				return noGenResource, err
+				return err
 			}
 			logger.V(2).Info("updated target resource")
+		} else {
+			if err := updateTriggerDeletionPolicy(logger, client, limiter, genResource, label["policy.kyverno.io/trigger-deletion-policy"]); err != nil {
+				return err
+			}
 		}
 	}
 
-	return newGenResource, nil
+	return nil
+}
+
+// updateTriggerDeletionPolicy sets the trigger deletion policy label of a generated resource which is
+// not synchronized, the other labels and the data of the resource are not changed
+func updateTriggerDeletionPolicy(logger logr.Logger, client *dclient.Client, limiter *writeLimiter, genResource kyverno.ResourceSpec, policy string) error {
+	obj, err := client.GetResource(genResource.APIVersion, genResource.Kind, genResource.Namespace, genResource.Name)
+	if err != nil {
+		return err
+	}
+
+	labels := obj.GetLabels()
+	if labels["policy.kyverno.io/trigger-deletion-policy"] == policy {
+		return nil
+	}
+
+	if policy != "" {
+		if labels == nil {
+			labels = make(map[string]string, 1)
+		}
+		labels["policy.kyverno.io/trigger-deletion-policy"] = policy
+	} else {
+		delete(labels, "policy.kyverno.io/trigger-deletion-policy")
+	}
+
+	obj.SetLabels(labels)
+	if err := limiter.wait(); err != nil {
+		return err
+	}
+	if _, err := client.UpdateResource(genResource.APIVersion, genResource.Kind, genResource.Namespace, obj, false); err != nil {
+		return fmt.Errorf("failed to update the trigger deletion policy of %s %s/%s: %v", genResource.Kind, genResource.Namespace, genResource.Name, err)
+	}
+
+	logger.V(3).Info("updated the trigger deletion policy of target resource", "triggerDeletionPolicy", policy)
+	return nil
 }
 
 func manageData(log logr.Logger, apiVersion, kind, namespace, name string, data map[string]interface{}, client *dclient.Client) (map[string]interface{}, ResourceMode, error) {
@@ -469,8 +715,13 @@ func manageClone(log logr.Logger, apiVersion, kind, namespace, name, policy stri
 		return nil, Skip, fmt.Errorf("source resource %s %s/%s/%s not found. %v", apiVersion, kind, rNamespace, rName, err)
 	}
 
+	return manageCloneSource(obj, kyverno.ResourceSpec{APIVersion: apiVersion, Kind: kind, Namespace: namespace, Name: name}, client)
+}
+
+// manageCloneSource returns the data of the source resource to create or update the generated resource with
+func manageCloneSource(obj *unstructured.Unstructured, genResource kyverno.ResourceSpec, client *dclient.Client) (map[string]interface{}, ResourceMode, error) {
 	// check if resource to be generated exists
-	newResource, err := client.GetResource(apiVersion, kind, namespace, name)
+	newResource, err := client.GetResource(genResource.APIVersion, genResource.Kind, genResource.Namespace, genResource.Name)
 	if err == nil {
 		obj.SetUID(newResource.GetUID())
 		obj.SetSelfLink(newResource.GetSelfLink())
@@ -1,6 +1,8 @@
 package generate
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -13,6 +15,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/event"
 	"github.com/kyverno/kyverno/pkg/policystatus"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
+	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -48,12 +51,18 @@ type Controller struct {
 	// policyLister can list/get cluster policy from the shared informer's store
 	policyLister kyvernolister.ClusterPolicyLister
 
+	// npolicyLister can list/get namespace policy from the shared informer's store
+	npolicyLister kyvernolister.PolicyLister
+
 	// grLister can list/get generate request from the shared informer's store
 	grLister kyvernolister.GenerateRequestNamespaceLister
 
 	// policySynced returns true if the Cluster policy store has been synced at least once
 	policySynced cache.InformerSynced
 
+	// npolicySynced returns true if the Namespace policy store has been synced at least once
+	npolicySynced cache.InformerSynced
+
 	// grSynced returns true if the Generate Request store has been synced at least once
 	grSynced cache.InformerSynced
 
@@ -68,6 +77,36 @@ type Controller struct {
 
 	Config   config.Interface
 	resCache resourcecache.ResourceCache
+
+	// writeLimiter throttles the writes of the generated resources, so that the resources generated
+	// when a policy applies to many triggers at once do not overwhelm the API server
+	writeLimiter *writeLimiter
+}
+
+// writeLimiter throttles the writes of the generated resources, the writes waiting for the
+// limiter are cancelled once the controller is stopped
+type writeLimiter struct {
+	limiter *rate.Limiter
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func newWriteLimiter(qps float64, burst int) *writeLimiter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &writeLimiter{limiter: rate.NewLimiter(rate.Limit(qps), burst), ctx: ctx, cancel: cancel}
+}
+
+// wait blocks until a write is allowed, the writes are not throttled by a nil limiter
+func (l *writeLimiter) wait() error {
+	if l == nil {
+		return nil
+	}
+
+	if err := l.limiter.Wait(l.ctx); err != nil {
+		return fmt.Errorf("failed to wait for the generate rate limiter: %v", err)
+	}
+
+	return nil
 }
 
 //NewController returns an instance of the Generate-Request Controller
@@ -75,6 +114,7 @@ func NewController(
 	kyvernoClient *kyvernoclient.Clientset,
 	client *dclient.Client,
 	policyInformer kyvernoinformer.ClusterPolicyInformer,
+	npolicyInformer kyvernoinformer.PolicyInformer,
 	grInformer kyvernoinformer.GenerateRequestInformer,
 	eventGen event.Interface,
 	dynamicInformer dynamicinformer.DynamicSharedInformerFactory,
@@ -82,6 +122,8 @@ func NewController(
 	log logr.Logger,
 	dynamicConfig config.Interface,
 	resourceCache resourcecache.ResourceCache,
+	qps float64,
+	burst int,
 ) (*Controller, error) {
 
 	c := Controller{
@@ -94,6 +136,7 @@ func NewController(
 		policyStatusListener: policyStatus,
 		Config:               dynamicConfig,
 		resCache:             resourceCache,
+		writeLimiter:         newWriteLimiter(qps, burst),
 	}
 
 	c.statusControl = StatusControl{client: kyvernoClient}
@@ -110,9 +153,11 @@ func NewController(
 	})
 
 	c.policyLister = policyInformer.Lister()
+	c.npolicyLister = npolicyInformer.Lister()
 	c.grLister = grInformer.Lister().GenerateRequests(config.KyvernoNamespace)
 
 	c.policySynced = policyInformer.Informer().HasSynced
+	c.npolicySynced = npolicyInformer.Informer().HasSynced
 	c.grSynced = grInformer.Informer().HasSynced
 
 	//TODO: dynamic registration
@@ -255,11 +300,12 @@ func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
 	logger := c.log
 	defer utilruntime.HandleCrash()
 	defer c.queue.ShutDown()
+	defer c.writeLimiter.cancel()
 
 	logger.Info("starting")
 	defer logger.Info("shutting down")
 
-	if !cache.WaitForCacheSync(stopCh, c.policySynced, c.grSynced) {
+	if !cache.WaitForCacheSync(stopCh, c.policySynced, c.npolicySynced, c.grSynced) {
 		logger.Info("failed to sync informer cache")
 		return
 	}
@@ -0,0 +1,173 @@
+package generate
+
+import (
+	contextdefault "context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	kyvernofake "github.com/kyverno/kyverno/pkg/client/clientset/versioned/fake"
+	"github.com/kyverno/kyverno/pkg/config"
+	dclient "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func newSecret(namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	secret := &unstructured.Unstructured{}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	secret.SetNamespace(namespace)
+	secret.SetName(name)
+	secret.SetLabels(labels)
+	return secret
+}
+
+func Test_deleteStaleClones(t *testing.T) {
+	cloneLabels := func(rule string) map[string]string {
+		return map[string]string{
+			"app.kubernetes.io/managed-by":      "kyverno",
+			"policy.kyverno.io/policy-name":     "sync-secrets",
+			"policy.kyverno.io/gr-name":         "gr-prod",
+			"policy.kyverno.io/clone-list-rule": rule,
+		}
+	}
+
+	client, err := dclient.NewMockClient(runtime.NewScheme(), map[schema.GroupVersionResource]string{{Version: "v1", Resource: "secrets"}: "SecretList"},
+		newSecret("prod", "regcred", cloneLabels("clone-secrets")),
+		// the clone of a source deleted or no longer selected
+		newSecret("prod", "old-regcred", cloneLabels("clone-secrets")),
+		// the clone of another rule and a resource not generated
+		newSecret("prod", "tls", cloneLabels("clone-certificates")),
+		newSecret("prod", "manual", nil),
+	)
+	assert.NilError(t, err)
+	client.SetDiscovery(dclient.NewFakeDiscoveryClient(nil))
+
+	gr := kyverno.GenerateRequest{}
+	gr.SetName("gr-prod")
+	generation := kyverno.Generation{ResourceSpec: kyverno.ResourceSpec{APIVersion: "v1", Kind: "Secret", Namespace: "prod"}}
+	cloned := []kyverno.ResourceSpec{{APIVersion: "v1", Kind: "Secret", Namespace: "prod", Name: "regcred"}}
+	assert.NilError(t, deleteStaleClones(logr.Discard(), client, "clone-secrets", "sync-secrets", gr, generation, cloned))
+
+	secrets, err := client.ListResource("v1", "Secret", "prod", nil)
+	assert.NilError(t, err)
+
+	var names []string
+	for _, secret := range secrets.Items {
+		names = append(names, secret.GetName())
+	}
+	assert.DeepEqual(t, names, []string{"manual", "regcred", "tls"})
+}
+
+func Test_updateTriggerDeletionPolicy(t *testing.T) {
+	client, err := dclient.NewMockClient(runtime.NewScheme(), map[schema.GroupVersionResource]string{{Version: "v1", Resource: "secrets"}: "SecretList"},
+		newSecret("prod", "regcred", map[string]string{"policy.kyverno.io/gr-name": "gr-prod", "policy.kyverno.io/synchronize": "disable"}),
+	)
+	assert.NilError(t, err)
+	client.SetDiscovery(dclient.NewFakeDiscoveryClient(nil))
+
+	genResource := kyverno.ResourceSpec{APIVersion: "v1", Kind: "Secret", Namespace: "prod", Name: "regcred"}
+	labels := func() map[string]string {
+		secret, err := client.GetResource("v1", "Secret", "prod", "regcred")
+		assert.NilError(t, err)
+		return secret.GetLabels()
+	}
+
+	// the label is stamped on the existing resource which is not synchronized
+	assert.NilError(t, updateTriggerDeletionPolicy(logr.Discard(), client, nil, genResource, string(kyverno.TriggerDeletionRetain)))
+	assert.DeepEqual(t, labels(), map[string]string{
+		"policy.kyverno.io/gr-name":                 "gr-prod",
+		"policy.kyverno.io/synchronize":             "disable",
+		"policy.kyverno.io/trigger-deletion-policy": "Retain",
+	})
+
+	// the label is removed when the rule deletes the generated resources again
+	assert.NilError(t, updateTriggerDeletionPolicy(logr.Discard(), client, nil, genResource, ""))
+	assert.DeepEqual(t, labels(), map[string]string{"policy.kyverno.io/gr-name": "gr-prod", "policy.kyverno.io/synchronize": "disable"})
+}
+
+func Test_applyRule_ProtectedNamespace(t *testing.T) {
+	rule := kyverno.Rule{}
+	assert.NilError(t, json.Unmarshal([]byte(`{
+		"name": "copy-regcred",
+		"generate": {"apiVersion": "v1", "kind": "Secret", "namespace": "{{request.object.metadata.name}}", "name": "regcred", "data": {"type": "Opaque"}}
+	}`), &rule))
+
+	ctx := context.NewContext()
+	assert.NilError(t, ctx.AddResource([]byte(`{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "kube-system"}}`)))
+
+	// the namespace substituted from the variables is protected
+	isProtected := func(namespace string) bool { return namespace == "kube-system" }
+	_, err := applyRule(logr.Discard(), nil, nil, rule, unstructured.Unstructured{}, ctx, "copy-regcred", kyverno.GenerateRequest{}, isProtected)
+	assert.Error(t, err, "cannot generate Secret regcred in the protected namespace kube-system")
+}
+
+func Test_generateResource_WriteLimiter(t *testing.T) {
+	client, err := dclient.NewMockClient(runtime.NewScheme(), map[schema.GroupVersionResource]string{{Version: "v1", Resource: "secrets"}: "SecretList"})
+	assert.NilError(t, err)
+	client.SetDiscovery(dclient.NewFakeDiscoveryClient(nil))
+
+	trigger := unstructured.Unstructured{}
+	trigger.SetAPIVersion("v1")
+	trigger.SetKind("Namespace")
+	trigger.SetName("prod")
+	genResource := kyverno.ResourceSpec{APIVersion: "v1", Kind: "Secret", Namespace: "prod", Name: "regcred"}
+	rdata := map[string]interface{}{"type": "Opaque"}
+
+	// the writes waiting for the limiter are cancelled once the controller is stopped
+	limiter := newWriteLimiter(1.0/3600, 1)
+	assert.Assert(t, limiter.limiter.Allow())
+	limiter.cancel()
+	err = generateResource(logr.Discard(), client, limiter, kyverno.Rule{}, trigger, "copy-regcred", kyverno.GenerateRequest{}, genResource, rdata, Create)
+	assert.ErrorContains(t, err, "failed to wait for the generate rate limiter")
+	_, err = client.GetResource("v1", "Secret", "prod", "regcred")
+	assert.ErrorContains(t, err, "not found")
+
+	// the writes are not throttled without a limiter
+	assert.NilError(t, (*writeLimiter)(nil).wait())
+}
+
+func Test_generateResource_DryRunFailure(t *testing.T) {
+	client, err := dclient.NewMockClient(runtime.NewScheme(), map[schema.GroupVersionResource]string{{Version: "v1", Resource: "secrets"}: "SecretList"})
+	assert.NilError(t, err)
+	client.SetDiscovery(dclient.NewFakeDiscoveryClient(nil))
+
+	// the admission of the generated resource is rejected on the dry-run
+	creates := 0
+	client.GetDynamicInterface().(*dynamicfake.FakeDynamicClient).PrependReactor("create", "secrets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		creates++
+		return true, nil, errors.New("admission webhook denied the request")
+	})
+
+	trigger := unstructured.Unstructured{}
+	trigger.SetAPIVersion("v1")
+	trigger.SetKind("Namespace")
+	trigger.SetName("prod")
+	gr := kyverno.GenerateRequest{}
+	gr.SetName("gr-prod")
+	gr.SetNamespace(config.KyvernoNamespace)
+	genResource := kyverno.ResourceSpec{APIVersion: "v1", Kind: "Secret", Namespace: "prod", Name: "regcred"}
+
+	genErr := generateResource(logr.Discard(), client, nil, kyverno.Rule{}, trigger, "copy-regcred", gr, genResource, map[string]interface{}{"type": "Opaque"}, Create)
+	assert.ErrorContains(t, genErr, "dry-run create of Secret prod/regcred failed: admission webhook denied the request")
+	assert.Equal(t, creates, 1)
+	_, err = client.GetResource("v1", "Secret", "prod", "regcred")
+	assert.ErrorContains(t, err, "not found")
+
+	// the failure of the dry-run is reported in the status of the request
+	kyvernoClient := kyvernofake.NewSimpleClientset(&gr)
+	assert.NilError(t, updateStatus(StatusControl{client: kyvernoClient}, gr, genErr, nil))
+	updated, err := kyvernoClient.KyvernoV1().GenerateRequests(config.KyvernoNamespace).Get(contextdefault.TODO(), "gr-prod", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, updated.Status.State, kyverno.Failed)
+	assert.Equal(t, updated.Status.Message, genErr.Error())
+}
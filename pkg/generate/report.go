@@ -14,9 +14,38 @@ func failedEvents(err error, gr kyverno.GenerateRequest, resource unstructured.U
 	re.Kind = resource.GetKind()
 	re.Namespace = resource.GetNamespace()
 	re.Name = resource.GetName()
-	re.Reason = event.PolicyFailed.String()
+	re.Reason = event.GenerationFailed.String()
 	re.Source = event.GeneratePolicyController
 	re.Message = fmt.Sprintf("policy %s failed to apply: %v", gr.Spec.Policy, err)
 
 	return []event.Info{re}
 }
+
+func generatedEvents(gr kyverno.GenerateRequest, resource unstructured.Unstructured, genResources []kyverno.ResourceSpec) []event.Info {
+	var events []event.Info
+	for _, genResource := range genResources {
+		re := event.Info{}
+		re.Kind = resource.GetKind()
+		re.Namespace = resource.GetNamespace()
+		re.Name = resource.GetName()
+		re.Reason = event.ResourceGenerated.String()
+		re.Source = event.GeneratePolicyController
+		re.Message = fmt.Sprintf("policy %s generated %s", gr.Spec.Policy, genResource.ToKey())
+		re.Related = event.Object{APIVersion: genResource.APIVersion, Kind: genResource.Kind, Namespace: genResource.Namespace, Name: genResource.Name}
+		events = append(events, re)
+	}
+
+	return events
+}
+
+func skippedEvents(gr kyverno.GenerateRequest, resource unstructured.Unstructured) []event.Info {
+	re := event.Info{}
+	re.Kind = resource.GetKind()
+	re.Namespace = resource.GetNamespace()
+	re.Name = resource.GetName()
+	re.Reason = event.PolicySkipped.String()
+	re.Source = event.GeneratePolicyController
+	re.Message = fmt.Sprintf("policy %s does not apply to the resource anymore", gr.Spec.Policy)
+
+	return []event.Info{re}
+}
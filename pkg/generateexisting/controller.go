@@ -0,0 +1,359 @@
+package generateexisting
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1"
+	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/common"
+	"github.com/kyverno/kyverno/pkg/config"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine"
+	enginectx "github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/resourcecache"
+	"github.com/kyverno/kyverno/pkg/webhooks/generate"
+	"golang.org/x/time/rate"
+	"k8s.io/api/admission/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	informers "k8s.io/client-go/informers/core/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	workQueueName       = "generate-existing"
+	workQueueRetryLimit = 5
+
+	// the generate requests of existing resources are limited to 10 per second, with bursts of 100
+	rateLimitQPS   = 10
+	rateLimitBurst = 100
+)
+
+// request identifies a policy and the trigger resource, the policy is identified by the
+// <namespace>/<name> key of namespaced policies. A request without trigger processes all
+// resources matched by the policy.
+type request struct {
+	policy     string
+	apiVersion string
+	kind       string
+	namespace  string
+	name       string
+}
+
+func (r request) hasTrigger() bool {
+	return r.kind != ""
+}
+
+// Controller applies generate rules with generateExisting to the resources which exist
+// when the policy is created or updated. The resources generated for new resources are
+// still requested by the admission webhook.
+type Controller struct {
+	client *client.Client
+	queue  workqueue.RateLimitingInterface
+
+	pLister  kyvernolister.ClusterPolicyLister
+	npLister kyvernolister.PolicyLister
+	nsLister listerv1.NamespaceLister
+
+	pSynced        cache.InformerSynced
+	npSynced       cache.InformerSynced
+	nsListerSynced cache.InformerSynced
+
+	// grGenerator creates the generate requests processed by the generate controller
+	grGenerator generate.GenerateRequests
+
+	configHandler config.Interface
+	resCache      resourcecache.ResourceCache
+
+	log logr.Logger
+}
+
+// NewController returns a new instance of the generate existing controller
+func NewController(client *client.Client,
+	pInformer kyvernoinformer.ClusterPolicyInformer,
+	npInformer kyvernoinformer.PolicyInformer,
+	namespaces informers.NamespaceInformer,
+	grGenerator generate.GenerateRequests,
+	configHandler config.Interface,
+	resCache resourcecache.ResourceCache,
+	log logr.Logger) *Controller {
+
+	rateLimiter := workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 1000*time.Second),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(rateLimitQPS), rateLimitBurst)},
+	)
+
+	c := &Controller{
+		client:         client,
+		queue:          workqueue.NewNamedRateLimitingQueue(rateLimiter, workQueueName),
+		pLister:        pInformer.Lister(),
+		npLister:       npInformer.Lister(),
+		nsLister:       namespaces.Lister(),
+		pSynced:        pInformer.Informer().HasSynced,
+		npSynced:       npInformer.Informer().HasSynced,
+		nsListerSynced: namespaces.Informer().HasSynced,
+		grGenerator:    grGenerator,
+		configHandler:  configHandler,
+		resCache:       resCache,
+		log:            log,
+	}
+
+	pInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.addPolicy,
+		UpdateFunc: c.updatePolicy,
+	})
+
+	npInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.addNsPolicy,
+		UpdateFunc: c.updateNsPolicy,
+	})
+
+	return c
+}
+
+func (c *Controller) addPolicy(obj interface{}) {
+	c.enqueuePolicy(obj.(*kyverno.ClusterPolicy))
+}
+
+func (c *Controller) updatePolicy(old, cur interface{}) {
+	pOld := old.(*kyverno.ClusterPolicy)
+	pNew := cur.(*kyverno.ClusterPolicy)
+	if reflect.DeepEqual(pOld.Spec, pNew.Spec) {
+		return
+	}
+
+	c.enqueuePolicy(pNew)
+}
+
+func (c *Controller) addNsPolicy(obj interface{}) {
+	p := obj.(*kyverno.Policy)
+	c.enqueuePolicy(convertPolicy(p))
+}
+
+func (c *Controller) updateNsPolicy(old, cur interface{}) {
+	pOld := old.(*kyverno.Policy)
+	pNew := cur.(*kyverno.Policy)
+	if reflect.DeepEqual(pOld.Spec, pNew.Spec) {
+		return
+	}
+
+	c.enqueuePolicy(convertPolicy(pNew))
+}
+
+func (c *Controller) enqueuePolicy(policy *kyverno.ClusterPolicy) {
+	if len(generateExistingRules(policy)) == 0 {
+		return
+	}
+
+	key := policyKey(policy)
+	c.log.V(4).Info("policy added", "policy", key)
+	c.queue.Add(request{policy: key})
+}
+
+// Run starts the workers
+func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
+	logger := c.log
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger.Info("starting")
+	defer logger.Info("shutting down")
+
+	if !cache.WaitForCacheSync(stopCh, c.pSynced, c.npSynced, c.nsListerSynced) {
+		logger.Info("failed to sync informer cache")
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (c *Controller) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	defer c.queue.Done(obj)
+
+	req, ok := obj.(request)
+	if !ok {
+		c.queue.Forget(obj)
+		c.log.Info("incorrect type: expecting type 'request'", "object", obj)
+		return true
+	}
+
+	err := c.process(req)
+	c.handleErr(err, req)
+
+	return true
+}
+
+func (c *Controller) handleErr(err error, req request) {
+	logger := c.log.WithValues("policy", req.policy, "kind", req.kind, "namespace", req.namespace, "name", req.name)
+	if err == nil {
+		c.queue.Forget(req)
+		return
+	}
+
+	if c.queue.NumRequeues(req) < workQueueRetryLimit {
+		logger.V(3).Info("retrying generate existing request", "error", err.Error())
+		c.queue.AddRateLimited(req)
+		return
+	}
+
+	logger.Error(err, "failed to process generate existing request")
+	c.queue.Forget(req)
+}
+
+func (c *Controller) process(req request) error {
+	policy, err := common.GetPolicy(c.pLister, c.npLister, req.policy)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !req.hasTrigger() {
+		return c.enqueueTriggers(policy)
+	}
+
+	trigger, err := c.client.GetResource(req.apiVersion, req.kind, req.namespace, req.name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			c.log.V(4).Info("trigger resource not found", "kind", req.kind, "namespace", req.namespace, "name", req.name)
+			return nil
+		}
+		return err
+	}
+
+	return c.requestGenerate(policy, trigger)
+}
+
+// enqueueTriggers enqueues the existing resources matched by the generate existing rules of the policy,
+// the triggers of namespaced policies are listed in the namespace of the policy
+func (c *Controller) enqueueTriggers(policy *kyverno.ClusterPolicy) error {
+	key := policyKey(policy)
+	logger := c.log.WithValues("policy", key)
+	for _, rule := range generateExistingRules(policy) {
+		for _, kind := range rule.MatchResources.Kinds {
+			resources, err := c.client.ListResource("", kind, policy.Namespace, rule.MatchResources.Selector)
+			if err != nil {
+				logger.Error(err, "failed to list resources", "kind", kind)
+				continue
+			}
+
+			for _, resource := range resources.Items {
+				c.queue.AddRateLimited(request{
+					policy:     key,
+					apiVersion: resource.GetAPIVersion(),
+					kind:       resource.GetKind(),
+					namespace:  resource.GetNamespace(),
+					name:       resource.GetName(),
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// requestGenerate creates a generate request for the trigger resource when it
+// matches any of the generate existing rules of the policy
+func (c *Controller) requestGenerate(policy *kyverno.ClusterPolicy, trigger *unstructured.Unstructured) error {
+	key := policyKey(policy)
+	logger := c.log.WithValues("policy", key, "kind", trigger.GetKind(), "namespace", trigger.GetNamespace(), "name", trigger.GetName())
+
+	triggerRaw, err := trigger.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to marshal trigger resource: %v", err)
+	}
+
+	ctx := enginectx.NewContext()
+	if err := ctx.AddResource(triggerRaw); err != nil {
+		return fmt.Errorf("failed to load trigger resource in context: %v", err)
+	}
+
+	// only the generate existing rules are evaluated, the other rules are applied on admission
+	policyCopy := policy.DeepCopy()
+	policyCopy.Spec.Rules = generateExistingRules(policy)
+
+	policyContext := &engine.PolicyContext{
+		Policy:              *policyCopy,
+		NewResource:         *trigger,
+		Client:              c.client,
+		ExcludeGroupRole:    c.configHandler.GetExcludeGroupRole(),
+		ExcludeResourceFunc: c.configHandler.ToFilter,
+		ResourceCache:       c.resCache,
+		JSONContext:         ctx,
+		NamespaceLabels:     common.GetNamespaceSelectorsFromNamespaceLister(trigger.GetKind(), trigger.GetNamespace(), c.nsLister, logger),
+	}
+
+	resp := engine.Generate(policyContext)
+	if len(resp.GetSuccessRules()) == 0 {
+		return nil
+	}
+
+	gr := kyverno.GenerateRequestSpec{
+		Policy: key,
+		Resource: kyverno.ResourceSpec{
+			Kind:       resp.PolicyResponse.Resource.Kind,
+			Namespace:  resp.PolicyResponse.Resource.Namespace,
+			Name:       resp.PolicyResponse.Resource.Name,
+			APIVersion: resp.PolicyResponse.Resource.APIVersion,
+		},
+		Context: kyverno.GenerateRequestContext{
+			AdmissionRequestInfo: kyverno.AdmissionRequestInfoObject{
+				Operation: string(v1beta1.Create),
+			},
+		},
+	}
+
+	if err := c.grGenerator.Apply(gr, v1beta1.Create); err != nil {
+		return fmt.Errorf("failed to create generate request: %v", err)
+	}
+
+	logger.V(3).Info("generate request created", "rules", resp.GetSuccessRules())
+	return nil
+}
+
+// policyKey returns the name of cluster policies and the <namespace>/<name> key of namespaced policies
+func policyKey(policy *kyverno.ClusterPolicy) string {
+	if policy.Namespace != "" {
+		return policy.Namespace + "/" + policy.Name
+	}
+	return policy.Name
+}
+
+func convertPolicy(policy *kyverno.Policy) *kyverno.ClusterPolicy {
+	cpol := kyverno.ClusterPolicy(*policy)
+	return &cpol
+}
+
+// generateExistingRules returns the generate rules of the policy which are applied to existing resources
+func generateExistingRules(policy *kyverno.ClusterPolicy) []kyverno.Rule {
+	var rules []kyverno.Rule
+	for _, rule := range policy.Spec.Rules {
+		if rule.HasGenerateExisting() {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
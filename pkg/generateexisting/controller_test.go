@@ -0,0 +1,80 @@
+package generateexisting
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/common"
+	dclient "github.com/kyverno/kyverno/pkg/dclient"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func newConfigMap(namespace, name string) *unstructured.Unstructured {
+	configMap := &unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetNamespace(namespace)
+	configMap.SetName(name)
+	return configMap
+}
+
+func Test_NamespacedPolicy(t *testing.T) {
+	policy := &kyverno.Policy{}
+	assert.NilError(t, json.Unmarshal([]byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "Policy",
+		"metadata": {"name": "copy-settings", "namespace": "team-a"},
+		"spec": {
+			"rules": [{
+				"name": "copy-settings",
+				"match": {"resources": {"kinds": ["ConfigMap"]}},
+				"generate": {"generateExisting": true, "kind": "ConfigMap", "namespace": "team-a", "name": "settings-copy", "data": {"data": {"copied": "true"}}}
+			}]
+		}
+	}`), policy))
+
+	policies := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	assert.NilError(t, policies.Add(policy))
+
+	client, err := dclient.NewMockClient(runtime.NewScheme(), map[schema.GroupVersionResource]string{{Version: "v1", Resource: "configmaps"}: "ConfigMapList"},
+		newConfigMap("team-a", "settings"),
+		newConfigMap("team-b", "settings"),
+	)
+	assert.NilError(t, err)
+	client.SetDiscovery(dclient.NewFakeDiscoveryClient(nil))
+
+	c := &Controller{
+		client:   client,
+		queue:    workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		pLister:  kyvernolister.NewClusterPolicyLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		npLister: kyvernolister.NewPolicyLister(policies),
+		log:      logr.Discard(),
+	}
+	defer c.queue.ShutDown()
+
+	// the namespaced policy is identified by its key
+	c.addNsPolicy(policy)
+	assert.Equal(t, c.queue.Len(), 1)
+	obj, _ := c.queue.Get()
+	req := obj.(request)
+	assert.Equal(t, req, request{policy: "team-a/copy-settings"})
+	c.queue.Done(obj)
+
+	cpol, err := common.GetPolicy(c.pLister, c.npLister, req.policy)
+	assert.NilError(t, err)
+	assert.Equal(t, cpol.Namespace, "team-a")
+
+	// only the triggers in the namespace of the policy are enqueued
+	assert.NilError(t, c.enqueueTriggers(cpol))
+	obj, _ = c.queue.Get()
+	assert.Equal(t, obj.(request), request{policy: "team-a/copy-settings", apiVersion: "v1", kind: "ConfigMap", namespace: "team-a", name: "settings"})
+	assert.Equal(t, c.queue.Len(), 0)
+}
@@ -0,0 +1,243 @@
+package globalcontext
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1"
+	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	workQueueName = "global-context"
+
+	// defaultRefreshInterval is used for entries without a refresh interval
+	defaultRefreshInterval = 10 * time.Minute
+
+	// minRefreshInterval bounds the load of the refreshes on the API server and the external services
+	minRefreshInterval = 10 * time.Second
+
+	// errorRefreshInterval is the maximum interval before an entry that failed to be fetched is fetched again
+	errorRefreshInterval = 30 * time.Second
+)
+
+// entry is the data of a global context entry, or the error of its last refresh
+type entry struct {
+	data       []byte
+	err        error
+	generation int64
+
+	// refreshAt is the time of the next refresh of the entry
+	refreshAt time.Time
+}
+
+// Controller keeps the data of the GlobalContextEntry resources in memory. Each entry is
+// fetched when it is created or updated and then on its refresh interval, the rules of all
+// policies read the data from memory instead of fetching it for each admission request.
+type Controller struct {
+	client *client.Client
+	queue  workqueue.RateLimitingInterface
+
+	lister kyvernolister.GlobalContextEntryLister
+	synced cache.InformerSynced
+
+	mu      sync.RWMutex
+	entries map[string]*entry
+
+	log logr.Logger
+}
+
+// NewController returns a new instance of the global context controller
+func NewController(client *client.Client, informer kyvernoinformer.GlobalContextEntryInformer, log logr.Logger) *Controller {
+	c := &Controller{
+		client:  client,
+		queue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), workQueueName),
+		lister:  informer.Lister(),
+		synced:  informer.Informer().HasSynced,
+		entries: make(map[string]*entry),
+		log:     log,
+	}
+
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.addEntry,
+		UpdateFunc: c.updateEntry,
+		DeleteFunc: c.deleteEntry,
+	})
+
+	return c
+}
+
+// Get returns the JSON data of the global context entry
+func (c *Controller) Get(name string) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("global context entry %s not found", name)
+	}
+
+	if e.err != nil {
+		return nil, fmt.Errorf("global context entry %s is not available: %v", name, e.err)
+	}
+
+	return e.data, nil
+}
+
+func (c *Controller) addEntry(obj interface{}) {
+	c.queue.Add(obj.(*kyverno.GlobalContextEntry).Name)
+}
+
+func (c *Controller) updateEntry(old, cur interface{}) {
+	eOld := old.(*kyverno.GlobalContextEntry)
+	eNew := cur.(*kyverno.GlobalContextEntry)
+	if reflect.DeepEqual(eOld.Spec, eNew.Spec) {
+		return
+	}
+
+	c.queue.Add(eNew.Name)
+}
+
+func (c *Controller) deleteEntry(obj interface{}) {
+	e, ok := obj.(*kyverno.GlobalContextEntry)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			c.log.Info("couldn't get object from tombstone", "obj", obj)
+			return
+		}
+
+		if e, ok = tombstone.Obj.(*kyverno.GlobalContextEntry); !ok {
+			c.log.Info("tombstone contained object that is not a GlobalContextEntry", "obj", obj)
+			return
+		}
+	}
+
+	c.queue.Add(e.Name)
+}
+
+// Run starts the workers
+func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
+	logger := c.log
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger.Info("starting")
+	defer logger.Info("shutting down")
+
+	if !cache.WaitForCacheSync(stopCh, c.synced) {
+		logger.Info("failed to sync informer cache")
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (c *Controller) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	defer c.queue.Done(obj)
+
+	name, ok := obj.(string)
+	if !ok {
+		c.queue.Forget(obj)
+		c.log.Info("incorrect type: expecting type 'string'", "object", obj)
+		return true
+	}
+
+	if err := c.process(name); err != nil {
+		c.log.Error(err, "failed to process global context entry", "name", name)
+	}
+
+	c.queue.Forget(obj)
+	return true
+}
+
+// process fetches the data of the entry and schedules its next refresh
+func (c *Controller) process(name string) error {
+	gctx, err := c.lister.Get(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			c.mu.Lock()
+			delete(c.entries, name)
+			c.mu.Unlock()
+			return nil
+		}
+		return err
+	}
+
+	// an unchanged entry is not fetched before its refresh, it is enqueued again by the scheduled refresh
+	c.mu.RLock()
+	current, ok := c.entries[name]
+	c.mu.RUnlock()
+	if ok && current.generation == gctx.Generation && time.Now().Before(current.refreshAt) {
+		return nil
+	}
+
+	logger := c.log.WithValues("name", name)
+	data, err := engine.FetchGlobalContextData(logger, c.client, name, gctx.Spec)
+
+	interval := refreshInterval(gctx)
+	if err != nil && interval > errorRefreshInterval {
+		interval = errorRefreshInterval
+	}
+
+	e := &entry{generation: gctx.Generation, refreshAt: time.Now().Add(interval)}
+	if err != nil {
+		// the data of the last successful refresh of the same spec is kept until the entry is fetched again
+		e.err = err
+		if ok && current.err == nil && current.generation == gctx.Generation {
+			e.data, e.err = current.data, nil
+		}
+	} else {
+		e.data = data
+	}
+
+	c.mu.Lock()
+	c.entries[name] = e
+	c.mu.Unlock()
+
+	c.queue.AddAfter(name, interval)
+
+	if err != nil {
+		return fmt.Errorf("failed to fetch global context entry: %v", err)
+	}
+
+	logger.V(4).Info("refreshed global context entry", "refreshInterval", interval)
+	return nil
+}
+
+func refreshInterval(gctx *kyverno.GlobalContextEntry) time.Duration {
+	if gctx.Spec.RefreshInterval == nil || gctx.Spec.RefreshInterval.Duration == 0 {
+		return defaultRefreshInterval
+	}
+
+	if gctx.Spec.RefreshInterval.Duration < minRefreshInterval {
+		return minRefreshInterval
+	}
+
+	return gctx.Spec.RefreshInterval.Duration
+}
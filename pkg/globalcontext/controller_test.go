@@ -0,0 +1,89 @@
+package globalcontext
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned/fake"
+	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_Controller(t *testing.T) {
+	version := 1
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"version": %d, "teams": ["payments", "search"]}`, version)
+	}))
+	defer server.Close()
+
+	gctx := &kyverno.GlobalContextEntry{
+		ObjectMeta: metav1.ObjectMeta{Name: "teams", Generation: 1},
+		Spec: kyverno.GlobalContextEntrySpec{
+			ServiceCall: &kyverno.ServiceCall{
+				URL:      server.URL + "/teams",
+				CABundle: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+				JMESPath: "{version: version, count: length(teams)}",
+			},
+			RefreshInterval: &metav1.Duration{Duration: time.Hour},
+		},
+	}
+
+	informer := kyvernoinformer.NewSharedInformerFactory(fake.NewSimpleClientset(), 0).Kyverno().V1().GlobalContextEntries()
+	c := NewController(nil, informer, log.Log)
+	assert.NilError(t, informer.Informer().GetIndexer().Add(gctx))
+
+	_, err := c.Get("teams")
+	assert.ErrorContains(t, err, "global context entry teams not found")
+
+	assert.NilError(t, c.process("teams"))
+	data, err := c.Get("teams")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"count":2,"version":1}`)
+
+	// the entry is not fetched again before its refresh interval
+	version = 2
+	assert.NilError(t, c.process("teams"))
+	data, err = c.Get("teams")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"count":2,"version":1}`)
+
+	// an updated entry is fetched immediately
+	gctx = gctx.DeepCopy()
+	gctx.Generation = 2
+	assert.NilError(t, informer.Informer().GetIndexer().Update(gctx))
+	assert.NilError(t, c.process("teams"))
+	data, err = c.Get("teams")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"count":2,"version":2}`)
+
+	// the data is kept when a refresh fails
+	c.entries["teams"].refreshAt = time.Now()
+	server.Close()
+	assert.ErrorContains(t, c.process("teams"), "failed to fetch global context entry")
+	data, err = c.Get("teams")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"count":2,"version":2}`)
+
+	assert.NilError(t, informer.Informer().GetIndexer().Delete(gctx))
+	assert.NilError(t, c.process("teams"))
+	_, err = c.Get("teams")
+	assert.ErrorContains(t, err, "global context entry teams not found")
+}
+
+func Test_refreshInterval(t *testing.T) {
+	gctx := &kyverno.GlobalContextEntry{}
+	assert.Equal(t, refreshInterval(gctx), defaultRefreshInterval)
+
+	gctx.Spec.RefreshInterval = &metav1.Duration{Duration: time.Second}
+	assert.Equal(t, refreshInterval(gctx), minRefreshInterval)
+
+	gctx.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+	assert.Equal(t, refreshInterval(gctx), time.Hour)
+}
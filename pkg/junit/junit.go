@@ -0,0 +1,113 @@
+// Package junit exports the results of the policies as JUnit XML test reports,
+// e.g. to render them in CI pipelines.
+package junit
+
+import (
+	"encoding/xml"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+)
+
+// TestSuites is the root element of the report, with a test suite per policy
+type TestSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Suites   []TestSuite `xml:"testsuite"`
+}
+
+// TestSuite holds the test cases of a policy
+type TestSuite struct {
+	Name     string     `xml:"name,attr"`
+	Tests    int        `xml:"tests,attr"`
+	Failures int        `xml:"failures,attr"`
+	Cases    []TestCase `xml:"testcase"`
+}
+
+// TestCase is the result of a rule on a resource
+type TestCase struct {
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	Time      float64  `xml:"time,attr"`
+	Failure   *Failure `xml:"failure,omitempty"`
+}
+
+// Failure is the message of a failed rule
+type Failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Build returns the JUnit report of the validation rules of the engine responses,
+// with a test case per rule and resource
+func Build(responses []*response.EngineResponse) *TestSuites {
+	report := &TestSuites{Name: "kyverno"}
+	suites := map[string]int{}
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+
+		policy := resp.PolicyResponse
+		index, ok := suites[policy.Policy]
+		if !ok {
+			index = len(report.Suites)
+			suites[policy.Policy] = index
+			report.Suites = append(report.Suites, TestSuite{Name: policy.Policy})
+		}
+
+		suite := &report.Suites[index]
+		for _, rule := range policy.Rules {
+			if rule.Type != utils.Validation.String() {
+				continue
+			}
+
+			testCase := TestCase{
+				Name:      rule.Name + " " + resourceName(policy.Resource),
+				ClassName: policy.Policy + "." + rule.Name,
+				Time:      rule.RuleStats.ProcessingTime.Seconds(),
+			}
+
+			if !rule.Success {
+				testCase.Failure = &Failure{Message: rule.Message, Type: failureType(policy.ValidationFailureAction), Text: rule.Message}
+				suite.Failures++
+				report.Failures++
+			}
+
+			suite.Cases = append(suite.Cases, testCase)
+			suite.Tests++
+			report.Tests++
+		}
+	}
+
+	return report
+}
+
+// Marshal returns the indented XML of the report
+func (t *TestSuites) Marshal() ([]byte, error) {
+	data, err := xml.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+func resourceName(resource response.ResourceSpec) string {
+	if resource.Namespace == "" {
+		return resource.Kind + "/" + resource.Name
+	}
+
+	return resource.Kind + "/" + resource.Namespace + "/" + resource.Name
+}
+
+func failureType(validationFailureAction string) string {
+	if validationFailureAction == "" {
+		return "audit"
+	}
+
+	return validationFailureAction
+}
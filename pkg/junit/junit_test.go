@@ -0,0 +1,52 @@
+package junit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"gotest.tools/assert"
+)
+
+func Test_Build(t *testing.T) {
+	responses := []*response.EngineResponse{
+		{
+			PolicyResponse: response.PolicyResponse{
+				Policy:                  "require-labels",
+				Resource:                response.ResourceSpec{Kind: "Pod", Namespace: "default", Name: "nginx"},
+				ValidationFailureAction: "enforce",
+				Rules: []response.RuleResponse{
+					{Name: "require-team", Type: "Validation", Message: "the team label is required"},
+					{Name: "require-app", Type: "Validation", Success: true},
+					{Name: "add-labels", Type: "Mutation", Success: true},
+				},
+			},
+		},
+		{
+			PolicyResponse: response.PolicyResponse{
+				Policy:   "require-labels",
+				Resource: response.ResourceSpec{Kind: "Namespace", Name: "prod"},
+				Rules: []response.RuleResponse{
+					{Name: "require-team", Type: "Validation", Success: true},
+				},
+			},
+		},
+	}
+
+	report := Build(responses)
+	assert.Equal(t, report.Tests, 3)
+	assert.Equal(t, report.Failures, 1)
+	assert.Equal(t, len(report.Suites), 1)
+
+	suite := report.Suites[0]
+	assert.Equal(t, suite.Name, "require-labels")
+	assert.Equal(t, len(suite.Cases), 3)
+	assert.Equal(t, suite.Cases[0].Name, "require-team Pod/default/nginx")
+	assert.Equal(t, suite.Cases[0].Failure.Type, "enforce")
+	assert.Assert(t, suite.Cases[1].Failure == nil)
+	assert.Equal(t, suite.Cases[2].Name, "require-team Namespace/prod")
+
+	data, err := report.Marshal()
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(data), `<failure message="the team label is required" type="enforce">the team label is required</failure>`))
+}
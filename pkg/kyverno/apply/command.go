@@ -2,9 +2,9 @@ package apply
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"reflect"
 	"strings"
 	"time"
 
@@ -17,9 +17,9 @@ import (
 	sanitizederror "github.com/kyverno/kyverno/pkg/kyverno/sanitizedError"
 	"github.com/kyverno/kyverno/pkg/openapi"
 	policy2 "github.com/kyverno/kyverno/pkg/policy"
+	"github.com/kyverno/kyverno/pkg/sarif"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/cli-runtime/pkg/genericclioptions"
 	log "sigs.k8s.io/controller-runtime/pkg/log"
 	yaml1 "sigs.k8s.io/yaml"
 )
@@ -33,8 +33,8 @@ type resultCounts struct {
 }
 
 type Resource struct {
-	Name   string            `json:"name"`
-	Values map[string]string `json:"values"`
+	Name   string                 `json:"name"`
+	Values map[string]interface{} `json:"values"`
 }
 
 type Policy struct {
@@ -59,6 +59,72 @@ To apply on a resource:
 To apply on a cluster:
 	kyverno apply /path/to/policy.yaml /path/to/folderOfPolicies --cluster
 
+To apply the policies and the resources of directories, URLs or git repositories:
+	kyverno apply /path/to/folderOfPolicies --resource=/path/to/folderOfResources
+	kyverno apply https://raw.githubusercontent.com/<owner>/<repository>/<branch>/policy.yaml --resource=/path/to/resource.yaml
+	kyverno apply git::https://github.com/<owner>/<repository>//<directory>?ref=<branch, tag or commit> --resource=git::https://github.com/<owner>/<repository>//<directory>
+
+	The directories are read recursively, the YAML files which are not policies or resources are skipped.
+
+To apply the policies of the cluster on its resources, in the context of the kubeconfig:
+	kyverno apply --cluster --context=<context>
+
+To apply as a kubectl plugin, in the current context of kubectl:
+	kubectl kyverno apply --cluster --namespace=<namespace>
+
+To print the mutations side by side instead of a unified diff:
+	kyverno apply /path/to/policy.yaml --resource=/path/to/resource.yaml --diff=side-by-side
+
+To print the result of each rule applied on the resources:
+	kyverno apply /path/to/policy.yaml --resource=/path/to/resource.yaml --detailed-results
+
+To export the failed validation rules in SARIF, e.g. to upload them to code scanning:
+	kyverno apply /path/to/policy.yaml --resource=/path/to/resource.yaml --sarif=results.sarif
+
+To print the results of the validation rules in a machine-readable format, as json, yaml or sarif:
+	kyverno apply /path/to/policy.yaml --resource=/path/to/resource.yaml --output-format=json
+
+To export the results of the validation rules as JUnit XML, with a test case per rule and resource:
+	kyverno apply /path/to/policy.yaml --resource=/path/to/resource.yaml --junit=results.xml
+
+To fail the pipeline with a distinct exit code on the failures of the policies which are not scored:
+	kyverno apply /path/to/policy.yaml --resource=/path/to/resource.yaml --warn-exit-code=4
+
+	The failures of the policies annotated with policies.kyverno.io/scored: "false" are counted as
+	warnings. The command exits with:
+		0 when all the rules passed, or with the warn exit code when there are only warnings
+		1 when a rule failed
+		2 when a policy could not be applied on a resource
+		3 when the flags, the policies or the resources are invalid
+
+To apply on the manifests rendered by a helm chart, e.g. to check a release before installing it:
+	kyverno apply /path/to/policy.yaml --helm=/path/to/chart --helm-values=/path/to/values.yaml
+
+	The chart is rendered as helm install renders it, in the namespace of the namespace flag. The
+	values files of the chart are passed with the helm-values flag, the values-file flag is the file
+	of the values of the variables of the policies.
+
+To apply on the resources built by a kustomization, e.g. an overlay of a GitOps repository:
+	kyverno apply /path/to/policy.yaml -k /path/to/overlay
+
+	The kustomization is built as kustomize build builds it, the resources without a namespace are in
+	the namespace of the namespace flag.
+
+To apply on the Kubernetes resources of a terraform plan, e.g. to check a plan before applying it:
+	terraform plan -out=plan.out && terraform show -json plan.out > plan.json
+	kyverno apply /path/to/policy.yaml --terraform-plan=plan.json
+
+	The resources created or updated by the plan are the manifests of the kubernetes_manifest and
+	kubectl_manifest resources, the resources without a namespace are in the namespace of the
+	namespace flag. The other resources of the kubernetes provider are not applied.
+
+To validate the resources and the mutated resources against the OpenAPI schemas of Kubernetes:
+	kyverno apply /path/to/policy.yaml --resource=/path/to/resource.yaml --validate-schemas
+	kyverno apply /path/to/policy.yaml --resource=/path/to/resource.yaml --kubernetes-version=v1.20.0
+
+	The bundled schemas are used by default, the schemas of the Kubernetes version are downloaded
+	once and cached. The command exits with 3 when a resource is not valid, the mutated resources
+	which are not valid are counted as errors. The kinds without a schema are not validated.
 
 To apply policy with variables:
 
@@ -72,6 +138,8 @@ To apply policy with variables:
 
 		Format of value.yaml:
 
+		globalValues:
+			<variable of all policies and resources>: <value>
 		policies:
 			- name: <policy1 name>
 				resources:
@@ -94,14 +162,31 @@ To apply policy with variables:
 					<variable1 in policy2>: <value>
 					<variable2 in policy2>: <value>
 
+	The values of the variables which are only available in the cluster, such as the user info, the
+	namespace labels or the data of a context entry, are passed with their names, e.g.:
+
+		globalValues:
+			request.userInfo.username: admin
+			request.userInfo.groups: [system:masters]
+			namespaceLabels:
+				env: prod
+			dictionary.data.allowedRegistry: ghcr.io
+
+	The values may be lists or objects. The context entry of a rule is not fetched when its value is passed.
+
 More info: https://kyverno.io/docs/kyverno-cli/
 `
 
 func Command() *cobra.Command {
 	var cmd *cobra.Command
 	var resourcePaths []string
-	var cluster, policyReport bool
-	var mutateLogPath, variablesString, valuesFile, namespace string
+	var cluster, policyReport, detailedResults bool
+	var mutateLogPath, mutateDiff, variablesString, valuesFile, namespace, sarifPath, junitPath, outputFormat string
+	var helmChart, kustomization, terraformPlan string
+	var helmValues []string
+	var warnExitCode int
+	var validateSchemas bool
+	var kubernetesVersion string
 
 	cmd = &cobra.Command{
 		Use:     "apply",
@@ -110,44 +195,91 @@ func Command() *cobra.Command {
 		RunE: func(cmd *cobra.Command, policyPaths []string) (err error) {
 			defer func() {
 				if err != nil {
-					if !sanitizederror.IsErrorSanitized(err) {
+					if !sanitizederror.IsErrorSanitized(err) && !common.IsExitError(err) {
 						log.Log.Error(err, "failed to sanitize")
 						err = fmt.Errorf("internal error")
 					}
 				}
 			}()
 
-			validateEngineResponses, rc, resources, skippedPolicies, err := applyCommandHelper(resourcePaths, cluster, policyReport, mutateLogPath, variablesString, valuesFile, namespace, policyPaths)
+			if outputFormat != "" {
+				if err := common.ValidateOutputFormat(outputFormat); err != nil {
+					return sanitizederror.NewWithError("invalid output format", err)
+				}
+			}
+
+			// the other messages are printed to stderr, so that the results parsed by the
+			// pipelines are the only output
+			var out io.Writer = os.Stdout
+			if common.IsMachineReadable(outputFormat) {
+				out = os.Stderr
+			}
+
+			validateEngineResponses, rc, resources, skippedPolicies, err := applyCommandHelper(out, resourcePaths, cluster, policyReport, detailedResults, mutateLogPath, mutateDiff, variablesString, valuesFile, namespace, policyPaths, helmChart, helmValues, kustomization, terraformPlan, validateSchemas, kubernetesVersion)
 			if err != nil {
 				return err
 			}
 
-			printReportOrViolation(policyReport, validateEngineResponses, rc, resourcePaths, len(resources), skippedPolicies)
-			return nil
+			if sarifPath != "" {
+				if err := writeSarif(sarifPath, validateEngineResponses, resourcePaths, cluster); err != nil {
+					return err
+				}
+			}
+
+			if junitPath != "" {
+				if err := writeJUnit(junitPath, validateEngineResponses); err != nil {
+					return err
+				}
+			}
+
+			if outputFormat != "" {
+				var locate sarif.Locator
+				if !cluster {
+					locate = resourceLocator(resourcePaths)
+				}
+
+				if err := common.PrintResults(os.Stdout, outputFormat, validateEngineResponses, locate); err != nil {
+					return sanitizederror.NewWithError("failed to print the results", err)
+				}
+			}
+
+			code := printReportOrViolation(out, policyReport, validateEngineResponses, rc, resourcePaths, len(resources), skippedPolicies, warnExitCode)
+			return common.ResultsError(cmd, code)
 		},
 	}
 
 	cmd.Flags().StringArrayVarP(&resourcePaths, "resource", "r", []string{}, "Path to resource files")
 	cmd.Flags().BoolVarP(&cluster, "cluster", "c", false, "Checks if policies should be applied to cluster in the current context")
 	cmd.Flags().StringVarP(&mutateLogPath, "output", "o", "", "Prints the mutated resources in provided file/directory")
+	cmd.Flags().StringVarP(&mutateDiff, "diff", "", common.DiffUnified, "Prints the mutations as a unified or side-by-side diff of the original and the mutated resource, or none to print the mutated resource")
 	cmd.Flags().StringVarP(&variablesString, "set", "s", "", "Variables that are required")
 	cmd.Flags().StringVarP(&valuesFile, "values-file", "f", "", "File containing values for policy variables")
 	cmd.Flags().BoolVarP(&policyReport, "policy-report", "", false, "Generates policy report when passed (default policyviolation r")
-	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Optional Policy parameter passed with cluster flag")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the resources and the policies fetched with the cluster flag, all namespaces by default")
+	cmd.Flags().BoolVarP(&detailedResults, "detailed-results", "", false, "Prints the result of each mutation, validation and generation rule applied on the resources")
+	cmd.Flags().StringVarP(&sarifPath, "sarif", "", "", "Writes the failed validation rules in SARIF to the provided file, e.g. to upload them to code scanning")
+	cmd.Flags().StringVarP(&outputFormat, "output-format", "", "", "Prints the results of the validation rules as a table, or in json, yaml or sarif format, the other messages are printed to stderr with the machine-readable formats")
+	cmd.Flags().StringVarP(&junitPath, "junit", "", "", "Writes the results of the validation rules as JUnit XML to the provided file, e.g. to render them in CI pipelines")
+	cmd.Flags().StringVarP(&helmChart, "helm", "", "", "Path to a helm chart, a directory or a packaged chart, whose rendered manifests are the resources")
+	cmd.Flags().StringArrayVarP(&helmValues, "helm-values", "", []string{}, "Path to a values file of the helm chart, the last file takes precedence")
+	cmd.Flags().StringVarP(&kustomization, "kustomize", "k", "", "Path to a kustomization directory, whose built resources are the resources")
+	cmd.Flags().StringVarP(&terraformPlan, "terraform-plan", "", "", "Path to a terraform plan in JSON, as terraform show -json prints it, whose Kubernetes manifests are the resources")
+	cmd.Flags().BoolVarP(&validateSchemas, "validate-schemas", "", false, "Validates the resources and the mutated resources against the OpenAPI schemas of Kubernetes, the bundled schemas by default")
+	cmd.Flags().StringVarP(&kubernetesVersion, "kubernetes-version", "", "", "Kubernetes version, e.g. v1.20.0, whose OpenAPI schemas validate the resources, the schemas are downloaded once and cached")
+	cmd.Flags().IntVarP(&warnExitCode, "warn-exit-code", "", common.ExitPassed, "Exit code when the only failures are the failures of the policies which are not scored")
 	return cmd
 }
 
-func applyCommandHelper(resourcePaths []string, cluster bool, policyReport bool, mutateLogPath string,
-	variablesString string, valuesFile string, namespace string, policyPaths []string) (validateEngineResponses []*response.EngineResponse, rc *resultCounts, resources []*unstructured.Unstructured, skippedPolicies []SkippedPolicy, err error) {
+func applyCommandHelper(out io.Writer, resourcePaths []string, cluster bool, policyReport bool, detailedResults bool, mutateLogPath string,
+	mutateDiff string, variablesString string, valuesFile string, namespace string, policyPaths []string, helmChart string, helmValues []string, kustomization string, terraformPlan string, validateSchemas bool, kubernetesVersion string) (validateEngineResponses []*response.EngineResponse, rc *resultCounts, resources []*unstructured.Unstructured, skippedPolicies []SkippedPolicy, err error) {
 
-	kubernetesConfig := genericclioptions.NewConfigFlags(true)
 	fs := memfs.New()
 
-	if valuesFile != "" && variablesString != "" {
-		return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("pass the values either using set flag or values_file flag", err)
+	if err := common.ValidateDiffStyle(mutateDiff); err != nil {
+		return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("invalid diff flag", err)
 	}
 
-	variables, valuesMap, err := common.GetVariable(variablesString, valuesFile, fs, false, "")
+	variables, globalValues, valuesMap, err := common.GetVariable(variablesString, valuesFile, fs, false, "")
 	if err != nil {
 		if !sanitizederror.IsErrorSanitized(err) {
 			return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("failed to decode yaml", err)
@@ -160,9 +292,18 @@ func applyCommandHelper(resourcePaths []string, cluster bool, policyReport bool,
 		return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("failed to initialize openAPIController", err)
 	}
 
+	// the resources are validated against the schemas of the version when a version is passed
+	var schemaController *openapi.Controller
+	if validateSchemas || kubernetesVersion != "" {
+		schemaController, err = common.LoadSchemas(kubernetesVersion)
+		if err != nil {
+			return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("failed to load the schemas", err)
+		}
+	}
+
 	var dClient *client.Client
 	if cluster {
-		restConfig, err := kubernetesConfig.ToRESTConfig()
+		restConfig, err := common.RESTConfig()
 		if err != nil {
 			return validateEngineResponses, rc, resources, skippedPolicies, err
 		}
@@ -172,7 +313,7 @@ func applyCommandHelper(resourcePaths []string, cluster bool, policyReport bool,
 		}
 	}
 
-	if len(policyPaths) == 0 {
+	if len(policyPaths) == 0 && !cluster {
 		return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError(fmt.Sprintf("require policy"), err)
 	}
 
@@ -180,14 +321,31 @@ func applyCommandHelper(resourcePaths []string, cluster bool, policyReport bool,
 		return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("a stdin pipe can be used for either policies or resources, not both", err)
 	}
 
-	policies, err := common.GetPoliciesFromPaths(fs, policyPaths, false, "")
+	var policies []*v1.ClusterPolicy
+	if len(policyPaths) == 0 {
+		// the live policies are applied when no policy is passed with the cluster flag
+		policies, err = common.GetPoliciesFromCluster(dClient, namespace)
+	} else {
+		policies, err = common.GetPoliciesFromPaths(fs, policyPaths, false, "")
+	}
 	if err != nil {
-		fmt.Printf("Error: failed to load policies\nCause: %s\n", err)
-		os.Exit(1)
+		return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("failed to load policies", err)
+	}
+
+	if len(resourcePaths) == 0 && !cluster && helmChart == "" && kustomization == "" && terraformPlan == "" {
+		return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError(fmt.Sprintf("resource file(s), helm chart, kustomization, terraform plan or cluster required"), err)
+	}
+
+	if helmChart != "" && cluster {
+		return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("a helm chart cannot be applied with the cluster flag", nil)
+	}
+
+	if kustomization != "" && cluster {
+		return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("a kustomization cannot be applied with the cluster flag", nil)
 	}
 
-	if len(resourcePaths) == 0 && !cluster {
-		return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError(fmt.Sprintf("resource file(s) or cluster required"), err)
+	if terraformPlan != "" && cluster {
+		return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("a terraform plan cannot be applied with the cluster flag", nil)
 	}
 
 	mutateLogPathIsDir, err := checkMutateLogPath(mutateLogPath)
@@ -207,8 +365,44 @@ func applyCommandHelper(resourcePaths []string, cluster bool, policyReport bool,
 
 	resources, err = common.GetResourceAccordingToResourcePath(fs, resourcePaths, cluster, mutatedPolicies, dClient, namespace, policyReport, false, "")
 	if err != nil {
-		fmt.Printf("Error: failed to load resources\nCause: %s\n", err)
-		os.Exit(1)
+		return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("failed to load resources", err)
+	}
+
+	if helmChart != "" {
+		chartResources, err := renderHelmChart(helmChart, helmValues, namespace, openAPIController)
+		if err != nil {
+			return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("failed to render the helm chart", err)
+		}
+		resources = append(resources, chartResources...)
+	}
+
+	if kustomization != "" {
+		kustomizationResources, err := buildKustomization(kustomization, namespace, openAPIController)
+		if err != nil {
+			return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("failed to build the kustomization", err)
+		}
+		resources = append(resources, kustomizationResources...)
+	}
+
+	if terraformPlan != "" {
+		planResources, err := readTerraformPlan(terraformPlan, namespace, openAPIController)
+		if err != nil {
+			return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("failed to read the terraform plan", err)
+		}
+		resources = append(resources, planResources...)
+	}
+
+	if schemaController != nil {
+		invalid := false
+		for _, resource := range resources {
+			if err := common.ValidateResourceSchema(schemaController, resource); err != nil {
+				fmt.Fprintf(out, "Error: resource %s/%s/%s is not valid\nCause: %s\n", resource.GetNamespace(), resource.GetKind(), resource.GetName(), err)
+				invalid = true
+			}
+		}
+		if invalid {
+			return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError("the resources are not valid", nil)
+		}
 	}
 
 	msgPolicies := "1 policy"
@@ -222,7 +416,7 @@ func applyCommandHelper(resourcePaths []string, cluster bool, policyReport bool,
 	}
 
 	if len(mutatedPolicies) > 0 && len(resources) > 0 {
-		fmt.Printf("\napplying %s to %s... \n", msgPolicies, msgResources)
+		fmt.Fprintf(out, "\napplying %s to %s... \n", msgPolicies, msgResources)
 	}
 
 	rc = &resultCounts{}
@@ -255,31 +449,40 @@ func applyCommandHelper(resourcePaths []string, cluster bool, policyReport bool,
 
 		for _, resource := range resources {
 			// get values from file for this policy resource combination
-			thisPolicyResourceValues := make(map[string]string)
-			if len(valuesMap[policy.GetName()]) != 0 && !reflect.DeepEqual(valuesMap[policy.GetName()][resource.GetName()], Resource{}) {
-				thisPolicyResourceValues = valuesMap[policy.GetName()][resource.GetName()].Values
-			}
-
-			for k, v := range variables {
-				thisPolicyResourceValues[k] = v
-			}
+			resourceValues := valuesMap[policy.GetName()][resource.GetName()].Values
+			thisPolicyResourceValues := common.MergeVariables(globalValues, resourceValues, variables)
 
 			if len(common.PolicyHasVariables(*policy)) > 0 && len(thisPolicyResourceValues) == 0 {
 				return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError(fmt.Sprintf("policy %s have variables. pass the values for the variables using set/values_file flag", policy.Name), err)
 			}
 
-			ers, validateErs, responseError, rcErs, err := common.ApplyPolicyOnResource(policy, resource, mutateLogPath, mutateLogPathIsDir, thisPolicyResourceValues, policyReport)
+			ers, validateErs, responseError, rcErs, err := common.ApplyPolicyOnResource(out, policy, resource, mutateLogPath, mutateLogPathIsDir, mutateDiff, thisPolicyResourceValues, policyReport)
 			if err != nil {
-				return validateEngineResponses, rc, resources, skippedPolicies, sanitizederror.NewWithError(fmt.Errorf("failed to apply policy %v on resource %v", policy.Name, resource.GetName()).Error(), err)
+				applyErr := sanitizederror.NewWithError(fmt.Sprintf("failed to apply policy %v on resource %v", policy.Name, resource.GetName()), err)
+				return validateEngineResponses, rc, resources, skippedPolicies, &common.ExitError{Code: common.ExitErrors, Err: applyErr}
 			}
 			if responseError == true {
-				rc.fail++
+				// the failures of the policies which are not scored do not fail the command
+				if policy.IsScored() {
+					rc.fail++
+				} else {
+					rc.warn++
+				}
 			} else {
 				rc.pass++
 			}
 			if rcErs == true {
 				rc.error++
 			}
+			if schemaController != nil && len(ers) > 0 {
+				if err := common.ValidateMutatedResourceSchema(schemaController, ers[0]); err != nil {
+					fmt.Fprintf(out, "\npolicy %s -> mutated resource %s is not valid: %s\n", policy.Name, resource.GetName(), err)
+					rc.error++
+				}
+			}
+			if detailedResults {
+				printRuleResults(out, append(ers, validateErs))
+			}
 			engineResponses = append(engineResponses, ers...)
 			validateEngineResponses = append(validateEngineResponses, validateErs)
 		}
@@ -310,18 +513,18 @@ func checkMutateLogPath(mutateLogPath string) (mutateLogPathIsDir bool, err erro
 	return mutateLogPathIsDir, err
 }
 
-// printReportOrViolation - printing policy report/violations
-func printReportOrViolation(policyReport bool, validateEngineResponses []*response.EngineResponse, rc *resultCounts, resourcePaths []string, resourcesLen int, skippedPolicies []SkippedPolicy) {
+// printReportOrViolation - printing policy report/violations, and returns the exit code of the results
+func printReportOrViolation(out io.Writer, policyReport bool, validateEngineResponses []*response.EngineResponse, rc *resultCounts, resourcePaths []string, resourcesLen int, skippedPolicies []SkippedPolicy, warnExitCode int) int {
 	if policyReport {
 		os.Setenv("POLICY-TYPE", pkgCommon.PolicyReport)
 		resps := buildPolicyReports(validateEngineResponses, skippedPolicies)
 		if len(resps) > 0 || resourcesLen == 0 {
-			fmt.Println("----------------------------------------------------------------------\nPOLICY REPORT:\n----------------------------------------------------------------------")
+			fmt.Fprintln(out, "----------------------------------------------------------------------\nPOLICY REPORT:\n----------------------------------------------------------------------")
 			report, _ := generateCLIraw(resps)
 			yamlReport, _ := yaml1.Marshal(report)
-			fmt.Println(string(yamlReport))
+			fmt.Fprintln(out, string(yamlReport))
 		} else {
-			fmt.Println("----------------------------------------------------------------------\nPOLICY REPORT: skip generating policy report (no validate policy found/resource skipped)")
+			fmt.Fprintln(out, "----------------------------------------------------------------------\nPOLICY REPORT: skip generating policy report (no validate policy found/resource skipped)")
 		}
 	} else {
 		rcCount := rc.pass + rc.fail + rc.warn + rc.error + rc.skip
@@ -329,13 +532,11 @@ func printReportOrViolation(policyReport bool, validateEngineResponses []*respon
 			rc.skip += len(resourcePaths) - rcCount
 		}
 
-		fmt.Printf("\npass: %d, fail: %d, warn: %d, error: %d, skip: %d \n",
+		fmt.Fprintf(out, "\npass: %d, fail: %d, warn: %d, error: %d, skip: %d \n",
 			rc.pass, rc.fail, rc.warn, rc.error, rc.skip)
-
-		if rc.fail > 0 || rc.error > 0 {
-			os.Exit(1)
-		}
 	}
+
+	return common.ExitCode(rc.fail, rc.warn, rc.error, warnExitCode)
 }
 
 // createFileOrFolder - creating file or folder according to path provided
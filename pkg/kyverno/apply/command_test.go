@@ -1,9 +1,12 @@
 package apply
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	preport "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"github.com/kyverno/kyverno/pkg/kyverno/common"
 	"gotest.tools/assert"
 )
 
@@ -56,10 +59,38 @@ func Test_Apply(t *testing.T) {
 	}
 
 	for _, tc := range testcases {
-		validateEngineResponses, _, _, skippedPolicies, _ := applyCommandHelper(tc.ResourcePaths, false, true, "", "", "", "", tc.PolicyPaths)
+		validateEngineResponses, _, _, skippedPolicies, _ := applyCommandHelper(ioutil.Discard, tc.ResourcePaths, false, true, false, "", common.DiffNone, "", "", "", tc.PolicyPaths, "", nil, "", "", false, "")
 		resps := buildPolicyReports(validateEngineResponses, skippedPolicies)
 		for i, resp := range resps {
 			compareSummary(tc.expectedPolicyReports[i].Summary, resp.UnstructuredContent()["summary"].(map[string]interface{}))
 		}
 	}
 }
+
+func Test_PrintReportOrViolation_ExitCode(t *testing.T) {
+	rc := &resultCounts{pass: 1, fail: 1}
+	assert.Equal(t, printReportOrViolation(ioutil.Discard, false, nil, rc, nil, 1, nil, common.ExitPassed), common.ExitViolations)
+	assert.Equal(t, printReportOrViolation(ioutil.Discard, true, nil, rc, nil, 1, nil, common.ExitPassed), common.ExitViolations)
+
+	rc = &resultCounts{pass: 1, warn: 1}
+	assert.Equal(t, printReportOrViolation(ioutil.Discard, true, nil, rc, nil, 1, nil, 4), 4)
+}
+
+func Test_Apply_InvalidResourceSchema(t *testing.T) {
+	resourcePath := filepath.Join(t.TempDir(), "pod.yaml")
+	assert.NilError(t, ioutil.WriteFile(resourcePath, []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+spec:
+  containers:
+  - name: nginx
+    image: nginx:1.12
+    imagePullPolicy: Sometimes
+  restartPolicies: Always
+`), 0600))
+
+	_, _, _, _, err := applyCommandHelper(ioutil.Discard, []string{resourcePath}, false, false, false, "", common.DiffNone, "", "", "", []string{"../../../samples/best_practices/disallow_latest_tag.yaml"}, "", nil, "", "", true, "")
+	assert.Error(t, err, "the resources are not valid")
+	assert.Equal(t, common.ExitCodeOf(err), common.ExitInvalidInput)
+}
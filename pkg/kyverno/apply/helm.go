@@ -0,0 +1,167 @@
+package apply
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/openapi"
+	pkgutils "github.com/kyverno/kyverno/pkg/utils"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// helmReleaseName is the name of the release of the rendered charts, as the release of helm template
+const helmReleaseName = "release-name"
+
+// renderHelmChart renders the chart, a directory or a packaged chart, as helm install renders it
+// in the namespace, and returns the resources of its manifests. The values files are merged in
+// order, the last file takes precedence.
+func renderHelmChart(chartPath string, valuesFiles []string, namespace string, openAPIController *openapi.Controller) ([]*unstructured.Unstructured, error) {
+	chart, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s: %v", chartPath, err)
+	}
+
+	if chart.Metadata.Type == "library" {
+		return nil, fmt.Errorf("chart %s is a library chart, it cannot be installed", chartPath)
+	}
+
+	values := map[string]interface{}{}
+	for _, valuesFile := range valuesFiles {
+		fileValues, err := chartutil.ReadValuesFile(valuesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read values file %s: %v", valuesFile, err)
+		}
+		values = chartutil.CoalesceTables(fileValues, values)
+	}
+
+	if err := chartutil.ProcessDependencies(chart, values); err != nil {
+		return nil, fmt.Errorf("failed to process the dependencies of chart %s: %v", chartPath, err)
+	}
+
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	options := chartutil.ReleaseOptions{
+		Name:      helmReleaseName,
+		Namespace: namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	renderValues, err := chartutil.ToRenderValues(chart, values, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the values of chart %s: %v", chartPath, err)
+	}
+
+	manifests, err := engine.Render(chart, renderValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %s: %v", chartPath, err)
+	}
+
+	names := make([]string, 0, len(manifests))
+	for name := range manifests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var resources []*unstructured.Unstructured
+	for _, name := range names {
+		// the notes are printed by helm, they are not manifests
+		if filepath.Base(name) == "NOTES.txt" {
+			continue
+		}
+
+		documents, err := pkgutils.SplitYAMLDocuments([]byte(manifests[name]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest %s: %v", name, err)
+		}
+
+		for _, document := range documents {
+			resource, err := manifestToUnstructured(document)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode manifest %s: %v", name, err)
+			}
+			resources = append(resources, resource)
+		}
+	}
+
+	setDefaultNamespace(resources, namespace, openAPIController)
+	return resources, nil
+}
+
+// manifestToUnstructured converts a rendered manifest, of a chart or of a kustomization, the custom
+// resources of the manifests are not registered in the scheme
+func manifestToUnstructured(manifest []byte) (*unstructured.Unstructured, error) {
+	manifestJSON, err := yaml.YAMLToJSON(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := utils.ConvertToUnstructured(manifestJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if resource.GetKind() == "" || resource.GetAPIVersion() == "" {
+		return nil, fmt.Errorf("the manifest is not a resource, the apiVersion and the kind are required")
+	}
+
+	return resource, nil
+}
+
+// setDefaultNamespace sets the namespace of the namespaced resources without a namespace, the
+// resources are created in the namespace
+func setDefaultNamespace(resources []*unstructured.Unstructured, namespace string, openAPIController *openapi.Controller) {
+	scopes := newResourceScopes(openAPIController, resources)
+	for _, resource := range resources {
+		if resource.GetNamespace() == "" && scopes.namespaced(resource) {
+			resource.SetNamespace(namespace)
+		}
+	}
+}
+
+// resourceScopes are the scopes of the kinds of the resources, of the OpenAPI schemas and of the
+// custom resource definitions of the resources
+type resourceScopes struct {
+	openAPIController *openapi.Controller
+	// crds holds the group kind - namespaced map of the custom resource definitions
+	crds map[string]bool
+}
+
+func newResourceScopes(openAPIController *openapi.Controller, resources []*unstructured.Unstructured) *resourceScopes {
+	crds := make(map[string]bool)
+	for _, resource := range resources {
+		if resource.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+
+		group, _, _ := unstructured.NestedString(resource.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(resource.Object, "spec", "names", "kind")
+		scope, _, _ := unstructured.NestedString(resource.Object, "spec", "scope")
+		crds[group+"/"+kind] = scope != "Cluster"
+	}
+
+	return &resourceScopes{openAPIController: openAPIController, crds: crds}
+}
+
+// namespaced checks if the resource is namespaced, the kinds which are not found are namespaced,
+// e.g. the custom resources whose definition is installed separately
+func (s *resourceScopes) namespaced(resource *unstructured.Unstructured) bool {
+	if namespaced, ok := s.crds[resource.GroupVersionKind().Group+"/"+resource.GetKind()]; ok {
+		return namespaced
+	}
+
+	if s.openAPIController != nil {
+		if namespaced, found := s.openAPIController.IsNamespaced(resource.GetAPIVersion(), resource.GetKind()); found {
+			return namespaced
+		}
+	}
+
+	return true
+}
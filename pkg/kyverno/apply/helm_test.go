@@ -0,0 +1,70 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/kyverno/kyverno/pkg/openapi"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_RenderHelmChart(t *testing.T) {
+	openAPIController, err := openapi.NewOpenAPIController()
+	assert.NilError(t, err)
+
+	resources, err := renderHelmChart("../../../test/helm/nginx", nil, "", openAPIController)
+	assert.NilError(t, err)
+	assert.Equal(t, len(resources), 1)
+	assert.Equal(t, resources[0].GetKind(), "Deployment")
+	assert.Equal(t, resources[0].GetName(), "release-name-nginx")
+	assert.Equal(t, resources[0].GetNamespace(), "default")
+
+	resources, err = renderHelmChart("../../../test/helm/nginx", []string{"../../../test/helm/values-latest.yaml"}, "web", openAPIController)
+	assert.NilError(t, err)
+	assert.Equal(t, len(resources), 2)
+	assert.Equal(t, resources[0].GetKind(), "Deployment")
+	assert.Equal(t, resources[0].GetNamespace(), "web")
+	assert.Equal(t, resources[1].GetKind(), "Service")
+
+	containers, _, _ := unstructured.NestedSlice(resources[0].Object, "spec", "template", "spec", "containers")
+	assert.Equal(t, containers[0].(map[string]interface{})["image"], "nginx:latest")
+}
+
+func Test_SetDefaultNamespace(t *testing.T) {
+	openAPIController, err := openapi.NewOpenAPIController()
+	assert.NilError(t, err)
+
+	newResource := func(apiVersion, kind, namespace string) *unstructured.Unstructured {
+		resource := &unstructured.Unstructured{}
+		resource.SetAPIVersion(apiVersion)
+		resource.SetKind(kind)
+		resource.SetName("example")
+		resource.SetNamespace(namespace)
+		return resource
+	}
+
+	crd := newResource("apiextensions.k8s.io/v1", "CustomResourceDefinition", "")
+	crd.Object["spec"] = map[string]interface{}{
+		"group": "example.com",
+		"names": map[string]interface{}{"kind": "Cluster"},
+		"scope": "Cluster",
+	}
+
+	resources := []*unstructured.Unstructured{
+		newResource("apps/v1", "Deployment", ""),
+		newResource("v1", "ConfigMap", "monitoring"),
+		newResource("v1", "Namespace", ""),
+		newResource("rbac.authorization.k8s.io/v1", "ClusterRole", ""),
+		crd,
+		newResource("example.com/v1", "Cluster", ""),
+		newResource("example.com/v1", "Widget", ""),
+	}
+	setDefaultNamespace(resources, "web", openAPIController)
+
+	var namespaces []string
+	for _, resource := range resources {
+		namespaces = append(namespaces, resource.GetNamespace())
+	}
+	// the custom resources whose definition is not found are namespaced
+	assert.DeepEqual(t, namespaces, []string{"web", "monitoring", "", "", "", "", "web"})
+}
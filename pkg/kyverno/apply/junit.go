@@ -0,0 +1,23 @@
+package apply
+
+import (
+	"io/ioutil"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/junit"
+	sanitizederror "github.com/kyverno/kyverno/pkg/kyverno/sanitizedError"
+)
+
+// writeJUnit writes the results of the validation rules to the JUnit XML file
+func writeJUnit(junitPath string, validateEngineResponses []*response.EngineResponse) error {
+	data, err := junit.Build(validateEngineResponses).Marshal()
+	if err != nil {
+		return sanitizederror.NewWithError("failed to marshal the JUnit report", err)
+	}
+
+	if err := ioutil.WriteFile(junitPath, data, 0644); err != nil {
+		return sanitizederror.NewWithError("failed to write the JUnit report", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package apply
+
+import (
+	"fmt"
+
+	"github.com/kyverno/kyverno/pkg/openapi"
+	pkgutils "github.com/kyverno/kyverno/pkg/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+)
+
+// buildKustomization builds the kustomization of the directory, as kustomize build builds it,
+// and returns its resources. The namespaced resources without a namespace are in the namespace,
+// the default namespace by default.
+func buildKustomization(path string, namespace string, openAPIController *openapi.Controller) ([]*unstructured.Unstructured, error) {
+	kustomizer := krusty.MakeKustomizer(filesys.MakeFsOnDisk(), krusty.MakeDefaultOptions())
+	resMap, err := kustomizer.Run(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kustomization %s: %v", path, err)
+	}
+
+	manifests, err := resMap.AsYaml()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the resources of kustomization %s: %v", path, err)
+	}
+
+	documents, err := pkgutils.SplitYAMLDocuments(manifests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the resources of kustomization %s: %v", path, err)
+	}
+
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	resources := make([]*unstructured.Unstructured, 0, len(documents))
+	for _, document := range documents {
+		resource, err := manifestToUnstructured(document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the resources of kustomization %s: %v", path, err)
+		}
+		resources = append(resources, resource)
+	}
+
+	setDefaultNamespace(resources, namespace, openAPIController)
+	return resources, nil
+}
@@ -0,0 +1,27 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/kyverno/kyverno/pkg/openapi"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_BuildKustomization(t *testing.T) {
+	openAPIController, err := openapi.NewOpenAPIController()
+	assert.NilError(t, err)
+
+	resources, err := buildKustomization("../../../test/kustomize/overlay", "dev", openAPIController)
+	assert.NilError(t, err)
+	assert.Equal(t, len(resources), 1)
+	assert.Equal(t, resources[0].GetKind(), "Deployment")
+	assert.Equal(t, resources[0].GetName(), "dev-nginx")
+	assert.Equal(t, resources[0].GetNamespace(), "dev")
+
+	containers, _, _ := unstructured.NestedSlice(resources[0].Object, "spec", "template", "spec", "containers")
+	assert.Equal(t, containers[0].(map[string]interface{})["image"], "nginx:latest")
+
+	_, err = buildKustomization("../../../test/kustomize/missing", "", openAPIController)
+	assert.ErrorContains(t, err, "failed to build kustomization")
+}
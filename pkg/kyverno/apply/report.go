@@ -143,6 +143,7 @@ func buildPolicyResults(resps []*response.EngineResponse) map[string][]*report.P
 				result.Rule = rule.Name
 				result.Message = rule.Message
 				result.Status = report.PolicyStatus(rule.Check)
+				result.Data = rule.Properties
 				results[appname] = append(results[appname], &result)
 			}
 		}
@@ -0,0 +1,44 @@
+package apply
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+)
+
+// ruleResults returns the result of each rule applied on the resource, e.g.
+// "disallow-latest-tag/validate-image-tag (Validation) -> default/Pod/nginx: pass"
+func ruleResults(engineResponses []*response.EngineResponse) []string {
+	var results []string
+	for _, er := range engineResponses {
+		if er == nil {
+			continue
+		}
+
+		resource := er.PolicyResponse.Resource
+		resPath := fmt.Sprintf("%s/%s/%s", resource.Namespace, resource.Kind, resource.Name)
+		for _, rule := range er.PolicyResponse.Rules {
+			status := "pass"
+			if !rule.Success {
+				status = "fail"
+			}
+
+			result := fmt.Sprintf("%s/%s (%s) -> %s: %s", er.PolicyResponse.Policy, rule.Name, rule.Type, resPath, status)
+			if !rule.Success && rule.Message != "" {
+				result = fmt.Sprintf("%s, %s", result, rule.Message)
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+// printRuleResults prints the result of each mutation, validation and generation rule
+func printRuleResults(out io.Writer, engineResponses []*response.EngineResponse) {
+	for _, result := range ruleResults(engineResponses) {
+		fmt.Fprintln(out, result)
+	}
+}
@@ -0,0 +1,36 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"gotest.tools/assert"
+)
+
+func Test_RuleResults(t *testing.T) {
+	engineResponses := []*response.EngineResponse{
+		{
+			PolicyResponse: response.PolicyResponse{
+				Policy:   "add-labels",
+				Resource: response.ResourceSpec{Kind: "Pod", Namespace: "default", Name: "nginx"},
+				Rules:    []response.RuleResponse{{Name: "add-team", Type: "Mutation", Success: true}},
+			},
+		},
+		{
+			PolicyResponse: response.PolicyResponse{
+				Policy:   "disallow-latest-tag",
+				Resource: response.ResourceSpec{Kind: "Pod", Namespace: "default", Name: "nginx"},
+				Rules: []response.RuleResponse{
+					{Name: "require-image-tag", Type: "Validation", Success: true},
+					{Name: "validate-image-tag", Type: "Validation", Success: false, Message: "Using a mutable image tag e.g. 'latest' is not allowed"},
+				},
+			},
+		},
+	}
+
+	assert.DeepEqual(t, ruleResults(engineResponses), []string{
+		"add-labels/add-team (Mutation) -> default/Pod/nginx: pass",
+		"disallow-latest-tag/require-image-tag (Validation) -> default/Pod/nginx: pass",
+		"disallow-latest-tag/validate-image-tag (Validation) -> default/Pod/nginx: fail, Using a mutable image tag e.g. 'latest' is not allowed",
+	})
+}
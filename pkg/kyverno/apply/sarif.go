@@ -0,0 +1,57 @@
+package apply
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/kyverno/common"
+	sanitizederror "github.com/kyverno/kyverno/pkg/kyverno/sanitizedError"
+	"github.com/kyverno/kyverno/pkg/sarif"
+	"github.com/kyverno/kyverno/pkg/version"
+)
+
+// writeSarif writes the failed validation rules to the SARIF file, the results
+// are located in the resource files when the resources are not read from the cluster
+func writeSarif(sarifPath string, validateEngineResponses []*response.EngineResponse, resourcePaths []string, cluster bool) error {
+	var locate sarif.Locator
+	if !cluster {
+		locate = resourceLocator(resourcePaths)
+	}
+
+	data, err := sarif.Build(validateEngineResponses, version.BuildVersion, locate).Marshal()
+	if err != nil {
+		return sanitizederror.NewWithError("failed to marshal the SARIF log", err)
+	}
+
+	if err := ioutil.WriteFile(sarifPath, data, 0644); err != nil {
+		return sanitizederror.NewWithError("failed to write the SARIF log", err)
+	}
+
+	return nil
+}
+
+// resourceLocator maps the resources to the files they are declared in
+func resourceLocator(resourcePaths []string) sarif.Locator {
+	files := map[string]string{}
+	for _, path := range resourcePaths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		resources, err := common.GetResource(data)
+		if err != nil {
+			continue
+		}
+
+		for _, resource := range resources {
+			key := response.ResourceSpec{Kind: resource.GetKind(), Namespace: resource.GetNamespace(), Name: resource.GetName()}.GetKey()
+			files[key] = filepath.ToSlash(filepath.Clean(path))
+		}
+	}
+
+	return func(resource response.ResourceSpec) string {
+		return files[resource.GetKey()]
+	}
+}
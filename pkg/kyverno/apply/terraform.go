@@ -0,0 +1,138 @@
+package apply
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/kyverno/kyverno/pkg/openapi"
+	pkgutils "github.com/kyverno/kyverno/pkg/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// terraformPlan is the subset of the JSON plan, as terraform show -json prints it, which describes
+// the changes of the resources
+type terraformPlan struct {
+	FormatVersion   string                    `json:"format_version"`
+	ResourceChanges []terraformResourceChange `json:"resource_changes"`
+}
+
+type terraformResourceChange struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Change  struct {
+		Actions []string               `json:"actions"`
+		After   map[string]interface{} `json:"after"`
+	} `json:"change"`
+}
+
+// errUnknownManifest is the error of the manifests which are only known once the plan is applied,
+// e.g. when they depend on the attributes of other resources
+var errUnknownManifest = errors.New("the manifest is unknown until the plan is applied")
+
+// readTerraformPlan reads the Kubernetes resources created or updated by the JSON plan. The
+// manifests of the kubernetes_manifest resources of the kubernetes provider and the YAML bodies of
+// the kubectl_manifest resources of the kubectl provider are read, the namespaced resources without
+// a namespace are in the namespace, the default namespace by default. The resources whose manifest
+// is unknown are skipped with a warning.
+func readTerraformPlan(path string, namespace string, openAPIController *openapi.Controller) ([]*unstructured.Unstructured, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terraform plan %s: %v", path, err)
+	}
+
+	var plan terraformPlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to decode terraform plan %s: %v", path, err)
+	}
+
+	if plan.FormatVersion == "" {
+		return nil, fmt.Errorf("%s is not a JSON terraform plan, convert the plan with terraform show -json", path)
+	}
+
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	var resources []*unstructured.Unstructured
+	// overrideNamespaces holds the namespace of the resources whose namespace is overridden
+	overrideNamespaces := make(map[*unstructured.Unstructured]string)
+	for _, resourceChange := range plan.ResourceChanges {
+		if !isCreateOrUpdate(resourceChange.Change.Actions) || resourceChange.Change.After == nil {
+			continue
+		}
+
+		manifests, err := terraformManifests(resourceChange)
+		if errors.Is(err, errUnknownManifest) {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s, %v\n", resourceChange.Address, err)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the manifest of %s: %v", resourceChange.Address, err)
+		}
+
+		for _, manifest := range manifests {
+			resource, err := manifestToUnstructured(manifest)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode the manifest of %s: %v", resourceChange.Address, err)
+			}
+
+			if overrideNamespace, ok := resourceChange.Change.After["override_namespace"].(string); ok && overrideNamespace != "" {
+				overrideNamespaces[resource] = overrideNamespace
+			}
+
+			resources = append(resources, resource)
+		}
+	}
+
+	// the namespace of the cluster-wide resources is not overridden
+	scopes := newResourceScopes(openAPIController, resources)
+	for resource, overrideNamespace := range overrideNamespaces {
+		if scopes.namespaced(resource) {
+			resource.SetNamespace(overrideNamespace)
+		}
+	}
+
+	setDefaultNamespace(resources, namespace, openAPIController)
+	return resources, nil
+}
+
+// terraformManifests returns the manifests of the resource, the other resources have none
+func terraformManifests(resourceChange terraformResourceChange) ([][]byte, error) {
+	switch resourceChange.Type {
+	case "kubernetes_manifest":
+		manifest, ok := resourceChange.Change.After["manifest"]
+		if !ok || manifest == nil {
+			return nil, errUnknownManifest
+		}
+
+		manifestJSON, err := json.Marshal(manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		return [][]byte{manifestJSON}, nil
+	case "kubectl_manifest":
+		body, ok := resourceChange.Change.After["yaml_body"].(string)
+		if !ok {
+			return nil, errUnknownManifest
+		}
+
+		return pkgutils.SplitYAMLDocuments([]byte(body))
+	default:
+		return nil, nil
+	}
+}
+
+// isCreateOrUpdate checks if the actions create or update the resource, a replaced resource is
+// deleted and created
+func isCreateOrUpdate(actions []string) bool {
+	for _, action := range actions {
+		if action == "create" || action == "update" {
+			return true
+		}
+	}
+	return false
+}
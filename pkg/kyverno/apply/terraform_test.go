@@ -0,0 +1,32 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/kyverno/kyverno/pkg/openapi"
+	"gotest.tools/assert"
+)
+
+func Test_ReadTerraformPlan(t *testing.T) {
+	openAPIController, err := openapi.NewOpenAPIController()
+	assert.NilError(t, err)
+
+	resources, err := readTerraformPlan("../../../test/terraform/plan.json", "dev", openAPIController)
+	assert.NilError(t, err)
+	assert.Equal(t, len(resources), 3)
+
+	assert.Equal(t, resources[0].GetKind(), "Deployment")
+	assert.Equal(t, resources[0].GetName(), "nginx")
+	assert.Equal(t, resources[0].GetNamespace(), "dev")
+
+	assert.Equal(t, resources[1].GetKind(), "ConfigMap")
+	assert.Equal(t, resources[1].GetNamespace(), "monitoring")
+	assert.Equal(t, resources[1].Object["data"].(map[string]interface{})["level"], "debug")
+
+	// the resources whose manifest is unknown are skipped, the namespace of the cluster-wide resources is not overridden
+	assert.Equal(t, resources[2].GetKind(), "ClusterRole")
+	assert.Equal(t, resources[2].GetNamespace(), "")
+
+	_, err = readTerraformPlan("../../../test/kustomize/base/kustomization.yaml", "", openAPIController)
+	assert.ErrorContains(t, err, "failed to decode terraform plan")
+}
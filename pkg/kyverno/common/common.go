@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -34,8 +33,11 @@ import (
 // GetPolicies - Extracting the policies from multiple YAML
 
 type Resource struct {
-	Name   string            `json:"name"`
-	Values map[string]string `json:"values"`
+	Name string `json:"name"`
+
+	// Values are the values of the variables, e.g. request.userInfo.groups: [admins], the
+	// values may be strings, numbers, booleans, lists or objects
+	Values map[string]interface{} `json:"values"`
 }
 
 type Policy struct {
@@ -44,97 +46,78 @@ type Policy struct {
 }
 
 type Values struct {
+	// GlobalValues are the values of the variables of all the policies and resources, e.g.
+	// the user info or the namespace labels of the admission requests
+	GlobalValues map[string]interface{} `json:"globalValues"`
+
 	Policies []Policy `json:"policies"`
 }
 
+// GetPolicies reads the policies of the paths: files, HTTP(S) URLs, git references, or directories
+// whose YAML files are read recursively. The errors of the files which are not policies are returned.
 func GetPolicies(paths []string) (policies []*v1.ClusterPolicy, errors []error) {
-	for _, path := range paths {
-		log.Log.V(5).Info("reading policies", "path", path)
+	documents, errors := GetPolicyDocuments(paths)
+	for _, document := range documents {
+		policies = append(policies, document.Policy)
+	}
 
-		var (
-			fileDesc os.FileInfo
-			err      error
-		)
+	return policies, errors
+}
 
-		isHttpPath := strings.Contains(path, "http")
+// PolicyDocument is a policy and its YAML document, as it is written in its file
+type PolicyDocument struct {
+	Policy   *v1.ClusterPolicy
+	Document []byte
+}
 
-		// path clean and retrieving file info can be possible if it's not an HTTP URL
-		if !isHttpPath {
-			path = filepath.Clean(path)
-			fileDesc, err = os.Stat(path)
-			if err != nil {
-				err := fmt.Errorf("failed to process %v: %v", path, err.Error())
-				errors = append(errors, err)
-				continue
-			}
+// GetPolicyDocuments reads the policies of the paths, as GetPolicies reads them, with their documents
+func GetPolicyDocuments(paths []string) (documents []PolicyDocument, errors []error) {
+	for _, path := range paths {
+		log.Log.V(5).Info("reading policies", "path", path)
+
+		files, err := readYAMLFiles(path)
+		if err != nil {
+			err := fmt.Errorf("failed to process %v: %v", path, err.Error())
+			errors = append(errors, err)
+			continue
 		}
 
-		// apply file from a directory is possible only if the path is not HTTP URL
-		if !isHttpPath && fileDesc.IsDir() {
-			files, err := ioutil.ReadDir(path)
-			if err != nil {
-				err := fmt.Errorf("failed to process %v: %v", path, err.Error())
+		for _, file := range files {
+			documentsFromFile, errFromFile := getPolicyDocuments(file.content)
+			if errFromFile != nil {
+				err := fmt.Errorf("failed to process %s: %v", file.path, errFromFile.Error())
 				errors = append(errors, err)
 				continue
 			}
 
-			listOfFiles := make([]string, 0)
-			for _, file := range files {
-				ext := filepath.Ext(file.Name())
-				if ext == "" || ext == ".yaml" || ext == ".yml" {
-					listOfFiles = append(listOfFiles, filepath.Join(path, file.Name()))
-				}
-			}
-
-			policiesFromDir, errorsFromDir := GetPolicies(listOfFiles)
-			errors = append(errors, errorsFromDir...)
-			policies = append(policies, policiesFromDir...)
-
-		} else {
-			var fileBytes []byte
-			if isHttpPath {
-				resp, err := http.Get(path)
-				if err != nil {
-					err := fmt.Errorf("failed to process %v: %v", path, err.Error())
-					errors = append(errors, err)
-					continue
-				}
-				defer resp.Body.Close()
+			documents = append(documents, documentsFromFile...)
+		}
+	}
 
-				if resp.StatusCode != http.StatusOK {
-					err := fmt.Errorf("failed to process %v: %v", path, err.Error())
-					errors = append(errors, err)
-					continue
-				}
+	log.Log.V(3).Info("read policies", "policies", len(documents), "errors", len(errors))
+	return documents, errors
+}
 
-				fileBytes, err = ioutil.ReadAll(resp.Body)
-				if err != nil {
-					err := fmt.Errorf("failed to process %v: %v", path, err.Error())
-					errors = append(errors, err)
-					continue
-				}
-			} else {
-				fileBytes, err = ioutil.ReadFile(path)
-				if err != nil {
-					err := fmt.Errorf("failed to process %v: %v", path, err.Error())
-					errors = append(errors, err)
-					continue
-				}
-			}
+// getPolicyDocuments returns the policies of the file, an error is returned if a document is not a policy
+func getPolicyDocuments(content []byte) ([]PolicyDocument, error) {
+	yamlDocuments, err := utils.SplitYAMLDocuments(content)
+	if err != nil {
+		return nil, err
+	}
 
-			policiesFromFile, errFromFile := utils.GetPolicy(fileBytes)
-			if errFromFile != nil {
-				err := fmt.Errorf("failed to process %s: %v", path, errFromFile.Error())
-				errors = append(errors, err)
-				continue
-			}
+	documents := make([]PolicyDocument, 0, len(yamlDocuments))
+	for _, yamlDocument := range yamlDocuments {
+		policies, err := utils.GetPolicy(yamlDocument)
+		if err != nil {
+			return nil, err
+		}
 
-			policies = append(policies, policiesFromFile...)
+		for _, policy := range policies {
+			documents = append(documents, PolicyDocument{Policy: policy, Document: yamlDocument})
 		}
 	}
 
-	log.Log.V(3).Info("read policies", "policies", len(policies), "errors", len(errors))
-	return policies, errors
+	return documents, nil
 }
 
 // PolicyHasVariables - check for variables in the policy
@@ -152,6 +135,11 @@ func PolicyHasNonAllowedVariables(policy v1.ClusterPolicy) bool {
 	matchesAllowed := AllowedVariables.FindAllStringSubmatch(string(policyRaw), -1)
 
 	if len(matchesAll) > len(matchesAllowed) {
+		// If the policy declares its variables, the references are validated against the declarations
+		if len(policy.Spec.Variables) > 0 {
+			return false
+		}
+
 		// If rules contains Context then skip this validation
 		for _, rule := range policy.Spec.Rules {
 			if len(rule.Context) > 0 {
@@ -238,8 +226,7 @@ func GetCRDs(paths []string) (unstructuredCrds []*unstructured.Unstructured, err
 		} else {
 			getCRDs, err := GetCRD(path)
 			if err != nil {
-				fmt.Printf("\nError: failed to extract crds from %s.  \nCause: %s\n", path, err)
-				os.Exit(2)
+				return nil, sanitizederror.NewWithError(fmt.Sprintf("failed to extract crds from %s", path), err)
 			}
 			unstructuredCrds = append(unstructuredCrds, getCRDs...)
 		}
@@ -265,8 +252,7 @@ func GetCRD(path string) (unstructuredCrds []*unstructured.Unstructured, err err
 		if err == io.EOF || len(b) == 0 {
 			break
 		} else if err != nil {
-			fmt.Printf("\nError: unable to read crd from %s. Cause: %s\n", path, err)
-			os.Exit(2)
+			return nil, sanitizederror.NewWithError(fmt.Sprintf("unable to read crd from %s", path), err)
 		}
 		var u unstructured.Unstructured
 		err = yaml_v2.Unmarshal(b, &u)
@@ -299,10 +285,11 @@ func RemoveDuplicateVariables(matches [][]string) string {
 	return variableStr
 }
 
-// GetVariable - get the variables from console/file
-func GetVariable(variablesString, valuesFile string, fs billy.Filesystem, isGit bool, policyresoucePath string) (map[string]string, map[string]map[string]Resource, error) {
+// GetVariable - get the variables from console, and the global values and the values of each policy and resource from file
+func GetVariable(variablesString, valuesFile string, fs billy.Filesystem, isGit bool, policyresoucePath string) (map[string]interface{}, map[string]interface{}, map[string]map[string]Resource, error) {
 	valuesMap := make(map[string]map[string]Resource)
-	variables := make(map[string]string)
+	variables := make(map[string]interface{})
+	var globalValues map[string]interface{}
 	var yamlFile []byte
 	var err error
 	if variablesString != "" {
@@ -324,19 +311,21 @@ func GetVariable(variablesString, valuesFile string, fs billy.Filesystem, isGit
 		}
 
 		if err != nil {
-			return variables, valuesMap, sanitizederror.NewWithError("unable to read yaml", err)
+			return variables, globalValues, valuesMap, sanitizederror.NewWithError("unable to read yaml", err)
 		}
 
 		valuesBytes, err := yaml.ToJSON(yamlFile)
 		if err != nil {
-			return variables, valuesMap, sanitizederror.NewWithError("failed to convert json", err)
+			return variables, globalValues, valuesMap, sanitizederror.NewWithError("failed to convert json", err)
 		}
 
 		values := &Values{}
 		if err := json.Unmarshal(valuesBytes, values); err != nil {
-			return variables, valuesMap, sanitizederror.NewWithError("failed to decode yaml", err)
+			return variables, globalValues, valuesMap, sanitizederror.NewWithError("failed to decode yaml", err)
 		}
 
+		globalValues = values.GlobalValues
+
 		for _, p := range values.Policies {
 			pmap := make(map[string]Resource)
 			for _, r := range p.Resources {
@@ -346,7 +335,7 @@ func GetVariable(variablesString, valuesFile string, fs billy.Filesystem, isGit
 		}
 	}
 
-	return variables, valuesMap, nil
+	return variables, globalValues, valuesMap, nil
 }
 
 // MutatePolices - function to apply mutation on policies
@@ -368,8 +357,8 @@ func MutatePolices(policies []*v1.ClusterPolicy) ([]*v1.ClusterPolicy, error) {
 }
 
 // ApplyPolicyOnResource - function to apply policy on resource
-func ApplyPolicyOnResource(policy *v1.ClusterPolicy, resource *unstructured.Unstructured,
-	mutateLogPath string, mutateLogPathIsDir bool, variables map[string]string, policyReport bool) ([]*response.EngineResponse, *response.EngineResponse, bool, bool, error) {
+func ApplyPolicyOnResource(out io.Writer, policy *v1.ClusterPolicy, resource *unstructured.Unstructured,
+	mutateLogPath string, mutateLogPathIsDir bool, mutateDiff string, variables map[string]interface{}, policyReport bool) ([]*response.EngineResponse, *response.EngineResponse, bool, bool, error) {
 
 	responseError := false
 	rcError := false
@@ -379,27 +368,39 @@ func ApplyPolicyOnResource(policy *v1.ClusterPolicy, resource *unstructured.Unst
 	log.Log.V(3).Info("applying policy on resource", "policy", policy.Name, "resource", resPath)
 
 	ctx := context.NewContext()
+
+	// the resource is evaluated as the object of an admission request, the values of the
+	// variables override the request
+	resourceRaw, err := resource.MarshalJSON()
+	if err != nil {
+		return engineResponses, &response.EngineResponse{}, responseError, rcError, sanitizederror.NewWithError("failed to marshal resource", err)
+	}
+	if err := ctx.AddResource(resourceRaw); err != nil {
+		return engineResponses, &response.EngineResponse{}, responseError, rcError, sanitizederror.NewWithError("failed to add resource to the context", err)
+	}
+	if err := ctx.AddNamespace(resource.GetNamespace()); err != nil {
+		return engineResponses, &response.EngineResponse{}, responseError, rcError, sanitizederror.NewWithError("failed to add namespace to the context", err)
+	}
+
 	for key, value := range variables {
-		startString := ""
-		endString := ""
-		for _, k := range strings.Split(key, ".") {
-			startString += fmt.Sprintf(`{"%s":`, k)
-			endString += `}`
+		jsonData, err := variableJSON(key, value)
+		if err != nil {
+			return engineResponses, &response.EngineResponse{}, responseError, rcError, sanitizederror.NewWithError(fmt.Sprintf("invalid value of variable %s", key), err)
 		}
-
-		midString := fmt.Sprintf(`"%s"`, value)
-		finalString := startString + midString + endString
-		var jsonData = []byte(finalString)
 		ctx.AddJSON(jsonData)
 	}
 
+	// the context entries are not fetched when their values are passed, e.g. the data of a
+	// config map, since the cluster is not available
+	policy = removeContextEntries(policy, variables)
+
 	mutateResponse := engine.Mutate(&engine.PolicyContext{Policy: *policy, NewResource: *resource, JSONContext: ctx})
 	engineResponses = append(engineResponses, mutateResponse)
 
 	if !mutateResponse.IsSuccessful() {
-		fmt.Printf("Failed to apply mutate policy %s -> resource %s", policy.Name, resPath)
+		fmt.Fprintf(out, "Failed to apply mutate policy %s -> resource %s", policy.Name, resPath)
 		for i, r := range mutateResponse.PolicyResponse.Rules {
-			fmt.Printf("\n%d. %s", i+1, r.Message)
+			fmt.Fprintf(out, "\n%d. %s", i+1, r.Message)
 		}
 		responseError = true
 	} else {
@@ -411,17 +412,24 @@ func ApplyPolicyOnResource(policy *v1.ClusterPolicy, resource *unstructured.Unst
 
 			if mutateLogPath == "" {
 				mutatedResource := string(yamlEncodedResource)
+				if mutateDiff != DiffNone {
+					yamlEncodedOriginal, err := yamlv2.Marshal(resource.Object)
+					if err != nil {
+						rcError = true
+					}
+					mutatedResource = Diff(mutateDiff, string(yamlEncodedOriginal), mutatedResource, resPath, resPath+" (mutated)")
+				}
 				if len(strings.TrimSpace(mutatedResource)) > 0 {
-					fmt.Printf("\nmutate policy %s applied to %s:", policy.Name, resPath)
-					fmt.Printf("\n" + mutatedResource)
-					fmt.Printf("\n")
+					fmt.Fprintf(out, "\nmutate policy %s applied to %s:", policy.Name, resPath)
+					fmt.Fprint(out, "\n"+mutatedResource)
+					fmt.Fprintf(out, "\n")
 				}
 			} else {
 				err := PrintMutatedOutput(mutateLogPath, mutateLogPathIsDir, string(yamlEncodedResource), resource.GetName()+"-mutated")
 				if err != nil {
 					return engineResponses, &response.EngineResponse{}, responseError, rcError, sanitizederror.NewWithError("failed to print mutated result", err)
 				}
-				fmt.Printf("\n\nMutation:\nMutation has been applied successfully. Check the files.")
+				fmt.Fprintf(out, "\n\nMutation:\nMutation has been applied successfully. Check the files.")
 			}
 
 		}
@@ -439,10 +447,10 @@ func ApplyPolicyOnResource(policy *v1.ClusterPolicy, resource *unstructured.Unst
 	validateResponse := engine.Validate(policyCtx)
 	if !policyReport {
 		if !validateResponse.IsSuccessful() {
-			fmt.Printf("\npolicy %s -> resource %s failed: \n", policy.Name, resPath)
+			fmt.Fprintf(out, "\npolicy %s -> resource %s failed: \n", policy.Name, resPath)
 			for i, r := range validateResponse.PolicyResponse.Rules {
 				if !r.Success {
-					fmt.Printf("%d. %s: %s \n", i+1, r.Name, r.Message)
+					fmt.Fprintf(out, "%d. %s: %s \n", i+1, r.Name, r.Message)
 				}
 			}
 
@@ -472,9 +480,9 @@ func ApplyPolicyOnResource(policy *v1.ClusterPolicy, resource *unstructured.Unst
 		if len(generateResponse.PolicyResponse.Rules) > 0 {
 			log.Log.V(3).Info("generate resource is valid", "policy", policy.Name, "resource", resPath)
 		} else {
-			fmt.Printf("generate policy %s resource %s is invalid \n", policy.Name, resPath)
+			fmt.Fprintf(out, "generate policy %s resource %s is invalid \n", policy.Name, resPath)
 			for i, r := range generateResponse.PolicyResponse.Rules {
-				fmt.Printf("%d. %s \b", i+1, r.Message)
+				fmt.Fprintf(out, "%d. %s \b", i+1, r.Message)
 			}
 
 			responseError = true
@@ -514,10 +522,26 @@ func PrintMutatedOutput(mutateLogPath string, mutateLogPathIsDir bool, yaml stri
 func GetPoliciesFromPaths(fs billy.Filesystem, dirPath []string, isGit bool, policyresoucePath string) (policies []*v1.ClusterPolicy, err error) {
 	var errors []error
 	if isGit {
+		var paths []string
 		for _, pp := range dirPath {
-			filep, err := fs.Open(filepath.Join(policyresoucePath, pp))
+			path := filepath.Join(policyresoucePath, pp)
+			// the directories of the repository are read recursively
+			if info, err := fs.Stat(path); err == nil && info.IsDir() {
+				yamls, err := ListYAMLs(fs, path)
+				if err != nil {
+					fmt.Printf("Error: failed to list files of directory %s: %v", path, err.Error())
+					continue
+				}
+				paths = append(paths, yamls...)
+				continue
+			}
+			paths = append(paths, path)
+		}
+
+		for _, path := range paths {
+			filep, err := fs.Open(path)
 			if err != nil {
-				fmt.Printf("Error: file not available with path %s: %v", filep.Name(), err.Error())
+				fmt.Printf("Error: file not available with path %s: %v", path, err.Error())
 				continue
 			}
 			bytes, err := ioutil.ReadAll(filep)
@@ -0,0 +1,215 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DiffUnified prints the mutations as a unified diff of the original and the mutated resource
+	DiffUnified = "unified"
+
+	// DiffSideBySide prints the original and the mutated resource side by side
+	DiffSideBySide = "side-by-side"
+
+	// DiffNone prints the mutated resource
+	DiffNone = "none"
+)
+
+// diffContext is the number of unchanged lines printed around the changes of a unified diff
+const diffContext = 3
+
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// ValidateDiffStyle checks the style of the diff of the mutated resources
+func ValidateDiffStyle(style string) error {
+	switch style {
+	case DiffUnified, DiffSideBySide, DiffNone:
+		return nil
+	default:
+		return fmt.Errorf("unsupported diff %s, supported diffs are %s, %s and %s", style, DiffUnified, DiffSideBySide, DiffNone)
+	}
+}
+
+// Diff returns the diff of the original and the mutated YAML in the given style
+func Diff(style, original, mutated, originalName, mutatedName string) string {
+	switch style {
+	case DiffSideBySide:
+		return SideBySideDiff(original, mutated)
+	case DiffNone:
+		return mutated
+	default:
+		return UnifiedDiff(original, mutated, originalName, mutatedName)
+	}
+}
+
+// UnifiedDiff returns the unified diff of the lines of a and b, or an empty string when they are equal
+func UnifiedDiff(a, b, aName, bName string) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	// the line numbers in a and b of each operation
+	aLines, bLines := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	aLines[0], bLines[0] = 1, 1
+	for i, op := range ops {
+		aLines[i+1], bLines[i+1] = aLines[i], bLines[i]
+		if op.kind != '+' {
+			aLines[i+1]++
+		}
+		if op.kind != '-' {
+			bLines[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	for next := 0; next < len(ops); {
+		change := next
+		for change < len(ops) && ops[change].kind == ' ' {
+			change++
+		}
+		if change == len(ops) {
+			break
+		}
+
+		hunkStart := change - diffContext
+		if hunkStart < next {
+			hunkStart = next
+		}
+
+		// the changes separated by at most twice the context are in the same hunk
+		lastChange := change
+		for i := change; i < len(ops) && i-lastChange <= 2*diffContext; i++ {
+			if ops[i].kind != ' ' {
+				lastChange = i
+			}
+		}
+
+		hunkEnd := lastChange + 1 + diffContext
+		if hunkEnd > len(ops) {
+			hunkEnd = len(ops)
+		}
+
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", aName, bName)
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(aLines[hunkStart], aLines[hunkEnd]-aLines[hunkStart]),
+			hunkRange(bLines[hunkStart], bLines[hunkEnd]-bLines[hunkStart]))
+		for _, op := range ops[hunkStart:hunkEnd] {
+			fmt.Fprintf(&sb, "%c%s\n", op.kind, op.line)
+		}
+
+		next = hunkEnd
+	}
+
+	return sb.String()
+}
+
+// SideBySideDiff returns the lines of a and b side by side, the changed lines are marked with |,
+// the removed lines with < and the added lines with >
+func SideBySideDiff(a, b string) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	width := 0
+	for _, op := range ops {
+		if op.kind != '+' && len(op.line) > width {
+			width = len(op.line)
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			fmt.Fprintf(&sb, "%-*s   %s\n", width, ops[i].line, ops[i].line)
+			i++
+			continue
+		}
+
+		// pair the removed lines with the added lines which replace them
+		var removed, added []string
+		for ; i < len(ops) && ops[i].kind == '-'; i++ {
+			removed = append(removed, ops[i].line)
+		}
+		for ; i < len(ops) && ops[i].kind == '+'; i++ {
+			added = append(added, ops[i].line)
+		}
+
+		for j := 0; j < len(removed) || j < len(added); j++ {
+			switch {
+			case j < len(removed) && j < len(added):
+				fmt.Fprintf(&sb, "%-*s | %s\n", width, removed[j], added[j])
+			case j < len(removed):
+				fmt.Fprintf(&sb, "%-*s <\n", width, removed[j])
+			default:
+				fmt.Fprintf(&sb, "%-*s > %s\n", width, "", added[j])
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// diffLines returns the shortest edit script of a into b, computed from the longest common
+// subsequence of the lines
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+
+	return ops
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+var originalResource = `apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+  namespace: default
+spec:
+  containers:
+  - image: nginx
+    name: nginx
+`
+
+var mutatedResource = `apiVersion: v1
+kind: Pod
+metadata:
+  labels:
+    team: dev
+  name: nginx
+  namespace: default
+spec:
+  containers:
+  - image: nginx:1.19
+    name: nginx
+`
+
+func Test_UnifiedDiff(t *testing.T) {
+	expected := `--- original
++++ mutated
+@@ -1,9 +1,11 @@
+ apiVersion: v1
+ kind: Pod
+ metadata:
++  labels:
++    team: dev
+   name: nginx
+   namespace: default
+ spec:
+   containers:
+-  - image: nginx
++  - image: nginx:1.19
+     name: nginx
+`
+	assert.Equal(t, UnifiedDiff(originalResource, mutatedResource, "original", "mutated"), expected)
+	assert.Equal(t, UnifiedDiff(originalResource, originalResource, "original", "mutated"), "")
+}
+
+func Test_UnifiedDiff_Hunks(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n"
+
+	expected := `--- a
++++ b
+@@ -1,3 +1,4 @@
++0
+ 1
+ 2
+ 3
+@@ -9,4 +10,3 @@
+ 9
+ 10
+ 11
+-12
+`
+	assert.Equal(t, UnifiedDiff(a, b, "a", "b"), expected)
+}
+
+func Test_SideBySideDiff(t *testing.T) {
+	expected := `apiVersion: v1         apiVersion: v1
+kind: Pod              kind: Pod
+metadata:              metadata:
+                     >   labels:
+                     >     team: dev
+  name: nginx            name: nginx
+  namespace: default     namespace: default
+spec:                  spec:
+  containers:            containers:
+  - image: nginx     |   - image: nginx:1.19
+    name: nginx            name: nginx
+`
+	assert.Equal(t, SideBySideDiff(originalResource, mutatedResource), expected)
+}
+
+func Test_ValidateDiffStyle(t *testing.T) {
+	assert.NilError(t, ValidateDiffStyle(DiffUnified))
+	assert.NilError(t, ValidateDiffStyle(DiffSideBySide))
+	assert.NilError(t, ValidateDiffStyle(DiffNone))
+	assert.ErrorContains(t, ValidateDiffStyle("json"), "unsupported diff json")
+}
@@ -0,0 +1,88 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ExitPassed is the exit code when all the rules passed
+	ExitPassed = 0
+
+	// ExitViolations is the exit code when a rule failed, or a policy is invalid for the validate command
+	ExitViolations = 1
+
+	// ExitErrors is the exit code when the policies could not be evaluated on a resource
+	ExitErrors = 2
+
+	// ExitInvalidInput is the exit code when the flags, the policies or the resources cannot be loaded
+	ExitInvalidInput = 3
+)
+
+// ExitCode returns the exit code of the results of the policies. The errors take precedence over
+// the failures since the results are incomplete, the warnings exit with warnExitCode, which is
+// ExitPassed by default.
+func ExitCode(fail, warn, errs, warnExitCode int) int {
+	switch {
+	case errs > 0:
+		return ExitErrors
+	case fail > 0:
+		return ExitViolations
+	case warn > 0:
+		return warnExitCode
+	default:
+		return ExitPassed
+	}
+}
+
+// ExitError is the error of a command which exits with a code other than ExitInvalidInput, the error
+// is nil when the results are already printed
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit code %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ResultsError returns the error of the exit code of the printed results, nil when the results passed.
+// The error and the usage are not printed, as the results are.
+func ResultsError(cmd *cobra.Command, code int) error {
+	if code == ExitPassed {
+		return nil
+	}
+
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return &ExitError{Code: code}
+}
+
+// IsExitError checks if the error exits with its own code
+func IsExitError(err error) bool {
+	var exitErr *ExitError
+	return errors.As(err, &exitErr)
+}
+
+// ExitCodeOf returns the exit code of the error of a command, the other errors are the invalid flags,
+// and the policies or the resources which cannot be loaded
+func ExitCodeOf(err error) int {
+	if err == nil {
+		return ExitPassed
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return ExitInvalidInput
+}
@@ -0,0 +1,57 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/assert"
+)
+
+func Test_ExitCode(t *testing.T) {
+	testcases := []struct {
+		name         string
+		fail         int
+		warn         int
+		errors       int
+		warnExitCode int
+		expected     int
+	}{
+		{name: "passed", expected: ExitPassed},
+		{name: "violations", fail: 2, warn: 1, expected: ExitViolations},
+		{name: "errors", fail: 1, errors: 1, expected: ExitErrors},
+		{name: "warnings", warn: 1, expected: ExitPassed},
+		{name: "warnings with warn exit code", warn: 1, warnExitCode: 4, expected: 4},
+		{name: "violations with warn exit code", fail: 1, warn: 1, warnExitCode: 4, expected: ExitViolations},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, ExitCode(tc.fail, tc.warn, tc.errors, tc.warnExitCode), tc.expected, tc.name)
+	}
+}
+
+func Test_ExitCodeOf(t *testing.T) {
+	cmd := &cobra.Command{}
+	assert.NilError(t, ResultsError(cmd, ExitPassed))
+	assert.Assert(t, !cmd.SilenceErrors)
+
+	resultsErr := ResultsError(cmd, ExitViolations)
+	assert.Assert(t, cmd.SilenceErrors && cmd.SilenceUsage)
+
+	testcases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "passed", expected: ExitPassed},
+		{name: "results", err: resultsErr, expected: ExitViolations},
+		{name: "errors", err: &ExitError{Code: ExitErrors, Err: errors.New("failed to apply policy")}, expected: ExitErrors},
+		{name: "wrapped", err: fmt.Errorf("apply: %w", &ExitError{Code: ExitErrors}), expected: ExitErrors},
+		{name: "invalid input", err: errors.New("failed to load policies"), expected: ExitInvalidInput},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, ExitCodeOf(tc.err), tc.expected, tc.name)
+	}
+}
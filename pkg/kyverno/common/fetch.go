@@ -6,7 +6,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"path/filepath"
-	"strings"
+	"time"
 
 	"github.com/go-git/go-billy/v5"
 	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
@@ -14,6 +14,7 @@ import (
 	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
 	"github.com/kyverno/kyverno/pkg/utils"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
 	log "sigs.k8s.io/controller-runtime/pkg/log"
@@ -77,7 +78,7 @@ func GetResources(policies []*v1.ClusterPolicy, resourcePaths []string, dClient
 		}
 	} else if len(resourcePaths) > 0 {
 		for _, resourcePath := range resourcePaths {
-			resourceBytes, err := getFileBytes(resourcePath)
+			files, err := readYAMLFiles(resourcePath)
 			if err != nil {
 				if policyReport {
 					log.Log.V(3).Info(fmt.Sprintf("failed to load resources: %s.", resourcePath), "error", err)
@@ -87,13 +88,18 @@ func GetResources(policies []*v1.ClusterPolicy, resourcePaths []string, dClient
 				continue
 			}
 
-			getResources, err := GetResource(resourceBytes)
-			if err != nil {
-				return nil, err
-			}
+			for _, file := range files {
+				getResources, err := GetResource(file.content)
+				if err != nil {
+					// the files of a directory may be other manifests, e.g. the policies or kustomizations
+					if file.listed {
+						log.Log.V(3).Info("skipping file which is not a resource", "path", file.path, "error", err.Error())
+						continue
+					}
+					return nil, err
+				}
 
-			for _, resource := range getResources {
-				resources = append(resources, resource)
+				resources = append(resources, getResources...)
 			}
 		}
 	}
@@ -117,7 +123,7 @@ func GetResourcesWithTest(fs billy.Filesystem, policies []*v1.ClusterPolicy, res
 	}
 	if len(resourcePaths) > 0 {
 		for _, resourcePath := range resourcePaths {
-			var resourceBytes []byte
+			var files []yamlFile
 			var err error
 			if isGit {
 				filep, err := fs.Open(filepath.Join(policyresoucePath, resourcePath))
@@ -125,22 +131,31 @@ func GetResourcesWithTest(fs billy.Filesystem, policies []*v1.ClusterPolicy, res
 					fmt.Printf("Unable to open resource file: %s. error: %s", resourcePath, err)
 					continue
 				}
-				resourceBytes, err = ioutil.ReadAll(filep)
+				resourceBytes, err := ioutil.ReadAll(filep)
+				if err != nil {
+					fmt.Printf("Unable to read resource file: %s. error: %s", resourcePath, err)
+					continue
+				}
+				files = []yamlFile{{path: resourcePath, content: resourceBytes}}
 			} else {
-				resourceBytes, err = getFileBytes(resourcePath)
+				files, err = readYAMLFiles(resourcePath)
 			}
 			if err != nil {
 				fmt.Printf("\n----------------------------------------------------------------------\nfailed to load resources: %s. \nerror: %s\n----------------------------------------------------------------------\n", resourcePath, err)
 				continue
 			}
 
-			getResources, err := GetResource(resourceBytes)
-			if err != nil {
-				return nil, err
-			}
+			for _, file := range files {
+				getResources, err := GetResource(file.content)
+				if err != nil {
+					if file.listed {
+						log.Log.V(3).Info("skipping file which is not a resource", "path", file.path, "error", err.Error())
+						continue
+					}
+					return nil, err
+				}
 
-			for _, resource := range getResources {
-				resources = append(resources, resource)
+				resources = append(resources, getResources...)
 			}
 		}
 	}
@@ -172,6 +187,41 @@ func GetResource(resourceBytes []byte) ([]*unstructured.Unstructured, error) {
 	return resources, nil
 }
 
+// GetPoliciesFromCluster fetches the cluster policies, and the policies of the namespace
+// or of all namespaces when the namespace is empty, from the cluster in the current context
+func GetPoliciesFromCluster(dClient *client.Client, namespace string) ([]*v1.ClusterPolicy, error) {
+	var policies []*v1.ClusterPolicy
+
+	cpolList, err := dClient.ListResource("", "ClusterPolicy", "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster policies: %v", err)
+	}
+
+	for _, item := range cpolList.Items {
+		cpol := &v1.ClusterPolicy{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, cpol); err != nil {
+			return nil, fmt.Errorf("failed to convert cluster policy %s: %v", item.GetName(), err)
+		}
+		policies = append(policies, cpol)
+	}
+
+	polList, err := dClient.ListResource("", "Policy", namespace, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %v", err)
+	}
+
+	for _, item := range polList.Items {
+		pol := &v1.Policy{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, pol); err != nil {
+			return nil, fmt.Errorf("failed to convert policy %s/%s: %v", item.GetNamespace(), item.GetName(), err)
+		}
+		cpol := v1.ClusterPolicy(*pol)
+		policies = append(policies, &cpol)
+	}
+
+	return policies, nil
+}
+
 func getResourcesOfTypeFromCluster(resourceTypes []string, dClient *client.Client, namespace string) (map[string]map[string]*unstructured.Unstructured, error) {
 	r := make(map[string]map[string]*unstructured.Unstructured)
 
@@ -198,6 +248,9 @@ func getResourcesOfTypeFromCluster(resourceTypes []string, dClient *client.Clien
 	return r, nil
 }
 
+// httpClient fetches the files of the URLs, e.g. the policies, the resources and the schemas
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
 func getFileBytes(path string) ([]byte, error) {
 
 	var (
@@ -205,15 +258,15 @@ func getFileBytes(path string) ([]byte, error) {
 		err  error
 	)
 
-	if strings.Contains(path, "http") {
-		resp, err := http.Get(path)
+	if isHTTPPath(path) {
+		resp, err := httpClient.Get(path)
 		if err != nil {
 			return nil, err
 		}
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			return nil, err
+			return nil, fmt.Errorf("failed to get %s: %s", path, resp.Status)
 		}
 
 		file, err = ioutil.ReadAll(resp.Body)
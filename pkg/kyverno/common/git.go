@@ -0,0 +1,159 @@
+package common
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// gitReferencePrefix is the prefix of the paths which reference the files of a git repository
+const gitReferencePrefix = "git::"
+
+// GitReference references the files of a git repository, a path of the form
+// git::<repository URL>[//<path in the repository>][?ref=<branch, tag or commit>], e.g.
+// git::https://github.com/kyverno/policies//best-practices?ref=main
+type GitReference struct {
+	// URL of the repository
+	URL string
+
+	// Path of the file or the directory in the repository, the root by default
+	Path string
+
+	// Ref is the branch, the tag or the commit to check out, the default branch by default
+	Ref string
+}
+
+// IsGitReference checks if the path references the files of a git repository
+func IsGitReference(path string) bool {
+	return strings.HasPrefix(path, gitReferencePrefix)
+}
+
+// ParseGitReference parses a path of the form git::<repository URL>[//<path>][?ref=<ref>]
+func ParseGitReference(path string) (*GitReference, error) {
+	if !IsGitReference(path) {
+		return nil, fmt.Errorf("%s is not a git reference, it must start with %s", path, gitReferencePrefix)
+	}
+
+	reference := &GitReference{URL: strings.TrimPrefix(path, gitReferencePrefix), Path: "/"}
+	if i := strings.Index(reference.URL, "?"); i >= 0 {
+		query, err := url.ParseQuery(reference.URL[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid query of git reference %s: %v", path, err)
+		}
+
+		reference.Ref = query.Get("ref")
+		reference.URL = reference.URL[:i]
+	}
+
+	// the path in the repository follows the first double slash after the scheme
+	schemeEnd := strings.Index(reference.URL, "://")
+	if schemeEnd < 0 {
+		return nil, fmt.Errorf("invalid URL of git reference %s, the URL must have a scheme, e.g. https://", path)
+	}
+
+	if i := strings.Index(reference.URL[schemeEnd+3:], "//"); i >= 0 {
+		reference.Path = filepath.Join("/", reference.URL[schemeEnd+3+i+2:])
+		reference.URL = reference.URL[:schemeEnd+3+i]
+	}
+
+	return reference, nil
+}
+
+// CloneGitReference clones the repository of the reference in the filesystem, and checks out its ref
+func CloneGitReference(reference *GitReference, fs billy.Filesystem) error {
+	log.Log.V(3).Info("cloning repository", "url", reference.URL, "ref", reference.Ref)
+	repo, err := git.Clone(memory.NewStorage(), fs, &git.CloneOptions{
+		URL: reference.URL,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clone repository %s: %v", reference.URL, err)
+	}
+
+	if reference.Ref == "" {
+		return nil
+	}
+
+	hash, err := resolveGitRef(repo, reference.Ref)
+	if err != nil {
+		return fmt.Errorf("failed to resolve ref %s of repository %s: %v", reference.Ref, reference.URL, err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	return worktree.Checkout(&git.CheckoutOptions{Hash: hash, Force: true})
+}
+
+// resolveGitRef returns the commit of the branch, the tag or the commit of a cloned repository,
+// the branches of the clone are remote branches
+func resolveGitRef(repo *git.Repository, ref string) (plumbing.Hash, error) {
+	if branch, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", ref), true); err == nil {
+		return branch.Hash(), nil
+	}
+
+	if tag, err := repo.Tag(ref); err == nil {
+		// the annotated tags reference a tag object instead of the commit
+		if tagObject, err := repo.TagObject(tag.Hash()); err == nil {
+			commit, err := tagObject.Commit()
+			if err != nil {
+				return plumbing.ZeroHash, err
+			}
+			return commit.Hash, nil
+		}
+		return tag.Hash(), nil
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return *hash, nil
+}
+
+// ListYAMLs returns the YAML files of the directory of the filesystem and of its subdirectories,
+// the hidden directories, e.g. .github, are skipped
+func ListYAMLs(fs billy.Filesystem, path string) ([]string, error) {
+	path = filepath.Clean(path)
+	fis, err := fs.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	yamls := make([]string, 0)
+	for _, fi := range fis {
+		name := filepath.Join(path, fi.Name())
+		if fi.IsDir() {
+			if strings.HasPrefix(fi.Name(), ".") {
+				continue
+			}
+
+			moreYAMLs, err := ListYAMLs(fs, name)
+			if err != nil {
+				return nil, err
+			}
+
+			yamls = append(yamls, moreYAMLs...)
+			continue
+		}
+
+		if !isYAMLFile(name) {
+			continue
+		}
+
+		yamls = append(yamls, name)
+	}
+	return yamls, nil
+}
+
+func isYAMLFile(name string) bool {
+	ext := filepath.Ext(name)
+	return ext == ".yml" || ext == ".yaml"
+}
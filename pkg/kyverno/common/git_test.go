@@ -0,0 +1,36 @@
+package common
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_ParseGitReference(t *testing.T) {
+	testcases := []struct {
+		path     string
+		expected GitReference
+	}{
+		{
+			path:     "git::https://github.com/kyverno/policies",
+			expected: GitReference{URL: "https://github.com/kyverno/policies", Path: "/"},
+		},
+		{
+			path:     "git::https://github.com/kyverno/policies//best-practices?ref=main",
+			expected: GitReference{URL: "https://github.com/kyverno/policies", Path: "/best-practices", Ref: "main"},
+		},
+		{
+			path:     "git::file:///tmp/policies//pod-security/restricted.yaml?ref=v1.0.0",
+			expected: GitReference{URL: "file:///tmp/policies", Path: "/pod-security/restricted.yaml", Ref: "v1.0.0"},
+		},
+	}
+
+	for _, tc := range testcases {
+		reference, err := ParseGitReference(tc.path)
+		assert.NilError(t, err, tc.path)
+		assert.DeepEqual(t, *reference, tc.expected)
+	}
+
+	_, err := ParseGitReference("git::github.com/kyverno/policies")
+	assert.ErrorContains(t, err, "must have a scheme")
+}
@@ -0,0 +1,23 @@
+package common
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+)
+
+// kubernetesConfig is the kubeconfig used by the commands which fetch the policies and the
+// resources from the cluster, the KUBECONFIG environment variable is used by default
+var kubernetesConfig = genericclioptions.NewConfigFlags(true)
+
+// AddKubeConfigFlags adds the flags which select the kubeconfig file and its context, e.g. the
+// flags passed by kubectl when the CLI is run as a kubectl plugin
+func AddKubeConfigFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(kubernetesConfig.KubeConfig, "kubeconfig", "", "Path to the kubeconfig file used for the cluster")
+	cmd.PersistentFlags().StringVar(kubernetesConfig.Context, "context", "", "Name of the kubeconfig context used for the cluster, the current context is used by default")
+}
+
+// RESTConfig returns the client configuration of the selected kubeconfig context
+func RESTConfig() (*rest.Config, error) {
+	return kubernetesConfig.ToRESTConfig()
+}
@@ -0,0 +1,126 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/kataras/tablewriter"
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/policyreport"
+	"github.com/kyverno/kyverno/pkg/sarif"
+	"github.com/kyverno/kyverno/pkg/version"
+	"github.com/lensesio/tableprinter"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// OutputTable prints the results as a table, for terminals
+	OutputTable = "table"
+
+	// OutputJSON prints the results as a JSON list of policy report results
+	OutputJSON = "json"
+
+	// OutputYAML prints the results as a YAML list of policy report results
+	OutputYAML = "yaml"
+
+	// OutputSARIF prints the failed validation rules as a SARIF log, e.g. for code scanning
+	OutputSARIF = "sarif"
+)
+
+// ResultsTable is a row of the results printed as a table
+type ResultsTable struct {
+	ID       int    `header:"#"`
+	Policy   string `header:"policy"`
+	Rule     string `header:"rule"`
+	Resource string `header:"resource"`
+	Result   string `header:"result"`
+	Message  string `header:"message"`
+}
+
+// ValidateOutputFormat checks the format of the results
+func ValidateOutputFormat(format string) error {
+	switch format {
+	case OutputTable, OutputJSON, OutputYAML, OutputSARIF:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %s, supported formats are %s, %s, %s and %s", format, OutputTable, OutputJSON, OutputYAML, OutputSARIF)
+	}
+}
+
+// IsMachineReadable checks if the results are printed in a format parsed by tools, the other
+// messages of the commands must not be printed with them
+func IsMachineReadable(format string) bool {
+	return format == OutputJSON || format == OutputYAML || format == OutputSARIF
+}
+
+// PrintResults prints the results of the validation rules of the engine responses in the format,
+// the results are the policy report results built by the report subsystem. The locator of the SARIF
+// log is optional.
+func PrintResults(w io.Writer, format string, engineResponses []*response.EngineResponse, locate sarif.Locator) error {
+	if format == OutputSARIF {
+		data, err := sarif.Build(engineResponses, version.BuildVersion, locate).Marshal()
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
+	results := policyreport.BuildResults(engineResponses, log.Log)
+	if results == nil {
+		results = []*report.PolicyReportResult{}
+	}
+
+	switch format {
+	case OutputJSON:
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+
+	case OutputYAML:
+		data, err := yaml.Marshal(results)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprint(w, string(data))
+		return err
+	}
+
+	table := make([]*ResultsTable, 0, len(results))
+	for i, result := range results {
+		var resPath string
+		if len(result.Resources) > 0 {
+			resource := result.Resources[0]
+			resPath = fmt.Sprintf("%s/%s/%s", resource.Namespace, resource.Kind, resource.Name)
+		}
+
+		table = append(table, &ResultsTable{
+			ID:       i + 1,
+			Policy:   result.Policy,
+			Rule:     result.Rule,
+			Resource: resPath,
+			Result:   string(result.Status),
+			Message:  result.Message,
+		})
+	}
+
+	printer := tableprinter.New(w)
+	printer.BorderTop, printer.BorderBottom, printer.BorderLeft, printer.BorderRight = true, true, true, true
+	printer.CenterSeparator = "│"
+	printer.ColumnSeparator = "│"
+	printer.RowSeparator = "─"
+	printer.RowCharLimit = 300
+	printer.HeaderBgColor = tablewriter.BgBlackColor
+	printer.HeaderFgColor = tablewriter.FgGreenColor
+	printer.Print(table)
+	return nil
+}
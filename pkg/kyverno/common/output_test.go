@@ -0,0 +1,78 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var outputResponses = []*response.EngineResponse{
+	{
+		PatchedResource: unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata":   map[string]interface{}{"name": "nginx", "namespace": "default"},
+			},
+		},
+		PolicyResponse: response.PolicyResponse{
+			Policy: "require-labels",
+			Resource: response.ResourceSpec{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Namespace:  "default",
+				Name:       "nginx",
+			},
+			Rules: []response.RuleResponse{
+				{Name: "require-team", Type: "Validation", Success: true},
+				{Name: "require-owner", Type: "Validation", Success: false, Message: "label owner is required"},
+				{Name: "add-team", Type: "Mutation", Success: true},
+			},
+		},
+	},
+}
+
+func Test_PrintResults_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NilError(t, PrintResults(&buf, OutputJSON, outputResponses, nil))
+
+	var results []report.PolicyReportResult
+	assert.NilError(t, json.Unmarshal(buf.Bytes(), &results))
+	assert.Equal(t, len(results), 2)
+
+	assert.Equal(t, results[0].Rule, "require-team")
+	assert.Equal(t, results[0].Status, report.PolicyStatus(report.StatusPass))
+	assert.Equal(t, results[1].Rule, "require-owner")
+	assert.Equal(t, results[1].Status, report.PolicyStatus(report.StatusFail))
+	assert.Equal(t, results[1].Message, "label owner is required")
+	assert.Equal(t, results[1].Resources[0].Name, "nginx")
+}
+
+func Test_PrintResults_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NilError(t, PrintResults(&buf, OutputJSON, nil, nil))
+	assert.Equal(t, strings.TrimSpace(buf.String()), "[]")
+}
+
+func Test_PrintResults_Table(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NilError(t, PrintResults(&buf, OutputTable, outputResponses, nil))
+	assert.Assert(t, strings.Contains(buf.String(), "default/Pod/nginx"))
+	assert.Assert(t, strings.Contains(buf.String(), "label owner is required"))
+}
+
+func Test_ValidateOutputFormat(t *testing.T) {
+	for _, format := range []string{OutputTable, OutputJSON, OutputYAML, OutputSARIF} {
+		assert.NilError(t, ValidateOutputFormat(format))
+	}
+	assert.ErrorContains(t, ValidateOutputFormat("xml"), "unsupported output format xml")
+
+	assert.Assert(t, IsMachineReadable(OutputSARIF))
+	assert.Assert(t, !IsMachineReadable(OutputTable))
+}
@@ -0,0 +1,98 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/openapi"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// schemaURL is the URL of the OpenAPI v2 schema of a Kubernetes version, the version is a tag of
+// the kubernetes repository
+const schemaURL = "https://raw.githubusercontent.com/kubernetes/kubernetes/%s/api/openapi-spec/swagger.json"
+
+var kubernetesVersionRegex = regexp.MustCompile(`^v\d+\.\d+\.\d+$`)
+
+// LoadSchemas returns the OpenAPI controller of the schemas of the Kubernetes version, e.g. v1.20.0.
+// The bundled schemas are used when the version is empty, the schemas of the other versions are
+// downloaded once and cached in the user cache directory.
+func LoadSchemas(kubernetesVersion string) (*openapi.Controller, error) {
+	if kubernetesVersion == "" {
+		return openapi.NewOpenAPIController()
+	}
+
+	if !kubernetesVersionRegex.MatchString(kubernetesVersion) {
+		return nil, fmt.Errorf("invalid Kubernetes version %s, the version must be of the form v1.20.0", kubernetesVersion)
+	}
+
+	schema, err := readCachedSchema(kubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	controller, err := openapi.NewOpenAPIControllerFromSchema(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the schemas of Kubernetes %s: %v", kubernetesVersion, err)
+	}
+
+	return controller, nil
+}
+
+// readCachedSchema returns the cached schema of the Kubernetes version, the schema is downloaded
+// and cached if it is not cached yet
+func readCachedSchema(kubernetesVersion string) ([]byte, error) {
+	var cachePath string
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		cachePath = filepath.Join(cacheDir, "kyverno", "schemas", kubernetesVersion+".json")
+		if schema, err := ioutil.ReadFile(cachePath); err == nil {
+			log.Log.V(3).Info("using cached schemas", "version", kubernetesVersion, "path", cachePath)
+			return schema, nil
+		}
+	}
+
+	schema, err := getFileBytes(fmt.Sprintf(schemaURL, kubernetesVersion))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the schemas of Kubernetes %s: %v", kubernetesVersion, err)
+	}
+
+	if cachePath != "" {
+		// the schemas are downloaded again by the next run if they cannot be cached
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+			log.Log.V(3).Info("failed to cache schemas", "path", cachePath, "error", err.Error())
+		} else if err := ioutil.WriteFile(cachePath, schema, 0644); err != nil {
+			log.Log.V(3).Info("failed to cache schemas", "path", cachePath, "error", err.Error())
+		}
+	}
+
+	return schema, nil
+}
+
+// ValidateResourceSchema validates the resource against the schema of its apiVersion and kind. The
+// resources whose kind has no schema, e.g. the custom resources, are not validated.
+func ValidateResourceSchema(controller *openapi.Controller, resource *unstructured.Unstructured) error {
+	err := controller.ValidateResourceSchema(*resource)
+	var schemaNotFound *openapi.SchemaNotFound
+	if errors.As(err, &schemaNotFound) {
+		log.Log.V(3).Info("skipping schema validation", "kind", resource.GetKind(), "reason", err.Error())
+		return nil
+	}
+
+	return err
+}
+
+// ValidateMutatedResourceSchema validates the patched resource of the mutate response against its
+// schema, the resource is not validated if no mutate rule was applied
+func ValidateMutatedResourceSchema(controller *openapi.Controller, mutateResponse *response.EngineResponse) error {
+	if !mutateResponse.IsSuccessful() || len(mutateResponse.PolicyResponse.Rules) == 0 {
+		return nil
+	}
+
+	return ValidateResourceSchema(controller, &mutateResponse.PatchedResource)
+}
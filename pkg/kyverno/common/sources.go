@@ -0,0 +1,131 @@
+package common
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/memfs"
+)
+
+// yamlFile is a YAML file read from a path passed to the commands
+type yamlFile struct {
+	path    string
+	content []byte
+
+	// listed is set for the files listed from a directory or a git reference, the invalid
+	// listed files are skipped instead of failing the command
+	listed bool
+}
+
+// isHTTPPath checks if the path is an HTTP(S) URL
+func isHTTPPath(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// readYAMLFiles reads the files of the path: a file, an HTTP(S) URL, a git reference, or a
+// directory whose YAML files are read recursively
+func readYAMLFiles(path string) ([]yamlFile, error) {
+	if IsGitReference(path) {
+		return readGitReference(path)
+	}
+
+	if isHTTPPath(path) {
+		content, err := getFileBytes(path)
+		if err != nil {
+			return nil, err
+		}
+		return []yamlFile{{path: path, content: content}}, nil
+	}
+
+	path = filepath.Clean(path)
+	fileDesc, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fileDesc.IsDir() {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return []yamlFile{{path: path, content: content}}, nil
+	}
+
+	var files []yamlFile
+	err = filepath.Walk(path, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if filePath != path && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !isYAMLFile(filePath) {
+			return nil
+		}
+
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, yamlFile{path: filePath, content: content, listed: true})
+		return nil
+	})
+
+	return files, err
+}
+
+// readGitReference clones the repository of the git reference in memory, and reads the file or
+// the YAML files of the directory of the reference
+func readGitReference(path string) ([]yamlFile, error) {
+	reference, err := ParseGitReference(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := memfs.New()
+	if err := CloneGitReference(reference, fs); err != nil {
+		return nil, err
+	}
+
+	fileDesc, err := fs.Stat(reference.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s in repository %s: %v", reference.Path, reference.URL, err)
+	}
+
+	paths := []string{reference.Path}
+	if fileDesc.IsDir() {
+		paths, err = ListYAMLs(fs, reference.Path)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(paths)
+	}
+
+	files := make([]yamlFile, 0, len(paths))
+	for _, filePath := range paths {
+		file, err := fs.Open(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := ioutil.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, yamlFile{path: reference.URL + "//" + strings.TrimPrefix(filePath, "/"), content: content, listed: fileDesc.IsDir()})
+	}
+
+	return files, nil
+}
@@ -0,0 +1,58 @@
+package common
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+var sourcePolicy = `apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: %s
+spec:
+  rules:
+  - name: check-label
+    match:
+      resources:
+        kinds:
+        - Pod
+    validate:
+      message: label app is required
+      pattern:
+        metadata:
+          labels:
+            app: "?*"
+`
+
+func Test_GetPolicies_Directory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "policies")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"require-label.yaml":          fmt.Sprintf(sourcePolicy, "require-label"),
+		"nested/policies.yml":         "---\n# the nested policies\n---\n" + fmt.Sprintf(sourcePolicy, "nested-1") + "---\n" + fmt.Sprintf(sourcePolicy, "nested-2"),
+		"nested/README.md":            "the nested policies",
+		".github/workflows/lint.yaml": "name: lint",
+		"nested/kustomization.yaml":   "resources:\n- policies.yml\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NilError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	policies, errors := GetPolicies([]string{dir})
+	assert.Equal(t, len(errors), 1)
+
+	var names []string
+	for _, policy := range policies {
+		names = append(names, policy.Name)
+	}
+	assert.DeepEqual(t, names, []string{"nested-1", "nested-2", "require-label"})
+}
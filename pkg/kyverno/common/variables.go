@@ -0,0 +1,65 @@
+package common
+
+import (
+	"encoding/json"
+	"strings"
+
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+)
+
+// MergeVariables returns the values of the variables of a policy and a resource, the values of
+// the resource override the global values, and the values of the set flag override both
+func MergeVariables(globalValues, resourceValues, variables map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for _, values := range []map[string]interface{}{globalValues, resourceValues, variables} {
+		for key, value := range values {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
+
+// variableJSON returns the JSON of the value nested under the keys of the variable, e.g.
+// {"request":{"userInfo":{"username":"admin"}}} for request.userInfo.username=admin
+func variableJSON(key string, value interface{}) ([]byte, error) {
+	keys := strings.Split(key, ".")
+	for i := len(keys) - 1; i >= 0; i-- {
+		value = map[string]interface{}{keys[i]: value}
+	}
+
+	return json.Marshal(value)
+}
+
+// removeContextEntries returns the policy without the context entries whose values are passed
+// as variables, the policy is copied when an entry is removed
+func removeContextEntries(policy *v1.ClusterPolicy, variables map[string]interface{}) *v1.ClusterPolicy {
+	passed := make(map[string]bool)
+	for key := range variables {
+		passed[strings.Split(key, ".")[0]] = true
+	}
+
+	var copied *v1.ClusterPolicy
+	for i, rule := range policy.Spec.Rules {
+		var entries []v1.ContextEntry
+		for _, entry := range rule.Context {
+			if !passed[entry.Name] {
+				entries = append(entries, entry)
+			}
+		}
+
+		if len(entries) == len(rule.Context) {
+			continue
+		}
+
+		if copied == nil {
+			copied = policy.DeepCopy()
+		}
+		copied.Spec.Rules[i].Context = entries
+	}
+
+	if copied == nil {
+		return policy
+	}
+	return copied
+}
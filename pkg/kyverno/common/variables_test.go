@@ -0,0 +1,55 @@
+package common
+
+import (
+	"testing"
+
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"gotest.tools/assert"
+)
+
+func Test_VariableJSON(t *testing.T) {
+	data, err := variableJSON("request.userInfo.groups", []interface{}{"devs", "ops"})
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"request":{"userInfo":{"groups":["devs","ops"]}}}`)
+
+	data, err = variableJSON("dictionary.data.registry", `ghcr.io/"kyverno"`)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"dictionary":{"data":{"registry":"ghcr.io/\"kyverno\""}}}`)
+}
+
+func Test_MergeVariables(t *testing.T) {
+	globalValues := map[string]interface{}{"request.userInfo.username": "bob", "namespaceLabels": map[string]interface{}{"env": "dev"}}
+	resourceValues := map[string]interface{}{"request.userInfo.username": "alice"}
+	variables := map[string]interface{}{"namespaceLabels": map[string]interface{}{"env": "prod"}}
+
+	assert.DeepEqual(t, MergeVariables(globalValues, resourceValues, variables), map[string]interface{}{
+		"request.userInfo.username": "alice",
+		"namespaceLabels":           map[string]interface{}{"env": "prod"},
+	})
+
+	assert.DeepEqual(t, MergeVariables(nil, nil, nil), map[string]interface{}{})
+}
+
+func Test_RemoveContextEntries(t *testing.T) {
+	policy := &v1.ClusterPolicy{
+		Spec: v1.Spec{
+			Rules: []v1.Rule{
+				{
+					Name: "registries",
+					Context: []v1.ContextEntry{
+						{Name: "dictionary", ConfigMap: &v1.ConfigMapReference{Name: "registries", Namespace: "default"}},
+						{Name: "teams", ConfigMap: &v1.ConfigMapReference{Name: "teams", Namespace: "default"}},
+					},
+				},
+			},
+		},
+	}
+
+	unchanged := removeContextEntries(policy, map[string]interface{}{"request.userInfo.username": "bob"})
+	assert.Assert(t, unchanged == policy)
+
+	removed := removeContextEntries(policy, map[string]interface{}{"dictionary.data.registry": "ghcr.io"})
+	assert.Equal(t, len(removed.Spec.Rules[0].Context), 1)
+	assert.Equal(t, removed.Spec.Rules[0].Context[0].Name, "teams")
+	assert.Equal(t, len(policy.Spec.Rules[0].Context), 2)
+}
@@ -0,0 +1,108 @@
+package create
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/kyverno/kyverno/pkg/kyverno/common"
+	sanitizederror "github.com/kyverno/kyverno/pkg/kyverno/sanitizedError"
+	"github.com/spf13/cobra"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+var createPolicyHelp = `
+To create a validation policy which requires fields of the kind of an example resource:
+	kyverno create policy --from=/path/to/resource.yaml --require-fields=metadata.labels.team,spec.securityContext.runAsNonRoot
+
+To require the fields of the elements of a list, the list fields end with []:
+	kyverno create policy --from=/path/to/pod.yaml --require-fields=spec.containers[].resources.limits.memory
+
+To write the policy to a file, and to enforce it:
+	kyverno create policy --from=/path/to/resource.yaml --require-fields=metadata.labels.team --validation-failure-action=enforce --output=policy.yaml
+
+The policy matches the kind of the resource. The required fields must have a value, the booleans of
+the resource are required to have its value, e.g. runAsNonRoot: true. The policy is a starter policy,
+its name, its message and its pattern may be edited before applying it.
+`
+
+// Command returns create command
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "creates starter policies",
+	}
+	cmd.AddCommand(policyCommand())
+	return cmd
+}
+
+func policyCommand() *cobra.Command {
+	var from, name, validationFailureAction, output string
+	var requireFields []string
+	cmd := &cobra.Command{
+		Use:     "policy",
+		Short:   "creates a starter validation policy from an example resource",
+		Example: createPolicyHelp,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			defer func() {
+				if err != nil {
+					if !sanitizederror.IsErrorSanitized(err) {
+						log.Log.Error(err, "failed to sanitize")
+						err = fmt.Errorf("internal error")
+					}
+				}
+			}()
+
+			if from == "" {
+				return sanitizederror.NewWithError("an example resource is required, pass it with the from flag", nil)
+			}
+
+			if len(requireFields) == 0 {
+				return sanitizederror.NewWithError("the required fields are required, pass them with the require-fields flag", nil)
+			}
+
+			resourceBytes, err := ioutil.ReadFile(from)
+			if err != nil {
+				return sanitizederror.NewWithError("failed to read the example resource", err)
+			}
+
+			resources, err := common.GetResource(resourceBytes)
+			if err != nil {
+				return sanitizederror.NewWithError("failed to decode the example resource", err)
+			}
+
+			if len(resources) != 1 {
+				return sanitizederror.NewWithError(fmt.Sprintf("a single example resource is required, %s has %d resources", from, len(resources)), nil)
+			}
+
+			policy, err := generatePolicy(resources[0], name, requireFields, validationFailureAction)
+			if err != nil {
+				return sanitizederror.NewWithError("failed to create the policy", err)
+			}
+
+			policyYAML, err := yaml.Marshal(policy.Object)
+			if err != nil {
+				return sanitizederror.NewWithError("failed to encode the policy", err)
+			}
+
+			if output == "" {
+				fmt.Print(string(policyYAML))
+				return nil
+			}
+
+			if err := ioutil.WriteFile(output, policyYAML, 0644); err != nil {
+				return sanitizederror.NewWithError("failed to write the policy", err)
+			}
+
+			fmt.Printf("policy %s written to %s\n", policy.GetName(), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&from, "from", "", "", "Path to the example resource, the policy matches its kind")
+	cmd.Flags().StringSliceVarP(&requireFields, "require-fields", "", []string{}, "Paths of the required fields, separated by commas, e.g. metadata.labels.team")
+	cmd.Flags().StringVarP(&name, "name", "", "", "Name of the policy, require-<kind>-fields by default")
+	cmd.Flags().StringVarP(&validationFailureAction, "validation-failure-action", "", "audit", "Validation failure action of the policy, audit or enforce")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Writes the policy to the provided file instead of the standard output")
+	return cmd
+}
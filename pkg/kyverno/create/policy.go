@@ -0,0 +1,188 @@
+package create
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// requiredPattern is the pattern of the required fields, any value but an empty value
+const requiredPattern = "?*"
+
+// fieldSegment is a segment of the path of a required field, the list segments end with []
+type fieldSegment struct {
+	name string
+	list bool
+}
+
+// generatePolicy generates a validation policy which matches the kind of the example resource and
+// requires the fields, e.g. metadata.labels.team or spec.containers[].image. The booleans of the
+// example resource are required to have its value, the other fields are required to have a value.
+func generatePolicy(resource *unstructured.Unstructured, name string, fields []string, validationFailureAction string) (*unstructured.Unstructured, error) {
+	if validationFailureAction != "audit" && validationFailureAction != "enforce" {
+		return nil, fmt.Errorf("invalid validation failure action %s, audit or enforce are supported", validationFailureAction)
+	}
+
+	kind := resource.GetKind()
+	if kind == "" {
+		return nil, fmt.Errorf("the kind of the example resource is required")
+	}
+
+	if name == "" {
+		name = fmt.Sprintf("require-%s-fields", strings.ToLower(kind))
+	}
+
+	fields = uniqueFields(fields)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("at least one field is required")
+	}
+
+	pattern := map[string]interface{}{}
+	for _, field := range fields {
+		path, err := parseFieldPath(field)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := addRequiredField(pattern, resource.Object, path, field); err != nil {
+			return nil, err
+		}
+	}
+
+	message := fmt.Sprintf("The field %s is required.", fields[0])
+	if len(fields) > 1 {
+		message = fmt.Sprintf("The fields %s are required.", strings.Join(fields, ", "))
+	}
+
+	policy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kyverno.io/v1",
+		"kind":       "ClusterPolicy",
+		"metadata": map[string]interface{}{
+			"name": name,
+			"annotations": map[string]interface{}{
+				"policies.kyverno.io/description": fmt.Sprintf("Requires the fields %s of the %s resources.", strings.Join(fields, ", "), kind),
+			},
+		},
+		"spec": map[string]interface{}{
+			"validationFailureAction": validationFailureAction,
+			"background":              true,
+			"rules": []interface{}{
+				map[string]interface{}{
+					"name": "check-required-fields",
+					"match": map[string]interface{}{
+						"resources": map[string]interface{}{
+							"kinds": []interface{}{kind},
+						},
+					},
+					"validate": map[string]interface{}{
+						"message": message,
+						"pattern": pattern,
+					},
+				},
+			},
+		},
+	}}
+
+	return policy, nil
+}
+
+// uniqueFields returns the non-empty fields, in order and without duplicates
+func uniqueFields(fields []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		unique = append(unique, field)
+	}
+	return unique
+}
+
+func parseFieldPath(field string) ([]fieldSegment, error) {
+	var path []fieldSegment
+	for _, name := range strings.Split(field, ".") {
+		segment := fieldSegment{name: strings.TrimSuffix(name, "[]")}
+		segment.list = segment.name != name
+		if segment.name == "" {
+			return nil, fmt.Errorf("invalid field %s, the fields are separated by dots, e.g. metadata.labels.team", field)
+		}
+		path = append(path, segment)
+	}
+
+	return path, nil
+}
+
+// addRequiredField adds the required field to the pattern, the example is the value of the example
+// resource at the same path, nil if the example resource does not have the field
+func addRequiredField(pattern map[string]interface{}, example interface{}, path []fieldSegment, field string) error {
+	segment := path[0]
+	var exampleValue interface{}
+	if exampleMap, ok := example.(map[string]interface{}); ok {
+		exampleValue = exampleMap[segment.name]
+	}
+
+	if segment.list {
+		if len(path) == 1 {
+			return fmt.Errorf("field %s is a list, the fields of its elements are required, e.g. %s.name", field, field)
+		}
+
+		if _, ok := pattern[segment.name]; !ok {
+			pattern[segment.name] = []interface{}{map[string]interface{}{}}
+		}
+
+		elements, ok := pattern[segment.name].([]interface{})
+		if !ok {
+			return fmt.Errorf("field %s conflicts with another required field", field)
+		}
+
+		var exampleElement interface{}
+		if exampleList, ok := exampleValue.([]interface{}); ok && len(exampleList) > 0 {
+			exampleElement = exampleList[0]
+		}
+
+		return addRequiredField(elements[0].(map[string]interface{}), exampleElement, path[1:], field)
+	}
+
+	if len(path) == 1 {
+		if _, ok := pattern[segment.name]; ok {
+			return fmt.Errorf("field %s conflicts with another required field", field)
+		}
+
+		value, err := requiredValue(exampleValue, field)
+		if err != nil {
+			return err
+		}
+
+		pattern[segment.name] = value
+		return nil
+	}
+
+	if _, ok := pattern[segment.name]; !ok {
+		pattern[segment.name] = map[string]interface{}{}
+	}
+
+	child, ok := pattern[segment.name].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("field %s conflicts with another required field", field)
+	}
+
+	return addRequiredField(child, exampleValue, path[1:], field)
+}
+
+// requiredValue returns the pattern of the required field from its value in the example resource
+func requiredValue(exampleValue interface{}, field string) (interface{}, error) {
+	switch value := exampleValue.(type) {
+	case bool:
+		return value, nil
+	case map[string]interface{}:
+		return nil, fmt.Errorf("field %s is an object, its fields are required, e.g. %s.name", field, field)
+	case []interface{}:
+		return nil, fmt.Errorf("field %s is a list, the fields of its elements are required, e.g. %s[].name", field, field)
+	default:
+		return requiredPattern, nil
+	}
+}
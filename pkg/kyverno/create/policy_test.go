@@ -0,0 +1,59 @@
+package create
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_generatePolicy(t *testing.T) {
+	resource := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": "nginx", "labels": map[string]interface{}{"team": "web"}},
+		"spec": map[string]interface{}{
+			"securityContext": map[string]interface{}{"runAsNonRoot": true},
+			"containers":      []interface{}{map[string]interface{}{"name": "nginx", "image": "nginx"}},
+		},
+	}}
+
+	policy, err := generatePolicy(resource, "", []string{"metadata.labels.team", "spec.securityContext.runAsNonRoot", "spec.containers[].image", "metadata.labels.team"}, "audit")
+	assert.NilError(t, err)
+	assert.Equal(t, policy.GetName(), "require-pod-fields")
+
+	rules, _, _ := unstructured.NestedSlice(policy.Object, "spec", "rules")
+	assert.Equal(t, len(rules), 1)
+	rule := rules[0].(map[string]interface{})
+
+	kinds, _, _ := unstructured.NestedStringSlice(rule, "match", "resources", "kinds")
+	assert.DeepEqual(t, kinds, []string{"Pod"})
+
+	message, _, _ := unstructured.NestedString(rule, "validate", "message")
+	assert.Equal(t, message, "The fields metadata.labels.team, spec.securityContext.runAsNonRoot, spec.containers[].image are required.")
+
+	pattern, _, _ := unstructured.NestedMap(rule, "validate", "pattern")
+	assert.DeepEqual(t, pattern, map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]interface{}{"team": "?*"}},
+		"spec": map[string]interface{}{
+			"securityContext": map[string]interface{}{"runAsNonRoot": true},
+			"containers":      []interface{}{map[string]interface{}{"image": "?*"}},
+		},
+	})
+
+	_, err = generatePolicy(resource, "", []string{"metadata.labels"}, "audit")
+	assert.ErrorContains(t, err, "field metadata.labels is an object")
+
+	_, err = generatePolicy(resource, "", []string{"spec.containers"}, "audit")
+	assert.ErrorContains(t, err, "field spec.containers is a list")
+
+	_, err = generatePolicy(resource, "", []string{"spec.hostname", "spec.hostname.name"}, "audit")
+	assert.ErrorContains(t, err, "conflicts with another required field")
+
+	// the empty fields are ignored, e.g. with --require-fields=,
+	_, err = generatePolicy(resource, "", []string{"", " "}, "audit")
+	assert.ErrorContains(t, err, "at least one field is required")
+
+	_, err = generatePolicy(resource, "", []string{"metadata.labels.team"}, "deny")
+	assert.ErrorContains(t, err, "invalid validation failure action")
+}
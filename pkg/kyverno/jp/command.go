@@ -0,0 +1,119 @@
+package jp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/kyverno/kyverno/pkg/engine/context"
+	sanitizederror "github.com/kyverno/kyverno/pkg/kyverno/sanitizedError"
+	"github.com/spf13/cobra"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+var jpHelp = `
+To evaluate an expression on a JSON or YAML document:
+	kyverno jp "spec.containers[].image" --input=/path/to/pod.yaml
+
+To evaluate an expression on the standard input:
+	kubectl get pod nginx -o yaml | kyverno jp "metadata.labels"
+
+To evaluate a variable of a policy on a resource, the resource is request.object:
+	kyverno jp "{{ request.object.metadata.name }}" --input=/path/to/pod.yaml --resource
+
+To evaluate the custom functions of the policies:
+	kyverno jp "time_add(metadata.creationTimestamp, '24h')" --input=/path/to/pod.yaml
+	kyverno jp --list-functions
+
+The expressions are evaluated as the variables of the policies, the JMESPath functions and the
+custom functions are available. The result is printed as JSON.
+`
+
+// Command returns jp command
+func Command() *cobra.Command {
+	var input string
+	var asResource, listFunctions bool
+	cmd := &cobra.Command{
+		Use:     "jp <expression>",
+		Short:   "evaluates JMESPath expressions, as the variables of the policies, on a document",
+		Example: jpHelp,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			defer func() {
+				if err != nil {
+					if !sanitizederror.IsErrorSanitized(err) {
+						log.Log.Error(err, "failed to sanitize")
+						err = fmt.Errorf("internal error")
+					}
+				}
+			}()
+
+			if listFunctions {
+				for _, name := range context.FunctionNames() {
+					fmt.Println(name)
+				}
+				return nil
+			}
+
+			if len(args) != 1 {
+				return sanitizederror.NewWithError("a single expression is required", nil)
+			}
+
+			var document []byte
+			if input == "" || input == "-" {
+				document, err = ioutil.ReadAll(os.Stdin)
+			} else {
+				document, err = ioutil.ReadFile(input)
+			}
+			if err != nil {
+				return sanitizederror.NewWithError("failed to read the document", err)
+			}
+
+			result, err := evaluate(args[0], document, asResource)
+			if err != nil {
+				return sanitizederror.NewWithError(fmt.Sprintf("failed to evaluate %s", args[0]), err)
+			}
+
+			data, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return sanitizederror.NewWithError("failed to marshal the result", err)
+			}
+
+			fmt.Println(string(data))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&input, "input", "i", "", "Path to the JSON or YAML document, the standard input by default")
+	cmd.Flags().BoolVarP(&asResource, "resource", "", false, "Evaluates the expression on the document as the resource of the admission request, request.object")
+	cmd.Flags().BoolVarP(&listFunctions, "list-functions", "", false, "Prints the names of the custom functions")
+	return cmd
+}
+
+// evaluate evaluates the expression on the JSON or YAML document, the expression may be a
+// variable of a policy, e.g. {{ request.object.metadata.name }}
+func evaluate(expression string, document []byte, asResource bool) (interface{}, error) {
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "{{") && strings.HasSuffix(expression, "}}") {
+		expression = strings.TrimSpace(expression[2 : len(expression)-2])
+	}
+
+	documentJSON, err := yaml.YAMLToJSON(document)
+	if err != nil {
+		return nil, fmt.Errorf("the document is not JSON or YAML: %v", err)
+	}
+
+	ctx := context.NewContext()
+	if asResource {
+		err = ctx.AddResource(documentJSON)
+	} else {
+		err = ctx.AddJSON(documentJSON)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ctx.Query(expression)
+}
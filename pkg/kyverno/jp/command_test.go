@@ -0,0 +1,46 @@
+package jp
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+var pod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+  creationTimestamp: "2021-01-01T10:00:00Z"
+  labels:
+    app: nginx
+spec:
+  containers:
+  - name: nginx
+    image: nginx:1.19
+  - name: sidecar
+    image: ghcr.io/kyverno/sidecar:latest
+`
+
+func Test_Evaluate(t *testing.T) {
+	testcases := []struct {
+		expression string
+		asResource bool
+		expected   interface{}
+	}{
+		{expression: "metadata.name", expected: "nginx"},
+		{expression: "spec.containers[].image", expected: []interface{}{"nginx:1.19", "ghcr.io/kyverno/sidecar:latest"}},
+		{expression: "length(spec.containers)", expected: float64(2)},
+		{expression: "{{ request.object.metadata.labels.app }}", asResource: true, expected: "nginx"},
+		{expression: "time_add(metadata.creationTimestamp, '24h')", expected: "2021-01-02T10:00:00Z"},
+		{expression: "to_upper(metadata.name)", expected: "NGINX"},
+	}
+
+	for _, tc := range testcases {
+		result, err := evaluate(tc.expression, []byte(pod), tc.asResource)
+		assert.NilError(t, err, tc.expression)
+		assert.DeepEqual(t, result, tc.expected)
+	}
+
+	_, err := evaluate("spec.containers[", []byte(pod), false)
+	assert.ErrorContains(t, err, "incorrect query")
+}
@@ -3,8 +3,16 @@ package kyverno
 import (
 	"flag"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/kyverno/kyverno/pkg/kyverno/apply"
+	"github.com/kyverno/kyverno/pkg/kyverno/common"
+	"github.com/kyverno/kyverno/pkg/kyverno/create"
+	"github.com/kyverno/kyverno/pkg/kyverno/jp"
+	"github.com/kyverno/kyverno/pkg/kyverno/oci"
+	"github.com/kyverno/kyverno/pkg/kyverno/replay"
+	"github.com/kyverno/kyverno/pkg/kyverno/scan"
 	"github.com/kyverno/kyverno/pkg/kyverno/test"
 	"github.com/kyverno/kyverno/pkg/kyverno/validate"
 	"github.com/kyverno/kyverno/pkg/kyverno/version"
@@ -28,15 +36,36 @@ func CLI() {
 		apply.Command(),
 		validate.Command(),
 		test.Command(),
+		scan.Command(),
+		jp.Command(),
+		create.Command(),
+		oci.Command(),
+		replay.Command(),
 	}
 
 	cli.AddCommand(commands...)
+	common.AddKubeConfigFlags(cli)
 
-	if err := cli.Execute(); err != nil {
-		os.Exit(1)
+	if err := pluginCommand(cli).Execute(); err != nil {
+		os.Exit(common.ExitCodeOf(err))
 	}
 }
 
+// pluginCommand returns the command to execute, when the CLI is run as a kubectl plugin, i.e. as
+// kubectl kyverno, the command is nested in a kubectl command so that the usage shows kubectl kyverno
+func pluginCommand(cli *cobra.Command) *cobra.Command {
+	if !strings.HasPrefix(filepath.Base(os.Args[0]), "kubectl-") {
+		return cli
+	}
+
+	kubectl := &cobra.Command{
+		Use: "kubectl",
+	}
+	kubectl.AddCommand(cli)
+	kubectl.SetArgs(append([]string{cli.Name()}, os.Args[1:]...))
+	return kubectl
+}
+
 func configurelog(cli *cobra.Command) {
 	klog.InitFlags(nil)
 	log.SetLogger(klogr.New())
@@ -0,0 +1,224 @@
+package oci
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/kyverno/kyverno/pkg/kyverno/common"
+	sanitizederror "github.com/kyverno/kyverno/pkg/kyverno/sanitizedError"
+	"github.com/spf13/cobra"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var ociHelp = `
+To push the policies of files or directories as a policy bundle to a registry:
+	kyverno oci push /path/to/policy.yaml /path/to/folderOfPolicies --image=ghcr.io/<owner>/policies:v1
+
+To annotate the bundle, e.g. with its source:
+	kyverno oci push /path/to/folderOfPolicies --image=ghcr.io/<owner>/policies:v1 --annotation=org.opencontainers.image.source=https://github.com/<owner>/policies
+
+To sign the bundle with an unencrypted ECDSA private key, the signature is pushed as cosign pushes it:
+	kyverno oci push /path/to/folderOfPolicies --image=ghcr.io/<owner>/policies:v1 --key=/path/to/private.pem
+
+To pull the policies of a bundle to a directory, and to verify its signature with the public key:
+	kyverno oci pull --image=ghcr.io/<owner>/policies:v1 --directory=/path/to/folderOfPolicies
+	kyverno oci pull --image=ghcr.io/<owner>/policies:v1 --directory=/path/to/folderOfPolicies --key=/path/to/public.pem
+
+The registries are authenticated with the credentials of the docker config, e.g. of docker login.
+The policies of a bundle are the layers of an OCI artifact, a layer per policy, the pulled policies
+are written to the directory as <policy>.yaml files.
+`
+
+// Command returns oci command
+func Command() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "oci",
+		Short:   "pushes and pulls policy bundles to and from OCI registries",
+		Example: ociHelp,
+	}
+	cmd.AddCommand(pushCommand(), pullCommand())
+	return cmd
+}
+
+func pushCommand() *cobra.Command {
+	var image, key string
+	var annotations map[string]string
+	cmd := &cobra.Command{
+		Use:     "push",
+		Short:   "pushes policies as a policy bundle to an OCI registry",
+		Example: ociHelp,
+		RunE: func(cmd *cobra.Command, policyPaths []string) (err error) {
+			defer func() {
+				if err != nil {
+					if !sanitizederror.IsErrorSanitized(err) {
+						log.Log.Error(err, "failed to sanitize")
+						err = fmt.Errorf("internal error")
+					}
+				}
+			}()
+
+			if image == "" {
+				return sanitizederror.NewWithError("an image reference is required, pass it with the image flag", nil)
+			}
+
+			if len(policyPaths) == 0 {
+				return sanitizederror.NewWithError("require policy", nil)
+			}
+
+			digest, err := pushBundle(image, policyPaths, annotations, key, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+			if err != nil {
+				return sanitizederror.NewWithError("failed to push the policy bundle", err)
+			}
+
+			fmt.Printf("pushed %s@%s\n", image, digest)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&image, "image", "i", "", "Reference of the image of the bundle, e.g. ghcr.io/<owner>/policies:v1")
+	cmd.Flags().StringToStringVarP(&annotations, "annotation", "a", map[string]string{}, "Annotations of the manifest of the bundle, key=value")
+	cmd.Flags().StringVarP(&key, "key", "", "", "Path to the PEM encoded ECDSA private key which signs the bundle")
+	return cmd
+}
+
+func pullCommand() *cobra.Command {
+	var image, directory, key string
+	cmd := &cobra.Command{
+		Use:     "pull",
+		Short:   "pulls the policies of a policy bundle from an OCI registry",
+		Example: ociHelp,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			defer func() {
+				if err != nil {
+					if !sanitizederror.IsErrorSanitized(err) {
+						log.Log.Error(err, "failed to sanitize")
+						err = fmt.Errorf("internal error")
+					}
+				}
+			}()
+
+			if image == "" {
+				return sanitizederror.NewWithError("an image reference is required, pass it with the image flag", nil)
+			}
+
+			files, err := pullBundle(image, directory, key, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+			if err != nil {
+				return sanitizederror.NewWithError("failed to pull the policy bundle", err)
+			}
+
+			for _, file := range files {
+				fmt.Printf("pulled %s\n", file)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&image, "image", "i", "", "Reference of the image of the bundle, e.g. ghcr.io/<owner>/policies:v1")
+	cmd.Flags().StringVarP(&directory, "directory", "d", ".", "Directory to which the policies are written")
+	cmd.Flags().StringVarP(&key, "key", "", "", "Path to the PEM encoded ECDSA public key which verifies the signature of the bundle")
+	return cmd
+}
+
+// pushBundle pushes the policies of the paths as a policy bundle, signed with the private key of the
+// key file if it is passed, and returns the digest of the bundle
+func pushBundle(image string, policyPaths []string, annotations map[string]string, keyPath string, options ...remote.Option) (v1.Hash, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	var key *ecdsa.PrivateKey
+	if keyPath != "" {
+		if key, err = loadPrivateKey(keyPath); err != nil {
+			return v1.Hash{}, err
+		}
+	}
+
+	documents, errs := common.GetPolicyDocuments(policyPaths)
+	if len(errs) > 0 {
+		return v1.Hash{}, fmt.Errorf("failed to load policies: %v", errs)
+	}
+
+	if len(documents) == 0 {
+		return v1.Hash{}, fmt.Errorf("no policy found in %v", policyPaths)
+	}
+
+	bundle, err := newBundleImage(documents, annotations)
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	digest, err := bundle.Digest()
+	if err != nil {
+		return v1.Hash{}, err
+	}
+
+	if err := remote.Write(ref, bundle, options...); err != nil {
+		return v1.Hash{}, err
+	}
+
+	if key != nil {
+		if err := signBundle(ref.Context(), digest, key, options...); err != nil {
+			return v1.Hash{}, err
+		}
+	}
+
+	return digest, nil
+}
+
+// pullBundle pulls the policies of the bundle to the directory, the signature of the bundle is
+// verified first with the public key of the key file if it is passed, and returns the written files
+func pullBundle(image string, directory string, keyPath string, options ...remote.Option) ([]string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := remote.Image(ref, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyPath != "" {
+		key, err := loadPublicKey(keyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		digest, err := bundle.Digest()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := verifyBundle(ref.Context(), digest, key, options...); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := readBundleImage(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, err
+	}
+
+	written := make([]string, 0, len(files))
+	for _, file := range files {
+		path := filepath.Join(directory, file.title)
+		if err := ioutil.WriteFile(path, file.content, 0644); err != nil {
+			return nil, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
@@ -0,0 +1,93 @@
+package oci
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"gotest.tools/assert"
+)
+
+func writeKeys(t *testing.T, dir, prefix string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+
+	privateDER, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NilError(t, err)
+
+	privatePath := filepath.Join(dir, prefix+"-private.pem")
+	publicPath := filepath.Join(dir, prefix+"-public.pem")
+	assert.NilError(t, ioutil.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privateDER}), 0600))
+	assert.NilError(t, ioutil.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644))
+	return privatePath, publicPath
+}
+
+func Test_PushPullBundle(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "oci")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	privatePath, publicPath := writeKeys(t, dir, "signer")
+	otherPrivatePath, otherPublicPath := writeKeys(t, dir, "other")
+
+	image := strings.TrimPrefix(server.URL, "http://") + "/policies:v1"
+	policyPaths := []string{"../../../test/policy/validate/check_cpu_memory.yaml", "../../../test/policy/validate/check_nodeport.yaml"}
+	annotations := map[string]string{"org.opencontainers.image.source": "https://github.com/kyverno/policies"}
+
+	digest, err := pushBundle(image, policyPaths, annotations, privatePath)
+	assert.NilError(t, err)
+
+	ref, err := name.ParseReference(image)
+	assert.NilError(t, err)
+	bundle, err := remote.Image(ref)
+	assert.NilError(t, err)
+	manifest, err := bundle.Manifest()
+	assert.NilError(t, err)
+	assert.Equal(t, manifest.Config.MediaType, policyConfigMediaType)
+	assert.Equal(t, manifest.Annotations["org.opencontainers.image.source"], "https://github.com/kyverno/policies")
+	assert.Equal(t, len(manifest.Layers), 2)
+	assert.Equal(t, manifest.Layers[0].Annotations[annotationPolicy], "check-cpu-memory")
+
+	files, err := pullBundle(image, filepath.Join(dir, "policies"), publicPath)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, files, []string{filepath.Join(dir, "policies", "check-cpu-memory.yaml"), filepath.Join(dir, "policies", "check-node-port.yaml")})
+
+	content, err := ioutil.ReadFile(files[0])
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(content), "name: check-cpu-memory"))
+
+	_, err = pullBundle(image, filepath.Join(dir, "policies"), otherPublicPath)
+	assert.ErrorContains(t, err, "no signature of "+digest.String())
+
+	// the signatures of other keys are appended to the signatures of the bundle
+	otherKey, err := loadPrivateKey(otherPrivatePath)
+	assert.NilError(t, err)
+	assert.NilError(t, signBundle(ref.Context(), digest, otherKey))
+
+	signatures, err := remote.Image(signatureTag(ref.Context(), digest))
+	assert.NilError(t, err)
+	manifest, err = signatures.Manifest()
+	assert.NilError(t, err)
+	assert.Equal(t, len(manifest.Layers), 2)
+
+	for _, path := range []string{publicPath, otherPublicPath} {
+		_, err = pullBundle(image, filepath.Join(dir, "policies"), path)
+		assert.NilError(t, err)
+	}
+}
@@ -0,0 +1,227 @@
+package oci
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/kyverno/kyverno/pkg/kyverno/common"
+)
+
+const (
+	// policyConfigMediaType is the media type of the config of the policy bundles
+	policyConfigMediaType types.MediaType = "application/vnd.cncf.kyverno.config.v1+json"
+
+	// policyLayerMediaType is the media type of the layers of the policy bundles, a layer per policy
+	policyLayerMediaType types.MediaType = "application/vnd.cncf.kyverno.policy.layer.v1+yaml"
+
+	// annotationTitle is the file name of a layer, the pulled policies are written to their titles
+	annotationTitle = "org.opencontainers.image.title"
+
+	// annotationPolicy is the name of the policy of a layer
+	annotationPolicy = "io.kyverno.image.policy"
+)
+
+// bundleConfig is the config of the policy bundles
+type bundleConfig struct {
+	Policies []string `json:"policies"`
+}
+
+// blob is a layer of an artifact, or its config
+type blob struct {
+	content     []byte
+	mediaType   types.MediaType
+	annotations map[string]string
+}
+
+func (b *blob) descriptor() (v1.Descriptor, error) {
+	digest, _, err := v1.SHA256(bytes.NewReader(b.content))
+	if err != nil {
+		return v1.Descriptor{}, err
+	}
+
+	return v1.Descriptor{MediaType: b.mediaType, Size: int64(len(b.content)), Digest: digest, Annotations: b.annotations}, nil
+}
+
+func (b *blob) Digest() (v1.Hash, error) {
+	descriptor, err := b.descriptor()
+	return descriptor.Digest, err
+}
+
+func (b *blob) Compressed() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(b.content)), nil
+}
+
+func (b *blob) Size() (int64, error) {
+	return int64(len(b.content)), nil
+}
+
+func (b *blob) MediaType() (types.MediaType, error) {
+	return b.mediaType, nil
+}
+
+// artifactImage is an OCI artifact, e.g. a policy bundle whose layers are the YAML documents of
+// the policies, the layers are not compressed
+type artifactImage struct {
+	manifest []byte
+	config   []byte
+	layers   map[v1.Hash]*blob
+}
+
+func (i *artifactImage) RawConfigFile() ([]byte, error) {
+	return i.config, nil
+}
+
+func (i *artifactImage) MediaType() (types.MediaType, error) {
+	return types.OCIManifestSchema1, nil
+}
+
+func (i *artifactImage) RawManifest() ([]byte, error) {
+	return i.manifest, nil
+}
+
+func (i *artifactImage) LayerByDigest(digest v1.Hash) (partial.CompressedLayer, error) {
+	if layer, ok := i.layers[digest]; ok {
+		return layer, nil
+	}
+
+	return nil, fmt.Errorf("layer %s not found", digest)
+}
+
+// newArtifactImage returns the OCI artifact of the config and the layers, the annotations are the
+// annotations of its manifest
+func newArtifactImage(config *blob, layers []*blob, annotations map[string]string) (v1.Image, error) {
+	image := &artifactImage{config: config.content, layers: make(map[v1.Hash]*blob)}
+	manifest := v1.Manifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIManifestSchema1,
+		Layers:        make([]v1.Descriptor, 0, len(layers)),
+	}
+
+	if len(annotations) > 0 {
+		manifest.Annotations = annotations
+	}
+
+	var err error
+	manifest.Config, err = config.descriptor()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, layer := range layers {
+		descriptor, err := layer.descriptor()
+		if err != nil {
+			return nil, err
+		}
+
+		manifest.Layers = append(manifest.Layers, descriptor)
+		image.layers[descriptor.Digest] = layer
+	}
+
+	image.manifest, err = json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return partial.CompressedToImage(image)
+}
+
+// newBundleImage returns the OCI artifact of the policies, a layer per policy, the annotations are
+// the annotations of its manifest
+func newBundleImage(documents []common.PolicyDocument, annotations map[string]string) (v1.Image, error) {
+	config := bundleConfig{Policies: make([]string, 0, len(documents))}
+	layers := make([]*blob, 0, len(documents))
+	titles := make(map[string]bool)
+	for _, document := range documents {
+		name := document.Policy.GetName()
+		title := name + ".yaml"
+		if document.Policy.GetNamespace() != "" {
+			title = document.Policy.GetNamespace() + "-" + title
+		}
+		if titles[title] {
+			return nil, fmt.Errorf("policy %s is bundled more than once", name)
+		}
+		titles[title] = true
+
+		layers = append(layers, &blob{
+			content:     document.Document,
+			mediaType:   policyLayerMediaType,
+			annotations: map[string]string{annotationTitle: title, annotationPolicy: name},
+		})
+		config.Policies = append(config.Policies, name)
+	}
+
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return newArtifactImage(&blob{content: configJSON, mediaType: policyConfigMediaType}, layers, annotations)
+}
+
+// readLayer returns the content of the layer of the image
+func readLayer(image v1.Image, digest v1.Hash) ([]byte, error) {
+	layer, err := image.LayerByDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := layer.Compressed()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch layer %s: %v", digest, err)
+	}
+	defer reader.Close()
+
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch layer %s: %v", digest, err)
+	}
+
+	return content, nil
+}
+
+// bundleFile is a policy of a pulled policy bundle, and the name of its file
+type bundleFile struct {
+	title   string
+	content []byte
+}
+
+// readBundleImage returns the policies of the OCI artifact of a policy bundle
+func readBundleImage(image v1.Image) ([]bundleFile, error) {
+	manifest, err := image.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.Config.MediaType != policyConfigMediaType {
+		return nil, fmt.Errorf("the image is not a policy bundle, its config media type is %s", manifest.Config.MediaType)
+	}
+
+	files := make([]bundleFile, 0, len(manifest.Layers))
+	for _, descriptor := range manifest.Layers {
+		if descriptor.MediaType != policyLayerMediaType {
+			continue
+		}
+
+		content, err := readLayer(image, descriptor.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		// the titles are file names, the files are not written out of the directory of the bundle
+		title := filepath.Base(descriptor.Annotations[annotationTitle])
+		if title == "." || title == ".." || title == string(filepath.Separator) {
+			title = descriptor.Digest.Hex + ".yaml"
+		}
+
+		files = append(files, bundleFile{title: title, content: content})
+	}
+
+	return files, nil
+}
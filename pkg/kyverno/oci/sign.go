@@ -0,0 +1,226 @@
+package oci
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// The signatures are pushed as cosign pushes them, to the tag sha256-<digest>.sig of the repository
+// of the bundle, so that the bundles signed with cosign are verified with its public key.
+const (
+	signatureConfigMediaType types.MediaType = "application/vnd.oci.image.config.v1+json"
+	simpleSigningMediaType   types.MediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	annotationSignature                      = "dev.cosignproject.cosign/signature"
+	simpleSigningType                        = "cosign container image signature"
+)
+
+// simpleSigning is the payload of the signatures, the signed manifest digest of the bundle
+type simpleSigning struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]interface{} `json:"optional"`
+}
+
+// signatureTag returns the tag of the signatures of the digest
+func signatureTag(repository name.Repository, digest v1.Hash) name.Tag {
+	return repository.Tag(fmt.Sprintf("%s-%s.sig", digest.Algorithm, digest.Hex))
+}
+
+// signBundle signs the digest of the bundle of the repository with the key, and pushes the signature
+func signBundle(repository name.Repository, digest v1.Hash, key *ecdsa.PrivateKey, options ...remote.Option) error {
+	var payload simpleSigning
+	payload.Critical.Identity.DockerReference = repository.Name()
+	payload.Critical.Image.DockerManifestDigest = digest.String()
+	payload.Critical.Type = simpleSigningType
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(payloadJSON)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		return fmt.Errorf("failed to sign %s: %v", digest, err)
+	}
+
+	layer := &blob{
+		content:     payloadJSON,
+		mediaType:   simpleSigningMediaType,
+		annotations: map[string]string{annotationSignature: base64.StdEncoding.EncodeToString(signature)},
+	}
+	// the signature is appended to the signatures of the digest, as cosign appends them
+	tag := signatureTag(repository, digest)
+	layers, err := signatureLayers(tag, options...)
+	if err != nil {
+		return err
+	}
+
+	image, err := newArtifactImage(&blob{content: []byte("{}"), mediaType: signatureConfigMediaType}, append(layers, layer), nil)
+	if err != nil {
+		return err
+	}
+
+	if err := remote.Write(tag, image, options...); err != nil {
+		return fmt.Errorf("failed to push the signature %s: %v", tag, err)
+	}
+
+	return nil
+}
+
+// signatureLayers returns the layers of the signatures pushed to the tag, no layers are returned when
+// the tag does not exist
+func signatureLayers(tag name.Tag, options ...remote.Option) ([]*blob, error) {
+	image, err := remote.Image(tag, options...)
+	if err != nil {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch the signatures %s: %v", tag, err)
+	}
+
+	manifest, err := image.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	layers := make([]*blob, 0, len(manifest.Layers))
+	for _, descriptor := range manifest.Layers {
+		content, err := readLayer(image, descriptor.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		layers = append(layers, &blob{content: content, mediaType: descriptor.MediaType, annotations: descriptor.Annotations})
+	}
+
+	return layers, nil
+}
+
+// verifyBundle verifies that a signature of the digest of the bundle of the repository is signed
+// with the private key of the public key
+func verifyBundle(repository name.Repository, digest v1.Hash, key *ecdsa.PublicKey, options ...remote.Option) error {
+	tag := signatureTag(repository, digest)
+	image, err := remote.Image(tag, options...)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the signatures %s: %v", tag, err)
+	}
+
+	manifest, err := image.Manifest()
+	if err != nil {
+		return err
+	}
+
+	for _, descriptor := range manifest.Layers {
+		signature, err := base64.StdEncoding.DecodeString(descriptor.Annotations[annotationSignature])
+		if err != nil || len(signature) == 0 {
+			continue
+		}
+
+		payloadJSON, err := readLayer(image, descriptor.Digest)
+		if err != nil {
+			return err
+		}
+
+		hash := sha256.Sum256(payloadJSON)
+		if !ecdsa.VerifyASN1(key, hash[:], signature) {
+			continue
+		}
+
+		var payload simpleSigning
+		if err := json.Unmarshal(payloadJSON, &payload); err != nil {
+			continue
+		}
+
+		if payload.Critical.Image.DockerManifestDigest == digest.String() {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("no signature of %s is signed with the key", digest)
+}
+
+// loadPrivateKey loads the PEM encoded, unencrypted, ECDSA private key of the file
+func loadPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("the key of %s is not an ECDSA key", path)
+		}
+		return ecdsaKey, nil
+	default:
+		return nil, fmt.Errorf("the key of %s is not an unencrypted ECDSA private key, its type is %s", path, block.Type)
+	}
+}
+
+// loadPublicKey loads the PEM encoded ECDSA public key of the file, e.g. a cosign public key
+func loadPublicKey(path string) (*ecdsa.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("the key of %s is not a public key, its type is %s", path, block.Type)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("the key of %s is not an ECDSA key", path)
+	}
+	return ecdsaKey, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("the key of %s is not PEM encoded", path)
+	}
+	return block, nil
+}
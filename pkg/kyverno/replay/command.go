@@ -0,0 +1,140 @@
+package replay
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/kyverno/kyverno/pkg/kyverno/common"
+	sanitizederror "github.com/kyverno/kyverno/pkg/kyverno/sanitizedError"
+	"github.com/spf13/cobra"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var replayHelp = `
+To replay the captured admission requests of a directory through candidate policies:
+	kyverno replay /path/to/policy.yaml /path/to/folderOfPolicies --requests=/path/to/capture
+
+To replay a single AdmissionReview, and to print the result of each request:
+	kyverno replay /path/to/policy.yaml --requests=/path/to/review.json --detailed-results
+
+The requests are the AdmissionReview objects captured by the webhook with the captureConfig flag,
+the files of the directories are replayed in order. The mutation and the validation rules are applied
+as the webhook applies them: the requests are denied by the failures of the enforced policies. The
+roles of the users and the labels of the namespaces are not captured, the rules which match them
+are not applied. The command exits with:
+	1 when a request is denied
+	2 when a request could not be replayed
+	the warn exit code when the only failures are the failures of the audited policies
+`
+
+// Command returns replay command
+func Command() *cobra.Command {
+	var requestPaths []string
+	var detailedResults bool
+	var warnExitCode int
+	cmd := &cobra.Command{
+		Use:     "replay",
+		Short:   "replays captured admission requests through policies",
+		Example: replayHelp,
+		RunE: func(cmd *cobra.Command, policyPaths []string) (err error) {
+			defer func() {
+				if err != nil {
+					if !sanitizederror.IsErrorSanitized(err) && !common.IsExitError(err) {
+						log.Log.Error(err, "failed to sanitize")
+						err = fmt.Errorf("internal error")
+					}
+				}
+			}()
+
+			if len(policyPaths) == 0 {
+				return sanitizederror.NewWithError("require policy", nil)
+			}
+
+			if len(requestPaths) == 0 {
+				return sanitizederror.NewWithError("the captured requests are required, pass them with the requests flag", nil)
+			}
+
+			policies, err := common.GetPoliciesFromPaths(memfs.New(), policyPaths, false, "")
+			if err != nil {
+				return sanitizederror.NewWithError("failed to load policies", err)
+			}
+
+			mutatedPolicies, err := common.MutatePolices(policies)
+			if err != nil {
+				return sanitizederror.NewWithError("failed to mutate policies", err)
+			}
+
+			reviews, err := readReviews(requestPaths)
+			if err != nil {
+				return sanitizederror.NewWithError("failed to read the captured requests", err)
+			}
+
+			rc := &resultCounts{}
+			for _, review := range reviews {
+				result := replayRequest(review.request, mutatedPolicies)
+				result.file = review.file
+				rc.add(result)
+				printResult(result, detailedResults)
+			}
+
+			fmt.Printf("\nrequests: %d, allowed: %d, denied: %d, warn: %d, error: %d, skip: %d\n",
+				len(reviews), rc.allowed, rc.denied, rc.warn, rc.error, rc.skip)
+
+			return common.ResultsError(cmd, common.ExitCode(rc.denied, rc.warn, rc.error, warnExitCode))
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&requestPaths, "requests", "r", []string{}, "Path to a captured AdmissionReview, or to a directory of captured AdmissionReviews")
+	cmd.Flags().BoolVarP(&detailedResults, "detailed-results", "", false, "Prints the result of each request, including the allowed requests")
+	cmd.Flags().IntVarP(&warnExitCode, "warn-exit-code", "", common.ExitPassed, "Exit code when the only failures are the failures of the audited policies")
+	return cmd
+}
+
+type resultCounts struct {
+	allowed int
+	denied  int
+	warn    int
+	error   int
+	skip    int
+}
+
+func (rc *resultCounts) add(result *replayResult) {
+	switch {
+	case result.err != nil:
+		rc.error++
+	case result.skipped:
+		rc.skip++
+	case len(result.denials) > 0:
+		rc.denied++
+	default:
+		rc.allowed++
+	}
+
+	if result.err == nil && len(result.warnings) > 0 {
+		rc.warn++
+	}
+}
+
+func printResult(result *replayResult, detailedResults bool) {
+	switch {
+	case result.err != nil:
+		fmt.Printf("ERROR %s (%s): %v\n", result.request, result.file, result.err)
+	case result.skipped:
+		if detailedResults {
+			fmt.Printf("SKIP %s (%s)\n", result.request, result.file)
+		}
+	case len(result.denials) > 0:
+		fmt.Printf("DENIED %s (%s)\n", result.request, result.file)
+	default:
+		if detailedResults || len(result.warnings) > 0 {
+			fmt.Printf("ALLOWED %s (%s)\n", result.request, result.file)
+		}
+	}
+
+	for _, denial := range result.denials {
+		fmt.Printf("  enforce %s\n", denial)
+	}
+	for _, warning := range result.warnings {
+		fmt.Printf("  audit %s\n", warning)
+	}
+}
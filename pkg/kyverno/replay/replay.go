@@ -0,0 +1,214 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	pkgcommon "github.com/kyverno/kyverno/pkg/common"
+	"github.com/kyverno/kyverno/pkg/engine"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/utils"
+	v1beta1 "k8s.io/api/admission/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+// capturedReview is a captured admission request and the file it is read from
+type capturedReview struct {
+	file    string
+	request *v1beta1.AdmissionRequest
+}
+
+// readReviews reads the AdmissionReviews of the files, the JSON and YAML files of the directories
+// are read in the order of their names, i.e. in the order of their capture
+func readReviews(paths []string) ([]capturedReview, error) {
+	var reviews []capturedReview
+	for _, path := range paths {
+		files, err := listFiles(path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+
+			reviewJSON, err := yaml.YAMLToJSON(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %v", file, err)
+			}
+
+			review := &v1beta1.AdmissionReview{}
+			if err := json.Unmarshal(reviewJSON, review); err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %v", file, err)
+			}
+
+			if review.Request == nil {
+				return nil, fmt.Errorf("%s is not an AdmissionReview with a request", file)
+			}
+
+			reviews = append(reviews, capturedReview{file: file, request: review.Request})
+		}
+	}
+
+	return reviews, nil
+}
+
+func listFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	infos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, info := range infos {
+		ext := filepath.Ext(info.Name())
+		if info.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		files = append(files, filepath.Join(path, info.Name()))
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// replayResult is the result of a replayed request
+type replayResult struct {
+	file    string
+	request string
+	skipped bool
+	err     error
+
+	// denials are the failed rules of the enforced policies, the warnings of the audited policies
+	denials  []string
+	warnings []string
+}
+
+// replayRequest applies the policies to the request as the webhook applies them: the mutation
+// rules are applied first, and the validation rules are applied to the mutated resource
+func replayRequest(request *v1beta1.AdmissionRequest, policies []*v1.ClusterPolicy) *replayResult {
+	result := &replayResult{
+		request: fmt.Sprintf("%s %s %s", request.Operation, request.Kind.Kind, request.Name),
+	}
+	if request.Namespace != "" {
+		result.request = fmt.Sprintf("%s %s %s/%s", request.Operation, request.Kind.Kind, request.Namespace, request.Name)
+	}
+
+	// the deleted resources are neither mutated nor validated by the webhook
+	if request.Operation == v1beta1.Delete || len(request.Object.Raw) == 0 {
+		result.skipped = true
+		return result
+	}
+
+	resource, err := utils.ConvertResource(request.Object.Raw, request.Kind.Group, request.Kind.Version, request.Kind.Kind, request.Namespace)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	userRequestInfo := v1.RequestInfo{AdmissionUserInfo: *request.UserInfo.DeepCopy()}
+	ctx := context.NewContext()
+	if err := ctx.AddRequest(request); err != nil {
+		result.err = fmt.Errorf("failed to load the request in the context: %v", err)
+		return result
+	}
+	if err := ctx.AddUserInfo(userRequestInfo); err != nil {
+		result.err = fmt.Errorf("failed to load the user info in the context: %v", err)
+		return result
+	}
+	if err := ctx.AddServiceAccount(userRequestInfo.AdmissionUserInfo.Username); err != nil {
+		result.err = fmt.Errorf("failed to load the service account in the context: %v", err)
+		return result
+	}
+
+	policyContext := &engine.PolicyContext{
+		NewResource:   resource,
+		AdmissionInfo: userRequestInfo,
+		JSONContext:   ctx,
+	}
+
+	if request.Operation == v1beta1.Update && len(request.OldObject.Raw) > 0 {
+		oldResource, err := utils.ConvertResource(request.OldObject.Raw, request.Kind.Group, request.Kind.Version, request.Kind.Kind, request.Namespace)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		policyContext.OldResource = oldResource
+	}
+
+	policies = policiesOfNamespace(policies, request.Namespace)
+
+	// MUTATION
+	for _, policy := range policies {
+		policyContext.Policy = *policy
+		engineResponse := engine.Mutate(policyContext)
+		if !engineResponse.IsSuccessful() {
+			result.warnings = append(result.warnings, failedRules(engineResponse)...)
+			continue
+		}
+
+		if len(engineResponse.GetPatches()) > 0 {
+			policyContext.NewResource = engineResponse.PatchedResource
+			if err := ctx.ReplaceResource(policyContext.NewResource); err != nil {
+				result.err = err
+				return result
+			}
+		}
+	}
+
+	// VALIDATION
+	for _, policy := range policies {
+		policyContext.Policy = *policy
+		engineResponse := engine.Validate(policyContext)
+		if engineResponse.IsSuccessful() {
+			continue
+		}
+
+		if engineResponse.PolicyResponse.ValidationFailureAction == pkgcommon.Enforce {
+			result.denials = append(result.denials, failedRules(engineResponse)...)
+		} else {
+			result.warnings = append(result.warnings, failedRules(engineResponse)...)
+		}
+	}
+
+	return result
+}
+
+// policiesOfNamespace returns the cluster policies and the policies of the namespace
+func policiesOfNamespace(policies []*v1.ClusterPolicy, namespace string) []*v1.ClusterPolicy {
+	var selected []*v1.ClusterPolicy
+	for _, policy := range policies {
+		if policy.GetNamespace() == "" || policy.GetNamespace() == namespace {
+			selected = append(selected, policy)
+		}
+	}
+	return selected
+}
+
+func failedRules(engineResponse *response.EngineResponse) []string {
+	var rules []string
+	for _, rule := range engineResponse.PolicyResponse.Rules {
+		if !rule.Success {
+			rules = append(rules, fmt.Sprintf("%s/%s: %s", engineResponse.PolicyResponse.Policy, rule.Name, strings.TrimSpace(rule.Message)))
+		}
+	}
+	return rules
+}
@@ -0,0 +1,87 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"gotest.tools/assert"
+	v1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var podRequest = &v1beta1.AdmissionRequest{
+	UID:       "a1b2",
+	Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+	Namespace: "default",
+	Name:      "nginx",
+	Operation: v1beta1.Create,
+	Object: runtime.RawExtension{Raw: []byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {"name": "nginx", "namespace": "default"},
+		"spec": {"containers": [{"name": "nginx", "image": "nginx:latest"}]}
+	}`)},
+}
+
+func newPolicy(t *testing.T, policyJSON string) *v1.ClusterPolicy {
+	policy := &v1.ClusterPolicy{}
+	assert.NilError(t, json.Unmarshal([]byte(policyJSON), policy))
+	return policy
+}
+
+func Test_ReplayRequest(t *testing.T) {
+	requireTeam := `{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "require-team"},
+		"spec": {
+			"validationFailureAction": "%s",
+			"rules": [{
+				"name": "check-team",
+				"match": {"resources": {"kinds": ["Pod"]}},
+				"validate": {"message": "the team label is required", "pattern": {"metadata": {"labels": {"team": "?*"}}}}
+			}]
+		}
+	}`
+
+	addTeam := newPolicy(t, `{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "add-team"},
+		"spec": {
+			"rules": [{
+				"name": "add-team",
+				"match": {"resources": {"kinds": ["Pod"]}},
+				"mutate": {"patchStrategicMerge": {"metadata": {"labels": {"+(team)": "platform"}}}}
+			}]
+		}
+	}`)
+
+	enforce := newPolicy(t, fmt.Sprintf(requireTeam, "enforce"))
+	audit := newPolicy(t, fmt.Sprintf(requireTeam, "audit"))
+
+	result := replayRequest(podRequest, []*v1.ClusterPolicy{enforce})
+	assert.NilError(t, result.err)
+	assert.Equal(t, result.request, "CREATE Pod default/nginx")
+	assert.Equal(t, len(result.denials), 1)
+	assert.Equal(t, len(result.warnings), 0)
+
+	result = replayRequest(podRequest, []*v1.ClusterPolicy{audit})
+	assert.NilError(t, result.err)
+	assert.Equal(t, len(result.denials), 0)
+	assert.Equal(t, len(result.warnings), 1)
+
+	// the mutated resource is validated
+	result = replayRequest(podRequest, []*v1.ClusterPolicy{enforce, addTeam})
+	assert.NilError(t, result.err)
+	assert.Equal(t, len(result.denials), 0)
+	assert.Equal(t, len(result.warnings), 0)
+
+	deleteRequest := podRequest.DeepCopy()
+	deleteRequest.Operation = v1beta1.Delete
+	result = replayRequest(deleteRequest, []*v1.ClusterPolicy{enforce})
+	assert.Assert(t, result.skipped)
+}
@@ -0,0 +1,229 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/kyverno/common"
+	sanitizederror "github.com/kyverno/kyverno/pkg/kyverno/sanitizedError"
+	"github.com/kyverno/kyverno/pkg/openapi"
+	policy2 "github.com/kyverno/kyverno/pkg/policy"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var scanHelp = `
+To scan the resources of the cluster with the policies of the cluster:
+	kyverno scan
+
+To scan the resources of a namespace with local policies:
+	kyverno scan /path/to/policy.yaml /path/to/folderOfPolicies --namespace=<namespace>
+
+To export the results as JSON, e.g. to archive an audit:
+	kyverno scan --output-format=json > results.json
+
+To fail a pipeline on the failures of the policies annotated with policies.kyverno.io/scored: "false":
+	kyverno scan --warn-exit-code=4
+
+The policies are applied as the background scan applies them, the policies with background
+processing disabled are skipped since they rely on the information of the admission requests. The
+rules which reference the admission requests, e.g. request.userInfo or request.oldObject, are
+skipped and reported with the skip status.
+
+The command exits with 0 when all the rules passed, 1 when a validation rule failed and 3 when the
+flags or the policies are invalid. The failures of the policies which are not scored are warnings,
+they exit with the code of the warn-exit-code flag.
+`
+
+// resultCounts counts the results of the validation rules, the failures of the policies which are
+// not scored are warnings and the rules skipped in background mode are skipped
+type resultCounts struct {
+	pass int
+	fail int
+	warn int
+	skip int
+}
+
+func (rc *resultCounts) add(rule response.RuleResponse, scored bool) {
+	switch {
+	case rule.Skipped:
+		rc.skip++
+	case rule.Success:
+		rc.pass++
+	case scored:
+		rc.fail++
+	default:
+		rc.warn++
+	}
+}
+
+// Command returns scan command
+func Command() *cobra.Command {
+	var namespace, outputFormat string
+	var warnExitCode int
+	cmd := &cobra.Command{
+		Use:     "scan",
+		Short:   "scans the resources of a cluster with policies",
+		Example: scanHelp,
+		RunE: func(cmd *cobra.Command, policyPaths []string) (err error) {
+			defer func() {
+				if err != nil {
+					if !sanitizederror.IsErrorSanitized(err) && !common.IsExitError(err) {
+						log.Log.Error(err, "failed to sanitize")
+						err = fmt.Errorf("internal error")
+					}
+				}
+			}()
+
+			if err := common.ValidateOutputFormat(outputFormat); err != nil {
+				return sanitizederror.NewWithError("invalid output format", err)
+			}
+
+			engineResponses, rc, err := scanCluster(policyPaths, namespace)
+			if err != nil {
+				return err
+			}
+
+			if err := common.PrintResults(os.Stdout, outputFormat, engineResponses, nil); err != nil {
+				return sanitizederror.NewWithError("failed to print the results", err)
+			}
+
+			if !common.IsMachineReadable(outputFormat) {
+				fmt.Printf("\npass: %d, fail: %d, warn: %d, skip: %d\n", rc.pass, rc.fail, rc.warn, rc.skip)
+			}
+
+			return common.ResultsError(cmd, common.ExitCode(rc.fail, rc.warn, 0, warnExitCode))
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace of the resources and the policies of the cluster to scan, all namespaces by default")
+	cmd.Flags().StringVarP(&outputFormat, "output-format", "", common.OutputTable, "Prints the results as a table, or in json, yaml or sarif format")
+	cmd.Flags().IntVarP(&warnExitCode, "warn-exit-code", "", common.ExitPassed, "Exit code when the only failed rules are the rules of the policies which are not scored")
+	return cmd
+}
+
+// scanCluster applies the policies, or all the policies of the cluster, on the resources of the cluster
+func scanCluster(policyPaths []string, namespace string) ([]*response.EngineResponse, *resultCounts, error) {
+	restConfig, err := common.RESTConfig()
+	if err != nil {
+		return nil, nil, sanitizederror.NewWithError("failed to load the kubeconfig", err)
+	}
+
+	dClient, err := client.NewClient(restConfig, 15*time.Minute, make(chan struct{}), log.Log)
+	if err != nil {
+		return nil, nil, sanitizederror.NewWithError("failed to create the client", err)
+	}
+
+	var policies []*v1.ClusterPolicy
+	if len(policyPaths) == 0 {
+		policies, err = common.GetPoliciesFromCluster(dClient, namespace)
+	} else {
+		policies, err = common.GetPoliciesFromPaths(memfs.New(), policyPaths, false, "")
+	}
+	if err != nil {
+		return nil, nil, sanitizederror.NewWithError("failed to load policies", err)
+	}
+
+	mutatedPolicies, err := common.MutatePolices(policies)
+	if err != nil {
+		if !sanitizederror.IsErrorSanitized(err) {
+			return nil, nil, sanitizederror.NewWithError("failed to mutate policy", err)
+		}
+		return nil, nil, err
+	}
+
+	openAPIController, err := openapi.NewOpenAPIController()
+	if err != nil {
+		return nil, nil, sanitizederror.NewWithError("failed to initialize openAPIController", err)
+	}
+
+	var scanPolicies []*v1.ClusterPolicy
+	for _, policy := range mutatedPolicies {
+		if err := policy2.Validate(policy, nil, true, openAPIController); err != nil {
+			fmt.Fprintf(os.Stderr, "skipping policy %s as it is not valid: %v\n", policy.Name, err)
+			continue
+		}
+
+		if !policy.BackgroundProcessingEnabled() {
+			log.Log.V(3).Info("skipping policy with background processing disabled", "policy", policy.Name)
+			continue
+		}
+
+		scanPolicies = append(scanPolicies, policy)
+	}
+
+	resources, err := common.GetResources(scanPolicies, nil, dClient, true, namespace, true)
+	if err != nil {
+		return nil, nil, sanitizederror.NewWithError("failed to list the resources", err)
+	}
+
+	namespaces := newNamespaceCache(dClient)
+	rc := &resultCounts{}
+	var engineResponses []*response.EngineResponse
+	for _, policy := range scanPolicies {
+		for _, resource := range resources {
+			// the pods and the jobs of the controllers are checked through their controllers
+			if engine.ManagedPodResource(*policy, *resource) {
+				continue
+			}
+
+			labels, annotations := namespaces.metadata(resource.GetNamespace())
+			ers := policy2.ApplyPolicy(*policy, *resource, log.Log, dClient, labels, annotations)
+			for _, er := range ers {
+				for _, rule := range er.PolicyResponse.Rules {
+					if rule.Type == utils.Validation.String() {
+						rc.add(rule, policy.IsScored())
+					}
+				}
+			}
+			engineResponses = append(engineResponses, ers...)
+		}
+	}
+
+	return engineResponses, rc, nil
+}
+
+// namespaceCache fetches the labels and the annotations of the namespaces once
+type namespaceCache struct {
+	client     *client.Client
+	namespaces map[string]*unstructured.Unstructured
+}
+
+func newNamespaceCache(client *client.Client) *namespaceCache {
+	return &namespaceCache{
+		client:     client,
+		namespaces: make(map[string]*unstructured.Unstructured),
+	}
+}
+
+// metadata returns the labels and the annotations of the namespace, or nil for the cluster
+// wide resources and the namespaces which cannot be fetched
+func (c *namespaceCache) metadata(namespace string) (map[string]string, map[string]string) {
+	if namespace == "" {
+		return nil, nil
+	}
+
+	ns, ok := c.namespaces[namespace]
+	if !ok {
+		var err error
+		ns, err = c.client.GetResource("", "Namespace", "", namespace)
+		if err != nil {
+			log.Log.V(3).Info("failed to get namespace", "namespace", namespace, "error", err.Error())
+			ns = nil
+		}
+		c.namespaces[namespace] = ns
+	}
+
+	if ns == nil {
+		return nil, nil
+	}
+	return ns.GetLabels(), ns.GetAnnotations()
+}
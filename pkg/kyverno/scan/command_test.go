@@ -0,0 +1,42 @@
+package scan
+
+import (
+	"testing"
+
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/policyreport"
+	"gotest.tools/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_ResultCounts_Skipped(t *testing.T) {
+	er := &response.EngineResponse{
+		PolicyResponse: response.PolicyResponse{
+			Policy:   "require-owner",
+			Resource: response.ResourceSpec{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "nginx"},
+			Rules: []response.RuleResponse{
+				{Name: "check-team", Type: "Validation", Success: true},
+				{Name: "check-owner", Type: "Validation", Success: true, Skipped: true},
+				{Name: "check-app", Type: "Validation"},
+				{Name: "add-team", Type: "Mutation", Success: true},
+			},
+		},
+	}
+
+	rc := &resultCounts{}
+	for _, rule := range er.PolicyResponse.Rules {
+		if rule.Type == "Validation" {
+			rc.add(rule, true)
+		}
+	}
+	assert.Equal(t, *rc, resultCounts{pass: 1, fail: 1, skip: 1})
+
+	// the skipped rule is reported with the skip status
+	results := policyreport.BuildResults([]*response.EngineResponse{er}, log.Log)
+	statuses := make(map[string]report.PolicyStatus)
+	for _, result := range results {
+		statuses[result.Rule] = result.Status
+	}
+	assert.DeepEqual(t, statuses, map[string]report.PolicyStatus{"check-team": report.StatusPass, "check-owner": report.StatusSkip, "check-app": report.StatusFail})
+}
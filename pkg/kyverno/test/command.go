@@ -4,10 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/url"
 	"os"
 	"path/filepath"
-	"reflect"
 	"sort"
 	"strings"
 
@@ -32,13 +30,31 @@ import (
 	log "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+var testHelp = `
+To run the tests of a directory, the test files are searched recursively:
+	kyverno test /path/to/folderOfTests
+
+To run the tests of a git repository, at a branch, a tag or a commit:
+	kyverno test git::https://github.com/<owner>/<repository>//<directory>?ref=<ref>
+	kyverno test https://github.com/<owner>/<repository>/<branch>
+
+The policies and the resources of the test files are paths relative to the test file, the test files
+of a local directory may also reference URLs and git references.
+
+To validate the resources and the patched resources of the tests against the OpenAPI schemas of Kubernetes:
+	kyverno test /path/to/folderOfTests --validate-schemas
+	kyverno test /path/to/folderOfTests --kubernetes-version=v1.20.0
+`
+
 // Command returns version command
 func Command() *cobra.Command {
 	var cmd *cobra.Command
-	var valuesFile, fileName string
+	var valuesFile, fileName, kubernetesVersion string
+	var validateSchemas bool
 	cmd = &cobra.Command{
-		Use:   "test",
-		Short: "run tests from directory",
+		Use:     "test",
+		Short:   "run tests from directory",
+		Example: testHelp,
 		RunE: func(cmd *cobra.Command, dirPath []string) (err error) {
 			defer func() {
 				if err != nil {
@@ -48,7 +64,14 @@ func Command() *cobra.Command {
 					}
 				}
 			}()
-			_, err = testCommandExecute(dirPath, valuesFile, fileName)
+			var schemaController *openapi.Controller
+			if validateSchemas || kubernetesVersion != "" {
+				schemaController, err = common.LoadSchemas(kubernetesVersion)
+				if err != nil {
+					return sanitizederror.NewWithError("failed to load the schemas", err)
+				}
+			}
+			_, err = testCommandExecute(dirPath, valuesFile, fileName, schemaController)
 			if err != nil {
 				log.Log.V(3).Info("a directory is required")
 				return err
@@ -57,6 +80,8 @@ func Command() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&fileName, "file-name", "f", "test.yaml", "test filename")
+	cmd.Flags().BoolVarP(&validateSchemas, "validate-schemas", "", false, "Validates the resources and the patched resources against the OpenAPI schemas of Kubernetes, the bundled schemas by default")
+	cmd.Flags().StringVarP(&kubernetesVersion, "kubernetes-version", "", "", "Kubernetes version, e.g. v1.20.0, whose OpenAPI schemas validate the resources, the schemas are downloaded once and cached")
 	return cmd
 }
 
@@ -79,6 +104,9 @@ type TestResults struct {
 	Rule     string `json:"rule"`
 	Status   string `json:"status"`
 	Resource string `json:"resource"`
+
+	// PatchedResource is the path of the file of the resource expected after the mutation
+	PatchedResource string `json:"patchedResource,omitempty"`
 }
 
 type ReportResult struct {
@@ -87,8 +115,8 @@ type ReportResult struct {
 }
 
 type Resource struct {
-	Name   string            `json:"name"`
-	Values map[string]string `json:"values"`
+	Name   string                 `json:"name"`
+	Values map[string]interface{} `json:"values"`
 }
 
 type Table struct {
@@ -110,33 +138,24 @@ type resultCounts struct {
 	fail int
 }
 
-func testCommandExecute(dirPath []string, valuesFile string, fileName string) (rc *resultCounts, err error) {
+func testCommandExecute(dirPath []string, valuesFile string, fileName string, schemaController *openapi.Controller) (rc *resultCounts, err error) {
 	var errors []error
 	fs := memfs.New()
 	rc = &resultCounts{}
 	if len(dirPath) == 0 {
 		return rc, sanitizederror.NewWithError(fmt.Sprintf("a directory is required"), err)
 	}
-	if strings.Contains(string(dirPath[0]), "https://") {
-		gitURL, err := url.Parse(dirPath[0])
+	if common.IsGitReference(dirPath[0]) || strings.Contains(string(dirPath[0]), "https://") {
+		reference, err := gitReference(dirPath[0])
 		if err != nil {
-			return rc, sanitizederror.NewWithError("failed to parse URL", err)
-		}
-		pathElems := strings.Split(gitURL.Path[1:], "/")
-		if len(pathElems) != 3 {
-			err := fmt.Errorf("invalid URL path %s - expected https://github.com/:owner/:repository/:branch", gitURL.Path)
 			fmt.Printf("Error: failed to parse URL \nCause: %s\n", err)
-			os.Exit(1)
+			os.Exit(common.ExitInvalidInput)
 		}
-		gitURL.Path = strings.Join([]string{"/", pathElems[0], pathElems[1]}, "/")
-		repoURL := gitURL.String()
-		cloneRepo, err := clone(repoURL, fs)
-		if err != nil {
+		if err := common.CloneGitReference(reference, fs); err != nil {
 			fmt.Printf("Error: failed to clone repository \nCause: %s\n", err)
-			os.Exit(1)
+			os.Exit(common.ExitInvalidInput)
 		}
-		log.Log.V(3).Info(" clone repository", cloneRepo)
-		policyYamls, err := listYAMLs(fs, "/")
+		policyYamls, err := common.ListYAMLs(fs, reference.Path)
 		if err != nil {
 			return rc, sanitizederror.NewWithError("failed to list YAMLs in repository", err)
 		}
@@ -155,7 +174,7 @@ func testCommandExecute(dirPath []string, valuesFile string, fileName string) (r
 					sanitizederror.NewWithError("failed to convert to JSON", err)
 					continue
 				}
-				if err := applyPoliciesFromPath(fs, policyBytes, valuesFile, true, policyresoucePath, rc); err != nil {
+				if err := applyPoliciesFromPath(fs, policyBytes, valuesFile, true, policyresoucePath, rc, schemaController); err != nil {
 					return rc, sanitizederror.NewWithError("failed to apply test command", err)
 				}
 			}
@@ -169,7 +188,7 @@ func testCommandExecute(dirPath []string, valuesFile string, fileName string) (r
 		if err != nil {
 			errors = append(errors, err)
 		}
-		err := getLocalDirTestFiles(fs, path, fileName, valuesFile, rc)
+		err := getLocalDirTestFiles(fs, path, fileName, valuesFile, rc, schemaController)
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -181,20 +200,20 @@ func testCommandExecute(dirPath []string, valuesFile string, fileName string) (r
 		}
 	}
 	if rc.fail > 0 {
-		os.Exit(1)
+		os.Exit(common.ExitViolations)
 	}
-	os.Exit(0)
+	os.Exit(common.ExitPassed)
 	return rc, nil
 }
 
-func getLocalDirTestFiles(fs billy.Filesystem, path, fileName, valuesFile string, rc *resultCounts) error {
+func getLocalDirTestFiles(fs billy.Filesystem, path, fileName, valuesFile string, rc *resultCounts, schemaController *openapi.Controller) error {
 	files, err := ioutil.ReadDir(path)
 	if err != nil {
 		return fmt.Errorf("failed to read %v: %v", path, err.Error())
 	}
 	for _, file := range files {
 		if file.IsDir() {
-			getLocalDirTestFiles(fs, filepath.Join(path, file.Name()), fileName, valuesFile, rc)
+			getLocalDirTestFiles(fs, filepath.Join(path, file.Name()), fileName, valuesFile, rc, schemaController)
 			continue
 		}
 		if strings.Contains(file.Name(), fileName) {
@@ -208,7 +227,7 @@ func getLocalDirTestFiles(fs billy.Filesystem, path, fileName, valuesFile string
 				sanitizederror.NewWithError("failed to convert json", err)
 				continue
 			}
-			if err := applyPoliciesFromPath(fs, valuesBytes, valuesFile, false, path, rc); err != nil {
+			if err := applyPoliciesFromPath(fs, valuesBytes, valuesFile, false, path, rc, schemaController); err != nil {
 				sanitizederror.NewWithError("failed to apply test command", err)
 				continue
 			}
@@ -223,7 +242,7 @@ func buildPolicyResults(resps []*response.EngineResponse) map[string][]interface
 	for _, info := range infos {
 		for _, infoResult := range info.Results {
 			for _, rule := range infoResult.Rules {
-				if rule.Type != utils.Validation.String() {
+				if rule.Type != utils.Validation.String() && rule.Type != utils.Mutation.String() {
 					continue
 				}
 				result := report.PolicyReportResult{
@@ -247,6 +266,11 @@ func getPolicyResouceFullPath(path []string, policyresoucePath string, isGit boo
 	var pol []string
 	if !isGit {
 		for _, p := range path {
+			// the URLs and the git references are not relative to the directory of the test
+			if common.IsGitReference(p) || strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://") {
+				pol = append(pol, p)
+				continue
+			}
 			pol = append(pol, filepath.Join(policyresoucePath, p))
 		}
 		return pol
@@ -254,7 +278,7 @@ func getPolicyResouceFullPath(path []string, policyresoucePath string, isGit boo
 	return path
 }
 
-func applyPoliciesFromPath(fs billy.Filesystem, policyBytes []byte, valuesFile string, isGit bool, policyresoucePath string, rc *resultCounts) (err error) {
+func applyPoliciesFromPath(fs billy.Filesystem, policyBytes []byte, valuesFile string, isGit bool, policyresoucePath string, rc *resultCounts, schemaController *openapi.Controller) (err error) {
 	openAPIController, err := openapi.NewOpenAPIController()
 	engineResponses := make([]*response.EngineResponse, 0)
 	validateEngineResponses := make([]*response.EngineResponse, 0)
@@ -269,7 +293,7 @@ func applyPoliciesFromPath(fs billy.Filesystem, policyBytes []byte, valuesFile s
 
 	fmt.Printf("\nExecuting %s...", values.Name)
 
-	_, valuesMap, err := common.GetVariable(variablesString, values.Variables, fs, isGit, policyresoucePath)
+	_, globalValues, valuesMap, err := common.GetVariable(variablesString, values.Variables, fs, isGit, policyresoucePath)
 	if err != nil {
 		if !sanitizederror.IsErrorSanitized(err) {
 			return sanitizederror.NewWithError("failed to decode yaml", err)
@@ -283,7 +307,7 @@ func applyPoliciesFromPath(fs billy.Filesystem, policyBytes []byte, valuesFile s
 	policies, err := common.GetPoliciesFromPaths(fs, fullPolicyPath, isGit, policyresoucePath)
 	if err != nil {
 		fmt.Printf("Error: failed to load policies\nCause: %s\n", err)
-		os.Exit(1)
+		os.Exit(common.ExitInvalidInput)
 	}
 	mutatedPolicies, err := common.MutatePolices(policies)
 	if err != nil {
@@ -294,7 +318,19 @@ func applyPoliciesFromPath(fs billy.Filesystem, policyBytes []byte, valuesFile s
 	resources, err := common.GetResourceAccordingToResourcePath(fs, fullResourcePath, false, mutatedPolicies, dClient, "", false, isGit, policyresoucePath)
 	if err != nil {
 		fmt.Printf("Error: failed to load resources\nCause: %s\n", err)
-		os.Exit(1)
+		os.Exit(common.ExitInvalidInput)
+	}
+	if schemaController != nil {
+		invalid := false
+		for _, resource := range resources {
+			if err := common.ValidateResourceSchema(schemaController, resource); err != nil {
+				fmt.Printf("Error: resource %s/%s/%s is not valid\nCause: %s\n", resource.GetNamespace(), resource.GetKind(), resource.GetName(), err)
+				invalid = true
+			}
+		}
+		if invalid {
+			os.Exit(common.ExitInvalidInput)
+		}
 	}
 	msgPolicies := "1 policy"
 	if len(mutatedPolicies) > 1 {
@@ -326,33 +362,48 @@ func applyPoliciesFromPath(fs billy.Filesystem, policyBytes []byte, valuesFile s
 			continue
 		}
 		for _, resource := range resources {
-			thisPolicyResourceValues := make(map[string]string)
-			if len(valuesMap[policy.GetName()]) != 0 && !reflect.DeepEqual(valuesMap[policy.GetName()][resource.GetName()], Resource{}) {
-				thisPolicyResourceValues = valuesMap[policy.GetName()][resource.GetName()].Values
-			}
+			resourceValues := valuesMap[policy.GetName()][resource.GetName()].Values
+			thisPolicyResourceValues := common.MergeVariables(globalValues, resourceValues, nil)
 			if len(common.PolicyHasVariables(*policy)) > 0 && len(thisPolicyResourceValues) == 0 {
 				return sanitizederror.NewWithError(fmt.Sprintf("policy %s have variables. pass the values for the variables using set/values_file flag", policy.Name), err)
 			}
 
-			ers, validateErs, _, _, err := common.ApplyPolicyOnResource(policy, resource, "", false, thisPolicyResourceValues, true)
+			ers, validateErs, _, _, err := common.ApplyPolicyOnResource(os.Stdout, policy, resource, "", false, common.DiffUnified, thisPolicyResourceValues, true)
 			if err != nil {
 				return sanitizederror.NewWithError(fmt.Errorf("failed to apply policy %v on resource %v", policy.Name, resource.GetName()).Error(), err)
 			}
+			if schemaController != nil && len(ers) > 0 {
+				if err := common.ValidateMutatedResourceSchema(schemaController, ers[0]); err != nil {
+					fmt.Printf("\npolicy %s -> mutated resource %s is not valid: %s\n", policy.Name, resource.GetName(), err)
+					rc.fail++
+				}
+			}
 			engineResponses = append(engineResponses, ers...)
 			validateEngineResponses = append(validateEngineResponses, validateErs)
 		}
 	}
-	resultsMap := buildPolicyResults(validateEngineResponses)
-	resultErr := printTestResult(resultsMap, values.Results, rc)
+	resultsMap := buildPolicyResults(append(engineResponses, validateEngineResponses...))
+	patchDiffs, err := comparePatchedResources(fs, values.Results, buildPatchedResources(engineResponses), isGit, policyresoucePath)
+	if err != nil {
+		return sanitizederror.NewWithError("failed to compare the patched resources", err)
+	}
+
+	skipped := make(map[string]bool)
+	for _, policy := range skippedPolicies {
+		skipped[policy.Name] = true
+	}
+
+	resultErr := printTestResult(resultsMap, values.Results, skipped, patchDiffs, rc)
 	if resultErr != nil {
 		return sanitizederror.NewWithError("Unable to genrate result. Error:", resultErr)
 	}
 	return
 }
 
-func printTestResult(resps map[string][]interface{}, testResults []TestResults, rc *resultCounts) error {
+func printTestResult(resps map[string][]interface{}, testResults []TestResults, skipped map[string]bool, patchDiffs map[int][]string, rc *resultCounts) error {
 	printer := tableprinter.New(os.Stdout)
 	table := []*Table{}
+	var failures []string
 	boldRed := color.New(color.FgRed).Add(color.Bold)
 	boldFgCyan := color.New(color.FgCyan).Add(color.Bold)
 	for i, v := range testResults {
@@ -367,22 +418,27 @@ func printTestResult(resps map[string][]interface{}, testResults []TestResults,
 		}
 		var r []ReportResult
 		json.Unmarshal(valuesBytes, &r)
-		res.Result = boldRed.Sprintf("Fail")
-		if len(r) != 0 {
-			var resource TestResults
-			for _, testRes := range r {
-				if testRes.Resources[0].Name == v.Resource {
-					resource.Policy = testRes.Policy
-					resource.Rule = testRes.Rule
-					resource.Status = testRes.Status
-					resource.Resource = testRes.Resources[0].Name
-					if v == resource {
-						res.Result = "Pass"
-						rc.pass++
-					} else {
-						rc.fail++
-					}
-				}
+
+		status := actualStatus(r, v)
+		if skipped[v.Policy] {
+			status = report.StatusSkip
+		}
+
+		var diffs []string
+		if v.Status != "" && status != v.Status {
+			diffs = append(diffs, fmt.Sprintf("expected status %s, got %s", v.Status, status))
+		}
+		diffs = append(diffs, patchDiffs[i]...)
+
+		if len(diffs) == 0 {
+			res.Result = "Pass"
+			rc.pass++
+		} else {
+			res.Result = boldRed.Sprintf("Fail")
+			rc.fail++
+			failures = append(failures, fmt.Sprintf("%d. %s with %s/%s:", res.ID, v.Resource, v.Policy, v.Rule))
+			for _, diff := range diffs {
+				failures = append(failures, "    "+diff)
 			}
 		}
 		table = append(table, res)
@@ -398,5 +454,12 @@ func printTestResult(resps map[string][]interface{}, testResults []TestResults,
 	printer.HeaderBgColor = tablewriter.BgBlackColor
 	printer.HeaderFgColor = tablewriter.FgGreenColor
 	printer.Print(table)
+
+	if len(failures) > 0 {
+		fmt.Printf("\nFailed tests:\n")
+		for _, failure := range failures {
+			fmt.Println(failure)
+		}
+	}
 	return nil
 }
@@ -1,46 +1,28 @@
 package test
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
+	"net/url"
+	"strings"
 
-	"github.com/go-git/go-billy/v5"
-	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/kyverno/kyverno/pkg/kyverno/common"
 )
 
-func clone(path string, fs billy.Filesystem) (*git.Repository, error) {
-	return git.Clone(memory.NewStorage(), fs, &git.CloneOptions{
-		URL:      path,
-		Progress: os.Stdout,
-	})
-}
+// gitReference returns the git reference of the tests, a git reference of the form
+// git::<repository URL>[//<path>][?ref=<ref>] or a URL of the form https://github.com/:owner/:repository/:branch
+func gitReference(path string) (*common.GitReference, error) {
+	if common.IsGitReference(path) {
+		return common.ParseGitReference(path)
+	}
 
-func listYAMLs(fs billy.Filesystem, path string) ([]string, error) {
-	path = filepath.Clean(path)
-	fis, err := fs.ReadDir(path)
+	gitURL, err := url.Parse(path)
 	if err != nil {
 		return nil, err
 	}
-	yamls := make([]string, 0)
-	for _, fi := range fis {
-		name := filepath.Join(path, fi.Name())
-		if fi.IsDir() {
-			moreYAMLs, err := listYAMLs(fs, name)
-			if err != nil {
-				return nil, err
-			}
-
-			yamls = append(yamls, moreYAMLs...)
-			continue
-		}
-
-		ext := filepath.Ext(name)
-		if ext != ".yml" && ext != ".yaml" {
-			continue
-		}
-
-		yamls = append(yamls, name)
+	pathElems := strings.Split(gitURL.Path[1:], "/")
+	if len(pathElems) != 3 {
+		return nil, fmt.Errorf("invalid URL path %s - expected https://github.com/:owner/:repository/:branch", gitURL.Path)
 	}
-	return yamls, nil
+	gitURL.Path = strings.Join([]string{"", pathElems[0], pathElems[1]}, "/")
+	return &common.GitReference{URL: gitURL.String(), Path: "/", Ref: pathElems[2]}, nil
 }
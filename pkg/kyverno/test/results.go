@@ -0,0 +1,134 @@
+package test
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/go-git/go-billy/v5"
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/kyverno/common"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// patchedResourceKey returns the key of the resource patched by the mutation rules of the policy
+func patchedResourceKey(policy, resource string) string {
+	return policy + "/" + resource
+}
+
+// buildPatchedResources returns the resources patched by the mutation rules of each policy
+func buildPatchedResources(resps []*response.EngineResponse) map[string]unstructured.Unstructured {
+	patchedResources := make(map[string]unstructured.Unstructured)
+	for _, resp := range resps {
+		for _, rule := range resp.PolicyResponse.Rules {
+			if rule.Type == utils.Mutation.String() {
+				key := patchedResourceKey(resp.PolicyResponse.Policy, resp.PolicyResponse.Resource.Name)
+				patchedResources[key] = resp.PatchedResource
+				break
+			}
+		}
+	}
+
+	return patchedResources
+}
+
+// comparePatchedResources compares the expected patched resources of the test results to the
+// resources patched by the policies, the differences are returned by index of the test result
+func comparePatchedResources(fs billy.Filesystem, testResults []TestResults, patchedResources map[string]unstructured.Unstructured, isGit bool, policyresoucePath string) (map[int][]string, error) {
+	diffs := make(map[int][]string)
+	for i, v := range testResults {
+		if v.PatchedResource == "" {
+			continue
+		}
+
+		paths := getPolicyResouceFullPath([]string{v.PatchedResource}, policyresoucePath, isGit)
+		expected, err := common.GetResourcesWithTest(fs, nil, paths, isGit, policyresoucePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load patched resource %s: %v", v.PatchedResource, err)
+		}
+
+		if len(expected) != 1 {
+			return nil, fmt.Errorf("patched resource %s must contain exactly one resource", v.PatchedResource)
+		}
+
+		actual, ok := patchedResources[patchedResourceKey(v.Policy, v.Resource)]
+		if !ok {
+			diffs[i] = []string{fmt.Sprintf("resource %s is not patched by policy %s", v.Resource, v.Policy)}
+			continue
+		}
+
+		if diff := resourceDiff(expected[0].Object, actual.Object, ""); len(diff) > 0 {
+			diffs[i] = diff
+		}
+	}
+
+	return diffs, nil
+}
+
+// resourceDiff returns the paths of the values which differ between the expected and the actual resource
+func resourceDiff(expected, actual interface{}, path string) []string {
+	expectedMap, expectedIsMap := expected.(map[string]interface{})
+	actualMap, actualIsMap := actual.(map[string]interface{})
+	if expectedIsMap && actualIsMap {
+		keys := make(map[string]bool)
+		for key := range expectedMap {
+			keys[key] = true
+		}
+		for key := range actualMap {
+			keys[key] = true
+		}
+
+		sortedKeys := make([]string, 0, len(keys))
+		for key := range keys {
+			sortedKeys = append(sortedKeys, key)
+		}
+		sort.Strings(sortedKeys)
+
+		var diffs []string
+		for _, key := range sortedKeys {
+			diffs = append(diffs, resourceDiff(expectedMap[key], actualMap[key], path+"/"+key)...)
+		}
+		return diffs
+	}
+
+	expectedList, expectedIsList := expected.([]interface{})
+	actualList, actualIsList := actual.([]interface{})
+	if expectedIsList && actualIsList && len(expectedList) == len(actualList) {
+		var diffs []string
+		for i := range expectedList {
+			diffs = append(diffs, resourceDiff(expectedList[i], actualList[i], fmt.Sprintf("%s/%d", path, i))...)
+		}
+		return diffs
+	}
+
+	if reflect.DeepEqual(expected, actual) {
+		return nil
+	}
+
+	switch {
+	case expected == nil:
+		return []string{fmt.Sprintf("%s: unexpected %v", path, actual)}
+	case actual == nil:
+		return []string{fmt.Sprintf("%s: expected %v, missing", path, expected)}
+	default:
+		return []string{fmt.Sprintf("%s: expected %v, got %v", path, expected, actual)}
+	}
+}
+
+// actualStatus returns the status of the rule applied on the resource of the test result, the
+// status is skip when the rule is not applied on the resource
+func actualStatus(results []ReportResult, v TestResults) string {
+	for _, result := range results {
+		if len(result.Resources) == 0 {
+			continue
+		}
+
+		if result.Policy == v.Policy && result.Rule == v.Rule && result.Resources[0].Name == v.Resource {
+			return result.Status
+		}
+	}
+
+	return report.StatusSkip
+}
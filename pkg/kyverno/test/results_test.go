@@ -0,0 +1,61 @@
+package test
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_ResourceDiff(t *testing.T) {
+	expected := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":   "nginx",
+			"labels": map[string]interface{}{"team": "dev"},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "nginx", "image": "nginx:1.19"},
+			},
+		},
+	}
+
+	actual := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        "nginx",
+			"annotations": map[string]interface{}{"owner": "ops"},
+		},
+		"spec": map[string]interface{}{
+			"containers": []interface{}{
+				map[string]interface{}{"name": "nginx", "image": "nginx:latest"},
+			},
+		},
+	}
+
+	assert.DeepEqual(t, resourceDiff(expected, actual, ""), []string{
+		"/metadata/annotations: unexpected map[owner:ops]",
+		"/metadata/labels: expected map[team:dev], missing",
+		"/spec/containers/0/image: expected nginx:1.19, got nginx:latest",
+	})
+
+	assert.Equal(t, len(resourceDiff(expected, expected, "")), 0)
+}
+
+func Test_ActualStatus(t *testing.T) {
+	result := TestResults{Policy: "add-label", Rule: "add-team", Resource: "nginx", Status: "pass"}
+
+	var results []ReportResult
+	assert.Equal(t, actualStatus(results, result), "skip")
+
+	results = append(results, ReportResult{
+		TestResults: TestResults{Policy: "add-label", Rule: "add-team", Status: "fail"},
+		Resources:   []*corev1.ObjectReference{{Name: "busybox"}},
+	})
+	assert.Equal(t, actualStatus(results, result), "skip")
+
+	results = append(results, ReportResult{
+		TestResults: TestResults{Policy: "add-label", Rule: "add-team", Status: "fail"},
+		Resources:   []*corev1.ObjectReference{{Name: "nginx"}},
+	})
+	assert.Equal(t, actualStatus(results, result), "fail")
+}
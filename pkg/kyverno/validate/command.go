@@ -22,6 +22,7 @@ import (
 func Command() *cobra.Command {
 	var outputType string
 	var crdPaths []string
+	var strict bool
 	cmd := &cobra.Command{
 		Use:     "validate",
 		Short:   "Validates kyverno policies",
@@ -86,7 +87,7 @@ func Command() *cobra.Command {
 				crds, err := common.GetCRDs(crdPaths)
 				if err != nil {
 					fmt.Printf("\nError: crd is invalid. \nFile: %s \nCause: %s\n", crdPaths, err)
-					os.Exit(1)
+					os.Exit(common.ExitInvalidInput)
 				}
 				for _, crd := range crds {
 					openAPIController.ParseCRD(*crd)
@@ -94,6 +95,7 @@ func Command() *cobra.Command {
 			}
 
 			invalidPolicyFound := false
+			warningFound := false
 			for _, policy := range policies {
 				fmt.Println("----------------------------------------------------------------------")
 				err := policy2.Validate(policy, nil, true, openAPIController)
@@ -103,6 +105,15 @@ func Command() *cobra.Command {
 					invalidPolicyFound = true
 				} else {
 					fmt.Printf("Policy %s is valid.\n\n", policy.Name)
+					warnings := policy2.Lint(*policy)
+					for _, warning := range warnings {
+						fmt.Printf("Warning: %s\n", warning)
+					}
+					if len(warnings) > 0 {
+						fmt.Println()
+						warningFound = true
+					}
+
 					if outputType != "" {
 						logger := log.Log.WithName("validate")
 						p, err := common.MutatePolicy(policy, logger)
@@ -123,13 +134,14 @@ func Command() *cobra.Command {
 				}
 			}
 
-			if invalidPolicyFound == true {
-				os.Exit(1)
+			if invalidPolicyFound == true || (strict && warningFound) {
+				os.Exit(common.ExitViolations)
 			}
 			return nil
 		},
 	}
 	cmd.Flags().StringVarP(&outputType, "output", "o", "", "Prints the mutated policy in yaml or json format")
 	cmd.Flags().StringArrayVarP(&crdPaths, "crd", "c", []string{}, "Path to CRD files")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fails if a policy has lint warnings, e.g. an undefined variable")
 	return cmd
 }
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Compliance exports the percentage of the resources evaluated by the policy reports
+// which passed, by policy and cluster-wide
+type Compliance struct {
+	policyGauge  *prometheus.GaugeVec
+	clusterGauge prometheus.Gauge
+
+	mu sync.Mutex
+	// series are the label sets set for each policy, to delete the series of the
+	// deleted policies
+	series map[string]prometheus.Labels
+}
+
+// NewCompliance registers the compliance gauges
+func NewCompliance(registerer prometheus.Registerer) *Compliance {
+	c := &Compliance{
+		policyGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "policy_compliance_percentage",
+			Help:      "Percentage of the resources evaluated by the policy which passed, by policy and namespace.",
+		}, []string{"policy", "namespace"}),
+		clusterGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cluster_compliance_percentage",
+			Help:      "Percentage of the resources evaluated by any policy which passed all the policies.",
+		}),
+		series: map[string]prometheus.Labels{},
+	}
+
+	registerer.MustRegister(c.policyGauge, c.clusterGauge)
+	return c
+}
+
+// SetPolicy sets the compliance of the policy, the namespace of the cluster policy
+// is empty. The compliance is removed when no resource was evaluated.
+func (c *Compliance) SetPolicy(key, ns, policy string, passed, evaluated int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if evaluated == 0 {
+		c.deleteSeries(key)
+		return
+	}
+
+	labels := prometheus.Labels{"policy": policy, "namespace": ns}
+	c.policyGauge.With(labels).Set(percentage(passed, evaluated))
+	c.series[key] = labels
+}
+
+// SetCluster sets the cluster-wide compliance, which is 100 when no resource was evaluated
+func (c *Compliance) SetCluster(passed, evaluated int) {
+	if evaluated == 0 {
+		c.clusterGauge.Set(100)
+		return
+	}
+
+	c.clusterGauge.Set(percentage(passed, evaluated))
+}
+
+// Retain removes the compliance of the policies which are not in keys
+func (c *Compliance) Retain(keys map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.series {
+		if !keys[key] {
+			c.deleteSeries(key)
+		}
+	}
+}
+
+func (c *Compliance) deleteSeries(key string) {
+	if labels, ok := c.series[key]; ok {
+		c.policyGauge.Delete(labels)
+	}
+	delete(c.series, key)
+}
+
+func percentage(passed, evaluated int) float64 {
+	return float64(passed) * 100 / float64(evaluated)
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/assert"
+)
+
+func Test_Compliance(t *testing.T) {
+	compliance := NewCompliance(prometheus.NewRegistry())
+
+	compliance.SetPolicy("require-labels", "", "require-labels", 3, 4)
+	compliance.SetPolicy("default/disallow-latest", "default", "disallow-latest", 1, 1)
+	compliance.SetCluster(3, 4)
+
+	expected := `
+# HELP kyverno_policy_compliance_percentage Percentage of the resources evaluated by the policy which passed, by policy and namespace.
+# TYPE kyverno_policy_compliance_percentage gauge
+kyverno_policy_compliance_percentage{namespace="",policy="require-labels"} 75
+kyverno_policy_compliance_percentage{namespace="default",policy="disallow-latest"} 100
+`
+	assert.NilError(t, testutil.CollectAndCompare(compliance.policyGauge, strings.NewReader(expected)))
+	assert.Equal(t, testutil.ToFloat64(compliance.clusterGauge), float64(75))
+
+	compliance.SetPolicy("require-labels", "", "require-labels", 0, 0)
+	assert.Equal(t, testutil.CollectAndCount(compliance.policyGauge), 1)
+
+	compliance.Retain(map[string]bool{})
+	assert.Equal(t, testutil.CollectAndCount(compliance.policyGauge), 0)
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Coverage exports the number of resources matched by each rule of the policies,
+// the rules which never matched any resource have a value of 0
+type Coverage struct {
+	gauge *prometheus.GaugeVec
+
+	mu sync.Mutex
+	// series are the label sets set for each policy, to delete the series of the
+	// deleted policies and rules
+	series map[string][]prometheus.Labels
+}
+
+// NewCoverage registers the coverage gauge
+func NewCoverage(registerer prometheus.Registerer) *Coverage {
+	c := &Coverage{
+		gauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "policy_rule_matches",
+			Help:      "Number of resources matched by the rules of the policies by policy, rule and namespace.",
+		}, []string{"policy", "rule", "namespace"}),
+		series: map[string][]prometheus.Labels{},
+	}
+
+	registerer.MustRegister(c.gauge)
+	return c
+}
+
+// SetPolicy replaces the matches of the rules of the policy, the namespace of
+// the cluster policy is empty
+func (c *Coverage) SetPolicy(key, ns, policy string, matches map[string]int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.deleteSeries(key)
+	series := make([]prometheus.Labels, 0, len(matches))
+	for rule, count := range matches {
+		labels := prometheus.Labels{"policy": policy, "rule": rule, "namespace": ns}
+		c.gauge.With(labels).Set(float64(count))
+		series = append(series, labels)
+	}
+
+	if len(series) > 0 {
+		c.series[key] = series
+	}
+}
+
+// Retain removes the matches of the policies which are not in keys
+func (c *Coverage) Retain(keys map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.series {
+		if !keys[key] {
+			c.deleteSeries(key)
+		}
+	}
+}
+
+func (c *Coverage) deleteSeries(key string) {
+	for _, labels := range c.series[key] {
+		c.gauge.Delete(labels)
+	}
+	delete(c.series, key)
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/assert"
+)
+
+func Test_Coverage(t *testing.T) {
+	coverage := NewCoverage(prometheus.NewRegistry())
+
+	coverage.SetPolicy("require-labels", "", "require-labels", map[string]int{"require-team": 3, "require-app": 0})
+	coverage.SetPolicy("default/disallow-latest", "default", "disallow-latest", map[string]int{"validate-image-tag": 1})
+
+	expected := `
+# HELP kyverno_policy_rule_matches Number of resources matched by the rules of the policies by policy, rule and namespace.
+# TYPE kyverno_policy_rule_matches gauge
+kyverno_policy_rule_matches{namespace="",policy="require-labels",rule="require-app"} 0
+kyverno_policy_rule_matches{namespace="",policy="require-labels",rule="require-team"} 3
+kyverno_policy_rule_matches{namespace="default",policy="disallow-latest",rule="validate-image-tag"} 1
+`
+	assert.NilError(t, testutil.CollectAndCompare(coverage.gauge, strings.NewReader(expected)))
+
+	coverage.SetPolicy("require-labels", "", "require-labels", map[string]int{"require-team": 4})
+	assert.Equal(t, testutil.CollectAndCount(coverage.gauge), 2)
+
+	coverage.Retain(map[string]bool{"require-labels": true})
+	assert.Equal(t, testutil.CollectAndCount(coverage.gauge), 1)
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DropReasonQueueFull is the reason of the events dropped because too many events are queued
+	DropReasonQueueFull = "queue_full"
+
+	// DropReasonRetriesExceeded is the reason of the events dropped after failing to be recorded
+	DropReasonRetriesExceeded = "retries_exceeded"
+)
+
+// DroppedEvents counts the events which were not recorded by the event generator, or the
+// notifications which were not sent by the notifiers, by reason
+type DroppedEvents struct {
+	counter *prometheus.CounterVec
+}
+
+// NewDroppedEvents registers the dropped events counter
+func NewDroppedEvents(registerer prometheus.Registerer) *DroppedEvents {
+	return newDroppedEvents(registerer, "events_dropped_total", "Number of the events which were not recorded by reason, e.g. queue_full or retries_exceeded.")
+}
+
+// NewDroppedNotifications registers the dropped notifications counter
+func NewDroppedNotifications(registerer prometheus.Registerer) *DroppedEvents {
+	return newDroppedEvents(registerer, "notifications_dropped_total", "Number of the notifications which were not sent by reason, e.g. queue_full or retries_exceeded.")
+}
+
+func newDroppedEvents(registerer prometheus.Registerer, name, help string) *DroppedEvents {
+	d := &DroppedEvents{
+		counter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      name,
+			Help:      help,
+		}, []string{"reason"}),
+	}
+
+	registerer.MustRegister(d.counter)
+	return d
+}
+
+// Inc counts a dropped event
+func (d *DroppedEvents) Inc(reason string) {
+	d.counter.WithLabelValues(reason).Inc()
+}
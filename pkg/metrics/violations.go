@@ -0,0 +1,117 @@
+// Package metrics exports the Prometheus metrics of Kyverno.
+package metrics
+
+import (
+	"sync"
+
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "kyverno"
+
+	// the statuses of the results counted as violations
+	statusFail  = "fail"
+	statusError = "error"
+)
+
+// Violations exports the current number of violations of the policy reports,
+// by policy, rule, severity and namespace
+type Violations struct {
+	gauge *prometheus.GaugeVec
+
+	mu sync.Mutex
+	// reports are the violations counted for each report, the reports of a namespace
+	// may count the violations of the same series
+	reports map[string]map[violation]float64
+	// totals are the violations of the series summed over the reports
+	totals map[violation]float64
+}
+
+// violation is the label set of a series
+type violation struct {
+	policy, rule, severity, namespace string
+}
+
+func (v violation) labels() prometheus.Labels {
+	return prometheus.Labels{"policy": v.policy, "rule": v.rule, "severity": v.severity, "namespace": v.namespace}
+}
+
+// NewViolations registers the violations gauge
+func NewViolations(registerer prometheus.Registerer) *Violations {
+	v := &Violations{
+		gauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "policy_violations",
+			Help:      "Number of failed results of the policy reports by policy, rule, severity and namespace.",
+		}, []string{"policy", "rule", "severity", "namespace"}),
+		reports: map[string]map[violation]float64{},
+		totals:  map[violation]float64{},
+	}
+
+	registerer.MustRegister(v.gauge)
+	return v
+}
+
+// SetReport replaces the violations of the report, the namespace of the
+// cluster policy report is empty
+func (v *Violations) SetReport(key, ns string, results []*report.PolicyReportResult) {
+	counts := map[violation]float64{}
+	for _, result := range results {
+		if result.Status != statusFail && result.Status != statusError {
+			continue
+		}
+		counts[violation{policy: result.Policy, rule: result.Rule, severity: string(result.Severity), namespace: ns}]++
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	changed := v.deleteReport(key)
+	for series, count := range counts {
+		v.totals[series] += count
+		changed[series] = true
+	}
+
+	if len(counts) > 0 {
+		v.reports[key] = counts
+	}
+
+	v.update(changed)
+}
+
+// DeleteReport removes the violations of a deleted report
+func (v *Violations) DeleteReport(key string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.update(v.deleteReport(key))
+}
+
+// deleteReport subtracts the violations of the report from the totals and
+// returns the series changed
+func (v *Violations) deleteReport(key string) map[violation]bool {
+	changed := map[violation]bool{}
+	for series, count := range v.reports[key] {
+		v.totals[series] -= count
+		changed[series] = true
+	}
+
+	delete(v.reports, key)
+	return changed
+}
+
+// update sets the changed series to their totals, the series without
+// violations are deleted
+func (v *Violations) update(changed map[violation]bool) {
+	for series := range changed {
+		if v.totals[series] <= 0 {
+			delete(v.totals, series)
+			v.gauge.Delete(series.labels())
+			continue
+		}
+
+		v.gauge.With(series.labels()).Set(v.totals[series])
+	}
+}
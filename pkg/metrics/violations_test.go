@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/assert"
+)
+
+func Test_Violations(t *testing.T) {
+	violations := NewViolations(prometheus.NewRegistry())
+
+	violations.SetReport("default/polr-ns-default", "default", []*report.PolicyReportResult{
+		{Policy: "require-labels", Rule: "require-team", Severity: "high", Status: "fail"},
+		{Policy: "require-labels", Rule: "require-team", Severity: "high", Status: "fail"},
+		{Policy: "require-labels", Rule: "require-app", Status: "error"},
+		{Policy: "require-labels", Rule: "require-env", Status: "pass"},
+	})
+
+	expected := `
+# HELP kyverno_policy_violations Number of failed results of the policy reports by policy, rule, severity and namespace.
+# TYPE kyverno_policy_violations gauge
+kyverno_policy_violations{namespace="default",policy="require-labels",rule="require-app",severity=""} 1
+kyverno_policy_violations{namespace="default",policy="require-labels",rule="require-team",severity="high"} 2
+`
+	assert.NilError(t, testutil.CollectAndCompare(violations.gauge, strings.NewReader(expected)))
+
+	violations.SetReport("default/polr-ns-default", "default", []*report.PolicyReportResult{
+		{Policy: "require-labels", Rule: "require-team", Severity: "high", Status: "fail"},
+	})
+	assert.Equal(t, testutil.CollectAndCount(violations.gauge), 1)
+
+	violations.DeleteReport("default/polr-ns-default")
+	assert.Equal(t, testutil.CollectAndCount(violations.gauge), 0)
+}
+
+func Test_Violations_ReportsOfNamespace(t *testing.T) {
+	violations := NewViolations(prometheus.NewRegistry())
+
+	results := []*report.PolicyReportResult{{Policy: "require-labels", Rule: "require-team", Severity: "high", Status: "fail"}}
+	violations.SetReport("default/polr-ns-default", "default", results)
+	violations.SetReport("default/polr-ns-default-team", "default", append(results, results...))
+
+	expected := `
+# HELP kyverno_policy_violations Number of failed results of the policy reports by policy, rule, severity and namespace.
+# TYPE kyverno_policy_violations gauge
+kyverno_policy_violations{namespace="default",policy="require-labels",rule="require-team",severity="high"} 3
+`
+	assert.NilError(t, testutil.CollectAndCompare(violations.gauge, strings.NewReader(expected)))
+
+	// the violations of the other report are kept
+	violations.DeleteReport("default/polr-ns-default-team")
+	assert.Equal(t, testutil.ToFloat64(violations.gauge), float64(1))
+
+	violations.SetReport("default/polr-ns-default-team", "default", results)
+	violations.SetReport("default/polr-ns-default", "default", nil)
+	assert.Equal(t, testutil.ToFloat64(violations.gauge), float64(1))
+
+	violations.DeleteReport("default/polr-ns-default-team")
+	assert.Equal(t, testutil.CollectAndCount(violations.gauge), 0)
+}
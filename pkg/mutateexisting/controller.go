@@ -0,0 +1,475 @@
+package mutateexisting
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1"
+	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/common"
+	"github.com/kyverno/kyverno/pkg/config"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/engine"
+	enginectx "github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/resourcecache"
+	"golang.org/x/time/rate"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	informers "k8s.io/client-go/informers/core/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	workQueueName       = "mutate-existing"
+	workQueueRetryLimit = 5
+
+	// the admitted triggers are retried with an exponential backoff until they are persisted, for
+	// about 20 seconds, longer than the timeouts of the webhooks which may still deny the requests
+	pendingTriggerRetryLimit = 12
+
+	// the updates of target resources are limited to 10 per second, with bursts of 100
+	rateLimitQPS   = 10
+	rateLimitBurst = 100
+)
+
+// errTriggerPending is returned while the admitted trigger resource is not persisted yet
+var errTriggerPending = errors.New("the admitted trigger resource is not persisted yet")
+
+// request identifies a policy and the trigger resource.
+// A request without trigger processes all resources matched by the policy.
+type request struct {
+	// policy is the name of a ClusterPolicy or namespace/name of a Policy
+	policy     string
+	apiVersion string
+	kind       string
+	namespace  string
+	name       string
+
+	// admitted is set for the triggers of admission requests, they are processed once persisted
+	admitted bool
+	// resourceVersion is the version of the updated resource before the admitted update
+	resourceVersion string
+}
+
+func (r request) hasTrigger() bool {
+	return r.kind != ""
+}
+
+// pending checks if the admitted trigger is not persisted yet, the trigger is nil when not found
+func (r request) pending(trigger *unstructured.Unstructured) bool {
+	if !r.admitted {
+		return false
+	}
+
+	if trigger == nil {
+		return true
+	}
+
+	return r.resourceVersion != "" && trigger.GetResourceVersion() == r.resourceVersion
+}
+
+// Controller applies mutate rules with targets to existing resources.
+// The rules are applied when a policy is created or updated, and when
+// a resource matched by the rule (the trigger) is created or updated.
+type Controller struct {
+	client *client.Client
+	queue  workqueue.RateLimitingInterface
+
+	pLister  kyvernolister.ClusterPolicyLister
+	npLister kyvernolister.PolicyLister
+	nsLister listerv1.NamespaceLister
+
+	pSynced        cache.InformerSynced
+	npSynced       cache.InformerSynced
+	nsListerSynced cache.InformerSynced
+
+	configHandler config.Interface
+	resCache      resourcecache.ResourceCache
+
+	// dryRun reports the mutations of target resources without persisting them
+	dryRun bool
+
+	log logr.Logger
+}
+
+// NewController returns a new instance of the mutate existing controller
+func NewController(client *client.Client,
+	pInformer kyvernoinformer.ClusterPolicyInformer,
+	npInformer kyvernoinformer.PolicyInformer,
+	namespaces informers.NamespaceInformer,
+	configHandler config.Interface,
+	resCache resourcecache.ResourceCache,
+	dryRun bool,
+	log logr.Logger) *Controller {
+
+	rateLimiter := workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 1000*time.Second),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(rateLimitQPS), rateLimitBurst)},
+	)
+
+	c := &Controller{
+		client:         client,
+		queue:          workqueue.NewNamedRateLimitingQueue(rateLimiter, workQueueName),
+		pLister:        pInformer.Lister(),
+		npLister:       npInformer.Lister(),
+		nsLister:       namespaces.Lister(),
+		pSynced:        pInformer.Informer().HasSynced,
+		npSynced:       npInformer.Informer().HasSynced,
+		nsListerSynced: namespaces.Informer().HasSynced,
+		configHandler:  configHandler,
+		resCache:       resCache,
+		dryRun:         dryRun,
+		log:            log,
+	}
+
+	pInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.addPolicy,
+		UpdateFunc: c.updatePolicy,
+	})
+
+	npInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.addNsPolicy,
+		UpdateFunc: c.updateNsPolicy,
+	})
+
+	return c
+}
+
+// AddTrigger enqueues the resource of an allowed admission request for the policies with mutate
+// existing rules. The resource is not persisted yet, the trigger is processed once the created
+// resource exists, or once the version of the updated resource changed. The triggers of requests
+// denied after their admission are dropped.
+func (c *Controller) AddTrigger(policies []*kyverno.ClusterPolicy, resource unstructured.Unstructured, update bool) {
+	if resource.GetName() == "" {
+		return
+	}
+
+	var resourceVersion string
+	if update {
+		resourceVersion = resource.GetResourceVersion()
+	}
+
+	for _, policy := range policies {
+		if !hasMutateExisting(policy) {
+			continue
+		}
+
+		c.log.V(4).Info("trigger added", "policy", policy.Name, "kind", resource.GetKind(), "namespace", resource.GetNamespace(), "name", resource.GetName())
+		c.queue.Add(request{
+			policy:     policyKey(policy),
+			apiVersion: resource.GetAPIVersion(),
+			kind:       resource.GetKind(),
+			namespace:  resource.GetNamespace(),
+			name:       resource.GetName(),
+
+			admitted:        true,
+			resourceVersion: resourceVersion,
+		})
+	}
+}
+
+func (c *Controller) addPolicy(obj interface{}) {
+	c.enqueuePolicy(obj.(*kyverno.ClusterPolicy))
+}
+
+func (c *Controller) updatePolicy(old, cur interface{}) {
+	pOld := old.(*kyverno.ClusterPolicy)
+	pNew := cur.(*kyverno.ClusterPolicy)
+	if reflect.DeepEqual(pOld.Spec, pNew.Spec) {
+		return
+	}
+
+	c.enqueuePolicy(pNew)
+}
+
+func (c *Controller) addNsPolicy(obj interface{}) {
+	c.enqueuePolicy(convertPolicyToClusterPolicy(obj.(*kyverno.Policy)))
+}
+
+func (c *Controller) updateNsPolicy(old, cur interface{}) {
+	npOld := old.(*kyverno.Policy)
+	npNew := cur.(*kyverno.Policy)
+	if reflect.DeepEqual(npOld.Spec, npNew.Spec) {
+		return
+	}
+
+	c.enqueuePolicy(convertPolicyToClusterPolicy(npNew))
+}
+
+func (c *Controller) enqueuePolicy(policy *kyverno.ClusterPolicy) {
+	if !hasMutateExisting(policy) {
+		return
+	}
+
+	c.log.V(4).Info("policy added", "policy", policyKey(policy))
+	c.queue.Add(request{policy: policyKey(policy)})
+}
+
+// Run starts the workers
+func (c *Controller) Run(workers int, stopCh <-chan struct{}) {
+	logger := c.log
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger.Info("starting", "dryRun", c.dryRun)
+	defer logger.Info("shutting down")
+
+	if !cache.WaitForCacheSync(stopCh, c.pSynced, c.npSynced, c.nsListerSynced) {
+		logger.Info("failed to sync informer cache")
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (c *Controller) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	defer c.queue.Done(obj)
+
+	req, ok := obj.(request)
+	if !ok {
+		c.queue.Forget(obj)
+		c.log.Info("incorrect type: expecting type 'request'", "object", obj)
+		return true
+	}
+
+	err := c.process(req)
+	c.handleErr(err, req)
+
+	return true
+}
+
+func (c *Controller) handleErr(err error, req request) {
+	logger := c.log.WithValues("policy", req.policy, "kind", req.kind, "namespace", req.namespace, "name", req.name)
+	if err == nil {
+		c.queue.Forget(req)
+		return
+	}
+
+	retryLimit := workQueueRetryLimit
+	if errors.Is(err, errTriggerPending) {
+		retryLimit = pendingTriggerRetryLimit
+	}
+
+	if c.queue.NumRequeues(req) < retryLimit {
+		logger.V(3).Info("retrying mutate existing request", "error", err.Error())
+		c.queue.AddRateLimited(req)
+		return
+	}
+
+	logger.Error(err, "failed to process mutate existing request")
+	c.queue.Forget(req)
+}
+
+func (c *Controller) process(req request) error {
+	policy, err := c.getPolicy(req.policy)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !req.hasTrigger() {
+		return c.enqueueTriggers(policy)
+	}
+
+	trigger, err := c.client.GetResource(req.apiVersion, req.kind, req.namespace, req.name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		trigger = nil
+	}
+
+	if req.pending(trigger) && c.queue.NumRequeues(req) < pendingTriggerRetryLimit {
+		return errTriggerPending
+	}
+
+	if trigger == nil {
+		// the resource was deleted, or the admission request was denied after its admission
+		c.log.V(4).Info("trigger resource not found", "kind", req.kind, "namespace", req.namespace, "name", req.name)
+		return nil
+	}
+
+	return c.mutateTargets(policy, *trigger)
+}
+
+// enqueueTriggers enqueues the existing resources matched by the mutate existing rules of the policy
+func (c *Controller) enqueueTriggers(policy *kyverno.ClusterPolicy) error {
+	logger := c.log.WithValues("policy", policyKey(policy))
+	for _, rule := range policy.Spec.Rules {
+		if !rule.HasMutateExisting() {
+			continue
+		}
+
+		for _, kind := range rule.MatchResources.Kinds {
+			resources, err := c.client.ListResource("", kind, policy.GetNamespace(), rule.MatchResources.Selector)
+			if err != nil {
+				logger.Error(err, "failed to list resources", "kind", kind)
+				continue
+			}
+
+			for _, resource := range resources.Items {
+				c.queue.AddRateLimited(request{
+					policy:     policyKey(policy),
+					apiVersion: resource.GetAPIVersion(),
+					kind:       resource.GetKind(),
+					namespace:  resource.GetNamespace(),
+					name:       resource.GetName(),
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// mutateTargets applies the mutate existing rules of the policy for the trigger resource and updates the targets
+func (c *Controller) mutateTargets(policy *kyverno.ClusterPolicy, trigger unstructured.Unstructured) error {
+	logger := c.log.WithValues("policy", policyKey(policy), "kind", trigger.GetKind(), "namespace", trigger.GetNamespace(), "name", trigger.GetName())
+
+	triggerRaw, err := trigger.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to marshal trigger resource: %v", err)
+	}
+
+	ctx := enginectx.NewContext()
+	if err := ctx.AddResource(triggerRaw); err != nil {
+		return fmt.Errorf("failed to load trigger resource in context: %v", err)
+	}
+
+	policyContext := &engine.PolicyContext{
+		Policy:              *policy,
+		NewResource:         trigger,
+		Client:              c.client,
+		ExcludeGroupRole:    c.configHandler.GetExcludeGroupRole(),
+		ImmutableFieldsFunc: c.configHandler.GetImmutableFields,
+		ResourceCache:       c.resCache,
+		JSONContext:         ctx,
+		NamespaceLabels:     common.GetNamespaceSelectorsFromNamespaceLister(trigger.GetKind(), trigger.GetNamespace(), c.nsLister, logger),
+	}
+
+	var errs []string
+	responses := engine.MutateExisting(policyContext, c.targetGetter(policy))
+	for _, resp := range responses {
+		target := resp.PolicyResponse.Resource
+		if !resp.IsSuccessful() {
+			errs = append(errs, fmt.Sprintf("failed to mutate %s: %s", target.GetKey(), strings.Join(failureMessages(resp), ", ")))
+			continue
+		}
+
+		if c.dryRun {
+			logger.Info("dry-run: target not updated", "target", target.GetKey(), "patches", string(engineutils.JoinPatches(resp.GetPatches())))
+		}
+
+		patched := resp.PatchedResource
+		annotatePatches(&patched, resp, logger)
+		if _, err := c.client.UpdateResource(patched.GetAPIVersion(), patched.GetKind(), patched.GetNamespace(), patched.Object, c.dryRun); err != nil {
+			errs = append(errs, fmt.Sprintf("failed to update %s: %v", target.GetKey(), err))
+			continue
+		}
+
+		logger.V(3).Info("target mutated", "target", target.GetKey(), "rules", resp.GetSuccessRules(), "dryRun", c.dryRun)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// targetGetter fetches the targets, the targets of a Policy are restricted to its namespace
+func (c *Controller) targetGetter(policy *kyverno.ClusterPolicy) engine.TargetGetter {
+	return func(target kyverno.ResourceSpec) (*unstructured.Unstructured, error) {
+		if ns := policy.GetNamespace(); ns != "" && target.Namespace != ns {
+			return nil, fmt.Errorf("target namespace %s must be the policy namespace %s", target.Namespace, ns)
+		}
+
+		return c.client.GetResource(target.APIVersion, target.Kind, target.Namespace, target.Name)
+	}
+}
+
+func (c *Controller) getPolicy(key string) (*kyverno.ClusterPolicy, error) {
+	if ns, name, err := cache.SplitMetaNamespaceKey(key); err == nil && ns != "" {
+		policy, err := c.npLister.Policies(ns).Get(name)
+		if err != nil {
+			return nil, err
+		}
+		return convertPolicyToClusterPolicy(policy), nil
+	}
+
+	return c.pLister.Get(key)
+}
+
+// annotatePatches records the rules that patched the target in the patches annotation
+func annotatePatches(target *unstructured.Unstructured, resp *response.EngineResponse, log logr.Logger) {
+	value := engineutils.PatchesAnnotationValue([]*response.EngineResponse{resp}, log)
+	if value == nil {
+		return
+	}
+
+	annotations := target.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[engineutils.PatchesAnnotation] = string(value)
+	target.SetAnnotations(annotations)
+}
+
+func failureMessages(resp *response.EngineResponse) (messages []string) {
+	for _, rule := range resp.PolicyResponse.Rules {
+		if !rule.Success {
+			messages = append(messages, fmt.Sprintf("rule %s: %s", rule.Name, rule.Message))
+		}
+	}
+	return messages
+}
+
+func hasMutateExisting(policy *kyverno.ClusterPolicy) bool {
+	for _, rule := range policy.Spec.Rules {
+		if rule.HasMutateExisting() {
+			return true
+		}
+	}
+	return false
+}
+
+func policyKey(policy *kyverno.ClusterPolicy) string {
+	if policy.GetNamespace() != "" {
+		return policy.GetNamespace() + "/" + policy.GetName()
+	}
+	return policy.GetName()
+}
+
+// convertPolicyToClusterPolicy - convert Policy to ClusterPolicy
+// This will retain the kind of Policy and convert type to ClusterPolicy
+func convertPolicyToClusterPolicy(nsPolicy *kyverno.Policy) *kyverno.ClusterPolicy {
+	cpol := kyverno.ClusterPolicy(*nsPolicy)
+	return &cpol
+}
@@ -0,0 +1,92 @@
+package mutateexisting
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func Test_AddTrigger(t *testing.T) {
+	policy := &kyverno.ClusterPolicy{}
+	assert.NilError(t, json.Unmarshal([]byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "sync-team"},
+		"spec": {
+			"rules": [{
+				"name": "label-configmap",
+				"match": {"resources": {"kinds": ["Secret"]}},
+				"mutate": {
+					"targets": [{"apiVersion": "v1", "kind": "ConfigMap", "namespace": "{{ request.object.metadata.namespace }}", "name": "team"}],
+					"patchStrategicMerge": {"metadata": {"labels": {"synced": "true"}}}
+				}
+			}]
+		}
+	}`), policy))
+
+	c := &Controller{queue: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()), log: logr.Discard()}
+	defer c.queue.ShutDown()
+
+	secret := unstructured.Unstructured{}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	secret.SetNamespace("default")
+	secret.SetName("credentials")
+	secret.SetResourceVersion("10")
+
+	c.AddTrigger([]*kyverno.ClusterPolicy{policy, {}}, secret, false)
+	c.AddTrigger([]*kyverno.ClusterPolicy{policy}, secret, true)
+	assert.Equal(t, c.queue.Len(), 2)
+
+	created, _ := c.queue.Get()
+	updated, _ := c.queue.Get()
+	assert.Equal(t, created.(request), request{policy: "sync-team", apiVersion: "v1", kind: "Secret", namespace: "default", name: "credentials", admitted: true})
+	assert.Equal(t, updated.(request).resourceVersion, "10")
+}
+
+func Test_RequestPending(t *testing.T) {
+	persisted := &unstructured.Unstructured{}
+	persisted.SetResourceVersion("11")
+
+	testcases := []struct {
+		description string
+		req         request
+		trigger     *unstructured.Unstructured
+		pending     bool
+	}{
+		{
+			description: "created trigger not persisted yet",
+			req:         request{kind: "Secret", admitted: true},
+			pending:     true,
+		},
+		{
+			description: "created trigger persisted",
+			req:         request{kind: "Secret", admitted: true},
+			trigger:     persisted,
+		},
+		{
+			description: "updated trigger not persisted yet",
+			req:         request{kind: "Secret", admitted: true, resourceVersion: "11"},
+			trigger:     persisted,
+			pending:     true,
+		},
+		{
+			description: "updated trigger persisted",
+			req:         request{kind: "Secret", admitted: true, resourceVersion: "10"},
+			trigger:     persisted,
+		},
+		{
+			description: "existing trigger of a policy",
+			req:         request{kind: "Secret"},
+		},
+	}
+
+	for _, testcase := range testcases {
+		assert.Equal(t, testcase.req.pending(testcase.trigger), testcase.pending, testcase.description)
+	}
+}
@@ -0,0 +1,100 @@
+package notifier
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"sigs.k8s.io/yaml"
+)
+
+// the notifier types
+const (
+	WebhookType = "webhook"
+	SlackType   = "slack"
+	SyslogType  = "syslog"
+)
+
+// Config configures the notifiers of the violation and enforcement events
+type Config struct {
+	// QPS is the maximum number of notifications sent per second, 10 by default
+	QPS float64 `json:"qps,omitempty"`
+
+	// Burst is the maximum burst of notifications sent, 20 by default
+	Burst int `json:"burst,omitempty"`
+
+	// Notifiers are the sinks the notifications are pushed to
+	Notifiers []NotifierConfig `json:"notifiers"`
+}
+
+// NotifierConfig configures a single notifier
+type NotifierConfig struct {
+	// Name identifies the notifier in the logs
+	Name string `json:"name"`
+
+	// Type is one of webhook, slack or syslog
+	Type string `json:"type"`
+
+	// URL is the endpoint of the webhook and slack notifiers
+	URL string `json:"url,omitempty"`
+
+	// Headers are added to the requests of the webhook notifier
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Network and Address of the syslog server, the local syslog server is used when the address is empty
+	Network string `json:"network,omitempty"`
+	Address string `json:"address,omitempty"`
+
+	// Template is a Go template rendered with the notification. The webhook notifier posts the
+	// notification as JSON and the other notifiers send a one-line summary when it is empty.
+	Template string `json:"template,omitempty"`
+}
+
+// LoadConfig reads the notifiers configuration from a YAML or JSON file
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notifiers configuration %s: %v", path, err)
+	}
+
+	return ParseConfig(data)
+}
+
+// ParseConfig parses and validates the notifiers configuration
+func ParseConfig(data []byte) (*Config, error) {
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse notifiers configuration: %v", err)
+	}
+
+	if config.QPS <= 0 {
+		config.QPS = 10
+	}
+
+	if config.Burst <= 0 {
+		config.Burst = 20
+	}
+
+	names := make(map[string]bool, len(config.Notifiers))
+	for _, n := range config.Notifiers {
+		if n.Name == "" {
+			return nil, fmt.Errorf("notifier name is required")
+		}
+
+		if names[n.Name] {
+			return nil, fmt.Errorf("duplicate notifier %s", n.Name)
+		}
+		names[n.Name] = true
+
+		switch n.Type {
+		case WebhookType, SlackType:
+			if n.URL == "" {
+				return nil, fmt.Errorf("url is required for %s notifier %s", n.Type, n.Name)
+			}
+		case SyslogType:
+		default:
+			return nil, fmt.Errorf("invalid type %s of notifier %s, expected one of %s, %s or %s", n.Type, n.Name, WebhookType, SlackType, SyslogType)
+		}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,184 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/event"
+	"github.com/kyverno/kyverno/pkg/metrics"
+	"golang.org/x/time/rate"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	workQueueName       = "notifier"
+	workQueueRetryLimit = 5
+
+	// maxQueuedNotifications is the maximum number of pending notifications, the new notifications
+	// are dropped when the violations are added faster than they are sent
+	maxQueuedNotifications = 1000
+)
+
+// request is a notification sent to a single notifier, so that a failing
+// notifier only retries its own notifications
+type request struct {
+	notifier     string
+	notification Notification
+}
+
+// Dispatcher pushes the violation and enforcement events to the notifiers,
+// the notifications are rate limited and retried on failures
+type Dispatcher struct {
+	notifiers map[string]Notifier
+	limiter   *rate.Limiter
+	queue     workqueue.RateLimitingInterface
+	// dropped counts the notifications which were not sent, it is not set when the metrics are disabled
+	dropped *metrics.DroppedEvents
+	log     logr.Logger
+}
+
+// NewDispatcher returns a dispatcher of the configured notifiers
+func NewDispatcher(config *Config, dropped *metrics.DroppedEvents, log logr.Logger) (*Dispatcher, error) {
+	notifiers := make(map[string]Notifier, len(config.Notifiers))
+	for _, c := range config.Notifiers {
+		n, err := New(c)
+		if err != nil {
+			return nil, err
+		}
+		notifiers[n.Name()] = n
+	}
+
+	return &Dispatcher{
+		notifiers: notifiers,
+		limiter:   rate.NewLimiter(rate.Limit(config.QPS), config.Burst),
+		queue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), workQueueName),
+		dropped:   dropped,
+		log:       log,
+	}, nil
+}
+
+// Add queues the notifications of the policy violation events, including the blocked requests
+func (d *Dispatcher) Add(infos ...event.Info) {
+	for _, info := range infos {
+		if info.Reason != event.PolicyViolation.String() {
+			continue
+		}
+
+		// the events on the policies duplicate the events on the resources
+		if info.Kind == "ClusterPolicy" || info.Kind == "Policy" {
+			continue
+		}
+
+		n := Notification{
+			Kind:      info.Kind,
+			Namespace: info.Namespace,
+			Name:      info.Name,
+			Reason:    info.Reason,
+			Action:    info.Action,
+			Message:   info.Message,
+			Source:    info.Source.String(),
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		}
+
+		for name := range d.notifiers {
+			if d.queue.Len() >= maxQueuedNotifications {
+				d.log.V(4).Info("dropping notification as too many notifications are queued", "notifier", name, "kind", n.Kind, "namespace", n.Namespace, "name", n.Name)
+				d.drop(metrics.DropReasonQueueFull)
+				continue
+			}
+
+			d.queue.Add(request{notifier: name, notification: n})
+		}
+	}
+}
+
+func (d *Dispatcher) drop(reason string) {
+	if d.dropped != nil {
+		d.dropped.Inc(reason)
+	}
+}
+
+// Run starts the workers
+func (d *Dispatcher) Run(workers int, stopCh <-chan struct{}) {
+	logger := d.log
+	defer utilruntime.HandleCrash()
+	defer d.queue.ShutDown()
+
+	logger.Info("start", "notifiers", len(d.notifiers))
+	defer logger.Info("shutting down")
+
+	// the workers waiting for the rate limiter stop once the dispatcher is stopped
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(func() { d.runWorker(ctx) }, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (d *Dispatcher) runWorker(ctx context.Context) {
+	for d.processNextWorkItem(ctx) {
+	}
+}
+
+func (d *Dispatcher) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := d.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	defer d.queue.Done(obj)
+
+	req, ok := obj.(request)
+	if !ok {
+		d.queue.Forget(obj)
+		d.log.Info("incorrect type: expecting type 'request'", "object", obj)
+		return true
+	}
+
+	err := d.notify(ctx, req)
+	if ctx.Err() != nil {
+		d.log.V(4).Info("stopped waiting for the notification rate limiter", "notifier", req.notifier)
+		d.queue.Forget(req)
+		return false
+	}
+
+	d.handleErr(err, req)
+	return true
+}
+
+func (d *Dispatcher) notify(ctx context.Context, req request) error {
+	n, ok := d.notifiers[req.notifier]
+	if !ok {
+		return nil
+	}
+
+	if err := d.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	return n.Notify(req.notification)
+}
+
+func (d *Dispatcher) handleErr(err error, req request) {
+	logger := d.log.WithValues("notifier", req.notifier, "kind", req.notification.Kind, "namespace", req.notification.Namespace, "name", req.notification.Name)
+	if err == nil {
+		d.queue.Forget(req)
+		return
+	}
+
+	if d.queue.NumRequeues(req) < workQueueRetryLimit {
+		logger.V(3).Info("retrying notification", "error", err.Error())
+		d.queue.AddRateLimited(req)
+		return
+	}
+
+	logger.Error(err, "failed to send notification")
+	d.queue.Forget(req)
+	d.drop(metrics.DropReasonRetriesExceeded)
+}
@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/kyverno/pkg/event"
+	"github.com/kyverno/kyverno/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"golang.org/x/time/rate"
+	"gotest.tools/assert"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeNotifier struct {
+	notifications []Notification
+}
+
+func (n *fakeNotifier) Name() string { return "fake" }
+
+func (n *fakeNotifier) Notify(notification Notification) error {
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+func newTestDispatcher(limiter *rate.Limiter, dropped *metrics.DroppedEvents) (*Dispatcher, *fakeNotifier) {
+	n := &fakeNotifier{}
+	return &Dispatcher{
+		notifiers: map[string]Notifier{n.Name(): n},
+		limiter:   limiter,
+		queue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), workQueueName),
+		dropped:   dropped,
+		log:       log.Log,
+	}, n
+}
+
+func Test_DropNotificationsWhenQueueIsFull(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	d, _ := newTestDispatcher(rate.NewLimiter(rate.Inf, 1), metrics.NewDroppedNotifications(registry))
+	defer d.queue.ShutDown()
+
+	for i := 0; i < maxQueuedNotifications+5; i++ {
+		d.Add(event.Info{Kind: "Pod", Namespace: "default", Name: fmt.Sprintf("pod%d", i), Reason: event.PolicyViolation.String()})
+	}
+	assert.Equal(t, d.queue.Len(), maxQueuedNotifications)
+
+	expected := `
+# HELP kyverno_notifications_dropped_total Number of the notifications which were not sent by reason, e.g. queue_full or retries_exceeded.
+# TYPE kyverno_notifications_dropped_total counter
+kyverno_notifications_dropped_total{reason="queue_full"} 5
+`
+	assert.NilError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "kyverno_notifications_dropped_total"))
+}
+
+func Test_StopWaitingForTheRateLimiter(t *testing.T) {
+	d, n := newTestDispatcher(rate.NewLimiter(rate.Every(time.Hour), 1), nil)
+	defer d.queue.ShutDown()
+
+	// the notification is not sent and the worker stops once the dispatcher is stopped
+	assert.Assert(t, d.limiter.Allow())
+	d.Add(event.Info{Kind: "Pod", Namespace: "default", Name: "pod1", Reason: event.PolicyViolation.String()})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go cancel()
+	assert.Assert(t, !d.processNextWorkItem(ctx))
+	assert.Equal(t, len(n.notifications), 0)
+	assert.Equal(t, d.queue.Len(), 0)
+}
@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+const defaultTemplate = `[{{ .Reason }}] {{ .Kind }} {{ if .Namespace }}{{ .Namespace }}/{{ end }}{{ .Name }}: {{ .Message }}`
+
+// Notification is a violation or enforcement event pushed to the notifiers
+type Notification struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+	Action    string `json:"action,omitempty"`
+	Message   string `json:"message"`
+	Source    string `json:"source"`
+	Timestamp string `json:"timestamp"`
+}
+
+// Notifier pushes notifications to an external sink
+type Notifier interface {
+	Name() string
+	Notify(n Notification) error
+}
+
+// New returns the notifier of the configuration
+func New(config NotifierConfig) (Notifier, error) {
+	tmpl, err := parseTemplate(config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.Type {
+	case WebhookType:
+		return newWebhookNotifier(config, tmpl), nil
+	case SlackType:
+		return newSlackNotifier(config, tmpl), nil
+	case SyslogType:
+		return newSyslogNotifier(config, tmpl), nil
+	default:
+		return nil, fmt.Errorf("invalid type %s of notifier %s", config.Type, config.Name)
+	}
+}
+
+func parseTemplate(config NotifierConfig) (*template.Template, error) {
+	text := config.Template
+	if text == "" {
+		if config.Type == WebhookType {
+			return nil, nil
+		}
+		text = defaultTemplate
+	}
+
+	tmpl, err := template.New(config.Name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the template of notifier %s: %v", config.Name, err)
+	}
+
+	return tmpl, nil
+}
+
+func render(tmpl *template.Template, n Notification) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, n); err != nil {
+		return "", fmt.Errorf("failed to render notification: %v", err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,126 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+var notification = Notification{
+	Kind:      "Pod",
+	Namespace: "default",
+	Name:      "nginx",
+	Reason:    "PolicyViolation",
+	Message:   "policy 'require-labels' (Validation) rule 'require-team' failed",
+	Source:    "admission-controller",
+	Timestamp: "2021-04-01T10:00:00Z",
+}
+
+func Test_ParseConfig(t *testing.T) {
+	testcases := []struct {
+		description string
+		config      string
+		err         string
+	}{
+		{
+			description: "valid configuration",
+			config: `
+notifiers:
+- name: alerts
+  type: webhook
+  url: https://alerts.example.com
+- name: local
+  type: syslog`,
+		},
+		{
+			description: "missing url",
+			config: `
+notifiers:
+- name: team
+  type: slack`,
+			err: "url is required for slack notifier team",
+		},
+		{
+			description: "invalid type",
+			config: `
+notifiers:
+- name: team
+  type: email`,
+			err: "invalid type email of notifier team, expected one of webhook, slack or syslog",
+		},
+		{
+			description: "duplicate name",
+			config: `
+notifiers:
+- name: local
+  type: syslog
+- name: local
+  type: syslog`,
+			err: "duplicate notifier local",
+		},
+	}
+
+	for _, testcase := range testcases {
+		config, err := ParseConfig([]byte(testcase.config))
+		if testcase.err != "" {
+			assert.Error(t, err, testcase.err, testcase.description)
+			continue
+		}
+
+		assert.NilError(t, err, testcase.description)
+		assert.Equal(t, config.QPS, float64(10), testcase.description)
+		assert.Equal(t, config.Burst, 20, testcase.description)
+	}
+}
+
+func Test_WebhookNotifier(t *testing.T) {
+	var body []byte
+	var token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		token = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	n, err := New(NotifierConfig{Name: "alerts", Type: WebhookType, URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}})
+	assert.NilError(t, err)
+	assert.NilError(t, n.Notify(notification))
+
+	var received Notification
+	assert.NilError(t, json.Unmarshal(body, &received))
+	assert.DeepEqual(t, received, notification)
+	assert.Equal(t, token, "Bearer token")
+
+	n, err = New(NotifierConfig{Name: "alerts", Type: WebhookType, URL: server.URL, Template: `{"resource": "{{ .Kind }}/{{ .Name }}"}`})
+	assert.NilError(t, err)
+	assert.NilError(t, n.Notify(notification))
+	assert.Equal(t, string(body), `{"resource": "Pod/nginx"}`)
+}
+
+func Test_SlackNotifier(t *testing.T) {
+	var message map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&message)
+	}))
+	defer server.Close()
+
+	n, err := New(NotifierConfig{Name: "team", Type: SlackType, URL: server.URL})
+	assert.NilError(t, err)
+	assert.NilError(t, n.Notify(notification))
+	assert.Equal(t, message["text"], "[PolicyViolation] Pod default/nginx: policy 'require-labels' (Validation) rule 'require-team' failed")
+}
+
+func Test_NotifierStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	n, err := New(NotifierConfig{Name: "alerts", Type: WebhookType, URL: server.URL})
+	assert.NilError(t, err)
+	assert.Error(t, n.Notify(notification), "failed to post notification: status 503")
+}
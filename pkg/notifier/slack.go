@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+)
+
+// slackNotifier posts the notifications to a Slack compatible incoming webhook
+type slackNotifier struct {
+	name     string
+	url      string
+	template *template.Template
+	client   *http.Client
+}
+
+func newSlackNotifier(config NotifierConfig, tmpl *template.Template) *slackNotifier {
+	return &slackNotifier{
+		name:     config.Name,
+		url:      config.URL,
+		template: tmpl,
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (s *slackNotifier) Name() string {
+	return s.name
+}
+
+func (s *slackNotifier) Notify(n Notification) error {
+	text, err := render(s.template, n)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack message: %v", err)
+	}
+
+	return post(s.client, s.url, nil, body)
+}
@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"fmt"
+	"log/syslog"
+	"sync"
+	"text/template"
+)
+
+const syslogTag = "kyverno"
+
+// syslogNotifier writes the notifications to a syslog server
+type syslogNotifier struct {
+	name     string
+	network  string
+	address  string
+	template *template.Template
+
+	mu     sync.Mutex
+	writer *syslog.Writer
+}
+
+func newSyslogNotifier(config NotifierConfig, tmpl *template.Template) *syslogNotifier {
+	return &syslogNotifier{
+		name:     config.Name,
+		network:  config.Network,
+		address:  config.Address,
+		template: tmpl,
+	}
+}
+
+func (s *syslogNotifier) Name() string {
+	return s.name
+}
+
+func (s *syslogNotifier) Notify(n Notification) error {
+	text, err := render(s.template, n)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the connection is established on the first notification, the writer reconnects on failures
+	if s.writer == nil {
+		writer, err := syslog.Dial(s.network, s.address, syslog.LOG_WARNING|syslog.LOG_DAEMON, syslogTag)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog %s: %v", s.address, err)
+		}
+		s.writer = writer
+	}
+
+	if err := s.writer.Warning(text); err != nil {
+		return fmt.Errorf("failed to write to syslog %s: %v", s.address, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"text/template"
+	"time"
+)
+
+const requestTimeout = 10 * time.Second
+
+// webhookNotifier posts the notifications to an HTTP endpoint
+type webhookNotifier struct {
+	name     string
+	url      string
+	headers  map[string]string
+	template *template.Template
+	client   *http.Client
+}
+
+func newWebhookNotifier(config NotifierConfig, tmpl *template.Template) *webhookNotifier {
+	return &webhookNotifier{
+		name:     config.Name,
+		url:      config.URL,
+		headers:  config.Headers,
+		template: tmpl,
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (w *webhookNotifier) Name() string {
+	return w.name
+}
+
+func (w *webhookNotifier) Notify(n Notification) error {
+	var body []byte
+	if w.template == nil {
+		data, err := json.Marshal(n)
+		if err != nil {
+			return fmt.Errorf("failed to marshal notification: %v", err)
+		}
+		body = data
+	} else {
+		text, err := render(w.template, n)
+		if err != nil {
+			return err
+		}
+		body = []byte(text)
+	}
+
+	return post(w.client, w.url, w.headers, body)
+}
+
+// post sends the body to the URL and fails on a non 2xx response
+func post(client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post notification: %v", err)
+	}
+
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to post notification: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package openapi
+
+import "fmt"
+
+// SchemaNotFound is returned when no OpenAPI model is available for a kind
+type SchemaNotFound struct {
+	kind string
+	err  error
+}
+
+func (e *SchemaNotFound) Error() string {
+	return fmt.Sprintf("pre-validation: couldn't find model %s, err: %v", e.kind, e.err)
+}
+
+//NewSchemaNotFound returns a new SchemaNotFound error
+func NewSchemaNotFound(kind string, err error) *SchemaNotFound {
+	return &SchemaNotFound{kind: kind, err: err}
+}
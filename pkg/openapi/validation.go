@@ -29,8 +29,14 @@ type Controller struct {
 	// kindToDefinitionName holds the kind - definition map
 	// i.e. - Namespace: io.k8s.api.core.v1.Namespace
 	kindToDefinitionName concurrentMap
-	crdList              []string
-	models               proto.Models
+	// gvkToDefinitionName holds the group version kind - definition map
+	// i.e. - apps/v1/Deployment: io.k8s.api.apps.v1.Deployment
+	gvkToDefinitionName concurrentMap
+	// gvkToNamespaced holds the group version kind - namespaced map of the resources of the paths
+	// i.e. - apps/v1/Deployment: true
+	gvkToNamespaced concurrentMap
+	crdList         []string
+	models          proto.Models
 }
 
 func newConcurrentMap() concurrentMap {
@@ -57,17 +63,25 @@ func (m concurrentMap) GetSchema(key string) *openapiv2.Schema {
 
 // NewOpenAPIController initializes a new instance of OpenAPIController
 func NewOpenAPIController() (*Controller, error) {
+	return NewOpenAPIControllerFromSchema([]byte(data.SwaggerDoc))
+}
+
+// NewOpenAPIControllerFromSchema initializes a new instance of OpenAPIController with the
+// OpenAPI v2 schema of a Kubernetes version, i.e. its api/openapi-spec/swagger.json
+func NewOpenAPIControllerFromSchema(schema []byte) (*Controller, error) {
 	controller := &Controller{
 		definitions:          newConcurrentMap(),
 		kindToDefinitionName: newConcurrentMap(),
+		gvkToDefinitionName:  newConcurrentMap(),
+		gvkToNamespaced:      newConcurrentMap(),
 	}
 
-	defaultDoc, err := getSchemaDocument()
+	doc, err := getSchemaDocument(schema)
 	if err != nil {
 		return nil, err
 	}
 
-	err = controller.useOpenAPIDocument(defaultDoc)
+	err = controller.useOpenAPIDocument(doc)
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +94,8 @@ func (o *Controller) ValidatePolicyFields(policy v1.ClusterPolicy) error {
 	return o.ValidatePolicyMutation(policy)
 }
 
-// ValidateResource ...
+// ValidateResource validates the resource against the OpenAPI schema of the kind.
+// A SchemaNotFound error is returned if no schema is known for the kind.
 func (o *Controller) ValidateResource(patchedResource unstructured.Unstructured, kind string) error {
 	var err error
 
@@ -90,7 +105,7 @@ func (o *Controller) ValidateResource(patchedResource unstructured.Unstructured,
 		// Check if kind is a CRD
 		schema, err = o.getCRDSchema(kind)
 		if err != nil || schema == nil {
-			return fmt.Errorf("pre-validation: couldn't find model %s, err: %v", kind, err)
+			return NewSchemaNotFound(kind, err)
 		}
 		delete(patchedResource.Object, "kind")
 	}
@@ -107,6 +122,48 @@ func (o *Controller) ValidateResource(patchedResource unstructured.Unstructured,
 	return nil
 }
 
+// ValidateResourceSchema validates the resource against the OpenAPI schema of its group, version
+// and kind. An error is returned if the kind is known but its version is not served, e.g. a removed
+// API version, and a SchemaNotFound error if no schema is known for the kind.
+func (o *Controller) ValidateResourceSchema(resource unstructured.Unstructured) error {
+	gvk := resource.GroupVersionKind()
+	definitionName := o.gvkToDefinitionName.GetKind(gvkKey(gvk.Group, gvk.Version, gvk.Kind))
+	if definitionName == "" {
+		if o.kindToDefinitionName.GetKind(gvk.Kind) == "" {
+			return NewSchemaNotFound(gvk.Kind, nil)
+		}
+		if o.gvkToDefinitionName.GetKind(gvkKey("", "", gvk.Kind)) == "" {
+			// only the kind of the custom resources is known
+			return o.ValidateResource(resource, gvk.Kind)
+		}
+		return fmt.Errorf("apiVersion %s is not served for kind %s", resource.GetAPIVersion(), gvk.Kind)
+	}
+
+	schema := o.models.LookupModel(definitionName)
+	if schema == nil {
+		return NewSchemaNotFound(gvk.Kind, nil)
+	}
+
+	if errs := validation.ValidateModel(resource.UnstructuredContent(), schema, definitionName); len(errs) > 0 {
+		var errorMessages []string
+		for i := range errs {
+			errorMessages = append(errorMessages, errs[i].Error())
+		}
+
+		return fmt.Errorf(strings.Join(errorMessages, "\n\n"))
+	}
+
+	return nil
+}
+
+// gvkKey returns the key of the group version kind, the key of the kind when the version is empty
+func gvkKey(group, version, kind string) string {
+	if version == "" {
+		return kind
+	}
+	return group + "/" + version + "/" + kind
+}
+
 // ValidatePolicyMutation ...
 func (o *Controller) ValidatePolicyMutation(policy v1.ClusterPolicy) error {
 	var kindToRules = make(map[string][]v1.Rule)
@@ -150,6 +207,28 @@ func (o *Controller) useOpenAPIDocument(doc *openapiv2.Document) error {
 		o.definitions.Set(definition.GetName(), definition.GetValue())
 		path := strings.Split(definition.GetName(), ".")
 		o.kindToDefinitionName.Set(path[len(path)-1], definition.GetName())
+
+		for _, gvk := range groupVersionKinds(definition.GetValue()) {
+			o.gvkToDefinitionName.Set(gvkKey(gvk.Group, gvk.Version, gvk.Kind), definition.GetName())
+			o.gvkToDefinitionName.Set(gvkKey("", "", gvk.Kind), definition.GetName())
+		}
+	}
+
+	for _, path := range doc.GetPaths().GetPath() {
+		// the namespaced resources are served in the namespaces, and in all the namespaces for the lists
+		namespaced := strings.Contains(path.GetName(), "/namespaces/{namespace}/")
+		item := path.GetValue()
+		for _, operation := range []*openapiv2.Operation{item.GetGet(), item.GetPut(), item.GetPost(), item.GetDelete(), item.GetPatch()} {
+			gvk, ok := operationGroupVersionKind(operation)
+			if !ok {
+				continue
+			}
+
+			key := gvkKey(gvk.Group, gvk.Version, gvk.Kind)
+			if current, ok := o.gvkToNamespaced.Get(key); !ok || (namespaced && !current.(bool)) {
+				o.gvkToNamespaced.Set(key, namespaced)
+			}
+		}
 	}
 
 	var err error
@@ -161,9 +240,67 @@ func (o *Controller) useOpenAPIDocument(doc *openapiv2.Document) error {
 	return nil
 }
 
-func getSchemaDocument() (*openapiv2.Document, error) {
+type groupVersionKind struct {
+	Group   string `yaml:"group"`
+	Version string `yaml:"version"`
+	Kind    string `yaml:"kind"`
+}
+
+// groupVersionKinds returns the group version kinds of the definition of the resources, the
+// x-kubernetes-group-version-kind extension
+func groupVersionKinds(schema *openapiv2.Schema) []groupVersionKind {
+	for _, extension := range schema.GetVendorExtension() {
+		if extension.GetName() != "x-kubernetes-group-version-kind" {
+			continue
+		}
+
+		var gvks []groupVersionKind
+		if err := yaml.Unmarshal([]byte(extension.GetValue().GetYaml()), &gvks); err != nil {
+			log.Log.V(4).Info("invalid group version kind extension", "error", err.Error())
+			return nil
+		}
+		return gvks
+	}
+
+	return nil
+}
+
+// operationGroupVersionKind returns the group version kind of the resources of the operation of a
+// path, the x-kubernetes-group-version-kind extension
+func operationGroupVersionKind(operation *openapiv2.Operation) (groupVersionKind, bool) {
+	for _, extension := range operation.GetVendorExtension() {
+		if extension.GetName() != "x-kubernetes-group-version-kind" {
+			continue
+		}
+
+		var gvk groupVersionKind
+		if err := yaml.Unmarshal([]byte(extension.GetValue().GetYaml()), &gvk); err != nil || gvk.Kind == "" {
+			return gvk, false
+		}
+		return gvk, true
+	}
+
+	return groupVersionKind{}, false
+}
+
+// IsNamespaced returns whether the resources of the kind are namespaced, and whether the kind is
+// served by the paths of the schemas, e.g. the custom resources are not
+func (o *Controller) IsNamespaced(apiVersion, kind string) (namespaced bool, found bool) {
+	group, version := "", apiVersion
+	if parts := strings.SplitN(apiVersion, "/", 2); len(parts) == 2 {
+		group, version = parts[0], parts[1]
+	}
+
+	value, ok := o.gvkToNamespaced.Get(gvkKey(group, version, kind))
+	if !ok {
+		return false, false
+	}
+	return value.(bool), true
+}
+
+func getSchemaDocument(schema []byte) (*openapiv2.Document, error) {
 	var spec yaml.Node
-	err := yaml.Unmarshal([]byte(data.SwaggerDoc), &spec)
+	err := yaml.Unmarshal(schema, &spec)
 	if err != nil {
 		return nil, err
 	}
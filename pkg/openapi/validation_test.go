@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func Test_ValidateMutationPolicy(t *testing.T) {
@@ -70,3 +72,66 @@ func Test_addDefaultFieldsToSchema(t *testing.T) {
 	addingDefaultFieldsToSchema([]byte(`null`))
 	addingDefaultFieldsToSchema(nil)
 }
+
+func Test_ValidateResource(t *testing.T) {
+	o, _ := NewOpenAPIController()
+
+	var valid, invalid, unknown unstructured.Unstructured
+	_ = json.Unmarshal([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"nginx"},"spec":{"containers":[{"name":"nginx","image":"nginx"}]}}`), &valid.Object)
+	_ = json.Unmarshal([]byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"nginx"},"spec":{"containers":[{"name":"nginx","image":"nginx","nonExistantField":"Always"}]}}`), &invalid.Object)
+	_ = json.Unmarshal([]byte(`{"apiVersion":"example.com/v1","kind":"Unknown","metadata":{"name":"test"}}`), &unknown.Object)
+
+	assert.NilError(t, o.ValidateResource(valid, "Pod"))
+
+	err := o.ValidateResource(invalid, "Pod")
+	assert.ErrorContains(t, err, `unknown field "nonExistantField"`)
+	_, ok := err.(*SchemaNotFound)
+	assert.Assert(t, !ok)
+
+	err = o.ValidateResource(unknown, "Unknown")
+	_, ok = err.(*SchemaNotFound)
+	assert.Assert(t, ok, "expected SchemaNotFound error, got %v", err)
+}
+
+func Test_ValidateResourceSchema(t *testing.T) {
+	o, err := NewOpenAPIController()
+	assert.NilError(t, err)
+
+	var valid, invalid, removed, unknown unstructured.Unstructured
+	_ = json.Unmarshal([]byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx"},"spec":{"selector":{"matchLabels":{"app":"nginx"}},"template":{"metadata":{"labels":{"app":"nginx"}},"spec":{"containers":[{"name":"nginx","image":"nginx"}]}}}}`), &valid.Object)
+	_ = json.Unmarshal([]byte(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"nginx"},"spec":{"replicas":"two","template":{"spec":{"containers":[{"name":"nginx","image":"nginx"}]}}}}`), &invalid.Object)
+	_ = json.Unmarshal([]byte(`{"apiVersion":"extensions/v1beta1","kind":"Deployment","metadata":{"name":"nginx"}}`), &removed.Object)
+	_ = json.Unmarshal([]byte(`{"apiVersion":"example.com/v1","kind":"Unknown","metadata":{"name":"test"}}`), &unknown.Object)
+
+	assert.NilError(t, o.ValidateResourceSchema(valid))
+	assert.ErrorContains(t, o.ValidateResourceSchema(invalid), "replicas")
+	assert.ErrorContains(t, o.ValidateResourceSchema(removed), "apiVersion extensions/v1beta1 is not served for kind Deployment")
+
+	err = o.ValidateResourceSchema(unknown)
+	_, ok := err.(*SchemaNotFound)
+	assert.Assert(t, ok, "expected SchemaNotFound error, got %v", err)
+}
+
+func Test_IsNamespaced(t *testing.T) {
+	o, err := NewOpenAPIController()
+	assert.NilError(t, err)
+
+	testcases := []struct {
+		apiVersion string
+		kind       string
+		namespaced bool
+		found      bool
+	}{
+		{apiVersion: "v1", kind: "Pod", namespaced: true, found: true},
+		{apiVersion: "apps/v1", kind: "Deployment", namespaced: true, found: true},
+		{apiVersion: "v1", kind: "Namespace", found: true},
+		{apiVersion: "rbac.authorization.k8s.io/v1", kind: "ClusterRole", found: true},
+		{apiVersion: "example.com/v1", kind: "Widget"},
+	}
+
+	for _, tc := range testcases {
+		namespaced, found := o.IsNamespaced(tc.apiVersion, tc.kind)
+		assert.Equal(t, namespaced, tc.namespaced, tc.kind)
+		assert.Equal(t, found, tc.found, tc.kind)
+	}
+}
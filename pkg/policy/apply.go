@@ -14,6 +14,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/engine/response"
+	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
 	"github.com/kyverno/kyverno/pkg/utils"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -22,7 +23,7 @@ import (
 // applyPolicy applies policy on a resource
 func applyPolicy(policy kyverno.ClusterPolicy, resource unstructured.Unstructured,
 	logger logr.Logger, excludeGroupRole []string, resCache resourcecache.ResourceCache,
-	client *client.Client, namespaceLabels map[string]string) (responses []*response.EngineResponse) {
+	client *client.Client, namespaceLabels, namespaceAnnotations map[string]string) (responses []*response.EngineResponse) {
 
 	startTime := time.Now()
 	defer func() {
@@ -39,6 +40,17 @@ func applyPolicy(policy kyverno.ClusterPolicy, resource unstructured.Unstructure
 	var engineResponseMutation, engineResponseValidation *response.EngineResponse
 	var err error
 
+	// the rules which reference the data of the admission requests are not applied
+	admissionOnlyRules, err := AdmissionOnlyRules(policy)
+	if err != nil {
+		logger.Error(err, "failed to find the rules which reference the admission requests")
+	}
+
+	var skippedRules []kyverno.Rule
+	if len(admissionOnlyRules) > 0 {
+		policy, skippedRules = withoutRules(policy, admissionOnlyRules)
+	}
+
 	ctx := context.NewContext()
 	err = ctx.AddResource(transformResource(resource))
 	if err != nil {
@@ -50,6 +62,11 @@ func applyPolicy(policy kyverno.ClusterPolicy, resource unstructured.Unstructure
 		logger.Error(err, "failed to add namespace to ctx")
 	}
 
+	err = ctx.AddNamespaceMetadata(namespaceLabels, namespaceAnnotations)
+	if err != nil {
+		logger.Error(err, "failed to add namespace metadata to ctx")
+	}
+
 	engineResponseMutation, err = mutation(policy, resource, logger, resCache, ctx, namespaceLabels)
 	if err != nil {
 		logger.Error(err, "failed to process mutation rule")
@@ -66,11 +83,79 @@ func applyPolicy(policy kyverno.ClusterPolicy, resource unstructured.Unstructure
 	}
 
 	engineResponseValidation = engine.Validate(policyCtx)
-	engineResponses = append(engineResponses, mergeRuleRespose(engineResponseMutation, engineResponseValidation))
+	engineResponse := mergeRuleRespose(engineResponseMutation, engineResponseValidation)
+	addSkippedRules(engineResponse, policy, resource, skippedRules, excludeGroupRole, namespaceLabels)
+	engineResponses = append(engineResponses, engineResponse)
 
 	return engineResponses
 }
 
+// withoutRules returns a copy of the policy without the rules, and the removed rules
+func withoutRules(policy kyverno.ClusterPolicy, ruleNames map[string]bool) (kyverno.ClusterPolicy, []kyverno.Rule) {
+	policyCopy := *policy.DeepCopy()
+	policyCopy.Spec.Rules = nil
+
+	var removed []kyverno.Rule
+	for _, rule := range policy.Spec.Rules {
+		if ruleNames[rule.Name] {
+			removed = append(removed, rule)
+			continue
+		}
+		policyCopy.Spec.Rules = append(policyCopy.Spec.Rules, rule)
+	}
+
+	return policyCopy, removed
+}
+
+// addSkippedRules adds a skipped result for each validation rule which matches the resource, the
+// user info of the rules is not matched as it is only known from the admission requests
+func addSkippedRules(engineResponse *response.EngineResponse, policy kyverno.ClusterPolicy, resource unstructured.Unstructured,
+	skippedRules []kyverno.Rule, excludeGroupRole []string, namespaceLabels map[string]string) {
+	for _, rule := range skippedRules {
+		if !rule.HasValidate() {
+			continue
+		}
+
+		rule.MatchResources.UserInfo = kyverno.UserInfo{}
+		rule.ExcludeResources.UserInfo = kyverno.UserInfo{}
+		if err := engine.MatchesResourceDescription(resource, rule, kyverno.RequestInfo{}, excludeGroupRole, namespaceLabels); err != nil {
+			continue
+		}
+
+		if engineResponse.PolicyResponse.Resource.Name == "" {
+			engineResponse.PolicyResponse.Policy = policy.Name
+			engineResponse.PolicyResponse.Resource = response.ResourceSpec{
+				Kind:       resource.GetKind(),
+				APIVersion: resource.GetAPIVersion(),
+				Namespace:  resource.GetNamespace(),
+				Name:       resource.GetName(),
+				UID:        string(resource.GetUID()),
+			}
+		}
+
+		severity := rule.Severity
+		if severity == "" {
+			severity = policy.GetRuleSeverity(rule.Name)
+		}
+
+		engineResponse.PolicyResponse.Rules = append(engineResponse.PolicyResponse.Rules, response.RuleResponse{
+			Name:     rule.Name,
+			Type:     engineutils.Validation.String(),
+			Message:  "rule skipped in background mode, it references the information of the admission requests",
+			Success:  true,
+			Skipped:  true,
+			Severity: severity,
+		})
+	}
+}
+
+// ApplyPolicy applies the policy on an existing resource as the background scan does, without
+// the resource cache, e.g. to scan the resources of a cluster from the CLI
+func ApplyPolicy(policy kyverno.ClusterPolicy, resource unstructured.Unstructured, logger logr.Logger,
+	client *client.Client, namespaceLabels, namespaceAnnotations map[string]string) []*response.EngineResponse {
+	return applyPolicy(policy, resource, logger, nil, nil, client, namespaceLabels, namespaceAnnotations)
+}
+
 func mutation(policy kyverno.ClusterPolicy, resource unstructured.Unstructured, log logr.Logger, resCache resourcecache.ResourceCache, jsonContext *context.Context, namespaceLabels map[string]string) (*response.EngineResponse, error) {
 
 	policyContext := &engine.PolicyContext{
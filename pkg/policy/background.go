@@ -12,10 +12,35 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-//ContainsVariablesOtherThanObject returns error if variable that does not start from request.object
-func ContainsVariablesOtherThanObject(policy kyverno.ClusterPolicy) error {
-	var err error
+// admissionOnlyVars are the variables of the admission requests which are not available to the
+// background scans of the existing resources
+var admissionOnlyVars = []string{"request.userInfo", "request.roles", "request.clusterRoles", "request.oldObject", "request.operation", "serviceAccountName", "serviceAccountNamespace"}
+
+// AdmissionOnlyRules returns the names of the rules which reference the data of the admission
+// requests, i.e. the user info of the match and exclude blocks or the variables of the admission
+// requests such as request.userInfo and request.oldObject. These rules are skipped by the
+// background scans. It returns an error when the variables of a rule are not valid.
+func AdmissionOnlyRules(policy kyverno.ClusterPolicy) (map[string]bool, error) {
+	rules := make(map[string]bool)
 	for idx, rule := range policy.Spec.Rules {
+		if err := ruleContainsVariablesOtherThanObject(policy, idx, rule, true); err != nil {
+			return nil, err
+		}
+
+		if err := ruleContainsVariablesOtherThanObject(policy, idx, rule, false); err != nil {
+			rules[rule.Name] = true
+		}
+	}
+
+	return rules, nil
+}
+
+// ruleContainsVariablesOtherThanObject returns error if the rule uses a variable that does not start
+// from request.object, the variables of the admission requests are allowed when allowAdmissionVars is set
+func ruleContainsVariablesOtherThanObject(policy kyverno.ClusterPolicy, idx int, rule kyverno.Rule, allowAdmissionVars bool) error {
+	var err error
+
+	if !allowAdmissionVars {
 		if path := userInfoDefined(rule.MatchResources.UserInfo); path != "" {
 			return fmt.Errorf("invalid variable used at path: spec/rules[%d]/match/%s", idx, path)
 		}
@@ -23,99 +48,208 @@ func ContainsVariablesOtherThanObject(policy kyverno.ClusterPolicy) error {
 		if path := userInfoDefined(rule.ExcludeResources.UserInfo); path != "" {
 			return fmt.Errorf("invalid variable used at path: spec/rules[%d]/exclude/%s", idx, path)
 		}
+	}
 
-		filterVars := []string{"request.object", "request.namespace"}
-		ctx := context.NewContext(filterVars...)
+	filterVars := []string{"request.object", "request.namespace", "namespaceLabels", "namespaceAnnotations"}
+	if allowAdmissionVars {
+		filterVars = append(filterVars, admissionOnlyVars...)
+	}
+	ctx := context.NewContext(filterVars...)
+	for _, variable := range policy.Spec.Variables {
+		ctx.AddBuiltInVars(variable.Name)
+	}
 
-		for contextIdx, contextEntry := range rule.Context {
-			if contextEntry.APICall != nil {
-				ctx.AddBuiltInVars(contextEntry.Name)
+	for contextIdx, contextEntry := range rule.Context {
+		if contextEntry.APICall != nil {
+			ctx.AddBuiltInVars(contextEntry.Name)
 
-				if _, err := variables.SubstituteVars(log.Log, ctx, contextEntry.APICall.URLPath); !checkNotFoundErr(err) {
-					return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/apiCall/urlPath: %s", idx, contextIdx, err.Error())
-				}
+			if _, err := variables.SubstituteVars(log.Log, ctx, contextEntry.APICall.URLPath); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/apiCall/urlPath: %s", idx, contextIdx, err.Error())
+			}
 
-				if _, err := variables.SubstituteVars(log.Log, ctx, contextEntry.APICall.JMESPath); !checkNotFoundErr(err) {
-					return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/apiCall/jmesPath: %s", idx, contextIdx, err.Error())
-				}
+			if _, err := variables.SubstituteVars(log.Log, ctx, contextEntry.APICall.JMESPath); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/apiCall/jmesPath: %s", idx, contextIdx, err.Error())
 			}
+		}
 
-			if contextEntry.ConfigMap != nil {
-				ctx.AddBuiltInVars(contextEntry.Name)
+		if contextEntry.ImageRegistry != nil {
+			ctx.AddBuiltInVars(contextEntry.Name)
 
-				if _, err = variables.SubstituteVars(log.Log, ctx, contextEntry.ConfigMap.Name); !checkNotFoundErr(err) {
-					return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/configMap/name: %s", idx, contextIdx, err.Error())
-				}
+			if _, err := variables.SubstituteVars(log.Log, ctx, contextEntry.ImageRegistry.Reference); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/imageRegistry/reference: %s", idx, contextIdx, err.Error())
+			}
 
-				if _, err = variables.SubstituteVars(log.Log, ctx, contextEntry.ConfigMap.Namespace); !checkNotFoundErr(err) {
-					return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/configMap/namespace: %s", idx, contextIdx, err.Error())
-				}
+			if _, err := variables.SubstituteVars(log.Log, ctx, contextEntry.ImageRegistry.JMESPath); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/imageRegistry/jmesPath: %s", idx, contextIdx, err.Error())
 			}
 		}
 
-		if rule.AnyAllConditions != nil {
-			if err = validatePreConditions(idx, ctx, rule.AnyAllConditions); err != nil {
-				return err
+		if contextEntry.ServiceCall != nil {
+			ctx.AddBuiltInVars(contextEntry.Name)
+
+			if _, err := variables.SubstituteVars(log.Log, ctx, contextEntry.ServiceCall.URL); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/serviceCall/url: %s", idx, contextIdx, err.Error())
+			}
+
+			if _, err := variables.SubstituteVars(log.Log, ctx, contextEntry.ServiceCall.JMESPath); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/serviceCall/jmesPath: %s", idx, contextIdx, err.Error())
 			}
 		}
 
-		if rule.Mutation.Overlay != nil {
-			if rule.Mutation.Overlay, err = variables.SubstituteVars(log.Log, ctx, rule.Mutation.Overlay); !checkNotFoundErr(err) {
-				return fmt.Errorf("invalid variable used at spec/rules[%d]/mutate/overlay: %s", idx, err.Error())
+		if contextEntry.GlobalReference != nil {
+			ctx.AddBuiltInVars(contextEntry.Name)
+
+			if _, err := variables.SubstituteVars(log.Log, ctx, contextEntry.GlobalReference.JMESPath); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/globalReference/jmesPath: %s", idx, contextIdx, err.Error())
 			}
 		}
 
-		if rule.Mutation.PatchStrategicMerge != nil {
-			if rule.Mutation.Overlay, err = variables.SubstituteVars(log.Log, ctx, rule.Mutation.PatchStrategicMerge); !checkNotFoundErr(err) {
-				return fmt.Errorf("invalid variable used at spec/rules[%d]/mutate/patchStrategicMerge: %s", idx, err.Error())
+		if contextEntry.ConfigMap != nil {
+			ctx.AddBuiltInVars(contextEntry.Name)
+
+			if _, err = variables.SubstituteVars(log.Log, ctx, contextEntry.ConfigMap.Name); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/configMap/name: %s", idx, contextIdx, err.Error())
+			}
+
+			if _, err = variables.SubstituteVars(log.Log, ctx, contextEntry.ConfigMap.Namespace); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/configMap/namespace: %s", idx, contextIdx, err.Error())
 			}
 		}
 
-		if rule.Validation.Pattern != nil {
-			if rule.Validation.Pattern, err = variables.SubstituteVars(log.Log, ctx, rule.Validation.Pattern); !checkNotFoundErr(err) {
-				return fmt.Errorf("invalid variable used at spec/rules[%d]/validate/pattern: %s", idx, err.Error())
+		if contextEntry.Secret != nil {
+			ctx.AddBuiltInVars(contextEntry.Name)
+
+			if _, err = variables.SubstituteVars(log.Log, ctx, contextEntry.Secret.Name); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/secret/name: %s", idx, contextIdx, err.Error())
+			}
+
+			if _, err = variables.SubstituteVars(log.Log, ctx, contextEntry.Secret.Namespace); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/context[%d]/secret/namespace: %s", idx, contextIdx, err.Error())
 			}
 		}
+	}
 
-		anyPattern, err := rule.Validation.DeserializeAnyPattern()
-		if err != nil {
-			return fmt.Errorf("failed to deserialize anyPattern, expect array: %s", err.Error())
+	if rule.AnyAllConditions != nil {
+		if err = validatePreConditions(idx, ctx, rule.AnyAllConditions); err != nil {
+			return err
 		}
+	}
 
-		for idx2, pattern := range anyPattern {
-			if anyPattern[idx2], err = variables.SubstituteVars(log.Log, ctx, pattern); !checkNotFoundErr(err) {
-				return fmt.Errorf("invalid variable used at spec/rules[%d]/validate/anyPattern[%d]: %s", idx, idx2, err.Error())
+	for targetIdx, target := range rule.Mutation.Targets {
+		for _, field := range []string{target.APIVersion, target.Kind, target.Namespace, target.Name} {
+			if _, err = variables.SubstituteVars(log.Log, ctx, field); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/mutate/targets[%d]: %s", idx, targetIdx, err.Error())
 			}
 		}
+	}
+
+	if rule.HasMutateExisting() {
+		ctx.AddBuiltInVars("target")
+	}
 
-		if _, err = variables.SubstituteVars(log.Log, ctx, rule.Validation.Message); !checkNotFoundErr(err) {
-			return fmt.Errorf("invalid variable used at spec/rules[%d]/validate/message: %s", idx, err.Error())
+	if rule.Mutation.Overlay != nil {
+		if rule.Mutation.Overlay, err = variables.SubstituteVars(log.Log, ctx, rule.Mutation.Overlay); !checkNotFoundErr(err) {
+			return fmt.Errorf("invalid variable used at spec/rules[%d]/mutate/overlay: %s", idx, err.Error())
 		}
+	}
 
-		if rule.Validation.Deny != nil {
-			if err = validateDenyConditions(idx, ctx, rule.Validation.Deny.AnyAllConditions); err != nil {
-				return err
+	if rule.Mutation.PatchStrategicMerge != nil {
+		if rule.Mutation.Overlay, err = variables.SubstituteVars(log.Log, ctx, rule.Mutation.PatchStrategicMerge); !checkNotFoundErr(err) {
+			return fmt.Errorf("invalid variable used at spec/rules[%d]/mutate/patchStrategicMerge: %s", idx, err.Error())
+		}
+	}
+
+	if foreach := rule.Mutation.ForEachMutation; foreach != nil {
+		ctx.AddBuiltInVars("element")
+
+		if foreach.PatchStrategicMerge != nil {
+			if _, err = variables.SubstituteVars(log.Log, ctx, foreach.PatchStrategicMerge); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/mutate/foreach/patchStrategicMerge: %s", idx, err.Error())
 			}
 		}
 
-		if _, err = variables.SubstituteVars(log.Log, ctx, rule.Generation.Name); !checkNotFoundErr(err) {
-			return fmt.Errorf("invalid variable used at spec/rules[%d]/generate/name: %v", idx, err)
+		if _, err = variables.SubstituteVars(log.Log, ctx, foreach.PatchesJSON6902); !checkNotFoundErr(err) {
+			return fmt.Errorf("invalid variable used at spec/rules[%d]/mutate/foreach/patchesJson6902: %s", idx, err.Error())
+		}
+	}
+
+	if rule.Validation.Pattern != nil {
+		if rule.Validation.Pattern, err = variables.SubstituteVars(log.Log, ctx, rule.Validation.Pattern); !checkNotFoundErr(err) {
+			return fmt.Errorf("invalid variable used at spec/rules[%d]/validate/pattern: %s", idx, err.Error())
+		}
+	}
+
+	anyPattern, err := rule.Validation.DeserializeAnyPattern()
+	if err != nil {
+		return fmt.Errorf("failed to deserialize anyPattern, expect array: %s", err.Error())
+	}
+
+	for idx2, pattern := range anyPattern {
+		if anyPattern[idx2], err = variables.SubstituteVars(log.Log, ctx, pattern); !checkNotFoundErr(err) {
+			return fmt.Errorf("invalid variable used at spec/rules[%d]/validate/anyPattern[%d]: %s", idx, idx2, err.Error())
 		}
+	}
+
+	if _, err = variables.SubstituteVars(log.Log, ctx, rule.Validation.Message); !checkNotFoundErr(err) {
+		return fmt.Errorf("invalid variable used at spec/rules[%d]/validate/message: %s", idx, err.Error())
+	}
 
-		if _, err = variables.SubstituteVars(log.Log, ctx, rule.Generation.Namespace); !checkNotFoundErr(err) {
-			return fmt.Errorf("invalid variable used at spec/rules[%d]/generate/name: %v", idx, err)
+	if rule.Validation.Deny != nil {
+		if err = validateDenyConditions(idx, ctx, rule.Validation.Deny.AnyAllConditions); err != nil {
+			return err
 		}
+	}
+
+	if foreach := rule.Validation.ForEachValidation; foreach != nil {
+		ctx.AddBuiltInVars("element")
 
-		if _, err = variables.SubstituteVars(log.Log, ctx, rule.Generation.Data); !checkNotFoundErr(err) {
-			return fmt.Errorf("invalid variable used at spec/rules[%d]/generate/data: %v", idx, err)
+		if foreach.Pattern != nil {
+			if _, err = variables.SubstituteVars(log.Log, ctx, foreach.Pattern); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/validate/foreach/pattern: %s", idx, err.Error())
+			}
 		}
 
-		if _, err = variables.SubstituteVars(log.Log, ctx, rule.Generation.Clone.Name); !checkNotFoundErr(err) {
-			return fmt.Errorf("invalid variable used at spec/rules[%d]/generate/clone/name: %v", idx, err)
+		anyPattern, err := foreach.ElementValidation("").DeserializeAnyPattern()
+		if err != nil {
+			return fmt.Errorf("failed to deserialize foreach anyPattern, expect array: %s", err.Error())
 		}
 
-		if _, err = variables.SubstituteVars(log.Log, ctx, rule.Generation.Clone.Namespace); !checkNotFoundErr(err) {
-			return fmt.Errorf("invalid variable used at spec/rules[%d]/generate/clone/namespace: %v", idx, err)
+		for idx2, pattern := range anyPattern {
+			if _, err = variables.SubstituteVars(log.Log, ctx, pattern); !checkNotFoundErr(err) {
+				return fmt.Errorf("invalid variable used at spec/rules[%d]/validate/foreach/anyPattern[%d]: %s", idx, idx2, err.Error())
+			}
+		}
+
+		if foreach.Deny != nil {
+			if err = validateDenyConditions(idx, ctx, foreach.Deny.AnyAllConditions); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err = variables.SubstituteVars(log.Log, ctx, rule.Generation.Name); !checkNotFoundErr(err) {
+		return fmt.Errorf("invalid variable used at spec/rules[%d]/generate/name: %v", idx, err)
+	}
+
+	if _, err = variables.SubstituteVars(log.Log, ctx, rule.Generation.Namespace); !checkNotFoundErr(err) {
+		return fmt.Errorf("invalid variable used at spec/rules[%d]/generate/name: %v", idx, err)
+	}
+
+	if _, err = variables.SubstituteVars(log.Log, ctx, rule.Generation.Data); !checkNotFoundErr(err) {
+		return fmt.Errorf("invalid variable used at spec/rules[%d]/generate/data: %v", idx, err)
+	}
+
+	if _, err = variables.SubstituteVars(log.Log, ctx, rule.Generation.Clone.Name); !checkNotFoundErr(err) {
+		return fmt.Errorf("invalid variable used at spec/rules[%d]/generate/clone/name: %v", idx, err)
+	}
+
+	if _, err = variables.SubstituteVars(log.Log, ctx, rule.Generation.Clone.Namespace); !checkNotFoundErr(err) {
+		return fmt.Errorf("invalid variable used at spec/rules[%d]/generate/clone/namespace: %v", idx, err)
+	}
+
+	if rule.Generation.CloneList != nil {
+		if _, err = variables.SubstituteVars(log.Log, ctx, rule.Generation.CloneList.Namespace); !checkNotFoundErr(err) {
+			return fmt.Errorf("invalid variable used at spec/rules[%d]/generate/cloneList/namespace: %v", idx, err)
 		}
 	}
 
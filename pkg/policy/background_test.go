@@ -0,0 +1,89 @@
+package policy
+
+import (
+	"encoding/json"
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/openapi"
+	"github.com/kyverno/kyverno/pkg/policyreport"
+	"gotest.tools/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var admissionOnlyPolicy = []byte(`{
+	"apiVersion": "kyverno.io/v1",
+	"kind": "ClusterPolicy",
+	"metadata": {"name": "require-labels"},
+	"spec": {
+		"rules": [
+			{
+				"name": "check-team",
+				"match": {"resources": {"kinds": ["Pod"]}},
+				"validate": {"message": "the team label is required", "pattern": {"metadata": {"labels": {"team": "?*"}}}}
+			},
+			{
+				"name": "check-owner",
+				"match": {"resources": {"kinds": ["Pod"]}},
+				"validate": {"message": "the owner label is the user", "pattern": {"metadata": {"labels": {"owner": "{{request.userInfo.username}}"}}}}
+			},
+			{
+				"name": "check-admins",
+				"match": {"resources": {"kinds": ["Pod"]}, "clusterRoles": ["cluster-admin"]},
+				"validate": {"message": "the admin label is required", "pattern": {"metadata": {"labels": {"admin": "?*"}}}}
+			},
+			{
+				"name": "check-services",
+				"match": {"resources": {"kinds": ["Service"]}},
+				"validate": {"message": "the previous type is kept", "pattern": {"spec": {"type": "{{request.oldObject.spec.type}}"}}}
+			}
+		]
+	}
+}`)
+
+func Test_AdmissionOnlyRules(t *testing.T) {
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(admissionOnlyPolicy, &policy))
+
+	rules, err := AdmissionOnlyRules(policy)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, rules, map[string]bool{"check-owner": true, "check-admins": true, "check-services": true})
+
+	// the policy is valid in background mode, the admission only rules are skipped
+	openAPIController, err := openapi.NewOpenAPIController()
+	assert.NilError(t, err)
+	assert.NilError(t, Validate(&policy, nil, true, openAPIController))
+
+	policy.Spec.Rules[0].Validation.Message = "{{ request.unknown }}"
+	_, err = AdmissionOnlyRules(policy)
+	assert.ErrorContains(t, err, "invalid variable used at spec/rules[0]/validate/message")
+}
+
+func Test_ApplyPolicySkipsAdmissionOnlyRules(t *testing.T) {
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(admissionOnlyPolicy, &policy))
+
+	resource, err := engineutils.ConvertToUnstructured([]byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {"name": "nginx", "namespace": "default", "labels": {"team": "payments"}},
+		"spec": {"containers": [{"name": "nginx", "image": "nginx"}]}
+	}`))
+	assert.NilError(t, err)
+
+	responses := ApplyPolicy(policy, *resource, log.Log, nil, nil, nil)
+	assert.Equal(t, len(responses), 1)
+
+	checks := map[string]string{}
+	for _, info := range policyreport.GeneratePRsFromEngineResponse(responses, log.Log) {
+		for _, result := range info.Results {
+			for _, rule := range result.Rules {
+				checks[rule.Name] = rule.Check
+			}
+		}
+	}
+
+	// the rule matching the services is not reported for the pod
+	assert.DeepEqual(t, checks, map[string]string{"check-team": "pass", "check-owner": "skip", "check-admins": "skip"})
+}
@@ -1,6 +1,7 @@
 package policy
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -159,36 +160,42 @@ func GetAllNamespaces(nslister listerv1.NamespaceLister, log logr.Logger) []stri
 	return results
 }
 
+// getResourceList lists the resources from the informer cache of the kind if there is one, otherwise
+// from the API server in pages, with the concurrency and the rate of the list requests limited
 func (pc *PolicyController) getResourceList(kind, namespace string, labelSelector *metav1.LabelSelector, log logr.Logger) interface{} {
-	list, err := func() (list []*unstructured.Unstructured, err error) {
-		var selector labels.Selector
-		if labelSelector == nil {
-			selector = labels.Everything()
-		} else {
-			if selector, err = metav1.LabelSelectorAsSelector(labelSelector); err != nil {
-				return nil, err
+	if genericCache, ok := pc.resCache.GetGVRCache(kind); ok {
+		list, err := func() (list []*unstructured.Unstructured, err error) {
+			var selector labels.Selector
+			if labelSelector == nil {
+				selector = labels.Everything()
+			} else {
+				if selector, err = metav1.LabelSelectorAsSelector(labelSelector); err != nil {
+					return nil, err
+				}
 			}
-		}
 
-		genericCache, _ := pc.resCache.GetGVRCache(kind)
+			if namespace != "" {
+				list, err = genericCache.NamespacedLister(namespace).List(selector)
+			} else {
+				list, err = genericCache.Lister().List(selector)
+			}
+			return list, err
+		}()
 
-		if namespace != "" {
-			list, err = genericCache.NamespacedLister(namespace).List(selector)
-		} else {
-			list, err = genericCache.Lister().List(selector)
+		if err == nil {
+			return list
 		}
-		return list, err
-	}()
-
-	if err != nil {
 		log.V(3).Info("failed to list resource using lister, try to query from the API server", "err", err.Error())
-	} else {
-		return list
 	}
 
-	resourceList, err := pc.client.ListResource("", kind, namespace, labelSelector)
+	if pc.listLimiter != nil {
+		pc.listLimiter <- struct{}{}
+		defer func() { <-pc.listLimiter }()
+	}
+
+	resourceList, err := pc.client.ListResourceInPages(context.TODO(), "", kind, namespace, labelSelector, pc.scanOptions.ListPageSize, pc.listRateLimiter)
 	if err != nil {
-		log.Error(err, "failed to list resources", "kind")
+		log.Error(err, "failed to list resources", "kind", kind)
 		return nil
 	}
 
@@ -14,7 +14,9 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
-func (pc *PolicyController) processExistingResources(policy *kyverno.ClusterPolicy) {
+// processExistingResources applies the policy to the existing resources matched by the rules,
+// by all the validation rules of the policy when the rules are nil
+func (pc *PolicyController) processExistingResources(policy *kyverno.ClusterPolicy, rules map[string]bool) {
 	logger := pc.log.WithValues("policy", policy.Name)
 	logger.V(4).Info("applying policy to existing resources")
 
@@ -26,11 +28,15 @@ func (pc *PolicyController) processExistingResources(policy *kyverno.ClusterPoli
 			continue
 		}
 
+		if rules != nil && !rules[rule.Name] {
+			continue
+		}
+
 		for _, k := range rule.MatchResources.Kinds {
 			logger = logger.WithValues("rule", rule.Name, "kind", k)
 			namespaced, err := pc.rm.GetScope(k)
 			if err != nil {
-				if err := pc.registerResource(k); err != nil {
+				if err := pc.registerScope(k); err != nil {
 					logger.Error(err, "failed to find resource", "kind", k)
 					continue
 				}
@@ -44,21 +50,87 @@ func (pc *PolicyController) processExistingResources(policy *kyverno.ClusterPoli
 			}
 
 			namespaces := pc.getNamespacesForRule(&rule, logger.WithValues("kind", k))
-			for _, ns := range namespaces {
+			pc.scanNamespaces(namespaces, func(ns string) {
 				pc.applyAndReportPerNamespace(policy, k, ns, rule, logger.WithValues("kind", k).WithValues("ns", ns))
-			}
+			})
 		}
 	}
 }
 
-func (pc *PolicyController) registerResource(gvk string) (err error) {
-	genericCache, ok := pc.resCache.GetGVRCache(gvk)
-	if !ok {
-		if genericCache, err = pc.resCache.CreateGVKInformer(gvk); err != nil {
-			return fmt.Errorf("failed to create informer for %s: %v", gvk, err)
+// ScanOptions configures the background scans of the existing resources
+type ScanOptions struct {
+	// Interval is the interval of the scans, the resources are only scanned when the policies
+	// change when it is 0
+	Interval time.Duration
+
+	// KindConcurrency is the number of namespaces scanned concurrently for each kind of a rule,
+	// the namespaces are scanned one by one when it is 0 or 1
+	KindConcurrency int
+
+	// MaxListCalls is the maximum number of concurrent list requests to the API server, for the
+	// resources which are not in the informer caches, the requests are not limited when it is 0
+	MaxListCalls int
+
+	// ListPageSize is the number of resources of each page of the list requests to the API server,
+	// the resources are listed at once when it is 0
+	ListPageSize int64
+
+	// ListQPS and ListBurst limit the rate of the list requests to the API server, a request per
+	// page, the rate is not limited when the QPS is 0
+	ListQPS   float64
+	ListBurst int
+}
+
+// scanNamespaces scans the namespaces with the kind concurrency workers and returns once all the
+// namespaces are scanned
+func (pc *PolicyController) scanNamespaces(namespaces []string, scan func(ns string)) {
+	workers := pc.scanOptions.KindConcurrency
+	if workers > len(namespaces) {
+		workers = len(namespaces)
+	}
+
+	if workers <= 1 {
+		for _, ns := range namespaces {
+			scan(ns)
 		}
+		return
+	}
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ns := range queue {
+				scan(ns)
+			}
+		}()
+	}
+
+	for _, ns := range namespaces {
+		queue <- ns
 	}
-	pc.rm.RegisterScope(gvk, genericCache.IsNamespaced())
+	close(queue)
+	wg.Wait()
+}
+
+// registerScope registers the scope of the kind. No informer is created for the scanned kinds, the
+// resources of the kinds without informer cache are listed from the API server with the list limits
+// of the scan options.
+func (pc *PolicyController) registerScope(gvk string) error {
+	if genericCache, ok := pc.resCache.GetGVRCache(gvk); ok {
+		pc.rm.RegisterScope(gvk, genericCache.IsNamespaced())
+		return nil
+	}
+
+	gv, k := common.GetKindFromGVK(gvk)
+	apiResource, _, err := pc.client.DiscoveryClient.FindResource(gv, k)
+	if err != nil {
+		return fmt.Errorf("failed to find resource %s: %v", gvk, err)
+	}
+
+	pc.rm.RegisterScope(gvk, apiResource.Namespaced)
 	return nil
 }
 
@@ -75,7 +147,7 @@ func (pc *PolicyController) applyAndReportPerNamespace(policy *kyverno.ClusterPo
 		engineResponses = append(engineResponses, responses...)
 	}
 
-	pc.report(policy.Name, engineResponses, logger)
+	pc.report(policy, engineResponses, logger)
 }
 
 func (pc *PolicyController) applyPolicy(policy *kyverno.ClusterPolicy, resource unstructured.Unstructured, logger logr.Logger) (engineResponses []*response.EngineResponse) {
@@ -85,7 +157,8 @@ func (pc *PolicyController) applyPolicy(policy *kyverno.ClusterPolicy, resource
 	}
 
 	namespaceLabels := common.GetNamespaceSelectorsFromNamespaceLister(resource.GetKind(), resource.GetNamespace(), pc.nsLister, logger)
-	engineResponse := applyPolicy(*policy, resource, logger, pc.configHandler.GetExcludeGroupRole(), pc.resCache, pc.client, namespaceLabels)
+	namespaceAnnotations := common.GetNamespaceAnnotationsFromNamespaceLister(resource.GetKind(), resource.GetNamespace(), pc.nsLister, logger)
+	engineResponse := applyPolicy(*policy, resource, logger, pc.configHandler.GetExcludeGroupRole(), pc.resCache, pc.client, namespaceLabels, namespaceAnnotations)
 	engineResponses = append(engineResponses, engineResponse...)
 
 	// post-processing, register the resource as processed
@@ -0,0 +1,214 @@
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/config"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/event"
+	"github.com/kyverno/kyverno/pkg/policyreport"
+	"github.com/kyverno/kyverno/pkg/resourcecache"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_ScanNamespaces(t *testing.T) {
+	namespaces := []string{"default", "dev", "kube-system", "prod", "staging"}
+
+	for _, kindConcurrency := range []int{0, 1, 2, 10} {
+		pc := &PolicyController{scanOptions: ScanOptions{KindConcurrency: kindConcurrency}}
+
+		var mutex sync.Mutex
+		var scanned []string
+		running, maxRunning := 0, 0
+		pc.scanNamespaces(namespaces, func(ns string) {
+			mutex.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mutex.Lock()
+			running--
+			scanned = append(scanned, ns)
+			mutex.Unlock()
+		})
+
+		sort.Strings(scanned)
+		assert.DeepEqual(t, scanned, namespaces)
+
+		expected := kindConcurrency
+		if expected < 1 {
+			expected = 1
+		}
+		if expected > len(namespaces) {
+			expected = len(namespaces)
+		}
+		assert.Assert(t, maxRunning <= expected, "kind concurrency %d", kindConcurrency)
+	}
+}
+
+type fakeDiscovery struct {
+	client.IDiscovery
+}
+
+func (d fakeDiscovery) FindResource(apiVersion string, kind string) (*metav1.APIResource, schema.GroupVersionResource, error) {
+	return &metav1.APIResource{Name: "pods", Kind: "Pod", Namespaced: true}, schema.GroupVersionResource{Version: "v1", Resource: "pods"}, nil
+}
+
+type fakeResourceCache struct {
+	created int
+}
+
+func (c *fakeResourceCache) CreateInformers(resources ...string) []error {
+	c.created += len(resources)
+	return nil
+}
+
+func (c *fakeResourceCache) CreateGVKInformer(kind string) (resourcecache.GenericCache, error) {
+	c.created++
+	return nil, errors.New("informers must not be created by the background scans")
+}
+
+func (c *fakeResourceCache) StopResourceInformer(resource string) {}
+
+func (c *fakeResourceCache) GetGVRCache(resource string) (resourcecache.GenericCache, bool) {
+	return nil, false
+}
+
+type fakeEventGen struct{}
+
+func (fakeEventGen) Add(infos ...event.Info) {}
+
+type fakePRGenerator struct {
+	mutex sync.Mutex
+	infos []policyreport.Info
+}
+
+func (g *fakePRGenerator) Add(infos ...policyreport.Info) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.infos = append(g.infos, infos...)
+}
+
+func Test_ProcessExistingResources_ListsInPages(t *testing.T) {
+	// the API server returns a pod per page, the continue token is the name of the next pod
+	names := []string{"pod-a", "pod-b", "pod-c"}
+	var mutex sync.Mutex
+	var requests []string
+	running, maxRunning := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, r.URL.Path+"?"+r.URL.RawQuery)
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+		defer func() {
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+		}()
+
+		index := 0
+		for i, name := range names {
+			if name == r.URL.Query().Get("continue") {
+				index = i
+			}
+		}
+
+		namespace := strings.Split(r.URL.Path, "/")[4]
+		list := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "v1", "kind": "PodList"}}
+		list.Items = []unstructured.Unstructured{{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]interface{}{"name": names[index], "namespace": namespace, "uid": namespace + "-" + names[index]},
+			"status":     map[string]interface{}{"phase": "Running"},
+		}}}
+		if index+1 < len(names) {
+			list.SetContinue(names[index+1])
+		}
+
+		data, _ := list.MarshalJSON()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	dclient, err := client.NewClient(&rest.Config{Host: server.URL}, time.Hour, stopCh, log.Log)
+	assert.NilError(t, err)
+	dclient.SetDiscovery(fakeDiscovery{IDiscovery: client.NewFakeDiscoveryClient([]schema.GroupVersionResource{{Version: "v1", Resource: "pods"}})})
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, ns := range []string{"ns-a", "ns-b"} {
+		assert.NilError(t, indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}))
+	}
+
+	resCache, prGenerator := &fakeResourceCache{}, &fakePRGenerator{}
+	pc := &PolicyController{
+		client:        dclient,
+		resCache:      resCache,
+		rm:            NewResourceManager(30),
+		nsLister:      listerv1.NewNamespaceLister(indexer),
+		configHandler: &config.ConfigData{},
+		eventGen:      fakeEventGen{},
+		prGenerator:   prGenerator,
+		scanOptions:   ScanOptions{KindConcurrency: 2, MaxListCalls: 1, ListPageSize: 1},
+		listLimiter:   make(chan struct{}, 1),
+		log:           log.Log,
+	}
+
+	var policy kyverno.ClusterPolicy
+	assert.NilError(t, json.Unmarshal([]byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "require-team"},
+		"spec": {
+			"background": true,
+			"rules": [{
+				"name": "check-team",
+				"match": {"resources": {"kinds": ["Pod"]}},
+				"validate": {"message": "the team label is required", "pattern": {"metadata": {"labels": {"team": "?*"}}}}
+			}]
+		}
+	}`), &policy))
+
+	pc.processExistingResources(&policy, nil)
+
+	assert.Equal(t, resCache.created, 0)
+	assert.Equal(t, maxRunning, 1)
+	assert.Equal(t, len(requests), 6)
+	for _, request := range requests {
+		assert.Assert(t, strings.Contains(request, "limit=1"), request)
+	}
+
+	results := 0
+	for _, info := range prGenerator.infos {
+		results += len(info.Results)
+	}
+	assert.Equal(t, results, 6)
+}
@@ -10,6 +10,7 @@ import (
 	commonAnchors "github.com/kyverno/kyverno/pkg/engine/anchor/common"
 	"github.com/kyverno/kyverno/pkg/engine/variables"
 	"github.com/kyverno/kyverno/pkg/policy/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Generate provides implementation to validate 'generate' rule
@@ -40,9 +41,17 @@ func (g *Generate) Validate() (string, error) {
 		return "", fmt.Errorf("only one of data or clone can be specified")
 	}
 
+	if rule.CloneList != nil && (rule.Data != nil || rule.Clone != (kyverno.CloneFrom{})) {
+		return "", fmt.Errorf("only one of data, clone or cloneList can be specified")
+	}
+
 	kind, name, namespace := rule.Kind, rule.Name, rule.Namespace
 
-	if name == "" {
+	if rule.CloneList != nil {
+		if name != "" {
+			return "name", fmt.Errorf("name cannot be specified with cloneList, the generated resources have the names of the source resources")
+		}
+	} else if name == "" {
 		return "name", fmt.Errorf("name cannot be empty")
 	}
 	if kind == "" {
@@ -55,6 +64,11 @@ func (g *Generate) Validate() (string, error) {
 			return fmt.Sprintf("clone.%s", path), err
 		}
 	}
+	if rule.CloneList != nil {
+		if path, err := g.validateCloneList(*rule.CloneList, kind); err != nil {
+			return fmt.Sprintf("cloneList.%s", path), err
+		}
+	}
 	if rule.Data != nil {
 		//TODO: is this required ?? as anchors can only be on pattern and not resource
 		// we can add this check by not sure if its needed here
@@ -79,21 +93,39 @@ func (g *Generate) validateClone(c kyverno.CloneFrom, kind string) (string, erro
 		return "name", fmt.Errorf("name cannot be empty")
 	}
 
-	namespace := c.Namespace
+	return "", g.canIGetSource(kind, c.Namespace)
+}
+
+func (g *Generate) validateCloneList(c kyverno.CloneList, kind string) (string, error) {
+	if c.Namespace == "" {
+		return "namespace", fmt.Errorf("namespace cannot be empty")
+	}
+
+	if c.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(c.Selector); err != nil {
+			return "selector", fmt.Errorf("invalid selector: %v", err)
+		}
+	}
+
+	return "", g.canIGetSource(kind, c.Namespace)
+}
+
+// canIGetSource returns a error if kyverno cannot get the source resources of clones
+func (g *Generate) canIGetSource(kind, namespace string) error {
 	// Skip if there is variable defined
 	if !variables.IsVariable(kind) && !variables.IsVariable(namespace) {
 		// GET
 		ok, err := g.authCheck.CanIGet(kind, namespace)
 		if err != nil {
-			return "", err
+			return err
 		}
 		if !ok {
-			return "", fmt.Errorf("kyverno does not have permissions to 'get' resource %s/%s. Update permissions in ClusterRole 'kyverno:generatecontroller'", kind, namespace)
+			return fmt.Errorf("kyverno does not have permissions to 'get' resource %s/%s. Update permissions in ClusterRole 'kyverno:generatecontroller'", kind, namespace)
 		}
 	} else {
 		g.log.V(4).Info("name & namespace uses variables, so cannot be resolved. Skipping Auth Checks.")
 	}
-	return "", nil
+	return nil
 }
 
 //canIGenerate returns a error if kyverno cannot perform operations
@@ -87,3 +87,58 @@ func Test_Validate_Generate_HasAnchors(t *testing.T) {
 		assert.Assert(t, err != nil)
 	}
 }
+
+func Test_Validate_Generate_CloneList(t *testing.T) {
+	testcases := []struct {
+		description string
+		generate    string
+		path        string
+		err         string
+	}{
+		{
+			description: "clone list",
+			generate:    `{"kind": "Secret", "namespace": "{{request.object.metadata.name}}", "cloneList": {"namespace": "default", "selector": {"matchLabels": {"sync": "true"}}}}`,
+		},
+		{
+			description: "clone list without selector",
+			generate:    `{"kind": "Secret", "namespace": "{{request.object.metadata.name}}", "cloneList": {"namespace": "default"}}`,
+		},
+		{
+			description: "clone list with name",
+			generate:    `{"kind": "Secret", "name": "regcred", "namespace": "dev", "cloneList": {"namespace": "default"}}`,
+			path:        "name",
+			err:         "name cannot be specified with cloneList, the generated resources have the names of the source resources",
+		},
+		{
+			description: "clone list and clone",
+			generate:    `{"kind": "Secret", "namespace": "dev", "clone": {"namespace": "default", "name": "regcred"}, "cloneList": {"namespace": "default"}}`,
+			err:         "only one of data, clone or cloneList can be specified",
+		},
+		{
+			description: "clone list without namespace",
+			generate:    `{"kind": "Secret", "namespace": "dev", "cloneList": {"selector": {"matchLabels": {"sync": "true"}}}}`,
+			path:        "cloneList.namespace",
+			err:         "namespace cannot be empty",
+		},
+		{
+			description: "invalid selector",
+			generate:    `{"kind": "Secret", "namespace": "dev", "cloneList": {"namespace": "default", "selector": {"matchExpressions": [{"key": "sync", "operator": "Equals"}]}}}`,
+			path:        "cloneList.selector",
+			err:         "invalid selector",
+		},
+	}
+
+	for _, testcase := range testcases {
+		var genRule kyverno.Generation
+		err := json.Unmarshal([]byte(testcase.generate), &genRule)
+		assert.NilError(t, err, testcase.description)
+
+		path, err := NewFakeGenerate(genRule).Validate()
+		if testcase.err == "" {
+			assert.NilError(t, err, testcase.description)
+		} else {
+			assert.ErrorContains(t, err, testcase.err, testcase.description)
+			assert.Equal(t, path, testcase.path, testcase.description)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jmespath/go-jmespath"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/common"
+	"github.com/kyverno/kyverno/pkg/engine/operator"
+	kyvernocommon "github.com/kyverno/kyverno/pkg/kyverno/common"
+	"github.com/kyverno/kyverno/pkg/utils"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Lint returns the warnings of a valid policy, the mistakes which do not make the policy
+// invalid but make its rules behave unexpectedly:
+// - the rules which are never applied, e.g. the duplicate rules
+// - the variables which are not defined, or which are not valid JMESPath expressions
+// - the patterns which can never match, e.g. a comparison of strings
+func Lint(p kyverno.ClusterPolicy) []string {
+	var warnings []string
+	warnings = append(warnings, lintUnreachableRules(p)...)
+	warnings = append(warnings, lintVariables(p)...)
+	warnings = append(warnings, lintPatterns(p)...)
+	return warnings
+}
+
+// lintUnreachableRules returns the rules which are duplicates of a previous rule, and the
+// rules whose preconditions are never satisfied
+func lintUnreachableRules(p kyverno.ClusterPolicy) []string {
+	var warnings []string
+	for i, rule := range p.Spec.Rules {
+		for _, previous := range p.Spec.Rules[:i] {
+			r, prev := rule, previous
+			r.Name, prev.Name = "", ""
+			if reflect.DeepEqual(r, prev) {
+				warnings = append(warnings, fmt.Sprintf("rules[%d] (%s): the rule is a duplicate of rule %s", i, rule.Name, previous.Name))
+				break
+			}
+		}
+
+		if condition, ok := unsatisfiablePrecondition(rule.AnyAllConditions); ok {
+			warnings = append(warnings, fmt.Sprintf("rules[%d] (%s): the rule is never applied, the precondition %s is never satisfied", i, rule.Name, condition))
+		}
+	}
+
+	return warnings
+}
+
+// unsatisfiablePrecondition returns the precondition which is never satisfied, the constant
+// conditions of all conditions, or of all any conditions
+func unsatisfiablePrecondition(preconditions interface{}) (string, bool) {
+	if preconditions == nil {
+		return "", false
+	}
+
+	raw, err := json.Marshal(preconditions)
+	if err != nil {
+		return "", false
+	}
+
+	var conditions kyverno.AnyAllConditions
+	if err := json.Unmarshal(raw, &conditions.AllConditions); err != nil {
+		if err := json.Unmarshal(raw, &conditions); err != nil {
+			return "", false
+		}
+	}
+
+	for _, condition := range conditions.AllConditions {
+		if neverSatisfied(condition) {
+			return conditionString(condition), true
+		}
+	}
+
+	if len(conditions.AnyConditions) == 0 {
+		return "", false
+	}
+
+	var any []string
+	for _, condition := range conditions.AnyConditions {
+		if !neverSatisfied(condition) {
+			return "", false
+		}
+		any = append(any, conditionString(condition))
+	}
+
+	return strings.Join(any, " or "), true
+}
+
+// neverSatisfied checks if the condition compares constants which are never equal, or never different
+func neverSatisfied(condition kyverno.Condition) bool {
+	raw, err := json.Marshal([]interface{}{condition.Key, condition.Value})
+	if err != nil || strings.Contains(string(raw), "{{") {
+		return false
+	}
+
+	switch condition.Operator {
+	case kyverno.Equal, kyverno.Equals:
+		return !reflect.DeepEqual(condition.Key, condition.Value)
+	case kyverno.NotEqual, kyverno.NotEquals:
+		return reflect.DeepEqual(condition.Key, condition.Value)
+	default:
+		return false
+	}
+}
+
+func conditionString(condition kyverno.Condition) string {
+	return fmt.Sprintf("'%v %s %v'", condition.Key, condition.Operator, condition.Value)
+}
+
+// lintVariables returns the variables which are not valid JMESPath expressions, and which do
+// not reference a built-in variable or a context entry of the rule. The variables of the policies
+// which declare their variables are checked by the validation of the policy.
+func lintVariables(p kyverno.ClusterPolicy) []string {
+	if len(p.Spec.Variables) != 0 {
+		return nil
+	}
+
+	var warnings []string
+	for i, rule := range p.Spec.Rules {
+		known := append([]string{}, builtInVariables...)
+		for _, entry := range rule.Context {
+			known = append(known, entry.Name)
+		}
+
+		strs, err := ruleStrings(rule)
+		if err != nil {
+			continue
+		}
+
+		var undefined []string
+		for _, str := range strs {
+			for _, variable := range kyvernocommon.RegexVariables.FindAllString(str, -1) {
+				expr := strings.TrimSpace(variable[2 : len(variable)-2])
+				if _, err := jmespath.Compile(expr); err != nil {
+					warnings = append(warnings, fmt.Sprintf("rules[%d] (%s): invalid variable %s: %v", i, rule.Name, variable, err))
+					continue
+				}
+
+				for _, root := range variableRoots(expr) {
+					if !utils.ContainsString(known, root) && !utils.ContainsString(undefined, root) {
+						undefined = append(undefined, root)
+					}
+				}
+			}
+		}
+
+		for _, root := range undefined {
+			warnings = append(warnings, fmt.Sprintf("rules[%d] (%s): variable %s is not defined, it is not a built-in variable or a context entry of the rule", i, rule.Name, root))
+		}
+	}
+
+	return warnings
+}
+
+// lintPatterns returns the values of the validation patterns which can never match
+func lintPatterns(p kyverno.ClusterPolicy) []string {
+	var warnings []string
+	for i, rule := range p.Spec.Rules {
+		var patterns []interface{}
+		if rule.Validation.Pattern != nil {
+			patterns = append(patterns, rule.Validation.Pattern)
+		}
+		if anyPatterns, err := rule.Validation.DeserializeAnyPattern(); err == nil {
+			patterns = append(patterns, anyPatterns...)
+		}
+
+		for _, pattern := range patterns {
+			raw, err := json.Marshal(pattern)
+			if err != nil {
+				continue
+			}
+
+			var data interface{}
+			if err := json.Unmarshal(raw, &data); err != nil {
+				continue
+			}
+
+			for _, warning := range lintPattern(data, "") {
+				warnings = append(warnings, fmt.Sprintf("rules[%d] (%s): %s", i, rule.Name, warning))
+			}
+		}
+	}
+
+	return warnings
+}
+
+func lintPattern(pattern interface{}, path string) []string {
+	var warnings []string
+	switch typed := pattern.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			warnings = append(warnings, lintPattern(typed[key], path+"/"+key)...)
+		}
+	case []interface{}:
+		for i, element := range typed {
+			warnings = append(warnings, lintPattern(element, fmt.Sprintf("%s/%d", path, i))...)
+		}
+	case string:
+		if err := lintStringPattern(typed); err != nil {
+			warnings = append(warnings, fmt.Sprintf("pattern %s never matches at %s: %v", typed, path, err))
+		}
+	}
+
+	return warnings
+}
+
+// lintStringPattern checks the alternatives (|) and the conjunctions (&) of the string pattern,
+// the comparison operators are only applicable to quantities and semantic versions
+func lintStringPattern(pattern string) error {
+	if strings.Contains(pattern, "{{") {
+		return nil
+	}
+
+	for _, alternative := range strings.Split(pattern, "|") {
+		for _, condition := range strings.Split(alternative, "&") {
+			condition = strings.TrimSpace(condition)
+			op := operator.GetOperatorFromStringPattern(condition)
+			if op == operator.Equal || op == operator.NotEqual {
+				continue
+			}
+
+			operand := condition[len(op):]
+			if common.IsSemver(operand) {
+				continue
+			}
+
+			if _, err := apiresource.ParseQuantity(operand); err != nil {
+				return fmt.Errorf("operator %s is not applicable to %q, it is not a quantity or a semantic version", op, operand)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ruleStrings returns the keys and the string values of the rule
+func ruleStrings(rule kyverno.Rule) ([]string, error) {
+	ruleRaw, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	var ruleData interface{}
+	if err := json.Unmarshal(ruleRaw, &ruleData); err != nil {
+		return nil, err
+	}
+
+	return collectStrings(ruleData, nil), nil
+}
@@ -3,6 +3,7 @@ package mutate
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	commonAnchors "github.com/kyverno/kyverno/pkg/engine/anchor/common"
@@ -26,6 +27,10 @@ func NewMutateFactory(rule kyverno.Mutation) *Mutate {
 //Validate validates the 'mutate' rule
 func (m *Mutate) Validate() (string, error) {
 	rule := m.rule
+	if err := validateMutationForms(rule); err != nil {
+		return "", err
+	}
+
 	// JSON Patches
 	if len(rule.Patches) != 0 {
 		for i, patch := range rule.Patches {
@@ -41,6 +46,84 @@ func (m *Mutate) Validate() (string, error) {
 			return path, err
 		}
 	}
+	// Strategic Merge Patch
+	if rule.PatchStrategicMerge != nil {
+		if path, err := validatePatchStrategicMerge(rule.PatchStrategicMerge); err != nil {
+			return path, err
+		}
+	}
+	// ForEach
+	if rule.ForEachMutation != nil {
+		if path, err := validateForEach(rule.ForEachMutation); err != nil {
+			return fmt.Sprintf("foreach.%s", path), err
+		}
+	}
+	// Targets
+	if len(rule.Targets) != 0 {
+		if path, err := validateTargets(rule); err != nil {
+			return path, err
+		}
+	}
+	return "", nil
+}
+
+// validateTargets checks each target declares a kind and the mutation form supports targets
+func validateTargets(rule kyverno.Mutation) (string, error) {
+	if len(rule.Patches) != 0 || rule.Overlay != nil {
+		return "targets", errors.New("targets can only be used with patchStrategicMerge, patchesJson6902 or foreach")
+	}
+
+	for i, target := range rule.Targets {
+		if target.Kind == "" {
+			return fmt.Sprintf("targets[%d].kind", i), errors.New("target kind must be specified")
+		}
+	}
+
+	return "", nil
+}
+
+// validatePatchStrategicMerge checks the strategic merge patch is an object with supported anchors
+func validatePatchStrategicMerge(patchStrategicMerge interface{}) (string, error) {
+	if _, ok := patchStrategicMerge.(map[string]interface{}); !ok {
+		return "patchStrategicMerge", fmt.Errorf("patchStrategicMerge must be an object, found %T", patchStrategicMerge)
+	}
+	path, err := common.ValidatePattern(patchStrategicMerge, "/", []commonAnchors.IsAnchor{commonAnchors.IsConditionAnchor, commonAnchors.IsAddingAnchor})
+	if err != nil {
+		return fmt.Sprintf("patchStrategicMerge.%s", path), err
+	}
+	return "", nil
+}
+
+// validateMutationForms checks that a single form of mutation is declared per rule
+func validateMutationForms(rule kyverno.Mutation) error {
+	count := 0
+	for _, declared := range []bool{len(rule.Patches) != 0, rule.Overlay != nil, rule.PatchStrategicMerge != nil, rule.PatchesJSON6902 != "", rule.ForEachMutation != nil} {
+		if declared {
+			count++
+		}
+	}
+
+	if count > 1 {
+		return errors.New("only one of patches, overlay, patchStrategicMerge, patchesJson6902 or foreach is allowed per mutate rule")
+	}
+
+	return nil
+}
+
+// validateForEach checks the list is specified and exactly one of patchStrategicMerge/patchesJson6902 exists
+func validateForEach(foreach *kyverno.ForEachMutation) (string, error) {
+	if strings.TrimSpace(foreach.List) == "" {
+		return "list", errors.New("foreach.list must be specified")
+	}
+
+	if (foreach.PatchStrategicMerge != nil) == (foreach.PatchesJSON6902 != "") {
+		return "", errors.New("only one of foreach.patchStrategicMerge or foreach.patchesJson6902 must be specified")
+	}
+
+	if foreach.PatchStrategicMerge != nil {
+		return validatePatchStrategicMerge(foreach.PatchStrategicMerge)
+	}
+
 	return "", nil
 }
 
@@ -149,3 +149,117 @@ func Test_Validate_Mutate_Unsupported(t *testing.T) {
 		assert.Assert(t, err != nil)
 	}
 }
+
+func Test_Validate_Mutate_PatchStrategicMerge(t *testing.T) {
+	testcases := []struct {
+		rawMutate []byte
+		valid     bool
+	}{
+		{
+			rawMutate: []byte(`{"patchStrategicMerge": {"metadata": {"labels": {"+(app)": "default"}}, "spec": {"(serviceAccountName)": "*"}}}`),
+			valid:     true,
+		},
+		{
+			rawMutate: []byte(`{"patchStrategicMerge": {"spec": {"^(serviceAccountName)": "*"}}}`),
+			valid:     false,
+		},
+		{
+			rawMutate: []byte(`{"patchStrategicMerge": ["spec"]}`),
+			valid:     false,
+		},
+		{
+			rawMutate: []byte(`{"patchStrategicMerge": {"spec": {}}, "patchesJson6902": "- op: add\n  path: /spec/x\n  value: y"}`),
+			valid:     false,
+		},
+		{
+			rawMutate: []byte(`{"overlay": {"spec": {}}, "patches": [{"path": "/spec/x", "op": "remove"}]}`),
+			valid:     false,
+		},
+	}
+
+	for _, tc := range testcases {
+		var mutate kyverno.Mutation
+		err := json.Unmarshal(tc.rawMutate, &mutate)
+		assert.NilError(t, err)
+
+		checker := NewMutateFactory(mutate)
+		_, err = checker.Validate()
+		assert.Equal(t, err == nil, tc.valid, string(tc.rawMutate))
+	}
+}
+
+func Test_Validate_Mutate_ForEach(t *testing.T) {
+	testcases := []struct {
+		rawMutate []byte
+		valid     bool
+	}{
+		{
+			rawMutate: []byte(`{"foreach": {"list": "request.object.spec.containers", "patchStrategicMerge": {"spec": {"containers": [{"name": "{{element.name}}", "imagePullPolicy": "Always"}]}}}}`),
+			valid:     true,
+		},
+		{
+			rawMutate: []byte(`{"foreach": {"list": "request.object.spec.containers", "patchesJson6902": "- op: add\n  path: /spec/containers/{{elementIndex}}/x\n  value: y"}}`),
+			valid:     true,
+		},
+		{
+			rawMutate: []byte(`{"foreach": {"patchStrategicMerge": {"spec": {}}}}`),
+			valid:     false,
+		},
+		{
+			rawMutate: []byte(`{"foreach": {"list": "request.object.spec.containers"}}`),
+			valid:     false,
+		},
+		{
+			rawMutate: []byte(`{"foreach": {"list": "request.object.spec.containers", "patchStrategicMerge": {"spec": {"^(name)": "*"}}}}`),
+			valid:     false,
+		},
+		{
+			rawMutate: []byte(`{"patchStrategicMerge": {"spec": {}}, "foreach": {"list": "request.object.spec.containers", "patchStrategicMerge": {"spec": {}}}}`),
+			valid:     false,
+		},
+	}
+
+	for _, tc := range testcases {
+		var mutate kyverno.Mutation
+		err := json.Unmarshal(tc.rawMutate, &mutate)
+		assert.NilError(t, err)
+
+		checker := NewMutateFactory(mutate)
+		_, err = checker.Validate()
+		assert.Equal(t, err == nil, tc.valid, string(tc.rawMutate))
+	}
+}
+
+func Test_Validate_Mutate_Targets(t *testing.T) {
+	testcases := []struct {
+		rawMutate []byte
+		valid     bool
+	}{
+		{
+			rawMutate: []byte(`{"targets": [{"apiVersion": "v1", "kind": "ConfigMap", "namespace": "{{request.object.metadata.namespace}}", "name": "dictionary"}], "patchStrategicMerge": {"metadata": {"labels": {"foo": "bar"}}}}`),
+			valid:     true,
+		},
+		{
+			rawMutate: []byte(`{"targets": [{"apiVersion": "v1", "kind": "ConfigMap", "name": "dictionary"}], "patchesJson6902": "- op: add\n  path: /metadata/labels/foo\n  value: bar"}`),
+			valid:     true,
+		},
+		{
+			rawMutate: []byte(`{"targets": [{"apiVersion": "v1", "name": "dictionary"}], "patchStrategicMerge": {"metadata": {"labels": {"foo": "bar"}}}}`),
+			valid:     false,
+		},
+		{
+			rawMutate: []byte(`{"targets": [{"apiVersion": "v1", "kind": "ConfigMap", "name": "dictionary"}], "overlay": {"metadata": {"labels": {"foo": "bar"}}}}`),
+			valid:     false,
+		},
+	}
+
+	for _, tc := range testcases {
+		var mutate kyverno.Mutation
+		err := json.Unmarshal(tc.rawMutate, &mutate)
+		assert.NilError(t, err)
+
+		checker := NewMutateFactory(mutate)
+		_, err = checker.Validate()
+		assert.Equal(t, err == nil, tc.valid, string(tc.rawMutate))
+	}
+}
@@ -4,13 +4,15 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
 	"github.com/kyverno/kyverno/pkg/event"
 	"github.com/kyverno/kyverno/pkg/policyreport"
 )
 
-func (pc *PolicyController) report(policy string, engineResponses []*response.EngineResponse, logger logr.Logger) {
-	eventInfos := generateEvents(logger, engineResponses)
+func (pc *PolicyController) report(policy *kyverno.ClusterPolicy, engineResponses []*response.EngineResponse, logger logr.Logger) {
+	eventInfos := generateEvents(logger, policy, engineResponses)
 	pc.eventGen.Add(eventInfos...)
 
 	pvInfos := policyreport.GeneratePRsFromEngineResponse(engineResponses, logger)
@@ -22,18 +24,22 @@ func (pc *PolicyController) report(policy string, engineResponses []*response.En
 	logger.V(4).Info("added a request to RCR generator", "key", info.ToKey())
 }
 
-func generateEvents(log logr.Logger, ers []*response.EngineResponse) []event.Info {
+func generateEvents(log logr.Logger, policy *kyverno.ClusterPolicy, ers []*response.EngineResponse) []event.Info {
 	var eventInfos []event.Info
+	if !policy.EventsEnabled() {
+		return eventInfos
+	}
+
 	for _, er := range ers {
 		if er.IsSuccessful() {
 			continue
 		}
-		eventInfos = append(eventInfos, generateEventsPerEr(log, er)...)
+		eventInfos = append(eventInfos, generateEventsPerEr(log, policy, er)...)
 	}
 	return eventInfos
 }
 
-func generateEventsPerEr(log logr.Logger, er *response.EngineResponse) []event.Info {
+func generateEventsPerEr(log logr.Logger, policy *kyverno.ClusterPolicy, er *response.EngineResponse) []event.Info {
 	var eventInfos []event.Info
 
 	logger := log.WithValues("policy", er.PolicyResponse.Policy, "kind", er.PolicyResponse.Resource.Kind, "namespace", er.PolicyResponse.Resource.Namespace, "name", er.PolicyResponse.Resource.Name)
@@ -50,14 +56,30 @@ func generateEventsPerEr(log logr.Logger, er *response.EngineResponse) []event.I
 		e.Namespace = er.PolicyResponse.Resource.Namespace
 		e.Name = er.PolicyResponse.Resource.Name
 		e.Reason = event.PolicyViolation.String()
+		if rule.Type != utils.Validation.String() {
+			e.Reason = event.PolicyError.String()
+		}
 		e.Source = event.PolicyController
+		e.Related = policyObject(policy)
 		e.Message = fmt.Sprintf("policy '%s' (%s) rule '%s' failed. %v", er.PolicyResponse.Policy, rule.Type, rule.Name, rule.Message)
+		if rule.Severity != "" {
+			e.Message = fmt.Sprintf("policy '%s' (%s) rule '%s' failed with severity %s. %v", er.PolicyResponse.Policy, rule.Type, rule.Name, rule.Severity, rule.Message)
+		}
 		eventInfos = append(eventInfos, e)
 	}
 
 	return eventInfos
 }
 
+// policyObject identifies the policy as the related object of the events
+func policyObject(policy *kyverno.ClusterPolicy) event.Object {
+	kind := "ClusterPolicy"
+	if policy.Namespace != "" {
+		kind = "Policy"
+	}
+	return event.Object{APIVersion: kyverno.SchemeGroupVersion.String(), Kind: kind, Namespace: policy.Namespace, Name: policy.Name}
+}
+
 func mergePvInfos(infos []policyreport.Info) policyreport.Info {
 	aggregatedInfo := policyreport.Info{}
 	if len(infos) == 0 {
@@ -0,0 +1,77 @@
+package policy
+
+import (
+	"reflect"
+	"sync"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+)
+
+// pendingRules stores the rules of the queued policies which are rescanned, so that the update of
+// a policy only rescans the kinds and the namespaces matched by its added and changed rules
+type pendingRules struct {
+	mutex sync.Mutex
+
+	// rules are the rules of each policy key, all the rules are rescanned when the rules are nil
+	rules map[string]map[string]bool
+}
+
+func newPendingRules() *pendingRules {
+	return &pendingRules{rules: make(map[string]map[string]bool)}
+}
+
+// add adds the rules of the policy key, all the rules when the rule names are nil
+func (p *pendingRules) add(key string, ruleNames []string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	rules, ok := p.rules[key]
+	if ruleNames == nil || (ok && rules == nil) {
+		p.rules[key] = nil
+		return
+	}
+
+	if !ok {
+		rules = make(map[string]bool, len(ruleNames))
+		p.rules[key] = rules
+	}
+
+	for _, name := range ruleNames {
+		rules[name] = true
+	}
+}
+
+// pop returns and removes the rules of the policy key, it returns nil when all the rules are
+// rescanned, including when the policy key was not added
+func (p *pendingRules) pop(key string) map[string]bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	rules := p.rules[key]
+	delete(p.rules, key)
+	return rules
+}
+
+// changedRules returns the names of the rules of the current policy which are added or changed,
+// and whether all the rules changed, i.e. when the spec of the policy changed besides its rules
+func changedRules(old, cur *kyverno.ClusterPolicy) (ruleNames []string, all bool) {
+	oldSpec, curSpec := old.Spec, cur.Spec
+	oldSpec.Rules, curSpec.Rules = nil, nil
+	if !reflect.DeepEqual(oldSpec, curSpec) {
+		return nil, true
+	}
+
+	oldRules := make(map[string]kyverno.Rule, len(old.Spec.Rules))
+	for _, rule := range old.Spec.Rules {
+		oldRules[rule.Name] = rule
+	}
+
+	ruleNames = []string{}
+	for _, rule := range cur.Spec.Rules {
+		if oldRule, ok := oldRules[rule.Name]; !ok || !reflect.DeepEqual(oldRule, rule) {
+			ruleNames = append(ruleNames, rule.Name)
+		}
+	}
+
+	return ruleNames, false
+}
@@ -0,0 +1,167 @@
+package policy
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_PendingRules(t *testing.T) {
+	pending := newPendingRules()
+
+	// a policy which is not added rescans all the rules on retries
+	assert.Assert(t, pending.pop("require-labels") == nil)
+
+	pending.add("require-labels", []string{"check-team"})
+	pending.add("require-labels", []string{"check-app"})
+	assert.DeepEqual(t, pending.pop("require-labels"), map[string]bool{"check-team": true, "check-app": true})
+	assert.Assert(t, pending.pop("require-labels") == nil)
+
+	// all the rules take precedence over the targeted rules
+	pending.add("require-labels", []string{"check-team"})
+	pending.add("require-labels", nil)
+	pending.add("require-labels", []string{"check-app"})
+	assert.Assert(t, pending.pop("require-labels") == nil)
+}
+
+func Test_ChangedRules(t *testing.T) {
+	rule := func(name, kind string) kyverno.Rule {
+		return kyverno.Rule{
+			Name:           name,
+			MatchResources: kyverno.MatchResources{ResourceDescription: kyverno.ResourceDescription{Kinds: []string{kind}}},
+		}
+	}
+
+	old := &kyverno.ClusterPolicy{Spec: kyverno.Spec{
+		ValidationFailureAction: "audit",
+		Rules:                   []kyverno.Rule{rule("check-pods", "Pod"), rule("check-services", "Service"), rule("check-secrets", "Secret")},
+	}}
+
+	cur := old.DeepCopy()
+	cur.Spec.Rules = []kyverno.Rule{rule("check-pods", "Pod"), rule("check-services", "Ingress"), rule("check-configmaps", "ConfigMap")}
+	ruleNames, all := changedRules(old, cur)
+	assert.Assert(t, !all)
+	assert.DeepEqual(t, ruleNames, []string{"check-services", "check-configmaps"})
+
+	// the deleted rules are not rescanned
+	cur = old.DeepCopy()
+	cur.Spec.Rules = cur.Spec.Rules[:1]
+	ruleNames, all = changedRules(old, cur)
+	assert.Assert(t, !all)
+	assert.Equal(t, len(ruleNames), 0)
+
+	cur = old.DeepCopy()
+	cur.Spec.ValidationFailureAction = "enforce"
+	_, all = changedRules(old, cur)
+	assert.Assert(t, all)
+}
+
+func Test_EnqueueChangedRules(t *testing.T) {
+	rule := func(name, kind string) kyverno.Rule {
+		return kyverno.Rule{
+			Name:           name,
+			MatchResources: kyverno.MatchResources{ResourceDescription: kyverno.ResourceDescription{Kinds: []string{kind}}},
+		}
+	}
+
+	old := &kyverno.ClusterPolicy{Spec: kyverno.Spec{
+		Rules: []kyverno.Rule{rule("check-pods", "Pod"), rule("check-services", "Service")},
+	}}
+	old.SetName("require-labels")
+
+	cur := old.DeepCopy()
+	cur.Spec.Rules = []kyverno.Rule{rule("check-pods", "Pod"), rule("check-services", "Ingress"), rule("check-configmaps", "ConfigMap")}
+
+	prGenerator := &fakePRGenerator{}
+	pc := &PolicyController{
+		prGenerator:  prGenerator,
+		queue:        workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		pendingRules: newPendingRules(),
+		log:          log.Log,
+	}
+	defer pc.queue.ShutDown()
+
+	pc.enqueueChangedRules(old, cur)
+
+	// the results of the services of the changed rule are removed, the ingresses are rescanned
+	assert.Equal(t, len(prGenerator.infos), 1)
+	assert.Equal(t, prGenerator.infos[0].PolicyName, "require-labels")
+	assert.Equal(t, prGenerator.infos[0].Results[0].Rules[0].Name, "check-services")
+
+	assert.Equal(t, pc.queue.Len(), 1)
+	assert.DeepEqual(t, pc.pendingRules.pop("require-labels"), map[string]bool{"check-services": true, "check-configmaps": true})
+}
+
+type failingClusterPolicyLister struct {
+	kyvernolister.ClusterPolicyLister
+}
+
+func (failingClusterPolicyLister) List(labels.Selector) ([]*kyverno.ClusterPolicy, error) {
+	return nil, errors.New("the cache is not synced")
+}
+
+func Test_EnqueuePolicies(t *testing.T) {
+	disabled := false
+	rule := kyverno.Rule{
+		Name:           "check-team",
+		MatchResources: kyverno.MatchResources{ResourceDescription: kyverno.ResourceDescription{Kinds: []string{"Pod"}}},
+		Validation:     kyverno.Validation{Message: "the team label is required", Pattern: map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"team": "?*"}}}},
+	}
+	unknownVarRule := *rule.DeepCopy()
+	unknownVarRule.Validation.Message = "{{ request.unknown }}"
+
+	clusterPolicy := func(name string, background *bool, rule kyverno.Rule) *kyverno.ClusterPolicy {
+		p := &kyverno.ClusterPolicy{Spec: kyverno.Spec{Background: background, Rules: []kyverno.Rule{rule}}}
+		p.SetName(name)
+		return p
+	}
+	policy := func(namespace, name string, background *bool, rule kyverno.Rule) *kyverno.Policy {
+		p := &kyverno.Policy{Spec: kyverno.Spec{Background: background, Rules: []kyverno.Rule{rule}}}
+		p.SetNamespace(namespace)
+		p.SetName(name)
+		return p
+	}
+
+	pIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	assert.NilError(t, pIndexer.Add(clusterPolicy("require-labels", nil, rule)))
+	assert.NilError(t, pIndexer.Add(clusterPolicy("require-labels-admission", &disabled, rule)))
+	assert.NilError(t, pIndexer.Add(clusterPolicy("require-labels-invalid", nil, unknownVarRule)))
+
+	npIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	assert.NilError(t, npIndexer.Add(policy("prod", "require-labels", nil, rule)))
+	assert.NilError(t, npIndexer.Add(policy("prod", "require-labels-admission", &disabled, rule)))
+	assert.NilError(t, npIndexer.Add(policy("dev", "require-labels-invalid", nil, unknownVarRule)))
+
+	pc := &PolicyController{
+		pLister:      kyvernolister.NewClusterPolicyLister(pIndexer),
+		npLister:     kyvernolister.NewPolicyLister(npIndexer),
+		queue:        workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		pendingRules: newPendingRules(),
+		log:          log.Log,
+	}
+	defer pc.queue.ShutDown()
+
+	// only the policies processed in the background are queued
+	pc.enqueuePolicies()
+	var keys []string
+	for pc.queue.Len() > 0 {
+		key, _ := pc.queue.Get()
+		keys = append(keys, key.(string))
+		pc.queue.Done(key)
+	}
+	sort.Strings(keys)
+	assert.DeepEqual(t, keys, []string{"prod/require-labels", "require-labels"})
+
+	// the scan is skipped when the policies cannot be listed
+	pc.pLister = failingClusterPolicyLister{}
+	pc.enqueuePolicies()
+	assert.Equal(t, pc.queue.Len(), 0)
+}
@@ -1,17 +1,21 @@
 package policy
 
 import (
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"reflect"
 	"strings"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/jmespath/go-jmespath"
 	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/kyverno/common"
 
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
 	dclient "github.com/kyverno/kyverno/pkg/dclient"
 	"github.com/kyverno/kyverno/pkg/openapi"
 	"github.com/kyverno/kyverno/pkg/utils"
@@ -39,8 +43,17 @@ func Validate(policy *kyverno.ClusterPolicy, client *dclient.Client, mock bool,
 	if path, err := validateUniqueRuleName(p); err != nil {
 		return fmt.Errorf("path: spec.%s: %v", path, err)
 	}
+
+	if path, err := validateVariableDeclarations(p); err != nil {
+		return fmt.Errorf("path: spec.%s: %v", path, err)
+	}
+
+	if path, err := validateVariableReferences(p); err != nil {
+		return fmt.Errorf("path: spec.%s: %v", path, err)
+	}
+	// the rules which reference the data of the admission requests are skipped in background mode
 	if p.Spec.Background == nil || *p.Spec.Background == true {
-		if err := ContainsVariablesOtherThanObject(p); err != nil {
+		if _, err := AdmissionOnlyRules(p); err != nil {
 			return fmt.Errorf("only select variables are allowed in background mode. Set spec.background=false to disable background mode for this policy rule: %s ", err)
 		}
 	}
@@ -65,6 +78,12 @@ func Validate(policy *kyverno.ClusterPolicy, client *dclient.Client, mock bool,
 			return fmt.Errorf("path: spec.rules[%d]: %v", i, err)
 		}
 
+		for _, key := range []string{report.ResultTimestampKey, report.ResultLastSeenKey, report.ResultCountKey} {
+			if _, ok := rule.ReportProperties[key]; ok {
+				return fmt.Errorf("path: spec.rules[%d].reportProperties: %s is reserved for the reporting of the result", i, key)
+			}
+		}
+
 		// validate Cluster Resources in namespaced policy
 		// For namespaced policy, ClusterResource type field and values are not allowed in match and exclude
 		if !mock && p.ObjectMeta.Namespace != "" {
@@ -495,6 +514,12 @@ func validateResources(rule kyverno.Rule) (string, error) {
 			return fmt.Sprintf("validate.deny.%s", path), err
 		}
 	}
+	//validating the values present under validate.foreach.deny.conditions, if they exist
+	if foreach := rule.Validation.ForEachValidation; foreach != nil && foreach.Deny != nil && foreach.Deny.AnyAllConditions != nil {
+		if path, err := validateConditions(foreach.Deny.AnyAllConditions, "conditions"); err != nil {
+			return fmt.Sprintf("validate.foreach.deny.%s", path), err
+		}
+	}
 	return "", nil
 }
 
@@ -640,8 +665,16 @@ func validateRuleContext(rule kyverno.Rule) error {
 			err = validateConfigMap(entry)
 		} else if entry.APICall != nil {
 			err = validateAPICall(entry)
+		} else if entry.ImageRegistry != nil {
+			err = validateImageRegistry(entry)
+		} else if entry.ServiceCall != nil {
+			err = validateServiceCall(entry)
+		} else if entry.GlobalReference != nil {
+			err = validateGlobalReference(entry)
+		} else if entry.Secret != nil {
+			err = validateSecret(entry)
 		} else {
-			return fmt.Errorf("a configMap or apiCall is required for context entries")
+			return fmt.Errorf("a configMap, apiCall, imageRegistry, serviceCall, globalReference or secret is required for context entries")
 		}
 
 		if err != nil {
@@ -661,6 +694,22 @@ func validateConfigMap(entry kyverno.ContextEntry) error {
 		return fmt.Errorf("both configMap and apiCall are not allowed in a context entry")
 	}
 
+	if entry.ImageRegistry != nil {
+		return fmt.Errorf("both configMap and imageRegistry are not allowed in a context entry")
+	}
+
+	if entry.ServiceCall != nil {
+		return fmt.Errorf("both configMap and serviceCall are not allowed in a context entry")
+	}
+
+	if entry.GlobalReference != nil {
+		return fmt.Errorf("both configMap and globalReference are not allowed in a context entry")
+	}
+
+	if entry.Secret != nil {
+		return fmt.Errorf("both configMap and secret are not allowed in a context entry")
+	}
+
 	if entry.ConfigMap.Name == "" {
 		return fmt.Errorf("a name is required for configMap context entry")
 	}
@@ -681,6 +730,22 @@ func validateAPICall(entry kyverno.ContextEntry) error {
 		return fmt.Errorf("both configMap and apiCall are not allowed in a context entry")
 	}
 
+	if entry.ImageRegistry != nil {
+		return fmt.Errorf("both apiCall and imageRegistry are not allowed in a context entry")
+	}
+
+	if entry.ServiceCall != nil {
+		return fmt.Errorf("both apiCall and serviceCall are not allowed in a context entry")
+	}
+
+	if entry.GlobalReference != nil {
+		return fmt.Errorf("both apiCall and globalReference are not allowed in a context entry")
+	}
+
+	if entry.Secret != nil {
+		return fmt.Errorf("both apiCall and secret are not allowed in a context entry")
+	}
+
 	if _, err := engine.NewAPIPath(entry.APICall.URLPath); err != nil {
 		return err
 	}
@@ -694,6 +759,136 @@ func validateAPICall(entry kyverno.ContextEntry) error {
 	return nil
 }
 
+func validateImageRegistry(entry kyverno.ContextEntry) error {
+	if entry.ImageRegistry == nil {
+		return fmt.Errorf("imageRegistry is empty")
+	}
+
+	if entry.ImageRegistry.Reference == "" {
+		return fmt.Errorf("a reference is required for imageRegistry context entry")
+	}
+
+	if entry.ServiceCall != nil {
+		return fmt.Errorf("both imageRegistry and serviceCall are not allowed in a context entry")
+	}
+
+	if entry.GlobalReference != nil {
+		return fmt.Errorf("both imageRegistry and globalReference are not allowed in a context entry")
+	}
+
+	if entry.Secret != nil {
+		return fmt.Errorf("both imageRegistry and secret are not allowed in a context entry")
+	}
+
+	// the reference is validated when it has no variables, variables are resolved during the rule execution
+	if !strings.Contains(entry.ImageRegistry.Reference, "{{") {
+		if _, err := name.ParseReference(entry.ImageRegistry.Reference); err != nil {
+			return err
+		}
+	}
+
+	if entry.ImageRegistry.JMESPath != "" {
+		if _, err := jmespath.NewParser().Parse(entry.ImageRegistry.JMESPath); err != nil {
+			return fmt.Errorf("failed to parse JMESPath %s: %v", entry.ImageRegistry.JMESPath, err)
+		}
+	}
+
+	return nil
+}
+
+func validateServiceCall(entry kyverno.ContextEntry) error {
+	call := entry.ServiceCall
+	if call == nil {
+		return fmt.Errorf("serviceCall is empty")
+	}
+
+	if entry.GlobalReference != nil {
+		return fmt.Errorf("both serviceCall and globalReference are not allowed in a context entry")
+	}
+
+	if entry.Secret != nil {
+		return fmt.Errorf("both serviceCall and secret are not allowed in a context entry")
+	}
+
+	if call.URL == "" {
+		return fmt.Errorf("a url is required for serviceCall context entry")
+	}
+
+	// the URL is validated when it has no variables, variables are resolved during the rule execution
+	if !strings.Contains(call.URL, "{{") {
+		u, err := url.Parse(call.URL)
+		if err != nil {
+			return fmt.Errorf("invalid url %s for serviceCall context entry: %v", call.URL, err)
+		}
+
+		if u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("an https url is required for serviceCall context entry, found %s", call.URL)
+		}
+	}
+
+	if call.CABundle != "" {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(call.CABundle)) {
+			return fmt.Errorf("caBundle of serviceCall context entry does not contain any PEM encoded certificate")
+		}
+	}
+
+	if call.AuthSecret != nil {
+		if call.AuthSecret.Name == "" || call.AuthSecret.Namespace == "" || call.AuthSecret.Key == "" {
+			return fmt.Errorf("a name, namespace and key are required for the authSecret of serviceCall context entry")
+		}
+	}
+
+	if call.TimeoutSeconds < 0 {
+		return fmt.Errorf("timeoutSeconds of serviceCall context entry must not be negative")
+	}
+
+	if call.CacheSeconds < 0 {
+		return fmt.Errorf("cacheSeconds of serviceCall context entry must not be negative")
+	}
+
+	if call.JMESPath != "" {
+		if _, err := jmespath.NewParser().Parse(call.JMESPath); err != nil {
+			return fmt.Errorf("failed to parse JMESPath %s: %v", call.JMESPath, err)
+		}
+	}
+
+	return nil
+}
+
+func validateGlobalReference(entry kyverno.ContextEntry) error {
+	if entry.GlobalReference == nil {
+		return fmt.Errorf("globalReference is empty")
+	}
+
+	if entry.Secret != nil {
+		return fmt.Errorf("both globalReference and secret are not allowed in a context entry")
+	}
+
+	if entry.GlobalReference.Name == "" {
+		return fmt.Errorf("a name is required for globalReference context entry")
+	}
+
+	if entry.GlobalReference.JMESPath != "" {
+		if _, err := jmespath.NewParser().Parse(entry.GlobalReference.JMESPath); err != nil {
+			return fmt.Errorf("failed to parse JMESPath %s: %v", entry.GlobalReference.JMESPath, err)
+		}
+	}
+
+	return nil
+}
+
+func validateSecret(entry kyverno.ContextEntry) error {
+	if entry.Secret == nil {
+		return fmt.Errorf("secret is empty")
+	}
+
+	if entry.Secret.Name == "" || entry.Secret.Namespace == "" || entry.Secret.Key == "" {
+		return fmt.Errorf("a name, namespace and key are required for secret context entry")
+	}
+
+	return nil
+}
+
 // validateResourceDescription checks if all necessary fields are present and have values. Also checks a Selector.
 // field type is checked through openapi
 // Returns error if
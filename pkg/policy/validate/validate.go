@@ -2,7 +2,9 @@ package validate
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/Masterminds/semver"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	commonAnchors "github.com/kyverno/kyverno/pkg/engine/anchor/common"
 	"github.com/kyverno/kyverno/pkg/policy/common"
@@ -30,6 +32,22 @@ func (v *Validate) Validate() (string, error) {
 		return "", err
 	}
 
+	if rule.DeprecatedAPIs != nil {
+		return "", nil
+	}
+
+	if rule.ForEachValidation != nil {
+		if path, err := validatePatterns(rule.ForEachValidation.ElementValidation(rule.Message)); err != nil {
+			return fmt.Sprintf("foreach.%s", path), err
+		}
+		return "", nil
+	}
+
+	return validatePatterns(&rule)
+}
+
+// validatePatterns validates the pattern and anyPattern declarations
+func validatePatterns(rule *kyverno.Validation) (string, error) {
 	if rule.Pattern != nil {
 		if path, err := common.ValidatePattern(rule.Pattern, "/", []commonAnchors.IsAnchor{commonAnchors.IsConditionAnchor, commonAnchors.IsExistenceAnchor, commonAnchors.IsEqualityAnchor, commonAnchors.IsNegationAnchor}); err != nil {
 			return fmt.Sprintf("pattern.%s", path), err
@@ -53,8 +71,24 @@ func (v *Validate) Validate() (string, error) {
 // validateOverlayPattern checks one of pattern/anyPattern must exist
 func (v *Validate) validateOverlayPattern() error {
 	rule := v.rule
+	if rule.DeprecatedAPIs != nil {
+		if rule.Pattern != nil || rule.AnyPattern != nil || rule.Deny != nil || rule.ForEachValidation != nil {
+			return fmt.Errorf("pattern, anyPattern, deny and foreach are not allowed with deprecatedAPIs")
+		}
+
+		return validateDeprecatedAPIs(rule.DeprecatedAPIs)
+	}
+
+	if rule.ForEachValidation != nil {
+		if rule.Pattern != nil || rule.AnyPattern != nil || rule.Deny != nil {
+			return fmt.Errorf("pattern, anyPattern and deny are not allowed with foreach, declare them in foreach instead")
+		}
+
+		return validateForEach(rule.ForEachValidation)
+	}
+
 	if rule.Pattern == nil && rule.AnyPattern == nil && rule.Deny == nil {
-		return fmt.Errorf("pattern, anyPattern or deny must be specified")
+		return fmt.Errorf("pattern, anyPattern, deny, foreach or deprecatedAPIs must be specified")
 	}
 
 	if rule.Pattern != nil && rule.AnyPattern != nil {
@@ -63,3 +97,36 @@ func (v *Validate) validateOverlayPattern() error {
 
 	return nil
 }
+
+// validateForEach checks the list is specified and exactly one of pattern/anyPattern/deny exists
+func validateForEach(foreach *kyverno.ForEachValidation) error {
+	if strings.TrimSpace(foreach.List) == "" {
+		return fmt.Errorf("foreach.list must be specified")
+	}
+
+	count := 0
+	for _, declared := range []bool{foreach.Pattern != nil, foreach.AnyPattern != nil, foreach.Deny != nil} {
+		if declared {
+			count++
+		}
+	}
+
+	if count != 1 {
+		return fmt.Errorf("only one of foreach.pattern, foreach.anyPattern or foreach.deny must be specified")
+	}
+
+	return nil
+}
+
+// validateDeprecatedAPIs checks the target Kubernetes version is a valid version
+func validateDeprecatedAPIs(check *kyverno.DeprecatedAPIs) error {
+	if strings.TrimSpace(check.KubernetesVersion) == "" {
+		return fmt.Errorf("deprecatedAPIs.kubernetesVersion must be specified")
+	}
+
+	if _, err := semver.NewVersion(check.KubernetesVersion); err != nil {
+		return fmt.Errorf("invalid deprecatedAPIs.kubernetesVersion %s: %v", check.KubernetesVersion, err)
+	}
+
+	return nil
+}
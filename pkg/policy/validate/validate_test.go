@@ -379,3 +379,75 @@ func Test_Validate_Validate_Unsupported(t *testing.T) {
 	}
 
 }
+
+func Test_Validate_ForEach(t *testing.T) {
+	testcases := []struct {
+		rawValidation []byte
+		valid         bool
+	}{
+		{
+			rawValidation: []byte(`{"foreach": {"list": "request.object.spec.containers", "pattern": {"image": "registry.corp.com/*"}}}`),
+			valid:         true,
+		},
+		{
+			rawValidation: []byte(`{"foreach": {"pattern": {"image": "registry.corp.com/*"}}}`),
+			valid:         false,
+		},
+		{
+			rawValidation: []byte(`{"foreach": {"list": "request.object.spec.containers", "pattern": {"image": "*"}, "deny": {}}}`),
+			valid:         false,
+		},
+		{
+			rawValidation: []byte(`{"pattern": {"spec": {}}, "foreach": {"list": "request.object.spec.containers", "pattern": {"image": "*"}}}`),
+			valid:         false,
+		},
+		{
+			rawValidation: []byte(`{"foreach": {"list": "request.object.spec.containers", "pattern": {"^(image)": "*"}}}`),
+			valid:         false,
+		},
+	}
+
+	for _, tc := range testcases {
+		var validation kyverno.Validation
+		err := json.Unmarshal(tc.rawValidation, &validation)
+		assert.NilError(t, err)
+
+		checker := NewValidateFactory(validation)
+		_, err = checker.Validate()
+		assert.Equal(t, err == nil, tc.valid, string(tc.rawValidation))
+	}
+}
+
+func Test_Validate_DeprecatedAPIs(t *testing.T) {
+	testcases := []struct {
+		rawValidation []byte
+		valid         bool
+	}{
+		{
+			rawValidation: []byte(`{"deprecatedAPIs": {"kubernetesVersion": "1.22"}}`),
+			valid:         true,
+		},
+		{
+			rawValidation: []byte(`{"deprecatedAPIs": {}}`),
+			valid:         false,
+		},
+		{
+			rawValidation: []byte(`{"deprecatedAPIs": {"kubernetesVersion": "latest"}}`),
+			valid:         false,
+		},
+		{
+			rawValidation: []byte(`{"pattern": {"spec": {}}, "deprecatedAPIs": {"kubernetesVersion": "1.22"}}`),
+			valid:         false,
+		},
+	}
+
+	for _, tc := range testcases {
+		var validation kyverno.Validation
+		err := json.Unmarshal(tc.rawValidation, &validation)
+		assert.NilError(t, err)
+
+		checker := NewValidateFactory(validation)
+		_, err = checker.Validate()
+		assert.Equal(t, err == nil, tc.valid, string(tc.rawValidation))
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/event"
 	"github.com/kyverno/kyverno/pkg/policyreport"
 	"github.com/kyverno/kyverno/pkg/resourcecache"
+	"golang.org/x/time/rate"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -95,6 +96,20 @@ type PolicyController struct {
 	// resCache - controls creation and fetching of resource informer cache
 	resCache resourcecache.ResourceCache
 
+	// scanOptions configures the background scans of the existing resources
+	scanOptions ScanOptions
+
+	// pendingRules are the rules of the queued policies which are rescanned
+	pendingRules *pendingRules
+
+	// listLimiter limits the concurrent list requests to the API server, it is nil when
+	// the requests are not limited
+	listLimiter chan struct{}
+
+	// listRateLimiter limits the rate of the list requests to the API server, it is nil when
+	// the rate is not limited
+	listRateLimiter *rate.Limiter
+
 	log logr.Logger
 }
 
@@ -109,7 +124,8 @@ func NewPolicyController(kyvernoClient *kyvernoclient.Clientset,
 	prGenerator policyreport.GeneratorInterface,
 	namespaces informers.NamespaceInformer,
 	log logr.Logger,
-	resCache resourcecache.ResourceCache) (*PolicyController, error) {
+	resCache resourcecache.ResourceCache,
+	scanOptions ScanOptions) (*PolicyController, error) {
 
 	// Event broad caster
 	eventBroadcaster := record.NewBroadcaster()
@@ -130,6 +146,16 @@ func NewPolicyController(kyvernoClient *kyvernoclient.Clientset,
 		prGenerator:   prGenerator,
 		log:           log,
 		resCache:      resCache,
+		scanOptions:   scanOptions,
+		pendingRules:  newPendingRules(),
+	}
+
+	if scanOptions.MaxListCalls > 0 {
+		pc.listLimiter = make(chan struct{}, scanOptions.MaxListCalls)
+	}
+
+	if scanOptions.ListQPS > 0 {
+		pc.listRateLimiter = rate.NewLimiter(rate.Limit(scanOptions.ListQPS), scanOptions.ListBurst)
 	}
 
 	pInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -170,8 +196,8 @@ func (pc *PolicyController) canBackgroundProcess(p *kyverno.ClusterPolicy) bool
 		return false
 	}
 
-	if err := ContainsVariablesOtherThanObject(*p); err != nil {
-		logger.V(4).Info("policy cannot be processed in the background")
+	if _, err := AdmissionOnlyRules(*p); err != nil {
+		logger.V(4).Info("policy cannot be processed in the background", "reason", err.Error())
 		return false
 	}
 
@@ -197,18 +223,20 @@ func (pc *PolicyController) updatePolicy(old, cur interface{}) {
 	oldP := old.(*kyverno.ClusterPolicy)
 	curP := cur.(*kyverno.ClusterPolicy)
 
-	if !pc.canBackgroundProcess(curP) {
+	if reflect.DeepEqual(oldP.Spec, curP.Spec) {
 		return
 	}
 
-	if reflect.DeepEqual(oldP.Spec, curP.Spec) {
+	// the results of the deleted rules are removed whether the policy is processed in the background or not
+	pc.enqueueRCRDeletedRule(oldP, curP)
+
+	if !pc.canBackgroundProcess(curP) {
 		return
 	}
 
 	logger.V(4).Info("updating policy", "name", oldP.Name)
 
-	pc.enqueueRCRDeletedRule(oldP, curP)
-	pc.enqueuePolicy(curP)
+	pc.enqueueChangedRules(oldP, curP)
 }
 
 func (pc *PolicyController) deletePolicy(obj interface{}) {
@@ -255,18 +283,20 @@ func (pc *PolicyController) updateNsPolicy(old, cur interface{}) {
 	oldP := old.(*kyverno.Policy)
 	curP := cur.(*kyverno.Policy)
 	ncurP := ConvertPolicyToClusterPolicy(curP)
-	if !pc.canBackgroundProcess(ncurP) {
+	if reflect.DeepEqual(oldP.Spec, curP.Spec) {
 		return
 	}
 
-	if reflect.DeepEqual(oldP.Spec, curP.Spec) {
+	noldP := ConvertPolicyToClusterPolicy(oldP)
+	pc.enqueueRCRDeletedRule(noldP, ncurP)
+
+	if !pc.canBackgroundProcess(ncurP) {
 		return
 	}
 
 	logger.V(4).Info("updating namespace policy", "namespace", oldP.Namespace, "name", oldP.Name)
 
-	pc.enqueueRCRDeletedRule(ConvertPolicyToClusterPolicy(oldP), ncurP)
-	pc.enqueuePolicy(ncurP)
+	pc.enqueueChangedRules(noldP, ncurP)
 }
 
 func (pc *PolicyController) deleteNsPolicy(obj interface{}) {
@@ -304,20 +334,25 @@ func (pc *PolicyController) enqueueRCRDeletedRule(old, cur *kyverno.ClusterPolic
 
 	for _, rule := range old.Spec.Rules {
 		if !curRule[rule.Name] {
-			pc.prGenerator.Add(policyreport.Info{
-				PolicyName: cur.GetName(),
-				Results: []policyreport.EngineResponseResult{
-					{
-						Rules: []kyverno.ViolatedRule{
-							{Name: rule.Name},
-						},
-					},
-				},
-			})
+			pc.enqueueRCRRemovedRule(cur.GetName(), rule.Name)
 		}
 	}
 }
 
+// enqueueRCRRemovedRule removes the results of the rule from the reports
+func (pc *PolicyController) enqueueRCRRemovedRule(policyName, ruleName string) {
+	pc.prGenerator.Add(policyreport.Info{
+		PolicyName: policyName,
+		Results: []policyreport.EngineResponseResult{
+			{
+				Rules: []kyverno.ViolatedRule{
+					{Name: ruleName},
+				},
+			},
+		},
+	})
+}
+
 func (pc *PolicyController) enqueueRCRDeletedPolicy(policyName string) {
 	pc.prGenerator.Add(policyreport.Info{
 		PolicyName: policyName,
@@ -325,12 +360,48 @@ func (pc *PolicyController) enqueueRCRDeletedPolicy(policyName string) {
 }
 
 func (pc *PolicyController) enqueuePolicy(policy *kyverno.ClusterPolicy) {
+	pc.enqueuePolicyRules(policy, nil)
+}
+
+// enqueueChangedRules enqueues the updated policy, only the kinds and the namespaces matched by
+// its added and changed rules are rescanned, all of them when the spec changed besides the rules.
+// A changed rule is deleted and added again, its results are removed before the rescan since the
+// rule may not match the resources of its results anymore.
+func (pc *PolicyController) enqueueChangedRules(old, cur *kyverno.ClusterPolicy) {
+	ruleNames, all := changedRules(old, cur)
+	if all {
+		pc.enqueuePolicy(cur)
+		return
+	}
+
+	if len(ruleNames) == 0 {
+		pc.log.V(4).Info("no rule to rescan, the rules are deleted", "name", cur.Name)
+		return
+	}
+
+	oldRules := make(map[string]bool, len(old.Spec.Rules))
+	for _, rule := range old.Spec.Rules {
+		oldRules[rule.Name] = true
+	}
+
+	for _, name := range ruleNames {
+		if oldRules[name] {
+			pc.enqueueRCRRemovedRule(cur.GetName(), name)
+		}
+	}
+
+	pc.enqueuePolicyRules(cur, ruleNames)
+}
+
+// enqueuePolicyRules enqueues the policy to rescan the rules, all the rules when the rule names are nil
+func (pc *PolicyController) enqueuePolicyRules(policy *kyverno.ClusterPolicy, ruleNames []string) {
 	logger := pc.log
 	key, err := cache.MetaNamespaceKeyFunc(policy)
 	if err != nil {
 		logger.Error(err, "failed to enqueue policy")
 		return
 	}
+	pc.pendingRules.add(key, ruleNames)
 	pc.queue.Add(key)
 }
 
@@ -352,9 +423,46 @@ func (pc *PolicyController) Run(workers int, stopCh <-chan struct{}) {
 	for i := 0; i < workers; i++ {
 		go wait.Until(pc.worker, time.Second, stopCh)
 	}
+
+	if pc.scanOptions.Interval > 0 {
+		go wait.Until(pc.enqueuePolicies, pc.scanOptions.Interval, stopCh)
+	}
+
 	<-stopCh
 }
 
+// enqueuePolicies enqueues the policies processed in the background, so that their validation
+// rules are applied on the existing resources, including the resources created before the policies
+func (pc *PolicyController) enqueuePolicies() {
+	logger := pc.log
+	policies, err := pc.pLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "failed to list cluster policies, skipping the background scan")
+		return
+	}
+
+	nsPolicies, err := pc.npLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "failed to list policies, skipping the background scan")
+		return
+	}
+
+	for _, p := range policies {
+		if pc.canBackgroundProcess(p) {
+			pc.enqueuePolicy(p)
+		}
+	}
+
+	for _, p := range nsPolicies {
+		pol := ConvertPolicyToClusterPolicy(p)
+		if pc.canBackgroundProcess(pol) {
+			pc.enqueuePolicy(pol)
+		}
+	}
+
+	logger.V(4).Info("queued policies for background scan", "clusterPolicies", len(policies), "policies", len(nsPolicies))
+}
+
 // worker runs a worker thread that just dequeues items, processes them, and marks them done.
 // It enforces that the syncHandler is never invoked concurrently with the same key.
 func (pc *PolicyController) worker() {
@@ -400,6 +508,9 @@ func (pc *PolicyController) syncPolicy(key string) error {
 		logger.V(4).Info("finished syncing policy", "key", key, "processingTime", time.Since(startTime).String())
 	}()
 
+	// all the rules are rescanned when the policy is retried
+	rules := pc.pendingRules.pop(key)
+
 	grList, err := pc.grLister.List(labels.Everything())
 	if err != nil {
 		logger.Error(err, "failed to list generate request")
@@ -416,7 +527,7 @@ func (pc *PolicyController) syncPolicy(key string) error {
 	}
 
 	updateGR(pc.kyvernoClient, policy.Name, grList, logger)
-	pc.processExistingResources(policy)
+	pc.processExistingResources(policy, rules)
 	return nil
 }
 
@@ -831,7 +831,7 @@ func Test_BackGroundUserInfo_match_roles(t *testing.T) {
 	err = json.Unmarshal(rawPolicy, &policy)
 	assert.NilError(t, err)
 
-	err = ContainsVariablesOtherThanObject(*policy)
+	err = ruleContainsVariablesOtherThanObject(*policy, 0, policy.Spec.Rules[0], false)
 	assert.Equal(t, err.Error(), "invalid variable used at path: spec/rules[0]/match/roles")
 }
 
@@ -863,7 +863,7 @@ func Test_BackGroundUserInfo_match_clusterRoles(t *testing.T) {
 	err = json.Unmarshal(rawPolicy, &policy)
 	assert.NilError(t, err)
 
-	err = ContainsVariablesOtherThanObject(*policy)
+	err = ruleContainsVariablesOtherThanObject(*policy, 0, policy.Spec.Rules[0], false)
 
 	assert.Equal(t, err.Error(), "invalid variable used at path: spec/rules[0]/match/clusterRoles")
 }
@@ -899,7 +899,7 @@ func Test_BackGroundUserInfo_match_subjects(t *testing.T) {
 	err = json.Unmarshal(rawPolicy, &policy)
 	assert.NilError(t, err)
 
-	err = ContainsVariablesOtherThanObject(*policy)
+	err = ruleContainsVariablesOtherThanObject(*policy, 0, policy.Spec.Rules[0], false)
 
 	assert.Equal(t, err.Error(), "invalid variable used at path: spec/rules[0]/match/subjects")
 }
@@ -931,7 +931,7 @@ func Test_BackGroundUserInfo_mutate_overlay1(t *testing.T) {
 	err = json.Unmarshal(rawPolicy, &policy)
 	assert.NilError(t, err)
 
-	err = ContainsVariablesOtherThanObject(*policy)
+	err = ruleContainsVariablesOtherThanObject(*policy, 0, policy.Spec.Rules[0], false)
 	assert.Assert(t, err != nil)
 }
 
@@ -962,7 +962,7 @@ func Test_BackGroundUserInfo_mutate_overlay2(t *testing.T) {
 	err = json.Unmarshal(rawPolicy, &policy)
 	assert.NilError(t, err)
 
-	err = ContainsVariablesOtherThanObject(*policy)
+	err = ruleContainsVariablesOtherThanObject(*policy, 0, policy.Spec.Rules[0], false)
 	assert.Assert(t, err != nil)
 }
 
@@ -993,7 +993,7 @@ func Test_BackGroundUserInfo_validate_pattern(t *testing.T) {
 	err = json.Unmarshal(rawPolicy, &policy)
 	assert.NilError(t, err)
 
-	err = ContainsVariablesOtherThanObject(*policy)
+	err = ruleContainsVariablesOtherThanObject(*policy, 0, policy.Spec.Rules[0], false)
 	assert.Assert(t, err != nil, err)
 }
 
@@ -1028,7 +1028,7 @@ func Test_BackGroundUserInfo_validate_anyPattern(t *testing.T) {
 	err = json.Unmarshal(rawPolicy, &policy)
 	assert.NilError(t, err)
 
-	err = ContainsVariablesOtherThanObject(*policy)
+	err = ruleContainsVariablesOtherThanObject(*policy, 0, policy.Spec.Rules[0], false)
 	assert.Assert(t, err != nil)
 }
 
@@ -1063,7 +1063,7 @@ func Test_BackGroundUserInfo_validate_anyPattern_multiple_var(t *testing.T) {
 	err = json.Unmarshal(rawPolicy, &policy)
 	assert.NilError(t, err)
 
-	err = ContainsVariablesOtherThanObject(*policy)
+	err = ruleContainsVariablesOtherThanObject(*policy, 0, policy.Spec.Rules[0], false)
 	assert.Assert(t, err != nil)
 }
 
@@ -1098,7 +1098,7 @@ func Test_BackGroundUserInfo_validate_anyPattern_serviceAccount(t *testing.T) {
 	err = json.Unmarshal(rawPolicy, &policy)
 	assert.NilError(t, err)
 
-	err = ContainsVariablesOtherThanObject(*policy)
+	err = ruleContainsVariablesOtherThanObject(*policy, 0, policy.Spec.Rules[0], false)
 	assert.Assert(t, err != nil)
 }
 
@@ -1237,3 +1237,498 @@ func Test_doesMatchExcludeConflict(t *testing.T) {
 		}
 	}
 }
+
+func Test_validateRuleContext_ImageRegistry(t *testing.T) {
+	testcases := []struct {
+		description string
+		context     []byte
+		err         string
+	}{
+		{
+			description: "reference with variables",
+			context:     []byte(`[{"name":"image","imageRegistry":{"reference":"{{request.object.spec.containers[0].image}}","jmesPath":"configData.config.Labels"}}]`),
+		},
+		{
+			description: "static reference",
+			context:     []byte(`[{"name":"image","imageRegistry":{"reference":"ghcr.io/kyverno/kyverno:latest"}}]`),
+		},
+		{
+			description: "missing reference",
+			context:     []byte(`[{"name":"image","imageRegistry":{"jmesPath":"digest"}}]`),
+			err:         "a reference is required for imageRegistry context entry",
+		},
+		{
+			description: "invalid reference",
+			context:     []byte(`[{"name":"image","imageRegistry":{"reference":"ghcr.io/Kyverno"}}]`),
+			err:         "could not parse reference: ghcr.io/Kyverno",
+		},
+		{
+			description: "invalid JMESPath",
+			context:     []byte(`[{"name":"image","imageRegistry":{"reference":"nginx","jmesPath":"configData.["}}]`),
+			err:         "failed to parse JMESPath configData.[",
+		},
+		{
+			description: "apiCall and imageRegistry",
+			context:     []byte(`[{"name":"image","apiCall":{"urlPath":"/api/v1/namespaces"},"imageRegistry":{"reference":"nginx"}}]`),
+			err:         "both apiCall and imageRegistry are not allowed in a context entry",
+		},
+	}
+
+	for _, testcase := range testcases {
+		var rule kyverno.Rule
+		err := json.Unmarshal(testcase.context, &rule.Context)
+		assert.NilError(t, err, testcase.description)
+
+		err = validateRuleContext(rule)
+		if testcase.err == "" {
+			assert.NilError(t, err, testcase.description)
+		} else {
+			assert.ErrorContains(t, err, testcase.err, testcase.description)
+		}
+	}
+}
+
+func Test_validateRuleContext_ServiceCall(t *testing.T) {
+	testcases := []struct {
+		description string
+		context     []byte
+		err         string
+	}{
+		{
+			description: "url with variables",
+			context:     []byte(`[{"name":"app","serviceCall":{"url":"https://cmdb.corp.com/apps/{{request.namespace}}","jmesPath":"owner","timeoutSeconds":2,"cacheSeconds":60}}]`),
+		},
+		{
+			description: "auth secret",
+			context:     []byte(`[{"name":"app","serviceCall":{"url":"https://cmdb.corp.com/apps","authSecret":{"name":"cmdb","namespace":"kyverno","key":"token"}}}]`),
+		},
+		{
+			description: "missing url",
+			context:     []byte(`[{"name":"app","serviceCall":{"jmesPath":"owner"}}]`),
+			err:         "a url is required for serviceCall context entry",
+		},
+		{
+			description: "http url",
+			context:     []byte(`[{"name":"app","serviceCall":{"url":"http://cmdb.corp.com/apps"}}]`),
+			err:         "an https url is required for serviceCall context entry",
+		},
+		{
+			description: "invalid caBundle",
+			context:     []byte(`[{"name":"app","serviceCall":{"url":"https://cmdb.corp.com/apps","caBundle":"not a certificate"}}]`),
+			err:         "caBundle of serviceCall context entry does not contain any PEM encoded certificate",
+		},
+		{
+			description: "incomplete auth secret",
+			context:     []byte(`[{"name":"app","serviceCall":{"url":"https://cmdb.corp.com/apps","authSecret":{"name":"cmdb","namespace":"kyverno"}}}]`),
+			err:         "a name, namespace and key are required for the authSecret of serviceCall context entry",
+		},
+		{
+			description: "negative timeout",
+			context:     []byte(`[{"name":"app","serviceCall":{"url":"https://cmdb.corp.com/apps","timeoutSeconds":-1}}]`),
+			err:         "timeoutSeconds of serviceCall context entry must not be negative",
+		},
+		{
+			description: "invalid JMESPath",
+			context:     []byte(`[{"name":"app","serviceCall":{"url":"https://cmdb.corp.com/apps","jmesPath":"owner.["}}]`),
+			err:         "failed to parse JMESPath owner.[",
+		},
+		{
+			description: "apiCall and serviceCall",
+			context:     []byte(`[{"name":"app","apiCall":{"urlPath":"/api/v1/namespaces"},"serviceCall":{"url":"https://cmdb.corp.com/apps"}}]`),
+			err:         "both apiCall and serviceCall are not allowed in a context entry",
+		},
+	}
+
+	for _, testcase := range testcases {
+		var rule kyverno.Rule
+		err := json.Unmarshal(testcase.context, &rule.Context)
+		assert.NilError(t, err, testcase.description)
+
+		err = validateRuleContext(rule)
+		if testcase.err == "" {
+			assert.NilError(t, err, testcase.description)
+		} else {
+			assert.ErrorContains(t, err, testcase.err, testcase.description)
+		}
+	}
+}
+
+func Test_validateRuleContext_GlobalReference(t *testing.T) {
+	testcases := []struct {
+		description string
+		context     []byte
+		err         string
+	}{
+		{
+			description: "global reference",
+			context:     []byte(`[{"name":"registries","globalReference":{"name":"allowed-registries","jmesPath":"items[].name"}}]`),
+		},
+		{
+			description: "missing name",
+			context:     []byte(`[{"name":"registries","globalReference":{"jmesPath":"items[].name"}}]`),
+			err:         "a name is required for globalReference context entry",
+		},
+		{
+			description: "invalid JMESPath",
+			context:     []byte(`[{"name":"registries","globalReference":{"name":"allowed-registries","jmesPath":"items[."}}]`),
+			err:         "failed to parse JMESPath items[.",
+		},
+		{
+			description: "serviceCall and globalReference",
+			context:     []byte(`[{"name":"registries","serviceCall":{"url":"https://cmdb.corp.com/registries"},"globalReference":{"name":"allowed-registries"}}]`),
+			err:         "both serviceCall and globalReference are not allowed in a context entry",
+		},
+	}
+
+	for _, testcase := range testcases {
+		var rule kyverno.Rule
+		err := json.Unmarshal(testcase.context, &rule.Context)
+		assert.NilError(t, err, testcase.description)
+
+		err = validateRuleContext(rule)
+		if testcase.err == "" {
+			assert.NilError(t, err, testcase.description)
+		} else {
+			assert.ErrorContains(t, err, testcase.err, testcase.description)
+		}
+	}
+}
+
+func Test_validateRuleContext_Secret(t *testing.T) {
+	testcases := []struct {
+		description string
+		context     []byte
+		err         string
+	}{
+		{
+			description: "secret",
+			context:     []byte(`[{"name":"signingKey","secret":{"name":"cosign","namespace":"kyverno","key":"cosign.pub"}}]`),
+		},
+		{
+			description: "secret with variables",
+			context:     []byte(`[{"name":"signingKey","secret":{"name":"{{request.object.metadata.labels.team}}-key","namespace":"{{request.namespace}}","key":"cosign.pub"}}]`),
+		},
+		{
+			description: "missing key",
+			context:     []byte(`[{"name":"signingKey","secret":{"name":"cosign","namespace":"kyverno"}}]`),
+			err:         "a name, namespace and key are required for secret context entry",
+		},
+		{
+			description: "configMap and secret",
+			context:     []byte(`[{"name":"signingKey","configMap":{"name":"keys","namespace":"kyverno"},"secret":{"name":"cosign","namespace":"kyverno","key":"cosign.pub"}}]`),
+			err:         "both configMap and secret are not allowed in a context entry",
+		},
+		{
+			description: "missing source",
+			context:     []byte(`[{"name":"signingKey"}]`),
+			err:         "a configMap, apiCall, imageRegistry, serviceCall, globalReference or secret is required for context entries",
+		},
+	}
+
+	for _, testcase := range testcases {
+		var rule kyverno.Rule
+		err := json.Unmarshal(testcase.context, &rule.Context)
+		assert.NilError(t, err, testcase.description)
+
+		err = validateRuleContext(rule)
+		if testcase.err == "" {
+			assert.NilError(t, err, testcase.description)
+		} else {
+			assert.ErrorContains(t, err, testcase.err, testcase.description)
+		}
+	}
+}
+
+func Test_Validate_Variables(t *testing.T) {
+	testcases := []struct {
+		description string
+		variables   string
+		message     string
+		err         string
+	}{
+		{
+			description: "declared variables and context entries",
+			variables:   `[{"name": "maxReplicas", "type": "number", "default": 3}, {"name": "team"}]`,
+			message:     "{{ team }} allows {{ maxReplicas }} replicas for {{ request.object.metadata.name }} in {{ teams.payments }}",
+		},
+		{
+			description: "functions and projections",
+			variables:   `[{"name": "registries", "type": "array", "default": ["ghcr.io"]}]`,
+			message:     "{{ to_upper(request.object.spec.containers[?name == 'nginx'].image | [0]) }} {{ length(registries) }}",
+		},
+		{
+			description: "undeclared variable",
+			variables:   `[{"name": "maxReplicas", "type": "number"}]`,
+			message:     "{{ maxReplica }} replicas",
+			err:         "path: spec.rules[0]: variable {{ maxReplica }} references maxReplica which is not declared in spec.variables or in the rule context",
+		},
+		{
+			description: "undeclared variable in function argument",
+			variables:   `[{"name": "maxReplicas", "type": "number"}]`,
+			message:     "{{ add(request.object.spec.replicas, minReplicas) }} replicas",
+			err:         "references minReplicas which is not declared",
+		},
+		{
+			description: "malformed variable",
+			variables:   `[{"name": "maxReplicas"}]`,
+			message:     "{{ maxReplicas[ }} replicas",
+			err:         "path: spec.rules[0]: invalid variable {{ maxReplicas[ }}",
+		},
+		{
+			description: "invalid name",
+			variables:   `[{"name": "max-replicas"}]`,
+			message:     "replicas",
+			err:         "path: spec.variables[0].name: invalid variable name \"max-replicas\"",
+		},
+		{
+			description: "built-in name",
+			variables:   `[{"name": "request"}]`,
+			message:     "replicas",
+			err:         "path: spec.variables[0].name: variable request is a built-in variable",
+		},
+		{
+			description: "duplicate name",
+			variables:   `[{"name": "maxReplicas"}, {"name": "maxReplicas"}]`,
+			message:     "replicas",
+			err:         "path: spec.variables[1].name: duplicate variable maxReplicas",
+		},
+		{
+			description: "invalid type",
+			variables:   `[{"name": "maxReplicas", "type": "integer"}]`,
+			message:     "replicas",
+			err:         "path: spec.variables[0].type: invalid type integer of variable maxReplicas",
+		},
+		{
+			description: "default of another type",
+			variables:   `[{"name": "maxReplicas", "type": "number", "default": "3"}]`,
+			message:     "replicas",
+			err:         "path: spec.variables[0].default: default value 3 of variable maxReplicas is not of type number",
+		},
+	}
+
+	openAPIController, _ := openapi.NewOpenAPIController()
+	for _, testcase := range testcases {
+		rawPolicy := []byte(`{
+			"apiVersion": "kyverno.io/v1",
+			"kind": "ClusterPolicy",
+			"metadata": {"name": "replicas"},
+			"spec": {
+				"background": false,
+				"variables": ` + testcase.variables + `,
+				"rules": [
+					{
+						"name": "max-replicas",
+						"context": [{"name": "teams", "configMap": {"name": "teams", "namespace": "default"}}],
+						"match": {"resources": {"kinds": ["Deployment"]}},
+						"validate": {
+							"message": "` + testcase.message + `",
+							"deny": {}
+						}
+					}
+				]
+			}
+		}`)
+
+		var policy *kyverno.ClusterPolicy
+		err := json.Unmarshal(rawPolicy, &policy)
+		assert.NilError(t, err, testcase.description)
+
+		err = Validate(policy, nil, true, openAPIController)
+		if testcase.err == "" {
+			assert.NilError(t, err, testcase.description)
+		} else {
+			assert.ErrorContains(t, err, testcase.err, testcase.description)
+		}
+	}
+}
+
+func Test_variableRoots(t *testing.T) {
+	testcases := []struct {
+		expr  string
+		roots []string
+	}{
+		{expr: "request.object.metadata.name", roots: []string{"request"}},
+		{expr: "maxReplicas", roots: []string{"maxReplicas"}},
+		{expr: "\"tls.crt\".data", roots: []string{"tls.crt"}},
+		{expr: "add(request.object.spec.replicas, minReplicas)", roots: []string{"request", "minReplicas"}},
+		{expr: "time_now()", roots: nil},
+		{expr: "request.object.spec.containers[?name == 'nginx' && image != other].image", roots: []string{"request"}},
+		{expr: "items[*].{n: name, t: team} | [0].n", roots: []string{"items"}},
+		{expr: "{name: request.object.metadata.name, max: maxReplicas}", roots: []string{"request", "maxReplicas"}},
+		{expr: "[request.name, team][0]", roots: []string{"request", "team"}},
+		{expr: "sort_by(request.object.items, &metadata.name)", roots: []string{"request"}},
+		{expr: "element.name || defaultName", roots: []string{"element", "defaultName"}},
+		{expr: "!(enabled) && `true`", roots: []string{"enabled"}},
+		{expr: "@.name", roots: nil},
+		{expr: "`{\"name\": \"team\"}`", roots: nil},
+		{expr: "request.object.metadata.labels.\"app.kubernetes.io/name\"", roots: []string{"request"}},
+		{expr: "split(image, ':')[0] == request.object.spec.image", roots: []string{"image", "request"}},
+		{expr: "map(&to_upper(name), request.items)", roots: []string{"request"}},
+	}
+
+	for _, testcase := range testcases {
+		assert.DeepEqual(t, variableRoots(testcase.expr), testcase.roots)
+	}
+}
+
+func Test_Lint(t *testing.T) {
+	rawPolicy := []byte(`
+	{
+		"metadata": {
+		   "name": "lint"
+		},
+		"spec": {
+		   "rules": [
+			  {
+				 "name": "check-memory",
+				 "match": {
+					"resources": {
+					   "kinds": ["Pod"]
+					}
+				 },
+				 "validate": {
+					"pattern": {
+					   "metadata": {
+						  "name": "{{ request.object.metadata.name }}",
+						  "labels": {
+							 "owner": "{{ owner.name }}"
+						  }
+					   },
+					   "spec": {
+						  "containers": [
+							 {
+								"resources": {
+								   "limits": {
+									  "memory": "<=1Gi",
+									  "cpu": ">high"
+								   }
+								}
+							 }
+						  ]
+					   }
+					}
+				 }
+			  },
+			  {
+				 "name": "check-memory-copy",
+				 "match": {
+					"resources": {
+					   "kinds": ["Pod"]
+					}
+				 },
+				 "validate": {
+					"pattern": {
+					   "metadata": {
+						  "name": "{{ request.object.metadata.name }}",
+						  "labels": {
+							 "owner": "{{ owner.name }}"
+						  }
+					   },
+					   "spec": {
+						  "containers": [
+							 {
+								"resources": {
+								   "limits": {
+									  "memory": "<=1Gi",
+									  "cpu": ">high"
+								   }
+								}
+							 }
+						  ]
+					   }
+					}
+				 }
+			  },
+			  {
+				 "name": "disabled",
+				 "match": {
+					"resources": {
+					   "kinds": ["Pod"]
+					}
+				 },
+				 "preconditions": [
+					{
+					   "key": "a",
+					   "operator": "Equals",
+					   "value": "b"
+					}
+				 ],
+				 "validate": {
+					"deny": {}
+				 }
+			  }
+		   ]
+		}
+	 }`)
+
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal(rawPolicy, &policy)
+	assert.NilError(t, err)
+
+	warnings := Lint(policy)
+	assert.DeepEqual(t, warnings, []string{
+		"rules[1] (check-memory-copy): the rule is a duplicate of rule check-memory",
+		"rules[2] (disabled): the rule is never applied, the precondition 'a Equals b' is never satisfied",
+		"rules[0] (check-memory): variable owner is not defined, it is not a built-in variable or a context entry of the rule",
+		"rules[1] (check-memory-copy): variable owner is not defined, it is not a built-in variable or a context entry of the rule",
+		"rules[0] (check-memory): pattern >high never matches at /spec/containers/0/resources/limits/cpu: operator > is not applicable to \"high\", it is not a quantity or a semantic version",
+		"rules[1] (check-memory-copy): pattern >high never matches at /spec/containers/0/resources/limits/cpu: operator > is not applicable to \"high\", it is not a quantity or a semantic version",
+	})
+}
+
+func Test_Lint_ValidPolicy(t *testing.T) {
+	rawPolicy := []byte(`
+	{
+		"metadata": {
+		   "name": "lint"
+		},
+		"spec": {
+		   "rules": [
+			  {
+				 "name": "check-owner",
+				 "context": [
+					{
+					   "name": "owner",
+					   "configMap": {
+						  "name": "owners",
+						  "namespace": "default"
+					   }
+					}
+				 ],
+				 "match": {
+					"resources": {
+					   "kinds": ["Pod"]
+					}
+				 },
+				 "validate": {
+					"pattern": {
+					   "metadata": {
+						  "labels": {
+							 "owner": "{{ owner.data.name }}"
+						  }
+					   },
+					   "spec": {
+						  "containers": [
+							 {
+								"image": "!*:latest",
+								"resources": {
+								   "limits": {
+									  "memory": "<=1Gi & >=100Mi"
+								   }
+								}
+							 }
+						  ]
+					   }
+					}
+				 }
+			  }
+		   ]
+		}
+	 }`)
+
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal(rawPolicy, &policy)
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(Lint(policy)), 0)
+}
@@ -0,0 +1,134 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jmespath/go-jmespath"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine"
+	"github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/kyverno/common"
+	"github.com/kyverno/kyverno/pkg/utils"
+)
+
+// builtInVariables are added to the context by the engine, they are not declared by policies
+var builtInVariables = []string{"request", "serviceAccountName", "serviceAccountNamespace", "namespaceLabels", "namespaceAnnotations", "element", "elementIndex", "target"}
+
+var variableTypes = []string{"string", "number", "boolean", "array", "object"}
+
+var regexVariableName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// validateVariableDeclarations checks the names, types and default values of the declared variables
+func validateVariableDeclarations(p kyverno.ClusterPolicy) (string, error) {
+	names := make(map[string]bool)
+	for i, variable := range p.Spec.Variables {
+		path := fmt.Sprintf("variables[%d]", i)
+		if !regexVariableName.MatchString(variable.Name) {
+			return path + ".name", fmt.Errorf("invalid variable name %q: must start with a letter or an underscore and contain only letters, digits and underscores", variable.Name)
+		}
+
+		if utils.ContainsString(builtInVariables, variable.Name) {
+			return path + ".name", fmt.Errorf("variable %s is a built-in variable", variable.Name)
+		}
+
+		if names[variable.Name] {
+			return path + ".name", fmt.Errorf("duplicate variable %s", variable.Name)
+		}
+		names[variable.Name] = true
+
+		if variable.Type == "" {
+			continue
+		}
+
+		if !utils.ContainsString(variableTypes, variable.Type) {
+			return path + ".type", fmt.Errorf("invalid type %s of variable %s: must be one of %s", variable.Type, variable.Name, strings.Join(variableTypes, ", "))
+		}
+
+		if variable.Default != nil && !engine.HasVariableType(variable.Default, variable.Type) {
+			return path + ".default", fmt.Errorf("default value %v of variable %s is not of type %s", variable.Default, variable.Name, variable.Type)
+		}
+	}
+
+	return "", nil
+}
+
+// validateVariableReferences checks that the variables of the rules are valid JMESPath expressions
+// which only reference built-in variables, declared variables and the context entries of the rule.
+// The references are only checked when the policy declares its variables.
+func validateVariableReferences(p kyverno.ClusterPolicy) (string, error) {
+	if len(p.Spec.Variables) == 0 {
+		return "", nil
+	}
+
+	declared := append([]string{}, builtInVariables...)
+	for _, variable := range p.Spec.Variables {
+		declared = append(declared, variable.Name)
+	}
+
+	for i, rule := range p.Spec.Rules {
+		known := append([]string{}, declared...)
+		for _, entry := range rule.Context {
+			known = append(known, entry.Name)
+		}
+
+		strs, err := ruleStrings(rule)
+		if err != nil {
+			return fmt.Sprintf("rules[%d]", i), err
+		}
+
+		for _, str := range strs {
+			for _, variable := range common.RegexVariables.FindAllString(str, -1) {
+				expr := strings.TrimSpace(variable[2 : len(variable)-2])
+				if _, err := jmespath.Compile(expr); err != nil {
+					return fmt.Sprintf("rules[%d]", i), fmt.Errorf("invalid variable %s: %v", variable, err)
+				}
+
+				for _, root := range variableRoots(expr) {
+					if !utils.ContainsString(known, root) {
+						return fmt.Sprintf("rules[%d]", i), fmt.Errorf("variable %s references %s which is not declared in spec.variables or in the rule context", variable, root)
+					}
+				}
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// collectStrings returns the keys and the string values of an unmarshalled JSON document
+func collectStrings(data interface{}, strs []string) []string {
+	switch v := data.(type) {
+	case string:
+		return append(strs, v)
+	case []interface{}:
+		for _, item := range v {
+			strs = collectStrings(item, strs)
+		}
+	case map[string]interface{}:
+		for key, value := range v {
+			strs = collectStrings(value, append(strs, key))
+		}
+	}
+
+	return strs
+}
+
+// variableRoots returns the identifiers of a JMESPath expression which are evaluated against
+// the context, e.g. request and maxReplicas in add(request.object.spec.replicas, maxReplicas).
+// Identifiers evaluated against other values, in sub-expressions, projections, filters, pipes and
+// expression references, and the names of the functions are not returned.
+func variableRoots(expr string) []string {
+	ast, err := jmespath.NewParser().Parse(expr)
+	if err != nil {
+		return nil
+	}
+
+	var roots []string
+	for _, path := range context.VariablePaths(ast) {
+		roots = append(roots, path[0])
+	}
+
+	return roots
+}
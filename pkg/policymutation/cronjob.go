@@ -48,7 +48,7 @@ func generateCronJobRule(rule kyverno.Rule, controllers string, log logr.Logger)
 		}
 
 		cronJobRule.Mutation = newMutation.DeepCopy()
-		return *cronJobRule
+		return rewriteRequestVariables(*cronJobRule, "jobTemplate", logger)
 	}
 
 	if (jobRule.Mutation != nil) && (jobRule.Mutation.PatchStrategicMerge != nil) {
@@ -60,7 +60,7 @@ func generateCronJobRule(rule kyverno.Rule, controllers string, log logr.Logger)
 			},
 		}
 		cronJobRule.Mutation = newMutation.DeepCopy()
-		return *cronJobRule
+		return rewriteRequestVariables(*cronJobRule, "jobTemplate", logger)
 	}
 
 	if (jobRule.Validation != nil) && (jobRule.Validation.Pattern != nil) {
@@ -73,7 +73,7 @@ func generateCronJobRule(rule kyverno.Rule, controllers string, log logr.Logger)
 			},
 		}
 		cronJobRule.Validation = newValidate.DeepCopy()
-		return *cronJobRule
+		return rewriteRequestVariables(*cronJobRule, "jobTemplate", logger)
 	}
 
 	if (jobRule.Validation != nil) && (jobRule.Validation.AnyPattern != nil) {
@@ -97,7 +97,39 @@ func generateCronJobRule(rule kyverno.Rule, controllers string, log logr.Logger)
 			Message:    rule.Validation.Message,
 			AnyPattern: patterns,
 		}
-		return *cronJobRule
+		return rewriteRequestVariables(*cronJobRule, "jobTemplate", logger)
+	}
+
+	if (jobRule.Mutation != nil) && (jobRule.Mutation.PatchesJSON6902 != "") {
+		patchesJSON6902, err := nestPatchesJSON6902(jobRule.Mutation.PatchesJSON6902, "jobTemplate")
+		if err != nil {
+			logger.Error(err, "failed to generate patchesJson6902 for cronJob")
+			return kyvernoRule{}
+		}
+
+		cronJobRule.Mutation = &kyverno.Mutation{
+			PatchesJSON6902:   patchesJSON6902,
+			MissingPathPolicy: jobRule.Mutation.MissingPathPolicy,
+		}
+		return rewriteRequestVariables(*cronJobRule, "jobTemplate", logger)
+	}
+
+	if (jobRule.Mutation != nil) && (jobRule.Mutation.ForEachMutation != nil) {
+		foreach, err := nestForEachMutation(jobRule.Mutation.ForEachMutation, "jobTemplate")
+		if err != nil {
+			logger.Error(err, "failed to generate foreach mutation for cronJob")
+			return kyvernoRule{}
+		}
+
+		cronJobRule.Mutation = &kyverno.Mutation{
+			ForEachMutation:   foreach,
+			MissingPathPolicy: jobRule.Mutation.MissingPathPolicy,
+		}
+		return rewriteRequestVariables(*cronJobRule, "jobTemplate", logger)
+	}
+
+	if (jobRule.Validation != nil) && (jobRule.Validation.Deny != nil || jobRule.Validation.ForEachValidation != nil) {
+		return rewriteRequestVariables(*cronJobRule, "jobTemplate", logger)
 	}
 
 	return kyvernoRule{}
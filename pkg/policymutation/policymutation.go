@@ -1,6 +1,7 @@
 package policymutation
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine"
 	"github.com/kyverno/kyverno/pkg/utils"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"sigs.k8s.io/yaml"
 )
 
 // GenerateJSONPatchesForDefaults generates default JSON patches for
@@ -358,6 +360,9 @@ func generateRulePatches(policy kyverno.ClusterPolicy, controllers string, log l
 
 type kyvernoRule struct {
 	Name             string                    `json:"name"`
+	Category         string                    `json:"category,omitempty"`
+	Severity         string                    `json:"severity,omitempty"`
+	ReportProperties map[string]string         `json:"reportProperties,omitempty"`
 	MatchResources   *kyverno.MatchResources   `json:"match"`
 	ExcludeResources *kyverno.ExcludeResources `json:"exclude,omitempty"`
 	Context          *[]kyverno.ContextEntry   `json:"context,omitempty"`
@@ -383,7 +388,14 @@ func generateRuleForControllers(rule kyverno.Rule, controllers string, log logr.
 		return kyvernoRule{}
 	}
 
-	if rule.Mutation.Overlay == nil && !rule.HasValidate() && rule.Mutation.PatchStrategicMerge == nil {
+	if rule.Mutation.Overlay == nil && !rule.HasValidate() && rule.Mutation.PatchStrategicMerge == nil &&
+		rule.Mutation.PatchesJSON6902 == "" && rule.Mutation.ForEachMutation == nil {
+		return kyvernoRule{}
+	}
+
+	// the targets of mutate existing rules are not nested in the pod template
+	if rule.HasMutateExisting() {
+		logger.V(4).Info("skip generating rule on pod controllers: mutate existing rules are not supported", "rule", rule.Name)
 		return kyvernoRule{}
 	}
 
@@ -422,8 +434,11 @@ func generateRuleForControllers(rule kyverno.Rule, controllers string, log logr.
 		name = name[:63]
 	}
 	controllerRule := &kyvernoRule{
-		Name:           name,
-		MatchResources: match.DeepCopy(),
+		Name:             name,
+		Category:         rule.Category,
+		Severity:         rule.Severity,
+		ReportProperties: rule.ReportProperties,
+		MatchResources:   match.DeepCopy(),
 	}
 
 	if len(rule.Context) > 0 {
@@ -462,7 +477,7 @@ func generateRuleForControllers(rule kyverno.Rule, controllers string, log logr.
 		}
 
 		controllerRule.Mutation = newMutation.DeepCopy()
-		return *controllerRule
+		return rewriteRequestVariables(*controllerRule, "template", logger)
 	}
 
 	if rule.Mutation.PatchStrategicMerge != nil {
@@ -475,7 +490,7 @@ func generateRuleForControllers(rule kyverno.Rule, controllers string, log logr.
 		}
 
 		controllerRule.Mutation = newMutation.DeepCopy()
-		return *controllerRule
+		return rewriteRequestVariables(*controllerRule, "template", logger)
 	}
 
 	if rule.Validation.Pattern != nil {
@@ -488,7 +503,7 @@ func generateRuleForControllers(rule kyverno.Rule, controllers string, log logr.
 			},
 		}
 		controllerRule.Validation = newValidate.DeepCopy()
-		return *controllerRule
+		return rewriteRequestVariables(*controllerRule, "template", logger)
 	}
 
 	if rule.Validation.AnyPattern != nil {
@@ -512,12 +527,126 @@ func generateRuleForControllers(rule kyverno.Rule, controllers string, log logr.
 			Message:    rule.Validation.Message,
 			AnyPattern: patterns,
 		}
-		return *controllerRule
+		return rewriteRequestVariables(*controllerRule, "template", logger)
+	}
+
+	if rule.Mutation.PatchesJSON6902 != "" {
+		patchesJSON6902, err := nestPatchesJSON6902(rule.Mutation.PatchesJSON6902, "template")
+		if err != nil {
+			logger.Error(err, "failed to generate patchesJson6902 for pod controllers")
+			return kyvernoRule{}
+		}
+
+		controllerRule.Mutation = &kyverno.Mutation{
+			PatchesJSON6902:   patchesJSON6902,
+			MissingPathPolicy: rule.Mutation.MissingPathPolicy,
+		}
+		return rewriteRequestVariables(*controllerRule, "template", logger)
+	}
+
+	if rule.Mutation.ForEachMutation != nil {
+		foreach, err := nestForEachMutation(rule.Mutation.ForEachMutation, "template")
+		if err != nil {
+			logger.Error(err, "failed to generate foreach mutation for pod controllers")
+			return kyvernoRule{}
+		}
+
+		controllerRule.Mutation = &kyverno.Mutation{
+			ForEachMutation:   foreach,
+			MissingPathPolicy: rule.Mutation.MissingPathPolicy,
+		}
+		return rewriteRequestVariables(*controllerRule, "template", logger)
+	}
+
+	// deny conditions and foreach declarations reference the pod through variables
+	if rule.Validation.Deny != nil || rule.Validation.ForEachValidation != nil {
+		controllerRule.Validation = &kyverno.Validation{
+			Message:           rule.Validation.Message,
+			Deny:              rule.Validation.DeepCopy().Deny,
+			ForEachValidation: rule.Validation.DeepCopy().ForEachValidation,
+		}
+		return rewriteRequestVariables(*controllerRule, "template", logger)
 	}
 
 	return kyvernoRule{}
 }
 
+// nestPatchesJSON6902 prefixes the paths of the patches with "/spec/<key>",
+// e.g. "/spec/containers/0/image" becomes "/spec/template/spec/containers/0/image"
+func nestPatchesJSON6902(patchesJSON6902 string, key string) (string, error) {
+	patchesRaw, err := yaml.YAMLToJSON([]byte(patchesJSON6902))
+	if err != nil {
+		return "", fmt.Errorf("failed to convert patchesJson6902 to JSON: %v", err)
+	}
+
+	var patches []map[string]interface{}
+	if err := json.Unmarshal(patchesRaw, &patches); err != nil {
+		return "", fmt.Errorf("failed to unmarshal patchesJson6902: %v", err)
+	}
+
+	prefix := "/spec/" + key
+	for _, patch := range patches {
+		for _, field := range []string{"path", "from"} {
+			if path, ok := patch[field].(string); ok {
+				patch[field] = prefix + path
+			}
+		}
+	}
+
+	nestedRaw, err := json.Marshal(patches)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal patchesJson6902: %v", err)
+	}
+
+	return string(nestedRaw), nil
+}
+
+// nestForEachMutation nests the patches of the foreach declaration under "spec.<key>"
+func nestForEachMutation(foreach *kyverno.ForEachMutation, key string) (*kyverno.ForEachMutation, error) {
+	nested := &kyverno.ForEachMutation{}
+	foreach.DeepCopyInto(nested)
+	if nested.PatchStrategicMerge != nil {
+		nested.PatchStrategicMerge = map[string]interface{}{
+			"spec": map[string]interface{}{
+				key: nested.PatchStrategicMerge,
+			},
+		}
+	}
+
+	if nested.PatchesJSON6902 != "" {
+		patchesJSON6902, err := nestPatchesJSON6902(nested.PatchesJSON6902, key)
+		if err != nil {
+			return nil, err
+		}
+		nested.PatchesJSON6902 = patchesJSON6902
+	}
+
+	return nested, nil
+}
+
+// rewriteRequestVariables replaces the variables referencing the spec of the request
+// object by the spec nested under "spec.<key>", e.g. "request.object.spec.containers"
+// becomes "request.object.spec.template.spec.containers"
+func rewriteRequestVariables(rule kyvernoRule, key string, log logr.Logger) kyvernoRule {
+	ruleRaw, err := json.Marshal(rule)
+	if err != nil {
+		log.Error(err, "failed to marshal generated rule", "rule", rule.Name)
+		return kyvernoRule{}
+	}
+
+	for _, object := range []string{"request.object.spec", "request.oldObject.spec"} {
+		ruleRaw = bytes.ReplaceAll(ruleRaw, []byte(object), []byte(fmt.Sprintf("%s.%s.spec", object, key)))
+	}
+
+	var rewritten kyvernoRule
+	if err := json.Unmarshal(ruleRaw, &rewritten); err != nil {
+		log.Error(err, "failed to unmarshal generated rule", "rule", rule.Name)
+		return kyvernoRule{}
+	}
+
+	return rewritten
+}
+
 // defaultPodControllerAnnotation inserts an annotation
 // "pod-policies.kyverno.io/autogen-controllers=DaemonSet,Deployment,Job,StatefulSet" to policy
 func defaultPodControllerAnnotation(ann map[string]string) ([]byte, error) {
@@ -152,3 +152,50 @@ func Test_CronJobAndDeployment(t *testing.T) {
 
 	assert.DeepEqual(t, rulePatches, expectedPatches)
 }
+
+func Test_PatchesJSON6902AndDeny(t *testing.T) {
+	policyRaw := []byte(`
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: pod-policy
+spec:
+  rules:
+  - name: set-image
+    match:
+      resources:
+        kinds:
+        - Pod
+    mutate:
+      patchesJson6902: |-
+        - op: replace
+          path: /spec/containers/0/image
+          value: "registry.io/{{request.object.spec.containers[0].image}}"
+  - name: deny-host-network
+    match:
+      resources:
+        kinds:
+        - Pod
+    validate:
+      message: host network is not allowed
+      deny:
+        conditions:
+        - key: "{{request.object.spec.hostNetwork}}"
+          operator: Equals
+          value: true
+`)
+	policies, err := utils.GetPolicy(policyRaw)
+	assert.NilError(t, err)
+
+	rulePatches, errs := generateRulePatches(*policies[0], "Deployment,CronJob", log.Log)
+	assert.Equal(t, len(errs), 0)
+
+	expectedPatches := [][]byte{
+		[]byte(`{"path":"/spec/rules/2","op":"add","value":{"name":"autogen-set-image","match":{"resources":{"kinds":["Deployment"]}},"mutate":{"patchesJson6902":"[{\"op\":\"replace\",\"path\":\"/spec/template/spec/containers/0/image\",\"value\":\"registry.io/{{request.object.spec.template.spec.containers[0].image}}\"}]"}}}`),
+		[]byte(`{"path":"/spec/rules/3","op":"add","value":{"name":"autogen-cronjob-set-image","match":{"resources":{"kinds":["CronJob"]}},"mutate":{"patchesJson6902":"[{\"op\":\"replace\",\"path\":\"/spec/jobTemplate/spec/template/spec/containers/0/image\",\"value\":\"registry.io/{{request.object.spec.jobTemplate.spec.template.spec.containers[0].image}}\"}]"}}}`),
+		[]byte(`{"path":"/spec/rules/4","op":"add","value":{"name":"autogen-deny-host-network","match":{"resources":{"kinds":["Deployment"]}},"validate":{"message":"host network is not allowed","deny":{"conditions":[{"key":"{{request.object.spec.template.spec.hostNetwork}}","operator":"Equals","value":true}]}}}}`),
+		[]byte(`{"path":"/spec/rules/5","op":"add","value":{"name":"autogen-cronjob-deny-host-network","match":{"resources":{"kinds":["CronJob"]}},"validate":{"message":"host network is not allowed","deny":{"conditions":[{"key":"{{request.object.spec.jobTemplate.spec.template.spec.hostNetwork}}","operator":"Equals","value":true}]}}}}`),
+	}
+
+	assert.DeepEqual(t, rulePatches, expectedPatches)
+}
@@ -3,6 +3,7 @@ package policyreport
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
@@ -89,6 +90,15 @@ func (builder *requestBuilder) build(info Info) (req *unstructured.Unstructured,
 			}
 
 			result := builder.buildRCRResult(info.PolicyName, infoResult.Resource, rule)
+			if owner := infoResult.Owner; owner != nil {
+				result.Resources = append([]*v1.ObjectReference{{
+					Kind:       owner.Kind,
+					Namespace:  owner.Namespace,
+					APIVersion: owner.APIVersion,
+					Name:       owner.Name,
+					UID:        types.UID(owner.UID),
+				}}, result.Resources...)
+			}
 			results = append(results, result)
 		}
 	}
@@ -132,6 +142,14 @@ func (builder *requestBuilder) build(info Info) (req *unstructured.Unstructured,
 }
 
 func (builder *requestBuilder) buildRCRResult(policy string, resource response.ResourceSpec, rule kyverno.ViolatedRule) *report.PolicyReportResult {
+	result := BuildResult(policy, resource, rule)
+	result.Category = builder.fetchCategory(policy, resource.Namespace, rule.Name)
+	return result
+}
+
+// BuildResult returns the policy report result of the rule applied on the resource, the results
+// of the report change requests and of the CLI are built the same way
+func BuildResult(policy string, resource response.ResourceSpec, rule kyverno.ViolatedRule) *report.PolicyReportResult {
 	result := &report.PolicyReportResult{
 		Policy: policy,
 		Resources: []*v1.ObjectReference{
@@ -144,15 +162,40 @@ func (builder *requestBuilder) buildRCRResult(policy string, resource response.R
 			},
 		},
 		Scored:   true,
-		Category: builder.fetchCategory(policy, resource.Namespace),
+		Severity: report.PolicySeverity(rule.Severity),
 	}
 
 	result.Rule = rule.Name
 	result.Message = rule.Message
 	result.Status = report.PolicyStatus(rule.Check)
+	result.Data = make(map[string]string, len(rule.Properties)+2)
+	for key, value := range rule.Properties {
+		result.Data[key] = value
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	result.Data[report.ResultTimestampKey] = now
+	result.Data[report.ResultLastSeenKey] = now
 	return result
 }
 
+// BuildResults returns the policy report results of the validation rules of the engine responses
+func BuildResults(ers []*response.EngineResponse, log logr.Logger) []*report.PolicyReportResult {
+	var results []*report.PolicyReportResult
+	for _, info := range GeneratePRsFromEngineResponse(ers, log) {
+		for _, infoResult := range info.Results {
+			for _, rule := range infoResult.Rules {
+				if rule.Type != utils.Validation.String() {
+					continue
+				}
+
+				results = append(results, BuildResult(info.PolicyName, infoResult.Resource, rule))
+			}
+		}
+	}
+
+	return results
+}
+
 func set(obj *unstructured.Unstructured, info Info) {
 	obj.SetAPIVersion(request.SchemeGroupVersion.Group + "/" + request.SchemeGroupVersion.Version)
 
@@ -228,8 +271,9 @@ func buildPVInfo(er *response.EngineResponse) Info {
 		Namespace:  er.PatchedResource.GetNamespace(),
 		Results: []EngineResponseResult{
 			{
-				Resource: er.GetResourceSpec(),
-				Rules:    buildViolatedRules(er),
+				Resource:   er.GetResourceSpec(),
+				Rules:      buildViolatedRules(er),
+				controller: metav1.GetControllerOf(&er.PatchedResource),
 			},
 		},
 	}
@@ -240,34 +284,35 @@ func buildViolatedRules(er *response.EngineResponse) []kyverno.ViolatedRule {
 	var violatedRules []kyverno.ViolatedRule
 	for _, rule := range er.PolicyResponse.Rules {
 		vrule := kyverno.ViolatedRule{
-			Name:    rule.Name,
-			Type:    rule.Type,
-			Message: rule.Message,
+			Name:       rule.Name,
+			Type:       rule.Type,
+			Message:    rule.Message,
+			Severity:   rule.Severity,
+			Properties: rule.Properties,
 		}
 		vrule.Check = report.StatusFail
 		if rule.Success {
 			vrule.Check = report.StatusPass
 		}
+		if rule.Skipped {
+			vrule.Check = report.StatusSkip
+		}
 		violatedRules = append(violatedRules, vrule)
 	}
 	return violatedRules
 }
 
-const categoryLabel string = "policies.kyverno.io/category"
-
-func (builder *requestBuilder) fetchCategory(policy, ns string) string {
+// fetchCategory returns the category of the rule, or the category annotation of the policy
+func (builder *requestBuilder) fetchCategory(policy, ns, rule string) string {
 	cpol, err := builder.cpolLister.Get(policy)
 	if err == nil {
-		if ann := cpol.GetAnnotations(); ann != nil {
-			return ann[categoryLabel]
-		}
+		return cpol.GetRuleCategory(rule)
 	}
 
 	pol, err := builder.polLister.Policies(ns).Get(policy)
 	if err == nil {
-		if ann := pol.GetAnnotations(); ann != nil {
-			return ann[categoryLabel]
-		}
+		cpol := kyverno.ClusterPolicy(*pol)
+		return cpol.GetRuleCategory(rule)
 	}
 
 	return ""
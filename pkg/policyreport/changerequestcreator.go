@@ -2,8 +2,10 @@ package policyreport
 
 import (
 	"crypto/rand"
+	"fmt"
 	"math/big"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +22,7 @@ type creator interface {
 	add(request *unstructured.Unstructured)
 	create(request *unstructured.Unstructured) error
 	run(stopChan <-chan struct{})
+	flush()
 }
 
 type changeRequestCreator struct {
@@ -34,6 +37,10 @@ type changeRequestCreator struct {
 	mutex sync.RWMutex
 	queue []string
 
+	// flushMutex serializes the flushes of the ticker and of the shutdown,
+	// so that the buffered requests are created once
+	flushMutex sync.Mutex
+
 	tickerInterval time.Duration
 
 	log logr.Logger
@@ -83,20 +90,28 @@ func (c *changeRequestCreator) run(stopChan <-chan struct{}) {
 	for {
 		select {
 		case <-ticker.C:
-			requests, size := c.mergeRequests()
-			for _, request := range requests {
-				if err := c.create(request); err != nil {
-					c.log.Error(err, "failed to create report change request", "req", request.Object)
-				}
-			}
-
-			c.cleanupQueue(size)
+			c.flush()
 		case <-stopChan:
 			return
 		}
 	}
 }
 
+// flush merges and creates the buffered requests
+func (c *changeRequestCreator) flush() {
+	c.flushMutex.Lock()
+	defer c.flushMutex.Unlock()
+
+	requests, size := c.mergeRequests()
+	for _, request := range requests {
+		if err := c.create(request); err != nil {
+			c.log.Error(err, "failed to create report change request", "req", request.Object)
+		}
+	}
+
+	c.cleanupQueue(size)
+}
+
 func (c *changeRequestCreator) cleanupQueue(size int) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -201,30 +216,73 @@ func merge(dst, src *unstructured.Unstructured) bool {
 
 	if dstResults, ok, _ := unstructured.NestedSlice(dst.UnstructuredContent(), "results"); ok {
 		if srcResults, ok, _ := unstructured.NestedSlice(src.UnstructuredContent(), "results"); ok {
-			dstResults = append(dstResults, srcResults...)
+			// the repeated results of a resource, e.g. a crash looping Pod, are merged
+			// into the latest result of the policy rule for the resource
+			dstResults = deduplicateResults(append(dstResults, srcResults...))
 
 			if err := unstructured.SetNestedSlice(dst.UnstructuredContent(), dstResults, "results"); err == nil {
-				addSummary(dst, src)
-				return true
+				if err := unstructured.SetNestedMap(dst.UnstructuredContent(), updateSummary(dstResults), "summary"); err == nil {
+					return true
+				}
 			}
 		}
 	}
 	return false
 }
 
-func addSummary(dst, src *unstructured.Unstructured) {
-	if dstSum, ok, _ := unstructured.NestedMap(dst.UnstructuredContent(), "summary"); ok {
-		if srcSum, ok, _ := unstructured.NestedMap(src.UnstructuredContent(), "summary"); ok {
-			for key, dstVal := range dstSum {
-				if dstValInt, ok := dstVal.(int64); ok {
-					if srcVal, ok := srcSum[key].(int64); ok {
-						dstSum[key] = dstValInt + srcVal
-					}
-				}
+// maxResultsPerResource limits the results of a resource in the merged change requests,
+// the most recently reported results are kept
+const maxResultsPerResource = 100
+
+// deduplicateResults keeps the last result of each policy, rule and resource, the repeated results
+// keep the timestamp of their first report and count the times they were reported
+func deduplicateResults(results []interface{}) []interface{} {
+	index := make(map[string]int, len(results))
+	deduplicated := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		resultMap, ok := result.(map[string]interface{})
+		if !ok {
+			deduplicated = append(deduplicated, result)
+			continue
+		}
+
+		if _, ok := resultMap["resources"].([]interface{}); !ok {
+			deduplicated = append(deduplicated, result)
+			continue
+		}
+
+		key, ok := generateHashKey(resultMap, deletedResource{})
+		if !ok {
+			deduplicated = append(deduplicated, result)
+			continue
+		}
+
+		if i, ok := index[key]; ok {
+			if sameResult(deduplicated[i], resultMap) {
+				deduplicated[i] = repeatResult(deduplicated[i].(map[string]interface{}), resultMap)
+			} else {
+				deduplicated[i] = result
 			}
+			continue
+		}
+
+		index[key] = len(deduplicated)
+		deduplicated = append(deduplicated, result)
+	}
+
+	return limitResults(deduplicated, maxResultsPerResource, resourceKey)
+}
+
+// resourceKey returns the key of the resources of the result
+func resourceKey(result map[string]interface{}) string {
+	resources, _ := result["resources"].([]interface{})
+	keys := make([]string, 0, len(resources))
+	for _, r := range resources {
+		if resource, ok := r.(map[string]interface{}); ok {
+			keys = append(keys, fmt.Sprintf("%s-%s-%s", resource["kind"], resource["namespace"], resource["name"]))
 		}
-		unstructured.SetNestedMap(dst.UnstructuredContent(), dstSum, "summary")
 	}
+	return strings.Join(keys, "-")
 }
 
 func isDeleteRequest(request *unstructured.Unstructured) bool {
@@ -0,0 +1,70 @@
+package policyreport
+
+import (
+	"fmt"
+	"testing"
+
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"gotest.tools/assert"
+)
+
+func Test_DeduplicateResults(t *testing.T) {
+	results := []interface{}{
+		newResult("require-team", "fail", "2021-04-01T10:00:00Z", "2021-04-01T10:00:00Z"),
+		newResult("require-app", "fail", "2021-04-01T10:00:00Z", "2021-04-01T10:00:00Z"),
+		newResult("require-team", "fail", "2021-04-01T10:05:00Z", "2021-04-01T10:05:00Z"),
+		newResult("require-team", "fail", "2021-04-01T10:10:00Z", "2021-04-01T10:10:00Z"),
+		newResult("require-app", "pass", "2021-04-01T10:10:00Z", "2021-04-01T10:10:00Z"),
+	}
+
+	deduplicated := deduplicateResults(results)
+	assert.Equal(t, len(deduplicated), 2)
+
+	// the repeated result keeps its first timestamp and counts the reports
+	team := deduplicated[0].(map[string]interface{})
+	data := team["data"].(map[string]interface{})
+	assert.Equal(t, team["rule"], "require-team")
+	assert.Equal(t, data[report.ResultTimestampKey], "2021-04-01T10:00:00Z")
+	assert.Equal(t, data[report.ResultLastSeenKey], "2021-04-01T10:10:00Z")
+	assert.Equal(t, data[report.ResultCountKey], "3")
+
+	// the changed result replaces the previous one
+	app := deduplicated[1].(map[string]interface{})
+	data = app["data"].(map[string]interface{})
+	assert.Equal(t, app["status"], "pass")
+	assert.Equal(t, data[report.ResultTimestampKey], "2021-04-01T10:10:00Z")
+	_, ok := data[report.ResultCountKey]
+	assert.Assert(t, !ok)
+}
+
+func Test_DeduplicateResults_MaxResultsPerResource(t *testing.T) {
+	var results []interface{}
+	for i := 0; i <= maxResultsPerResource; i++ {
+		timestamp := fmt.Sprintf("2021-04-01T%02d:%02d:00Z", i/60, i%60)
+		results = append(results, newResult(fmt.Sprintf("rule-%d", i), "fail", timestamp, timestamp))
+	}
+
+	deduplicated := deduplicateResults(results)
+	assert.Equal(t, len(deduplicated), maxResultsPerResource)
+
+	// the least recently reported result is removed
+	assert.Equal(t, deduplicated[0].(map[string]interface{})["rule"], "rule-1")
+}
+
+func Test_UpdateResults_SumsCounts(t *testing.T) {
+	old := newResult("require-team", "fail", "2021-04-01T10:00:00Z", "2021-04-01T10:05:00Z")
+	old["data"].(map[string]interface{})[report.ResultCountKey] = "2"
+	oldReport := map[string]interface{}{"results": []interface{}{old}}
+
+	new := newResult("require-team", "fail", "2021-04-01T10:10:00Z", "2021-04-01T10:10:00Z")
+	new["data"].(map[string]interface{})[report.ResultCountKey] = "3"
+	newReport := map[string]interface{}{"results": []interface{}{new}}
+
+	updated, _, err := updateResults(oldReport, newReport, nil, ResultRetention{})
+	assert.NilError(t, err)
+
+	data := updated["results"].([]interface{})[0].(map[string]interface{})["data"].(map[string]interface{})
+	assert.Equal(t, data[report.ResultTimestampKey], "2021-04-01T10:00:00Z")
+	assert.Equal(t, data[report.ResultLastSeenKey], "2021-04-01T10:10:00Z")
+	assert.Equal(t, data[report.ResultCountKey], "5")
+}
@@ -0,0 +1,80 @@
+package policyreport
+
+import (
+	"fmt"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maxOwnerDepth limits the ownerReferences walked for a resource, e.g. Pod -> ReplicaSet -> Deployment
+const maxOwnerDepth = 5
+
+// attributeToOwners sets the top-level controlling owner of the resources of the results, so that the
+// reports point at the resource users edit (e.g. the Deployment of a Pod). The results keep their
+// resource, the results of sibling resources are reported separately and removed with the resources.
+func (gen *Generator) attributeToOwners(info Info) Info {
+	if !gen.ownerAttribution || info.GetRuleLength() == 0 {
+		return info
+	}
+
+	for i, result := range info.Results {
+		if result.controller == nil {
+			continue
+		}
+
+		owner := gen.controllingOwner(result.Resource.Namespace, *result.controller)
+		info.Results[i].Owner = &owner
+	}
+
+	return info
+}
+
+// controllingOwner walks the controller ownerReferences from the controller of a resource and returns
+// the top-level owner, or the last owner found when an owner cannot be fetched. The owners are read
+// from the informer caches of their kinds.
+func (gen *Generator) controllingOwner(namespace string, controller metav1.OwnerReference) response.ResourceSpec {
+	owner := ownerSpec(namespace, controller)
+	for depth := 1; depth < maxOwnerDepth; depth++ {
+		obj, err := gen.getOwner(owner)
+		if err != nil {
+			gen.log.V(4).Info("failed to get owner", "ownerKind", owner.Kind, "namespace", owner.Namespace, "ownerName", owner.Name, "error", err.Error())
+			return owner
+		}
+
+		ref := metav1.GetControllerOf(obj)
+		if ref == nil {
+			return owner
+		}
+
+		owner = ownerSpec(namespace, *ref)
+	}
+
+	return owner
+}
+
+func (gen *Generator) getOwner(owner response.ResourceSpec) (*unstructured.Unstructured, error) {
+	genericCache, err := gen.resCache.CreateGVKInformer(owner.APIVersion + "/" + owner.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create informer: %v", err)
+	}
+
+	if genericCache.IsNamespaced() {
+		return genericCache.NamespacedLister(owner.Namespace).Get(owner.Name)
+	}
+
+	return genericCache.Lister().Get(owner.Name)
+}
+
+// ownerSpec returns the owner of the reference, the owners of namespaced resources are in the same
+// namespace, the namespace of the cluster-wide owners is ignored when they are fetched
+func ownerSpec(namespace string, ref metav1.OwnerReference) response.ResourceSpec {
+	return response.ResourceSpec{
+		Kind:       ref.Kind,
+		APIVersion: ref.APIVersion,
+		Namespace:  namespace,
+		Name:       ref.Name,
+		UID:        string(ref.UID),
+	}
+}
@@ -0,0 +1,138 @@
+package policyreport
+
+import (
+	"errors"
+	"testing"
+
+	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/resourcecache"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic/dynamiclister"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeGenericCache struct {
+	gvr     schema.GroupVersionResource
+	indexer cache.Indexer
+}
+
+func (c *fakeGenericCache) StopInformer() {}
+
+func (c *fakeGenericCache) IsNamespaced() bool { return true }
+
+func (c *fakeGenericCache) Lister() dynamiclister.Lister {
+	return dynamiclister.New(c.indexer, c.gvr)
+}
+
+func (c *fakeGenericCache) NamespacedLister(namespace string) dynamiclister.NamespaceLister {
+	return c.Lister().Namespace(namespace)
+}
+
+func (c *fakeGenericCache) GVR() schema.GroupVersionResource { return c.gvr }
+
+type fakeResourceCache struct {
+	caches map[string]*fakeGenericCache
+}
+
+func (c *fakeResourceCache) CreateInformers(resources ...string) []error { return nil }
+
+func (c *fakeResourceCache) CreateGVKInformer(kind string) (resourcecache.GenericCache, error) {
+	if genericCache, ok := c.caches[kind]; ok {
+		return genericCache, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (c *fakeResourceCache) StopResourceInformer(resource string) {}
+
+func (c *fakeResourceCache) GetGVRCache(resource string) (resourcecache.GenericCache, bool) {
+	genericCache, ok := c.caches[resource]
+	return genericCache, ok
+}
+
+func newOwned(apiVersion, kind, name string, owner *metav1.OwnerReference) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	if owner != nil {
+		obj.SetOwnerReferences([]metav1.OwnerReference{*owner})
+	}
+	return obj
+}
+
+func controllerRef(apiVersion, kind, name string) *metav1.OwnerReference {
+	controller := true
+	return &metav1.OwnerReference{APIVersion: apiVersion, Kind: kind, Name: name, UID: types.UID("uid-" + name), Controller: &controller}
+}
+
+func Test_AttributeToOwners(t *testing.T) {
+	replicaSets := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NilError(t, replicaSets.Add(newOwned("apps/v1", "ReplicaSet", "nginx-5d4f", controllerRef("apps/v1", "Deployment", "nginx"))))
+	deployments := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NilError(t, deployments.Add(newOwned("apps/v1", "Deployment", "nginx", nil)))
+
+	resCache := &fakeResourceCache{caches: map[string]*fakeGenericCache{
+		"apps/v1/ReplicaSet": {gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}, indexer: replicaSets},
+		"apps/v1/Deployment": {gvr: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, indexer: deployments},
+	}}
+
+	var ers []*response.EngineResponse
+	for _, name := range []string{"nginx-5d4f-a", "nginx-5d4f-b"} {
+		pod := newOwned("v1", "Pod", name, controllerRef("apps/v1", "ReplicaSet", "nginx-5d4f"))
+		ers = append(ers, &response.EngineResponse{
+			PatchedResource: *pod,
+			PolicyResponse: response.PolicyResponse{
+				Policy:   "require-team",
+				Resource: response.ResourceSpec{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: name},
+				Rules:    []response.RuleResponse{{Name: "check-team", Type: "Validation"}},
+			},
+		})
+	}
+	infos := GeneratePRsFromEngineResponse(ers, log.Log)
+	assert.Equal(t, len(infos), 2)
+
+	// the results are not attributed by default
+	gen := &Generator{resCache: resCache, log: log.Log}
+	assert.Assert(t, gen.attributeToOwners(infos[0]).Results[0].Owner == nil)
+
+	gen.ownerAttribution = true
+	for i := range infos {
+		infos[i] = gen.attributeToOwners(infos[i])
+		assert.DeepEqual(t, infos[i].Results[0].Owner, &response.ResourceSpec{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "nginx", UID: "uid-nginx"})
+	}
+
+	policies := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	builder := NewBuilder(kyvernolister.NewClusterPolicyLister(policies), kyvernolister.NewPolicyLister(policies))
+	var results []interface{}
+	for _, info := range infos {
+		req, err := builder.build(info)
+		assert.NilError(t, err)
+
+		reqResults, _, _ := unstructured.NestedSlice(req.UnstructuredContent(), "results")
+		assert.Equal(t, len(reqResults), 1)
+
+		resources := reqResults[0].(map[string]interface{})["resources"].([]interface{})
+		assert.Equal(t, resources[0].(map[string]interface{})["kind"], "Deployment")
+		assert.Equal(t, resources[1].(map[string]interface{})["kind"], "Pod")
+		results = append(results, reqResults...)
+	}
+
+	// the results of the sibling pods are kept separately
+	report := map[string]interface{}{"results": results}
+	assert.Equal(t, hashResults(report, nil).Len(), 2)
+
+	// the results of a deleted pod are removed
+	deleted := []deletedResource{{kind: "Pod", ns: "default", name: "nginx-5d4f-a"}}
+	remaining := getResultsFromHash(hashResults(report, deleted))
+	assert.Equal(t, len(remaining), 1)
+	resources := remaining[0].(map[string]interface{})["resources"].([]interface{})
+	assert.Equal(t, resources[1].(map[string]interface{})["name"], "nginx-5d4f-b")
+}
@@ -3,7 +3,10 @@ package policyreport
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cornelk/hashmap"
 	changerequest "github.com/kyverno/kyverno/pkg/api/kyverno/v1alpha1"
@@ -11,6 +14,20 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
+// ResultRetention limits the results kept in the policy reports, zero values are unlimited
+type ResultRetention struct {
+	// TTL is the maximum age of the results since they were last reported
+	TTL time.Duration
+
+	// MaxResultsPerPolicy is the maximum number of results of a policy in each report,
+	// the most recent results are kept
+	MaxResultsPerPolicy int
+}
+
+func (r ResultRetention) enabled() bool {
+	return r.TTL > 0 || r.MaxResultsPerPolicy > 0
+}
+
 type deletedResource struct {
 	kind, ns, name string
 }
@@ -53,7 +70,7 @@ func getDeletedResources(aggregatedRequests interface{}) (resources []deletedRes
 	return
 }
 
-func updateResults(oldReport, newReport map[string]interface{}, aggregatedRequests interface{}) (map[string]interface{}, bool, error) {
+func updateResults(oldReport, newReport map[string]interface{}, aggregatedRequests interface{}, retention ResultRetention) (map[string]interface{}, bool, error) {
 	deleteResources := getDeletedResources(aggregatedRequests)
 	oldResults := hashResults(oldReport, deleteResources)
 	var hasDuplicate bool
@@ -65,8 +82,13 @@ func updateResults(oldReport, newReport map[string]interface{}, aggregatedReques
 				continue
 			}
 			if key, ok := generateHashKey(resMap, deletedResource{}); ok {
-				if _, exist := oldResults.Get(key); exist {
+				if old, exist := oldResults.Get(key); exist {
 					hasDuplicate = exist
+					// the unchanged result keeps the timestamp of its first report
+					if sameResult(old, resMap) {
+						oldResults.Set(key, repeatResult(old.(map[string]interface{}), resMap))
+						continue
+					}
 				}
 
 				oldResults.Set(key, res)
@@ -74,7 +96,7 @@ func updateResults(oldReport, newReport map[string]interface{}, aggregatedReques
 		}
 	}
 
-	results := getResultsFromHash(oldResults)
+	results := pruneResults(getResultsFromHash(oldResults), retention, time.Now())
 	if err := unstructured.SetNestedSlice(newReport, results, "results"); err != nil {
 		return nil, hasDuplicate, err
 	}
@@ -86,6 +108,193 @@ func updateResults(oldReport, newReport map[string]interface{}, aggregatedReques
 	return newReport, hasDuplicate, nil
 }
 
+// sameResult checks if the results are equal, ignoring the timestamps and the counts
+func sameResult(old interface{}, new map[string]interface{}) bool {
+	oldMap, ok := old.(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	return reflect.DeepEqual(withoutTimestamp(oldMap), withoutTimestamp(new))
+}
+
+// repeatResult returns the old result with the last seen timestamp of the new result and
+// the sum of the counts of both results
+func repeatResult(old, new map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(old))
+	for k, v := range old {
+		result[k] = v
+	}
+
+	oldData, _ := old["data"].(map[string]interface{})
+	data := make(map[string]interface{}, len(oldData)+2)
+	for k, v := range oldData {
+		data[k] = v
+	}
+
+	newData, _ := new["data"].(map[string]interface{})
+	if lastSeen, ok := newData[report.ResultLastSeenKey]; ok {
+		data[report.ResultLastSeenKey] = lastSeen
+	}
+	data[report.ResultCountKey] = strconv.Itoa(resultCount(old) + resultCount(new))
+	result["data"] = data
+	return result
+}
+
+// resultCount returns how many times the result was reported, the results without count were reported once
+func resultCount(result map[string]interface{}) int {
+	data, _ := result["data"].(map[string]interface{})
+	value, ok := data[report.ResultCountKey].(string)
+	if !ok {
+		return 1
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 1 {
+		return 1
+	}
+	return count
+}
+
+func withoutTimestamp(result map[string]interface{}) map[string]interface{} {
+	data, ok := result["data"].(map[string]interface{})
+	if !ok {
+		return result
+	}
+
+	copied := make(map[string]interface{}, len(result))
+	for k, v := range result {
+		copied[k] = v
+	}
+
+	copiedData := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		if k != report.ResultTimestampKey && k != report.ResultLastSeenKey && k != report.ResultCountKey {
+			copiedData[k] = v
+		}
+	}
+
+	if len(copiedData) == 0 {
+		delete(copied, "data")
+	} else {
+		copied["data"] = copiedData
+	}
+	return copied
+}
+
+// pruneResults removes the results not reported again within the TTL and the least recently
+// reported results of the policies exceeding the maximum number of results, the results without
+// timestamp are never pruned by the TTL
+func pruneResults(results []interface{}, retention ResultRetention, now time.Time) []interface{} {
+	if !retention.enabled() {
+		return results
+	}
+
+	pruned := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		timestamp, ok := resultTimestamp(result)
+		if ok && retention.TTL > 0 && now.Sub(timestamp) > retention.TTL {
+			continue
+		}
+
+		pruned = append(pruned, result)
+	}
+
+	return limitResults(pruned, retention.MaxResultsPerPolicy, func(result map[string]interface{}) string {
+		policy, _ := result["policy"].(string)
+		return policy
+	})
+}
+
+// limitResults removes the least recently reported results of the groups exceeding the maximum
+// number of results, the results without timestamp are removed first and the results without group
+// are not limited
+func limitResults(results []interface{}, max int, group func(map[string]interface{}) string) []interface{} {
+	if max <= 0 {
+		return results
+	}
+
+	timestamps := make(map[int]time.Time, len(results))
+	byGroup := make(map[string][]int)
+	for index, result := range results {
+		if timestamp, ok := resultTimestamp(result); ok {
+			timestamps[index] = timestamp
+		}
+
+		if resultMap, ok := result.(map[string]interface{}); ok {
+			if key := group(resultMap); key != "" {
+				byGroup[key] = append(byGroup[key], index)
+			}
+		}
+	}
+
+	removed := make(map[int]bool)
+	for _, indexes := range byGroup {
+		if len(indexes) <= max {
+			continue
+		}
+
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return timestamps[indexes[i]].After(timestamps[indexes[j]])
+		})
+
+		for _, index := range indexes[max:] {
+			removed[index] = true
+		}
+	}
+
+	if len(removed) == 0 {
+		return results
+	}
+
+	limited := make([]interface{}, 0, len(results)-len(removed))
+	for i, result := range results {
+		if !removed[i] {
+			limited = append(limited, result)
+		}
+	}
+	return limited
+}
+
+// ReportedAt returns the time the result was reported at, the results of the reports
+// created before the timestamps were added have no timestamp
+func ReportedAt(result *report.PolicyReportResult) (time.Time, bool) {
+	timestamp, err := time.Parse(time.RFC3339, result.Data[report.ResultTimestampKey])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return timestamp, true
+}
+
+// resultTimestamp returns the time the result was last reported at, the results reported before the
+// last seen timestamps were added only have the timestamp of their first report
+func resultTimestamp(result interface{}) (time.Time, bool) {
+	resultMap, ok := result.(map[string]interface{})
+	if !ok {
+		return time.Time{}, false
+	}
+
+	data, ok := resultMap["data"].(map[string]interface{})
+	if !ok {
+		return time.Time{}, false
+	}
+
+	value, ok := data[report.ResultLastSeenKey].(string)
+	if !ok {
+		value, ok = data[report.ResultTimestampKey].(string)
+	}
+	if !ok {
+		return time.Time{}, false
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return timestamp, true
+}
+
 func hashResults(policyReport map[string]interface{}, deleteResources []deletedResource) *hashmap.HashMap {
 	resultsHash := &hashmap.HashMap{}
 
@@ -124,33 +333,34 @@ func getResultsFromHash(resHash *hashmap.HashMap) []interface{} {
 	return results
 }
 
+// generateHashKey returns the key of the result, for its policy, rule and resources. The results
+// attributed to an owner have the owner and the resource, the results of the deleted resource,
+// or of its owner, have no key.
 func generateHashKey(result map[string]interface{}, dr deletedResource) (string, bool) {
 	resources := result["resources"].([]interface{})
 	if len(resources) < 1 {
 		return "", false
 	}
 
-	resource := resources[0].(map[string]interface{})
-	if !reflect.DeepEqual(dr, deletedResource{}) {
-		if resource["kind"] == dr.kind {
-			if resource["name"] == dr.name && resource["namespace"] == dr.ns {
-				return "", false
-			}
+	key := fmt.Sprintf("%s-%s", result["policy"], result["rule"])
+	for _, r := range resources {
+		resource := r.(map[string]interface{})
+		if !reflect.DeepEqual(dr, deletedResource{}) {
+			if resource["kind"] == dr.kind {
+				if resource["name"] == dr.name && resource["namespace"] == dr.ns {
+					return "", false
+				}
 
-			if dr.kind == "Namespace" && resource["name"] == dr.name {
-				return "", false
+				if dr.kind == "Namespace" && resource["name"] == dr.name {
+					return "", false
+				}
 			}
 		}
 
+		key = fmt.Sprintf("%s-%s-%s-%s", key, resource["kind"], resource["namespace"], resource["name"])
 	}
 
-	return fmt.Sprintf(
-		"%s-%s-%s-%s-%s",
-		result["policy"],
-		result["rule"],
-		resource["kind"],
-		resource["namespace"],
-		resource["name"]), true
+	return key, true
 }
 
 func updateSummary(results []interface{}) map[string]interface{} {
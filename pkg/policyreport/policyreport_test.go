@@ -0,0 +1,72 @@
+package policyreport
+
+import (
+	"testing"
+	"time"
+
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"gotest.tools/assert"
+)
+
+func newResult(rule, status, reportedAt, lastSeenAt string) map[string]interface{} {
+	data := map[string]interface{}{report.ResultTimestampKey: reportedAt}
+	if lastSeenAt != "" {
+		data[report.ResultLastSeenKey] = lastSeenAt
+	}
+
+	return map[string]interface{}{
+		"policy": "require-labels",
+		"rule":   rule,
+		"status": status,
+		"data":   data,
+		"resources": []interface{}{
+			map[string]interface{}{"kind": "Pod", "namespace": "default", "name": "nginx"},
+		},
+	}
+}
+
+func Test_UpdateResults_RefreshesLastSeen(t *testing.T) {
+	oldReport := map[string]interface{}{
+		"results": []interface{}{newResult("require-team", "fail", "2021-04-01T10:00:00Z", "2021-04-01T10:00:00Z")},
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	newReport := map[string]interface{}{
+		"results": []interface{}{newResult("require-team", "fail", now, now)},
+	}
+
+	// the result reported again is kept even if it was first reported before the TTL
+	updated, _, err := updateResults(oldReport, newReport, nil, ResultRetention{TTL: time.Hour})
+	assert.NilError(t, err)
+
+	results := updated["results"].([]interface{})
+	assert.Equal(t, len(results), 1)
+
+	data := results[0].(map[string]interface{})["data"].(map[string]interface{})
+	assert.Equal(t, data[report.ResultTimestampKey], "2021-04-01T10:00:00Z")
+	assert.Equal(t, data[report.ResultLastSeenKey], now)
+}
+
+func Test_PruneResults(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2021-04-02T10:00:00Z")
+	results := []interface{}{
+		// reported first long ago, seen recently
+		newResult("require-team", "fail", "2021-03-01T10:00:00Z", "2021-04-02T09:00:00Z"),
+		// not seen since the TTL
+		newResult("require-app", "fail", "2021-03-01T10:00:00Z", "2021-03-02T10:00:00Z"),
+		// reported before the last seen timestamps were added
+		newResult("require-owner", "pass", "2021-04-02T08:00:00Z", ""),
+		newResult("require-env", "pass", "2021-03-01T10:00:00Z", ""),
+	}
+
+	pruned := pruneResults(results, ResultRetention{TTL: 24 * time.Hour}, now)
+	var rules []string
+	for _, result := range pruned {
+		rules = append(rules, result.(map[string]interface{})["rule"].(string))
+	}
+	assert.DeepEqual(t, rules, []string{"require-team", "require-owner"})
+
+	pruned = pruneResults(results, ResultRetention{MaxResultsPerPolicy: 1}, now)
+	assert.Equal(t, len(pruned), 1)
+	assert.Equal(t, pruned[0].(map[string]interface{})["rule"], "require-team")
+}
@@ -15,6 +15,7 @@ import (
 	policyreport "github.com/kyverno/kyverno/pkg/client/listers/policyreport/v1alpha1"
 	"github.com/kyverno/kyverno/pkg/config"
 	dclient "github.com/kyverno/kyverno/pkg/dclient"
+	"github.com/kyverno/kyverno/pkg/metrics"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,6 +34,9 @@ import (
 const (
 	prWorkQueueName     = "policy-report-controller"
 	clusterpolicyreport = "clusterpolicyreport"
+
+	// resultsPruneInterval is the interval of the pruning of the reports when a retention is set
+	resultsPruneInterval = 10 * time.Minute
 )
 
 // ReportGenerator creates policy report
@@ -56,6 +60,11 @@ type ReportGenerator struct {
 
 	queue workqueue.RateLimitingInterface
 
+	retention ResultRetention
+
+	// violations exports the violations of the reports, it is not set when the metrics are disabled
+	violations *metrics.Violations
+
 	log logr.Logger
 }
 
@@ -67,12 +76,16 @@ func NewReportGenerator(
 	reportReqInformer requestinformer.ReportChangeRequestInformer,
 	clusterReportReqInformer requestinformer.ClusterReportChangeRequestInformer,
 	namespace informers.NamespaceInformer,
+	retention ResultRetention,
+	violations *metrics.Violations,
 	log logr.Logger) *ReportGenerator {
 
 	gen := &ReportGenerator{
-		dclient: dclient,
-		queue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), prWorkQueueName),
-		log:     log,
+		dclient:    dclient,
+		queue:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), prWorkQueueName),
+		retention:  retention,
+		violations: violations,
+		log:        log,
 	}
 
 	reportReqInformer.Informer().AddEventHandler(
@@ -87,6 +100,16 @@ func NewReportGenerator(
 			UpdateFunc: gen.updateClusterReportChangeRequest,
 		})
 
+	if violations != nil {
+		reportHandler := cache.ResourceEventHandlerFuncs{
+			AddFunc:    gen.setViolations,
+			UpdateFunc: func(_, cur interface{}) { gen.setViolations(cur) },
+			DeleteFunc: gen.deleteViolations,
+		}
+		clusterReportInformer.Informer().AddEventHandler(reportHandler)
+		reportInformer.Informer().AddEventHandler(reportHandler)
+	}
+
 	gen.clusterReportLister = clusterReportInformer.Lister()
 	gen.clusterReportSynced = clusterReportInformer.Informer().HasSynced
 	gen.reportLister = reportInformer.Lister()
@@ -166,6 +189,33 @@ func (g *ReportGenerator) updateClusterReportChangeRequest(old interface{}, cur
 	g.queue.Add("")
 }
 
+// setViolations updates the violation metrics of the policy report
+func (g *ReportGenerator) setViolations(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		g.log.Error(err, "failed to compute the key of the policy report")
+		return
+	}
+
+	switch r := obj.(type) {
+	case *report.ClusterPolicyReport:
+		g.violations.SetReport(key, "", r.Results)
+	case *report.PolicyReport:
+		g.violations.SetReport(key, r.GetNamespace(), r.Results)
+	}
+}
+
+// deleteViolations removes the violation metrics of the deleted policy report
+func (g *ReportGenerator) deleteViolations(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		g.log.Error(err, "failed to compute the key of the policy report")
+		return
+	}
+
+	g.violations.DeleteReport(key)
+}
+
 // Run starts the workers
 func (g *ReportGenerator) Run(workers int, stopCh <-chan struct{}) {
 	logger := g.log
@@ -183,9 +233,34 @@ func (g *ReportGenerator) Run(workers int, stopCh <-chan struct{}) {
 		go wait.Until(g.runWorker, time.Second, stopCh)
 	}
 
+	if g.retention.enabled() {
+		go wait.Until(g.enqueueReports, resultsPruneInterval, stopCh)
+	}
+
 	<-stopCh
 }
 
+// enqueueReports enqueues the existing reports, so that the results exceeding the retention are pruned
+func (g *ReportGenerator) enqueueReports() {
+	reports, err := g.reportLister.List(labels.Everything())
+	if err != nil {
+		g.log.Error(err, "failed to list policy reports")
+	}
+
+	for _, report := range reports {
+		g.queue.Add(report.GetNamespace())
+	}
+
+	clusterReports, err := g.clusterReportLister.List(labels.Everything())
+	if err != nil {
+		g.log.Error(err, "failed to list cluster policy reports")
+	}
+
+	if len(clusterReports) != 0 {
+		g.queue.Add("")
+	}
+}
+
 func (g *ReportGenerator) runWorker() {
 	for g.processNextWorkItem() {
 	}
@@ -265,7 +340,7 @@ func (g *ReportGenerator) syncHandler(key string) error {
 // return the existing report if exist
 func (g *ReportGenerator) createReportIfNotPresent(namespace string, new *unstructured.Unstructured, aggregatedRequests interface{}) (report interface{}, err error) {
 	log := g.log.WithName("createReportIfNotPresent")
-	obj, hasDuplicate, err := updateResults(new.UnstructuredContent(), new.UnstructuredContent(), nil)
+	obj, hasDuplicate, err := updateResults(new.UnstructuredContent(), new.UnstructuredContent(), nil, g.retention)
 	if hasDuplicate && err != nil {
 		g.log.Error(err, "failed to remove duplicate results", "policy report", new.GetName())
 	} else {
@@ -570,7 +645,7 @@ func (g *ReportGenerator) updateReport(old interface{}, new *unstructured.Unstru
 		new.SetResourceVersion(oldTyped.GetResourceVersion())
 	}
 
-	obj, _, err := updateResults(oldUnstructured, new.UnstructuredContent(), aggregatedRequests)
+	obj, _, err := updateResults(oldUnstructured, new.UnstructuredContent(), aggregatedRequests, g.retention)
 	if err != nil {
 		return fmt.Errorf("failed to update results entry: %v", err)
 	}
@@ -1,6 +1,7 @@
 package policyreport
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -18,6 +19,8 @@ import (
 	dclient "github.com/kyverno/kyverno/pkg/dclient"
 	"github.com/kyverno/kyverno/pkg/engine/response"
 	"github.com/kyverno/kyverno/pkg/policystatus"
+	"github.com/kyverno/kyverno/pkg/resourcecache"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
@@ -56,8 +59,17 @@ type Generator struct {
 	queue     workqueue.RateLimitingInterface
 	dataStore *dataStore
 
+	// workers waits for the workers to process the queued results on shutdown
+	workers sync.WaitGroup
+
 	requestCreator creator
 
+	// resCache provides the informer caches of the owners of the resources
+	resCache resourcecache.ResourceCache
+
+	// ownerAttribution attributes the results of the controlled resources to their owners
+	ownerAttribution bool
+
 	log logr.Logger
 }
 
@@ -69,6 +81,9 @@ func NewReportChangeRequestGenerator(client *policyreportclient.Clientset,
 	cpolInformer kyvernoinformer.ClusterPolicyInformer,
 	polInformer kyvernoinformer.PolicyInformer,
 	policyStatus policystatus.Listener,
+	flushInterval time.Duration,
+	resCache resourcecache.ResourceCache,
+	ownerAttribution bool,
 	log logr.Logger) *Generator {
 	gen := Generator{
 		dclient:                          dclient,
@@ -82,7 +97,9 @@ func NewReportChangeRequestGenerator(client *policyreportclient.Clientset,
 		polListerSynced:                  polInformer.Informer().HasSynced,
 		queue:                            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), workQueueName),
 		dataStore:                        newDataStore(),
-		requestCreator:                   newChangeRequestCreator(dclient, 3*time.Second, log.WithName("requestCreator")),
+		requestCreator:                   newChangeRequestCreator(dclient, flushInterval, log.WithName("requestCreator")),
+		resCache:                         resCache,
+		ownerAttribution:                 ownerAttribution,
 		log:                              log,
 	}
 
@@ -132,6 +149,13 @@ type Info struct {
 type EngineResponseResult struct {
 	Resource response.ResourceSpec
 	Rules    []kyverno.ViolatedRule
+
+	// Owner is the top-level controlling owner of the resource, it is reported as the first
+	// resource of the results when the results are attributed to the owners
+	Owner *response.ResourceSpec
+
+	// controller is the controller reference of the resource
+	controller *metav1.OwnerReference
 }
 
 func (i Info) ToKey() string {
@@ -185,7 +209,11 @@ func (gen *Generator) Run(workers int, stopCh <-chan struct{}) {
 	}
 
 	for i := 0; i < workers; i++ {
-		go wait.Until(gen.runWorker, time.Second, stopCh)
+		gen.workers.Add(1)
+		go func() {
+			defer gen.workers.Done()
+			wait.Until(gen.runWorker, time.Second, stopCh)
+		}()
 	}
 
 	go gen.requestCreator.run(stopCh)
@@ -193,6 +221,29 @@ func (gen *Generator) Run(workers int, stopCh <-chan struct{}) {
 	<-stopCh
 }
 
+// Flush creates the buffered report change requests on shutdown. The queue is shut down, so that
+// the workers stop once the queued engine responses are processed, and the requests are created when
+// the workers are stopped or the context is done.
+func (gen *Generator) Flush(ctx context.Context) {
+	logger := gen.log
+	gen.queue.ShutDown()
+
+	stopped := make(chan struct{})
+	go func() {
+		gen.workers.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		logger.Info("flushing report change requests before all queued results are processed", "pending", gen.queue.Len())
+	}
+
+	gen.requestCreator.flush()
+	logger.V(2).Info("flushed report change requests")
+}
+
 func (gen *Generator) runWorker() {
 	for gen.processNextWorkItem() {
 	}
@@ -263,7 +314,7 @@ func (gen *Generator) processNextWorkItem() bool {
 
 func (gen *Generator) syncHandler(info Info) error {
 	builder := NewBuilder(gen.cpolLister, gen.polLister)
-	reportReq, err := builder.build(info)
+	reportReq, err := builder.build(gen.attributeToOwners(info))
 	if err != nil {
 		return fmt.Errorf("unable to build reportChangeRequest: %v", err)
 	}
@@ -0,0 +1,96 @@
+package policyreport
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// fakeCreator records its calls, the requests are added once they are released
+type fakeCreator struct {
+	mutex   sync.Mutex
+	calls   []string
+	adding  chan struct{}
+	release chan struct{}
+}
+
+func (c *fakeCreator) add(request *unstructured.Unstructured) {
+	c.adding <- struct{}{}
+	<-c.release
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.calls = append(c.calls, "add")
+}
+
+func (c *fakeCreator) create(request *unstructured.Unstructured) error { return nil }
+
+func (c *fakeCreator) run(stopChan <-chan struct{}) {}
+
+func (c *fakeCreator) flush() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.calls = append(c.calls, "flush")
+}
+
+func Test_Flush(t *testing.T) {
+	policies := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	synced := func() bool { return true }
+	creator := &fakeCreator{adding: make(chan struct{}), release: make(chan struct{})}
+	gen := &Generator{
+		cpolLister:             kyvernolister.NewClusterPolicyLister(policies),
+		polLister:              kyvernolister.NewPolicyLister(policies),
+		reportReqSynced:        synced,
+		clusterReportReqSynced: synced,
+		cpolListerSynced:       synced,
+		polListerSynced:        synced,
+		queue:                  workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		dataStore:              newDataStore(),
+		requestCreator:         creator,
+		log:                    log.Log,
+	}
+
+	for _, name := range []string{"nginx", "redis", "mysql"} {
+		gen.Add(Info{
+			PolicyName: "require-team",
+			Namespace:  "default",
+			Results: []EngineResponseResult{{
+				Resource: response.ResourceSpec{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: name},
+				Rules:    []kyverno.ViolatedRule{{Name: "check-team", Type: "Validation", Check: "fail"}},
+			}},
+		})
+	}
+
+	// the last result is being processed when the generator is stopped
+	stopCh := make(chan struct{})
+	go gen.Run(1, stopCh)
+	for i := 0; i < 2; i++ {
+		<-creator.adding
+		creator.release <- struct{}{}
+	}
+	<-creator.adding
+	close(stopCh)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		close(creator.release)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	gen.Flush(ctx)
+
+	// the queued results are processed before the requests are flushed
+	assert.DeepEqual(t, creator.calls, []string{"add", "add", "add", "flush"})
+	assert.Equal(t, gen.queue.Len(), 0)
+}
@@ -0,0 +1,161 @@
+package policystatus
+
+import (
+	"strings"
+
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// tally maps the resources evaluated by the policy reports to whether they passed all the results
+type tally map[string]bool
+
+func (t tally) add(resource string, passed bool) {
+	if p, ok := t[resource]; ok {
+		passed = passed && p
+	}
+	t[resource] = passed
+}
+
+func (t tally) merge(other tally) {
+	for resource, passed := range other {
+		t.add(resource, passed)
+	}
+}
+
+func (t tally) passed() int {
+	passed := 0
+	for _, p := range t {
+		if p {
+			passed++
+		}
+	}
+	return passed
+}
+
+// summary returns the compliance summary of the tally, or nil when no resource was evaluated
+func (t tally) summary() *v1.ComplianceSummary {
+	if len(t) == 0 {
+		return nil
+	}
+
+	passed := t.passed()
+	return &v1.ComplianceSummary{
+		Evaluated:  len(t),
+		Passed:     passed,
+		Percentage: passed * 100 / len(t),
+	}
+}
+
+// complianceTallies holds the resources evaluated by the policy reports, by namespace
+// of the report and policy, and cluster-wide
+type complianceTallies struct {
+	// policies are the tallies of the policies by namespace/name of the report and policy,
+	// the namespace of the cluster policy report is empty
+	policies map[string]tally
+	cluster  tally
+
+	// namespaced are the namespace/name keys of the namespaced policies, the results of
+	// a namespaced policy are not counted for a cluster policy of the same name
+	namespaced map[string]bool
+}
+
+func newComplianceTallies() *complianceTallies {
+	return &complianceTallies{
+		policies:   map[string]tally{},
+		cluster:    tally{},
+		namespaced: map[string]bool{},
+	}
+}
+
+func talliesKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func (c *complianceTallies) addResults(namespace string, results []*report.PolicyReportResult) {
+	for _, result := range results {
+		// the skipped results do not evaluate the resource
+		if result.Status == report.StatusSkip {
+			continue
+		}
+
+		passed := result.Status != report.StatusFail && result.Status != report.StatusError
+		key := talliesKey(namespace, result.Policy)
+		if c.policies[key] == nil {
+			c.policies[key] = tally{}
+		}
+
+		for _, resource := range result.Resources {
+			resourceKey := resource.Kind + "/" + resource.Namespace + "/" + resource.Name
+			c.policies[key].add(resourceKey, passed)
+			c.cluster.add(resourceKey, passed)
+		}
+	}
+}
+
+// addNamespacedPolicy records a namespaced policy, its results are reported in the
+// report of its namespace with the results of the cluster policies
+func (c *complianceTallies) addNamespacedPolicy(namespace, name string) {
+	c.namespaced[talliesKey(namespace, name)] = true
+}
+
+// policy returns the tally of the policy, the results of a cluster policy are
+// reported in all the reports while the results of a namespaced policy are reported
+// in the report of its namespace
+func (c *complianceTallies) policy(namespace, name string) tally {
+	if namespace != "" {
+		return c.policies[talliesKey(namespace, name)]
+	}
+
+	t := tally{}
+	for key, policyTally := range c.policies {
+		if c.namespaced[key] || key[strings.Index(key, "/")+1:] != name {
+			continue
+		}
+		t.merge(policyTally)
+	}
+	return t
+}
+
+// reportCompliance reads the results of the policy reports, it returns nil when
+// the report listers are not set
+func (s *Sync) reportCompliance() *complianceTallies {
+	if s.polrLister == nil || s.cpolrLister == nil {
+		return nil
+	}
+
+	tallies := newComplianceTallies()
+
+	reports, err := s.polrLister.List(labels.Everything())
+	if err != nil {
+		s.log.Error(err, "failed to list policy reports")
+		return nil
+	}
+
+	for _, r := range reports {
+		tallies.addResults(r.Namespace, r.Results)
+	}
+
+	nsPolicies, err := s.nsLister.List(labels.Everything())
+	if err != nil {
+		s.log.Error(err, "failed to list namespaced policies")
+		return nil
+	}
+
+	for _, policy := range nsPolicies {
+		tallies.addNamespacedPolicy(policy.Namespace, policy.Name)
+	}
+
+	clusterReports, err := s.cpolrLister.List(labels.Everything())
+	if err != nil {
+		s.log.Error(err, "failed to list cluster policy reports")
+		return nil
+	}
+
+	for _, r := range clusterReports {
+		tallies.addResults("", r.Results)
+	}
+
+	return tallies
+}
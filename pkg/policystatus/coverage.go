@@ -0,0 +1,37 @@
+package policystatus
+
+import (
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+)
+
+// ruleMatches returns the number of resources matched by each rule of the policy,
+// the rules without statistics did not match any resource
+func ruleMatches(rules []v1.Rule, status v1.PolicyStatus) map[string]int {
+	matches := make(map[string]int, len(rules))
+	for _, rule := range rules {
+		matches[rule.Name] = 0
+	}
+
+	for _, ruleStat := range status.Rules {
+		if _, ok := matches[ruleStat.Name]; ok {
+			matches[ruleStat.Name] = ruleStat.MatchedCount
+		}
+	}
+
+	return matches
+}
+
+// unmatchedRules returns the names of the rules of the policy which have not
+// matched any resource, in the order of the policy
+func unmatchedRules(rules []v1.Rule, status v1.PolicyStatus) []string {
+	matches := ruleMatches(rules, status)
+
+	var unmatched []string
+	for _, rule := range rules {
+		if matches[rule.Name] == 0 {
+			unmatched = append(unmatched, rule.Name)
+		}
+	}
+
+	return unmatched
+}
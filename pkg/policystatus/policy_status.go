@@ -13,6 +13,8 @@ import (
 	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
 	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	policyreport "github.com/kyverno/kyverno/pkg/client/listers/policyreport/v1alpha1"
+	"github.com/kyverno/kyverno/pkg/metrics"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	log "sigs.k8s.io/controller-runtime/pkg/log"
@@ -56,7 +58,16 @@ type Sync struct {
 	client   *versioned.Clientset
 	lister   kyvernolister.ClusterPolicyLister
 	nsLister kyvernolister.PolicyLister
-	log      logr.Logger
+
+	// the policy reports are read to summarize the compliance of the policies
+	polrLister  policyreport.PolicyReportLister
+	cpolrLister policyreport.ClusterPolicyReportLister
+
+	// coverage and compliance export the summaries of the policies, they are not set when the metrics are disabled
+	coverage   *metrics.Coverage
+	compliance *metrics.Compliance
+
+	log logr.Logger
 }
 
 type cache struct {
@@ -66,18 +77,28 @@ type cache struct {
 }
 
 // NewSync creates a new Sync instance
-func NewSync(c *versioned.Clientset, lister kyvernolister.ClusterPolicyLister, nsLister kyvernolister.PolicyLister) *Sync {
+func NewSync(c *versioned.Clientset,
+	lister kyvernolister.ClusterPolicyLister,
+	nsLister kyvernolister.PolicyLister,
+	polrLister policyreport.PolicyReportLister,
+	cpolrLister policyreport.ClusterPolicyReportLister,
+	coverage *metrics.Coverage,
+	compliance *metrics.Compliance) *Sync {
 	return &Sync{
 		cache: &cache{
 			dataMu:     sync.RWMutex{},
 			data:       make(map[string]v1.PolicyStatus),
 			keyToMutex: newKeyToMutex(),
 		},
-		client:   c,
-		lister:   lister,
-		nsLister: nsLister,
-		Listener: make(chan statusUpdater, 20),
-		log:      log.Log.WithName("PolicyStatus"),
+		client:      c,
+		lister:      lister,
+		nsLister:    nsLister,
+		polrLister:  polrLister,
+		cpolrLister: cpolrLister,
+		coverage:    coverage,
+		compliance:  compliance,
+		Listener:    make(chan statusUpdater, 20),
+		log:         log.Log.WithName("PolicyStatus"),
 	}
 }
 
@@ -135,15 +156,20 @@ func (s *Sync) updateStatusCache(stopCh <-chan struct{}) {
 // writePolicyStatus sends the update request to the APIServer
 // syncs the status (from cache) to the policy
 func (s *Sync) writePolicyStatus() {
+	tallies := s.reportCompliance()
+	written := map[string]bool{}
 	for key, status := range s.getCachedStatus() {
+		written[key] = true
 		s.log.V(4).Info("updating policy status", "policy", key)
 		namespace, policyName := s.parseStatusKey(key)
 		if namespace == "" {
-			s.updateClusterPolicy(policyName, key, status)
+			s.updateClusterPolicy(policyName, key, status, tallies)
 		} else {
-			s.updateNamespacedPolicyStatus(policyName, namespace, key, status)
+			s.updateNamespacedPolicyStatus(policyName, namespace, key, status, tallies)
 		}
 	}
+
+	s.updateSummaries(written, tallies)
 }
 
 func (s *Sync) parseStatusKey(key string) (string, string) {
@@ -159,7 +185,7 @@ func (s *Sync) parseStatusKey(key string) (string, string) {
 	return namespace, policyName
 }
 
-func (s *Sync) updateClusterPolicy(policyName, key string, status v1.PolicyStatus) {
+func (s *Sync) updateClusterPolicy(policyName, key string, status v1.PolicyStatus, tallies *complianceTallies) {
 	defer s.deleteCachedStatus(key)
 
 	policy, err := s.lister.Get(policyName)
@@ -168,6 +194,8 @@ func (s *Sync) updateClusterPolicy(policyName, key string, status v1.PolicyStatu
 		return
 	}
 
+	status = summarize("", policyName, policy.Spec.Rules, status, tallies)
+
 	if reflect.DeepEqual(status, policy.Status) {
 		return
 	}
@@ -179,7 +207,7 @@ func (s *Sync) updateClusterPolicy(policyName, key string, status v1.PolicyStatu
 	}
 }
 
-func (s *Sync) updateNamespacedPolicyStatus(policyName, namespace, key string, status v1.PolicyStatus) {
+func (s *Sync) updateNamespacedPolicyStatus(policyName, namespace, key string, status v1.PolicyStatus, tallies *complianceTallies) {
 	defer s.deleteCachedStatus(key)
 
 	policy, err := s.nsLister.Policies(namespace).Get(policyName)
@@ -188,6 +216,8 @@ func (s *Sync) updateNamespacedPolicyStatus(policyName, namespace, key string, s
 		return
 	}
 
+	status = summarize(namespace, policyName, policy.Spec.Rules, status, tallies)
+
 	if reflect.DeepEqual(status, policy.Status) {
 		return
 	}
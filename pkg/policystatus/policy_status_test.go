@@ -3,12 +3,15 @@ package policystatus
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
 	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
 	lv1 "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
 )
 
@@ -67,7 +70,7 @@ func TestKeyToMutex(t *testing.T) {
 	expectedCache := `{"policy1":{"rulesAppliedCount":100}}`
 
 	stopCh := make(chan struct{})
-	s := NewSync(nil, dummyLister{}, dummyNsLister{})
+	s := NewSync(nil, dummyLister{}, dummyNsLister{}, nil, nil, nil, nil)
 	for i := 0; i < 100; i++ {
 		go s.updateStatusCache(stopCh)
 	}
@@ -84,3 +87,76 @@ func TestKeyToMutex(t *testing.T) {
 		t.Errorf("\nTestcase Failed\nGot:\n%v\nExpected:\n%v\n", string(cacheRaw), expectedCache)
 	}
 }
+
+func TestUnmatchedRules(t *testing.T) {
+	rules := []v1.Rule{{Name: "rule1"}, {Name: "rule2"}, {Name: "rule3"}}
+	status := v1.PolicyStatus{
+		Rules: []v1.RuleStats{
+			{Name: "rule2", MatchedCount: 2, AppliedCount: 2},
+			{Name: "rule3"},
+			{Name: "deleted-rule", MatchedCount: 1},
+		},
+	}
+
+	unmatched := unmatchedRules(rules, status)
+	if !reflect.DeepEqual(unmatched, []string{"rule1", "rule3"}) {
+		t.Errorf("\nTestcase Failed\nGot:\n%v\nExpected:\n%v\n", unmatched, []string{"rule1", "rule3"})
+	}
+
+	status.Rules[0].Name = "rule1"
+	status.Rules[1].MatchedCount = 1
+	if unmatched := unmatchedRules(rules, status); !reflect.DeepEqual(unmatched, []string{"rule2"}) {
+		t.Errorf("\nTestcase Failed\nGot:\n%v\nExpected:\n%v\n", unmatched, []string{"rule2"})
+	}
+}
+
+func TestComplianceTallies(t *testing.T) {
+	pod := func(namespace, name string) []*corev1.ObjectReference {
+		return []*corev1.ObjectReference{{Kind: "Pod", Namespace: namespace, Name: name}}
+	}
+
+	tallies := newComplianceTallies()
+	tallies.addResults("default", []*report.PolicyReportResult{
+		{Policy: "require-labels", Rule: "require-team", Resources: pod("default", "pod1"), Status: "pass"},
+		{Policy: "require-labels", Rule: "require-app", Resources: pod("default", "pod1"), Status: "fail"},
+		{Policy: "require-labels", Rule: "require-team", Resources: pod("default", "pod2"), Status: "pass"},
+		{Policy: "disallow-latest", Rule: "validate-image-tag", Resources: pod("default", "pod2"), Status: "skip"},
+	})
+	tallies.addResults("test", []*report.PolicyReportResult{
+		{Policy: "require-labels", Rule: "require-team", Resources: pod("test", "pod3"), Status: "warn"},
+	})
+
+	expected := &v1.ComplianceSummary{Evaluated: 3, Passed: 2, Percentage: 66}
+	if summary := tallies.policy("", "require-labels").summary(); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("\nTestcase Failed\nGot:\n%v\nExpected:\n%v\n", summary, expected)
+	}
+
+	expected = &v1.ComplianceSummary{Evaluated: 1, Passed: 1, Percentage: 100}
+	if summary := tallies.policy("test", "require-labels").summary(); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("\nTestcase Failed\nGot:\n%v\nExpected:\n%v\n", summary, expected)
+	}
+
+	if summary := tallies.policy("", "disallow-latest").summary(); summary != nil {
+		t.Errorf("\nTestcase Failed\nGot:\n%v\nExpected:\nnil\n", summary)
+	}
+
+	if passed, evaluated := tallies.cluster.passed(), len(tallies.cluster); passed != 2 || evaluated != 3 {
+		t.Errorf("\nTestcase Failed\nGot:\n%d/%d\nExpected:\n2/3\n", passed, evaluated)
+	}
+
+	// the results of a namespaced policy are not counted for the cluster policy of the same name
+	tallies.addResults("staging", []*report.PolicyReportResult{
+		{Policy: "require-labels", Rule: "require-team", Resources: pod("staging", "pod4"), Status: report.StatusFail},
+	})
+	tallies.addNamespacedPolicy("staging", "require-labels")
+
+	expected = &v1.ComplianceSummary{Evaluated: 3, Passed: 2, Percentage: 66}
+	if summary := tallies.policy("", "require-labels").summary(); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("\nTestcase Failed\nGot:\n%v\nExpected:\n%v\n", summary, expected)
+	}
+
+	expected = &v1.ComplianceSummary{Evaluated: 1, Passed: 0, Percentage: 0}
+	if summary := tallies.policy("staging", "require-labels").summary(); !reflect.DeepEqual(summary, expected) {
+		t.Errorf("\nTestcase Failed\nGot:\n%v\nExpected:\n%v\n", summary, expected)
+	}
+}
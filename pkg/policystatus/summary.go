@@ -0,0 +1,97 @@
+package policystatus
+
+import (
+	"context"
+	"reflect"
+
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// summarize sets the unmatched rules and the compliance of the policy status
+func summarize(namespace, name string, rules []v1.Rule, status v1.PolicyStatus, tallies *complianceTallies) v1.PolicyStatus {
+	status.UnmatchedRules = unmatchedRules(rules, status)
+	if tallies != nil {
+		status.Compliance = tallies.policy(namespace, name).summary()
+	}
+
+	return status
+}
+
+// updateSummaries updates the summary of the policies whose status was not written
+// from the cache, e.g. the policies which never matched any resource, and exports
+// the coverage and the compliance of all the policies
+func (s *Sync) updateSummaries(written map[string]bool, tallies *complianceTallies) {
+	keys := map[string]bool{}
+
+	policies, err := s.lister.List(labels.Everything())
+	if err != nil {
+		s.log.Error(err, "failed to list policies")
+		return
+	}
+
+	for _, policy := range policies {
+		keys[policy.Name] = true
+		status := summarize("", policy.Name, policy.Spec.Rules, policy.Status, tallies)
+		s.setMetrics(policy.Name, "", policy.Name, policy.Spec.Rules, status)
+		if written[policy.Name] || reflect.DeepEqual(status, policy.Status) {
+			continue
+		}
+
+		policy = policy.DeepCopy()
+		policy.Status = status
+		_, err = s.client.KyvernoV1().ClusterPolicies().UpdateStatus(context.TODO(), policy, metav1.UpdateOptions{})
+		if err != nil {
+			s.log.Error(err, "failed to update policy summary", "policy", policy.Name)
+		}
+	}
+
+	nsPolicies, err := s.nsLister.List(labels.Everything())
+	if err != nil {
+		s.log.Error(err, "failed to list namespaced policies")
+		return
+	}
+
+	for _, policy := range nsPolicies {
+		key := policy.Namespace + "/" + policy.Name
+		keys[key] = true
+		status := summarize(policy.Namespace, policy.Name, policy.Spec.Rules, policy.Status, tallies)
+		s.setMetrics(key, policy.Namespace, policy.Name, policy.Spec.Rules, status)
+		if written[key] || reflect.DeepEqual(status, policy.Status) {
+			continue
+		}
+
+		policy = policy.DeepCopy()
+		policy.Status = status
+		_, err = s.client.KyvernoV1().Policies(policy.Namespace).UpdateStatus(context.TODO(), policy, metav1.UpdateOptions{})
+		if err != nil {
+			s.log.Error(err, "failed to update namespaced policy summary", "policy", key)
+		}
+	}
+
+	if s.coverage != nil {
+		s.coverage.Retain(keys)
+	}
+
+	if s.compliance != nil {
+		s.compliance.Retain(keys)
+		if tallies != nil {
+			s.compliance.SetCluster(tallies.cluster.passed(), len(tallies.cluster))
+		}
+	}
+}
+
+func (s *Sync) setMetrics(key, namespace, name string, rules []v1.Rule, status v1.PolicyStatus) {
+	if s.coverage != nil {
+		s.coverage.SetPolicy(key, namespace, name, ruleMatches(rules, status))
+	}
+
+	if s.compliance != nil {
+		var passed, evaluated int
+		if status.Compliance != nil {
+			passed, evaluated = status.Compliance.Passed, status.Compliance.Evaluated
+		}
+		s.compliance.SetPolicy(key, namespace, name, passed, evaluated)
+	}
+}
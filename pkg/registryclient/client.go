@@ -0,0 +1,152 @@
+package registryclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+const (
+	// the platform selected from multi-platform images
+	defaultOS           = "linux"
+	defaultArchitecture = "amd64"
+)
+
+// DefaultClient is the client used to fetch image data
+var DefaultClient = NewClient(http.DefaultTransport, 10*time.Second)
+
+// ImageData is the information of an image fetched from its registry
+type ImageData struct {
+	// Image is the image reference as requested
+	Image string `json:"image"`
+
+	// ResolvedImage is the image reference with the digest of the manifest
+	ResolvedImage string `json:"resolvedImage"`
+
+	Registry   string `json:"registry"`
+	Repository string `json:"repository"`
+
+	// Identifier is the tag or the digest of the requested image reference
+	Identifier string `json:"identifier"`
+
+	// Digest is the digest of the image manifest
+	Digest string `json:"digest"`
+
+	// Manifest is the image manifest, for multi-platform images the manifest of the linux/amd64 image
+	Manifest interface{} `json:"manifest"`
+
+	// ConfigData is the image configuration, with the labels, the creation time and the architecture of the image
+	ConfigData interface{} `json:"configData"`
+}
+
+// Client fetches image data from OCI/Docker V2 registries
+type Client struct {
+	transport http.RoundTripper
+	timeout   time.Duration
+}
+
+// NewClient returns a registry client using the HTTP transport, the requests of an image are
+// canceled after the timeout
+func NewClient(transport http.RoundTripper, timeout time.Duration) *Client {
+	return &Client{transport: transport, timeout: timeout}
+}
+
+// FetchImageData returns the manifest and the configuration of the image,
+// the credentials of the keychain are used to authenticate with the registry.
+// The requests are canceled with the context or after the timeout of the client.
+func (c *Client) FetchImageData(ctx context.Context, image string, keychain authn.Keychain) (*ImageData, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	options := []remote.Option{remote.WithTransport(c.transport), remote.WithContext(ctx)}
+	if keychain != nil {
+		options = append(options, remote.WithAuthFromKeychain(keychain))
+	}
+
+	descriptor, err := remote.Get(ref, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest of %s: %v", image, err)
+	}
+
+	img, err := platformImage(descriptor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select image of %s: %v", image, err)
+	}
+
+	manifestRaw, err := img.RawManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest of %s: %v", image, err)
+	}
+
+	var manifestData interface{}
+	if err := json.Unmarshal(manifestRaw, &manifestData); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest of %s: %v", image, err)
+	}
+
+	configRaw, err := img.RawConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch configuration of %s: %v", image, err)
+	}
+
+	var configData interface{}
+	if err := json.Unmarshal(configRaw, &configData); err != nil {
+		return nil, fmt.Errorf("failed to decode configuration of %s: %v", image, err)
+	}
+
+	// the digest of an index is kept, it identifies the image for all platforms
+	repository := ref.Context()
+	return &ImageData{
+		Image:         image,
+		ResolvedImage: repository.Name() + "@" + descriptor.Digest.String(),
+		Registry:      repository.RegistryStr(),
+		Repository:    repository.RepositoryStr(),
+		Identifier:    ref.Identifier(),
+		Digest:        descriptor.Digest.String(),
+		Manifest:      manifestData,
+		ConfigData:    configData,
+	}, nil
+}
+
+// platformImage returns the image of the descriptor, for an index the linux/amd64 image, or the
+// first image if there is none
+func platformImage(descriptor *remote.Descriptor) (v1.Image, error) {
+	if !descriptor.MediaType.IsIndex() {
+		return descriptor.Image()
+	}
+
+	index, err := descriptor.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(manifest.Manifests) == 0 {
+		return nil, fmt.Errorf("image index has no manifests")
+	}
+
+	digest := manifest.Manifests[0].Digest
+	for _, m := range manifest.Manifests {
+		if m.Platform != nil && m.Platform.OS == defaultOS && m.Platform.Architecture == defaultArchitecture {
+			digest = m.Digest
+			break
+		}
+	}
+
+	return index.Image(digest)
+}
@@ -0,0 +1,134 @@
+package registryclient
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// newTestRegistry serves a registry whose requests require the credentials user:secret
+func newTestRegistry() *httptest.Server {
+	handler := registry.New()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test-registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+}
+
+// pushIndex pushes a multi-platform image, the amd64 image has the labels
+func pushIndex(t *testing.T, image string, labels map[string]string, options ...remote.Option) v1.Hash {
+	newImage := func(labels map[string]string) v1.Image {
+		img, err := random.Image(64, 1)
+		assert.NilError(t, err)
+		img, err = mutate.Config(img, v1.Config{Labels: labels})
+		assert.NilError(t, err)
+		return img
+	}
+
+	index := mutate.AppendManifests(empty.Index,
+		mutate.IndexAddendum{Add: newImage(nil), Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "arm64"}}},
+		mutate.IndexAddendum{Add: newImage(labels), Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}}},
+	)
+
+	ref, err := name.ParseReference(image)
+	assert.NilError(t, err)
+	assert.NilError(t, remote.WriteIndex(ref, index, options...))
+
+	digest, err := index.Digest()
+	assert.NilError(t, err)
+	return digest
+}
+
+func Test_FetchImageData(t *testing.T) {
+	server := newTestRegistry()
+	defer server.Close()
+
+	registry := strings.TrimPrefix(server.URL, "http://")
+	auth := base64.StdEncoding.EncodeToString([]byte("user:secret"))
+	keychain, err := NewKeychain(corev1.Secret{
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(fmt.Sprintf(`{"auths": {"%s": {"auth": "%s"}}}`, server.URL, auth)),
+		},
+	})
+	assert.NilError(t, err)
+
+	digest := pushIndex(t, registry+"/team/app:1.0", map[string]string{"team": "payments"}, remote.WithAuthFromKeychain(keychain))
+
+	client := NewClient(http.DefaultTransport, 10*time.Second)
+	imageData, err := client.FetchImageData(context.Background(), registry+"/team/app:1.0", keychain)
+	assert.NilError(t, err)
+
+	assert.Equal(t, imageData.Registry, registry)
+	assert.Equal(t, imageData.Repository, "team/app")
+	assert.Equal(t, imageData.Identifier, "1.0")
+	assert.Equal(t, imageData.Digest, digest.String())
+	assert.Equal(t, imageData.ResolvedImage, registry+"/team/app@"+digest.String())
+
+	// the configuration of the amd64 image is selected
+	configData := imageData.ConfigData.(map[string]interface{})
+	assert.DeepEqual(t, configData["config"].(map[string]interface{})["Labels"], map[string]interface{}{"team": "payments"})
+
+	// without credentials the registry is accessed anonymously
+	_, err = client.FetchImageData(context.Background(), registry+"/team/app:1.0", nil)
+	assert.ErrorContains(t, err, "401 Unauthorized")
+
+	_, err = client.FetchImageData(context.Background(), registry+"/team/app:2.0", keychain)
+	assert.ErrorContains(t, err, "MANIFEST_UNKNOWN")
+}
+
+func Test_NewKeychain(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("user:secret"))
+	keychain, err := NewKeychain(
+		corev1.Secret{
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(`{"auths": {"https://index.docker.io/v1/": {"auth": "` + auth + `"}}}`),
+			},
+		},
+		corev1.Secret{
+			Type: corev1.SecretTypeDockercfg,
+			Data: map[string][]byte{
+				corev1.DockerConfigKey: []byte(`{"ghcr.io": {"username": "bot", "password": "token"}, "docker.io": {"username": "other", "password": "other"}}`),
+			},
+		},
+	)
+	assert.NilError(t, err)
+
+	resolve := func(registry string) *authn.AuthConfig {
+		reg, err := name.NewRegistry(registry)
+		assert.NilError(t, err)
+		authenticator, err := keychain.Resolve(reg)
+		assert.NilError(t, err)
+		config, err := authenticator.Authorization()
+		assert.NilError(t, err)
+		return config
+	}
+
+	assert.DeepEqual(t, resolve("docker.io"), &authn.AuthConfig{Username: "user", Password: "secret"})
+	assert.DeepEqual(t, resolve("ghcr.io"), &authn.AuthConfig{Username: "bot", Password: "token"})
+	assert.DeepEqual(t, resolve("quay.io"), &authn.AuthConfig{})
+
+	_, err = NewKeychain(corev1.Secret{Type: corev1.SecretTypeOpaque})
+	assert.ErrorContains(t, err, "is not an image pull secret")
+}
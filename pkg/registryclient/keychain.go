@@ -0,0 +1,117 @@
+package registryclient
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Auth holds the credentials of a registry
+type Auth struct {
+	Username string
+	Password string
+}
+
+// Keychain stores the registry credentials by registry
+type Keychain map[string]Auth
+
+// dockerConfig is the format of the docker configuration stored in image pull secrets
+type dockerConfig struct {
+	Auths map[string]dockerAuth `json:"auths"`
+}
+
+type dockerAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// NewKeychain returns the registry credentials of the image pull secrets,
+// the secrets must be of type kubernetes.io/dockerconfigjson or kubernetes.io/dockercfg
+func NewKeychain(secrets ...corev1.Secret) (Keychain, error) {
+	keychain := Keychain{}
+	for _, secret := range secrets {
+		var auths map[string]dockerAuth
+		switch secret.Type {
+		case corev1.SecretTypeDockerConfigJson:
+			var config dockerConfig
+			if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+				return nil, fmt.Errorf("failed to decode secret %s/%s: %v", secret.Namespace, secret.Name, err)
+			}
+			auths = config.Auths
+
+		case corev1.SecretTypeDockercfg:
+			if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &auths); err != nil {
+				return nil, fmt.Errorf("failed to decode secret %s/%s: %v", secret.Namespace, secret.Name, err)
+			}
+
+		default:
+			return nil, fmt.Errorf("secret %s/%s of type %s is not an image pull secret", secret.Namespace, secret.Name, secret.Type)
+		}
+
+		for server, entry := range auths {
+			auth, err := entry.credentials()
+			if err != nil {
+				return nil, fmt.Errorf("invalid credentials for %s in secret %s/%s: %v", server, secret.Namespace, secret.Name, err)
+			}
+
+			// the first secret providing credentials for a registry is used, as for the kubelet
+			registry := registryFromServer(server)
+			if _, ok := keychain[registry]; !ok {
+				keychain[registry] = auth
+			}
+		}
+	}
+
+	return keychain, nil
+}
+
+// Resolve returns the credentials of the registry of the target, the registries without
+// credentials are accessed anonymously
+func (k Keychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	auth, ok := k[registryFromServer(target.RegistryStr())]
+	if !ok {
+		return authn.Anonymous, nil
+	}
+
+	return authn.FromConfig(authn.AuthConfig{Username: auth.Username, Password: auth.Password}), nil
+}
+
+func (a dockerAuth) credentials() (Auth, error) {
+	if a.Auth == "" {
+		return Auth{Username: a.Username, Password: a.Password}, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+	if err != nil {
+		return Auth{}, err
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return Auth{}, fmt.Errorf("auth must be of the form username:password")
+	}
+
+	return Auth{Username: parts[0], Password: parts[1]}, nil
+}
+
+// registryFromServer returns the registry of a docker configuration server entry,
+// which can be a URL such as https://index.docker.io/v1/
+func registryFromServer(server string) string {
+	registry := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	if i := strings.Index(registry, "/"); i >= 0 {
+		registry = registry[:i]
+	}
+
+	switch registry {
+	case name.DefaultRegistry, "docker.io", "registry-1.docker.io":
+		return name.DefaultRegistry
+	}
+
+	return registry
+}
@@ -0,0 +1,204 @@
+// Package sarif exports the results of the policies in the Static Analysis Results
+// Interchange Format (SARIF), e.g. to upload them to code scanning dashboards.
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/engine/utils"
+)
+
+const (
+	schema         = "https://json.schemastore.org/sarif-2.1.0.json"
+	version        = "2.1.0"
+	toolName       = "kyverno"
+	informationURI = "https://kyverno.io"
+)
+
+// the result levels
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+// Log is a SARIF log with a single run of Kyverno
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is the tool and the results of a single analysis
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the analysis tool
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver describes Kyverno and the rules of the policies
+type Driver struct {
+	Name           string `json:"name"`
+	Version        string `json:"version,omitempty"`
+	InformationURI string `json:"informationUri"`
+	Rules          []Rule `json:"rules"`
+}
+
+// Rule is a rule of a policy, identified by <policy>/<rule>
+type Rule struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	ShortDescription Message `json:"shortDescription"`
+}
+
+// Result is a failed rule on a resource
+type Result struct {
+	RuleID    string     `json:"ruleId"`
+	RuleIndex int        `json:"ruleIndex"`
+	Level     string     `json:"level"`
+	Message   Message    `json:"message"`
+	Locations []Location `json:"locations"`
+}
+
+// Message is a text message
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Location is the file and the resource of a result
+type Location struct {
+	PhysicalLocation *PhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []LogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+// PhysicalLocation is the file of a result
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+}
+
+// ArtifactLocation is the URI of a file, relative to the root of the repository
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// LogicalLocation is the resource of a result
+type LogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// Locator returns the file of a resource, or an empty string when it is not known
+type Locator func(resource response.ResourceSpec) string
+
+// Build returns the SARIF log of the failed validation rules of the engine responses
+func Build(responses []*response.EngineResponse, toolVersion string, locate Locator) *Log {
+	driver := Driver{
+		Name:           toolName,
+		Version:        toolVersion,
+		InformationURI: informationURI,
+		Rules:          []Rule{},
+	}
+
+	results := []Result{}
+	ruleIndex := map[string]int{}
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+
+		policy := resp.PolicyResponse
+		for _, rule := range policy.Rules {
+			if rule.Type != utils.Validation.String() || rule.Success {
+				continue
+			}
+
+			id := policy.Policy + "/" + rule.Name
+			index, ok := ruleIndex[id]
+			if !ok {
+				index = len(driver.Rules)
+				ruleIndex[id] = index
+				driver.Rules = append(driver.Rules, Rule{
+					ID:               id,
+					Name:             rule.Name,
+					ShortDescription: Message{Text: fmt.Sprintf("rule %s of policy %s", rule.Name, policy.Policy)},
+				})
+			}
+
+			results = append(results, Result{
+				RuleID:    id,
+				RuleIndex: index,
+				Level:     level(rule.Severity, policy.ValidationFailureAction),
+				Message:   Message{Text: rule.Message},
+				Locations: []Location{location(policy.Resource, locate)},
+			})
+		}
+	}
+
+	return &Log{
+		Schema:  schema,
+		Version: version,
+		Runs: []Run{
+			{
+				Tool:    Tool{Driver: driver},
+				Results: results,
+			},
+		},
+	}
+}
+
+// Marshal returns the indented JSON of the SARIF log
+func (l *Log) Marshal() ([]byte, error) {
+	return json.MarshalIndent(l, "", "  ")
+}
+
+// level maps the severity of the rule to the level of the result, the enforced
+// rules are errors and the audited rules are warnings when the severity is not set
+func level(severity, validationFailureAction string) string {
+	switch severity {
+	case "critical", "high":
+		return LevelError
+	case "medium":
+		return LevelWarning
+	case "low":
+		return LevelNote
+	}
+
+	if strings.EqualFold(validationFailureAction, "enforce") {
+		return LevelError
+	}
+
+	return LevelWarning
+}
+
+func location(resource response.ResourceSpec, locate Locator) Location {
+	name := resource.Name
+	if resource.Namespace != "" {
+		name = resource.Namespace + "/" + resource.Name
+	}
+
+	loc := Location{
+		LogicalLocations: []LogicalLocation{
+			{
+				Name:               resource.Name,
+				FullyQualifiedName: resource.Kind + "/" + name,
+				Kind:               "resource",
+			},
+		},
+	}
+
+	if locate != nil {
+		if uri := locate(resource); uri != "" {
+			loc.PhysicalLocation = &PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: uri}}
+		}
+	}
+
+	return loc
+}
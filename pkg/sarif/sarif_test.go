@@ -0,0 +1,64 @@
+package sarif
+
+import (
+	"testing"
+
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"gotest.tools/assert"
+)
+
+func Test_Build(t *testing.T) {
+	responses := []*response.EngineResponse{
+		{
+			PolicyResponse: response.PolicyResponse{
+				Policy:                  "require-labels",
+				Resource:                response.ResourceSpec{Kind: "Pod", Namespace: "default", Name: "nginx"},
+				ValidationFailureAction: "enforce",
+				Rules: []response.RuleResponse{
+					{Name: "require-team", Type: "Validation", Message: "the team label is required"},
+					{Name: "require-app", Type: "Validation", Message: "the app label is required", Severity: "low"},
+					{Name: "require-env", Type: "Validation", Success: true},
+				},
+			},
+		},
+		{
+			PolicyResponse: response.PolicyResponse{
+				Policy:   "require-labels",
+				Resource: response.ResourceSpec{Kind: "Namespace", Name: "prod"},
+				Rules: []response.RuleResponse{
+					{Name: "require-team", Type: "Validation", Message: "the team label is required"},
+				},
+			},
+		},
+	}
+
+	locate := func(resource response.ResourceSpec) string {
+		if resource.Kind == "Pod" {
+			return "manifests/pod.yaml"
+		}
+		return ""
+	}
+
+	log := Build(responses, "v1.4.0", locate)
+	assert.Equal(t, log.Version, "2.1.0")
+	assert.Equal(t, len(log.Runs), 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, run.Tool.Driver.Version, "v1.4.0")
+	assert.Equal(t, len(run.Tool.Driver.Rules), 2)
+	assert.Equal(t, run.Tool.Driver.Rules[0].ID, "require-labels/require-team")
+	assert.Equal(t, run.Tool.Driver.Rules[1].ID, "require-labels/require-app")
+
+	assert.Equal(t, len(run.Results), 3)
+	assert.Equal(t, run.Results[0].Level, LevelError)
+	assert.Equal(t, run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI, "manifests/pod.yaml")
+	assert.Equal(t, run.Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName, "Pod/default/nginx")
+
+	assert.Equal(t, run.Results[1].Level, LevelNote)
+	assert.Equal(t, run.Results[1].RuleIndex, 1)
+
+	assert.Equal(t, run.Results[2].Level, LevelWarning)
+	assert.Equal(t, run.Results[2].RuleIndex, 0)
+	assert.Assert(t, run.Results[2].Locations[0].PhysicalLocation == nil)
+	assert.Equal(t, run.Results[2].Locations[0].LogicalLocations[0].FullyQualifiedName, "Namespace/prod")
+}
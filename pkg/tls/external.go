@@ -0,0 +1,85 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// ReadPemPairFiles reads the TLS pair and the CA certificate provided externally, e.g. the
+// files of a mounted secret. The CA certificate is optional.
+func ReadPemPairFiles(certFile, keyFile, caFile string) (*PemPair, []byte, error) {
+	if keyFile == "" {
+		return nil, nil, errors.New("the TLS private key file is required with the TLS certificate file")
+	}
+
+	certificate, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read TLS certificate: %v", err)
+	}
+
+	privateKey, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read TLS private key: %v", err)
+	}
+
+	var caData []byte
+	if caFile != "" {
+		caData, err = ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read CA certificate: %v", err)
+		}
+	}
+
+	return &PemPair{Certificate: certificate, PrivateKey: privateKey}, caData, nil
+}
+
+// ValidateServingCertificate checks that the TLS pair provided externally is valid for the
+// webhook service DNS name, and that it is signed by the CA when the CA is provided
+func ValidateServingCertificate(pemPair *PemPair, caData []byte, dnsName string) error {
+	if _, err := tls.X509KeyPair(pemPair.Certificate, pemPair.PrivateKey); err != nil {
+		return fmt.Errorf("invalid TLS key/certificate pair: %v", err)
+	}
+
+	block, rest := pem.Decode(pemPair.Certificate)
+	if block == nil {
+		return errors.New("failed to decode the TLS certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse the TLS certificate: %v", err)
+	}
+
+	if err := cert.VerifyHostname(dnsName); err != nil {
+		return fmt.Errorf("the TLS certificate is not valid for the webhook service: %v", err)
+	}
+
+	if len(caData) == 0 {
+		return nil
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caData) {
+		return errors.New("failed to parse the CA certificate")
+	}
+
+	// the chain of the certificate file is used as intermediates
+	intermediates := x509.NewCertPool()
+	intermediates.AppendCertsFromPEM(rest)
+
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:       dnsName,
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("the TLS certificate is not signed by the CA: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package tls
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	assert.NilError(t, ioutil.WriteFile(path, data, 0600))
+	return path
+}
+
+func Test_ReadPemPairFiles(t *testing.T) {
+	dir := t.TempDir()
+	certFile := writeFile(t, dir, "tls.crt", []byte("certificate"))
+	keyFile := writeFile(t, dir, "tls.key", []byte("key"))
+	caFile := writeFile(t, dir, "ca.crt", []byte("ca"))
+
+	pemPair, caData, err := ReadPemPairFiles(certFile, keyFile, caFile)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pemPair, &PemPair{Certificate: []byte("certificate"), PrivateKey: []byte("key")})
+	assert.DeepEqual(t, caData, []byte("ca"))
+
+	// the CA certificate is optional
+	_, caData, err = ReadPemPairFiles(certFile, keyFile, "")
+	assert.NilError(t, err)
+	assert.Assert(t, caData == nil)
+
+	_, _, err = ReadPemPairFiles(certFile, "", "")
+	assert.ErrorContains(t, err, "private key file is required")
+
+	_, _, err = ReadPemPairFiles(certFile, keyFile, filepath.Join(dir, "missing.crt"))
+	assert.ErrorContains(t, err, "failed to read CA certificate")
+}
+
+func Test_ValidateServingCertificate(t *testing.T) {
+	props := CertificateProps{Service: "kyverno-svc", Namespace: "kyverno", APIServerHost: "10.0.0.1"}
+	dnsName := GenerateInClusterServiceName(props)
+
+	caCert, caPem, err := GenerateCACert(CertificateOptions{})
+	assert.NilError(t, err)
+	pemPair, err := GenerateCertPem(caCert, props, "", CertificateOptions{})
+	assert.NilError(t, err)
+
+	_, otherCAPem, err := GenerateCACert(CertificateOptions{})
+	assert.NilError(t, err)
+
+	assert.NilError(t, ValidateServingCertificate(pemPair, caPem.Certificate, dnsName))
+	// the chain is not verified without the CA
+	assert.NilError(t, ValidateServingCertificate(pemPair, nil, dnsName))
+
+	assert.ErrorContains(t, ValidateServingCertificate(pemPair, otherCAPem.Certificate, dnsName), "not signed by the CA")
+	assert.ErrorContains(t, ValidateServingCertificate(pemPair, caPem.Certificate, "kyverno-svc.other.svc"), "not valid for the webhook service")
+	assert.ErrorContains(t, ValidateServingCertificate(pemPair, []byte("invalid"), dnsName), "failed to parse the CA certificate")
+	assert.ErrorContains(t, ValidateServingCertificate(&PemPair{Certificate: pemPair.Certificate, PrivateKey: caPem.PrivateKey}, nil, dnsName), "invalid TLS key/certificate pair")
+}
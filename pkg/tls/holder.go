@@ -0,0 +1,57 @@
+package tls
+
+import (
+	"crypto/tls"
+	"errors"
+	"sync"
+)
+
+// CertificateHolder holds the serving certificate of the webhook server, the certificate
+// can be replaced while the server is running, e.g. when it is renewed
+type CertificateHolder struct {
+	mu          sync.RWMutex
+	pemPair     *PemPair
+	certificate *tls.Certificate
+}
+
+// NewCertificateHolder returns a holder of the TLS pair
+func NewCertificateHolder(pemPair *PemPair) (*CertificateHolder, error) {
+	h := &CertificateHolder{}
+	if err := h.Update(pemPair); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Update replaces the serving certificate, the new connections use the new certificate
+func (h *CertificateHolder) Update(pemPair *PemPair) error {
+	if pemPair == nil {
+		return errors.New("TLS pair is not initialized")
+	}
+
+	certificate, err := tls.X509KeyPair(pemPair.Certificate, pemPair.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pemPair = pemPair
+	h.certificate = &certificate
+	return nil
+}
+
+// PemPair returns the TLS pair of the serving certificate
+func (h *CertificateHolder) PemPair() *PemPair {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.pemPair
+}
+
+// GetCertificate returns the serving certificate, it is used as the GetCertificate function of the tls.Config
+func (h *CertificateHolder) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.certificate, nil
+}
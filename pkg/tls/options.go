@@ -0,0 +1,77 @@
+package tls
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// tlsVersions are the names of the TLS versions accepted as minimum version of the webhook server
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ServerOptions are the TLS settings of the webhook server
+type ServerOptions struct {
+	// MinVersion is the minimum TLS version accepted by the server
+	MinVersion uint16
+
+	// CipherSuites are the cipher suites of TLS 1.2 accepted by the server, the defaults of
+	// the Go library are used when empty. The cipher suites of TLS 1.3 are not configurable.
+	CipherSuites []uint16
+}
+
+// NewServerOptions parses the minimum TLS version, e.g. 1.2, and the comma separated names
+// of the cipher suites, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+func NewServerOptions(minVersion, cipherSuites string) (ServerOptions, error) {
+	var options ServerOptions
+
+	version, ok := tlsVersions[minVersion]
+	if !ok {
+		return options, fmt.Errorf("unsupported minimum TLS version %s, supported versions are 1.2 and 1.3", minVersion)
+	}
+	options.MinVersion = version
+
+	// only the secure cipher suites of TLS 1.2 can be enabled
+	suites := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		if supportsTLS12(suite) {
+			suites[suite.Name] = suite.ID
+		}
+	}
+
+	for _, name := range strings.Split(cipherSuites, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		id, ok := suites[name]
+		if !ok {
+			return options, fmt.Errorf("unsupported or insecure cipher suite %s, only the cipher suites of TLS 1.2 are configurable", name)
+		}
+		options.CipherSuites = append(options.CipherSuites, id)
+	}
+
+	return options, nil
+}
+
+func supportsTLS12(suite *tls.CipherSuite) bool {
+	for _, version := range suite.SupportedVersions {
+		if version == tls.VersionTLS12 {
+			return true
+		}
+	}
+	return false
+}
+
+// ServerConfig returns the TLS configuration of the webhook server, the serving certificate is
+// read from the holder for each connection, so that a renewed certificate is served
+func ServerConfig(certificates *CertificateHolder, options ServerOptions) *tls.Config {
+	return &tls.Config{
+		GetCertificate: certificates.GetCertificate,
+		MinVersion:     options.MinVersion,
+		CipherSuites:   options.CipherSuites,
+	}
+}
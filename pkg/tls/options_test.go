@@ -0,0 +1,61 @@
+package tls
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_NewServerOptions(t *testing.T) {
+	testcases := []struct {
+		description  string
+		minVersion   string
+		cipherSuites string
+		options      ServerOptions
+		err          string
+	}{
+		{
+			description: "default cipher suites",
+			minVersion:  "1.2",
+			options:     ServerOptions{MinVersion: tls.VersionTLS12},
+		},
+		{
+			description:  "cipher suites of TLS 1.2",
+			minVersion:   "1.2",
+			cipherSuites: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+			options: ServerOptions{
+				MinVersion:   tls.VersionTLS12,
+				CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384},
+			},
+		},
+		{
+			description: "unsupported version",
+			minVersion:  "1.1",
+			err:         "unsupported minimum TLS version 1.1, supported versions are 1.2 and 1.3",
+		},
+		{
+			description:  "insecure cipher suite",
+			minVersion:   "1.2",
+			cipherSuites: "TLS_RSA_WITH_RC4_128_SHA",
+			err:          "unsupported or insecure cipher suite TLS_RSA_WITH_RC4_128_SHA, only the cipher suites of TLS 1.2 are configurable",
+		},
+		{
+			description:  "cipher suite of TLS 1.3",
+			minVersion:   "1.3",
+			cipherSuites: "TLS_AES_128_GCM_SHA256",
+			err:          "unsupported or insecure cipher suite TLS_AES_128_GCM_SHA256, only the cipher suites of TLS 1.2 are configurable",
+		},
+	}
+
+	for _, testcase := range testcases {
+		options, err := NewServerOptions(testcase.minVersion, testcase.cipherSuites)
+		if testcase.err != "" {
+			assert.Error(t, err, testcase.err, testcase.description)
+			continue
+		}
+
+		assert.NilError(t, err, testcase.description)
+		assert.DeepEqual(t, options, testcase.options)
+	}
+}
@@ -1,6 +1,9 @@
 package tls
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -16,6 +19,47 @@ import (
 
 const certValidityDuration = 10 * 365 * 24 * time.Hour
 
+// The key algorithms of the generated certificates
+const (
+	KeyAlgorithmRSA   = "RSA"
+	KeyAlgorithmECDSA = "ECDSA"
+)
+
+// CertificateOptions are the settings of the generated self-signed certificates
+type CertificateOptions struct {
+	// Validity is the validity period of the certificates, the default is ten years
+	Validity time.Duration
+
+	// DNSNames are the additional DNS SANs of the serving certificate, e.g. the custom
+	// names of the webhook service
+	DNSNames []string
+
+	// KeyAlgorithm is the algorithm of the keys, RSA (default) or ECDSA
+	KeyAlgorithm string
+}
+
+// Validate checks the certificate options
+func (o CertificateOptions) Validate() error {
+	if o.Validity < 0 {
+		return fmt.Errorf("invalid certificate validity %v", o.Validity)
+	}
+
+	switch o.KeyAlgorithm {
+	case "", KeyAlgorithmRSA, KeyAlgorithmECDSA:
+		return nil
+	default:
+		return fmt.Errorf("unsupported key algorithm %s, supported algorithms are %s and %s", o.KeyAlgorithm, KeyAlgorithmRSA, KeyAlgorithmECDSA)
+	}
+}
+
+func (o CertificateOptions) validity() time.Duration {
+	if o.Validity == 0 {
+		return certValidityDuration
+	}
+
+	return o.Validity
+}
+
 // CertificateProps Properties of TLS certificate which should be issued for webhook server
 type CertificateProps struct {
 	Service       string
@@ -33,7 +77,7 @@ type PemPair struct {
 // KeyPair ...
 type KeyPair struct {
 	Cert *x509.Certificate
-	Key  *rsa.PrivateKey
+	Key  crypto.Signer
 }
 
 // GeneratePrivateKey Generates RSA private key
@@ -51,6 +95,34 @@ func PrivateKeyToPem(rsaKey *rsa.PrivateKey) []byte {
 	return pem.EncodeToMemory(privateKey)
 }
 
+// generateKey generates the private key of the algorithm
+func generateKey(algorithm string) (crypto.Signer, error) {
+	if algorithm == KeyAlgorithmECDSA {
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+
+	return rsa.GenerateKey(rand.Reader, 2048)
+}
+
+// keyToPem creates the PEM block of the RSA or ECDSA private key
+func keyToPem(key crypto.Signer) ([]byte, error) {
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("unsupported private key")
+		}
+		return PrivateKeyToPem(rsaKey), nil
+	}
+
+	der, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
 // CertificateToPem ...
 func CertificateToPem(certificateDER []byte) []byte {
 	certificate := &pem.Block{
@@ -63,10 +135,10 @@ func CertificateToPem(certificateDER []byte) []byte {
 
 // GenerateCACert creates the self-signed CA cert and private key
 // it will be used to sign the webhook server certificate
-func GenerateCACert() (*KeyPair, *PemPair, error) {
+func GenerateCACert(options CertificateOptions) (*KeyPair, *PemPair, error) {
 	now := time.Now()
 	begin := now.Add(-1 * time.Hour)
-	end := now.Add(certValidityDuration)
+	end := now.Add(options.validity())
 
 	templ := &x509.Certificate{
 		SerialNumber: big.NewInt(0),
@@ -80,7 +152,7 @@ func GenerateCACert() (*KeyPair, *PemPair, error) {
 		IsCA:                  true,
 	}
 
-	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	key, err := generateKey(options.KeyAlgorithm)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error generating key: %v", err)
 	}
@@ -90,9 +162,14 @@ func GenerateCACert() (*KeyPair, *PemPair, error) {
 		return nil, nil, fmt.Errorf("error creating certificate: %v", err)
 	}
 
+	keyPem, err := keyToPem(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encoding key: %v", err)
+	}
+
 	pemPair := &PemPair{
 		Certificate: CertificateToPem(der),
-		PrivateKey:  PrivateKeyToPem(key),
+		PrivateKey:  keyPem,
 	}
 
 	cert, err := x509.ParseCertificate(der)
@@ -110,10 +187,10 @@ func GenerateCACert() (*KeyPair, *PemPair, error) {
 
 // GenerateCertPem takes the results of GenerateCACert and uses it to create the
 // PEM-encoded public certificate and private key, respectively
-func GenerateCertPem(caCert *KeyPair, props CertificateProps, serverIP string) (*PemPair, error) {
+func GenerateCertPem(caCert *KeyPair, props CertificateProps, serverIP string, options CertificateOptions) (*PemPair, error) {
 	now := time.Now()
 	begin := now.Add(-1 * time.Hour)
-	end := now.Add(certValidityDuration)
+	end := now.Add(options.validity())
 
 	dnsNames := make([]string, 3)
 	dnsNames[0] = fmt.Sprintf("%s", props.Service)
@@ -123,6 +200,7 @@ func GenerateCertPem(caCert *KeyPair, props CertificateProps, serverIP string) (
 	// The full service name is the CommonName for the certificate
 	commonName := GenerateInClusterServiceName(props)
 	dnsNames[2] = fmt.Sprintf("%s", commonName)
+	dnsNames = append(dnsNames, options.DNSNames...)
 
 	var ips []net.IP
 	apiServerIP := net.ParseIP(props.APIServerHost)
@@ -156,7 +234,7 @@ func GenerateCertPem(caCert *KeyPair, props CertificateProps, serverIP string) (
 		BasicConstraintsValid: true,
 	}
 
-	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	key, err := generateKey(options.KeyAlgorithm)
 	if err != nil {
 		return nil, fmt.Errorf("error generating key for webhook %v", err)
 	}
@@ -165,9 +243,14 @@ func GenerateCertPem(caCert *KeyPair, props CertificateProps, serverIP string) (
 		return nil, fmt.Errorf("error creating certificate for webhook %v", err)
 	}
 
+	keyPem, err := keyToPem(key)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding key for webhook %v", err)
+	}
+
 	pemPair := &PemPair{
 		Certificate: CertificateToPem(der),
-		PrivateKey:  PrivateKeyToPem(key),
+		PrivateKey:  keyPem,
 	}
 
 	return pemPair, nil
@@ -202,11 +285,19 @@ func IsTLSPairShouldBeUpdated(tlsPair *PemPair) bool {
 		return true
 	}
 
+	return IsTLSPairExpiring(tlsPair, timeReserveBeforeCertificateExpiration)
+}
+
+// IsTLSPairExpiring checks if the certificate of the TLS pair expires within the duration
+func IsTLSPairExpiring(tlsPair *PemPair, within time.Duration) bool {
+	if tlsPair == nil {
+		return true
+	}
+
 	expirationDate, err := tlsCertificateGetExpirationDate(tlsPair.Certificate)
 	if err != nil {
 		return true
 	}
 
-	// TODO : should use time.Until instead of t.Sub(time.Now()) (gosimple)
-	return expirationDate.Sub(time.Now()) < timeReserveBeforeCertificateExpiration
+	return time.Until(*expirationDate) < within
 }
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"k8s.io/apimachinery/pkg/util/yaml"
@@ -54,7 +55,22 @@ func SplitYAMLDocuments(yamlBytes []byte) (policies [][]byte, error error) {
 			return policies, fmt.Errorf("unable to read yaml")
 		}
 
+		if isEmptyDocument(b) {
+			continue
+		}
+
 		policies = append(policies, b)
 	}
 	return policies, nil
 }
+
+// isEmptyDocument checks if the YAML document only contains separators, comments and blank lines
+func isEmptyDocument(document []byte) bool {
+	for _, line := range strings.Split(string(document), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "---"))
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
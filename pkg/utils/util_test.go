@@ -92,3 +92,12 @@ func Test_higherVersion(t *testing.T) {
 	v, err = isVersionHigher("v1.5.9-rc2", 1, 5, 9)
 	assert.Assert(t, v == false && err == nil)
 }
+
+func Test_SplitYAMLDocuments_EmptyDocuments(t *testing.T) {
+	yamlBytes := []byte("---\n# the pods\n---\napiVersion: v1\nkind: Pod\n---\n\n---\napiVersion: v1\nkind: Service\n")
+	documents, err := SplitYAMLDocuments(yamlBytes)
+	assert.NilError(t, err)
+	assert.Equal(t, len(documents), 2)
+	assert.Equal(t, string(documents[0]), "apiVersion: v1\nkind: Pod\n")
+	assert.Equal(t, string(documents[1]), "apiVersion: v1\nkind: Service\n")
+}
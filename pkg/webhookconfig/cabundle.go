@@ -0,0 +1,157 @@
+package webhookconfig
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	errorsapi "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// SetCABundle sets the CA bundle of the webhook configurations, instead of the
+// root CA secret of Kyverno or the CA of the kubeconfig. An empty CA bundle is kept
+// empty, so that the API server verifies the serving certificate with its system roots.
+func (wrc *Register) SetCABundle(caData []byte) {
+	wrc.caMu.Lock()
+	defer wrc.caMu.Unlock()
+	wrc.caBundle = caData
+	wrc.caBundleSet = true
+}
+
+// getCABundle returns the CA bundle which is set, and whether it is set
+func (wrc *Register) getCABundle() ([]byte, bool) {
+	wrc.caMu.RLock()
+	defer wrc.caMu.RUnlock()
+	return wrc.caBundle, wrc.caBundleSet
+}
+
+// UpdateCABundle sets the CA bundle and injects it in the registered webhook configurations,
+// e.g. when the serving certificate is renewed by another CA
+func (wrc *Register) UpdateCABundle(caData []byte) error {
+	wrc.SetCABundle(caData)
+
+	errors := make([]string, 0)
+	for name, kind := range wrc.webhookConfigurations() {
+		if err := wrc.injectCABundle(kind, name, caData); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, ","))
+	}
+
+	return nil
+}
+
+// ReconcileCABundle repairs the CA bundle of the registered webhook configurations which
+// differs from the active CA, e.g. when it is overwritten by a GitOps tool
+func (wrc *Register) ReconcileCABundle() error {
+	if wrc.certLock != nil && !wrc.certLock.acquire() {
+		wrc.log.V(4).Info("the CA bundle is reconciled by the holder of the lock of the self-signed certificate")
+		return nil
+	}
+
+	caData := wrc.readCaData()
+	if caData == nil {
+		return fmt.Errorf("Unable to extract CA data from configuration")
+	}
+
+	errors := make([]string, 0)
+	for name, kind := range wrc.webhookConfigurations() {
+		cache, ok := wrc.resCache.GetGVRCache(kind)
+		if !ok {
+			continue
+		}
+
+		obj, err := cache.Lister().Get(name)
+		if err != nil {
+			// the missing webhook configurations are registered again by the monitor
+			continue
+		}
+
+		configuration := obj.DeepCopy()
+		if !setCABundle(configuration, caData) {
+			continue
+		}
+
+		wrc.log.Info("repairing the CA bundle of the webhook configuration", "kind", kind, "name", name)
+		if _, err := wrc.client.UpdateResource("", kind, "", configuration, false); err != nil {
+			errors = append(errors, fmt.Sprintf("failed to update the CA bundle of %s %s: %v", kind, name, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, ","))
+	}
+
+	return nil
+}
+
+// webhookConfigurations returns the kinds of the webhook configurations by name
+func (wrc *Register) webhookConfigurations() map[string]string {
+	return map[string]string{
+		wrc.getVerifyWebhookMutatingWebhookName():         kindMutating,
+		wrc.getPolicyMutatingWebhookConfigurationName():   kindMutating,
+		wrc.getPolicyValidatingWebhookConfigurationName(): kindValidating,
+		wrc.getResourceMutatingWebhookConfigName():        kindMutating,
+		wrc.getResourceValidatingWebhookConfigName():      kindValidating,
+	}
+}
+
+func (wrc *Register) injectCABundle(kind, name string, caData []byte) error {
+	logger := wrc.log.WithValues("kind", kind, "name", name)
+	configuration, err := wrc.client.GetResource("", kind, "", name)
+	if errorsapi.IsNotFound(err) {
+		logger.V(4).Info("webhook configuration not found, the CA bundle is set on registration")
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s: %v", kind, name, err)
+	}
+
+	if !setCABundle(configuration, caData) {
+		return nil
+	}
+
+	if _, err := wrc.client.UpdateResource("", kind, "", configuration, false); err != nil {
+		return fmt.Errorf("failed to update the CA bundle of %s %s: %v", kind, name, err)
+	}
+
+	logger.Info("updated the CA bundle of the webhook configuration")
+	return nil
+}
+
+// setCABundle sets the CA bundle of all the webhooks of the configuration, it returns
+// false when the configuration is up to date
+func setCABundle(configuration *unstructured.Unstructured, caData []byte) bool {
+	webhooks, _, _ := unstructured.NestedSlice(configuration.Object, "webhooks")
+	caBundle := base64.StdEncoding.EncodeToString(caData)
+
+	updated := false
+	for i := range webhooks {
+		webhook, ok := webhooks[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if current, _, _ := unstructured.NestedString(webhook, "clientConfig", "caBundle"); current == caBundle {
+			continue
+		}
+
+		if err := unstructured.SetNestedField(webhook, caBundle, "clientConfig", "caBundle"); err != nil {
+			continue
+		}
+
+		webhooks[i] = webhook
+		updated = true
+	}
+
+	if !updated {
+		return false
+	}
+
+	return unstructured.SetNestedSlice(configuration.Object, webhooks, "webhooks") == nil
+}
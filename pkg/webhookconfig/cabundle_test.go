@@ -0,0 +1,43 @@
+package webhookconfig
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_SetCABundle(t *testing.T) {
+	configuration := &unstructured.Unstructured{Object: map[string]interface{}{
+		"webhooks": []interface{}{
+			map[string]interface{}{"name": "validate.kyverno.svc", "clientConfig": map[string]interface{}{"caBundle": "b2xk"}},
+			map[string]interface{}{"name": "mutate.kyverno.svc", "clientConfig": map[string]interface{}{}},
+		},
+	}}
+
+	assert.Assert(t, setCABundle(configuration, []byte("new")))
+
+	webhooks, _, _ := unstructured.NestedSlice(configuration.Object, "webhooks")
+	for _, webhook := range webhooks {
+		caBundle, _, _ := unstructured.NestedString(webhook.(map[string]interface{}), "clientConfig", "caBundle")
+		assert.Equal(t, caBundle, base64.StdEncoding.EncodeToString([]byte("new")))
+	}
+
+	// the configuration is up to date
+	assert.Assert(t, !setCABundle(configuration, []byte("new")))
+}
+
+func Test_ReadCaData_External(t *testing.T) {
+	// the root CA secret and the kubeconfig are not read once the CA bundle is set
+	wrc := &Register{log: log.Log}
+
+	wrc.SetCABundle([]byte("ca"))
+	assert.DeepEqual(t, wrc.readCaData(), []byte("ca"))
+
+	wrc.SetCABundle(nil)
+	caData := wrc.readCaData()
+	assert.Assert(t, caData != nil)
+	assert.Equal(t, len(caData), 0)
+}
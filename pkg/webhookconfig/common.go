@@ -14,6 +14,14 @@ import (
 func (wrc *Register) readCaData() []byte {
 	logger := wrc.log
 	var caData []byte
+	if caData, set := wrc.getCABundle(); set {
+		if len(caData) == 0 {
+			logger.V(4).Info("the CA of the serving certificate is not provided, the webhooks are verified with the system roots")
+			return []byte{}
+		}
+		logger.V(4).Info("read CA from the serving certificate")
+		return caData
+	}
 	// Check if ca is defined in the secret tls-ca
 	// assume the key and signed cert have been defined in secret tls.kyverno
 	if caData = wrc.client.ReadRootCASecret(); len(caData) != 0 {
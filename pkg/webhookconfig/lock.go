@@ -0,0 +1,52 @@
+package webhookconfig
+
+import (
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	rest "k8s.io/client-go/rest"
+)
+
+// certLockDuration is the duration of the lock of the self-signed certificate, the holder refreshes
+// the lock when it reconciles the CA bundle of the webhook configurations
+const certLockDuration time.Duration = 5 * time.Minute
+
+// certLock is held by the replica which renews the self-signed certificate and reconciles the CA
+// bundle of the webhook configurations, the other replicas serve the certificate renewed by the holder
+type certLock struct {
+	client       *client.Client
+	clientConfig *rest.Config
+	holder       string
+	log          logr.Logger
+}
+
+func newCertLock(client *client.Client, clientConfig *rest.Config, log logr.Logger) *certLock {
+	// the holder is unique per process, as the pods keep their name once restarted
+	holder, _ := os.Hostname()
+	return &certLock{
+		client:       client,
+		clientConfig: clientConfig,
+		holder:       holder + "_" + string(uuid.NewUUID()),
+		log:          log,
+	}
+}
+
+// acquire returns true when the lock is held by the replica
+func (l *certLock) acquire() bool {
+	certProps, err := l.client.GetTLSCertProps(l.clientConfig)
+	if err != nil {
+		l.log.Error(err, "failed to get TLS Cert Properties")
+		return false
+	}
+
+	held, err := l.client.AcquireRootCALock(certProps, l.holder, certLockDuration, time.Now())
+	if err != nil {
+		l.log.Error(err, "failed to acquire the lock of the self-signed certificate")
+		return false
+	}
+
+	return held
+}
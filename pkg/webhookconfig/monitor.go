@@ -23,9 +23,9 @@ const (
 // change in the Kyverno deployment to force a webhook request. If no requests
 // are received after idleDeadline the webhooks are deleted and re-registered.
 //
-// Webhook configurations are checked every tickerInterval. Currently the check
-// only queries for the expected resource name, and does not compare other details
-// like the webhook settings.
+// Webhook configurations are checked every tickerInterval. The check queries for
+// the expected resource name and repairs the CA bundle of the webhooks, it does
+// not compare other details like the webhook settings.
 //
 type Monitor struct {
 	t   time.Time
@@ -78,6 +78,10 @@ func (t *Monitor) Run(register *Register, eventGen event.Interface, client *dcli
 				continue
 			}
 
+			if err := register.ReconcileCABundle(); err != nil {
+				logger.Error(err, "failed to reconcile the CA bundle of the webhooks")
+			}
+
 			timeDiff := time.Since(t.Time())
 			if timeDiff > idleDeadline {
 				err := fmt.Errorf("admission control configuration error")
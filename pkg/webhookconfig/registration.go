@@ -35,6 +35,17 @@ type Register struct {
 	serverIP       string // when running outside a cluster
 	timeoutSeconds int32
 	log            logr.Logger
+
+	// caBundle is the CA bundle of the webhook configurations when the serving
+	// certificate is not issued by Kyverno, e.g. when it is issued by cert-manager,
+	// it is empty when the certificate is trusted by the system roots of the API server
+	caMu        sync.RWMutex
+	caBundle    []byte
+	caBundleSet bool
+
+	// certLock gates the reconciliation of the CA bundle when the certificate is self-signed, so that
+	// it is reconciled by the replica which renews the certificate
+	certLock *certLock
 }
 
 // NewRegister creates new Register instance
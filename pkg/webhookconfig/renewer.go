@@ -0,0 +1,134 @@
+package webhookconfig
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/go-logr/logr"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	tls "github.com/kyverno/kyverno/pkg/tls"
+	rest "k8s.io/client-go/rest"
+)
+
+// certCheckInterval is the interval of the checks of the expiry of the serving certificate
+const certCheckInterval time.Duration = time.Hour
+
+// CertRenewer renews the self-signed serving certificate of the webhook server before it expires.
+//
+// The renewed certificate and its CA are stored in the secrets, the CA bundle of the webhook
+// configurations trusts both the previous and the new CA, and the webhook server serves the
+// renewed certificate to the new connections.
+//
+// The certificate is renewed by the replica which holds the lock of the root CA secret, the
+// other replicas serve the renewed certificate from its secret.
+type CertRenewer struct {
+	client       *client.Client
+	clientConfig *rest.Config
+	serverIP     string
+	certificates *tls.CertificateHolder
+	certOptions  tls.CertificateOptions
+	register     *Register
+	renewBefore  time.Duration
+	lock         *certLock
+	log          logr.Logger
+}
+
+// NewCertRenewer returns a new instance of the renewer of the self-signed serving certificate
+func NewCertRenewer(client *client.Client, clientConfig *rest.Config, serverIP string, certificates *tls.CertificateHolder, certOptions tls.CertificateOptions, register *Register, renewBefore time.Duration, log logr.Logger) *CertRenewer {
+	// the CA bundle of the self-signed certificate is reconciled by the holder of the lock
+	lock := newCertLock(client, clientConfig, log.WithName("CertLock"))
+	register.certLock = lock
+
+	return &CertRenewer{
+		client:       client,
+		clientConfig: clientConfig,
+		serverIP:     serverIP,
+		certificates: certificates,
+		certOptions:  certOptions,
+		register:     register,
+		renewBefore:  renewBefore,
+		lock:         lock,
+		log:          log,
+	}
+}
+
+// Run checks the expiry of the serving certificate until the stop channel is closed
+func (r *CertRenewer) Run(stopCh <-chan struct{}) {
+	logger := r.log
+	logger.V(2).Info("starting the certificate renewer", "interval", certCheckInterval, "renewBefore", r.renewBefore)
+
+	ticker := time.NewTicker(certCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.check(); err != nil {
+				logger.Error(err, "failed to renew the serving certificate")
+			}
+
+		case <-stopCh:
+			logger.V(2).Info("stopping the certificate renewer")
+			return
+		}
+	}
+}
+
+// check renews the serving certificate when it expires and the lock is held, or serves the
+// certificate renewed by the holder of the lock
+func (r *CertRenewer) check() error {
+	if !r.lock.acquire() {
+		return r.sync()
+	}
+
+	if !tls.IsTLSPairExpiring(r.certificates.PemPair(), r.renewBefore) {
+		return nil
+	}
+
+	return r.renew()
+}
+
+// sync serves the certificate of the secret when it was renewed by another replica
+func (r *CertRenewer) sync() error {
+	certProps, err := r.client.GetTLSCertProps(r.clientConfig)
+	if err != nil {
+		return err
+	}
+
+	tlsPair := r.client.ReadTLSPair(certProps)
+	if tlsPair == nil || bytes.Equal(tlsPair.Certificate, r.certificates.PemPair().Certificate) {
+		return nil
+	}
+
+	if err := r.certificates.Update(tlsPair); err != nil {
+		return err
+	}
+
+	r.log.Info("serving the certificate renewed by another replica")
+	return nil
+}
+
+func (r *CertRenewer) renew() error {
+	logger := r.log
+	logger.Info("renewing the serving certificate before it expires")
+
+	previousCA := r.client.ReadRootCASecret()
+	tlsPair, err := r.client.InitTLSPemPair(r.clientConfig, r.serverIP, r.certOptions)
+	if err != nil {
+		return err
+	}
+
+	// the webhook configurations trust both CAs, so that the requests sent to the
+	// previous certificate are accepted until the renewed certificate is served
+	caBundle := append(r.client.ReadRootCASecret(), previousCA...)
+	if err := r.register.UpdateCABundle(caBundle); err != nil {
+		return err
+	}
+
+	if err := r.certificates.Update(tlsPair); err != nil {
+		return err
+	}
+
+	logger.Info("serving the renewed certificate")
+	return nil
+}
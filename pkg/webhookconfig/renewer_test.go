@@ -0,0 +1,77 @@
+package webhookconfig
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/kyverno/kyverno/pkg/config"
+	client "github.com/kyverno/kyverno/pkg/dclient"
+	tls "github.com/kyverno/kyverno/pkg/tls"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	rest "k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func newSecret(name string, annotations map[string]string, data map[string][]byte) *unstructured.Unstructured {
+	secret := &unstructured.Unstructured{}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	secret.SetNamespace(config.KyvernoNamespace)
+	secret.SetName(name)
+	secret.SetAnnotations(annotations)
+
+	encoded := map[string]interface{}{}
+	for key, value := range data {
+		encoded[key] = base64.StdEncoding.EncodeToString(value)
+	}
+	secret.Object["data"] = encoded
+	return secret
+}
+
+func newServingPair(t *testing.T, props tls.CertificateProps) (*tls.PemPair, *tls.PemPair) {
+	caCert, caPem, err := tls.GenerateCACert(tls.CertificateOptions{Validity: time.Hour})
+	assert.NilError(t, err)
+	pemPair, err := tls.GenerateCertPem(caCert, props, "", tls.CertificateOptions{Validity: time.Hour})
+	assert.NilError(t, err)
+	return caPem, pemPair
+}
+
+func Test_CertRenewer_NotHolder(t *testing.T) {
+	clientConfig := &rest.Config{Host: "https://10.0.0.1"}
+	props := tls.CertificateProps{Service: config.KyvernoServiceName, Namespace: config.KyvernoNamespace, APIServerHost: "10.0.0.1"}
+	serviceName := tls.GenerateInClusterServiceName(props)
+
+	_, servedPair := newServingPair(t, props)
+	caPem, renewedPair := newServingPair(t, props)
+
+	// the lock is held by another replica, which renewed the certificate
+	lock := map[string]string{
+		"kyverno.io/cert-lock-holder": "kyverno-other",
+		"kyverno.io/cert-lock-expiry": time.Now().Add(time.Minute).UTC().Format(time.RFC3339),
+	}
+	dclient, err := client.NewMockClient(runtime.NewScheme(), nil,
+		newSecret(serviceName+".kyverno-tls-ca", lock, map[string][]byte{"rootCA.crt": caPem.Certificate}),
+		newSecret(serviceName+".kyverno-tls-pair", nil, map[string][]byte{"tls.crt": renewedPair.Certificate, "tls.key": renewedPair.PrivateKey}),
+	)
+	assert.NilError(t, err)
+	dclient.SetDiscovery(client.NewFakeDiscoveryClient(nil))
+
+	certificates, err := tls.NewCertificateHolder(servedPair)
+	assert.NilError(t, err)
+
+	// the served certificate expires within the renewal period, it is not renewed by the replica
+	register := &Register{client: dclient, clientConfig: clientConfig, log: log.Log}
+	renewer := NewCertRenewer(dclient, clientConfig, "", certificates, tls.CertificateOptions{}, register, 2*time.Hour, log.Log)
+	assert.NilError(t, renewer.check())
+	assert.DeepEqual(t, certificates.PemPair(), renewedPair)
+	rootCA, err := dclient.GetResource("", client.Secrets, config.KyvernoNamespace, serviceName+".kyverno-tls-ca")
+	assert.NilError(t, err)
+	caData, _, _ := unstructured.NestedString(rootCA.Object, "data", "rootCA.crt")
+	assert.Equal(t, caData, base64.StdEncoding.EncodeToString(caPem.Certificate))
+
+	// the CA bundle is reconciled by the holder, the webhook configurations are not read
+	assert.NilError(t, register.ReconcileCABundle())
+}
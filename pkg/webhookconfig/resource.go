@@ -14,23 +14,28 @@ func (wrc *Register) constructDebugMutatingWebhookConfig(caData []byte) *admrega
 	logger := wrc.log
 	url := fmt.Sprintf("https://%s%s", wrc.serverIP, config.MutatingWebhookServicePath)
 	logger.V(4).Info("Debug MutatingWebhookConfig registered", "url", url)
+
+	webhookCfg := generateDebugMutatingWebhook(
+		config.MutatingWebhookName,
+		url,
+		caData,
+		true,
+		wrc.timeoutSeconds,
+		[]string{"*/*"},
+		"*",
+		"*",
+		[]admregapi.OperationType{admregapi.Create, admregapi.Update},
+	)
+
+	// reinvoke the webhook if other mutating webhooks modified the resource
+	reinvoke := admregapi.IfNeededReinvocationPolicy
+	webhookCfg.ReinvocationPolicy = &reinvoke
+
 	return &admregapi.MutatingWebhookConfiguration{
 		ObjectMeta: v1.ObjectMeta{
 			Name: config.MutatingWebhookConfigurationDebugName,
 		},
-		Webhooks: []admregapi.MutatingWebhook{
-			generateDebugMutatingWebhook(
-				config.MutatingWebhookName,
-				url,
-				caData,
-				true,
-				wrc.timeoutSeconds,
-				[]string{"*/*"},
-				"*",
-				"*",
-				[]admregapi.OperationType{admregapi.Create, admregapi.Update},
-			),
-		},
+		Webhooks: []admregapi.MutatingWebhook{webhookCfg},
 	}
 }
 
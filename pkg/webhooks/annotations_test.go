@@ -98,3 +98,38 @@ func Test_annotation_failed_Patch(t *testing.T) {
 
 	assert.Assert(t, annPatches == nil)
 }
+
+func Test_annotation_multiple_patches(t *testing.T) {
+	patchStrs := []string{
+		`{ "op": "replace", "path": "/spec/containers/0/imagePullPolicy", "value": "IfNotPresent" }`,
+		`{ "op": "add", "path": "/spec/containers/1/imagePullPolicy", "value": "IfNotPresent" }`,
+	}
+	engineResponse := newEngineResponse("mutate-container", "default-imagepullpolicy", patchStrs, true, nil)
+	annPatches := generateAnnotationPatches([]*response.EngineResponse{engineResponse}, log.Log)
+
+	expectedPatches := `{"op":"add","path":"/metadata/annotations","value":{"policies.kyverno.io/patches":"default-imagepullpolicy.mutate-container.kyverno.io: replaced /spec/containers/0/imagePullPolicy, added /spec/containers/1/imagePullPolicy\n"}}`
+	assert.Equal(t, string(annPatches), expectedPatches)
+}
+
+func Test_annotation_added_by_policy(t *testing.T) {
+	patchStr := `{ "op": "add", "path": "/metadata/annotations", "value": {"team": "dev"} }`
+	first := newEngineResponse("add-annotation", "team", []string{patchStr}, true, nil)
+	second := newEngineResponse("mutate-container", "default-imagepullpolicy", []string{`{ "op": "replace", "path": "/spec/containers/0/imagePullPolicy", "value": "IfNotPresent" }`}, true, nil)
+	second.PatchedResource.SetAnnotations(map[string]string{"team": "dev"})
+
+	annPatches := generateAnnotationPatches([]*response.EngineResponse{first, second}, log.Log)
+
+	expectedPatches := `{"op":"add","path":"/metadata/annotations/policies.kyverno.io~1patches","value":"default-imagepullpolicy.mutate-container.kyverno.io: replaced /spec/containers/0/imagePullPolicy\nteam.add-annotation.kyverno.io: added /metadata/annotations\n"}`
+	assert.Equal(t, string(annPatches), expectedPatches)
+}
+
+func Test_annotation_unchanged(t *testing.T) {
+	patchStr := `{ "op": "replace", "path": "/spec/containers/0/imagePullPolicy", "value": "IfNotPresent" }`
+	engineResponse := newEngineResponse("mutate-container", "default-imagepullpolicy", []string{patchStr}, true, nil)
+	engineResponse.PatchedResource.SetAnnotations(map[string]string{
+		"policies.kyverno.io/patches": "default-imagepullpolicy.mutate-container.kyverno.io: replaced /spec/containers/0/imagePullPolicy\n",
+	})
+
+	annPatches := generateAnnotationPatches([]*response.EngineResponse{engineResponse}, log.Log)
+	assert.Assert(t, annPatches == nil)
+}
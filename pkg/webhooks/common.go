@@ -1,18 +1,22 @@
 package webhooks
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/common"
+	enginectx "github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/engine/response"
 	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
 	yamlv2 "gopkg.in/yaml.v2"
 	"k8s.io/api/admission/v1beta1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	listerv1 "k8s.io/client-go/listers/core/v1"
 )
 
 // isResponseSuccessful return true if all responses are successful
@@ -110,6 +114,11 @@ func processResourceWithPatches(patch []byte, resource []byte, log logr.Logger)
 	return resource
 }
 
+// rbacVariables matches the variables referencing the roles and cluster roles of the requesting user
+var rbacVariables = regexp.MustCompile(`\{\{\s*request\.(roles|clusterRoles)\b`)
+
+// containRBACInfo checks if the roles of the requesting user are needed to apply the policies,
+// either to match and exclude resources or to resolve the request.roles and request.clusterRoles variables
 func containRBACInfo(policies ...[]*kyverno.ClusterPolicy) bool {
 	for _, policySlice := range policies {
 		for _, policy := range policySlice {
@@ -117,12 +126,25 @@ func containRBACInfo(policies ...[]*kyverno.ClusterPolicy) bool {
 				if len(rule.MatchResources.Roles) > 0 || len(rule.MatchResources.ClusterRoles) > 0 || len(rule.ExcludeResources.Roles) > 0 || len(rule.ExcludeResources.ClusterRoles) > 0 {
 					return true
 				}
+
+				if hasRBACVariables(rule) {
+					return true
+				}
 			}
 		}
 	}
 	return false
 }
 
+func hasRBACVariables(rule kyverno.Rule) bool {
+	ruleRaw, err := json.Marshal(rule)
+	if err != nil {
+		return false
+	}
+
+	return rbacVariables.Match(ruleRaw)
+}
+
 // extracts the new and old resource as unstructured
 func extractResources(newRaw []byte, request *v1beta1.AdmissionRequest) (unstructured.Unstructured, unstructured.Unstructured, error) {
 	var emptyResource unstructured.Unstructured
@@ -153,6 +175,23 @@ func extractResources(newRaw []byte, request *v1beta1.AdmissionRequest) (unstruc
 	return new, old, err
 }
 
+// addNamespaceMetadata loads the labels and annotations of the request namespace in the context
+// as namespaceLabels and namespaceAnnotations, and returns the namespace labels
+func addNamespaceMetadata(ctx *enginectx.Context, request *v1beta1.AdmissionRequest, nsLister listerv1.NamespaceLister, logger logr.Logger) map[string]string {
+	namespaceLabels := make(map[string]string)
+	namespaceAnnotations := make(map[string]string)
+	if request.Kind.Kind != "Namespace" && request.Namespace != "" {
+		namespaceLabels = common.GetNamespaceSelectorsFromNamespaceLister(request.Kind.Kind, request.Namespace, nsLister, logger)
+		namespaceAnnotations = common.GetNamespaceAnnotationsFromNamespaceLister(request.Kind.Kind, request.Namespace, nsLister, logger)
+	}
+
+	if err := ctx.AddNamespaceMetadata(namespaceLabels, namespaceAnnotations); err != nil {
+		logger.Error(err, "failed to load namespace metadata in context")
+	}
+
+	return namespaceLabels
+}
+
 // convertResource converts raw bytes to an unstructured object
 func convertResource(raw []byte, group, version, kind, namespace string) (unstructured.Unstructured, error) {
 	obj, err := engineutils.ConvertToUnstructured(raw)
@@ -185,3 +224,9 @@ func excludeKyvernoResources(kind string) bool {
 		return false
 	}
 }
+
+// isDryRun checks if the admission request is a dry run, the webhooks declare no side effects on
+// dry runs so no events, reports, policy statuses or generate requests are created for them
+func isDryRun(request *v1beta1.AdmissionRequest) bool {
+	return request.DryRun != nil && *request.DryRun
+}
@@ -0,0 +1,85 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	enginectx "github.com/kyverno/kyverno/pkg/engine/context"
+	"gotest.tools/assert"
+	"k8s.io/api/admission/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_containRBACInfo(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     []byte
+		expected bool
+	}{
+		{
+			name:     "no roles",
+			rule:     []byte(`{"name":"created-by","match":{"resources":{"kinds":["Pod"]}},"mutate":{"patchStrategicMerge":{"metadata":{"annotations":{"created-by":"{{request.userInfo.username}}"}}}}}`),
+			expected: false,
+		},
+		{
+			name:     "match roles",
+			rule:     []byte(`{"name":"match-roles","match":{"resources":{"kinds":["Pod"]},"clusterRoles":["admin"]},"mutate":{"patchStrategicMerge":{"metadata":{"labels":{"admin":"true"}}}}}`),
+			expected: true,
+		},
+		{
+			name:     "roles variable",
+			rule:     []byte(`{"name":"roles-variable","match":{"resources":{"kinds":["Pod"]}},"validate":{"deny":{"conditions":[{"key":"admin","operator":"NotIn","value":"{{ request.clusterRoles }}"}]}}}`),
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		var rule kyverno.Rule
+		assert.NilError(t, json.Unmarshal(test.rule, &rule), test.name)
+		policy := &kyverno.ClusterPolicy{Spec: kyverno.Spec{Rules: []kyverno.Rule{rule}}}
+		assert.Equal(t, containRBACInfo([]*kyverno.ClusterPolicy{policy}), test.expected, test.name)
+	}
+}
+
+func Test_addNamespaceMetadata(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NilError(t, indexer.Add(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "payments",
+			Labels:      map[string]string{"environment": "prod"},
+			Annotations: map[string]string{"owner": "team-a"},
+		},
+	}))
+	nsLister := listerv1.NewNamespaceLister(indexer)
+
+	request := &v1beta1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		Namespace: "payments",
+	}
+
+	ctx := enginectx.NewContext()
+	namespaceLabels := addNamespaceMetadata(ctx, request, nsLister, log.Log)
+	assert.DeepEqual(t, namespaceLabels, map[string]string{"environment": "prod"})
+
+	environment, err := ctx.Query("namespaceLabels.environment")
+	assert.NilError(t, err)
+	assert.Equal(t, environment, "prod")
+
+	owner, err := ctx.Query("namespaceAnnotations.owner")
+	assert.NilError(t, err)
+	assert.Equal(t, owner, "team-a")
+
+	// cluster wide resources have no namespace metadata
+	request = &v1beta1.AdmissionRequest{Kind: metav1.GroupVersionKind{Version: "v1", Kind: "Namespace"}, Name: "payments"}
+	ctx = enginectx.NewContext()
+	assert.Equal(t, len(addNamespaceMetadata(ctx, request, nsLister, log.Log)), 0)
+
+	environment, err = ctx.Query("namespaceLabels.environment")
+	assert.NilError(t, err)
+	assert.Equal(t, environment, nil)
+}
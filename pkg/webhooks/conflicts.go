@@ -0,0 +1,152 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+)
+
+// PriorityAnnotation defines the annotation key for the mutation priority of a policy.
+// Policies with a higher priority are applied first and win mutation conflicts.
+const PriorityAnnotation = "policies.kyverno.io/priority"
+
+// sortByPriority returns a copy of the policies sorted by descending priority,
+// policies with the same priority are sorted by namespace and name
+func sortByPriority(policies []*kyverno.ClusterPolicy, log logr.Logger) []*kyverno.ClusterPolicy {
+	sorted := make([]*kyverno.ClusterPolicy, len(policies))
+	copy(sorted, policies)
+
+	priorities := make(map[*kyverno.ClusterPolicy]int, len(sorted))
+	for _, policy := range sorted {
+		priorities[policy] = getPriority(policy, log)
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if priorities[sorted[i]] != priorities[sorted[j]] {
+			return priorities[sorted[i]] > priorities[sorted[j]]
+		}
+		if sorted[i].GetNamespace() != sorted[j].GetNamespace() {
+			return sorted[i].GetNamespace() < sorted[j].GetNamespace()
+		}
+		return sorted[i].GetName() < sorted[j].GetName()
+	})
+
+	return sorted
+}
+
+func getPriority(policy *kyverno.ClusterPolicy, log logr.Logger) int {
+	value, ok := policy.GetAnnotations()[PriorityAnnotation]
+	if !ok {
+		return 0
+	}
+
+	priority, err := strconv.Atoi(value)
+	if err != nil {
+		log.Info("invalid policy priority, defaulting to 0", "policy", policy.GetName(), "priority", value)
+		return 0
+	}
+
+	return priority
+}
+
+// patchOwner is the rule that last set the value of a path
+type patchOwner struct {
+	policy string
+	rule   string
+	op     string
+	value  interface{}
+}
+
+// equals checks both rules set the same value, add and replace operations are equivalent
+func (o patchOwner) equals(other patchOwner) bool {
+	if (o.op == "remove") != (other.op == "remove") {
+		return false
+	}
+	return reflect.DeepEqual(o.value, other.value)
+}
+
+// patchOwners tracks the paths patched by the policies applied to a resource
+type patchOwners map[string]patchOwner
+
+// dropConflicts drops the patches of the engine response that patch a path already patched
+// by a previous policy with a different value, the patches of the previous policy are kept.
+// The other patches of the rules are kept, the message of the rules records the conflicts.
+func (owners patchOwners) dropConflicts(engineResponse *response.EngineResponse, log logr.Logger) (conflicts []string) {
+	policy := engineResponse.PolicyResponse.Policy
+	for i, rule := range engineResponse.PolicyResponse.Rules {
+		if !rule.Success || len(rule.Patches) == 0 {
+			continue
+		}
+
+		var kept [][]byte
+		var msgs []string
+		for _, patch := range rule.Patches {
+			path, owner, err := decodePatch(patch)
+			if err != nil {
+				log.Error(err, "failed to decode patch", "patch", string(patch))
+				kept = append(kept, patch)
+				continue
+			}
+
+			existing, ok := owners[path]
+			if !ok || existing.policy == policy || existing.equals(owner) {
+				kept = append(kept, patch)
+				continue
+			}
+
+			msg := fmt.Sprintf("mutation conflicts with rule %s of policy %s at path %s", existing.rule, existing.policy, path)
+			msgs = append(msgs, msg)
+			conflicts = append(conflicts, fmt.Sprintf("rule %s: %s", rule.Name, msg))
+		}
+
+		if len(msgs) == 0 {
+			continue
+		}
+
+		engineResponse.PolicyResponse.Rules[i].Patches = kept
+		engineResponse.PolicyResponse.Rules[i].Message = strings.Join(msgs, "; ")
+	}
+
+	return conflicts
+}
+
+// add records the paths patched by the successful rules of the engine response
+func (owners patchOwners) add(engineResponse *response.EngineResponse) {
+	for _, rule := range engineResponse.PolicyResponse.Rules {
+		if !rule.Success {
+			continue
+		}
+
+		for _, patch := range rule.Patches {
+			path, owner, err := decodePatch(patch)
+			if err != nil {
+				continue
+			}
+
+			owner.policy = engineResponse.PolicyResponse.Policy
+			owner.rule = rule.Name
+			owners[path] = owner
+		}
+	}
+}
+
+func decodePatch(patch []byte) (string, patchOwner, error) {
+	var p struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}
+
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return "", patchOwner{}, err
+	}
+
+	return p.Path, patchOwner{op: p.Op, value: p.Value}, nil
+}
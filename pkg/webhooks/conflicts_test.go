@@ -0,0 +1,64 @@
+package webhooks
+
+import (
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"gotest.tools/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_sortByPriority(t *testing.T) {
+	newPolicy := func(name, priority string) *kyverno.ClusterPolicy {
+		policy := &kyverno.ClusterPolicy{}
+		policy.SetName(name)
+		if priority != "" {
+			policy.SetAnnotations(map[string]string{PriorityAnnotation: priority})
+		}
+		return policy
+	}
+
+	policies := []*kyverno.ClusterPolicy{
+		newPolicy("c", ""),
+		newPolicy("b", "10"),
+		newPolicy("a", ""),
+		newPolicy("d", "invalid"),
+		newPolicy("e", "-1"),
+	}
+
+	var names []string
+	for _, policy := range sortByPriority(policies, log.Log) {
+		names = append(names, policy.GetName())
+	}
+
+	assert.DeepEqual(t, names, []string{"b", "a", "c", "d", "e"})
+	assert.Equal(t, policies[0].GetName(), "c")
+}
+
+func Test_dropConflicts(t *testing.T) {
+	owners := patchOwners{}
+
+	first := newEngineResponse("set-pull-policy", "always", []string{`{"op":"add","path":"/spec/containers/0/imagePullPolicy","value":"Always"}`}, true, nil)
+	assert.Equal(t, len(owners.dropConflicts(first, log.Log)), 0)
+	owners.add(first)
+
+	// the same value set by another policy is not a conflict
+	same := newEngineResponse("pull-policy", "always", []string{`{"op":"replace","path":"/spec/containers/0/imagePullPolicy","value":"Always"}`}, true, nil)
+	assert.Equal(t, len(owners.dropConflicts(same, log.Log)), 0)
+	assert.Assert(t, same.IsSuccessful())
+
+	conflicting := newEngineResponse("never-pull", "never", []string{
+		`{"op":"replace","path":"/spec/containers/0/imagePullPolicy","value":"Never"}`,
+		`{"op":"add","path":"/spec/containers/0/name","value":"nginx"}`,
+	}, true, nil)
+	conflicts := owners.dropConflicts(conflicting, log.Log)
+	assert.Equal(t, len(conflicts), 1)
+	// only the conflicting patch is dropped
+	assert.Assert(t, conflicting.IsSuccessful())
+	assert.DeepEqual(t, conflicting.GetPatches(), [][]byte{[]byte(`{"op":"add","path":"/spec/containers/0/name","value":"nginx"}`)})
+	assert.Equal(t, conflicting.PolicyResponse.Rules[0].Message, "mutation conflicts with rule always of policy set-pull-policy at path /spec/containers/0/imagePullPolicy")
+
+	removal := newEngineResponse("remove-pull-policy", "remove", []string{`{"op":"remove","path":"/spec/containers/0/imagePullPolicy"}`}, true, nil)
+	assert.Equal(t, len(owners.dropConflicts(removal, log.Log)), 1)
+	assert.Equal(t, len(removal.GetPatches()), 0)
+}
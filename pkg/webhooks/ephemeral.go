@@ -0,0 +1,86 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/engine/mutate"
+	v1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const ephemeralContainersPath = "/spec/ephemeralContainers"
+
+// isEphemeralContainersRequest checks if the request updates the ephemeral containers of a pod
+// through an EphemeralContainers object, which is used by the subresource prior to Kubernetes 1.22
+func isEphemeralContainersRequest(request *v1beta1.AdmissionRequest) bool {
+	return request.Resource.Resource == "pods" &&
+		request.SubResource == mutate.EphemeralContainersSubresource &&
+		request.Kind.Kind == "EphemeralContainers"
+}
+
+// ephemeralContainersToPod converts the EphemeralContainers object to a pod holding the ephemeral containers,
+// so that pod policies can be applied to it
+func ephemeralContainersToPod(resource unstructured.Unstructured) unstructured.Unstructured {
+	pod := unstructured.Unstructured{Object: map[string]interface{}{}}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	if metadata, ok := resource.Object["metadata"]; ok {
+		pod.Object["metadata"] = metadata
+	}
+
+	spec := map[string]interface{}{}
+	if containers, ok := resource.Object["ephemeralContainers"]; ok {
+		spec["ephemeralContainers"] = containers
+	}
+	pod.Object["spec"] = spec
+
+	return pod
+}
+
+// podPatchesToEphemeralContainers converts the patches of the pod view to patches of the
+// EphemeralContainers object, the patches of other fields are discarded
+func podPatchesToEphemeralContainers(patches []byte, log logr.Logger) []byte {
+	if len(patches) == 0 {
+		return nil
+	}
+
+	var operations []map[string]interface{}
+	if err := json.Unmarshal(patches, &operations); err != nil {
+		log.Error(err, "failed to decode patches")
+		return nil
+	}
+
+	var converted []map[string]interface{}
+	for _, operation := range operations {
+		path, _ := operation["path"].(string)
+		from, hasFrom := operation["from"].(string)
+		if !isEphemeralContainersPath(path) || (hasFrom && !isEphemeralContainersPath(from)) {
+			log.V(4).Info("discard patch, only ephemeral containers can be updated", "path", path)
+			continue
+		}
+
+		operation["path"] = strings.TrimPrefix(path, "/spec")
+		if hasFrom {
+			operation["from"] = strings.TrimPrefix(from, "/spec")
+		}
+		converted = append(converted, operation)
+	}
+
+	if len(converted) == 0 {
+		return nil
+	}
+
+	result, err := json.Marshal(converted)
+	if err != nil {
+		log.Error(err, "failed to encode patches")
+		return nil
+	}
+
+	return result
+}
+
+func isEphemeralContainersPath(path string) bool {
+	return path == ephemeralContainersPath || strings.HasPrefix(path, ephemeralContainersPath+"/")
+}
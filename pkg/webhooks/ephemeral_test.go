@@ -0,0 +1,34 @@
+package webhooks
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_ephemeralContainersToPod(t *testing.T) {
+	var resource unstructured.Unstructured
+	assert.NilError(t, resource.UnmarshalJSON([]byte(`{"apiVersion":"v1","kind":"EphemeralContainers","metadata":{"name":"nginx","namespace":"default"},"ephemeralContainers":[{"name":"debugger","image":"busybox"}]}`)))
+
+	pod := ephemeralContainersToPod(resource)
+	assert.Equal(t, pod.GetKind(), "Pod")
+	assert.Equal(t, pod.GetName(), "nginx")
+	assert.Equal(t, pod.GetNamespace(), "default")
+
+	containers, found, err := unstructured.NestedSlice(pod.Object, "spec", "ephemeralContainers")
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+	assert.Equal(t, len(containers), 1)
+}
+
+func Test_podPatchesToEphemeralContainers(t *testing.T) {
+	patches := []byte(`[{"op":"add","path":"/spec/ephemeralContainers/0/imagePullPolicy","value":"Always"},{"op":"add","path":"/metadata/annotations","value":{"policies.kyverno.io/patches":"added"}},{"op":"copy","from":"/spec/containers/0","path":"/spec/ephemeralContainers/1"}]`)
+
+	converted := podPatchesToEphemeralContainers(patches, log.Log)
+	assert.Equal(t, string(converted), `[{"op":"add","path":"/ephemeralContainers/0/imagePullPolicy","value":"Always"}]`)
+
+	assert.Assert(t, podPatchesToEphemeralContainers([]byte(`[{"op":"add","path":"/metadata/labels","value":{"app":"nginx"}}]`), log.Log) == nil)
+	assert.Assert(t, podPatchesToEphemeralContainers(nil, log.Log) == nil)
+}
@@ -16,6 +16,7 @@ import (
 	"k8s.io/api/admission/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
@@ -121,6 +122,42 @@ func (g *Generator) generate(grSpec kyverno.GenerateRequestSpec, action v1beta1.
 // -> receiving channel to take requests to create request
 // use worker pattern to read and create the CR resource
 
+// grLabels returns the labels of the generate request of the policy and the trigger resource, the
+// generate requests of namespaced policies are labeled with the namespace of the policy
+func grLabels(grSpec kyverno.GenerateRequestSpec) map[string]string {
+	namespace, name, err := cache.SplitMetaNamespaceKey(grSpec.Policy)
+	if err != nil {
+		namespace, name = "", grSpec.Policy
+	}
+
+	grLabels := map[string]string{
+		"generate.kyverno.io/policy-name":        name,
+		"generate.kyverno.io/resource-name":      grSpec.Resource.Name,
+		"generate.kyverno.io/resource-kind":      grSpec.Resource.Kind,
+		"generate.kyverno.io/resource-namespace": grSpec.Resource.Namespace,
+	}
+	if namespace != "" {
+		grLabels["generate.kyverno.io/policy-namespace"] = namespace
+	}
+	return grLabels
+}
+
+// grSelector selects the generate requests of the policy and the trigger resource, the generate requests
+// of cluster policies are not labeled with a policy namespace
+func grSelector(grSpec kyverno.GenerateRequestSpec) (labels.Selector, error) {
+	grLabels := grLabels(grSpec)
+	selector := labels.SelectorFromSet(labels.Set(grLabels))
+	if _, ok := grLabels["generate.kyverno.io/policy-namespace"]; ok {
+		return selector, nil
+	}
+
+	requirement, err := labels.NewRequirement("generate.kyverno.io/policy-namespace", selection.DoesNotExist, nil)
+	if err != nil {
+		return nil, err
+	}
+	return selector.Add(*requirement), nil
+}
+
 func retryApplyResource(client *kyvernoclient.Clientset, grSpec kyverno.GenerateRequestSpec,
 	log logr.Logger, action v1beta1.Operation, grLister kyvernolister.GenerateRequestNamespaceLister) error {
 
@@ -140,12 +177,11 @@ func retryApplyResource(client *kyvernoclient.Clientset, grSpec kyverno.Generate
 		isExist := false
 		if action == v1beta1.Create || action == v1beta1.Update {
 			log.V(4).Info("querying all generate requests")
-			selector := labels.SelectorFromSet(labels.Set(map[string]string{
-				"generate.kyverno.io/policy-name":        grSpec.Policy,
-				"generate.kyverno.io/resource-name":      grSpec.Resource.Name,
-				"generate.kyverno.io/resource-kind":      grSpec.Resource.Kind,
-				"generate.kyverno.io/resource-namespace": grSpec.Resource.Namespace,
-			}))
+			selector, err := grSelector(grSpec)
+			if err != nil {
+				return err
+			}
+
 			grList, err := grLister.List(selector)
 			if err != nil {
 				logger.Error(err, "failed to get generate request for the resource", "kind", grSpec.Resource.Kind, "name", grSpec.Resource.Name, "namespace", grSpec.Resource.Namespace)
@@ -173,12 +209,7 @@ func retryApplyResource(client *kyvernoclient.Clientset, grSpec kyverno.Generate
 
 			if !isExist {
 				gr.SetGenerateName("gr-")
-				gr.SetLabels(map[string]string{
-					"generate.kyverno.io/policy-name":        grSpec.Policy,
-					"generate.kyverno.io/resource-name":      grSpec.Resource.Name,
-					"generate.kyverno.io/resource-kind":      grSpec.Resource.Kind,
-					"generate.kyverno.io/resource-namespace": grSpec.Resource.Namespace,
-				})
+				gr.SetLabels(grLabels(grSpec))
 				_, err = client.KyvernoV1().GenerateRequests(config.KyvernoNamespace).Create(context.TODO(), &gr, metav1.CreateOptions{})
 				if err != nil {
 					return err
@@ -0,0 +1,29 @@
+package generate
+
+import (
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func Test_grSelector(t *testing.T) {
+	resource := kyverno.ResourceSpec{Kind: "Namespace", Name: "team-a"}
+	cluster := kyverno.GenerateRequestSpec{Policy: "add-quota", Resource: resource}
+	namespaced := kyverno.GenerateRequestSpec{Policy: "team-a/add-quota", Resource: resource}
+
+	// the label values cannot contain the separator of the namespaced policy key
+	assert.Equal(t, grLabels(namespaced)["generate.kyverno.io/policy-name"], "add-quota")
+	assert.Equal(t, grLabels(namespaced)["generate.kyverno.io/policy-namespace"], "team-a")
+
+	clusterSelector, err := grSelector(cluster)
+	assert.NilError(t, err)
+	namespacedSelector, err := grSelector(namespaced)
+	assert.NilError(t, err)
+
+	assert.Assert(t, clusterSelector.Matches(labels.Set(grLabels(cluster))))
+	assert.Assert(t, !clusterSelector.Matches(labels.Set(grLabels(namespaced))))
+	assert.Assert(t, namespacedSelector.Matches(labels.Set(grLabels(namespaced))))
+	assert.Assert(t, !namespacedSelector.Matches(labels.Set(grLabels(cluster))))
+}
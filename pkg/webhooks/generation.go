@@ -34,6 +34,9 @@ func (ws *WebhookServer) HandleGenerate(request *v1beta1.AdmissionRequest, polic
 	logger.V(4).Info("incoming request")
 	var engineResponses []*response.EngineResponse
 	if request.Operation == v1beta1.Create || request.Operation == v1beta1.Update {
+		// the sources of cloneList rules are selected by labels, they are not labeled by the policies
+		ws.handleCloneListSource(request, logger)
+
 		if len(policies) == 0 {
 			return
 		}
@@ -54,6 +57,8 @@ func (ws *WebhookServer) HandleGenerate(request *v1beta1.AdmissionRequest, polic
 			Client:              ws.client,
 		}
 
+		// the failures of the policies with suppressed events are not reported
+		suppressed := map[*response.EngineResponse]bool{}
 		for _, policy := range policies {
 			var rules []response.RuleResponse
 			policyContext.Policy = *policy
@@ -73,6 +78,7 @@ func (ws *WebhookServer) HandleGenerate(request *v1beta1.AdmissionRequest, polic
 				engineResponse.PolicyResponse.Rules = rules
 				// some generate rules do apply to the resource
 				engineResponses = append(engineResponses, engineResponse)
+				suppressed[engineResponse] = !policy.EventsEnabled()
 				ws.statusListener.Update(generateStats{
 					resp: engineResponse,
 				})
@@ -83,6 +89,10 @@ func (ws *WebhookServer) HandleGenerate(request *v1beta1.AdmissionRequest, polic
 		if failedResponse := applyGenerateRequest(ws.grGenerator, userRequestInfo, request.Operation, engineResponses...); err != nil {
 			// report failure event
 			for _, failedGR := range failedResponse {
+				if suppressed[failedGR.er] {
+					continue
+				}
+
 				events := failedEvents(fmt.Errorf("failed to create Generate Request: %v", failedGR.err), failedGR.gr, new)
 				ws.eventGen.Add(events...)
 			}
@@ -116,21 +126,84 @@ func (ws *WebhookServer) handleUpdate(request *v1beta1.AdmissionRequest, policie
 func (ws *WebhookServer) handleUpdateCloneSourceResource(resLabels map[string]string, logger logr.Logger) {
 	policyNames := strings.Split(resLabels["generate.kyverno.io/clone-policy-name"], ",")
 	for _, policyName := range policyNames {
-		selector := labels.SelectorFromSet(labels.Set(map[string]string{
-			"generate.kyverno.io/policy-name": policyName,
-		}))
-
-		grList, err := ws.grLister.List(selector)
-		if err != nil {
+		if err := ws.enqueuePolicyGenerateRequests(policyName); err != nil {
 			logger.Error(err, "failed to get generate request for the resource", "label", "generate.kyverno.io/policy-name")
 			return
 		}
-		for _, gr := range grList {
-			ws.grController.EnqueueGenerateRequestFromWebhook(gr)
+	}
+}
+
+//handleCloneListSource - handles creation, updation and deletion of the sources selected by the cloneList of generate policies
+func (ws *WebhookServer) handleCloneListSource(request *v1beta1.AdmissionRequest, logger logr.Logger) {
+	policies, err := ws.pLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "failed to list policies")
+		return
+	}
+
+	new, old, err := kyvernoutils.ExtractResources(nil, request)
+	if err != nil {
+		logger.Error(err, "failed to extract resource")
+		return
+	}
+
+	for _, policy := range policies {
+		if !isCloneListSource(policy, new, old) {
+			continue
+		}
+
+		if err := ws.enqueuePolicyGenerateRequests(policy.GetName()); err != nil {
+			logger.Error(err, "failed to get generate request for the resource", "label", "generate.kyverno.io/policy-name")
 		}
 	}
 }
 
+// isCloneListSource checks if a cloneList of the policy selects one of the resources, i.e. the
+// resource before or after the request, so that the synchronized clones of the resources deleted
+// or no longer selected are deleted
+func isCloneListSource(policy *kyverno.ClusterPolicy, resources ...unstructured.Unstructured) bool {
+	for _, rule := range policy.Spec.Rules {
+		cloneList := rule.Generation.CloneList
+		if cloneList == nil {
+			continue
+		}
+
+		selector := labels.Everything()
+		if cloneList.Selector != nil {
+			var err error
+			if selector, err = metav1.LabelSelectorAsSelector(cloneList.Selector); err != nil {
+				continue
+			}
+		}
+
+		for _, resource := range resources {
+			if resource.GetKind() == rule.Generation.Kind && resource.GetNamespace() == cloneList.Namespace && selector.Matches(labels.Set(resource.GetLabels())) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// enqueuePolicyGenerateRequests enqueues the generate requests of the policy to synchronize the generated resources
+func (ws *WebhookServer) enqueuePolicyGenerateRequests(policyName string) error {
+	selector := labels.SelectorFromSet(labels.Set(map[string]string{
+		"generate.kyverno.io/policy-name": policyName,
+	}))
+
+	grList, err := ws.grLister.List(selector)
+	if err != nil {
+		return err
+	}
+
+	for _, gr := range grList {
+		ws.grController.EnqueueGenerateRequestFromWebhook(gr)
+	}
+
+	return nil
+}
+
 //handleUpdateTargetResource - handles updation of target resource for generate policy
 func (ws *WebhookServer) handleUpdateTargetResource(request *v1beta1.AdmissionRequest, policies []*v1.ClusterPolicy, resLabels map[string]string, logger logr.Logger) {
 	enqueueBool := false
@@ -146,6 +219,23 @@ func (ws *WebhookServer) handleUpdateTargetResource(request *v1beta1.AdmissionRe
 	for _, policy := range policies {
 		if policy.GetName() == policyName {
 			for _, rule := range policy.Spec.Rules {
+				// the resources generated by a cloneList have the names of their source resources
+				cloneList := rule.Generation.CloneList
+				if rule.Generation.Kind == targetSourceKind && cloneList != nil {
+					obj, err := ws.client.GetResource("", rule.Generation.Kind, cloneList.Namespace, targetSourceName)
+					if err != nil {
+						logger.Error(err, fmt.Sprintf("source resource %s/%s/%s not found.", rule.Generation.Kind, cloneList.Namespace, targetSourceName))
+						continue
+					}
+
+					sourceObj, newResObj := stripNonPolicyFields(obj.Object, newRes.Object, logger)
+
+					if _, err := validate.ValidateResourceWithPattern(logger, newResObj, sourceObj); err != nil {
+						enqueueBool = true
+						break
+					}
+				}
+
 				if rule.Generation.Kind == targetSourceKind && rule.Generation.Name == targetSourceName {
 					data := rule.Generation.DeepCopy().Data
 					if data != nil {
@@ -258,6 +348,9 @@ func (ws *WebhookServer) handleDelete(request *v1beta1.AdmissionRequest) {
 		logger.Error(err, "failed to convert object resource to unstructured format")
 	}
 
+	// the synchronized clones of a deleted cloneList source are deleted
+	ws.handleCloneListSource(request, logger)
+
 	resLabels := resource.GetLabels()
 	if resLabels["app.kubernetes.io/managed-by"] == "kyverno" && resLabels["policy.kyverno.io/synchronize"] == "enable" && request.Operation == v1beta1.Delete {
 		grName := resLabels["policy.kyverno.io/gr-name"]
@@ -297,16 +390,16 @@ func applyGenerateRequest(gnGenerator generate.GenerateRequests, userRequestInfo
 	action v1beta1.Operation, engineResponses ...*response.EngineResponse) (failedGenerateRequest []generateRequestResponse) {
 
 	for _, er := range engineResponses {
-		gr := transform(userRequestInfo, er)
+		gr := transform(userRequestInfo, action, er)
 		if err := gnGenerator.Apply(gr, action); err != nil {
-			failedGenerateRequest = append(failedGenerateRequest, generateRequestResponse{gr: gr, err: err})
+			failedGenerateRequest = append(failedGenerateRequest, generateRequestResponse{gr: gr, er: er, err: err})
 		}
 	}
 
 	return
 }
 
-func transform(userRequestInfo kyverno.RequestInfo, er *response.EngineResponse) kyverno.GenerateRequestSpec {
+func transform(userRequestInfo kyverno.RequestInfo, action v1beta1.Operation, er *response.EngineResponse) kyverno.GenerateRequestSpec {
 	gr := kyverno.GenerateRequestSpec{
 		Policy: er.PolicyResponse.Policy,
 		Resource: kyverno.ResourceSpec{
@@ -317,6 +410,9 @@ func transform(userRequestInfo kyverno.RequestInfo, er *response.EngineResponse)
 		},
 		Context: kyverno.GenerateRequestContext{
 			UserRequestInfo: userRequestInfo,
+			AdmissionRequestInfo: kyverno.AdmissionRequestInfoObject{
+				Operation: string(action),
+			},
 		},
 	}
 
@@ -344,6 +440,7 @@ func (gs generateStats) UpdateStatus(status kyverno.PolicyStatus) kyverno.Policy
 	for _, rule := range gs.resp.PolicyResponse.Rules {
 		ruleStat := nameToRule[rule.Name]
 		ruleStat.Name = rule.Name
+		ruleStat.MatchedCount++
 
 		averageOver := int64(ruleStat.AppliedCount + ruleStat.FailedCount)
 		ruleStat.ExecutionTime = updateAverageTime(
@@ -395,6 +492,7 @@ func updateAverageTime(newTime time.Duration, oldAverageTimeString string, avera
 
 type generateRequestResponse struct {
 	gr  v1.GenerateRequestSpec
+	er  *response.EngineResponse
 	err error
 }
 
@@ -411,7 +509,7 @@ func failedEvents(err error, gr kyverno.GenerateRequestSpec, resource unstructur
 	re.Kind = resource.GetKind()
 	re.Namespace = resource.GetNamespace()
 	re.Name = resource.GetName()
-	re.Reason = event.PolicyFailed.String()
+	re.Reason = event.GenerationFailed.String()
 	re.Source = event.GeneratePolicyController
 	re.Message = fmt.Sprintf("policy %s failed to apply: %v", gr.Policy, err)
 
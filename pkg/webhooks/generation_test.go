@@ -1,10 +1,13 @@
 package webhooks
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func Test_updateFeildsInSourceAndUpdatedResource(t *testing.T) {
@@ -272,3 +275,44 @@ func Test_updateFeildsInSourceAndUpdatedResource(t *testing.T) {
 	}
 
 }
+
+func Test_isCloneListSource(t *testing.T) {
+	var policy kyverno.ClusterPolicy
+	err := json.Unmarshal([]byte(`{
+		"metadata": {"name": "sync-secrets"},
+		"spec": {
+			"rules": [
+				{
+					"name": "sync-secrets",
+					"match": {"resources": {"kinds": ["Namespace"]}},
+					"generate": {
+						"kind": "Secret",
+						"namespace": "{{request.object.metadata.name}}",
+						"synchronize": true,
+						"cloneList": {"namespace": "default", "selector": {"matchLabels": {"sync": "true"}}}
+					}
+				}
+			]
+		}
+	}`), &policy)
+	assert.NilError(t, err)
+
+	secret := func(namespace string, labels map[string]string) unstructured.Unstructured {
+		resource := unstructured.Unstructured{}
+		resource.SetKind("Secret")
+		resource.SetNamespace(namespace)
+		resource.SetName("regcred")
+		resource.SetLabels(labels)
+		return resource
+	}
+
+	assert.Assert(t, isCloneListSource(&policy, secret("default", map[string]string{"sync": "true"})))
+	assert.Assert(t, !isCloneListSource(&policy, secret("default", nil)))
+	assert.Assert(t, !isCloneListSource(&policy, secret("dev", map[string]string{"sync": "true"})))
+
+	// a source which is no longer selected after the request
+	assert.Assert(t, isCloneListSource(&policy, secret("default", nil), secret("default", map[string]string{"sync": "true"})))
+
+	// a deleted source, the request has no new object
+	assert.Assert(t, isCloneListSource(&policy, unstructured.Unstructured{}, secret("default", map[string]string{"sync": "true"})))
+}
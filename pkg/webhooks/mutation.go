@@ -1,10 +1,12 @@
 package webhooks
 
 import (
+	"fmt"
 	"reflect"
 	"sort"
 	"time"
 
+	"github.com/go-logr/logr"
 	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/common"
@@ -12,6 +14,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/engine/response"
 	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/openapi"
 	v1beta1 "k8s.io/api/admission/v1beta1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -36,13 +39,12 @@ func (ws *WebhookServer) HandleMutation(
 
 	logger := ws.log.WithValues("action", "mutate", "resource", resourceName, "operation", request.Operation)
 
-	var patches [][]byte
-	var engineResponses []*response.EngineResponse
 	policyContext := &engine.PolicyContext{
 		NewResource:         resource,
 		AdmissionInfo:       userRequestInfo,
 		ExcludeGroupRole:    ws.configHandler.GetExcludeGroupRole(),
 		ExcludeResourceFunc: ws.configHandler.ToFilter,
+		ImmutableFieldsFunc: ws.configHandler.GetImmutableFields,
 		ResourceCache:       ws.resCache,
 		JSONContext:         ctx,
 		Client:              ws.client,
@@ -50,35 +52,100 @@ func (ws *WebhookServer) HandleMutation(
 
 	if request.Operation == v1beta1.Update {
 		// set OldResource to inform engine of operation type
-		policyContext.OldResource = resource
+		policyContext.OldResource = oldResource(request, resource, logger)
+	}
+
+	patches, engineResponses := ws.applyMutatePolicies(request, policyContext, sortByPriority(policies, logger), !isDryRun(request), logger)
+
+	// generate annotations
+	if annPatches := generateAnnotationPatches(engineResponses, logger); annPatches != nil {
+		patches = append(patches, annPatches)
+	}
+
+	// REPORTING EVENTS
+	// Scenario 1:
+	//   some/all policies failed to apply on the resource. a policy violation is generated.
+	//   create an event on the resource and the policy that failed
+	// Scenario 2:
+	//   all policies were applied successfully.
+	//   create an event on the resource
+	// ADD EVENTS
+	if !isDryRun(request) {
+		events := generateEvents(engineResponses, policies, false, (request.Operation == v1beta1.Update), logger)
+		ws.eventGen.Add(events...)
+	}
+
+	// debug info
+	func() {
+		if len(patches) != 0 {
+			logger.V(4).Info("JSON patches generated")
+		}
+
+		// if any of the policies fails, print out the error
+		if !isResponseSuccessful(engineResponses) {
+			logger.Info("failed to apply mutation rules on the resource, reporting policy violation", "errors", getErrorMsg(engineResponses))
+		}
+	}()
+
+	// patches holds all the successful patches, if no patch is created, it returns nil
+	return engineutils.JoinPatches(patches)
+}
+
+// applyMutatePolicies applies the policies, in the given order, to the new resource of the policy
+// context and returns the patches of the policies applied and their engine responses. The conflicting
+// patches of the later policies and the patches which produce an invalid resource are discarded.
+// Each policy operates on the resource as mutated by the previous policies, the new resource of the
+// policy context is the mutated resource once the policies are applied. The mutating webhook and the
+// mutation preview share it, the status of the policies is only updated when updateStatus is set.
+func (ws *WebhookServer) applyMutatePolicies(request *v1beta1.AdmissionRequest, policyContext *engine.PolicyContext, policies []*kyverno.ClusterPolicy, updateStatus bool, logger logr.Logger) ([][]byte, []*response.EngineResponse) {
+	var patches [][]byte
+	var engineResponses []*response.EngineResponse
+
+	if request.Kind.Kind != "Namespace" && request.Namespace != "" {
+		policyContext.NamespaceLabels = common.GetNamespaceSelectorsFromNamespaceLister(request.Kind.Kind, request.Namespace, ws.nsLister, logger)
 	}
 
+	owners := patchOwners{}
 	for _, policy := range policies {
 		logger.V(3).Info("evaluating policy", "policy", policy.Name)
 
 		policyContext.Policy = *policy
-		if request.Kind.Kind != "Namespace" && request.Namespace != "" {
-			policyContext.NamespaceLabels = common.GetNamespaceSelectorsFromNamespaceLister(request.Kind.Kind, request.Namespace, ws.nsLister, logger)
-		}
 		engineResponse := engine.Mutate(policyContext)
 		policyPatches := engineResponse.GetPatches()
 
-		if engineResponse.PolicyResponse.RulesAppliedCount > 0 && len(policyPatches) > 0 {
+		if len(policyPatches) > 0 {
+			if conflicts := owners.dropConflicts(engineResponse, logger); len(conflicts) > 0 {
+				logger.Info("mutation conflicts with higher priority policies, skipping the conflicting patches", "policy", policy.Name, "conflicts", conflicts)
+				policyPatches = engineResponse.GetPatches()
+				if err := repatchResource(engineResponse, policyContext.NewResource, policyPatches); err != nil {
+					logger.Error(err, "failed to apply the patches without the conflicting patches", "policy", policy.Name)
+					failMutationRules(engineResponse, policyContext.NewResource, fmt.Sprintf("failed to apply the patches without the conflicting patches: %v", err))
+					policyPatches = nil
+				}
+			}
+		}
+
+		if len(policyPatches) > 0 {
+			if err := ws.validateMutatedResource(engineResponse); err != nil {
+				// the patches would produce an invalid object, skip them and report the rules as failed
+				logger.Info("mutated resource failed schema validation, skipping patches", "policy", policy.Name, "error", err.Error())
+				failMutationRules(engineResponse, policyContext.NewResource, fmt.Sprintf("mutated resource failed schema validation: %v", err))
+				policyPatches = nil
+			}
+		}
+
+		if updateStatus && engineResponse.PolicyResponse.RulesAppliedCount > 0 && len(engineResponse.PolicyResponse.Rules) > 0 {
 			ws.statusListener.Update(mutateStats{resp: engineResponse, namespace: policy.Namespace})
 		}
 
 		if !engineResponse.IsSuccessful() && len(engineResponse.GetFailedRules()) > 0 {
 			logger.Info("failed to apply policy", "policy", policy.Name, "failed rules", engineResponse.GetFailedRules())
-			continue
-		}
-
-		err := ws.openAPIController.ValidateResource(*engineResponse.PatchedResource.DeepCopy(), engineResponse.PatchedResource.GetKind())
-		if err != nil {
-			logger.V(4).Info("validation error", "policy", policy.Name, "error", err.Error())
+			engineResponses = append(engineResponses, engineResponse)
 			continue
 		}
 
 		if len(policyPatches) > 0 {
+			owners.add(engineResponse)
 			patches = append(patches, policyPatches...)
 			rules := engineResponse.GetSuccessRules()
 			logger.Info("mutation rules from policy applied successfully", "policy", policy.Name, "rules", rules)
@@ -86,38 +153,71 @@ func (ws *WebhookServer) HandleMutation(
 
 		policyContext.NewResource = engineResponse.PatchedResource
 		engineResponses = append(engineResponses, engineResponse)
+
+		// the next policies operate on the resource as mutated by the previous policies
+		if len(policyPatches) > 0 {
+			if err := policyContext.JSONContext.ReplaceResource(policyContext.NewResource); err != nil {
+				logger.Error(err, "failed to update the mutated resource in context", "policy", policy.Name)
+			}
+		}
 	}
 
-	// generate annotations
-	if annPatches := generateAnnotationPatches(engineResponses, logger); annPatches != nil {
-		patches = append(patches, annPatches)
+	return patches, engineResponses
+}
+
+// repatchResource sets the patched resource of the engine response to the resource patched by the
+// given patches, once patches of the policy are dropped
+func repatchResource(engineResponse *response.EngineResponse, resource unstructured.Unstructured, patches [][]byte) error {
+	if len(patches) == 0 {
+		engineResponse.PatchedResource = resource
+		return nil
 	}
 
-	// REPORTING EVENTS
-	// Scenario 1:
-	//   some/all policies failed to apply on the resource. a policy violation is generated.
-	//   create an event on the resource and the policy that failed
-	// Scenario 2:
-	//   all policies were applied successfully.
-	//   create an event on the resource
-	// ADD EVENTS
-	events := generateEvents(engineResponses, false, (request.Operation == v1beta1.Update), logger)
-	ws.eventGen.Add(events...)
+	resourceRaw, err := resource.MarshalJSON()
+	if err != nil {
+		return err
+	}
 
-	// debug info
-	func() {
-		if len(patches) != 0 {
-			logger.V(4).Info("JSON patches generated")
-		}
+	patchedRaw, err := engineutils.ApplyPatches(resourceRaw, patches)
+	if err != nil {
+		return err
+	}
 
-		// if any of the policies fails, print out the error
-		if !isResponseSuccessful(engineResponses) {
-			logger.Info("failed to apply mutation rules on the resource, reporting policy violation", "errors", getErrorMsg(engineResponses))
+	var patched unstructured.Unstructured
+	if err := patched.UnmarshalJSON(patchedRaw); err != nil {
+		return err
+	}
+
+	engineResponse.PatchedResource = patched
+	return nil
+}
+
+// validateMutatedResource validates the patched resource against its OpenAPI schema.
+// Kinds without a known schema are not validated.
+func (ws *WebhookServer) validateMutatedResource(engineResponse *response.EngineResponse) error {
+	err := ws.openAPIController.ValidateResource(*engineResponse.PatchedResource.DeepCopy(), engineResponse.PatchedResource.GetKind())
+	if _, ok := err.(*openapi.SchemaNotFound); ok {
+		ws.log.V(4).Info("skip schema validation of mutated resource", "reason", err.Error())
+		return nil
+	}
+
+	return err
+}
+
+// failMutationRules marks the rules that generated patches as failed with the message, discards their
+// patches and resets the patched resource to the resource as it was before the policy was applied
+func failMutationRules(engineResponse *response.EngineResponse, resource unstructured.Unstructured, msg string) {
+	for i, rule := range engineResponse.PolicyResponse.Rules {
+		if !rule.Success || len(rule.Patches) == 0 {
+			continue
 		}
-	}()
 
-	// patches holds all the successful patches, if no patch is created, it returns nil
-	return engineutils.JoinPatches(patches)
+		engineResponse.PolicyResponse.Rules[i].Success = false
+		engineResponse.PolicyResponse.Rules[i].Patches = nil
+		engineResponse.PolicyResponse.Rules[i].Message = msg
+	}
+
+	engineResponse.PatchedResource = resource
 }
 
 type mutateStats struct {
@@ -145,6 +245,7 @@ func (ms mutateStats) UpdateStatus(status kyverno.PolicyStatus) kyverno.PolicySt
 	for _, rule := range ms.resp.PolicyResponse.Rules {
 		ruleStat := nameToRule[rule.Name]
 		ruleStat.Name = rule.Name
+		ruleStat.MatchedCount++
 
 		averageOver := int64(ruleStat.AppliedCount + ruleStat.FailedCount)
 		ruleStat.ExecutionTime = updateAverageTime(
@@ -184,3 +285,23 @@ func (ms mutateStats) UpdateStatus(status kyverno.PolicyStatus) kyverno.PolicySt
 
 	return status
 }
+
+// oldResource returns the state of the resource prior to the update, the new resource is
+// returned if the request has no old object or the old object cannot be converted
+func oldResource(request *v1beta1.AdmissionRequest, resource unstructured.Unstructured, log logr.Logger) unstructured.Unstructured {
+	if request.OldObject.Raw == nil {
+		return resource
+	}
+
+	old, err := convertResource(request.OldObject.Raw, request.Kind.Group, request.Kind.Version, request.Kind.Kind, request.Namespace)
+	if err != nil {
+		log.Error(err, "failed to convert old resource to unstructured format")
+		return resource
+	}
+
+	if isEphemeralContainersRequest(request) {
+		return ephemeralContainersToPod(old)
+	}
+
+	return old
+}
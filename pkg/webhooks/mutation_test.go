@@ -0,0 +1,169 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine"
+	enginectx "github.com/kyverno/kyverno/pkg/engine/context"
+	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/openapi"
+	"gotest.tools/assert"
+	v1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_failMutationRules(t *testing.T) {
+	patchStr := `{ "op": "add", "path": "/spec/containers/0/nonExistantField", "value": "Always" }`
+	engineResponse := newEngineResponse("mutate-container", "add-field", []string{patchStr}, true, nil)
+
+	resource := unstructured.Unstructured{Object: map[string]interface{}{"kind": "Pod"}}
+	failMutationRules(engineResponse, resource, `mutated resource failed schema validation: unknown field "nonExistantField"`)
+
+	assert.Assert(t, !engineResponse.IsSuccessful())
+	assert.Equal(t, len(engineResponse.GetPatches()), 0)
+	assert.Equal(t, engineResponse.PolicyResponse.Rules[0].Message, `mutated resource failed schema validation: unknown field "nonExistantField"`)
+	assert.DeepEqual(t, engineResponse.PatchedResource, resource)
+}
+
+func newMutatePolicy(t *testing.T, name string, patch string) *kyverno.ClusterPolicy {
+	policy := &kyverno.ClusterPolicy{}
+	assert.NilError(t, json.Unmarshal([]byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "`+name+`"},
+		"spec": {
+			"rules": [{
+				"name": "`+name+`",
+				"match": {"resources": {"kinds": ["Pod"]}},
+				"mutate": {"patchStrategicMerge": `+patch+`}
+			}]
+		}
+	}`), policy))
+	return policy
+}
+
+func Test_applyMutatePolicies(t *testing.T) {
+	policies := []*kyverno.ClusterPolicy{
+		newMutatePolicy(t, "add-label", `{"metadata": {"labels": {"app": "{{request.object.metadata.name}}"}}}`),
+		// the policy operates on the resource mutated by the previous policy
+		newMutatePolicy(t, "copy-label", `{"metadata": {"annotations": {"app": "{{request.object.metadata.labels.app}}"}}}`),
+	}
+
+	resourceRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {"name": "nginx"},
+		"spec": {"containers": [{"name": "nginx", "image": "nginx:1.20"}]}
+	}`)
+	resource, err := engineutils.ConvertToUnstructured(resourceRaw)
+	assert.NilError(t, err)
+	ctx := enginectx.NewContext()
+	assert.NilError(t, ctx.AddResource(resourceRaw))
+
+	openAPIController, err := openapi.NewOpenAPIController()
+	assert.NilError(t, err)
+	ws := &WebhookServer{openAPIController: openAPIController, log: log.Log}
+
+	request := &v1beta1.AdmissionRequest{Kind: metav1.GroupVersionKind{Version: "v1", Kind: "Pod"}, Operation: v1beta1.Create}
+	policyContext := &engine.PolicyContext{JSONContext: ctx, NewResource: *resource}
+	patches, engineResponses := ws.applyMutatePolicies(request, policyContext, policies, false, log.Log)
+
+	assert.Equal(t, len(engineResponses), 2)
+	assert.DeepEqual(t, policyContext.NewResource.GetLabels(), map[string]string{"app": "nginx"})
+	assert.DeepEqual(t, policyContext.NewResource.GetAnnotations(), map[string]string{"app": "nginx"})
+
+	patch, err := jsonpatch.DecodePatch(engineutils.JoinPatches(patches))
+	assert.NilError(t, err)
+	patched, err := patch.Apply(resourceRaw)
+	assert.NilError(t, err)
+	patchedRaw, err := policyContext.NewResource.MarshalJSON()
+	assert.NilError(t, err)
+	assert.Assert(t, jsonpatch.Equal(patched, patchedRaw))
+}
+
+func Test_applyMutatePolicies_Conflicts(t *testing.T) {
+	policies := []*kyverno.ClusterPolicy{
+		newMutatePolicy(t, "set-team", `{"metadata": {"labels": {"team": "payments"}}}`),
+		// only the patch of the team label conflicts with the previous policy
+		newMutatePolicy(t, "set-team-tier", `{"metadata": {"labels": {"team": "search", "tier": "backend"}}}`),
+	}
+
+	resourceRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {"name": "nginx", "labels": {"app": "nginx"}},
+		"spec": {"containers": [{"name": "nginx", "image": "nginx:1.20"}]}
+	}`)
+	resource, err := engineutils.ConvertToUnstructured(resourceRaw)
+	assert.NilError(t, err)
+	ctx := enginectx.NewContext()
+	assert.NilError(t, ctx.AddResource(resourceRaw))
+
+	openAPIController, err := openapi.NewOpenAPIController()
+	assert.NilError(t, err)
+	ws := &WebhookServer{openAPIController: openAPIController, log: log.Log}
+
+	request := &v1beta1.AdmissionRequest{Kind: metav1.GroupVersionKind{Version: "v1", Kind: "Pod"}, Operation: v1beta1.Create}
+	policyContext := &engine.PolicyContext{JSONContext: ctx, NewResource: *resource}
+	patches, engineResponses := ws.applyMutatePolicies(request, policyContext, policies, false, log.Log)
+
+	assert.Equal(t, len(engineResponses), 2)
+	assert.Assert(t, engineResponses[1].IsSuccessful())
+	assert.Equal(t, engineResponses[1].PolicyResponse.Rules[0].Message, "mutation conflicts with rule set-team of policy set-team at path /metadata/labels/team")
+	assert.DeepEqual(t, policyContext.NewResource.GetLabels(), map[string]string{"app": "nginx", "team": "payments", "tier": "backend"})
+
+	patch, err := jsonpatch.DecodePatch(engineutils.JoinPatches(patches))
+	assert.NilError(t, err)
+	patched, err := patch.Apply(resourceRaw)
+	assert.NilError(t, err)
+	patchedRaw, err := policyContext.NewResource.MarshalJSON()
+	assert.NilError(t, err)
+	assert.Assert(t, jsonpatch.Equal(patched, patchedRaw))
+}
+
+func Test_oldResource(t *testing.T) {
+	resource, err := engineutils.ConvertToUnstructured([]byte(`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "nginx"}, "spec": {"replicas": 2}}`))
+	assert.NilError(t, err)
+
+	request := &v1beta1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Namespace: "default",
+		Operation: v1beta1.Update,
+		OldObject: runtime.RawExtension{Raw: []byte(`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "nginx"}, "spec": {"replicas": 3}}`)},
+	}
+
+	// the prior state of the resource is used for the update
+	old := oldResource(request, *resource, log.Log)
+	replicas, _, _ := unstructured.NestedInt64(old.Object, "spec", "replicas")
+	assert.Equal(t, replicas, int64(3))
+	assert.Equal(t, old.GetNamespace(), "default")
+
+	// the new resource is used when the old object is missing or invalid
+	request.OldObject.Raw = nil
+	assert.DeepEqual(t, oldResource(request, *resource, log.Log), *resource)
+	request.OldObject.Raw = []byte(`{`)
+	assert.DeepEqual(t, oldResource(request, *resource, log.Log), *resource)
+}
+
+func Test_oldResource_EphemeralContainers(t *testing.T) {
+	request := &v1beta1.AdmissionRequest{
+		Kind:        metav1.GroupVersionKind{Version: "v1", Kind: "EphemeralContainers"},
+		Resource:    metav1.GroupVersionResource{Version: "v1", Resource: "pods"},
+		SubResource: "ephemeralcontainers",
+		Namespace:   "default",
+		Operation:   v1beta1.Update,
+		OldObject:   runtime.RawExtension{Raw: []byte(`{"apiVersion": "v1", "kind": "EphemeralContainers", "metadata": {"name": "nginx"}, "ephemeralContainers": [{"name": "debugger", "image": "busybox"}]}`)},
+	}
+
+	// the old EphemeralContainers object is converted to the pod view of the new resource
+	old := oldResource(request, unstructured.Unstructured{}, log.Log)
+	assert.Equal(t, old.GetKind(), "Pod")
+	containers, _, _ := unstructured.NestedSlice(old.Object, "spec", "ephemeralContainers")
+	assert.Equal(t, len(containers), 1)
+}
@@ -0,0 +1,138 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine"
+	enginectx "github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/policycache"
+	userinfo "github.com/kyverno/kyverno/pkg/userinfo"
+	"github.com/kyverno/kyverno/pkg/utils"
+	v1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// mutatePreviewResponse is the response of the mutation preview
+type mutatePreviewResponse struct {
+	// Resource is the complete resource after all mutations are applied
+	Resource *unstructured.Unstructured `json:"resource"`
+	// Patch is the JSON patch between the requested and the mutated resource
+	Patch json.RawMessage `json:"patch,omitempty"`
+	// PolicyResponses lists the results of the mutate policies applied
+	PolicyResponses []response.PolicyResponse `json:"policyResponses,omitempty"`
+}
+
+// mutatePreview applies the mutate policies to the object of the admission review and
+// returns the mutated resource, no patches are applied and no events or reports are generated.
+// The requests are authenticated with a bearer token, the policies are applied for the user of
+// the token and the user info of the admission review is ignored.
+func (ws *WebhookServer) mutatePreview(rw http.ResponseWriter, r *http.Request) {
+	user, status, err := ws.authenticate(r)
+	if err != nil {
+		ws.log.WithName("MutatePreview").V(3).Info("unauthorized request", "reason", err.Error())
+		http.Error(rw, err.Error(), status)
+		return
+	}
+
+	admissionReview := ws.bodyToAdmissionReview(r, rw)
+	if admissionReview == nil {
+		return
+	}
+
+	request := admissionReview.Request
+	if request == nil {
+		http.Error(rw, "admission review has no request", http.StatusBadRequest)
+		return
+	}
+	request.UserInfo = user
+
+	logger := ws.log.WithName("MutatePreview").WithValues("uid", request.UID, "kind", request.Kind.Kind, "namespace", request.Namespace, "name", request.Name, "operation", request.Operation)
+
+	resource, err := utils.ConvertResource(request.Object.Raw, request.Kind.Group, request.Kind.Version, request.Kind.Kind, request.Namespace)
+	if err != nil {
+		logger.Error(err, "failed to convert RAW resource to unstructured format")
+		http.Error(rw, fmt.Sprintf("failed to convert resource: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	mutatePolicies := ws.pCache.Get(policycache.Mutate, nil)
+	mutatePolicies = append(mutatePolicies, ws.pCache.Get(policycache.Mutate, &request.Namespace)...)
+
+	var roles, clusterRoles []string
+	if containRBACInfo(mutatePolicies) {
+		roles, clusterRoles, err = userinfo.GetRoleRef(ws.rbLister, ws.crbLister, request, ws.configHandler)
+		if err != nil {
+			logger.Error(err, "failed to get RBAC information for request")
+		}
+	}
+
+	userRequestInfo := v1.RequestInfo{
+		Roles:             roles,
+		ClusterRoles:      clusterRoles,
+		AdmissionUserInfo: *request.UserInfo.DeepCopy()}
+
+	ctx := enginectx.NewContext()
+	if err := ctx.AddRequest(request); err != nil {
+		logger.Error(err, "failed to load incoming request in context")
+	}
+	if err := ctx.AddUserInfo(userRequestInfo); err != nil {
+		logger.Error(err, "failed to load userInfo in context")
+	}
+	if err := ctx.AddServiceAccount(userRequestInfo.AdmissionUserInfo.Username); err != nil {
+		logger.Error(err, "failed to load service account in context")
+	}
+	addNamespaceMetadata(ctx, request, ws.nsLister, logger)
+
+	policyContext := &engine.PolicyContext{
+		NewResource:         resource,
+		AdmissionInfo:       userRequestInfo,
+		ExcludeGroupRole:    ws.configHandler.GetExcludeGroupRole(),
+		ExcludeResourceFunc: ws.configHandler.ToFilter,
+		ImmutableFieldsFunc: ws.configHandler.GetImmutableFields,
+		ResourceCache:       ws.resCache,
+		JSONContext:         ctx,
+		Client:              ws.client,
+	}
+
+	if request.Operation == v1beta1.Update {
+		policyContext.OldResource = oldResource(request, resource, logger)
+	}
+
+	// the policies are applied as in the mutating webhook, without updating their status
+	patches, engineResponses := ws.applyMutatePolicies(request, policyContext, sortByPriority(mutatePolicies, logger), false, logger)
+	writePreviewResponse(rw, buildPreviewResponse(policyContext.NewResource, engineutils.JoinPatches(patches), engineResponses))
+}
+
+func buildPreviewResponse(resource unstructured.Unstructured, patches []byte, engineResponses []*response.EngineResponse) *mutatePreviewResponse {
+	previewResponse := &mutatePreviewResponse{
+		Resource: &resource,
+		Patch:    patches,
+	}
+
+	for _, engineResponse := range engineResponses {
+		if len(engineResponse.PolicyResponse.Rules) == 0 {
+			continue
+		}
+		previewResponse.PolicyResponses = append(previewResponse.PolicyResponses, engineResponse.PolicyResponse)
+	}
+
+	return previewResponse
+}
+
+func writePreviewResponse(rw http.ResponseWriter, previewResponse *mutatePreviewResponse) {
+	responseJSON, err := json.Marshal(previewResponse)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("Could not encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if _, err := rw.Write(responseJSON); err != nil {
+		http.Error(rw, fmt.Sprintf("could not write response: %v", err), http.StatusInternalServerError)
+	}
+}
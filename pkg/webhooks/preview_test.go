@@ -0,0 +1,53 @@
+package webhooks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/kyverno/pkg/config"
+	dclient "github.com/kyverno/kyverno/pkg/dclient"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_mutatePreview_Unauthorized(t *testing.T) {
+	client, err := dclient.NewMockClient(runtime.NewScheme(), nil)
+	assert.NilError(t, err)
+	client.SetDiscovery(dclient.NewFakeDiscoveryClient([]schema.GroupVersionResource{{Group: "authentication.k8s.io", Version: "v1", Resource: "tokenreviews"}}))
+	ws := &WebhookServer{client: client, log: log.Log}
+
+	body := `{"request": {"uid": "1", "kind": {"version": "v1", "kind": "Pod"}, "operation": "CREATE", "userInfo": {"username": "system:admin", "groups": ["system:masters"]}, "object": {"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "nginx"}}}}`
+	testcases := []struct {
+		description   string
+		authorization string
+		message       string
+	}{
+		{
+			description: "request without a token",
+			message:     "a bearer token is required",
+		},
+		{
+			description:   "request with a token which is not authenticated",
+			authorization: "Bearer invalid",
+			message:       "invalid token",
+		},
+	}
+
+	for _, testcase := range testcases {
+		r := httptest.NewRequest(http.MethodPost, config.MutatePreviewServicePath, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		if testcase.authorization != "" {
+			r.Header.Set("Authorization", testcase.authorization)
+		}
+
+		// the user info of the admission review is not trusted
+		rw := httptest.NewRecorder()
+		ws.mutatePreview(rw, r)
+		assert.Equal(t, rw.Code, http.StatusUnauthorized, testcase.description)
+		assert.Equal(t, strings.TrimSpace(rw.Body.String()), testcase.message, testcase.description)
+	}
+}
@@ -1,31 +1,79 @@
 package webhooks
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/go-logr/logr"
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/common"
 	"github.com/kyverno/kyverno/pkg/engine/response"
+	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
 
 	"github.com/kyverno/kyverno/pkg/event"
 )
 
 //generateEvents generates event info for the engine responses
-func generateEvents(engineResponses []*response.EngineResponse, blocked, onUpdate bool, log logr.Logger) []event.Info {
+func generateEvents(engineResponses []*response.EngineResponse, policies []*kyverno.ClusterPolicy, blocked, onUpdate bool, log logr.Logger) []event.Info {
 	var events []event.Info
 
 	// - Admission-Response is SUCCESS
 	//   - Some/All policies failed (policy violations generated)
 	//     - report event on resource that failed
+	//     - report event on policy that failed
+	//   - Some/All policies mutated the resource
+	//     - report event on resource that was mutated
+	//     - report event on policy that mutated the resource
+	// - Admission-Response is FAILURE (request blocked)
+	//   - report event on policy that blocked the request
+
+	suppressed := map[string]bool{}
+	for _, policy := range policies {
+		if !policy.EventsEnabled() {
+			suppressed[policy.Namespace+"/"+policy.Name] = true
+		}
+	}
 
 	for _, er := range engineResponses {
+		// the policy reports are still generated for the policies with suppressed events
+		namespace := er.PolicyResponse.Namespace
+		if suppressed[namespace+"/"+er.PolicyResponse.Policy] {
+			continue
+		}
+
 		if er.IsSuccessful() {
+			if len(er.GetPatches()) > 0 {
+				events = append(events, appliedEvents(er, namespace, log)...)
+			}
 			// do not create event on rules that were successful
 			continue
 		}
-		// Rules that failed
-		failedRules := er.GetFailedRules()
+		// Rules that failed, with their severity
+		// the failed validation rules are violations, the other failed rules are errors
+		var failedRules []string
+		reason := event.PolicyError
+		for _, rule := range er.PolicyResponse.Rules {
+			if rule.Success {
+				continue
+			}
+
+			if rule.Type == engineutils.Validation.String() {
+				reason = event.PolicyViolation
+			}
+
+			if rule.Severity != "" {
+				failedRules = append(failedRules, fmt.Sprintf("%s (severity %s)", rule.Name, rule.Severity))
+			} else {
+				failedRules = append(failedRules, rule.Name)
+			}
+		}
 		filedRulesStr := strings.Join(failedRules, ";")
 
+		action := ""
+		if blocked && er.PolicyResponse.ValidationFailureAction == common.Enforce {
+			action = event.ActionBlock
+		}
+
 		// Event on the resource
 		// event on resource
 		e := event.NewEvent(
@@ -34,14 +82,102 @@ func generateEvents(engineResponses []*response.EngineResponse, blocked, onUpdat
 			er.PolicyResponse.Resource.APIVersion,
 			er.PolicyResponse.Resource.Namespace,
 			er.PolicyResponse.Resource.Name,
-			event.PolicyViolation.String(),
+			reason.String(),
 			event.AdmissionController,
 			event.FResourcePolicyFailed,
 			filedRulesStr,
 			er.PolicyResponse.Policy,
 		)
+		e.Action = action
+		e.Related = policyObject(er.PolicyResponse.Policy, namespace)
+		events = append(events, e)
+
+		// Event on the policy
+		message := event.FPolicyApplyFailed
+		args := []interface{}{filedRulesStr, resourceKey(er.PolicyResponse.Resource)}
+		if action == event.ActionBlock {
+			message = event.FPolicyApplyBlockCreate
+			if onUpdate {
+				message = event.FPolicyBlockResourceUpdate
+			}
+			args = []interface{}{resourceKey(er.PolicyResponse.Resource), filedRulesStr}
+		}
+
+		e = event.NewEvent(
+			log,
+			policyKind(namespace),
+			"",
+			namespace,
+			er.PolicyResponse.Policy,
+			reason.String(),
+			event.AdmissionController,
+			message,
+			args...,
+		)
+		e.Action = action
+		e.Related = resourceObject(er.PolicyResponse.Resource)
 		events = append(events, e)
 	}
 
 	return events
 }
+
+// appliedEvents generates the events on the resource mutated by the policy and on the policy
+func appliedEvents(er *response.EngineResponse, policyNamespace string, log logr.Logger) []event.Info {
+	rules := strings.Join(er.GetSuccessRules(), ";")
+
+	resourceEvent := event.NewEvent(
+		log,
+		er.PolicyResponse.Resource.Kind,
+		er.PolicyResponse.Resource.APIVersion,
+		er.PolicyResponse.Resource.Namespace,
+		er.PolicyResponse.Resource.Name,
+		event.PolicyApplied.String(),
+		event.AdmissionController,
+		event.FResourcePolicyApplied,
+		rules,
+		er.PolicyResponse.Policy,
+	)
+	resourceEvent.Action = event.ActionMutate
+	resourceEvent.Related = policyObject(er.PolicyResponse.Policy, policyNamespace)
+
+	policyEvent := event.NewEvent(
+		log,
+		policyKind(policyNamespace),
+		"",
+		policyNamespace,
+		er.PolicyResponse.Policy,
+		event.PolicyApplied.String(),
+		event.AdmissionController,
+		event.FPolicyApplied,
+		rules,
+		resourceKey(er.PolicyResponse.Resource),
+	)
+	policyEvent.Action = event.ActionMutate
+	policyEvent.Related = resourceObject(er.PolicyResponse.Resource)
+
+	return []event.Info{resourceEvent, policyEvent}
+}
+
+// policyKind returns the kind of the policy, the namespaced policies have a namespace
+func policyKind(namespace string) string {
+	if namespace == "" {
+		return "ClusterPolicy"
+	}
+	return "Policy"
+}
+
+func policyObject(name, namespace string) event.Object {
+	return event.Object{APIVersion: kyverno.SchemeGroupVersion.String(), Kind: policyKind(namespace), Namespace: namespace, Name: name}
+}
+
+func resourceObject(resource response.ResourceSpec) event.Object {
+	return event.Object{APIVersion: resource.APIVersion, Kind: resource.Kind, Namespace: resource.Namespace, Name: resource.Name}
+}
+
+func resourceKey(resource response.ResourceSpec) string {
+	if resource.Namespace == "" {
+		return resource.Kind + "/" + resource.Name
+	}
+	return resource.Kind + "/" + resource.Namespace + "/" + resource.Name
+}
@@ -0,0 +1,113 @@
+package webhooks
+
+import (
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/response"
+	"github.com/kyverno/kyverno/pkg/event"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func Test_GenerateEvents(t *testing.T) {
+	policies := []*kyverno.ClusterPolicy{
+		{ObjectMeta: metav1.ObjectMeta{Name: "require-labels"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "add-labels", Namespace: "default"}},
+	}
+
+	resource := response.ResourceSpec{Kind: "Pod", APIVersion: "v1", Namespace: "default", Name: "nginx"}
+	engineResponses := []*response.EngineResponse{
+		{
+			PolicyResponse: response.PolicyResponse{
+				Policy:                  "require-labels",
+				Resource:                resource,
+				ValidationFailureAction: "enforce",
+				Rules:                   []response.RuleResponse{{Name: "check-team", Type: "Validation", Success: false}},
+			},
+		},
+		{
+			PolicyResponse: response.PolicyResponse{
+				Policy:    "add-labels",
+				Namespace: "default",
+				Resource:  resource,
+				Rules:     []response.RuleResponse{{Name: "add-team", Success: true, Patches: [][]byte{[]byte(`{"op":"add","path":"/metadata/labels/team","value":"a"}`)}}},
+			},
+		},
+	}
+
+	events := generateEvents(engineResponses, policies, true, false, log.Log)
+	assert.Equal(t, len(events), 4)
+
+	assert.Equal(t, events[0].Kind, "Pod")
+	assert.Equal(t, events[0].Reason, event.PolicyViolation.String())
+	assert.Equal(t, events[0].Message, "Rule(s) 'check-team' of policy 'require-labels' failed to apply on the resource")
+	assert.Equal(t, events[0].Action, event.ActionBlock)
+	assert.Equal(t, events[0].Related, event.Object{APIVersion: "kyverno.io/v1", Kind: "ClusterPolicy", Name: "require-labels"})
+
+	assert.Equal(t, events[1].Kind, "ClusterPolicy")
+	assert.Equal(t, events[1].Name, "require-labels")
+	assert.Equal(t, events[1].Reason, event.PolicyViolation.String())
+	assert.Equal(t, events[1].Message, "Resource Pod/default/nginx creation blocked by rule(s) check-team")
+	assert.Equal(t, events[1].Action, event.ActionBlock)
+	assert.Equal(t, events[1].Related, event.Object{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "nginx"})
+
+	assert.Equal(t, events[2].Kind, "Pod")
+	assert.Equal(t, events[2].Reason, event.PolicyApplied.String())
+	assert.Equal(t, events[2].Message, "Rule(s) 'add-team' of policy 'add-labels' applied on the resource")
+	assert.Equal(t, events[2].Action, event.ActionMutate)
+	assert.Equal(t, events[2].Related, event.Object{APIVersion: "kyverno.io/v1", Kind: "Policy", Namespace: "default", Name: "add-labels"})
+
+	assert.Equal(t, events[3].Kind, "Policy")
+	assert.Equal(t, events[3].Namespace, "default")
+	assert.Equal(t, events[3].Name, "add-labels")
+	assert.Equal(t, events[3].Message, "Rule(s) 'add-team' applied on resource Pod/default/nginx")
+
+	// the violations of the policies in audit mode are reported on the policy
+	engineResponses[0].PolicyResponse.ValidationFailureAction = "audit"
+	events = generateEvents(engineResponses[:1], policies, false, false, log.Log)
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[1].Reason, event.PolicyViolation.String())
+	assert.Equal(t, events[1].Message, "Rule(s) 'check-team' failed to apply on resource Pod/default/nginx")
+	assert.Equal(t, events[1].Action, "")
+
+	// the failed mutation rules are errors
+	engineResponses[1].PolicyResponse.Rules[0].Success = false
+	engineResponses[1].PolicyResponse.Rules[0].Type = "Mutation"
+	events = generateEvents(engineResponses[1:], policies, false, false, log.Log)
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[0].Reason, event.PolicyError.String())
+	assert.Equal(t, events[1].Reason, event.PolicyError.String())
+}
+
+func Test_GenerateEvents_SuppressedEvents(t *testing.T) {
+	policies := []*kyverno.ClusterPolicy{
+		{ObjectMeta: metav1.ObjectMeta{Name: "require-labels"}, Spec: kyverno.Spec{SuppressEvents: true}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "require-team", Annotations: map[string]string{"policies.kyverno.io/events": "disabled"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "require-owner"}},
+		// the namespaced policy of the same name as a cluster policy with suppressed events
+		{ObjectMeta: metav1.ObjectMeta{Name: "require-labels", Namespace: "default"}},
+	}
+
+	resource := response.ResourceSpec{Kind: "Pod", APIVersion: "v1", Namespace: "default", Name: "nginx"}
+	var engineResponses []*response.EngineResponse
+	for _, policy := range policies {
+		engineResponses = append(engineResponses, &response.EngineResponse{
+			PolicyResponse: response.PolicyResponse{
+				Policy:    policy.Name,
+				Namespace: policy.Namespace,
+				Resource:  resource,
+				Rules:     []response.RuleResponse{{Name: "check", Type: "Validation", Success: false}},
+			},
+		})
+	}
+
+	events := generateEvents(engineResponses, policies, false, false, log.Log)
+	assert.Equal(t, len(events), 4)
+	assert.Equal(t, events[0].Related.Name, "require-owner")
+	assert.Equal(t, events[1].Name, "require-owner")
+	assert.Equal(t, events[2].Related, event.Object{APIVersion: "kyverno.io/v1", Kind: "Policy", Namespace: "default", Name: "require-labels"})
+	assert.Equal(t, events[3].Kind, "Policy")
+	assert.Equal(t, events[3].Namespace, "default")
+}
@@ -0,0 +1,222 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"github.com/kyverno/kyverno/pkg/policyreport"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// reportResult is a result of a policy report, with the namespace of the report
+type reportResult struct {
+	// Namespace of the policy report, empty for the cluster policy report
+	Namespace string `json:"namespace,omitempty"`
+
+	*report.PolicyReportResult
+}
+
+// reportResultsResponse is the response of the reports API
+type reportResultsResponse struct {
+	Results []reportResult `json:"results"`
+}
+
+// reportResultsFilter filters the results by the query parameters of the request,
+// the empty parameters match all results
+type reportResultsFilter struct {
+	namespace string
+	policy    string
+	severity  string
+	status    string
+	since     time.Time
+}
+
+func (f reportResultsFilter) matches(result *report.PolicyReportResult) bool {
+	if f.policy != "" && result.Policy != f.policy {
+		return false
+	}
+
+	if f.severity != "" && string(result.Severity) != f.severity {
+		return false
+	}
+
+	if f.status != "" && string(result.Status) != f.status {
+		return false
+	}
+
+	if !f.since.IsZero() {
+		reportedAt, ok := policyreport.ReportedAt(result)
+		if !ok || reportedAt.Before(f.since) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reportResults lists the results of the policy reports. The requests are authenticated with
+// a bearer token and the user must be allowed to list the policy reports of the namespace, or
+// the policy reports of all namespaces and the cluster policy reports when no namespace is set.
+func (ws *WebhookServer) reportResults(rw http.ResponseWriter, r *http.Request) {
+	logger := ws.log.WithName("ReportResults")
+	query := r.URL.Query()
+	filter := reportResultsFilter{
+		namespace: query.Get("namespace"),
+		policy:    query.Get("policy"),
+		severity:  query.Get("severity"),
+		status:    query.Get("status"),
+	}
+
+	if since := query.Get("since"); since != "" {
+		timestamp, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("invalid since %s, expected an RFC 3339 timestamp: %v", since, err), http.StatusBadRequest)
+			return
+		}
+		filter.since = timestamp
+	}
+
+	if status, err := ws.authorizeReportResults(r, filter.namespace); err != nil {
+		logger.V(3).Info("unauthorized request", "namespace", filter.namespace, "reason", err.Error())
+		http.Error(rw, err.Error(), status)
+		return
+	}
+
+	results, err := ws.listReportResults(filter)
+	if err != nil {
+		logger.Error(err, "failed to list policy reports")
+		http.Error(rw, fmt.Sprintf("failed to list policy reports: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	responseJSON, err := json.Marshal(reportResultsResponse{Results: results})
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("Could not encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if _, err := rw.Write(responseJSON); err != nil {
+		http.Error(rw, fmt.Sprintf("could not write response: %v", err), http.StatusInternalServerError)
+	}
+}
+
+func (ws *WebhookServer) listReportResults(filter reportResultsFilter) ([]reportResult, error) {
+	results := []reportResult{}
+	if filter.namespace == "" {
+		cpolrs, err := ws.cpolrLister.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cpolr := range cpolrs {
+			results = appendReportResults(results, "", cpolr.Results, filter)
+		}
+	}
+
+	polrs, err := ws.polrLister.PolicyReports(filter.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(polrs, func(i, j int) bool { return polrs[i].Namespace < polrs[j].Namespace })
+	for _, polr := range polrs {
+		results = appendReportResults(results, polr.Namespace, polr.Results, filter)
+	}
+
+	return results, nil
+}
+
+func appendReportResults(results []reportResult, namespace string, reportResults []*report.PolicyReportResult, filter reportResultsFilter) []reportResult {
+	for _, result := range reportResults {
+		if filter.matches(result) {
+			results = append(results, reportResult{Namespace: namespace, PolicyReportResult: result})
+		}
+	}
+
+	return results
+}
+
+// authorizeReportResults authenticates the request and checks with SubjectAccessReviews that the
+// user can list the queried reports, it returns the HTTP status on failures
+func (ws *WebhookServer) authorizeReportResults(r *http.Request, namespace string) (int, error) {
+	user, status, err := ws.authenticate(r)
+	if err != nil {
+		return status, err
+	}
+
+	resources := []string{"policyreports"}
+	if namespace == "" {
+		resources = append(resources, "clusterpolicyreports")
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	for _, resource := range resources {
+		sar := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   user.Username,
+				Groups: user.Groups,
+				UID:    user.UID,
+				Extra:  extra,
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      "list",
+					Group:     report.SchemeGroupVersion.Group,
+					Resource:  resource,
+				},
+			},
+		}
+
+		obj, err := ws.client.CreateResource("", "SubjectAccessReview", "", sar, false)
+		if err != nil {
+			return http.StatusInternalServerError, fmt.Errorf("failed to review the access: %v", err)
+		}
+
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, sar); err != nil {
+			return http.StatusInternalServerError, fmt.Errorf("failed to convert the access review: %v", err)
+		}
+
+		if !sar.Status.Allowed {
+			return http.StatusForbidden, fmt.Errorf("user %s cannot list %s in the namespace %q", user.Username, resource, namespace)
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// authenticate reviews the bearer token of the request with a TokenReview and returns the
+// authenticated user, it returns the HTTP status on failures
+func (ws *WebhookServer) authenticate(r *http.Request) (authenticationv1.UserInfo, int, error) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		return authenticationv1.UserInfo{}, http.StatusUnauthorized, fmt.Errorf("a bearer token is required")
+	}
+
+	tokenReview := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	obj, err := ws.client.CreateResource("", "TokenReview", "", tokenReview, false)
+	if err != nil {
+		return authenticationv1.UserInfo{}, http.StatusInternalServerError, fmt.Errorf("failed to review the token: %v", err)
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, tokenReview); err != nil {
+		return authenticationv1.UserInfo{}, http.StatusInternalServerError, fmt.Errorf("failed to convert the token review: %v", err)
+	}
+
+	if !tokenReview.Status.Authenticated {
+		return authenticationv1.UserInfo{}, http.StatusUnauthorized, fmt.Errorf("invalid token")
+	}
+
+	return tokenReview.Status.User, http.StatusOK, nil
+}
@@ -0,0 +1,52 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	report "github.com/kyverno/kyverno/pkg/api/policyreport/v1alpha1"
+	"gotest.tools/assert"
+)
+
+func Test_ReportResultsFilter(t *testing.T) {
+	results := []*report.PolicyReportResult{
+		{Policy: "require-labels", Rule: "require-team", Severity: "high", Status: "fail", Data: map[string]string{"reportedAt": "2021-04-01T10:00:00Z"}},
+		{Policy: "require-labels", Rule: "require-app", Severity: "low", Status: "pass", Data: map[string]string{"reportedAt": "2021-04-02T10:00:00Z"}},
+		{Policy: "disallow-latest-tag", Rule: "validate-image-tag", Status: "fail"},
+	}
+
+	testcases := []struct {
+		description string
+		filter      reportResultsFilter
+		expected    []string
+	}{
+		{
+			description: "no filter",
+			expected:    []string{"require-team", "require-app", "validate-image-tag"},
+		},
+		{
+			description: "policy",
+			filter:      reportResultsFilter{policy: "require-labels"},
+			expected:    []string{"require-team", "require-app"},
+		},
+		{
+			description: "severity and status",
+			filter:      reportResultsFilter{severity: "high", status: "fail"},
+			expected:    []string{"require-team"},
+		},
+		{
+			description: "since excludes the older results and the results without timestamp",
+			filter:      reportResultsFilter{since: time.Date(2021, 4, 2, 0, 0, 0, 0, time.UTC)},
+			expected:    []string{"require-app"},
+		},
+	}
+
+	for _, testcase := range testcases {
+		var rules []string
+		for _, result := range appendReportResults(nil, "default", results, testcase.filter) {
+			assert.Equal(t, result.Namespace, "default", testcase.description)
+			rules = append(rules, result.Rule)
+		}
+		assert.DeepEqual(t, rules, testcase.expected)
+	}
+}
@@ -2,7 +2,6 @@ package webhooks
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,15 +12,18 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/julienschmidt/httprouter"
 	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/capture"
 	kyvernoclient "github.com/kyverno/kyverno/pkg/client/clientset/versioned"
 	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1"
+	policyreportinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions/policyreport/v1alpha1"
 	kyvernolister "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
-	"github.com/kyverno/kyverno/pkg/common"
+	policyreportlister "github.com/kyverno/kyverno/pkg/client/listers/policyreport/v1alpha1"
 	"github.com/kyverno/kyverno/pkg/config"
 	client "github.com/kyverno/kyverno/pkg/dclient"
 	enginectx "github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/event"
 	"github.com/kyverno/kyverno/pkg/generate"
+	"github.com/kyverno/kyverno/pkg/mutateexisting"
 	"github.com/kyverno/kyverno/pkg/openapi"
 	"github.com/kyverno/kyverno/pkg/policycache"
 	"github.com/kyverno/kyverno/pkg/policyreport"
@@ -115,6 +117,12 @@ type WebhookServer struct {
 	// nsListerSynced returns true if the namespace store has been synced at least once
 	nsListerSynced cache.InformerSynced
 
+	// list the policy reports queried by the reports API
+	polrLister  policyreportlister.PolicyReportLister
+	cpolrLister policyreportlister.ClusterPolicyReportLister
+	polrSynced  cache.InformerSynced
+	cpolrSynced cache.InformerSynced
+
 	auditHandler AuditHandler
 
 	log logr.Logger
@@ -128,6 +136,12 @@ type WebhookServer struct {
 
 	grController *generate.Controller
 
+	// mutateExisting applies mutate rules with targets in background
+	mutateExisting *mutateexisting.Controller
+
+	// capturer captures a sample of the resource admission requests, nil if the capture is disabled
+	capturer *capture.Capturer
+
 	debug bool
 }
 
@@ -136,7 +150,8 @@ type WebhookServer struct {
 func NewWebhookServer(
 	kyvernoClient *kyvernoclient.Clientset,
 	client *client.Client,
-	tlsPair *tlsutils.PemPair,
+	certificates *tlsutils.CertificateHolder,
+	tlsOptions tlsutils.ServerOptions,
 	grInformer kyvernoinformer.GenerateRequestInformer,
 	pInformer kyvernoinformer.ClusterPolicyInformer,
 	rbInformer rbacinformer.RoleBindingInformer,
@@ -144,6 +159,8 @@ func NewWebhookServer(
 	rInformer rbacinformer.RoleInformer,
 	crInformer rbacinformer.ClusterRoleInformer,
 	namespace informers.NamespaceInformer,
+	polrInformer policyreportinformer.PolicyReportInformer,
+	cpolrInformer policyreportinformer.ClusterPolicyReportInformer,
 	eventGen event.Interface,
 	pCache policycache.Interface,
 	webhookRegistrationClient *webhookconfig.Register,
@@ -159,20 +176,15 @@ func NewWebhookServer(
 	openAPIController *openapi.Controller,
 	resCache resourcecache.ResourceCache,
 	grc *generate.Controller,
+	mutateExisting *mutateexisting.Controller,
+	capturer *capture.Capturer,
 	debug bool,
 ) (*WebhookServer, error) {
 
-	if tlsPair == nil {
+	if certificates == nil {
 		return nil, errors.New("NewWebhookServer is not initialized properly")
 	}
 
-	var tlsConfig tls.Config
-	pair, err := tls.X509KeyPair(tlsPair.Certificate, tlsPair.PrivateKey)
-	if err != nil {
-		return nil, err
-	}
-	tlsConfig.Certificates = []tls.Certificate{pair}
-
 	ws := &WebhookServer{
 		client:         client,
 		kyvernoClient:  kyvernoClient,
@@ -186,6 +198,10 @@ func NewWebhookServer(
 		rSynced:        rInformer.Informer().HasSynced,
 		nsLister:       namespace.Lister(),
 		nsListerSynced: namespace.Informer().HasSynced,
+		polrLister:     polrInformer.Lister(),
+		cpolrLister:    cpolrInformer.Lister(),
+		polrSynced:     polrInformer.Informer().HasSynced,
+		cpolrSynced:    cpolrInformer.Informer().HasSynced,
 
 		crbLister:             crbInformer.Lister(),
 		crLister:              crInformer.Lister(),
@@ -206,15 +222,19 @@ func NewWebhookServer(
 		openAPIController:     openAPIController,
 		supportMutateValidate: supportMutateValidate,
 		resCache:              resCache,
+		mutateExisting:        mutateExisting,
+		capturer:              capturer,
 		debug:                 debug,
 	}
 
 	mux := httprouter.New()
-	mux.HandlerFunc("POST", config.MutatingWebhookServicePath, ws.handlerFunc(ws.ResourceMutation, true))
-	mux.HandlerFunc("POST", config.ValidatingWebhookServicePath, ws.handlerFunc(ws.resourceValidation, true))
+	mux.HandlerFunc("POST", config.MutatingWebhookServicePath, ws.handlerFunc(ws.captureRequests(capture.MutateWebhook, ws.ResourceMutation), true))
+	mux.HandlerFunc("POST", config.ValidatingWebhookServicePath, ws.handlerFunc(ws.captureRequests(capture.ValidateWebhook, ws.triggerMutateExisting(ws.resourceValidation)), true))
 	mux.HandlerFunc("POST", config.PolicyMutatingWebhookServicePath, ws.handlerFunc(ws.policyMutation, true))
 	mux.HandlerFunc("POST", config.PolicyValidatingWebhookServicePath, ws.handlerFunc(ws.policyValidation, true))
 	mux.HandlerFunc("POST", config.VerifyMutatingWebhookServicePath, ws.handlerFunc(ws.verifyHandler, false))
+	mux.HandlerFunc("POST", config.MutatePreviewServicePath, ws.mutatePreview)
+	mux.HandlerFunc("GET", config.ReportResultsServicePath, ws.reportResults)
 
 	// Handle Liveness responds to a Kubernetes Liveness probe
 	// Fail this request if Kubernetes should restart this instance
@@ -233,7 +253,7 @@ func NewWebhookServer(
 
 	ws.server = &http.Server{
 		Addr:         ":9443", // Listen on port for HTTPS requests
-		TLSConfig:    &tlsConfig,
+		TLSConfig:    tlsutils.ServerConfig(certificates, tlsOptions),
 		Handler:      mux,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
@@ -276,6 +296,51 @@ func (ws *WebhookServer) handlerFunc(handler func(request *v1beta1.AdmissionRequ
 	}
 }
 
+// captureRequests captures the admission requests of the handler of the webhook before they are
+// handled, the requests of the mutating webhook are captured before their mutation and the requests
+// of the validating webhook with all their mutations
+func (ws *WebhookServer) captureRequests(webhook string, handler func(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse) func(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+	if ws.capturer == nil {
+		return handler
+	}
+
+	return func(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+		ws.capturer.Capture(webhook, request)
+		return handler(request)
+	}
+}
+
+// triggerMutateExisting adds the resources of the requests allowed by the handler as triggers of the
+// mutate existing rules. The validating webhook sees the resources with all their mutations, the
+// controller processes a trigger once the admitted resource is persisted.
+func (ws *WebhookServer) triggerMutateExisting(handler func(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse) func(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+	return func(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
+		response := handler(request)
+		if !response.Allowed || isDryRun(request) || excludeKyvernoResources(request.Kind.Kind) {
+			return response
+		}
+
+		if request.Operation != v1beta1.Create && request.Operation != v1beta1.Update {
+			return response
+		}
+
+		resource, err := utils.ConvertResource(request.Object.Raw, request.Kind.Group, request.Kind.Version, request.Kind.Kind, request.Namespace)
+		if err != nil {
+			ws.log.Error(err, "failed to convert RAW resource to unstructured format", "kind", request.Kind.Kind, "namespace", request.Namespace, "name", request.Name)
+			return response
+		}
+
+		if isEphemeralContainersRequest(request) {
+			resource = ephemeralContainersToPod(resource)
+		}
+
+		policies := ws.pCache.Get(policycache.Mutate, nil)
+		policies = append(policies, ws.pCache.Get(policycache.Mutate, &request.Namespace)...)
+		ws.mutateExisting.AddTrigger(policies, resource, request.Operation == v1beta1.Update)
+		return response
+	}
+}
+
 func writeResponse(rw http.ResponseWriter, admissionReview *v1beta1.AdmissionReview) {
 	responseJSON, err := json.Marshal(admissionReview)
 	if err != nil {
@@ -334,6 +399,12 @@ func (ws *WebhookServer) ResourceMutation(request *v1beta1.AdmissionRequest) *v1
 		}
 	}
 
+	// pod policies are applied to the ephemeral containers of the EphemeralContainers object
+	ephemeralContainers := isEphemeralContainersRequest(request)
+	if ephemeralContainers {
+		resource = ephemeralContainersToPod(resource)
+	}
+
 	userRequestInfo := v1.RequestInfo{
 		Roles:             roles,
 		ClusterRoles:      clusterRoles,
@@ -345,6 +416,11 @@ func (ws *WebhookServer) ResourceMutation(request *v1beta1.AdmissionRequest) *v1
 	if err != nil {
 		logger.Error(err, "failed to load incoming request in context")
 	}
+	if ephemeralContainers {
+		if err := ctx.ReplaceResource(resource); err != nil {
+			logger.Error(err, "failed to load ephemeral containers in context")
+		}
+	}
 
 	err = ctx.AddUserInfo(userRequestInfo)
 	if err != nil {
@@ -354,6 +430,7 @@ func (ws *WebhookServer) ResourceMutation(request *v1beta1.AdmissionRequest) *v1
 	if err != nil {
 		logger.Error(err, "failed to load service account in context")
 	}
+	addNamespaceMetadata(ctx, request, ws.nsLister, logger)
 
 	var patches []byte
 	patchedResource := request.Object.Raw
@@ -362,6 +439,9 @@ func (ws *WebhookServer) ResourceMutation(request *v1beta1.AdmissionRequest) *v1
 	if ws.supportMutateValidate {
 		if resource.GetDeletionTimestamp() == nil {
 			patches = ws.HandleMutation(request, resource, mutatePolicies, ctx, userRequestInfo)
+			if ephemeralContainers {
+				patches = podPatchesToEphemeralContainers(patches, logger)
+			}
 			logger.V(6).Info("", "generated patches", string(patches))
 
 			// patch the resource with patches before handling validation rules
@@ -373,7 +453,8 @@ func (ws *WebhookServer) ResourceMutation(request *v1beta1.AdmissionRequest) *v1
 	}
 
 	// GENERATE
-	if request.Operation == v1beta1.Create || request.Operation == v1beta1.Update {
+	// dry runs have no side effects, no resources are generated or mutated for them
+	if (request.Operation == v1beta1.Create || request.Operation == v1beta1.Update) && !isDryRun(request) {
 		newRequest := request.DeepCopy()
 		newRequest.Object.Raw = patchedResource
 		go ws.HandleGenerate(newRequest, generatePolicies, ctx, userRequestInfo, ws.configHandler)
@@ -392,7 +473,7 @@ func (ws *WebhookServer) ResourceMutation(request *v1beta1.AdmissionRequest) *v1
 
 func (ws *WebhookServer) resourceValidation(request *v1beta1.AdmissionRequest) *v1beta1.AdmissionResponse {
 	logger := ws.log.WithName("Validate").WithValues("uid", request.UID, "kind", request.Kind.Kind, "namespace", request.Namespace, "name", request.Name, "operation", request.Operation)
-	if request.Operation == v1beta1.Delete {
+	if request.Operation == v1beta1.Delete && !isDryRun(request) {
 		ws.handleDelete(request)
 	}
 
@@ -423,7 +504,9 @@ func (ws *WebhookServer) resourceValidation(request *v1beta1.AdmissionRequest) *
 	policies = append(policies, nsPolicies...)
 	if len(policies) == 0 {
 		// push admission request to audit handler, this won't block the admission request
-		ws.auditHandler.Add(request.DeepCopy())
+		if !isDryRun(request) {
+			ws.auditHandler.Add(request.DeepCopy())
+		}
 
 		logger.V(4).Info("no enforce validation policies; returning AdmissionResponse.Allowed: true")
 		return &v1beta1.AdmissionResponse{Allowed: true}
@@ -470,10 +553,7 @@ func (ws *WebhookServer) resourceValidation(request *v1beta1.AdmissionRequest) *
 		logger.Error(err, "failed to load service account in context")
 	}
 
-	namespaceLabels := make(map[string]string)
-	if request.Kind.Kind != "Namespace" && request.Namespace != "" {
-		namespaceLabels = common.GetNamespaceSelectorsFromNamespaceLister(request.Kind.Kind, request.Namespace, ws.nsLister, logger)
-	}
+	namespaceLabels := addNamespaceMetadata(ctx, request, ws.nsLister, logger)
 
 	ok, msg := HandleValidation(request, policies, nil, ctx, userRequestInfo, ws.statusListener, ws.eventGen, ws.prGenerator, ws.log, ws.configHandler, ws.resCache, ws.client, namespaceLabels)
 	if !ok {
@@ -498,7 +578,7 @@ func (ws *WebhookServer) resourceValidation(request *v1beta1.AdmissionRequest) *
 // RunAsync TLS server in separate thread and returns control immediately
 func (ws *WebhookServer) RunAsync(stopCh <-chan struct{}) {
 	logger := ws.log
-	if !cache.WaitForCacheSync(stopCh, ws.grSynced, ws.pSynced, ws.rbSynced, ws.crbSynced, ws.rSynced, ws.crSynced) {
+	if !cache.WaitForCacheSync(stopCh, ws.grSynced, ws.pSynced, ws.rbSynced, ws.crbSynced, ws.rSynced, ws.crSynced, ws.polrSynced, ws.cpolrSynced) {
 		logger.Info("failed to sync informer cache")
 	}
 
@@ -52,6 +52,7 @@ func HandleValidation(
 	}
 
 	logger := log.WithValues("action", "validate", "resource", resourceName, "operation", request.Operation)
+	dryRun := isDryRun(request)
 
 	// Get new and old resource
 	newR, oldR, err := utils.ExtractResources(patchedResource, request)
@@ -96,10 +97,12 @@ func HandleValidation(
 		}
 
 		engineResponses = append(engineResponses, engineResponse)
-		statusListener.Update(validateStats{
-			resp:      engineResponse,
-			namespace: policy.Namespace,
-		})
+		if !dryRun {
+			statusListener.Update(validateStats{
+				resp:      engineResponse,
+				namespace: policy.Namespace,
+			})
+		}
 
 		if !engineResponse.IsSuccessful() {
 			logger.V(2).Info("validation failed", "policy", policy.Name, "failed rules", engineResponse.GetFailedRules())
@@ -125,7 +128,15 @@ func HandleValidation(
 	// Scenario 3:
 	//   all policies were applied successfully.
 	//   create an event on the resource
-	events := generateEvents(engineResponses, blocked, (request.Operation == v1beta1.Update), logger)
+	// dry runs are evaluated without side effects, they are not reported
+	if dryRun {
+		if blocked {
+			return false, getEnforceFailureErrorMsg(engineResponses)
+		}
+		return true, ""
+	}
+
+	events := generateEvents(engineResponses, policies, blocked, (request.Operation == v1beta1.Update), logger)
 	eventGen.Add(events...)
 	if blocked {
 		logger.V(4).Info("resource blocked")
@@ -183,6 +194,7 @@ func (vs validateStats) UpdateStatus(status kyverno.PolicyStatus) kyverno.Policy
 	for _, rule := range vs.resp.PolicyResponse.Rules {
 		ruleStat := nameToRule[rule.Name]
 		ruleStat.Name = rule.Name
+		ruleStat.MatchedCount++
 
 		averageOver := int64(ruleStat.AppliedCount + ruleStat.FailedCount)
 		ruleStat.ExecutionTime = updateAverageTime(
@@ -0,0 +1,89 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	v1 "github.com/kyverno/kyverno/pkg/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/config"
+	enginectx "github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/event"
+	"github.com/kyverno/kyverno/pkg/policyreport"
+	"github.com/kyverno/kyverno/pkg/policystatus"
+	"gotest.tools/assert"
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type fakeEventGen struct {
+	infos []event.Info
+}
+
+func (f *fakeEventGen) Add(infos ...event.Info) {
+	f.infos = append(f.infos, infos...)
+}
+
+type fakePRGenerator struct {
+	infos []policyreport.Info
+}
+
+func (f *fakePRGenerator) Add(infos ...policyreport.Info) {
+	f.infos = append(f.infos, infos...)
+}
+
+func Test_HandleValidation_DryRun(t *testing.T) {
+	policy := &v1.ClusterPolicy{}
+	assert.NilError(t, json.Unmarshal([]byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "require-team"},
+		"spec": {
+			"validationFailureAction": "audit",
+			"rules": [{
+				"name": "check-team",
+				"match": {"resources": {"kinds": ["Pod"]}},
+				"validate": {"message": "the team label is required", "pattern": {"metadata": {"labels": {"team": "?*"}}}}
+			}]
+		}
+	}`), policy))
+
+	newRequest := func(dryRun bool) *v1beta1.AdmissionRequest {
+		return &v1beta1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			Namespace: "default",
+			Name:      "nginx",
+			Operation: v1beta1.Create,
+			DryRun:    &dryRun,
+			Object: runtime.RawExtension{Raw: []byte(`{
+				"apiVersion": "v1",
+				"kind": "Pod",
+				"metadata": {"name": "nginx", "namespace": "default"},
+				"spec": {"containers": [{"name": "nginx", "image": "nginx"}]}
+			}`)},
+		}
+	}
+
+	for _, dryRun := range []bool{true, false} {
+		request := newRequest(dryRun)
+		ctx := enginectx.NewContext()
+		assert.NilError(t, ctx.AddRequest(request))
+
+		eventGen, prGenerator := &fakeEventGen{}, &fakePRGenerator{}
+		statusListener := make(policystatus.Listener, 10)
+		ok, _ := HandleValidation(request, []*v1.ClusterPolicy{policy}, nil, ctx, v1.RequestInfo{}, statusListener, eventGen, prGenerator,
+			log.Log, &config.ConfigData{}, nil, nil, nil)
+		assert.Assert(t, ok)
+
+		if dryRun {
+			assert.Equal(t, len(eventGen.infos), 0)
+			assert.Equal(t, len(prGenerator.infos), 0)
+			assert.Equal(t, len(statusListener), 0)
+		} else {
+			assert.Assert(t, len(eventGen.infos) > 0)
+			assert.Assert(t, len(prGenerator.infos) > 0)
+			assert.Equal(t, len(statusListener), 1)
+		}
+	}
+}